@@ -16,7 +16,10 @@ package main
 import (
 	"os"
 
+	"github.com/perses/perses/internal/cli/cmd/apiresources"
 	"github.com/perses/perses/internal/cli/cmd/apply"
+	"github.com/perses/perses/internal/cli/cmd/backup"
+	"github.com/perses/perses/internal/cli/cmd/completion"
 	"github.com/perses/perses/internal/cli/cmd/conf"
 	"github.com/perses/perses/internal/cli/cmd/dac"
 	"github.com/perses/perses/internal/cli/cmd/describe"
@@ -26,8 +29,10 @@ import (
 	"github.com/perses/perses/internal/cli/cmd/migrate"
 	"github.com/perses/perses/internal/cli/cmd/plugin"
 	"github.com/perses/perses/internal/cli/cmd/project"
+	"github.com/perses/perses/internal/cli/cmd/query"
 	"github.com/perses/perses/internal/cli/cmd/refresh"
 	"github.com/perses/perses/internal/cli/cmd/remove"
+	"github.com/perses/perses/internal/cli/cmd/restore"
 	"github.com/perses/perses/internal/cli/cmd/version"
 	"github.com/perses/perses/internal/cli/cmd/whoami"
 	"github.com/perses/perses/internal/cli/config"
@@ -47,7 +52,10 @@ func newRootCommand() *cobra.Command {
 	}
 
 	// The list of supported commands
+	cmd.AddCommand(apiresources.NewCMD())
 	cmd.AddCommand(apply.NewCMD())
+	cmd.AddCommand(backup.NewCMD())
+	cmd.AddCommand(completion.NewCMD())
 	cmd.AddCommand(conf.NewCMD())
 	cmd.AddCommand(dac.NewCMD())
 	cmd.AddCommand(describe.NewCMD())
@@ -57,8 +65,10 @@ func newRootCommand() *cobra.Command {
 	cmd.AddCommand(migrate.NewCMD())
 	cmd.AddCommand(plugin.NewCMD())
 	cmd.AddCommand(project.NewCMD())
+	cmd.AddCommand(query.NewCMD())
 	cmd.AddCommand(refresh.NewCMD())
 	cmd.AddCommand(remove.NewCMD())
+	cmd.AddCommand(restore.NewCMD())
 	cmd.AddCommand(version.NewCMD())
 	cmd.AddCommand(whoami.NewCMD())
 
@@ -68,6 +78,8 @@ func newRootCommand() *cobra.Command {
 
 	// Some custom settings about the percli itself
 	cmd.SilenceUsage = true
+	// The completion is provided by the command "completion" instead of the one cobra adds by default.
+	cmd.CompletionOptions.DisableDefaultCmd = true
 	cmd.SetOut(os.Stdout)
 	cmd.SetErr(os.Stderr)
 	return cmd
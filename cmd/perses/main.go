@@ -15,10 +15,13 @@ package main
 
 import (
 	"flag"
+	"time"
 
 	"github.com/perses/common/app"
 	"github.com/perses/perses/internal/api/core"
+	"github.com/perses/perses/internal/api/database"
 	"github.com/perses/perses/internal/api/impl/v1/view"
+	"github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/pkg/model/api/config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -42,6 +45,8 @@ func registerMetrics(register prometheus.Registerer) {
 	register.MustRegister(collectors.NewGoCollector())
 	register.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	view.RegisterMetrics(register)
+	database.RegisterMetrics(register)
+	plugin.RegisterMetrics(register)
 }
 
 func main() {
@@ -59,10 +64,14 @@ func main() {
 	promRegistry := prometheus.NewRegistry()
 	registerMetrics(promRegistry)
 
-	runner, dependencyManager, err := core.New(conf, *pprof, promRegistry, banner)
+	runner, dependencyManager, err := core.New(conf, *configFile, *pprof, promRegistry, banner)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	if len(*configFile) > 0 && conf.Plugin.FilterReloadInterval > 0 {
+		reloadTask := plugin.NewFilterReloadTask(dependencyManager.Service().GetPlugin(), *configFile, conf.Plugin)
+		runner.WithTimerTasks(time.Duration(conf.Plugin.FilterReloadInterval), reloadTask)
+	}
 	defer func() {
 		if daoCloseErr := dependencyManager.Persistence().GetPersesDAO().Close(); daoCloseErr != nil {
 			logrus.WithError(daoCloseErr).Error("unable to close the connection to the database")
@@ -56,4 +56,8 @@ import "github.com/perses/perses/cue/model/api/v1/common"
 // DEPRECATED: this is replaced by the struct github.com/perses/spec/go/dashboard.Spec
 #DashboardSpec: _
 
+// DashboardMetadata is the metadata of a dashboard. On top of the project metadata, it holds the fields only the
+// dashboards have.
+#DashboardMetadata: _
+
 #Dashboard: _
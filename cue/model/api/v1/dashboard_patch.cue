@@ -22,6 +22,8 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/perses/perses/cue/model/api/v1/common"
 	"github.com/perses/perses/cue/model/api/v1/dashboard"
 )
@@ -45,8 +47,15 @@ import (
 	links?: [...#Link] @go(Links,[]Link)
 }
 
+#DashboardMetadata: {
+	#ProjectMetadata
+
+	resourceVersion?: string    @go(ResourceVersion)
+	deletedAt?:       time.Time @go(DeletedAt,*time.Time)
+}
+
 #Dashboard: {
-	kind:     #KindDashboard   @go(Kind)
-	metadata: #ProjectMetadata @go(Metadata)
-	spec:     #DashboardSpec   @go(Spec)
+	kind:     #KindDashboard     @go(Kind)
+	metadata: #DashboardMetadata @go(Metadata)
+	spec:     #DashboardSpec     @go(Spec)
 }
@@ -7,7 +7,9 @@ package v1
 #Kind: _ // #enumKind
 
 #enumKind:
+	#KindAPIKey |
 	#KindDashboard |
+	#KindDashboardTemplate |
 	#KindDatasource |
 	#KindEphemeralDashboard |
 	#KindFolder |
@@ -20,21 +22,27 @@ package v1
 	#KindRole |
 	#KindRoleBinding |
 	#KindSecret |
+	#KindTemplatePolicy |
+	#KindTemplatePolicyBinding |
 	#KindUser |
 	#KindVariable
 
-#KindDashboard:          #Kind & "Dashboard"
-#KindDatasource:         #Kind & "Datasource"
-#KindEphemeralDashboard: #Kind & "EphemeralDashboard"
-#KindFolder:             #Kind & "Folder"
-#KindGlobalDatasource:   #Kind & "GlobalDatasource"
-#KindGlobalRole:         #Kind & "GlobalRole"
-#KindGlobalRoleBinding:  #Kind & "GlobalRoleBinding"
-#KindGlobalVariable:     #Kind & "GlobalVariable"
-#KindGlobalSecret:       #Kind & "GlobalSecret"
-#KindProject:            #Kind & "Project"
-#KindRole:               #Kind & "Role"
-#KindRoleBinding:        #Kind & "RoleBinding"
-#KindSecret:             #Kind & "Secret"
-#KindUser:               #Kind & "User"
-#KindVariable:           #Kind & "Variable"
+#KindAPIKey:                #Kind & "APIKey"
+#KindDashboard:             #Kind & "Dashboard"
+#KindDashboardTemplate:     #Kind & "DashboardTemplate"
+#KindDatasource:            #Kind & "Datasource"
+#KindEphemeralDashboard:    #Kind & "EphemeralDashboard"
+#KindFolder:                #Kind & "Folder"
+#KindGlobalDatasource:      #Kind & "GlobalDatasource"
+#KindGlobalRole:            #Kind & "GlobalRole"
+#KindGlobalRoleBinding:     #Kind & "GlobalRoleBinding"
+#KindGlobalVariable:        #Kind & "GlobalVariable"
+#KindGlobalSecret:          #Kind & "GlobalSecret"
+#KindProject:               #Kind & "Project"
+#KindRole:                  #Kind & "Role"
+#KindRoleBinding:           #Kind & "RoleBinding"
+#KindSecret:                #Kind & "Secret"
+#KindTemplatePolicy:        #Kind & "TemplatePolicy"
+#KindTemplatePolicyBinding: #Kind & "TemplatePolicyBinding"
+#KindUser:                  #Kind & "User"
+#KindVariable:              #Kind & "Variable"
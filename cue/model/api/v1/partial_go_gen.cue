@@ -15,3 +15,10 @@ package v1
 	metadata: #ProjectMetadata @go(Metadata)
 	spec?: {} @go(Spec,struct{})
 }
+
+// PartialDashboard is the PartialProjectEntity of the dashboards, keeping the metadata only the dashboards have.
+#PartialDashboard: {
+	kind:     #Kind              @go(Kind)
+	metadata: #DashboardMetadata @go(Metadata)
+	spec?: {} @go(Spec,struct{})
+}
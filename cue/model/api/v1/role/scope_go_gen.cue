@@ -7,7 +7,10 @@ package role
 #Scope: _ // #enumScope
 
 #enumScope:
+	#APIKeyScope |
 	#DashboardScope |
+	#DashboardTemplateScope |
+	#DashboardVersionScope |
 	#DatasourceScope |
 	#EphemeralDashboardScope |
 	#FolderScope |
@@ -20,23 +23,30 @@ package role
 	#RoleScope |
 	#RoleBindingScope |
 	#SecretScope |
+	#TemplatePolicyScope |
+	#TemplatePolicyBindingScope |
 	#UserScope |
 	#VariableScope |
 	#WildcardScope
 
-#DashboardScope:          #Scope & "Dashboard"
-#DatasourceScope:         #Scope & "Datasource"
-#EphemeralDashboardScope: #Scope & "EphemeralDashboard"
-#FolderScope:             #Scope & "Folder"
-#GlobalDatasourceScope:   #Scope & "GlobalDatasource"
-#GlobalRoleScope:         #Scope & "GlobalRole"
-#GlobalRoleBindingScope:  #Scope & "GlobalRoleBinding"
-#GlobalSecretScope:       #Scope & "GlobalSecret"
-#GlobalVariableScope:     #Scope & "GlobalVariable"
-#ProjectScope:            #Scope & "Project"
-#RoleScope:               #Scope & "Role"
-#RoleBindingScope:        #Scope & "RoleBinding"
-#SecretScope:             #Scope & "Secret"
-#UserScope:               #Scope & "User"
-#VariableScope:           #Scope & "Variable"
-#WildcardScope:           #Scope & "*"
+#APIKeyScope:                #Scope & "APIKey"
+#DashboardScope:             #Scope & "Dashboard"
+#DashboardTemplateScope:     #Scope & "DashboardTemplate"
+#DashboardVersionScope:      #Scope & "DashboardVersion"
+#DatasourceScope:            #Scope & "Datasource"
+#EphemeralDashboardScope:    #Scope & "EphemeralDashboard"
+#FolderScope:                #Scope & "Folder"
+#GlobalDatasourceScope:      #Scope & "GlobalDatasource"
+#GlobalRoleScope:            #Scope & "GlobalRole"
+#GlobalRoleBindingScope:     #Scope & "GlobalRoleBinding"
+#GlobalSecretScope:          #Scope & "GlobalSecret"
+#GlobalVariableScope:        #Scope & "GlobalVariable"
+#ProjectScope:               #Scope & "Project"
+#RoleScope:                  #Scope & "Role"
+#RoleBindingScope:           #Scope & "RoleBinding"
+#SecretScope:                #Scope & "Secret"
+#TemplatePolicyScope:        #Scope & "TemplatePolicy"
+#TemplatePolicyBindingScope: #Scope & "TemplatePolicyBinding"
+#UserScope:                  #Scope & "User"
+#VariableScope:              #Scope & "Variable"
+#WildcardScope:              #Scope & "*"
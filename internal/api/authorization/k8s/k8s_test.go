@@ -912,6 +912,7 @@ func TestGetPermissions(t *testing.T) {
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"Variable"}},
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"EphemeralDashboard"}},
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"Folder"}},
+				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"DashboardTemplate"}},
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"GlobalDatasource"}},
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"GlobalVariable"}},
 				{Actions: []v1Role.Action{"*"}, Scopes: []v1Role.Scope{"GlobalSecret"}},
@@ -929,6 +930,7 @@ func TestGetPermissions(t *testing.T) {
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"Variable"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"EphemeralDashboard"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"Folder"}},
+				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"DashboardTemplate"}},
 			}},
 		},
 		{
@@ -946,6 +948,7 @@ func TestGetPermissions(t *testing.T) {
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"Variable"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"EphemeralDashboard"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"Folder"}},
+				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"DashboardTemplate"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"GlobalVariable"}},
 				{Actions: []v1Role.Action{"read"}, Scopes: []v1Role.Scope{"GlobalSecret"}},
 			}, projectZero: {
@@ -955,6 +958,7 @@ func TestGetPermissions(t *testing.T) {
 				{Actions: []v1Role.Action{"create"}, Scopes: []v1Role.Scope{"Variable"}},
 				{Actions: []v1Role.Action{"create"}, Scopes: []v1Role.Scope{"EphemeralDashboard"}},
 				{Actions: []v1Role.Action{"create"}, Scopes: []v1Role.Scope{"Folder"}},
+				{Actions: []v1Role.Action{"create"}, Scopes: []v1Role.Scope{"DashboardTemplate"}},
 			}},
 		},
 		{
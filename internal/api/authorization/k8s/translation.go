@@ -52,6 +52,7 @@ var projectScopesToCheck = append(kubernetesResourcesProjectScopesToCheck,
 	v1Role.VariableScope,
 	v1Role.EphemeralDashboardScope,
 	v1Role.FolderScope,
+	v1Role.DashboardTemplateScope,
 )
 
 // globalScopesToCheck contains all scopes that should be checked at the wildcard (all-namespace)
@@ -92,7 +93,8 @@ func getK8sAction(action v1Role.Action) k8sAction {
 // checkSpecificPermission falls back to checking the project/namespace permission instead.
 func getK8sScope(scope v1Role.Scope) k8sScope {
 	switch scope {
-	case v1Role.DashboardScope:
+	case v1Role.DashboardScope, v1Role.DashboardVersionScope:
+		// The resource version is part of the dashboard resource in Kubernetes.
 		return k8sDashboardScope
 	case v1Role.GlobalDatasourceScope:
 		return k8sGlobalDatasourceScope
@@ -0,0 +1,111 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package native
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyContextKey   = "apiKey"
+	apiKeyProviderKind = "apikey"
+)
+
+// withAPIKey authenticates the requests sending an API key in the header `Authorization: Bearer <key>`.
+// The other requests are passed to the JWT middleware.
+func (n *native) withAPIKey(skipper middleware.Skipper, jwtMiddleware echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtNext := jwtMiddleware(next)
+		return func(ctx echo.Context) error {
+			if skipper != nil && skipper(ctx) {
+				return jwtNext(ctx)
+			}
+			token, found := strings.CutPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !found || !strings.HasPrefix(token, crypto.APIKeyPrefix) {
+				return jwtNext(ctx)
+			}
+			apiKey, err := n.authenticateAPIKey(token, time.Now())
+			if err != nil {
+				return err
+			}
+			// The permissions checked by the handlers already restrict a key to its project.
+			// The key is nonetheless rejected here for any other project, so a handler missing a check can't leak another project.
+			if project := utils.GetProjectParameter(ctx); len(project) > 0 && project != apiKey.Metadata.Project {
+				return apiInterface.HandleForbiddenError(fmt.Sprintf("the API key can only access the project %q", apiKey.Metadata.Project))
+			}
+			ctx.Set(apiKeyContextKey, apiKey)
+			// The key is also set as the user of the request, so the username is available to the services like for a JWT token.
+			ctx.Set("user", &jwt.Token{
+				Valid: true,
+				Claims: &crypto.JWTClaims{
+					RegisteredClaims: jwt.RegisteredClaims{Subject: fmt.Sprintf("%s/%s", apiKey.Metadata.Project, apiKey.Metadata.Name)},
+					ProviderInfo:     crypto.ProviderInfo{ProviderKind: apiKeyProviderKind, ProviderID: apiKey.Metadata.Project},
+				},
+			})
+			return next(ctx)
+		}
+	}
+}
+
+func (n *native) authenticateAPIKey(token string, now time.Time) (*v1.APIKey, error) {
+	project, name, secret, ok := crypto.ParseAPIKey(token)
+	if !ok {
+		return nil, apiInterface.HandleUnauthorizedError("invalid API key")
+	}
+	apiKey, err := n.apiKeyDAO.Get(project, name)
+	if err != nil {
+		if !errors.Is(err, apiInterface.NotFoundError) {
+			logrus.WithError(err).Errorf("unable to get the API key %s/%s", project, name)
+		}
+		return nil, apiInterface.HandleUnauthorizedError("invalid API key")
+	}
+	if !crypto.CompareAPIKeySecret(apiKey.Spec.HashedSecret, secret) {
+		return nil, apiInterface.HandleUnauthorizedError("invalid API key")
+	}
+	if apiKey.Spec.IsExpired(now) {
+		return nil, apiInterface.HandleUnauthorizedError("API key expired")
+	}
+	return apiKey, nil
+}
+
+// getAPIKey returns the API key that authenticated the request, or nil if the request has been authenticated with a JWT token.
+func getAPIKey(ctx echo.Context) *v1.APIKey {
+	if ctx == nil {
+		return nil
+	}
+	apiKey, _ := ctx.Get(apiKeyContextKey).(*v1.APIKey)
+	return apiKey
+}
+
+// apiKeyHasPermission checks the permission against the scopes of the key. An API key only has permissions in its own project,
+// and it can never manage the API keys, even with a wildcard scope.
+func apiKeyHasPermission(apiKey *v1.APIKey, requestAction v1Role.Action, requestProject string, requestScope v1Role.Scope) bool {
+	if requestProject != apiKey.Metadata.Project || requestScope == v1Role.APIKeyScope {
+		return false
+	}
+	return listHasPermission(apiKey.Spec.Permissions(), requestAction, requestScope)
+}
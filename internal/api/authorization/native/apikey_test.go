@@ -0,0 +1,202 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package native
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPIKeyDAO struct {
+	apikey.DAO
+	keys []*v1.APIKey
+}
+
+func (d *fakeAPIKeyDAO) Get(project string, name string) (*v1.APIKey, error) {
+	for _, key := range d.keys {
+		if key.Metadata.Project == project && key.Metadata.Name == name {
+			return key, nil
+		}
+	}
+	return nil, apiInterface.NotFoundError
+}
+
+func newAPIKey(t *testing.T, name string, scopes []string, expiresAt *time.Time) (*v1.APIKey, string) {
+	key, hashedSecret, err := crypto.GenerateAPIKey("perses", name)
+	require.NoError(t, err)
+	return &v1.APIKey{
+		Kind:     v1.KindAPIKey,
+		Metadata: *v1.NewProjectMetadata("perses", name),
+		Spec: v1.APIKeySpec{
+			HashedSecret: hashedSecret,
+			Scopes:       scopes,
+			ExpiresAt:    expiresAt,
+		},
+	}, key
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	reader, readerKey := newAPIKey(t, "reader", []string{"read:Dashboard"}, &future)
+	expired, expiredKey := newAPIKey(t, "expired", []string{"read:Dashboard"}, &past)
+	n := &native{
+		accessKey: []byte("secret"),
+		apiKeyDAO: &fakeAPIKeyDAO{keys: []*v1.APIKey{reader, expired}},
+	}
+	testSuite := []struct {
+		title   string
+		key     string
+		project string
+		action  role.Action
+		status  int
+	}{
+		{
+			title:   "key granting the permission",
+			key:     readerKey,
+			project: "perses",
+			action:  role.ReadAction,
+			status:  http.StatusOK,
+		},
+		{
+			title:   "scope mismatch",
+			key:     readerKey,
+			project: "perses",
+			action:  role.CreateAction,
+			status:  http.StatusForbidden,
+		},
+		{
+			title:   "other project",
+			key:     readerKey,
+			project: "demo",
+			action:  role.ReadAction,
+			status:  http.StatusForbidden,
+		},
+		{
+			title:   "expired key",
+			key:     expiredKey,
+			project: "perses",
+			action:  role.ReadAction,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			title:   "unknown key",
+			key:     "pak_perses/unknown/0123",
+			project: "perses",
+			action:  role.ReadAction,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			title:   "wrong secret",
+			key:     "pak_perses/reader/0123",
+			project: "perses",
+			action:  role.ReadAction,
+			status:  http.StatusUnauthorized,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			e := echo.New()
+			// The errors are translated to HTTP errors by a middleware of the server.
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return apiInterface.HandleError(next(c))
+				}
+			})
+			e.Use(n.Middleware(nil))
+			e.GET("/", func(c echo.Context) error {
+				if !n.HasPermission(c, test.action, test.project, role.DashboardScope) {
+					return c.NoContent(http.StatusForbidden)
+				}
+				return c.NoContent(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %s", test.key))
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+}
+
+func TestAPIKeyProjectIsolation(t *testing.T) {
+	admin, adminKey := newAPIKey(t, "admin", []string{"*:*"}, nil)
+	n := &native{
+		accessKey: []byte("secret"),
+		apiKeyDAO: &fakeAPIKeyDAO{keys: []*v1.APIKey{admin}},
+	}
+	testSuite := []struct {
+		title  string
+		path   string
+		status int
+	}{
+		{
+			title:  "own project",
+			path:   "/api/v1/projects/perses/dashboards",
+			status: http.StatusOK,
+		},
+		{
+			title:  "other project",
+			path:   "/api/v1/projects/demo/dashboards",
+			status: http.StatusForbidden,
+		},
+		{
+			title:  "global resource",
+			path:   "/api/v1/globaldatasources",
+			status: http.StatusOK,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return apiInterface.HandleError(next(c))
+				}
+			})
+			e.Use(n.Middleware(nil))
+			// The handlers don't check any permission, so only the middleware can reject the request.
+			e.GET("/api/v1/projects/:project/dashboards", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			e.GET("/api/v1/globaldatasources", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %s", adminKey))
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+}
+
+func TestAPIKeyCannotManageAPIKeys(t *testing.T) {
+	admin, _ := newAPIKey(t, "admin", []string{"*:*"}, nil)
+	assert.True(t, apiKeyHasPermission(admin, role.DeleteAction, "perses", role.SecretScope))
+	assert.False(t, apiKeyHasPermission(admin, role.CreateAction, "perses", role.APIKeyScope))
+	assert.False(t, apiKeyHasPermission(admin, role.CreateAction, v1.WildcardProject, role.ProjectScope))
+}
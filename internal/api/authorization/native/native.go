@@ -26,6 +26,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/perses/perses/internal/api/crypto"
 	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/globalrole"
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/role"
@@ -38,7 +39,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
+func New(userDAO user.DAO, apiKeyDAO apikey.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
 	globalRoleDAO globalrole.DAO, globalRoleBindingDAO globalrolebinding.DAO, conf config.Config) (*native, error) {
 	key, err := hex.DecodeString(string(conf.Security.EncryptionKey))
 	if err != nil {
@@ -47,12 +48,14 @@ func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
 	return &native{
 		cache:                &cache{},
 		userDAO:              userDAO,
+		apiKeyDAO:            apiKeyDAO,
 		roleDAO:              roleDAO,
 		roleBindingDAO:       roleBindingDAO,
 		globalRoleDAO:        globalRoleDAO,
 		globalRoleBindingDAO: globalRoleBindingDAO,
 		guestPermissions:     conf.Security.Authorization.Provider.Native.GuestPermissions,
 		accessKey:            key,
+		audience:             conf.Security.JWTAudience,
 	}, err
 }
 
@@ -60,9 +63,12 @@ func New(userDAO user.DAO, roleDAO role.DAO, roleBindingDAO rolebinding.DAO,
 type native struct {
 	// The key used to sign the JWT token, it is expected to be the same as the one used in the crypto package.
 	accessKey []byte
+	// audience is the list of audiences accepted in the `aud` claim of the JWT token. It is not checked when empty.
+	audience []string
 	// cache is used to store in memory the permissions of all users.
 	cache                *cache
 	userDAO              user.DAO
+	apiKeyDAO            apikey.DAO
 	roleDAO              role.DAO
 	roleBindingDAO       rolebinding.DAO
 	globalRoleDAO        globalrole.DAO
@@ -153,16 +159,21 @@ func (n *native) Middleware(skipper middleware.Skipper) echo.MiddlewareFunc {
 			}
 			c.Request().Header.Set("Authorization", fmt.Sprintf("Bearer %s.%s", payloadCookie.Value, signatureCookie.Value))
 		},
-		NewClaimsFunc: func(_ echo.Context) jwt.Claims {
-			return &crypto.JWTClaims{}
+		// The token is parsed by the crypto package, so the access and refresh tokens are verified the same way.
+		ParseTokenFunc: func(_ echo.Context, auth string) (any, error) {
+			return crypto.ParseToken(auth, n.accessKey, n.audience)
 		},
-		SigningMethod: jwt.SigningMethodHS512.Name,
-		SigningKey:    n.accessKey,
 	}
-	return echojwt.WithConfig(jwtMiddlewareConfig)
+	return n.withAPIKey(skipper, echojwt.WithConfig(jwtMiddlewareConfig))
 }
 
 func (n *native) GetUserProjects(ctx echo.Context, requestAction v1Role.Action, requestScope v1Role.Scope) ([]string, error) {
+	if apiKey := getAPIKey(ctx); apiKey != nil {
+		if apiKeyHasPermission(apiKey, requestAction, apiKey.Metadata.Project, requestScope) {
+			return []string{apiKey.Metadata.Project}, nil
+		}
+		return nil, nil
+	}
 	if listHasPermission(n.guestPermissions, requestAction, requestScope) {
 		return []string{v1.WildcardProject}, nil
 	}
@@ -176,6 +187,9 @@ func (n *native) GetUserProjects(ctx echo.Context, requestAction v1Role.Action,
 		logrus.Error("failed to get username from context to list the user projects")
 		return nil, apiInterface.InternalError
 	}
+	if n.cache.rolesHavePermission(n.getClaimsRoles(ctx), requestAction, requestScope) {
+		return []string{v1.WildcardProject}, nil
+	}
 	projectPermission := n.cache.permissions[username]
 	if globalPermissions, ok := projectPermission[v1.WildcardProject]; ok && listHasPermission(globalPermissions, requestAction, requestScope) {
 		return []string{v1.WildcardProject}, nil
@@ -200,6 +214,10 @@ func (n *native) HasPermission(ctx echo.Context, requestAction v1Role.Action, re
 		// If the endpoint is anonymous, we allow the request to pass through.
 		return true
 	}
+	if apiKey := getAPIKey(ctx); apiKey != nil {
+		// The permissions of an API key are limited to its scopes.
+		return apiKeyHasPermission(apiKey, requestAction, requestProject, requestScope)
+	}
 	username, err := n.GetUsername(ctx)
 	if err != nil {
 		logrus.WithError(err).Error("failed to get username from context to check the user permissions")
@@ -218,6 +236,9 @@ func (n *native) HasPermission(ctx echo.Context, requestAction v1Role.Action, re
 	// Checking cached permissions
 	n.mutex.RLock()
 	defer n.mutex.RUnlock()
+	if n.cache.rolesHavePermission(n.getClaimsRoles(ctx), requestAction, requestScope) {
+		return true
+	}
 	return n.cache.hasPermission(username, requestAction, requestProject, requestScope)
 }
 
@@ -227,6 +248,9 @@ func (n *native) HasCreateProjectPermission(ctx echo.Context, projectName string
 }
 
 func (n *native) GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permission, error) {
+	if apiKey := getAPIKey(ctx); apiKey != nil {
+		return map[string][]*v1Role.Permission{apiKey.Metadata.Project: apiKey.Spec.Permissions()}, nil
+	}
 	n.mutex.RLock()
 	defer n.mutex.RUnlock()
 	username, err := n.GetUsername(ctx)
@@ -240,6 +264,9 @@ func (n *native) GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permissi
 	}
 	userPermissions := make(map[string][]*v1Role.Permission)
 	userPermissions[v1.WildcardProject] = n.guestPermissions
+	for _, roleName := range n.getClaimsRoles(ctx) {
+		userPermissions[v1.WildcardProject] = append(userPermissions[v1.WildcardProject], n.cache.rolePermissions[roleName]...)
+	}
 	for project, projectPermissions := range n.cache.permissions[username] {
 		userPermissions[project] = append(userPermissions[project], projectPermissions...)
 	}
@@ -247,37 +274,47 @@ func (n *native) GetPermissions(ctx echo.Context) (map[string][]*v1Role.Permissi
 }
 
 func (n *native) RefreshPermissions() error {
-	permissions, err := n.loadAllPermissions()
+	permissions, rolePermissions, err := n.loadAllPermissions()
 	if err != nil {
 		return err
 	}
 	n.mutex.Lock()
 	n.cache.permissions = permissions
+	n.cache.rolePermissions = rolePermissions
 	n.mutex.Unlock()
 	return nil
 }
 
-// loadAllPermissions is loading all permissions for all users.
-func (n *native) loadAllPermissions() (usersPermissions, error) {
+// getClaimsRoles returns the global roles saved in the token by the claims mapping of the OIDC provider.
+func (n *native) getClaimsRoles(ctx echo.Context) []string {
+	providerInfo, err := n.GetProviderInfo(ctx)
+	if err != nil {
+		return nil
+	}
+	return providerInfo.Roles
+}
+
+// loadAllPermissions is loading all permissions for all users, and the permissions of every global role.
+func (n *native) loadAllPermissions() (usersPermissions, map[string][]*v1Role.Permission, error) {
 	users, err := n.userDAO.List(&user.Query{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	roles, err := n.roleDAO.List(&role.Query{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	globalRoles, err := n.globalRoleDAO.List(&globalrole.Query{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	roleBindings, err := n.roleBindingDAO.List(&rolebinding.Query{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	globalRoleBindings, err := n.globalRoleBindingDAO.List(&globalrolebinding.Query{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Build cache
@@ -313,5 +350,12 @@ func (n *native) loadAllPermissions() (usersPermissions, error) {
 			}
 		}
 	}
-	return permissionBuild, nil
+
+	rolePermissions := make(map[string][]*v1Role.Permission, len(globalRoles))
+	for _, globalRole := range globalRoles {
+		for i := range globalRole.Spec.Permissions {
+			rolePermissions[globalRole.Metadata.Name] = append(rolePermissions[globalRole.Metadata.Name], &globalRole.Spec.Permissions[i])
+		}
+	}
+	return permissionBuild, rolePermissions, nil
 }
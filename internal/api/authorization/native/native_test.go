@@ -15,11 +15,18 @@ package native
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/crypto"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/role"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func generateMockCache(userCount int, projectCountByUser int) cache {
@@ -267,3 +274,101 @@ func BenchmarkCacheHasPermission(b *testing.B) {
 		})
 	}
 }
+
+func TestMiddlewareAudience(t *testing.T) {
+	key := []byte("secret")
+	n := &native{accessKey: key, audience: []string{"perses", "perses-staging"}}
+	testSuite := []struct {
+		title    string
+		audience []string
+		status   int
+	}{
+		{
+			title:    "single audience matching",
+			audience: []string{"perses"},
+			status:   http.StatusOK,
+		},
+		{
+			title:    "one of several audiences matching",
+			audience: []string{"grafana", "perses-staging"},
+			status:   http.StatusOK,
+		},
+		{
+			title:    "audience mismatch",
+			audience: []string{"grafana"},
+			status:   http.StatusUnauthorized,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, &crypto.JWTClaims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject:   "jdoe",
+					Audience:  test.audience,
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+				},
+			}).SignedString(key)
+			require.NoError(t, err)
+
+			e := echo.New()
+			e.Use(n.Middleware(nil))
+			e.GET("/", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %s", token))
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+}
+
+func TestHasPermissionWithClaimsRoles(t *testing.T) {
+	n := &native{cache: &cache{
+		permissions: smallMockCache().permissions,
+		rolePermissions: map[string][]*role.Permission{
+			"viewer": {{Actions: []role.Action{role.ReadAction}, Scopes: []role.Scope{role.WildcardScope}}},
+		},
+	}}
+	testSuite := []struct {
+		title          string
+		roles          []string
+		reqAction      role.Action
+		expectedResult bool
+	}{
+		{
+			title:          "no role in the token",
+			reqAction:      role.ReadAction,
+			expectedResult: false,
+		},
+		{
+			title:          "role granting the permission",
+			roles:          []string{"viewer"},
+			reqAction:      role.ReadAction,
+			expectedResult: true,
+		},
+		{
+			title:          "role not granting the permission",
+			roles:          []string{"viewer"},
+			reqAction:      role.DeleteAction,
+			expectedResult: false,
+		},
+		{
+			title:          "unknown role",
+			roles:          []string{"admin"},
+			reqAction:      role.ReadAction,
+			expectedResult: false,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			ctx.Set("user", &jwt.Token{Claims: &crypto.JWTClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "user3"},
+				ProviderInfo:     crypto.ProviderInfo{Roles: test.roles},
+			}})
+			assert.Equal(t, test.expectedResult, n.HasPermission(ctx, test.reqAction, "project0", role.DashboardScope))
+		})
+	}
+}
@@ -37,6 +37,10 @@ func (p usersPermissions) addEntry(user string, project string, permission *v1Ro
 
 type cache struct {
 	permissions usersPermissions
+	// rolePermissions contains the permissions of every global role, by name.
+	// It is used for the roles granted by the claims mapping of the OIDC providers, as they are not bound to the
+	// users with a global role binding.
+	rolePermissions map[string][]*v1Role.Permission
 }
 
 func (c *cache) hasPermission(user string, requestAction v1Role.Action, requestProject string, requestScope v1Role.Scope) bool {
@@ -61,6 +65,16 @@ func (c *cache) hasPermission(user string, requestAction v1Role.Action, requestP
 	return listHasPermission(projectPermissions, requestAction, requestScope)
 }
 
+// rolesHavePermission checks the permissions of the given global roles. As a global role, its permissions apply to every project.
+func (c *cache) rolesHavePermission(roles []string, requestAction v1Role.Action, requestScope v1Role.Scope) bool {
+	for _, roleName := range roles {
+		if listHasPermission(c.rolePermissions[roleName], requestAction, requestScope) {
+			return true
+		}
+	}
+	return false
+}
+
 func listHasPermission(permissions []*v1Role.Permission, requestAction v1Role.Action, requestScope v1Role.Scope) bool {
 	for _, permission := range permissions {
 		for _, action := range permission.Actions {
@@ -0,0 +1,134 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelPlugin "github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/prometheus/common/version"
+	"github.com/sirupsen/logrus"
+)
+
+// listenAddressFlag is the flag defined by github.com/perses/common/echo to set the address of the HTTP server.
+const listenAddressFlag = "web.listen-address"
+
+type PluginInfo struct {
+	Name    string
+	Version string
+}
+
+func (p PluginInfo) String() string {
+	return fmt.Sprintf("%s@%s", p.Name, p.Version)
+}
+
+type AuthProviderInfo struct {
+	// Kind is the type of provider: native, kubernetes, oidc, oauth or custom.
+	Kind   string
+	SlugID string
+}
+
+func (a AuthProviderInfo) String() string {
+	if len(a.SlugID) == 0 {
+		return a.Kind
+	}
+	return fmt.Sprintf("%s/%s", a.Kind, a.SlugID)
+}
+
+// logStartupBanner emits a single log entry summarizing what the server is starting with.
+func logStartupBanner(conf config.Config, plugins []PluginInfo, providers []AuthProviderInfo) {
+	if !logrus.IsLevelEnabled(logrus.InfoLevel) {
+		return
+	}
+	pluginsLoaded := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		pluginsLoaded = append(pluginsLoaded, p.String())
+	}
+	authProviders := make([]string, 0, len(providers))
+	for _, p := range providers {
+		authProviders = append(authProviders, p.String())
+	}
+	storageBackend := "none"
+	if conf.Database.File != nil {
+		storageBackend = "file"
+	} else if conf.Database.SQL != nil {
+		storageBackend = "sql"
+	}
+	listenAddress := ""
+	if f := flag.Lookup(listenAddressFlag); f != nil {
+		listenAddress = f.Value.String()
+	}
+	logrus.WithFields(logrus.Fields{
+		"version":         version.Version,
+		"plugins_loaded":  pluginsLoaded,
+		"auth_providers":  authProviders,
+		"storage_backend": storageBackend,
+		"listen_addr":     listenAddress,
+	}).Info("Perses server initialized")
+}
+
+// collectPluginInfo returns the plugin modules successfully loaded by the plugin service.
+func collectPluginInfo(pluginService plugin.Plugin) []PluginInfo {
+	data, err := pluginService.List()
+	if err != nil {
+		logrus.WithError(err).Debug("unable to list the plugins loaded")
+		return nil
+	}
+	// Only the fields needed are decoded, a module that failed to load may not have a valid spec.
+	var modules []struct {
+		Metadata modelPlugin.ModuleMetadata `json:"metadata"`
+		Status   *modelPlugin.ModuleStatus  `json:"status"`
+	}
+	if unmarshalErr := json.Unmarshal(data, &modules); unmarshalErr != nil {
+		logrus.WithError(unmarshalErr).Debug("unable to decode the list of plugins loaded")
+		return nil
+	}
+	result := make([]PluginInfo, 0, len(modules))
+	for _, module := range modules {
+		if module.Status != nil && !module.Status.IsLoaded {
+			continue
+		}
+		result = append(result, PluginInfo{Name: module.Metadata.Name, Version: module.Metadata.Version})
+	}
+	return result
+}
+
+// collectAuthProviderInfo returns the authentication providers enabled in the configuration.
+func collectAuthProviderInfo(conf config.Config) []AuthProviderInfo {
+	if !conf.Security.EnableAuth {
+		return nil
+	}
+	providers := conf.Security.Authentication.Providers
+	var result []AuthProviderInfo
+	if providers.EnableNative {
+		result = append(result, AuthProviderInfo{Kind: "native"})
+	}
+	if providers.KubernetesProvider.Enable {
+		result = append(result, AuthProviderInfo{Kind: "kubernetes"})
+	}
+	for _, p := range providers.OIDC {
+		result = append(result, AuthProviderInfo{Kind: "oidc", SlugID: p.SlugID})
+	}
+	for _, p := range providers.OAuth {
+		result = append(result, AuthProviderInfo{Kind: "oauth", SlugID: p.SlugID})
+	}
+	for _, p := range providers.Custom {
+		result = append(result, AuthProviderInfo{Kind: "custom", SlugID: p.SlugID})
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogStartupBanner(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	conf := config.Config{
+		Database: config.Database{SQL: &config.SQL{}},
+		Security: config.Security{
+			EnableAuth: true,
+			Authentication: config.AuthenticationConfig{
+				Providers: config.AuthenticationProviders{
+					EnableNative: true,
+					OIDC:         []config.OIDCProvider{{Provider: config.Provider{SlugID: "azure"}}},
+				},
+			},
+		},
+	}
+	plugins := []PluginInfo{{Name: "Prometheus", Version: "0.5.0"}, {Name: "TimeSeriesChart", Version: "0.9.1"}}
+	logStartupBanner(conf, plugins, collectAuthProviderInfo(conf))
+
+	entries := hook.AllEntries()
+	if assert.Len(t, entries, 1) {
+		entry := entries[0]
+		assert.Equal(t, logrus.InfoLevel, entry.Level)
+		assert.Equal(t, []string{"Prometheus@0.5.0", "TimeSeriesChart@0.9.1"}, entry.Data["plugins_loaded"])
+		assert.Equal(t, []string{"native", "oidc/azure"}, entry.Data["auth_providers"])
+		assert.Equal(t, "sql", entry.Data["storage_backend"])
+		assert.Contains(t, entry.Data, "version")
+		assert.Contains(t, entry.Data, "listen_addr")
+	}
+}
+
+func TestLogStartupBanner_LevelAboveInfo(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+	previousLevel := logrus.GetLevel()
+	logrus.SetLevel(logrus.WarnLevel)
+	defer logrus.SetLevel(previousLevel)
+
+	logStartupBanner(config.Config{}, nil, nil)
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestCollectAuthProviderInfo_AuthDisabled(t *testing.T) {
+	conf := config.Config{
+		Security: config.Security{
+			Authentication: config.AuthenticationConfig{
+				Providers: config.AuthenticationProviders{EnableNative: true},
+			},
+		},
+	}
+	assert.Empty(t, collectAuthProviderInfo(conf))
+}
@@ -14,19 +14,22 @@
 package core
 
 import (
+	"flag"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/perses/common/app"
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/core/middleware"
 	"github.com/perses/perses/internal/api/dashboard"
 	"github.com/perses/perses/internal/api/dependency"
 	"github.com/perses/perses/internal/api/discovery"
+	"github.com/perses/perses/internal/api/impl/proxy"
+	"github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/internal/api/provisioning"
+	"github.com/perses/perses/internal/api/sse"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api/config"
 	"github.com/perses/perses/ui"
@@ -34,7 +37,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, banner string) (*app.Runner, dependency.Manager, error) {
+func New(conf config.Config, configFile string, enablePprof bool, registry *prometheus.Registry, banner string) (*app.Runner, dependency.Manager, error) {
 	dependencyManager, err := dependency.NewManager(conf)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to instantiate the dependency manager: %w", err)
@@ -43,7 +46,13 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 	if dbInitError := persesDAO.Init(); dbInitError != nil {
 		return nil, nil, fmt.Errorf("unable to initialize the database: %w", dbInitError)
 	}
-	persesAPI := NewPersesAPI(dependencyManager, conf)
+	var pluginUpdateChecker plugin.UpdateChecker
+	if len(conf.Plugin.RegistryURL) > 0 {
+		pluginUpdateChecker = plugin.NewUpdateChecker(dependencyManager.Service().GetPlugin(), conf.Plugin.RegistryURL)
+	}
+	proxyTransports := proxy.NewTransportPool(time.Duration(conf.Datasource.IdleConnectionTTL))
+	cors := middleware.NewCORS(conf.Security.CORS)
+	persesAPI := NewPersesAPI(dependencyManager, conf, configFile, pluginUpdateChecker, proxyTransports, cors)
 	persesFrontend := ui.NewPersesFrontend(conf, dependencyManager.Service().GetPlugin())
 	runner := app.NewRunner().WithDefaultHTTPServerAndPrometheusRegisterer(utils.MetricNamespace, registry, registry).SetBanner(banner)
 
@@ -56,6 +65,18 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 		runner.WithTimerTasks(time.Duration(conf.EphemeralDashboard.CleanupInterval), ephemeralDashboardsCleaner)
 	}
 
+	// delete permanently the dashboards kept in the trash for longer than the retention
+	if !conf.Security.Readonly {
+		trashCleaner := dashboard.NewTrashCleaner(dependencyManager.Service().GetDashboard(), time.Duration(conf.Dashboard.TrashRetentionDuration))
+		runner.WithTimerTasks(time.Duration(conf.Dashboard.TrashCleanupInterval), trashCleaner)
+	}
+
+	// close the connections to the datasources that are left idle for too long
+	runner.WithTimerTasks(time.Duration(conf.Datasource.IdleConnectionTTL), proxyTransports)
+
+	// drain the Server-Sent Events streams when the server stops
+	runner.WithTasks(sse.NewHub(time.Duration(conf.API.GracefulShutdownTimeout)))
+
 	if len(conf.Provisioning.Folders) > 0 {
 		provisioningTask := provisioning.New(dependencyManager.Service(), conf.Provisioning.Folders, persesDAO.IsCaseSensitive())
 		runner.WithTimerTasks(time.Duration(conf.Provisioning.Interval), provisioningTask)
@@ -77,11 +98,24 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 	unzipErr := dependencyManager.Service().GetPlugin().UnzipArchives()
 	if unzipErr != nil {
 		logrus.WithError(unzipErr).Error("unable to unzip the plugin archives")
+		go func() {
+			if alertErr := plugin.NotifyExtractionFailure(conf.Alerts.PluginExtractionFailure, unzipErr); alertErr != nil {
+				logrus.WithError(alertErr).Error("unable to notify about the plugin extraction failure")
+			}
+		}()
 	} else {
 		if pluginErr := dependencyManager.Service().GetPlugin().Load(); pluginErr != nil {
 			logrus.WithError(pluginErr).Error("unable to load the plugins")
 		}
 	}
+	runner.WithTasks(plugin.NewSignalReloadTask(dependencyManager.Service().GetPlugin()))
+	if pluginUpdateChecker != nil {
+		if len(conf.Plugin.UpdateCheckSchedule) > 0 {
+			runner.WithCronTasks(conf.Plugin.UpdateCheckSchedule.String(), pluginUpdateChecker)
+		} else {
+			runner.WithTimerTasks(time.Duration(conf.Plugin.UpdateCheckInterval), pluginUpdateChecker)
+		}
+	}
 
 	// register the API
 	runner.
@@ -93,25 +127,33 @@ func New(conf config.Config, enablePprof bool, registry *prometheus.Registry, ba
 			// let's skip the gzip compression when using the proxy and rely on the datasource behind.
 			return strings.HasPrefix(c.Request().URL.Path, fmt.Sprintf("%s/proxy", conf.APIPrefix)) ||
 				// When serving the plugins from a dev server, we don't want to compress the response since it's already compressed by rsbuild.
-				(conf.Plugin.EnableDev && strings.HasPrefix(c.Request().URL.Path, fmt.Sprintf("%s/plugins", conf.APIPrefix)))
+				(conf.Plugin.IsDevEnabled() && strings.HasPrefix(c.Request().URL.Path, fmt.Sprintf("%s/plugins", conf.APIPrefix)))
 		}).
+		Middleware(middleware.HandleRequestID()).
 		Middleware(middleware.HandleError()).
-		Middleware(middleware.CheckProject(dependencyManager.Service().GetProject()))
+		Middleware(middleware.CheckProject(dependencyManager.Service().GetProject())).
+		Middleware(middleware.HideDashboardResourceVersion(dependencyManager.Service().GetAuthorization()))
 	if !conf.Frontend.Disable {
 		runner.HTTPServerBuilder().APIRegistration(persesFrontend)
 	}
+	if conf.API.AutoTLSRedirect && isTLSConfigured() {
+		runner.HTTPServerBuilder().PreMiddleware(middleware.RedirectToHTTPS(conf.APIPrefix + utils.APIV1Prefix + "/health"))
+	}
 	if len(conf.APIPrefix) > 0 {
 		runner.HTTPServerBuilder().PreMiddleware(middleware.HandleAPIPrefix(conf.APIPrefix))
 	}
-	if conf.Security.CORS.Enable {
-		runner.HTTPServerBuilder().Middleware(echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
-			AllowOrigins:     conf.Security.CORS.AllowOrigins,
-			AllowMethods:     conf.Security.CORS.AllowMethods,
-			AllowHeaders:     conf.Security.CORS.AllowHeaders,
-			AllowCredentials: conf.Security.CORS.AllowCredentials,
-			ExposeHeaders:    conf.Security.CORS.ExposeHeaders,
-			MaxAge:           conf.Security.CORS.MaxAge,
-		}))
+	if len(conf.API.DeprecatedEndpoints) > 0 {
+		runner.HTTPServerBuilder().PreMiddleware(middleware.HandleDeprecatedEndpoints(conf.API))
 	}
+	runner.HTTPServerBuilder().PreMiddleware(middleware.HandleDashboardMediaType(conf.APIPrefix))
+	// The CORS middleware is always registered, so it can be enabled at runtime through the config API.
+	runner.HTTPServerBuilder().Middleware(cors.Handle())
+	logStartupBanner(conf, collectPluginInfo(dependencyManager.Service().GetPlugin()), collectAuthProviderInfo(conf))
 	return runner, dependencyManager, nil
 }
+
+// isTLSConfigured tells whether the HTTP server is started with a certificate, using the flag defined by perses/common.
+func isTLSConfigured() bool {
+	certFlag := flag.Lookup("web.tls-cert-file")
+	return certFlag != nil && len(certFlag.Value.String()) > 0
+}
@@ -0,0 +1,116 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/utils"
+)
+
+const (
+	// DashboardMediaType can be used instead of application/json to pin the version of the dashboard format sent or
+	// expected by the client, with the parameter "version": "application/vnd.perses.dashboard+json; version=1".
+	DashboardMediaType               = "application/vnd.perses.dashboard+json"
+	mediaTypeVersionParam            = "version"
+	defaultDashboardMediaTypeVersion = "1"
+)
+
+// dashboardMediaTypeVersions associates each version of DashboardMediaType to the version of the API serving it.
+var dashboardMediaTypeVersions = map[string]string{
+	"1": "v1",
+}
+
+// dashboardMediaTypeVersion returns the version of DashboardMediaType carried by the given media type.
+// The boolean is false if the media type is not DashboardMediaType.
+func dashboardMediaTypeVersion(value string) (string, bool, error) {
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", false, err
+	}
+	if mediaType != DashboardMediaType {
+		return "", false, nil
+	}
+	version, ok := params[mediaTypeVersionParam]
+	if !ok {
+		version = defaultDashboardMediaTypeVersion
+	}
+	return version, true, nil
+}
+
+// acceptedDashboardMediaTypeVersion looks for DashboardMediaType in the Accept header and returns its version.
+func acceptedDashboardMediaTypeVersion(accept string) (string, bool) {
+	for _, value := range strings.Split(accept, ",") {
+		if version, ok, err := dashboardMediaTypeVersion(strings.TrimSpace(value)); err == nil && ok {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// HandleDashboardMediaType routes the requests using DashboardMediaType, in the Content-Type or in the Accept header,
+// to the version of the API matching the version of the media type. A request without the parameter version is
+// considered to use the version 1. An unsupported version is rejected with 415 when it is the one of the body, and 406
+// when it is the one expected in the response.
+// As it rewrites the path, this middleware must be registered as a pre-middleware, so it runs before the routing.
+func HandleDashboardMediaType(apiPrefix string) echo.MiddlewareFunc {
+	v1Prefix := apiPrefix + utils.APIV1Prefix
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			var bodyVersion string
+			var hasBodyVersion bool
+			if contentType := req.Header.Get(echo.HeaderContentType); len(contentType) > 0 {
+				var err error
+				bodyVersion, hasBodyVersion, err = dashboardMediaTypeVersion(contentType)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnsupportedMediaType, fmt.Sprintf("invalid Content-Type: %s", err))
+				}
+			}
+			acceptVersion, hasAcceptVersion := acceptedDashboardMediaTypeVersion(req.Header.Get(echo.HeaderAccept))
+			if !hasBodyVersion && !hasAcceptVersion {
+				return next(c)
+			}
+
+			version := acceptVersion
+			if hasBodyVersion {
+				if _, ok := dashboardMediaTypeVersions[bodyVersion]; !ok {
+					return echo.NewHTTPError(http.StatusUnsupportedMediaType, fmt.Sprintf("version %q of the media type %s is not supported", bodyVersion, DashboardMediaType))
+				}
+				if hasAcceptVersion && acceptVersion != bodyVersion {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("the version of %s must be the same in the Content-Type and Accept headers", DashboardMediaType))
+				}
+				version = bodyVersion
+				// The body is a regular JSON document, this is what the handlers are expecting.
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			apiVersion, ok := dashboardMediaTypeVersions[version]
+			if !ok {
+				return echo.NewHTTPError(http.StatusNotAcceptable, fmt.Sprintf("version %q of the media type %s is not supported", version, DashboardMediaType))
+			}
+			if hasAcceptVersion {
+				c.Response().Header().Set(echo.HeaderContentType, fmt.Sprintf("%s; %s=%s", DashboardMediaType, mediaTypeVersionParam, version))
+			}
+			if rest, found := strings.CutPrefix(req.URL.Path, v1Prefix+"/"); found {
+				req.URL.Path = fmt.Sprintf("%s%s/%s/%s", apiPrefix, utils.APIPrefix, apiVersion, rest)
+				req.URL.RawPath = ""
+			}
+			return next(c)
+		}
+	}
+}
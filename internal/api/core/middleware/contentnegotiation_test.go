@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDashboardMediaTypeServer(apiPrefix string) *echo.Echo {
+	e := echo.New()
+	e.Pre(HandleDashboardMediaType(apiPrefix))
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{
+			"path":        c.Request().URL.Path,
+			"contentType": c.Request().Header.Get(echo.HeaderContentType),
+		})
+	}
+	e.POST(apiPrefix+"/api/v1/projects/:project/dashboards", handler)
+	e.POST("/api/v1/projects/:project/dashboards", handler)
+	return e
+}
+
+func TestHandleDashboardMediaType(t *testing.T) {
+	testSuite := []struct {
+		title               string
+		apiPrefix           string
+		contentType         string
+		accept              string
+		expectedStatus      int
+		expectedBody        string
+		expectedContentType string
+	}{
+		{
+			title:               "regular JSON request",
+			contentType:         echo.MIMEApplicationJSON,
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"contentType":"application/json","path":"/api/v1/projects/perses/dashboards"}`,
+			expectedContentType: echo.MIMEApplicationJSON,
+		},
+		{
+			title:               "media type version 1",
+			contentType:         DashboardMediaType + "; version=1",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"contentType":"application/json","path":"/api/v1/projects/perses/dashboards"}`,
+			expectedContentType: echo.MIMEApplicationJSON,
+		},
+		{
+			title:               "media type without version",
+			contentType:         DashboardMediaType,
+			accept:              DashboardMediaType,
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"contentType":"application/json","path":"/api/v1/projects/perses/dashboards"}`,
+			expectedContentType: DashboardMediaType + "; version=1",
+		},
+		{
+			title:               "media type version 1 with an API prefix",
+			apiPrefix:           "/perses",
+			contentType:         DashboardMediaType + "; version=1",
+			accept:              "text/html, " + DashboardMediaType + "; version=1",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"contentType":"application/json","path":"/perses/api/v1/projects/perses/dashboards"}`,
+			expectedContentType: DashboardMediaType + "; version=1",
+		},
+		{
+			title:          "unsupported version in Content-Type",
+			contentType:    DashboardMediaType + "; version=2",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			title:          "unsupported version in Accept",
+			contentType:    echo.MIMEApplicationJSON,
+			accept:         DashboardMediaType + "; version=2",
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			title:          "different versions in Content-Type and Accept",
+			contentType:    DashboardMediaType + "; version=1",
+			accept:         DashboardMediaType + "; version=2",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			title:          "invalid Content-Type",
+			contentType:    DashboardMediaType + "; version",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			e := newDashboardMediaTypeServer(test.apiPrefix)
+			req := httptest.NewRequest(http.MethodPost, test.apiPrefix+"/api/v1/projects/perses/dashboards", nil)
+			req.Header.Set(echo.HeaderContentType, test.contentType)
+			if len(test.accept) > 0 {
+				req.Header.Set(echo.HeaderAccept, test.accept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, rec.Body.String())
+				assert.Contains(t, rec.Header().Get(echo.HeaderContentType), test.expectedContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/perses/perses/pkg/model/api/config"
+)
+
+// CORS is the CORS middleware of the API. Its configuration can be replaced while the server is running.
+type CORS struct {
+	// middleware is nil when CORS is disabled.
+	middleware atomic.Pointer[echo.MiddlewareFunc]
+}
+
+func NewCORS(conf config.CORSConfig) *CORS {
+	c := &CORS{}
+	c.Update(conf)
+	return c
+}
+
+// Update replaces the configuration used by the next requests.
+func (c *CORS) Update(conf config.CORSConfig) {
+	if !conf.Enable {
+		c.middleware.Store(nil)
+		return
+	}
+	middleware := echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
+		AllowOrigins:     conf.AllowOrigins,
+		AllowMethods:     conf.AllowMethods,
+		AllowHeaders:     conf.AllowHeaders,
+		AllowCredentials: conf.AllowCredentials,
+		ExposeHeaders:    conf.ExposeHeaders,
+		MaxAge:           conf.MaxAge,
+	})
+	c.middleware.Store(&middleware)
+}
+
+// Handle returns the middleware applying the current CORS configuration to each request.
+func (c *CORS) Handle() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			middleware := c.middleware.Load()
+			if middleware == nil {
+				return next(ctx)
+			}
+			return (*middleware)(next)(ctx)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSUpdate(t *testing.T) {
+	cors := NewCORS(config.CORSConfig{})
+	e := echo.New()
+	e.Use(cors.Handle())
+	e.GET("/api/v1/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://perses.dev")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Empty(t, request().Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	cors.Update(config.CORSConfig{Enable: true, AllowOrigins: []string{"https://perses.dev"}})
+	assert.Equal(t, "https://perses.dev", request().Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	cors.Update(config.CORSConfig{Enable: true, AllowOrigins: []string{"https://demo.perses.dev"}})
+	assert.Empty(t, request().Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	cors.Update(config.CORSConfig{Enable: false})
+	assert.Empty(t, request().Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+type deprecatedEndpoint struct {
+	oldPath string
+	newPath string
+}
+
+// match returns the path the request must be served with, or false if the request doesn't target the deprecated endpoint.
+func (d deprecatedEndpoint) match(path string) (string, bool) {
+	if path == d.oldPath {
+		return d.newPath, true
+	}
+	if strings.HasPrefix(path, strings.TrimSuffix(d.oldPath, "/")+"/") {
+		return d.newPath + strings.TrimPrefix(path, strings.TrimSuffix(d.oldPath, "/")), true
+	}
+	return "", false
+}
+
+// HandleDeprecatedEndpoints serves the deprecated endpoints with the endpoint replacing them.
+// The response carries the headers defined by the RFC 8594: Deprecation, Sunset (if a date is configured)
+// and Link pointing to the new endpoint. Once the sunset date is passed, the deprecated endpoints answer with 410 Gone.
+// As it rewrites the path, this middleware must be registered as a pre-middleware, so it runs before the routing.
+func HandleDeprecatedEndpoints(conf config.API) echo.MiddlewareFunc {
+	endpoints := make([]deprecatedEndpoint, 0, len(conf.DeprecatedEndpoints))
+	for oldPath, newPath := range conf.DeprecatedEndpoints {
+		endpoints = append(endpoints, deprecatedEndpoint{oldPath: oldPath, newPath: newPath})
+	}
+	// The longest paths are checked first, so the most specific endpoint wins.
+	sort.Slice(endpoints, func(i, j int) bool {
+		return len(endpoints[i].oldPath) > len(endpoints[j].oldPath)
+	})
+	sunset := conf.DeprecatedEndpointsSunset
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			path := c.Request().URL.Path
+			for _, endpoint := range endpoints {
+				newPath, ok := endpoint.match(path)
+				if !ok {
+					continue
+				}
+				header := c.Response().Header()
+				header.Set("Deprecation", "true")
+				header.Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", newPath))
+				if sunset != nil {
+					header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+					if time.Now().After(*sunset) {
+						logrus.Warnf("deprecated endpoint %q has been removed, %q should be used instead", path, newPath)
+						return echo.NewHTTPError(http.StatusGone, fmt.Sprintf("endpoint %q has been removed, use %q instead", path, newPath))
+					}
+				}
+				logrus.Warnf("deprecated endpoint %q used, %q should be used instead", path, newPath)
+				c.Request().URL.Path = newPath
+				c.Request().URL.RawPath = ""
+				break
+			}
+			return next(c)
+		}
+	}
+}
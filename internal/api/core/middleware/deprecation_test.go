@@ -0,0 +1,104 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDeprecationServer(sunset *time.Time) *echo.Echo {
+	e := echo.New()
+	e.Pre(HandleDeprecatedEndpoints(config.API{
+		DeprecatedEndpoints: map[string]string{
+			"/api/v1/old":         "/api/v1/new",
+			"/api/v1/old/special": "/api/v1/special",
+		},
+		DeprecatedEndpointsSunset: sunset,
+	}))
+	e.GET("/api/v1/new", func(c echo.Context) error {
+		return c.String(http.StatusOK, "new")
+	})
+	e.GET("/api/v1/new/:name", func(c echo.Context) error {
+		return c.String(http.StatusOK, "new "+c.Param("name"))
+	})
+	e.GET("/api/v1/special", func(c echo.Context) error {
+		return c.String(http.StatusOK, "special")
+	})
+	return e
+}
+
+func serve(e *echo.Echo, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestHandleDeprecatedEndpoints(t *testing.T) {
+	sunset := time.Now().Add(24 * time.Hour)
+	e := newDeprecationServer(&sunset)
+
+	testSuite := []struct {
+		title string
+		path  string
+		body  string
+		link  string
+	}{
+		{title: "exact path", path: "/api/v1/old", body: "new", link: "</api/v1/new>; rel=\"successor-version\""},
+		{title: "sub path", path: "/api/v1/old/foo", body: "new foo", link: "</api/v1/new/foo>; rel=\"successor-version\""},
+		{title: "most specific path wins", path: "/api/v1/old/special", body: "special", link: "</api/v1/special>; rel=\"successor-version\""},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			rec := serve(e, test.path)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, test.body, rec.Body.String())
+			assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+			assert.Equal(t, sunset.UTC().Format(http.TimeFormat), rec.Header().Get("Sunset"))
+			assert.Equal(t, test.link, rec.Header().Get("Link"))
+		})
+	}
+}
+
+func TestHandleDeprecatedEndpoints_NotDeprecated(t *testing.T) {
+	rec := serve(newDeprecationServer(nil), "/api/v1/new")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Link"))
+
+	// a path sharing only a prefix with a deprecated endpoint is not deprecated
+	rec = serve(newDeprecationServer(nil), "/api/v1/older")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+}
+
+func TestHandleDeprecatedEndpoints_NoSunset(t *testing.T) {
+	rec := serve(newDeprecationServer(nil), "/api/v1/old")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+}
+
+func TestHandleDeprecatedEndpoints_Removed(t *testing.T) {
+	sunset := time.Now().Add(-time.Hour)
+	rec := serve(newDeprecationServer(&sunset), "/api/v1/old")
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Equal(t, "</api/v1/new>; rel=\"successor-version\"", rec.Header().Get("Link"))
+}
@@ -0,0 +1,32 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/perses/perses/internal/httpclient"
+)
+
+// HandleRequestID makes sure every incoming request carries a request ID. The ID is returned in the response headers
+// and stored in the request context so that outgoing calls made with the httpclient package forward it.
+func HandleRequestID() echo.MiddlewareFunc {
+	return echoMiddleware.RequestIDWithConfig(echoMiddleware.RequestIDConfig{
+		TargetHeader: httpclient.RequestIDHeader,
+		RequestIDHandler: func(c echo.Context, id string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(httpclient.WithRequestID(req.Context(), id)))
+		},
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/utils"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+const resourceVersionKey = "resourceVersion"
+
+// permissionChecker is the part of the authorization needed to know if the resource version can be exposed.
+type permissionChecker interface {
+	IsEnabled() bool
+	HasPermission(ctx echo.Context, requestAction v1Role.Action, requestProject string, requestScope v1Role.Scope) bool
+}
+
+// bufferedResponseWriter keeps the response in memory, so it can be modified before being sent.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// returnsDashboards returns true if the route matched by the request is returning one or many dashboards:
+// the dashboards themselves, the dashboards in the trash, the restored dashboards and the clones.
+func returnsDashboards(method string, path string) bool {
+	if !strings.HasPrefix(path, utils.APIV1Prefix+"/") {
+		return false
+	}
+	switch method {
+	case http.MethodGet:
+		return strings.HasSuffix(path, "/"+utils.PathDashboard) ||
+			strings.HasSuffix(path, "/"+utils.PathDashboard+"/:"+utils.ParamName) ||
+			strings.HasSuffix(path, "/"+utils.PathTrash)
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/"+utils.PathDashboard+"/:"+utils.ParamName+"/clone") ||
+			strings.HasSuffix(path, "/"+utils.PathTrash+"/:"+utils.ParamName+"/restore")
+	}
+	return false
+}
+
+// HideDashboardResourceVersion removes the resource version from the dashboards returned by the API when the
+// caller doesn't have the permission to read it (action "read" on the scope "DashboardVersion").
+// As an anonymous request doesn't carry any permission, the resource version is always removed from its response.
+// Without the resource version, the caller can still read the dashboards, but cannot update them, as the updates
+// require it (except for the dashboards saved before the resource versions were introduced).
+func HideDashboardResourceVersion(authz permissionChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !authz.IsEnabled() || !returnsDashboards(c.Request().Method, c.Path()) {
+				return next(c)
+			}
+			res := c.Response()
+			original := res.Writer
+			buffer := &bufferedResponseWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buffer
+			err := next(c)
+			res.Writer = original
+			if !res.Committed {
+				// Nothing has been written, the error (if any) is sent by the error handler.
+				return err
+			}
+			body := buffer.body.Bytes()
+			if buffer.status == http.StatusOK || buffer.status == http.StatusCreated {
+				body = removeResourceVersion(body, func(project string) bool {
+					return !utils.IsAnonymous(c) && authz.HasPermission(c, v1Role.ReadAction, project, v1Role.DashboardVersionScope)
+				})
+			}
+			original.WriteHeader(buffer.status)
+			if _, writeErr := original.Write(body); writeErr != nil {
+				return writeErr
+			}
+			return err
+		}
+	}
+}
+
+// removeResourceVersion removes the resource version from the dashboard (or the list of dashboards) contained in
+// the body when canRead returns false for the project of the dashboard.
+// The body is returned unchanged if it cannot be decoded or if nothing has been removed.
+func removeResourceVersion(body []byte, canRead func(project string) bool) []byte {
+	trimmed := bytes.TrimSpace(body)
+	isList := bytes.HasPrefix(trimmed, []byte("["))
+	var dashboards []map[string]json.RawMessage
+	if isList {
+		if err := json.Unmarshal(trimmed, &dashboards); err != nil {
+			return body
+		}
+	} else {
+		var dashboard map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &dashboard); err != nil {
+			return body
+		}
+		dashboards = append(dashboards, dashboard)
+	}
+
+	permissions := make(map[string]bool)
+	removed := false
+	for _, dashboard := range dashboards {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(dashboard["metadata"], &metadata); err != nil {
+			continue
+		}
+		if _, ok := metadata[resourceVersionKey]; !ok {
+			continue
+		}
+		var project string
+		_ = json.Unmarshal(metadata["project"], &project)
+		allowed, ok := permissions[project]
+		if !ok {
+			allowed = canRead(project)
+			permissions[project] = allowed
+		}
+		if allowed {
+			continue
+		}
+		delete(metadata, resourceVersionKey)
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return body
+		}
+		dashboard["metadata"] = data
+		removed = true
+	}
+	if !removed {
+		return body
+	}
+
+	var result []byte
+	var err error
+	if isList {
+		result, err = json.Marshal(dashboards)
+	} else {
+		result, err = json.Marshal(dashboards[0])
+	}
+	if err != nil {
+		return body
+	}
+	if bytes.HasSuffix(body, []byte("\n")) {
+		result = append(result, '\n')
+	}
+	return result
+}
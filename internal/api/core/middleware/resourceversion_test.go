@@ -0,0 +1,181 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/utils"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePermissionChecker struct {
+	enabled bool
+	// projects lists the projects where the resource version can be read.
+	projects []string
+}
+
+func (f *fakePermissionChecker) IsEnabled() bool {
+	return f.enabled
+}
+
+func (f *fakePermissionChecker) HasPermission(_ echo.Context, action v1Role.Action, project string, scope v1Role.Scope) bool {
+	if action != v1Role.ReadAction || scope != v1Role.DashboardVersionScope {
+		return false
+	}
+	for _, p := range f.projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	resourceVersionDashboard = `{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","resourceVersion":"3","version":3},"spec":{}}`
+	resourceVersionList      = `[{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","resourceVersion":"3","version":3},"spec":{}},` +
+		`{"kind":"Dashboard","metadata":{"name":"bar","project":"other","resourceVersion":"1","version":1},"spec":{}}]`
+)
+
+func newResourceVersionServer(authz permissionChecker, anonymous bool) *echo.Echo {
+	e := echo.New()
+	e.Use(HideDashboardResourceVersion(authz))
+	e.Use(HandleAnonymous(anonymous))
+	e.GET(utils.APIV1Prefix+"/projects/:project/dashboards/:name", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(resourceVersionDashboard))
+	})
+	e.GET(utils.APIV1Prefix+"/dashboards", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(resourceVersionList))
+	})
+	e.GET(utils.APIV1Prefix+"/projects/:project/trash", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(resourceVersionList))
+	})
+	e.POST(utils.APIV1Prefix+"/projects/:project/trash/:name/restore", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(resourceVersionDashboard))
+	})
+	e.POST(utils.APIV1Prefix+"/projects/:project/dashboards/:name/clone", func(c echo.Context) error {
+		return c.Blob(http.StatusCreated, echo.MIMEApplicationJSON, []byte(resourceVersionDashboard))
+	})
+	e.GET(utils.APIV1Prefix+"/projects/:project/datasources/:datasource", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, []byte(resourceVersionDashboard))
+	})
+	return e
+}
+
+func TestHideDashboardResourceVersion(t *testing.T) {
+	testSuite := []struct {
+		title     string
+		authz     permissionChecker
+		anonymous bool
+		method    string
+		path      string
+		status    int
+		result    string
+	}{
+		{
+			title:  "authorization disabled",
+			authz:  &fakePermissionChecker{},
+			path:   "/api/v1/projects/perses/dashboards/foo",
+			result: resourceVersionDashboard,
+		},
+		{
+			title:  "permission granted",
+			authz:  &fakePermissionChecker{enabled: true, projects: []string{"perses"}},
+			path:   "/api/v1/projects/perses/dashboards/foo",
+			result: resourceVersionDashboard,
+		},
+		{
+			title:  "permission denied",
+			authz:  &fakePermissionChecker{enabled: true},
+			path:   "/api/v1/projects/perses/dashboards/foo",
+			result: `{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","version":3},"spec":{}}`,
+		},
+		{
+			title:     "anonymous request",
+			authz:     &fakePermissionChecker{enabled: true, projects: []string{"perses"}},
+			anonymous: true,
+			path:      "/api/v1/projects/perses/dashboards/foo",
+			result:    `{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","version":3},"spec":{}}`,
+		},
+		{
+			title: "permission granted on a single project of the list",
+			authz: &fakePermissionChecker{enabled: true, projects: []string{"perses"}},
+			path:  "/api/v1/dashboards",
+			result: `[{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","resourceVersion":"3","version":3},"spec":{}},` +
+				`{"kind":"Dashboard","metadata":{"name":"bar","project":"other","version":1},"spec":{}}]`,
+		},
+		{
+			title: "trash",
+			authz: &fakePermissionChecker{enabled: true, projects: []string{"other"}},
+			path:  "/api/v1/projects/perses/trash",
+			result: `[{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","version":3},"spec":{}},` +
+				`{"kind":"Dashboard","metadata":{"name":"bar","project":"other","resourceVersion":"1","version":1},"spec":{}}]`,
+		},
+		{
+			title:  "restored dashboard",
+			authz:  &fakePermissionChecker{enabled: true},
+			method: http.MethodPost,
+			path:   "/api/v1/projects/perses/trash/foo/restore",
+			result: `{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","version":3},"spec":{}}`,
+		},
+		{
+			title:  "clone",
+			authz:  &fakePermissionChecker{enabled: true},
+			method: http.MethodPost,
+			path:   "/api/v1/projects/perses/dashboards/foo/clone",
+			status: http.StatusCreated,
+			result: `{"kind":"Dashboard","metadata":{"name":"foo","project":"perses","version":3},"spec":{}}`,
+		},
+		{
+			title:  "not a dashboard",
+			authz:  &fakePermissionChecker{enabled: true},
+			path:   "/api/v1/projects/perses/datasources/foo",
+			result: resourceVersionDashboard,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			e := newResourceVersionServer(test.authz, test.anonymous)
+			method := test.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+			status := test.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(method, test.path, nil))
+			assert.Equal(t, status, rec.Code)
+			assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, test.result, rec.Body.String())
+		})
+	}
+}
+
+func TestHideDashboardResourceVersion_Error(t *testing.T) {
+	e := echo.New()
+	e.Use(HideDashboardResourceVersion(&fakePermissionChecker{enabled: true}))
+	e.GET(utils.APIV1Prefix+"/projects/:project/dashboards/:name", func(_ echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "document not found")
+	})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/projects/perses/dashboards/foo", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "{\"message\":\"document not found\"}\n", rec.Body.String())
+}
@@ -0,0 +1,37 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RedirectToHTTPS redirects permanently the requests received over HTTP to the same URL using HTTPS.
+// The scheme is deduced from the connection or, behind a reverse proxy, from the X-Forwarded-Proto header.
+// The requests on the excluded paths (like the health check) are never redirected,
+// so a probe not following redirections keeps working.
+func RedirectToHTTPS(excludedPaths ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if c.Scheme() == "https" || slices.Contains(excludedPaths, req.URL.Path) {
+				return next(c)
+			}
+			return c.Redirect(http.StatusMovedPermanently, "https://"+req.Host+req.URL.RequestURI())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSRedirectServer() *echo.Echo {
+	e := echo.New()
+	e.Pre(RedirectToHTTPS("/api/v1/health"))
+	e.GET("/*", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	return e
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		target   string
+		location string
+	}{
+		{
+			title:    "root",
+			target:   "http://perses.dev/",
+			location: "https://perses.dev/",
+		},
+		{
+			title:    "path and query are preserved",
+			target:   "http://perses.dev:8080/api/v1/projects/perses/dashboards?name=demo&kind=Dashboard",
+			location: "https://perses.dev:8080/api/v1/projects/perses/dashboards?name=demo&kind=Dashboard",
+		},
+		{
+			title:    "escaped path is preserved",
+			target:   "http://perses.dev/projects/my%2Fproject",
+			location: "https://perses.dev/projects/my%2Fproject",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTLSRedirectServer().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+			assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+			assert.Equal(t, test.location, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRedirectToHTTPS_NotRedirected(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		target string
+		header http.Header
+	}{
+		{
+			// httptest sets the TLS connection state when the scheme is https
+			title:  "request over TLS",
+			target: "https://perses.dev/api/v1/projects",
+		},
+		{
+			title:  "request forwarded by a proxy terminating TLS",
+			target: "http://perses.dev/api/v1/projects",
+			header: http.Header{echo.HeaderXForwardedProto: []string{"https"}},
+		},
+		{
+			title:  "health check",
+			target: "http://perses.dev/api/v1/health",
+		},
+		{
+			title:  "health check with a query",
+			target: "http://perses.dev/api/v1/health?verbose=true",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			for key, values := range test.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			newTLSRedirectServer().ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, rec.Header().Get("Location"))
+		})
+	}
+}
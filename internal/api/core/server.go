@@ -14,15 +14,27 @@
 package core
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	echoUtils "github.com/perses/common/echo"
 	"github.com/perses/perses/internal/api/core/middleware"
 	"github.com/perses/perses/internal/api/dependency"
 	authendpoint "github.com/perses/perses/internal/api/impl/auth"
+	compareendpoint "github.com/perses/perses/internal/api/impl/compare"
 	configendpoint "github.com/perses/perses/internal/api/impl/config"
+	"github.com/perses/perses/internal/api/impl/federation"
 	migrateendpoint "github.com/perses/perses/internal/api/impl/migrate"
+	"github.com/perses/perses/internal/api/impl/pluginupdate"
 	"github.com/perses/perses/internal/api/impl/proxy"
+	"github.com/perses/perses/internal/api/impl/queryinspector"
+	"github.com/perses/perses/internal/api/impl/v1/apikey"
 	"github.com/perses/perses/internal/api/impl/v1/dashboard"
+	"github.com/perses/perses/internal/api/impl/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/impl/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/impl/v1/datasource"
 	"github.com/perses/perses/internal/api/impl/v1/ephemeraldashboard"
 	"github.com/perses/perses/internal/api/impl/v1/folder"
@@ -37,43 +49,68 @@ import (
 	"github.com/perses/perses/internal/api/impl/v1/role"
 	"github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	"github.com/perses/perses/internal/api/impl/v1/secret"
+	"github.com/perses/perses/internal/api/impl/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/impl/v1/templatepolicybinding"
 	"github.com/perses/perses/internal/api/impl/v1/user"
+	"github.com/perses/perses/internal/api/impl/v1/userfavorite"
 	"github.com/perses/perses/internal/api/impl/v1/variable"
 	"github.com/perses/perses/internal/api/impl/v1/view"
 	validateendpoint "github.com/perses/perses/internal/api/impl/validate"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	apiPlugin "github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/sirupsen/logrus"
 )
 
 type api struct {
 	echoUtils.Register
-	apiV1Endpoints         []route.Endpoint
+	// versionedEndpoints contains the endpoints served under /api/v<version>, by version.
+	// A version can coexist with the previous one while the clients are migrating.
+	versionedEndpoints     map[int][]route.Endpoint
 	apiEndpoints           []route.Endpoint
 	proxyEndpoint          route.Endpoint
 	authorizationMiddlware echo.MiddlewareFunc
+	ipExtractor            echo.IPExtractor
 	apiPrefix              string
 }
 
-func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoUtils.Register {
+// NewPersesAPI returns the routes of the API. updateChecker is optional and is nil when no plugin registry is configured.
+// transports is shared by the datasource proxies so the connections to the datasources are reused.
+// cors is the CORS middleware of the server, updated when the CORS configuration is changed through the config API.
+func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config, configFile string, updateChecker apiPlugin.UpdateChecker, transports *proxy.TransportPool, cors *middleware.CORS) echoUtils.Register {
 	readonly := cfg.Security.Readonly
 	persistenceManager := dependencyManager.Persistence()
 	serviceManager := dependencyManager.Service()
 	caseSensitive := persistenceManager.GetPersesDAO().IsCaseSensitive()
 	apiV1Endpoints := []route.Endpoint{
 		dashboard.NewEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		dashboard.NewCloneEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		dashboard.NewTrashEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		dashboard.NewTagEndpoint(serviceManager.GetDashboard(), serviceManager.GetAuthorization()),
+		dashboardhistory.NewEndpoint(serviceManager.GetDashboardHistory(), serviceManager.GetAuthorization()),
+		dashboardtemplate.NewEndpoint(serviceManager.GetDashboardTemplate(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		dashboardtemplate.NewInstantiateEndpoint(serviceManager.GetDashboardTemplate(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		compareendpoint.NewDiff(),
 		datasource.NewEndpoint(cfg.Datasource, serviceManager.GetDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		datasource.NewVariableEndpoint(cfg.Datasource,
+			datasource.NewVariableResolver(persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), time.Duration(cfg.Datasource.VariableCacheTTL)),
+			serviceManager.GetAuthorization()),
 		ephemeraldashboard.NewEndpoint(serviceManager.GetEphemeralDashboard(), serviceManager.GetAuthorization(), readonly, caseSensitive, cfg.EphemeralDashboard.Enable),
 		folder.NewEndpoint(serviceManager.GetFolder(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		globaldatasource.NewEndpoint(cfg.Datasource, serviceManager.GetGlobalDatasource(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		globalsecret.NewEndpoint(serviceManager.GetGlobalSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		globalvariable.NewEndpoint(cfg.Variable, serviceManager.GetGlobalVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		health.NewEndpoint(serviceManager.GetHealth()),
-		plugin.NewEndpoint(serviceManager.GetPlugin(), cfg.Plugin.EnableDev),
+		plugin.NewEndpoint(serviceManager.GetPlugin(), cfg.Plugin.IsDevEnabled()),
 		project.NewEndpoint(serviceManager.GetProject(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		secret.NewEndpoint(serviceManager.GetSecret(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		templatepolicy.NewEndpoint(serviceManager.GetTemplatePolicy(), serviceManager.GetAuthorization(), readonly, caseSensitive),
+		templatepolicybinding.NewEndpoint(serviceManager.GetTemplatePolicyBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		user.NewEndpoint(serviceManager.GetUser(), serviceManager.GetAuthorization(), cfg.Security.Authentication.DisableSignUp, readonly, caseSensitive),
+		userfavorite.NewEndpoint(serviceManager.GetUserFavorite(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		variable.NewEndpoint(cfg.Variable, serviceManager.GetVariable(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 		view.NewEndpoint(serviceManager.GetView(), serviceManager.GetAuthorization(), serviceManager.GetDashboard()),
 	}
@@ -81,7 +118,9 @@ func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoU
 	if cfg.Security.Authorization.Provider.Native.Enable {
 		// When the authorization is provided by a third-party service, roles are not managed by the Perses API.
 		// Therefore, we provide endpoints to manage them only if the native authorization is enabled.
+		// It is the same for the API keys, as their permissions are checked by the native authorization.
 		apiV1Endpoints = append(apiV1Endpoints,
+			apikey.NewEndpoint(serviceManager.GetAPIKey(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 			globalrole.NewEndpoint(serviceManager.GetGlobalRole(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 			globalrolebinding.NewEndpoint(serviceManager.GetGlobalRoleBinding(), serviceManager.GetAuthorization(), readonly, caseSensitive),
 			role.NewEndpoint(serviceManager.GetRole(), serviceManager.GetAuthorization(), readonly, caseSensitive),
@@ -101,24 +140,58 @@ func NewPersesAPI(dependencyManager dependency.Manager, cfg config.Config) echoU
 		logrus.WithError(err).Fatal("error initializing authentication endpoints")
 	}
 	apiEndpoints := []route.Endpoint{
-		configendpoint.New(cfg),
+		configendpoint.New(cfg, configFile, serviceManager.GetAuthorization(), cors, serviceManager.GetPlugin()),
 		migrateendpoint.New(serviceManager.GetMigration()),
 		validateendpoint.New(serviceManager.GetSchema(), serviceManager.GetDashboard()),
+		compareendpoint.New(),
 		authEndpoint,
+		proxy.NewAnnotationEndpoint(cfg.Datasource, cfg.API, persistenceManager.GetDashboard(), persistenceManager.GetSecret(), persistenceManager.GetGlobalSecret(),
+			persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), serviceManager.GetCrypto(), serviceManager.GetAuthorization(), transports),
+		proxy.NewPassthroughEndpoint(cfg.Datasource, cfg.API, persistenceManager.GetDashboard(), persistenceManager.GetSecret(), persistenceManager.GetGlobalSecret(),
+			persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), serviceManager.GetCrypto(), serviceManager.GetAuthorization(), transports),
+	}
+	if len(cfg.Federation.Peers) > 0 {
+		apiEndpoints = append(apiEndpoints, federation.New(cfg.Federation))
+	}
+	if updateChecker != nil {
+		apiEndpoints = append(apiEndpoints, pluginupdate.New(updateChecker, serviceManager.GetAuthorization()))
+	}
+	var queryAudit queryaudit.Service
+	if cfg.API.EnableQueryInspector {
+		queryAudit = serviceManager.GetQueryAudit()
+		apiEndpoints = append(apiEndpoints, queryinspector.New(queryAudit, serviceManager.GetAuthorization()))
 	}
 	return &api{
-		apiV1Endpoints: apiV1Endpoints,
-		apiEndpoints:   apiEndpoints,
-		proxyEndpoint: proxy.New(cfg.Datasource, persistenceManager.GetDashboard(), persistenceManager.GetSecret(), persistenceManager.GetGlobalSecret(),
-			persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), serviceManager.GetCrypto(), serviceManager.GetAuthorization()),
+		versionedEndpoints: map[int][]route.Endpoint{1: apiV1Endpoints},
+		apiEndpoints:       apiEndpoints,
+		proxyEndpoint: proxy.New(cfg.Datasource, cfg.API, persistenceManager.GetDashboard(), persistenceManager.GetSecret(), persistenceManager.GetGlobalSecret(),
+			persistenceManager.GetDatasource(), persistenceManager.GetGlobalDatasource(), serviceManager.GetCrypto(), serviceManager.GetAuthorization(), transports, queryAudit),
 		authorizationMiddlware: serviceManager.GetAuthorization().Middleware(func(_ echo.Context) bool {
 			return !cfg.Security.EnableAuth
 		}),
-		apiPrefix: cfg.APIPrefix,
+		ipExtractor: newIPExtractor(cfg.Security.TrustedProxies),
+		apiPrefix:   cfg.APIPrefix,
+	}
+}
+
+// newIPExtractor returns how to find the IP of the client when the requests go through trusted reverse proxies.
+// It returns nil when no proxy is configured, so the default behavior of echo is kept.
+func newIPExtractor(trustedProxies []common.IPOrCIDR) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return nil
+	}
+	// Only the configured proxies are trusted, not the private networks echo trusts by default.
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy.IPNet()))
 	}
+	return echo.ExtractIPFromXFFHeader(options...)
 }
 
 func (a *api) RegisterRoute(e *echo.Echo) {
+	if a.ipExtractor != nil {
+		e.IPExtractor = a.ipExtractor
+	}
 	// First, let's collect every route.
 	// The expecting result is a tree we will need to loop over.
 	groups := a.collectRoutes()
@@ -167,11 +240,21 @@ func (a *api) collectRoutes() []*route.Group {
 	for _, ept := range a.apiEndpoints {
 		ept.CollectRoutes(apiGroup)
 	}
-	apiV1Group := &route.Group{Path: a.apiPrefix + utils.APIV1Prefix}
-	for _, ept := range a.apiV1Endpoints {
-		ept.CollectRoutes(apiV1Group)
+	groups := []*route.Group{apiGroup}
+	versions := slices.Sorted(maps.Keys(a.versionedEndpoints))
+	for _, version := range versions {
+		groups = append(groups, a.newVersionGroup(version))
 	}
 	proxyGroup := &route.Group{Path: a.apiPrefix + "/proxy"}
 	a.proxyEndpoint.CollectRoutes(proxyGroup)
-	return []*route.Group{apiGroup, apiV1Group, proxyGroup}
+	return append(groups, proxyGroup)
+}
+
+// newVersionGroup returns the group of the routes of the given API version.
+func (a *api) newVersionGroup(version int) *route.Group {
+	group := &route.Group{Path: fmt.Sprintf("%s%s/v%d", a.apiPrefix, utils.APIPrefix, version)}
+	for _, ept := range a.versionedEndpoints[version] {
+		ept.CollectRoutes(group)
+	}
+	return group
 }
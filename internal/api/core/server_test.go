@@ -0,0 +1,48 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/perses/perses/internal/api/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEndpoint struct {
+	path string
+}
+
+func (e *fakeEndpoint) CollectRoutes(g *route.Group) {
+	g.GET(e.path, nil, true)
+}
+
+func TestCollectRoutesVersions(t *testing.T) {
+	a := &api{
+		versionedEndpoints: map[int][]route.Endpoint{
+			2: {&fakeEndpoint{path: "/dashboards"}},
+			1: {&fakeEndpoint{path: "/dashboards"}, &fakeEndpoint{path: "/projects"}},
+		},
+		proxyEndpoint: &fakeEndpoint{path: "/projects"},
+		apiPrefix:     "/perses",
+	}
+	groups := a.collectRoutes()
+	var paths []string
+	for _, g := range groups {
+		paths = append(paths, g.Path)
+	}
+	assert.Equal(t, []string{"/perses/api", "/perses/api/v1", "/perses/api/v2", "/perses/proxy"}, paths)
+	assert.Len(t, groups[1].Routes, 2)
+	assert.Len(t, groups[2].Routes, 1)
+}
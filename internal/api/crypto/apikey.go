@@ -0,0 +1,62 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix is the prefix of every API key. It tells the API keys apart from the JWT tokens.
+const APIKeyPrefix = "pak_"
+
+const apiKeySecretSize = 32
+
+// GenerateAPIKey generates the key of the API key <project>/<name>, and returns it with the hash of its secret.
+// The key has the format `pak_<project>/<name>/<secret>`, so the API key can be found without scanning every key stored.
+func GenerateAPIKey(project string, name string) (string, string, error) {
+	secret := make([]byte, apiKeySecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	encodedSecret := hex.EncodeToString(secret)
+	return fmt.Sprintf("%s%s/%s/%s", APIKeyPrefix, project, name, encodedSecret), HashAPIKeySecret(encodedSecret), nil
+}
+
+// ParseAPIKey splits a key generated by GenerateAPIKey into the project, the name and the secret of the API key.
+func ParseAPIKey(key string) (project string, name string, secret string, ok bool) {
+	value, found := strings.CutPrefix(key, APIKeyPrefix)
+	if !found {
+		return "", "", "", false
+	}
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func HashAPIKeySecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// CompareAPIKeySecret returns true if the secret matches the hash, in constant time.
+func CompareAPIKeySecret(hashedSecret string, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashedSecret), []byte(HashAPIKeySecret(secret))) == 1
+}
@@ -0,0 +1,64 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, hashedSecret, err := GenerateAPIKey("perses", "ci")
+	require.NoError(t, err)
+	project, name, secret, ok := ParseAPIKey(key)
+	require.True(t, ok)
+	assert.Equal(t, "perses", project)
+	assert.Equal(t, "ci", name)
+	assert.True(t, CompareAPIKeySecret(hashedSecret, secret))
+	assert.False(t, CompareAPIKeySecret(hashedSecret, secret+"0"))
+}
+
+func TestParseAPIKey(t *testing.T) {
+	testSuite := []struct {
+		title string
+		key   string
+		ok    bool
+	}{
+		{
+			title: "valid key",
+			key:   "pak_perses/ci/0123",
+			ok:    true,
+		},
+		{
+			title: "missing prefix",
+			key:   "perses/ci/0123",
+		},
+		{
+			title: "missing secret",
+			key:   "pak_perses/ci/",
+		},
+		{
+			title: "too many parts",
+			key:   "pak_perses/ci/0123/4567",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			_, _, _, ok := ParseAPIKey(test.key)
+			assert.Equal(t, test.ok, ok)
+		})
+	}
+}
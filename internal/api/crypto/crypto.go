@@ -73,6 +73,7 @@ func New(security config.Security) (Crypto, JWT, error) {
 			accessTokenTTL:  time.Duration(security.Authentication.AccessTokenTTL),
 			refreshTokenTTL: time.Duration(security.Authentication.RefreshTokenTTL),
 			cookieConfig:    security.Cookie,
+			audience:        security.JWTAudience,
 		}, nil
 }
 
@@ -33,6 +33,8 @@ const (
 type ProviderInfo struct {
 	ProviderKind string `json:"pkd"`
 	ProviderID   string `json:"pid"`
+	// Roles is the list of the global roles granted by the provider through its claims mapping.
+	Roles []string `json:"roles,omitempty"`
 }
 
 type JWTClaims struct {
@@ -40,11 +42,12 @@ type JWTClaims struct {
 	ProviderInfo
 }
 
-func signedToken(login string, providerInfo ProviderInfo, notBefore time.Time, expireAt time.Time, key []byte) (string, error) {
+func signedToken(login string, providerInfo ProviderInfo, notBefore time.Time, expireAt time.Time, key []byte, audience []string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, &JWTClaims{
 		ProviderInfo: providerInfo,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   login,
+			Audience:  audience,
 			ExpiresAt: jwt.NewNumericDate(expireAt),
 			NotBefore: jwt.NewNumericDate(notBefore),
 		},
@@ -54,6 +57,22 @@ func signedToken(login string, providerInfo ProviderInfo, notBefore time.Time, e
 	return token.SignedString(key)
 }
 
+// ParseToken verifies a token signed by Perses with the given key and returns it with its JWTClaims.
+// When audience is not empty, the `aud` claim of the token must contain at least one of its values.
+func ParseToken(token string, key []byte, audience []string) (*jwt.Token, error) {
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Name})}
+	if len(audience) > 0 {
+		options = append(options, jwt.WithAudience(audience...))
+	}
+	parsedToken, err := jwt.ParseWithClaims(token, &JWTClaims{}, func(_ *jwt.Token) (any, error) {
+		return key, nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return parsedToken, nil
+}
+
 type JWT interface {
 	SignedAccessToken(login string, providerInfo ProviderInfo) (string, error)
 	SignedRefreshToken(login string, providerInfo ProviderInfo) (string, error)
@@ -74,16 +93,17 @@ type jwtImpl struct {
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 	cookieConfig    config.Cookie
+	audience        []string
 }
 
 func (j *jwtImpl) SignedAccessToken(login string, providerInfo ProviderInfo) (string, error) {
 	now := time.Now()
-	return signedToken(login, providerInfo, now, now.Add(j.accessTokenTTL), j.accessKey)
+	return signedToken(login, providerInfo, now, now.Add(j.accessTokenTTL), j.accessKey, j.audience)
 }
 
 func (j *jwtImpl) SignedRefreshToken(login string, providerInfo ProviderInfo) (string, error) {
 	now := time.Now()
-	return signedToken(login, providerInfo, now, now.Add(j.refreshTokenTTL), j.refreshKey)
+	return signedToken(login, providerInfo, now, now.Add(j.refreshTokenTTL), j.refreshKey, j.audience)
 }
 
 func (j *jwtImpl) CreateAccessTokenCookie(accessToken string) (*http.Cookie, *http.Cookie) {
@@ -158,9 +178,7 @@ func (j *jwtImpl) DeleteRefreshTokenCookie() *http.Cookie {
 }
 
 func (j *jwtImpl) ValidateRefreshToken(token string) (*JWTClaims, error) {
-	parsedToken, err := jwt.ParseWithClaims(token, &JWTClaims{}, func(_ *jwt.Token) (any, error) {
-		return j.refreshKey, nil
-	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Name}))
+	parsedToken, err := ParseToken(token, j.refreshKey, j.audience)
 	if err != nil {
 		return nil, err
 	}
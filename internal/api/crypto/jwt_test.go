@@ -16,10 +16,12 @@ package crypto
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/perses/perses/internal/api/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJWTClaims_Serialization(t *testing.T) {
@@ -36,3 +38,55 @@ func TestJWTClaims_Serialization(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `{"sub":"jdoe","pkd":"oidc","pid":"azure"}`, string(result))
 }
+
+func TestParseToken_Audience(t *testing.T) {
+	key := []byte("secret")
+	testSuite := []struct {
+		title            string
+		tokenAudience    []string
+		expectedAudience []string
+		err              error
+	}{
+		{
+			title:         "audience not checked",
+			tokenAudience: []string{"grafana"},
+		},
+		{
+			title:            "single audience",
+			tokenAudience:    []string{"perses"},
+			expectedAudience: []string{"perses"},
+		},
+		{
+			title:            "one of several audiences matching",
+			tokenAudience:    []string{"perses", "perses-staging"},
+			expectedAudience: []string{"perses-prod", "perses-staging"},
+		},
+		{
+			title:            "audience mismatch",
+			tokenAudience:    []string{"grafana"},
+			expectedAudience: []string{"perses"},
+			err:              jwt.ErrTokenInvalidAudience,
+		},
+		{
+			title:            "audience missing",
+			expectedAudience: []string{"perses"},
+			err:              jwt.ErrTokenRequiredClaimMissing,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			now := time.Now()
+			token, err := signedToken("jdoe", ProviderInfo{}, now, now.Add(time.Minute), key, test.tokenAudience)
+			require.NoError(t, err)
+			parsedToken, err := ParseToken(token, key, test.expectedAudience)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			subject, err := parsedToken.Claims.GetSubject()
+			require.NoError(t, err)
+			assert.Equal(t, "jdoe", subject)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// Change is an element of a dashboard that is only in one of the compared dashboards, or that differs between them.
+type Change struct {
+	Type ChangeType `json:"type"`
+	// Name is the key of the panel, the name of the variable or the datasource reference.
+	Name string `json:"name"`
+}
+
+// Comparison lists the semantic differences between two dashboards, sorted by name.
+type Comparison struct {
+	Panels    []Change `json:"panels"`
+	Variables []Change `json:"variables"`
+	// Datasources are the datasources referenced in the dashboard, by the panel queries, the variables or the
+	// annotations, in the format `<kind>/<name>`, or `<kind>` for the default datasource of a kind.
+	// A reference can only be added or removed.
+	Datasources []Change `json:"datasources"`
+}
+
+// Compare returns what changes from the dashboard a to the dashboard b.
+// The dashboards are compared in their JSON form, so two elements are equal when they have the same content.
+func Compare(a, b *v1.Dashboard) (*Comparison, error) {
+	specA, err := decodeSpec(a)
+	if err != nil {
+		return nil, err
+	}
+	specB, err := decodeSpec(b)
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{
+		Panels:      compareElements(panelsOf(specA), panelsOf(specB)),
+		Variables:   compareElements(variablesOf(specA), variablesOf(specB)),
+		Datasources: compareElements(datasourceRefsOf(specA), datasourceRefsOf(specB)),
+	}, nil
+}
+
+// compareElements compares the elements identified by their name.
+func compareElements(a, b map[string]any) []Change {
+	changes := []Change{}
+	for name, element := range a {
+		other, ok := b[name]
+		if !ok {
+			changes = append(changes, Change{Type: Removed, Name: name})
+		} else if !reflect.DeepEqual(element, other) {
+			changes = append(changes, Change{Type: Changed, Name: name})
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			changes = append(changes, Change{Type: Added, Name: name})
+		}
+	}
+	slices.SortFunc(changes, func(x, y Change) int {
+		return cmp.Compare(x.Name, y.Name)
+	})
+	return changes
+}
+
+// decodeSpec returns the generic JSON representation, made of maps and slices, of the spec of the dashboard.
+func decodeSpec(dashboard *v1.Dashboard) (map[string]any, error) {
+	data, err := json.Marshal(dashboard.Spec)
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]any{}
+	return spec, json.Unmarshal(data, &spec)
+}
+
+func panelsOf(spec map[string]any) map[string]any {
+	panels, _ := spec["panels"].(map[string]any)
+	return panels
+}
+
+func variablesOf(spec map[string]any) map[string]any {
+	result := map[string]any{}
+	variables, _ := spec["variables"].([]any)
+	for _, variable := range variables {
+		variableSpec, _ := variable.(map[string]any)["spec"].(map[string]any)
+		if name, ok := variableSpec["name"].(string); ok {
+			result[name] = variable
+		}
+	}
+	return result
+}
+
+// datasourceRefsOf returns the datasources referenced in the spec, i.e. the objects with a kind or a name under a field
+// `datasource`.
+func datasourceRefsOf(spec map[string]any) map[string]any {
+	result := map[string]any{}
+	collectDatasourceRefs(spec, result)
+	return result
+}
+
+func collectDatasourceRefs(value any, refs map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if key == "datasource" {
+				if ref, ok := datasourceRef(child); ok {
+					refs[ref] = true
+					continue
+				}
+			}
+			collectDatasourceRefs(child, refs)
+		}
+	case []any:
+		for _, child := range v {
+			collectDatasourceRefs(child, refs)
+		}
+	}
+}
+
+func datasourceRef(value any) (string, bool) {
+	selector, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	kind, _ := selector["kind"].(string)
+	name, _ := selector["name"].(string)
+	switch {
+	case len(kind) > 0 && len(name) > 0:
+		return kind + "/" + name, true
+	case len(kind) > 0:
+		return kind, true
+	case len(name) > 0:
+		return name, true
+	}
+	return "", false
+}
@@ -0,0 +1,153 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panelJSON(title string, datasource string) string {
+	return fmt.Sprintf(`{
+  "kind": "Panel",
+  "spec": {
+    "display": {"name": %q},
+    "plugin": {"kind": "TimeSeriesChart", "spec": {}},
+    "queries": [
+      {
+        "kind": "TimeSeriesQuery",
+        "spec": {
+          "plugin": {
+            "kind": "PrometheusTimeSeriesQuery",
+            "spec": {"datasource": %s, "query": "up"}
+          }
+        }
+      }
+    ]
+  }
+}`, title, datasource)
+}
+
+func variableJSON(name string, matcher string) string {
+	return fmt.Sprintf(`{
+  "kind": "ListVariable",
+  "spec": {
+    "name": %q,
+    "plugin": {"kind": "PrometheusLabelNamesVariable", "spec": {"matchers": [%q]}}
+  }
+}`, name, matcher)
+}
+
+func newDashboard(t *testing.T, panels map[string]string, variables []string) *v1.Dashboard {
+	panelsJSON := "{"
+	i := 0
+	for key, panel := range panels {
+		if i > 0 {
+			panelsJSON += ","
+		}
+		panelsJSON += fmt.Sprintf("%q: %s", key, panel)
+		i++
+	}
+	panelsJSON += "}"
+	variablesJSON := "["
+	for i, variable := range variables {
+		if i > 0 {
+			variablesJSON += ","
+		}
+		variablesJSON += variable
+	}
+	variablesJSON += "]"
+	data := fmt.Sprintf(`{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "perses"},
+  "spec": {"panels": %s, "variables": %s, "duration": "1h"}
+}`, panelsJSON, variablesJSON)
+	dashboard := &v1.Dashboard{}
+	require.NoError(t, json.Unmarshal([]byte(data), dashboard))
+	return dashboard
+}
+
+func TestCompare(t *testing.T) {
+	prom := `{"kind": "PrometheusDatasource", "name": "prom"}`
+	thanos := `{"kind": "PrometheusDatasource", "name": "thanos"}`
+	defaultProm := `{"kind": "PrometheusDatasource"}`
+	testSuite := []struct {
+		title  string
+		a      *v1.Dashboard
+		b      *v1.Dashboard
+		result *Comparison
+	}{
+		{
+			title: "same dashboards",
+			a:     newDashboard(t, map[string]string{"cpu": panelJSON("CPU", prom)}, []string{variableJSON("job", "up")}),
+			b:     newDashboard(t, map[string]string{"cpu": panelJSON("CPU", prom)}, []string{variableJSON("job", "up")}),
+			result: &Comparison{
+				Panels:      []Change{},
+				Variables:   []Change{},
+				Datasources: []Change{},
+			},
+		},
+		{
+			title: "added elements",
+			a:     newDashboard(t, map[string]string{"cpu": panelJSON("CPU", prom)}, nil),
+			b: newDashboard(t,
+				map[string]string{"cpu": panelJSON("CPU", prom), "memory": panelJSON("Memory", defaultProm)},
+				[]string{variableJSON("job", "up")},
+			),
+			result: &Comparison{
+				Panels:      []Change{{Type: Added, Name: "memory"}},
+				Variables:   []Change{{Type: Added, Name: "job"}},
+				Datasources: []Change{{Type: Added, Name: "PrometheusDatasource"}},
+			},
+		},
+		{
+			title: "removed elements",
+			a: newDashboard(t,
+				map[string]string{"cpu": panelJSON("CPU", prom), "memory": panelJSON("Memory", thanos)},
+				[]string{variableJSON("instance", "up"), variableJSON("job", "up")},
+			),
+			b: newDashboard(t, map[string]string{"cpu": panelJSON("CPU", prom)}, []string{variableJSON("job", "up")}),
+			result: &Comparison{
+				Panels:      []Change{{Type: Removed, Name: "memory"}},
+				Variables:   []Change{{Type: Removed, Name: "instance"}},
+				Datasources: []Change{{Type: Removed, Name: "PrometheusDatasource/thanos"}},
+			},
+		},
+		{
+			title: "changed elements",
+			a:     newDashboard(t, map[string]string{"cpu": panelJSON("CPU", prom)}, []string{variableJSON("job", "up")}),
+			b:     newDashboard(t, map[string]string{"cpu": panelJSON("CPU usage", thanos)}, []string{variableJSON("job", "node_uname_info")}),
+			result: &Comparison{
+				Panels:    []Change{{Type: Changed, Name: "cpu"}},
+				Variables: []Change{{Type: Changed, Name: "job"}},
+				Datasources: []Change{
+					{Type: Removed, Name: "PrometheusDatasource/prom"},
+					{Type: Added, Name: "PrometheusDatasource/thanos"},
+				},
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := Compare(test.a, test.b)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
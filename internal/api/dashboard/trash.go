@@ -0,0 +1,44 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+)
+
+// NewTrashCleaner returns the task deleting permanently the dashboards kept in the trash for longer than the retention.
+func NewTrashCleaner(service dashboard.Service, retention time.Duration) async.SimpleTask {
+	return &trashCleaner{
+		service:   service,
+		retention: retention,
+	}
+}
+
+type trashCleaner struct {
+	async.SimpleTask
+	service   dashboard.Service
+	retention time.Duration
+}
+
+func (c *trashCleaner) String() string {
+	return "dashboards trash cleaner"
+}
+
+func (c *trashCleaner) Execute(_ context.Context, _ context.CancelFunc) error {
+	return c.service.PurgeTrash(time.Now().Add(-c.retention))
+}
@@ -23,6 +23,7 @@ import (
 	databaseFile "github.com/perses/perses/internal/api/database/file"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	databaseSQL "github.com/perses/perses/internal/api/database/sql"
+	databaseStateless "github.com/perses/perses/internal/api/database/stateless"
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
@@ -51,6 +52,9 @@ func (d *dao) Create(entity modelAPI.Entity) error {
 func (d *dao) Upsert(entity modelAPI.Entity) error {
 	return d.client.Upsert(entity)
 }
+func (d *dao) UpdateIfVersion(entity modelAPI.Entity, version uint64) error {
+	return d.client.UpdateIfVersion(entity, version)
+}
 func (d *dao) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
 	return d.client.Get(kind, metadata, entity)
 }
@@ -147,8 +151,14 @@ func New(conf config.Database) (databaseModel.DAO, error) {
 			SchemaName:    c.DBName,
 			CaseSensitive: c.CaseSensitive,
 		}
+	} else if conf.Stateless != nil {
+		statelessDAO, err := databaseStateless.New(*conf.Stateless)
+		if err != nil {
+			return nil, err
+		}
+		client = statelessDAO
 	} else {
 		return nil, fmt.Errorf("no dao defined")
 	}
-	return &dao{client: client}, nil
+	return &dao{client: newInstrumentedDAO(client, time.Duration(conf.SlowQueryThreshold))}, nil
 }
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	modelAPI "github.com/perses/perses/pkg/model/api"
@@ -32,18 +33,45 @@ import (
 func generateID(kind modelV1.Kind, metadata modelAPI.Metadata) (string, error) {
 	switch m := metadata.(type) {
 	case *modelV1.ProjectMetadata:
-		return filepath.Join(modelV1.PluralKindMap[kind], m.Project, m.Name), nil
+		return filepath.Join(getPluralKind(kind), m.Project, m.Name), nil
 	case *modelV1.Metadata:
-		return filepath.Join(modelV1.PluralKindMap[kind], m.Name), nil
+		return filepath.Join(getPluralKind(kind), m.Name), nil
 	}
 	return "", fmt.Errorf("metadata %T not managed", metadata)
 }
 
+// getPluralKind returns the name of the folder containing the documents of the given kind.
+func getPluralKind(kind modelV1.Kind) string {
+	// The plugin states, the query audits, the history of the dashboards and the favorites are not resources of the API,
+	// that's why these kinds are not part of the PluralKindMap.
+	switch kind {
+	case modelV1.KindDashboardHistory:
+		return "dashboardhistories"
+	case modelV1.KindPluginState:
+		return "pluginstates"
+	case modelV1.KindQueryAudit:
+		return "queryaudits"
+	case modelV1.KindUserFavorite:
+		return "userfavorites"
+	}
+	return modelV1.PluralKindMap[kind]
+}
+
 type DAO struct {
 	databaseModel.DAO
 	Folder        string
 	Extension     config.FileExtension
 	CaseSensitive bool
+	// versionMutex makes the verification of the version and the update atomic in UpdateIfVersion.
+	// The files are not shared between several instances of Perses, so a lock within the process is enough.
+	versionMutex sync.Mutex
+}
+
+// versionedDocument is the part of a document needed to know the version of its metadata.
+type versionedDocument struct {
+	Metadata struct {
+		Version uint64 `json:"version" yaml:"version"`
+	} `json:"metadata" yaml:"metadata"`
 }
 
 func (d *DAO) Init() error {
@@ -83,6 +111,31 @@ func (d *DAO) Upsert(entity modelAPI.Entity) error {
 	}
 	return d.upsert(key, entity)
 }
+func (d *DAO) UpdateIfVersion(entity modelAPI.Entity, version uint64) error {
+	entity.GetMetadata().Flatten(d.CaseSensitive)
+	key, generateIDErr := generateID(modelV1.Kind(entity.GetKind()), entity.GetMetadata())
+	if generateIDErr != nil {
+		return generateIDErr
+	}
+	d.versionMutex.Lock()
+	defer d.versionMutex.Unlock()
+	data, err := os.ReadFile(d.buildPath(key)) //nolint: gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &databaseModel.Error{Key: key, Code: databaseModel.ErrorCodeNotFound}
+		}
+		return err
+	}
+	current := &versionedDocument{}
+	if unMarshalErr := d.unmarshal(data, current); unMarshalErr != nil {
+		return unMarshalErr
+	}
+	if current.Metadata.Version != version {
+		return &databaseModel.Error{Key: key, Code: databaseModel.ErrorCodeVersionMismatch}
+	}
+	return d.upsert(key, entity)
+}
+
 func (d *DAO) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
 	metadata.Flatten(d.CaseSensitive)
 	key, generateIDErr := generateID(kind, metadata)
@@ -16,9 +16,11 @@ package databasefile
 import (
 	"os"
 	"testing"
+	"time"
 
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/stretchr/testify/assert"
@@ -63,6 +65,28 @@ func TestDAO_Upsert(t *testing.T) {
 	removeAllFiles(t)
 }
 
+func TestDAO_UpdateIfVersion(t *testing.T) {
+	d := newDAO()
+	projectEntity := &modelV1.Project{
+		Kind: modelV1.KindProject,
+		Metadata: modelV1.Metadata{
+			Name:    "perses",
+			Version: 1,
+		},
+	}
+	assert.True(t, databaseModel.IsKeyNotFound(d.UpdateIfVersion(projectEntity, 1)))
+	assert.NoError(t, d.Create(projectEntity))
+	projectEntity.Metadata.Version = 2
+	assert.NoError(t, d.UpdateIfVersion(projectEntity, 1))
+	// the version 1 has been replaced, so a second update based on it must be rejected
+	projectEntity.Metadata.Version = 3
+	assert.True(t, databaseModel.IsVersionMismatch(d.UpdateIfVersion(projectEntity, 1)))
+	result := &modelV1.Project{}
+	assert.NoError(t, d.Get(modelV1.KindProject, &modelV1.Metadata{Name: "perses"}, result))
+	assert.Equal(t, uint64(2), result.Metadata.Version)
+	removeAllFiles(t)
+}
+
 func TestDAO_Get(t *testing.T) {
 	d := newDAO()
 	projectEntity := &modelV1.Project{
@@ -110,3 +134,18 @@ func TestDAO_Delete(t *testing.T) {
 	assert.True(t, databaseModel.IsKeyNotFound(d.Get(modelV1.KindProject, projectEntity.GetMetadata(), result)))
 	removeAllFiles(t)
 }
+
+func TestDAO_QueryAudit(t *testing.T) {
+	d := newDAO()
+	now := time.Now()
+	assert.NoError(t, d.Create(modelV1.NewQueryAudit(modelV1.QueryAuditSpec{Datasource: "prometheus", Query: "up", Status: 200, Time: now})))
+	assert.NoError(t, d.Create(modelV1.NewQueryAudit(modelV1.QueryAuditSpec{Datasource: "prometheus", Query: "down", Status: 200, Time: now})))
+	assert.NoError(t, d.Create(modelV1.NewQueryAudit(modelV1.QueryAuditSpec{Datasource: "thanos", Query: "up", Status: 502, Time: now})))
+	var result []*modelV1.QueryAudit
+	assert.NoError(t, d.Query(&queryaudit.Query{Datasource: "prometheus"}, &result))
+	assert.Len(t, result, 2)
+	var all []*modelV1.QueryAudit
+	assert.NoError(t, d.Query(&queryaudit.Query{}, &all))
+	assert.Len(t, all, 3)
+	removeAllFiles(t)
+}
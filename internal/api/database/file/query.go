@@ -20,7 +20,10 @@ import (
 	"strings"
 
 	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/ephemeraldashboard"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
@@ -29,11 +32,16 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
@@ -54,20 +62,29 @@ func isFolderExist(folder string) (bool, error) {
 func (d *DAO) generateProjectResourceQuery(kind v1.Kind, project string) string {
 	if len(project) == 0 {
 		// It's used when we query a list of object. It can happen that the project is empty.
-		return filepath.Join(d.Folder, v1.PluralKindMap[kind])
+		return filepath.Join(d.Folder, getPluralKind(kind))
 	}
-	return filepath.Join(d.Folder, v1.PluralKindMap[kind], project)
+	return filepath.Join(d.Folder, getPluralKind(kind), project)
 }
 
 func (d *DAO) generateResourceQuery(kind v1.Kind) string {
-	return filepath.Join(d.Folder, v1.PluralKindMap[kind])
+	return filepath.Join(d.Folder, getPluralKind(kind))
 }
 
 func (d *DAO) buildQuery(query databaseModel.Query) (pathFolder string, prefix string, isExist bool, err error) {
 	switch qt := query.(type) {
+	case *apikey.Query:
+		pathFolder = d.generateProjectResourceQuery(v1.KindAPIKey, qt.Project)
+		prefix = qt.NamePrefix
 	case *dashboard.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindDashboard, qt.Project)
 		prefix = qt.NamePrefix
+	case *dashboardhistory.Query:
+		pathFolder = d.generateProjectResourceQuery(v1.KindDashboardHistory, qt.Project)
+		prefix = qt.NamePrefix
+	case *dashboardtemplate.Query:
+		pathFolder = d.generateProjectResourceQuery(v1.KindDashboardTemplate, qt.Project)
+		prefix = qt.NamePrefix
 	case *datasource.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindDatasource, qt.Project)
 		prefix = qt.NamePrefix
@@ -92,9 +109,14 @@ func (d *DAO) buildQuery(query databaseModel.Query) (pathFolder string, prefix s
 	case *globalvariable.Query:
 		pathFolder = d.generateResourceQuery(v1.KindGlobalVariable)
 		prefix = qt.NamePrefix
+	case *pluginstate.Query:
+		pathFolder = d.generateProjectResourceQuery(v1.KindPluginState, qt.Module)
+		prefix = qt.NamePrefix
 	case *project.Query:
 		pathFolder = d.generateResourceQuery(v1.KindProject)
 		prefix = qt.NamePrefix
+	case *queryaudit.Query:
+		pathFolder = d.generateProjectResourceQuery(v1.KindQueryAudit, qt.Datasource)
 	case *role.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindRole, qt.Project)
 		prefix = qt.NamePrefix
@@ -104,9 +126,18 @@ func (d *DAO) buildQuery(query databaseModel.Query) (pathFolder string, prefix s
 	case *secret.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindSecret, qt.Project)
 		prefix = qt.NamePrefix
+	case *templatepolicy.Query:
+		pathFolder = d.generateResourceQuery(v1.KindTemplatePolicy)
+		prefix = qt.NamePrefix
+	case *templatepolicybinding.Query:
+		pathFolder = d.generateResourceQuery(v1.KindTemplatePolicyBinding)
+		prefix = qt.NamePrefix
 	case *user.Query:
 		pathFolder = d.generateResourceQuery(v1.KindUser)
 		prefix = qt.NamePrefix
+	case *userfavorite.Query:
+		pathFolder = d.generateResourceQuery(v1.KindUserFavorite)
+		prefix = qt.NamePrefix
 	case *variable.Query:
 		pathFolder = d.generateProjectResourceQuery(v1.KindVariable, qt.Project)
 		prefix = qt.NamePrefix
@@ -19,10 +19,13 @@ import (
 
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
 	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +53,15 @@ func TestBuildQuery(t *testing.T) {
 			expectedPath:       "dashboards",
 			expectedNamePrefix: "meta",
 		},
+		{
+			title: "dashboardHistoryQuery",
+			query: &dashboardhistory.Query{
+				NamePrefix: "0123456789abcdef-",
+				Project:    "perses",
+			},
+			expectedPath:       filepath.Join("dashboardhistories", "perses"),
+			expectedNamePrefix: "0123456789abcdef-",
+		},
 		{
 			title: "datasourceQuery",
 			query: &datasource.Query{
@@ -84,6 +96,21 @@ func TestBuildQuery(t *testing.T) {
 			expectedPath:       "projects",
 			expectedNamePrefix: "meta",
 		},
+		{
+			title: "queryAuditQuery",
+			query: &queryaudit.Query{
+				Datasource: "prometheus",
+			},
+			expectedPath: filepath.Join("queryaudits", "prometheus"),
+		},
+		{
+			title: "userFavoriteQuery",
+			query: &userfavorite.Query{
+				NamePrefix: "0123456789abcdef-",
+			},
+			expectedPath:       "userfavorites",
+			expectedNamePrefix: "0123456789abcdef-",
+		},
 	}
 
 	for _, test := range testSuite {
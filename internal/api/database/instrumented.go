@@ -0,0 +1,161 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/utils"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// A histogram for the duration of the operations made against the database.
+var operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: utils.MetricNamespace,
+	Name:      "storage_operation_duration_seconds",
+	Help:      "The duration of the operations made against the database",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"operation"})
+
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(operationDuration)
+}
+
+// instrumentedDAO measures every call made to the underlying DAO.
+// Each duration is recorded in a Prometheus histogram, and the calls taking longer than the threshold are logged.
+type instrumentedDAO struct {
+	client    databaseModel.DAO
+	threshold time.Duration
+}
+
+func newInstrumentedDAO(client databaseModel.DAO, threshold time.Duration) databaseModel.DAO {
+	return &instrumentedDAO{
+		client:    client,
+		threshold: threshold,
+	}
+}
+
+// observe must be called with defer at the beginning of each instrumented method.
+// The key is only computed when the operation is slow, to avoid paying its cost on every call.
+func (d *instrumentedDAO) observe(operation string, start time.Time, key func() string) {
+	elapsed := time.Since(start)
+	operationDuration.WithLabelValues(operation).Observe(elapsed.Seconds())
+	if d.threshold > 0 && elapsed > d.threshold {
+		logrus.WithFields(logrus.Fields{
+			"operation": operation,
+			"key":       key(),
+			"elapsed":   elapsed.String(),
+		}).Warn("slow database operation")
+	}
+}
+
+func entityKey(entity modelAPI.Entity) func() string {
+	return func() string {
+		return metadataKey(modelV1.Kind(entity.GetKind()), entity.GetMetadata())()
+	}
+}
+
+func metadataKey(kind modelV1.Kind, metadata modelAPI.Metadata) func() string {
+	return func() string {
+		if projectMetadata, ok := metadata.(*modelV1.ProjectMetadata); ok {
+			return fmt.Sprintf("%s/%s/%s", kind, projectMetadata.Project, projectMetadata.Name)
+		}
+		return fmt.Sprintf("%s/%s", kind, metadata.GetName())
+	}
+}
+
+func queryKey(query databaseModel.Query) func() string {
+	return func() string {
+		return fmt.Sprintf("%T%+v", query, query)
+	}
+}
+
+func noKey() string {
+	return ""
+}
+
+func (d *instrumentedDAO) Close() error {
+	defer d.observe("close", time.Now(), noKey)
+	return d.client.Close()
+}
+
+func (d *instrumentedDAO) Init() error {
+	defer d.observe("init", time.Now(), noKey)
+	return d.client.Init()
+}
+
+func (d *instrumentedDAO) IsCaseSensitive() bool {
+	return d.client.IsCaseSensitive()
+}
+
+func (d *instrumentedDAO) Create(entity modelAPI.Entity) error {
+	defer d.observe("create", time.Now(), entityKey(entity))
+	return d.client.Create(entity)
+}
+
+func (d *instrumentedDAO) Upsert(entity modelAPI.Entity) error {
+	defer d.observe("upsert", time.Now(), entityKey(entity))
+	return d.client.Upsert(entity)
+}
+
+func (d *instrumentedDAO) UpdateIfVersion(entity modelAPI.Entity, version uint64) error {
+	defer d.observe("update_if_version", time.Now(), entityKey(entity))
+	return d.client.UpdateIfVersion(entity, version)
+}
+
+func (d *instrumentedDAO) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
+	defer d.observe("get", time.Now(), metadataKey(kind, metadata))
+	return d.client.Get(kind, metadata, entity)
+}
+
+func (d *instrumentedDAO) Query(query databaseModel.Query, slice any) error {
+	defer d.observe("query", time.Now(), queryKey(query))
+	return d.client.Query(query, slice)
+}
+
+func (d *instrumentedDAO) RawQuery(query databaseModel.Query) ([]json.RawMessage, error) {
+	defer d.observe("raw_query", time.Now(), queryKey(query))
+	return d.client.RawQuery(query)
+}
+
+func (d *instrumentedDAO) RawMetadataQuery(query databaseModel.Query, kind modelV1.Kind) ([]json.RawMessage, error) {
+	defer d.observe("raw_metadata_query", time.Now(), queryKey(query))
+	return d.client.RawMetadataQuery(query, kind)
+}
+
+func (d *instrumentedDAO) Delete(kind modelV1.Kind, metadata modelAPI.Metadata) error {
+	defer d.observe("delete", time.Now(), metadataKey(kind, metadata))
+	return d.client.Delete(kind, metadata)
+}
+
+func (d *instrumentedDAO) DeleteByQuery(query databaseModel.Query) error {
+	defer d.observe("delete_by_query", time.Now(), queryKey(query))
+	return d.client.DeleteByQuery(query)
+}
+
+func (d *instrumentedDAO) HealthCheck() bool {
+	defer d.observe("health_check", time.Now(), noKey)
+	return d.client.HealthCheck()
+}
+
+func (d *instrumentedDAO) GetLatestUpdateTime(kind []modelV1.Kind) (*string, error) {
+	defer d.observe("get_latest_update_time", time.Now(), func() string { return fmt.Sprintf("%v", kind) })
+	return d.client.GetLatestUpdateTime(kind)
+}
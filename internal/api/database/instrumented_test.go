@@ -0,0 +1,62 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowDAO is a DAO where every call to Get takes the configured delay.
+type slowDAO struct {
+	databaseModel.DAO
+	delay time.Duration
+}
+
+func (d *slowDAO) Get(_ modelV1.Kind, _ modelAPI.Metadata, _ modelAPI.Entity) error {
+	time.Sleep(d.delay)
+	return nil
+}
+
+func TestInstrumentedDAO_SlowOperation(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	d := newInstrumentedDAO(&slowDAO{delay: 20 * time.Millisecond}, 5*time.Millisecond)
+	assert.NoError(t, d.Get(modelV1.KindDashboard, modelV1.NewProjectMetadata("perses", "demo"), &modelV1.Dashboard{}))
+
+	entry := hook.LastEntry()
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "get", entry.Data["operation"])
+		assert.Equal(t, "Dashboard/perses/demo", entry.Data["key"])
+		assert.NotEmpty(t, entry.Data["elapsed"])
+	}
+}
+
+func TestInstrumentedDAO_FastOperation(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	d := newInstrumentedDAO(&slowDAO{}, time.Second)
+	assert.NoError(t, d.Get(modelV1.KindProject, modelV1.NewMetadata("perses"), &modelV1.Project{}))
+	assert.Empty(t, hook.AllEntries())
+}
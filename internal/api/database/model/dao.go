@@ -33,6 +33,9 @@ type DAO interface {
 	IsCaseSensitive() bool
 	Create(entity modelAPI.Entity) error
 	Upsert(entity modelAPI.Entity) error
+	// UpdateIfVersion replaces the stored entity only if the version of its metadata is still the given one.
+	// The verification and the update are atomic. An error with the code ErrorCodeVersionMismatch is returned otherwise.
+	UpdateIfVersion(entity modelAPI.Entity, version uint64) error
 	// Get will find a unique object. It will depend on the implementation to generate the key based on the kind and the metadata.
 	// entity is the object that will be used by the method to set the value returned by the database.
 	Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error
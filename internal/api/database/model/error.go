@@ -16,8 +16,9 @@ package model
 import "fmt"
 
 const (
-	ErrorCodeConflict = 409
-	ErrorCodeNotFound = 404
+	ErrorCodeConflict        = 409
+	ErrorCodeNotFound        = 404
+	ErrorCodeVersionMismatch = 412
 )
 
 // IsKeyNotFound returns true if the error code is ErrorCodeNotFound.
@@ -36,6 +37,14 @@ func IsKeyConflict(err error) bool {
 	return false
 }
 
+// IsVersionMismatch returns true if the error code is ErrorCodeVersionMismatch.
+func IsVersionMismatch(err error) bool {
+	if cErr, ok := err.(*Error); ok {
+		return cErr.Code == ErrorCodeVersionMismatch
+	}
+	return false
+}
+
 type Error struct {
 	Key  string
 	Code int
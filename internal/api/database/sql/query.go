@@ -20,7 +20,10 @@ import (
 
 	"github.com/huandu/go-sqlbuilder"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/ephemeraldashboard"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
@@ -29,11 +32,16 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalrolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
@@ -91,6 +99,23 @@ func (d *DAO) generateUpdateQuery(entity modelAPI.Entity) (string, []any, error)
 	return sql, args, nil
 }
 
+// generateUpdateIfVersionQuery generates the update of the entity matching only the row whose metadata still has the given version.
+func (d *DAO) generateUpdateIfVersionQuery(entity modelAPI.Entity, version uint64) (string, []any, error) {
+	id, tableName, idErr := d.getIDAndTableName(modelV1.Kind(entity.GetKind()), entity.GetMetadata())
+	if idErr != nil {
+		return "", nil, idErr
+	}
+	rowJSONDoc, unmarshalErr := json.Marshal(entity)
+	if unmarshalErr != nil {
+		return "", nil, unmarshalErr
+	}
+	builder := sqlbuilder.NewUpdateBuilder().Update(tableName)
+	builder.Where(builder.Equal(colID, id), builder.Equal(colMetadataVersion, version))
+	builder.Set(builder.Assign(colDoc, rowJSONDoc))
+	sql, args := builder.Build()
+	return sql, args, nil
+}
+
 func (d *DAO) generateSelectQuery(tableName string, project string, name string) (string, []any) {
 	p := project
 	n := name
@@ -114,8 +139,14 @@ func (d *DAO) buildQuery(query databaseModel.Query) (string, []any, error) {
 	var sqlQuery string
 	var args []any
 	switch qt := query.(type) {
+	case *apikey.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableAPIKey), qt.Project, qt.NamePrefix)
 	case *dashboard.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableDashboard), qt.Project, qt.NamePrefix)
+	case *dashboardhistory.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableDashboardHistory), qt.Project, qt.NamePrefix)
+	case *dashboardtemplate.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableDashboardTemplate), qt.Project, qt.NamePrefix)
 	case *datasource.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableDatasource), qt.Project, qt.NamePrefix)
 	case *ephemeraldashboard.Query:
@@ -132,16 +163,26 @@ func (d *DAO) buildQuery(query databaseModel.Query) (string, []any, error) {
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableGlobalSecret), "", qt.NamePrefix)
 	case *globalvariable.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableGlobalVariable), "", qt.NamePrefix)
+	case *pluginstate.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tablePluginState), qt.Module, qt.NamePrefix)
 	case *project.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableProject), "", qt.NamePrefix)
+	case *queryaudit.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableQueryAudit), qt.Datasource, "")
 	case *role.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableRole), qt.Project, qt.NamePrefix)
 	case *rolebinding.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableRoleBinding), qt.Project, qt.NamePrefix)
 	case *secret.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableSecret), qt.Project, qt.NamePrefix)
+	case *templatepolicy.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableTemplatePolicy), "", qt.NamePrefix)
+	case *templatepolicybinding.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableTemplatePolicyBinding), "", qt.NamePrefix)
 	case *user.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableUser), "", qt.NamePrefix)
+	case *userfavorite.Query:
+		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableUserFavorite), "", qt.NamePrefix)
 	case *variable.Query:
 		sqlQuery, args = d.generateSelectQuery(d.generateCompleteTableName(tableVariable), qt.Project, qt.NamePrefix)
 	default:
@@ -173,8 +214,14 @@ func (d *DAO) buildDeleteQuery(query databaseModel.Query) (string, []any, error)
 	var sqlQuery string
 	var args []any
 	switch qt := query.(type) {
+	case *apikey.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableAPIKey), qt.Project, qt.NamePrefix)
 	case *dashboard.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableDashboard), qt.Project, qt.NamePrefix)
+	case *dashboardhistory.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableDashboardHistory), qt.Project, qt.NamePrefix)
+	case *dashboardtemplate.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableDashboardTemplate), qt.Project, qt.NamePrefix)
 	case *datasource.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableDatasource), qt.Project, qt.NamePrefix)
 	case *ephemeraldashboard.Query:
@@ -191,16 +238,26 @@ func (d *DAO) buildDeleteQuery(query databaseModel.Query) (string, []any, error)
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableGlobalSecret), "", qt.NamePrefix)
 	case *globalvariable.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableGlobalVariable), "", qt.NamePrefix)
+	case *pluginstate.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tablePluginState), qt.Module, qt.NamePrefix)
 	case *project.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableProject), "", qt.NamePrefix)
+	case *queryaudit.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableQueryAudit), qt.Datasource, "")
 	case *role.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableRole), qt.Project, qt.NamePrefix)
 	case *rolebinding.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableRoleBinding), qt.Project, qt.NamePrefix)
 	case *secret.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableSecret), qt.Project, qt.NamePrefix)
+	case *templatepolicy.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableTemplatePolicy), "", qt.NamePrefix)
+	case *templatepolicybinding.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableTemplatePolicyBinding), "", qt.NamePrefix)
 	case *user.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableUser), "", qt.NamePrefix)
+	case *userfavorite.Query:
+		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableUserFavorite), "", qt.NamePrefix)
 	case *variable.Query:
 		sqlQuery, args = d.generateDeleteQuery(d.generateCompleteTableName(tableVariable), qt.Project, qt.NamePrefix)
 	default:
@@ -16,7 +16,9 @@ package databasesql
 import (
 	"testing"
 
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateProjectResourceSelectQuery(t *testing.T) {
@@ -65,3 +67,13 @@ func TestGenerateProjectResourceSelectQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateUpdateIfVersionQuery(t *testing.T) {
+	d := &DAO{SchemaName: "perses"}
+	entity := &modelV1.Project{Kind: modelV1.KindProject, Metadata: modelV1.Metadata{Name: "foo", Version: 4}}
+	sqlQuery, args, err := d.generateUpdateIfVersionQuery(entity, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE perses.project SET doc = ? WHERE id = ? AND JSON_EXTRACT(doc, '$.metadata.version') = ?", sqlQuery)
+	require.Len(t, args, 3)
+	assert.Equal(t, []any{"foo", uint64(3)}, args[1:])
+}
@@ -29,32 +29,48 @@ import (
 )
 
 const (
-	tableDashboard          = "dashboard"
-	tableDatasource         = "datasource"
-	tableEphemeralDashboard = "ephemeraldashboard"
-	tableFolder             = "folder"
-	tableGlobalDatasource   = "globaldatasource"
-	tableGlobalRole         = "globalrole"
-	tableGlobalRoleBinding  = "globalrolebinding"
-	tableGlobalSecret       = "globalsecret"
-	tableGlobalVariable     = "globalvariable"
-	tableProject            = "project"
-	tableRole               = "role"
-	tableRoleBinding        = "rolebinding"
-	tableSecret             = "secret"
-	tableUser               = "user"
-	tableVariable           = "variable"
+	tableAPIKey                = "apikey"
+	tableDashboard             = "dashboard"
+	tableDashboardHistory      = "dashboard_history"
+	tableDashboardTemplate     = "dashboardtemplate"
+	tableDatasource            = "datasource"
+	tableEphemeralDashboard    = "ephemeraldashboard"
+	tableFolder                = "folder"
+	tableGlobalDatasource      = "globaldatasource"
+	tableGlobalRole            = "globalrole"
+	tableGlobalRoleBinding     = "globalrolebinding"
+	tableGlobalSecret          = "globalsecret"
+	tableGlobalVariable        = "globalvariable"
+	tablePluginState           = "pluginstate"
+	tableProject               = "project"
+	tableQueryAudit            = "query_audit"
+	tableRole                  = "role"
+	tableRoleBinding           = "rolebinding"
+	tableSecret                = "secret"
+	tableTemplatePolicy        = "templatepolicy"
+	tableTemplatePolicyBinding = "templatepolicybinding"
+	tableUser                  = "user"
+	tableUserFavorite          = "user_favorite"
+	tableVariable              = "variable"
 
 	colID      = "id"
 	colDoc     = "doc"
 	colName    = "name"
 	colProject = "project"
+	// colMetadataVersion is the version of the metadata, read from the document.
+	colMetadataVersion = "JSON_EXTRACT(doc, '$.metadata.version')"
 )
 
 func getTableName(kind modelV1.Kind) (string, error) {
 	switch kind {
+	case modelV1.KindAPIKey:
+		return tableAPIKey, nil
 	case modelV1.KindDashboard:
 		return tableDashboard, nil
+	case modelV1.KindDashboardHistory:
+		return tableDashboardHistory, nil
+	case modelV1.KindDashboardTemplate:
+		return tableDashboardTemplate, nil
 	case modelV1.KindDatasource:
 		return tableDatasource, nil
 	case modelV1.KindEphemeralDashboard:
@@ -71,16 +87,26 @@ func getTableName(kind modelV1.Kind) (string, error) {
 		return tableGlobalSecret, nil
 	case modelV1.KindGlobalVariable:
 		return tableGlobalVariable, nil
+	case modelV1.KindPluginState:
+		return tablePluginState, nil
 	case modelV1.KindProject:
 		return tableProject, nil
+	case modelV1.KindQueryAudit:
+		return tableQueryAudit, nil
 	case modelV1.KindRole:
 		return tableRole, nil
 	case modelV1.KindRoleBinding:
 		return tableRoleBinding, nil
 	case modelV1.KindSecret:
 		return tableSecret, nil
+	case modelV1.KindTemplatePolicy:
+		return tableTemplatePolicy, nil
+	case modelV1.KindTemplatePolicyBinding:
+		return tableTemplatePolicyBinding, nil
 	case modelV1.KindUser:
 		return tableUser, nil
+	case modelV1.KindUserFavorite:
+		return tableUserFavorite, nil
 	case modelV1.KindVariable:
 		return tableVariable, nil
 	default:
@@ -113,12 +139,20 @@ func (d *DAO) Init() error {
 		d.createResourceTable(tableGlobalSecret),
 		d.createResourceTable(tableGlobalVariable),
 		d.createResourceTable(tableProject),
+		d.createResourceTable(tableTemplatePolicy),
+		d.createResourceTable(tableTemplatePolicyBinding),
 		d.createResourceTable(tableUser),
+		d.createResourceTable(tableUserFavorite),
 
+		d.createProjectResourceTable(tableAPIKey),
 		d.createProjectResourceTable(tableDashboard),
+		d.createProjectResourceTable(tableDashboardHistory),
+		d.createProjectResourceTable(tableDashboardTemplate),
 		d.createProjectResourceTable(tableDatasource),
 		d.createProjectResourceTable(tableEphemeralDashboard),
 		d.createProjectResourceTable(tableFolder),
+		d.createProjectResourceTable(tablePluginState),
+		d.createProjectResourceTable(tableQueryAudit),
 		d.createProjectResourceTable(tableRole),
 		d.createProjectResourceTable(tableRoleBinding),
 		d.createProjectResourceTable(tableSecret),
@@ -248,6 +282,35 @@ func (d *DAO) Upsert(entity modelAPI.Entity) error {
 	return upsertQuery.Close()
 }
 
+// UpdateIfVersion updates the row of the entity only if it still holds the given version.
+// When no row is updated, the entity is either missing or modified in the meantime.
+func (d *DAO) UpdateIfVersion(entity modelAPI.Entity, version uint64) error {
+	entity.GetMetadata().Flatten(d.CaseSensitive)
+	sqlQuery, args, queryGeneratorErr := d.generateUpdateIfVersionQuery(entity, version)
+	if queryGeneratorErr != nil {
+		return queryGeneratorErr
+	}
+	result, updateErr := d.DB.Exec(sqlQuery, args...)
+	if updateErr != nil {
+		return updateErr
+	}
+	rows, rowsErr := result.RowsAffected()
+	if rowsErr != nil {
+		return rowsErr
+	}
+	if rows > 0 {
+		return nil
+	}
+	id, isExist, err := d.exists(modelV1.Kind(entity.GetKind()), entity.GetMetadata())
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		return &databaseModel.Error{Key: id, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return &databaseModel.Error{Key: id, Code: databaseModel.ErrorCodeVersionMismatch}
+}
+
 func (d *DAO) Get(kind modelV1.Kind, metadata modelAPI.Metadata, entity modelAPI.Entity) error {
 	metadata.Flatten(d.CaseSensitive)
 	id, query, queryErr := d.get(kind, metadata)
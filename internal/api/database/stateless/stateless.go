@@ -0,0 +1,95 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package databasestateless provides a database that doesn't persist anything.
+// The resources are read at startup from a folder, typically a Kubernetes ConfigMap mounted in the container,
+// and are kept in a scratch folder that is removed when Perses stops.
+package databasestateless
+
+import (
+	"fmt"
+	"os"
+
+	databaseFile "github.com/perses/perses/internal/api/database/file"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/cli/file"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// DAO never modifies the source folder. Perses is forced in readonly mode when using it, so the only writes it receives
+// come from Perses itself (like the state of the plugins), and they are lost when Perses stops.
+type DAO struct {
+	*databaseFile.DAO
+	source string
+}
+
+func New(conf config.Stateless) (*DAO, error) {
+	scratch, err := os.MkdirTemp("", "perses-stateless-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the scratch folder of the stateless database: %w", err)
+	}
+	return &DAO{
+		DAO: &databaseFile.DAO{
+			Folder:        scratch,
+			Extension:     config.JSONExtension,
+			CaseSensitive: conf.CaseSensitive,
+		},
+		source: conf.Folder,
+	}, nil
+}
+
+// Init loads every resource available in the source folder.
+func (d *DAO) Init() error {
+	entities, errs := file.UnmarshalEntitiesFromDirectory(d.source)
+	for _, err := range errs {
+		logrus.WithError(err).Warningf("unable to load every resource from the folder %q", d.source)
+	}
+	projects := make(map[string]bool)
+	for _, entity := range entities {
+		if m, ok := entity.GetMetadata().(interface{ CreateNow() }); ok {
+			m.CreateNow()
+		}
+		if err := d.Upsert(entity); err != nil {
+			return fmt.Errorf("unable to load the %s %q: %w", entity.GetKind(), entity.GetMetadata().GetName(), err)
+		}
+		if m, ok := entity.GetMetadata().(*modelV1.ProjectMetadata); ok {
+			projects[m.Project] = true
+		}
+	}
+	// The projects don't have to be defined in the folder, they are created from the resources referencing them.
+	for name := range projects {
+		if err := d.createProject(name); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("%d resources loaded from the folder %q", len(entities), d.source)
+	return nil
+}
+
+func (d *DAO) createProject(name string) error {
+	project := &modelV1.Project{
+		Kind:     modelV1.KindProject,
+		Metadata: modelV1.Metadata{Name: name},
+	}
+	project.Metadata.CreateNow()
+	if err := d.Create(project); err != nil && !databaseModel.IsKeyConflict(err) {
+		return fmt.Errorf("unable to create the project %q: %w", name, err)
+	}
+	return nil
+}
+
+func (d *DAO) Close() error {
+	return os.RemoveAll(d.Folder)
+}
@@ -0,0 +1,71 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasestateless
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardYAML = `kind: Dashboard
+metadata:
+  name: demo
+  project: perses
+spec:
+  display:
+    name: Demo
+  panels: {}
+  layouts: []
+  duration: 1h
+`
+
+func TestStatelessDAO(t *testing.T) {
+	source := t.TempDir()
+	// resources can be organized in sub folders
+	require.NoError(t, os.Mkdir(filepath.Join(source, "dashboards"), 0o755))
+	dashboardFile := filepath.Join(source, "dashboards", "demo.yaml")
+	require.NoError(t, os.WriteFile(dashboardFile, []byte(dashboardYAML), 0o600))
+
+	dao, err := New(config.Stateless{Folder: source, CaseSensitive: true})
+	require.NoError(t, err)
+	require.NoError(t, dao.Init())
+
+	dashboard := &modelV1.Dashboard{}
+	require.NoError(t, dao.Get(modelV1.KindDashboard, modelV1.NewProjectMetadata("perses", "demo"), dashboard))
+	assert.Equal(t, "Demo", dashboard.Spec.Display.Name)
+
+	// the project is created from the dashboard referencing it
+	project := &modelV1.Project{}
+	require.NoError(t, dao.Get(modelV1.KindProject, modelV1.NewMetadata("perses"), project))
+
+	// what Perses saves doesn't reach the source folder
+	require.NoError(t, dao.Delete(modelV1.KindDashboard, modelV1.NewProjectMetadata("perses", "demo")))
+	err = dao.Get(modelV1.KindDashboard, modelV1.NewProjectMetadata("perses", "demo"), &modelV1.Dashboard{})
+	assert.True(t, databaseModel.IsKeyNotFound(err))
+	data, err := os.ReadFile(dashboardFile)
+	require.NoError(t, err)
+	assert.Equal(t, dashboardYAML, string(data))
+
+	scratch := dao.Folder
+	require.NoError(t, dao.Close())
+	_, err = os.Stat(scratch)
+	assert.True(t, os.IsNotExist(err))
+}
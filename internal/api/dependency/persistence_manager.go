@@ -16,7 +16,10 @@ package dependency
 import (
 	"github.com/perses/perses/internal/api/database"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiKeyImpl "github.com/perses/perses/internal/api/impl/v1/apikey"
 	dashboardImpl "github.com/perses/perses/internal/api/impl/v1/dashboard"
+	dashboardHistoryImpl "github.com/perses/perses/internal/api/impl/v1/dashboardhistory"
+	dashboardTemplateImpl "github.com/perses/perses/internal/api/impl/v1/dashboardtemplate"
 	datasourceImpl "github.com/perses/perses/internal/api/impl/v1/datasource"
 	ephemeralDashboardImpl "github.com/perses/perses/internal/api/impl/v1/ephemeraldashboard"
 	folderImpl "github.com/perses/perses/internal/api/impl/v1/folder"
@@ -26,13 +29,21 @@ import (
 	globalSecretImpl "github.com/perses/perses/internal/api/impl/v1/globalsecret"
 	globalVariableImpl "github.com/perses/perses/internal/api/impl/v1/globalvariable"
 	healthImpl "github.com/perses/perses/internal/api/impl/v1/health"
+	pluginStateImpl "github.com/perses/perses/internal/api/impl/v1/pluginstate"
 	projectImpl "github.com/perses/perses/internal/api/impl/v1/project"
+	queryAuditImpl "github.com/perses/perses/internal/api/impl/v1/queryaudit"
 	roleImpl "github.com/perses/perses/internal/api/impl/v1/role"
 	roleBindingImpl "github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	secretImpl "github.com/perses/perses/internal/api/impl/v1/secret"
+	templatePolicyImpl "github.com/perses/perses/internal/api/impl/v1/templatepolicy"
+	templatePolicyBindingImpl "github.com/perses/perses/internal/api/impl/v1/templatepolicybinding"
 	userImpl "github.com/perses/perses/internal/api/impl/v1/user"
+	userFavoriteImpl "github.com/perses/perses/internal/api/impl/v1/userfavorite"
 	variableImpl "github.com/perses/perses/internal/api/impl/v1/variable"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/ephemeraldashboard"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
@@ -42,17 +53,25 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
 	"github.com/perses/perses/internal/api/interface/v1/health"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	"github.com/perses/perses/pkg/model/api/config"
 )
 
 type PersistenceManager interface {
+	GetAPIKey() apikey.DAO
 	GetDashboard() dashboard.DAO
+	GetDashboardHistory() dashboardhistory.DAO
+	GetDashboardTemplate() dashboardtemplate.DAO
 	GetDatasource() datasource.DAO
 	GetEphemeralDashboard() ephemeraldashboard.DAO
 	GetFolder() folder.DAO
@@ -63,33 +82,46 @@ type PersistenceManager interface {
 	GetGlobalVariable() globalvariable.DAO
 	GetHealth() health.DAO
 	GetPersesDAO() databaseModel.DAO
+	GetPluginState() pluginstate.DAO
 	GetProject() project.DAO
+	GetQueryAudit() queryaudit.DAO
 	GetRole() role.DAO
 	GetRoleBinding() rolebinding.DAO
 	GetSecret() secret.DAO
+	GetTemplatePolicy() templatepolicy.DAO
+	GetTemplatePolicyBinding() templatepolicybinding.DAO
 	GetUser() user.DAO
+	GetUserFavorite() userfavorite.DAO
 	GetVariable() variable.DAO
 }
 
 type persistence struct {
 	PersistenceManager
-	dashboard          dashboard.DAO
-	datasource         datasource.DAO
-	ephemeralDashboard ephemeraldashboard.DAO
-	folder             folder.DAO
-	globalDatasource   globaldatasource.DAO
-	globalRole         globalrole.DAO
-	globalRoleBinding  globalrolebinding.DAO
-	globalSecret       globalsecret.DAO
-	globalVariable     globalvariable.DAO
-	health             health.DAO
-	perses             databaseModel.DAO
-	project            project.DAO
-	role               role.DAO
-	roleBinding        rolebinding.DAO
-	secret             secret.DAO
-	user               user.DAO
-	variable           variable.DAO
+	apiKey                apikey.DAO
+	dashboard             dashboard.DAO
+	dashboardHistory      dashboardhistory.DAO
+	dashboardTemplate     dashboardtemplate.DAO
+	datasource            datasource.DAO
+	ephemeralDashboard    ephemeraldashboard.DAO
+	folder                folder.DAO
+	globalDatasource      globaldatasource.DAO
+	globalRole            globalrole.DAO
+	globalRoleBinding     globalrolebinding.DAO
+	globalSecret          globalsecret.DAO
+	globalVariable        globalvariable.DAO
+	health                health.DAO
+	perses                databaseModel.DAO
+	pluginState           pluginstate.DAO
+	project               project.DAO
+	queryAudit            queryaudit.DAO
+	role                  role.DAO
+	roleBinding           rolebinding.DAO
+	secret                secret.DAO
+	templatePolicy        templatepolicy.DAO
+	templatePolicyBinding templatepolicybinding.DAO
+	user                  user.DAO
+	userFavorite          userfavorite.DAO
+	variable              variable.DAO
 }
 
 func newPersistenceManager(conf config.Database) (PersistenceManager, error) {
@@ -97,7 +129,10 @@ func newPersistenceManager(conf config.Database) (PersistenceManager, error) {
 	if err != nil {
 		return nil, err
 	}
+	apiKeyDAO := apiKeyImpl.NewDAO(persesDAO)
 	dashboardDAO := dashboardImpl.NewDAO(persesDAO)
+	dashboardHistoryDAO := dashboardHistoryImpl.NewDAO(persesDAO)
+	dashboardTemplateDAO := dashboardTemplateImpl.NewDAO(persesDAO)
 	datasourceDAO := datasourceImpl.NewDAO(persesDAO)
 	ephemeralDashboardDAO := ephemeralDashboardImpl.NewDAO(persesDAO)
 	folderDAO := folderImpl.NewDAO(persesDAO)
@@ -107,37 +142,62 @@ func newPersistenceManager(conf config.Database) (PersistenceManager, error) {
 	globalSecretDAO := globalSecretImpl.NewDAO(persesDAO)
 	globalVariableDAO := globalVariableImpl.NewDAO(persesDAO)
 	healthDAO := healthImpl.NewDAO(persesDAO)
+	pluginStateDAO := pluginStateImpl.NewDAO(persesDAO)
 	projectDAO := projectImpl.NewDAO(persesDAO)
+	queryAuditDAO := queryAuditImpl.NewDAO(persesDAO)
 	roleDAO := roleImpl.NewDAO(persesDAO)
 	roleBindingDAO := roleBindingImpl.NewDAO(persesDAO)
 	secretDAO := secretImpl.NewDAO(persesDAO)
+	templatePolicyDAO := templatePolicyImpl.NewDAO(persesDAO)
+	templatePolicyBindingDAO := templatePolicyBindingImpl.NewDAO(persesDAO)
 	userDAO := userImpl.NewDAO(persesDAO)
+	userFavoriteDAO := userFavoriteImpl.NewDAO(persesDAO)
 	variableDAO := variableImpl.NewDAO(persesDAO)
 	return &persistence{
-		dashboard:          dashboardDAO,
-		datasource:         datasourceDAO,
-		ephemeralDashboard: ephemeralDashboardDAO,
-		folder:             folderDAO,
-		globalDatasource:   globalDatatasourceDAO,
-		globalRole:         globalRoleDAO,
-		globalRoleBinding:  globalRoleBindingDAO,
-		globalSecret:       globalSecretDAO,
-		globalVariable:     globalVariableDAO,
-		health:             healthDAO,
-		perses:             persesDAO,
-		project:            projectDAO,
-		role:               roleDAO,
-		roleBinding:        roleBindingDAO,
-		secret:             secretDAO,
-		user:               userDAO,
-		variable:           variableDAO,
+		apiKey:                apiKeyDAO,
+		dashboard:             dashboardDAO,
+		dashboardHistory:      dashboardHistoryDAO,
+		dashboardTemplate:     dashboardTemplateDAO,
+		datasource:            datasourceDAO,
+		ephemeralDashboard:    ephemeralDashboardDAO,
+		folder:                folderDAO,
+		globalDatasource:      globalDatatasourceDAO,
+		globalRole:            globalRoleDAO,
+		globalRoleBinding:     globalRoleBindingDAO,
+		globalSecret:          globalSecretDAO,
+		globalVariable:        globalVariableDAO,
+		health:                healthDAO,
+		perses:                persesDAO,
+		pluginState:           pluginStateDAO,
+		project:               projectDAO,
+		queryAudit:            queryAuditDAO,
+		role:                  roleDAO,
+		roleBinding:           roleBindingDAO,
+		secret:                secretDAO,
+		templatePolicy:        templatePolicyDAO,
+		templatePolicyBinding: templatePolicyBindingDAO,
+		user:                  userDAO,
+		userFavorite:          userFavoriteDAO,
+		variable:              variableDAO,
 	}, nil
 }
 
+func (p *persistence) GetAPIKey() apikey.DAO {
+	return p.apiKey
+}
+
 func (p *persistence) GetDashboard() dashboard.DAO {
 	return p.dashboard
 }
 
+func (p *persistence) GetDashboardHistory() dashboardhistory.DAO {
+	return p.dashboardHistory
+}
+
+func (p *persistence) GetDashboardTemplate() dashboardtemplate.DAO {
+	return p.dashboardTemplate
+}
+
 func (p *persistence) GetDatasource() datasource.DAO {
 	return p.datasource
 }
@@ -178,10 +238,18 @@ func (p *persistence) GetPersesDAO() databaseModel.DAO {
 	return p.perses
 }
 
+func (p *persistence) GetPluginState() pluginstate.DAO {
+	return p.pluginState
+}
+
 func (p *persistence) GetProject() project.DAO {
 	return p.project
 }
 
+func (p *persistence) GetQueryAudit() queryaudit.DAO {
+	return p.queryAudit
+}
+
 func (p *persistence) GetRole() role.DAO {
 	return p.role
 }
@@ -194,10 +262,22 @@ func (p *persistence) GetSecret() secret.DAO {
 	return p.secret
 }
 
+func (p *persistence) GetTemplatePolicy() templatepolicy.DAO {
+	return p.templatePolicy
+}
+
+func (p *persistence) GetTemplatePolicyBinding() templatepolicybinding.DAO {
+	return p.templatePolicyBinding
+}
+
 func (p *persistence) GetUser() user.DAO {
 	return p.user
 }
 
+func (p *persistence) GetUserFavorite() userfavorite.DAO {
+	return p.userFavorite
+}
+
 func (p *persistence) GetVariable() variable.DAO {
 	return p.variable
 }
@@ -16,9 +16,14 @@
 package dependency
 
 import (
+	"time"
+
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/crypto"
+	apiKeyImpl "github.com/perses/perses/internal/api/impl/v1/apikey"
 	dashboardImpl "github.com/perses/perses/internal/api/impl/v1/dashboard"
+	dashboardHistoryImpl "github.com/perses/perses/internal/api/impl/v1/dashboardhistory"
+	dashboardTemplateImpl "github.com/perses/perses/internal/api/impl/v1/dashboardtemplate"
 	datasourceImpl "github.com/perses/perses/internal/api/impl/v1/datasource"
 	ephemeralDashboardImpl "github.com/perses/perses/internal/api/impl/v1/ephemeraldashboard"
 	folderImpl "github.com/perses/perses/internal/api/impl/v1/folder"
@@ -29,13 +34,20 @@ import (
 	globalVariableImpl "github.com/perses/perses/internal/api/impl/v1/globalvariable"
 	healthImpl "github.com/perses/perses/internal/api/impl/v1/health"
 	projectImpl "github.com/perses/perses/internal/api/impl/v1/project"
+	queryAuditImpl "github.com/perses/perses/internal/api/impl/v1/queryaudit"
 	roleImpl "github.com/perses/perses/internal/api/impl/v1/role"
 	roleBindingImpl "github.com/perses/perses/internal/api/impl/v1/rolebinding"
 	secretImpl "github.com/perses/perses/internal/api/impl/v1/secret"
+	templatePolicyImpl "github.com/perses/perses/internal/api/impl/v1/templatepolicy"
+	templatePolicyBindingImpl "github.com/perses/perses/internal/api/impl/v1/templatepolicybinding"
 	userImpl "github.com/perses/perses/internal/api/impl/v1/user"
+	userFavoriteImpl "github.com/perses/perses/internal/api/impl/v1/userfavorite"
 	variableImpl "github.com/perses/perses/internal/api/impl/v1/variable"
 	viewImpl "github.com/perses/perses/internal/api/impl/v1/view"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/ephemeraldashboard"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
@@ -46,10 +58,14 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
 	"github.com/perses/perses/internal/api/interface/v1/health"
 	"github.com/perses/perses/internal/api/interface/v1/project"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	"github.com/perses/perses/internal/api/interface/v1/view"
 	"github.com/perses/perses/internal/api/plugin"
@@ -59,9 +75,12 @@ import (
 )
 
 type ServiceManager interface {
+	GetAPIKey() apikey.Service
 	GetAuthorization() authorization.Authorization
 	GetCrypto() crypto.Crypto
 	GetDashboard() dashboard.Service
+	GetDashboardHistory() dashboardhistory.Service
+	GetDashboardTemplate() dashboardtemplate.Service
 	GetDatasource() datasource.Service
 	GetEphemeralDashboard() ephemeraldashboard.Service
 	GetFolder() folder.Service
@@ -75,40 +94,51 @@ type ServiceManager interface {
 	GetMigration() migrate.Migration
 	GetPlugin() plugin.Plugin
 	GetProject() project.Service
+	GetQueryAudit() queryaudit.Service
 	GetSchema() schema.Schema
 	GetRole() role.Service
 	GetRoleBinding() rolebinding.Service
 	GetSecret() secret.Service
+	GetTemplatePolicy() templatepolicy.Service
+	GetTemplatePolicyBinding() templatepolicybinding.Service
 	GetUser() user.Service
+	GetUserFavorite() userfavorite.Service
 	GetVariable() variable.Service
 	GetView() view.Service
 }
 
 type service struct {
 	ServiceManager
-	authorization      authorization.Authorization
-	crypto             crypto.Crypto
-	dashboard          dashboard.Service
-	datasource         datasource.Service
-	ephemeralDashboard ephemeraldashboard.Service
-	folder             folder.Service
-	globalDatasource   globaldatasource.Service
-	globalRole         globalrole.Service
-	globalRoleBinding  globalrolebinding.Service
-	globalSecret       globalsecret.Service
-	globalVariable     globalvariable.Service
-	health             health.Service
-	jwt                crypto.JWT
-	migrate            migrate.Migration
-	plugin             plugin.Plugin
-	project            project.Service
-	schema             schema.Schema
-	role               role.Service
-	roleBinding        rolebinding.Service
-	secret             secret.Service
-	user               user.Service
-	variable           variable.Service
-	view               view.Service
+	apiKey                apikey.Service
+	authorization         authorization.Authorization
+	crypto                crypto.Crypto
+	dashboard             dashboard.Service
+	dashboardHistory      dashboardhistory.Service
+	dashboardTemplate     dashboardtemplate.Service
+	datasource            datasource.Service
+	ephemeralDashboard    ephemeraldashboard.Service
+	folder                folder.Service
+	globalDatasource      globaldatasource.Service
+	globalRole            globalrole.Service
+	globalRoleBinding     globalrolebinding.Service
+	globalSecret          globalsecret.Service
+	globalVariable        globalvariable.Service
+	health                health.Service
+	jwt                   crypto.JWT
+	migrate               migrate.Migration
+	plugin                plugin.Plugin
+	project               project.Service
+	queryAudit            queryaudit.Service
+	schema                schema.Schema
+	role                  role.Service
+	roleBinding           rolebinding.Service
+	secret                secret.Service
+	templatePolicy        templatepolicy.Service
+	templatePolicyBinding templatepolicybinding.Service
+	user                  user.Service
+	userFavorite          userfavorite.Service
+	variable              variable.Service
+	view                  view.Service
 }
 
 func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManager, error) {
@@ -116,16 +146,19 @@ func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManag
 	if err != nil {
 		return nil, err
 	}
-	authzService, err := authorization.New(dao.GetUser(), dao.GetRole(), dao.GetRoleBinding(), dao.GetGlobalRole(), dao.GetGlobalRoleBinding(), conf)
+	authzService, err := authorization.New(dao.GetUser(), dao.GetAPIKey(), dao.GetRole(), dao.GetRoleBinding(), dao.GetGlobalRole(), dao.GetGlobalRoleBinding(), conf)
 	if err != nil {
 		return nil, err
 	}
-	pluginService := plugin.New(conf.Plugin)
+	pluginService := plugin.NewWithKVStore(conf.Plugin, dao.GetPluginState())
 	schemaService := pluginService.Schema()
 	migrateService := pluginService.Migration()
-	dashboardService := dashboardImpl.NewService(conf, dao.GetDashboard(), dao.GetGlobalVariable(), dao.GetVariable(), schemaService)
+	apiKeyService := apiKeyImpl.NewService(dao.GetAPIKey(), authzService)
+	dashboardHistoryService := dashboardHistoryImpl.NewService(dao.GetDashboardHistory(), authzService, conf.Dashboard.MaxHistoryVersions)
+	dashboardService := dashboardImpl.NewService(conf, dao.GetDashboard(), dashboardHistoryService, dao.GetUserFavorite(), dao.GetGlobalVariable(), dao.GetVariable(), dao.GetTemplatePolicy(), dao.GetTemplatePolicyBinding(), schemaService)
+	dashboardTemplateService := dashboardTemplateImpl.NewService(dao.GetDashboardTemplate(), dashboardService, dao.GetGlobalVariable(), dao.GetVariable(), schemaService)
 	datasourceService := datasourceImpl.NewService(dao.GetDatasource(), schemaService)
-	ephemeralDashboardService := ephemeralDashboardImpl.NewService(dao.GetEphemeralDashboard(), dao.GetGlobalVariable(), dao.GetVariable(), schemaService)
+	ephemeralDashboardService := ephemeralDashboardImpl.NewService(dao.GetEphemeralDashboard(), dao.GetGlobalVariable(), dao.GetVariable(), schemaService, time.Duration(conf.API.MinRefreshInterval))
 	folderService := folderImpl.NewService(dao.GetFolder())
 	variableService := variableImpl.NewService(dao.GetVariable(), schemaService)
 	globalDatasourceService := globalDatasourceImpl.NewService(dao.GetGlobalDatasource(), schemaService)
@@ -134,41 +167,56 @@ func newServiceManager(dao PersistenceManager, conf config.Config) (ServiceManag
 	globalSecret := globalSecretImpl.NewService(dao.GetGlobalSecret(), cryptoService)
 	globalVariableService := globalVariableImpl.NewService(dao.GetGlobalVariable(), schemaService)
 	healthService := healthImpl.NewService(dao.GetHealth())
-	projectService := projectImpl.NewService(dao.GetProject(), dao.GetFolder(), dao.GetDatasource(), dao.GetDashboard(), dao.GetRole(), dao.GetRoleBinding(), dao.GetSecret(), dao.GetVariable(), authzService)
+	projectService := projectImpl.NewService(dao.GetProject(), dao.GetFolder(), dao.GetDatasource(), dao.GetDashboard(), dao.GetDashboardHistory(), dao.GetDashboardTemplate(), dao.GetUserFavorite(), dao.GetRole(), dao.GetRoleBinding(), dao.GetSecret(), dao.GetVariable(), dao.GetAPIKey(), authzService)
+	queryAuditService := queryAuditImpl.NewService(dao.GetQueryAudit())
 	roleService := roleImpl.NewService(dao.GetRole(), authzService, schemaService)
 	roleBindingService := roleBindingImpl.NewService(dao.GetRoleBinding(), dao.GetRole(), dao.GetUser(), authzService, schemaService)
 	secretService := secretImpl.NewService(dao.GetSecret(), cryptoService)
-	userService := userImpl.NewService(dao.GetUser(), authzService)
+	templatePolicyService := templatePolicyImpl.NewService(dao.GetTemplatePolicy())
+	templatePolicyBindingService := templatePolicyBindingImpl.NewService(dao.GetTemplatePolicyBinding(), dao.GetTemplatePolicy())
+	userService := userImpl.NewService(dao.GetUser(), dao.GetUserFavorite(), authzService)
+	userFavoriteService := userFavoriteImpl.NewService(dao.GetUserFavorite(), dao.GetUser(), dao.GetDashboard())
 	viewService := viewImpl.NewMetricsViewService()
 
 	svc := &service{
-		authorization:      authzService,
-		crypto:             cryptoService,
-		dashboard:          dashboardService,
-		datasource:         datasourceService,
-		ephemeralDashboard: ephemeralDashboardService,
-		folder:             folderService,
-		globalDatasource:   globalDatasourceService,
-		globalRole:         globalRole,
-		globalRoleBinding:  globalRoleBinding,
-		globalSecret:       globalSecret,
-		globalVariable:     globalVariableService,
-		health:             healthService,
-		jwt:                jwtService,
-		migrate:            migrateService,
-		plugin:             pluginService,
-		project:            projectService,
-		role:               roleService,
-		roleBinding:        roleBindingService,
-		schema:             schemaService,
-		secret:             secretService,
-		user:               userService,
-		variable:           variableService,
-		view:               viewService,
+		apiKey:                apiKeyService,
+		authorization:         authzService,
+		crypto:                cryptoService,
+		dashboard:             dashboardService,
+		dashboardHistory:      dashboardHistoryService,
+		dashboardTemplate:     dashboardTemplateService,
+		datasource:            datasourceService,
+		ephemeralDashboard:    ephemeralDashboardService,
+		folder:                folderService,
+		globalDatasource:      globalDatasourceService,
+		globalRole:            globalRole,
+		globalRoleBinding:     globalRoleBinding,
+		globalSecret:          globalSecret,
+		globalVariable:        globalVariableService,
+		health:                healthService,
+		jwt:                   jwtService,
+		migrate:               migrateService,
+		plugin:                pluginService,
+		project:               projectService,
+		queryAudit:            queryAuditService,
+		role:                  roleService,
+		roleBinding:           roleBindingService,
+		schema:                schemaService,
+		secret:                secretService,
+		templatePolicy:        templatePolicyService,
+		templatePolicyBinding: templatePolicyBindingService,
+		user:                  userService,
+		userFavorite:          userFavoriteService,
+		variable:              variableService,
+		view:                  viewService,
 	}
 	return svc, nil
 }
 
+func (s *service) GetAPIKey() apikey.Service {
+	return s.apiKey
+}
+
 func (s *service) GetAuthorization() authorization.Authorization {
 	return s.authorization
 }
@@ -181,6 +229,14 @@ func (s *service) GetDashboard() dashboard.Service {
 	return s.dashboard
 }
 
+func (s *service) GetDashboardHistory() dashboardhistory.Service {
+	return s.dashboardHistory
+}
+
+func (s *service) GetDashboardTemplate() dashboardtemplate.Service {
+	return s.dashboardTemplate
+}
+
 func (s *service) GetDatasource() datasource.Service {
 	return s.datasource
 }
@@ -233,6 +289,10 @@ func (s *service) GetProject() project.Service {
 	return s.project
 }
 
+func (s *service) GetQueryAudit() queryaudit.Service {
+	return s.queryAudit
+}
+
 func (s *service) GetSchema() schema.Schema {
 	return s.schema
 }
@@ -249,10 +309,22 @@ func (s *service) GetSecret() secret.Service {
 	return s.secret
 }
 
+func (s *service) GetTemplatePolicy() templatepolicy.Service {
+	return s.templatePolicy
+}
+
+func (s *service) GetTemplatePolicyBinding() templatepolicybinding.Service {
+	return s.templatePolicyBinding
+}
+
 func (s *service) GetUser() user.Service {
 	return s.user
 }
 
+func (s *service) GetUserFavorite() userfavorite.Service {
+	return s.userFavorite
+}
+
 func (s *service) GetVariable() variable.Service {
 	return s.variable
 }
@@ -14,6 +14,7 @@
 package api
 
 // this file is just there to run the command generate
+//go:generate go run generate.go -package=apikey -plural=apikeys -kind=APIKey -isProjectResource=true
 //go:generate go run generate.go -package=dashboard -plural=dashboards -kind=Dashboard -isProjectResource=true
 //go:generate go run generate.go -package=datasource -plural=datasources -kind=Datasource -isProjectResource=true
 //go:generate go run generate.go -package=ephemeraldashboard -plural=ephemeraldashboards -kind=EphemeralDashboard -isProjectResource=true
@@ -27,5 +28,7 @@ package api
 //go:generate go run generate.go -package=role -plural=roles -kind=Role -isProjectResource=true
 //go:generate go run generate.go -package=rolebinding -plural=rolebindings -kind=RoleBinding -isProjectResource=true
 //go:generate go run generate.go -package=secret -plural=secrets -kind=Secret -isProjectResource=true
+//go:generate go run generate.go -package=templatepolicy -plural=templatepolicies -kind=TemplatePolicy
+//go:generate go run generate.go -package=templatepolicybinding -plural=templatepolicybindings -kind=TemplatePolicyBinding
 //go:generate go run generate.go -package=user -plural=users -kind=User
 //go:generate go run generate.go -package=variable -plural=variables -kind=Variable -isProjectResource=true
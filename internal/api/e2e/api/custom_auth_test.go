@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/perses/perses/internal/api/dependency"
+	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
+	"github.com/perses/perses/internal/api/utils"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	pluginAPI "github.com/perses/perses/pkg/plugin/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockCustomProviderSlugID = "mock"
+
+type mockCustomProvider struct {
+	disabled bool
+}
+
+func (m *mockCustomProvider) Authenticate(username, password string) (*pluginAPI.UserInfo, error) {
+	if username != "bob" || password != "password" {
+		return nil, pluginAPI.ErrInvalidCredentials
+	}
+	return &pluginAPI.UserInfo{Login: username, FirstName: "Bob", Email: "bob@example.com"}, nil
+}
+
+func (m *mockCustomProvider) RefreshToken(_ string) (*pluginAPI.TokenInfo, error) {
+	if m.disabled {
+		return nil, errors.New("user disabled")
+	}
+	return &pluginAPI.TokenInfo{}, nil
+}
+
+func withMockCustomProvider(t *testing.T, provider *mockCustomProvider, testFunc func(*httptest.Server, *httpexpect.Expect, dependency.PersistenceManager) []modelAPI.Entity) {
+	if err := pluginAPI.RegisterAuthProvider(mockCustomProviderSlugID, provider); err != nil {
+		t.Fatal(err)
+	}
+	defer pluginAPI.UnregisterAuthProvider(mockCustomProviderSlugID)
+	conf := e2eframework.DefaultAuthConfig()
+	conf.Security.Authentication.Providers.Custom = []apiConfig.CustomProvider{{SlugID: mockCustomProviderSlugID, Name: "Mock"}}
+	e2eframework.WithServerConfig(t, conf, testFunc)
+}
+
+func customLoginPath() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIPrefix, utils.PathAuthProviders, utils.AuthnKindCustom, mockCustomProviderSlugID, utils.PathLogin)
+}
+
+func TestAuth_CustomProvider(t *testing.T) {
+	withMockCustomProvider(t, &mockCustomProvider{}, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []modelAPI.Entity {
+		jsonToken := expect.POST(customLoginPath()).
+			WithJSON(modelAPI.Auth{Login: "bob", Password: "password"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON()
+		jsonToken.Path("$.access_token").NotNull().NotEqual("")
+		jsonToken.Path("$.refresh_token").NotNull().NotEqual("")
+
+		// The user must have been created from the information given by the provider
+		usr, err := manager.GetUser().Get("bob")
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", usr.Spec.FirstName)
+
+		expect.POST(fmt.Sprintf("%s/%s/%s", utils.APIPrefix, utils.PathAuth, utils.PathRefresh)).
+			WithJSON(modelAPI.RefreshRequest{RefreshToken: jsonToken.Path("$.refresh_token").String().Raw()}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Path("$.access_token").NotNull().NotEqual("")
+		return []modelAPI.Entity{&modelV1.User{Kind: modelV1.KindUser, Metadata: modelV1.Metadata{Name: "bob"}}}
+	})
+}
+
+func TestAuth_CustomProviderWrongPassword(t *testing.T) {
+	withMockCustomProvider(t, &mockCustomProvider{}, func(_ *httptest.Server, expect *httpexpect.Expect, _ dependency.PersistenceManager) []modelAPI.Entity {
+		expect.POST(customLoginPath()).
+			WithJSON(modelAPI.Auth{Login: "bob", Password: "wrong"}).
+			Expect().
+			Status(http.StatusBadRequest)
+		return []modelAPI.Entity{}
+	})
+}
+
+func TestAuth_CustomProviderRefreshRejected(t *testing.T) {
+	provider := &mockCustomProvider{}
+	withMockCustomProvider(t, provider, func(_ *httptest.Server, expect *httpexpect.Expect, _ dependency.PersistenceManager) []modelAPI.Entity {
+		refreshToken := expect.POST(customLoginPath()).
+			WithJSON(modelAPI.Auth{Login: "bob", Password: "password"}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Path("$.refresh_token").String().Raw()
+
+		provider.disabled = true
+		expect.POST(fmt.Sprintf("%s/%s/%s", utils.APIPrefix, utils.PathAuth, utils.PathRefresh)).
+			WithJSON(modelAPI.RefreshRequest{RefreshToken: refreshToken}).
+			Expect().
+			Status(http.StatusUnauthorized)
+		return []modelAPI.Entity{&modelV1.User{Kind: modelV1.KindUser, Metadata: modelV1.Metadata{Name: "bob"}}}
+	})
+}
@@ -65,6 +65,7 @@ func TestUpdateDashboardIncreaseVersion(t *testing.T) {
 			JSON().
 			Raw())
 
+		entity.Metadata.ResourceVersion = dashboard.Metadata.ResourceVersion
 		updatedDashboard := extractDashboardFromHTTPBody(expect.PUT(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, dashboard.Metadata.Project, utils.PathDashboard, dashboard.Metadata.Name)).
 			WithJSON(entity).
 			Expect().
@@ -73,6 +74,7 @@ func TestUpdateDashboardIncreaseVersion(t *testing.T) {
 			Raw())
 		assert.True(t, dashboard.Metadata.Version+1 == updatedDashboard.Metadata.Version)
 
+		entity.Metadata.ResourceVersion = updatedDashboard.Metadata.ResourceVersion
 		updatedDashboard = extractDashboardFromHTTPBody(expect.PUT(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, dashboard.Metadata.Project, utils.PathDashboard, dashboard.Metadata.Name)).
 			WithJSON(entity).
 			Expect().
@@ -85,6 +87,70 @@ func TestUpdateDashboardIncreaseVersion(t *testing.T) {
 	})
 }
 
+func TestUpdateDashboardWithoutResourceVersion(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		entity := e2eframework.NewDashboard(t, "perses", "test")
+		project := e2eframework.NewProject("perses")
+		e2eframework.CreateAndWaitUntilEntityExists(t, manager, project)
+
+		expect.POST(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, entity.Metadata.Project, utils.PathDashboard)).
+			WithJSON(entity).
+			Expect().
+			Status(http.StatusOK)
+
+		expect.PUT(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, entity.Metadata.Project, utils.PathDashboard, entity.Metadata.Name)).
+			WithJSON(entity).
+			Expect().
+			Status(http.StatusPreconditionRequired)
+		return []api.Entity{project, entity}
+	})
+}
+
+func TestUpdateDashboardDryRun(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		entity := e2eframework.NewDashboard(t, "perses", "test")
+		project := e2eframework.NewProject("perses")
+		e2eframework.CreateAndWaitUntilEntitiesExist(t, manager, project, entity)
+		path := fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, entity.Metadata.Project, utils.PathDashboard, entity.Metadata.Name)
+
+		// unchanged dashboard
+		expect.PUT(path).
+			WithQuery("dry-run", true).
+			WithJSON(entity).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// changed dashboard
+		changedEntity := e2eframework.NewDashboard(t, "perses", "test")
+		changedEntity.Spec.Duration = "1h"
+		expect.PUT(path).
+			WithQuery("dry-run", true).
+			WithJSON(changedEntity).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			IsEqual([]map[string]any{{"op": "replace", "path": "/spec/duration", "value": "1h"}})
+
+		// invalid dashboard
+		invalidEntity := e2eframework.NewDashboard(t, "perses", "other")
+		expect.PUT(path).
+			WithQuery("dry-run", true).
+			WithJSON(invalidEntity).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		// nothing has been persisted
+		dashboard := extractDashboardFromHTTPBody(expect.GET(path).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Raw())
+		assert.Equal(t, entity.Spec.Duration, dashboard.Spec.Duration)
+		assert.Equal(t, uint64(0), dashboard.Metadata.Version)
+		return []api.Entity{project, entity}
+	})
+}
+
 func TestListDashboardInEmptyProject(t *testing.T) {
 	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
 		demoDashboard := e2eframework.NewDashboard(t, "perses", "Demo")
@@ -116,7 +182,7 @@ func TestListDashboardWithOnlyMetadata(t *testing.T) {
 			Status(http.StatusOK)
 
 		response.JSON().Array().Length().IsEqual(1)
-		response.JSON().Array().Value(0).Object().IsEqual(modelV1.PartialProjectEntity{
+		response.JSON().Array().Value(0).Object().IsEqual(modelV1.PartialDashboard{
 			Kind:     demoDashboard.Kind,
 			Metadata: demoDashboard.Metadata,
 			Spec:     struct{}{},
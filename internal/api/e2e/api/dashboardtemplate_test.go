@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/dependency"
+	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+)
+
+func TestMainScenarioDashboardTemplate(t *testing.T) {
+	e2eframework.MainTestScenarioWithProject(t, utils.PathDashboardTemplate, func(projectName string, name string) (api.Entity, api.Entity) {
+		return e2eframework.NewProject(projectName), e2eframework.NewDashboardTemplate(t, projectName, name)
+	})
+}
+
+func TestInstantiateDashboardTemplate(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		project := e2eframework.NewProject("perses")
+		template := e2eframework.NewDashboardTemplate(t, project.Metadata.Name, "node")
+		e2eframework.CreateAndWaitUntilEntitiesExist(t, manager, project, template)
+		path := fmt.Sprintf("%s/%s/%s/%s/%s/instantiate", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathDashboardTemplate, template.Metadata.Name)
+
+		// The parameter "job" has no default value.
+		expect.POST(path).
+			WithJSON(map[string]any{"parameters": map[string]any{}}).
+			Expect().
+			Status(http.StatusBadRequest)
+
+		result := expect.POST(path).
+			WithJSON(map[string]any{"name": "node-exporter", "parameters": map[string]any{"job": "node"}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		result.Path("$.kind").IsEqual("Dashboard")
+		result.Path("$.metadata.name").IsEqual("node-exporter")
+
+		// Without ?save=true, the dashboard is not created.
+		expect.GET(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathDashboard, "node-exporter")).
+			Expect().
+			Status(http.StatusNotFound)
+
+		expect.POST(path).
+			WithQuery("save", "true").
+			WithJSON(map[string]any{"name": "node-exporter", "parameters": map[string]any{"job": "node"}}).
+			Expect().
+			Status(http.StatusCreated).
+			Header(echo.HeaderLocation).
+			IsEqual(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathDashboard, "node-exporter"))
+
+		dashboard, err := manager.GetDashboard().Get(project.Metadata.Name, "node-exporter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []api.Entity{project, template, dashboard}
+	})
+}
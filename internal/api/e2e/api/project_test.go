@@ -54,3 +54,34 @@ func TestDeleteProjectWithSubResources(t *testing.T) {
 		return []api.Entity{}
 	})
 }
+
+func TestProjectStats(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.PersistenceManager) []api.Entity {
+		projectName := "perses"
+		project := e2eframework.NewProject(projectName)
+		firstDash := e2eframework.NewDashboard(t, projectName, "Demo")
+		secondDash := e2eframework.NewDashboard(t, projectName, "Benchmark")
+		datasource := e2eframework.NewDatasource(t, projectName, "Demo")
+		variable := e2eframework.NewVariable(projectName, "Demo")
+		e2eframework.CreateAndWaitUntilEntitiesExist(t, manager, project, firstDash, secondDash, datasource, variable)
+
+		stats := expect.GET(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, projectName, utils.PathStats)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		stats.Value("dashboardCount").Number().IsEqual(2)
+		stats.Value("datasourceCount").Number().IsEqual(1)
+		stats.Value("variableCount").Number().IsEqual(1)
+		stats.Keys().ContainsAll("createdAt", "lastModifiedAt")
+		return []api.Entity{project, firstDash, secondDash, datasource, variable}
+	})
+}
+
+func TestProjectStatsNotFound(t *testing.T) {
+	e2eframework.WithServer(t, func(_ *httptest.Server, expect *httpexpect.Expect, _ dependency.PersistenceManager) []api.Entity {
+		expect.GET(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, "unknown", utils.PathStats)).
+			Expect().
+			Status(http.StatusNotFound)
+		return []api.Entity{}
+	})
+}
@@ -107,12 +107,14 @@ func newSQLDatasourceSpec(t *testing.T) datasourceSpec.Spec {
 func newDashboard(projectName string, dashboardName string, dtsName string, dts *v1.Datasource) *v1.Dashboard {
 	entity := &v1.Dashboard{
 		Kind: v1.KindDashboard,
-		Metadata: v1.ProjectMetadata{
-			Metadata: v1.Metadata{
-				Name: dashboardName,
-			},
-			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
-				Project: projectName,
+		Metadata: v1.DashboardMetadata{
+			ProjectMetadata: v1.ProjectMetadata{
+				Metadata: v1.Metadata{
+					Name: dashboardName,
+				},
+				ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+					Project: projectName,
+				},
 			},
 		},
 		Spec: dashboard.Spec{
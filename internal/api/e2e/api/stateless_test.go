@@ -0,0 +1,76 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	e2eframework "github.com/perses/perses/internal/api/e2e/framework"
+	"github.com/perses/perses/internal/api/utils"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatelessDatabase(t *testing.T) {
+	folder := t.TempDir()
+	dashboard := e2eframework.NewDashboard(t, "perses", "Demo")
+	data, err := json.Marshal(dashboard)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "dashboard.json"), data, 0o600))
+
+	conf := e2eframework.DefaultConfig()
+	conf.EphemeralDashboard.Enable = false
+	conf.Database = apiConfig.Database{
+		Stateless: &apiConfig.Stateless{Folder: folder, CaseSensitive: true},
+	}
+	require.NoError(t, conf.Verify())
+
+	server, expect, dependencyManager := e2eframework.CreateServer(t, conf)
+	defer dependencyManager.Persistence().GetPersesDAO().Close()
+	defer server.Close()
+
+	projectPath := fmt.Sprintf("%s/%s/perses", utils.APIV1Prefix, utils.PathProject)
+	dashboardsPath := fmt.Sprintf("%s/%s", projectPath, utils.PathDashboard)
+
+	expect.GET(projectPath).
+		Expect().
+		Status(http.StatusOK)
+	expect.GET(fmt.Sprintf("%s/Demo", dashboardsPath)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("metadata").Object().Value("name").IsEqual("Demo")
+	expect.GET(dashboardsPath).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(1)
+
+	expect.POST(dashboardsPath).
+		WithJSON(dashboard).
+		Expect().
+		Status(http.StatusMethodNotAllowed)
+	expect.PUT(fmt.Sprintf("%s/Demo", dashboardsPath)).
+		WithJSON(dashboard).
+		Expect().
+		Status(http.StatusMethodNotAllowed)
+	expect.DELETE(fmt.Sprintf("%s/Demo", dashboardsPath)).
+		Expect().
+		Status(http.StatusMethodNotAllowed)
+}
@@ -78,3 +78,142 @@ func TestMainScenarioUser(t *testing.T) {
 	e2eframework.DeleteTestScenario(t, path, creator)
 	e2eframework.NotFoundTestScenario(t, path, creator)
 }
+
+func TestUserFavorites(t *testing.T) {
+	e2eframework.WithServerAuthConfig(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.Manager, token string) []modelAPI.Entity {
+		project := e2eframework.NewProject("perses")
+		dashboard := e2eframework.NewDashboard(t, project.Metadata.Name, "myDashboard")
+		e2eframework.CreateAndWaitUntilEntitiesExist(t, manager.Persistence(), project, dashboard)
+		favoritePath := fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathUser, "alice", utils.PathFavorite)
+		dashboardFavoritePath := fmt.Sprintf("%s/%s/%s", favoritePath, project.Metadata.Name, dashboard.Metadata.Name)
+
+		// add
+		expect.PUT(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// add again
+		expect.PUT(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// unknown dashboard
+		expect.PUT(fmt.Sprintf("%s/%s/%s", favoritePath, project.Metadata.Name, "unknown")).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNotFound)
+
+		// list
+		list := expect.GET(favoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array()
+		list.Length().IsEqual(1)
+		list.Value(0).Object().HasValue("kind", string(modelV1.KindDashboard))
+		list.Value(0).Object().Path("$.metadata.project").IsEqual(project.Metadata.Name)
+		list.Value(0).Object().Path("$.metadata.name").IsEqual(dashboard.Metadata.Name)
+
+		// the dashboards of the list are flagged when they are a favorite of the user
+		expect.GET(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathDashboard)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().Value(0).Object().HasValue("favorited", true)
+
+		// remove
+		expect.DELETE(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// remove again
+		expect.DELETE(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNotFound)
+
+		// a dashboard in the trash is hidden from the favorites until it is restored
+		expect.PUT(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+		expect.DELETE(fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathDashboard, dashboard.Metadata.Name)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+		expect.GET(favoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().IsEmpty()
+		expect.PUT(dashboardFavoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNotFound)
+		expect.POST(fmt.Sprintf("%s/%s/%s/%s/%s/restore", utils.APIV1Prefix, utils.PathProject, project.Metadata.Name, utils.PathTrash, dashboard.Metadata.Name)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK)
+		expect.GET(favoritePath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().Length().IsEqual(1)
+		return []modelAPI.Entity{dashboard, project}
+	})
+}
+
+func TestUserFavorites_CurrentUser(t *testing.T) {
+	e2eframework.WithServerAuthConfig(t, func(_ *httptest.Server, expect *httpexpect.Expect, manager dependency.Manager, token string) []modelAPI.Entity {
+		project := e2eframework.NewProject("perses")
+		dashboard := e2eframework.NewDashboard(t, project.Metadata.Name, "myDashboard")
+		e2eframework.CreateAndWaitUntilEntitiesExist(t, manager.Persistence(), project, dashboard)
+
+		// add through the "me" alias
+		expect.POST(fmt.Sprintf("%s/%s/me/%s", utils.APIV1Prefix, utils.PathUser, utils.PathFavorite)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			WithJSON(map[string]string{"project": project.Metadata.Name, "dashboard": dashboard.Metadata.Name}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// the favorite is the one of the authenticated user
+		expect.GET(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathUser, "alice", utils.PathFavorite)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().Length().IsEqual(1)
+
+		// list and remove through the paths without a user
+		currentUserPath := fmt.Sprintf("%s/%s/%s", utils.APIV1Prefix, utils.PathCurrentUser, utils.PathFavorite)
+		expect.GET(currentUserPath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Array().Length().IsEqual(1)
+		expect.DELETE(fmt.Sprintf("%s/%s/%s", currentUserPath, project.Metadata.Name, dashboard.Metadata.Name)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusNoContent)
+
+		// missing dashboard in the body
+		expect.POST(currentUserPath).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			WithJSON(map[string]string{"project": project.Metadata.Name}).
+			Expect().
+			Status(http.StatusBadRequest)
+		return []modelAPI.Entity{dashboard, project}
+	})
+}
+
+func TestUserFavorites_OtherUser(t *testing.T) {
+	e2eframework.WithServerAuthConfig(t, func(_ *httptest.Server, expect *httpexpect.Expect, _ dependency.Manager, token string) []modelAPI.Entity {
+		expect.GET(fmt.Sprintf("%s/%s/%s/%s", utils.APIV1Prefix, utils.PathUser, "bob", utils.PathFavorite)).
+			WithHeader(e2eframework.CreateAuthorizationHeader(token)).
+			Expect().
+			Status(http.StatusForbidden)
+		return []modelAPI.Entity{}
+	})
+}
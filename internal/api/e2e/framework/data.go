@@ -50,6 +50,13 @@ func CreateGetFunc(t *testing.T, persistenceManager dependency.PersistenceManage
 		upsertFunc = func() error {
 			return persistenceManager.GetDashboard().Update(entity)
 		}
+	case *v1.DashboardTemplate:
+		getFunc = func() (api.Entity, error) {
+			return persistenceManager.GetDashboardTemplate().Get(entity.Metadata.Project, entity.Metadata.Name)
+		}
+		upsertFunc = func() error {
+			return persistenceManager.GetDashboardTemplate().Update(entity)
+		}
 	case *v1.Datasource:
 		getFunc = func() (api.Entity, error) {
 			return persistenceManager.GetDatasource().Get(entity.Metadata.Project, entity.Metadata.Name)
@@ -328,6 +335,30 @@ func NewDashboard(t *testing.T, projectName string, name string) *v1.Dashboard {
 	return dashboard
 }
 
+func NewDashboardTemplate(t *testing.T, projectName string, name string) *v1.DashboardTemplate {
+	dashboard := NewDashboard(t, projectName, name)
+	return &v1.DashboardTemplate{
+		Kind:     v1.KindDashboardTemplate,
+		Metadata: dashboard.Metadata.ProjectMetadata,
+		Spec: v1.DashboardTemplateSpec{
+			DashboardTemplateSpecBase: v1.DashboardTemplateSpecBase{
+				Parameters: []v1.TemplateParameter{
+					{
+						Name: "job",
+						Type: v1.TemplateParameterTypeString,
+					},
+					{
+						Name:    "text",
+						Type:    v1.TemplateParameterTypeString,
+						Default: "default",
+					},
+				},
+			},
+			Spec: dashboard.Spec,
+		},
+	}
+}
+
 func newRoleSpec() v1.RoleSpec {
 	return v1.RoleSpec{
 		Permissions: []role.Permission{
@@ -38,6 +38,7 @@ import (
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/role"
 	"github.com/perses/perses/pkg/model/api/v1/secret"
 	"github.com/perses/spec/go/common"
@@ -68,7 +69,7 @@ func DefaultConfig() apiConfig.Config {
 			Enable: true,
 		},
 		Plugin: apiConfig.Plugin{
-			Path: filepath.Join(projectPath, "plugins"),
+			Path: modelCommon.NonEmptyString(filepath.Join(projectPath, "plugins")),
 			ArchivePaths: []string{
 				filepath.Join(projectPath, "plugins-archive"),
 			},
@@ -161,7 +162,10 @@ func CreateAuthorizationHeader(token string) (string, string) {
 }
 
 func CreateServer(t *testing.T, conf apiConfig.Config) (*httptest.Server, *httpexpect.Expect, dependency.Manager) {
-	if useSQL == "true" {
+	switch {
+	case conf.Database.Stateless != nil:
+		// The stateless database is tested as is, it doesn't depend on the storage used by the other tests.
+	case useSQL == "true":
 		conf.Database = apiConfig.Database{
 			SQL: &apiConfig.SQL{
 				User:                 "user",
@@ -173,13 +177,13 @@ func CreateServer(t *testing.T, conf apiConfig.Config) (*httptest.Server, *httpe
 				CaseSensitive:        true,
 			},
 		}
-	} else {
+	default:
 		conf.Database = apiConfig.Database{
 			File: defaultFileConfig(),
 		}
 	}
 	registerer := prometheus.NewRegistry()
-	runner, dependencyManager, err := core.New(conf, false, registerer, "")
+	runner, dependencyManager, err := core.New(conf, "", false, registerer, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/crypto"
@@ -30,6 +31,7 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/user"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/internal/httpclient"
 	clientConfig "github.com/perses/perses/pkg/client/config"
 	"github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
@@ -87,10 +89,10 @@ func newHTTPClient(httpConfig config.HTTP) (*http.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &http.Client{
-		Timeout:   time.Duration(httpConfig.Timeout),
-		Transport: roundTripper,
-	}, nil
+	return httpclient.NewHTTPClient(
+		httpclient.WithTransport(roundTripper),
+		httpclient.WithTimeout(time.Duration(httpConfig.Timeout)),
+	), nil
 }
 
 type authEndpoint interface {
@@ -143,6 +145,15 @@ func New(dao user.DAO, jwt crypto.JWT, authz authorization.Authorization, provid
 		}
 		ep.endpoints = append(ep.endpoints, oauthEp)
 	}
+
+	// Register the custom providers if any. They must have been registered through the plugin API beforehand.
+	for _, provider := range providers.Custom {
+		customEp, err := newCustomEndpoint(provider, jwt, dao, authz)
+		if err != nil {
+			return nil, err
+		}
+		ep.endpoints = append(ep.endpoints, customEp)
+	}
 	return ep, nil
 }
 
@@ -179,9 +190,36 @@ func (e *endpoint) refresh(ctx echo.Context) error {
 	}
 	claims, err := e.jwt.ValidateRefreshToken(refreshToken)
 	if err != nil {
+		if errors.Is(err, jwt.ErrTokenInvalidAudience) || errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+			return apiinterface.HandleUnauthorizedError(err.Error())
+		}
 		return apiinterface.HandleBadRequestError(err.Error())
 	}
-	accessToken, err := e.tokenManagement.accessToken(claims.Subject, claims.ProviderInfo, ctx.SetCookie)
+	login := claims.Subject
+	if claims.ProviderInfo.ProviderKind == utils.AuthnKindCustom {
+		// The custom providers are asked whether the user is still allowed to get a new access token.
+		login, err = e.refreshCustom(refreshToken, claims)
+		if err != nil {
+			return err
+		}
+	}
+	if claims.ProviderInfo.ProviderKind == utils.AuthnKindOIDC {
+		// The OIDC providers can end the session with a back-channel logout,
+		// or be asked to refresh their own token, ending the session when they refuse it.
+		if oidcEp := e.findOIDCEndpoint(claims.ProviderInfo.ProviderID); oidcEp != nil {
+			var openedAt time.Time
+			if claims.NotBefore != nil {
+				openedAt = claims.NotBefore.Time
+			}
+			if err = oidcEp.checkSession(login, openedAt); err != nil {
+				return err
+			}
+			if err = oidcEp.refresh(ctx.Request().Context(), login); err != nil {
+				return err
+			}
+		}
+	}
+	accessToken, err := e.tokenManagement.accessToken(login, claims.ProviderInfo, ctx.SetCookie)
 	if err != nil {
 		return err
 	}
@@ -192,6 +230,24 @@ func (e *endpoint) refresh(ctx echo.Context) error {
 	})
 }
 
+func (e *endpoint) refreshCustom(refreshToken string, claims *crypto.JWTClaims) (string, error) {
+	for _, ep := range e.endpoints {
+		if customEp, ok := ep.(*customEndpoint); ok && customEp.GetSlugID() == claims.ProviderInfo.ProviderID {
+			return customEp.refresh(refreshToken, claims.Subject)
+		}
+	}
+	return "", apiinterface.HandleUnauthorizedError(fmt.Sprintf("custom auth provider %q is not available anymore", claims.ProviderInfo.ProviderID))
+}
+
+func (e *endpoint) findOIDCEndpoint(slugID string) *oIDCEndpoint {
+	for _, ep := range e.endpoints {
+		if oidcEp, ok := ep.(*oIDCEndpoint); ok && oidcEp.GetSlugID() == slugID {
+			return oidcEp
+		}
+	}
+	return nil
+}
+
 func (e *endpoint) logout(ctx echo.Context) error {
 	jwtHeaderPayloadCookie, signatureCookie := e.jwt.DeleteAccessTokenCookie()
 	ctx.SetCookie(e.jwt.DeleteRefreshTokenCookie())
@@ -14,12 +14,18 @@
 package auth
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"golang.org/x/oauth2"
 )
 
 func TestGetRedirectURI_WithAPIPrefix(t *testing.T) {
@@ -67,3 +73,34 @@ func TestDecodeOAuthState(t *testing.T) {
 	state = "short--"
 	assert.Equal(t, "", decodeOAuthState(state))
 }
+
+// Test for withOAuthErrorMdw: ensures the errors of the device access token request are relayed to the client,
+// so it knows whether it must keep polling the token endpoint.
+func TestWithOAuthErrorMdw(t *testing.T) {
+	cases := []struct {
+		title string
+		err   error
+		want  string
+	}{
+		{"authorization pending", oidc.ErrAuthorizationPending(), "authorization_pending"},
+		{"slow down", oidc.ErrSlowDown(), "slow_down"},
+		{"expired token", &oauth2.RetrieveError{ErrorCode: "expired_token"}, "expired_token"},
+		{"access denied", &api.OAuthError{ErrorCode: "access_denied"}, "access_denied"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.title, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/token", nil), rec)
+			err := withOAuthErrorMdw(func(_ echo.Context) error { return tc.err })(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			oauthErr := &api.OAuthError{}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), oauthErr))
+			assert.Equal(t, tc.want, oauthErr.ErrorCode)
+		})
+	}
+
+	// Any other error is left to the default error handler
+	err := withOAuthErrorMdw(func(_ echo.Context) error { return echo.ErrForbidden })(echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/token", nil), httptest.NewRecorder()))
+	assert.Equal(t, echo.ErrForbidden, err)
+}
@@ -0,0 +1,191 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/zitadel/oidc/v3/pkg/client/rp"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+)
+
+// backChannelLogoutEvent is the event that a logout token must contain.
+// See https://openid.net/specs/openid-connect-backchannel-1_0.html#LogoutToken
+const backChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// oidcSessions keeps track of the users logged in with an OIDC provider, so their sessions can be ended when the
+// provider asks for it. Like the provider tokens, it is kept in memory and is lost when Perses restarts.
+type oidcSessions struct {
+	mutex sync.Mutex
+	// subjects maps the subject of the users at the provider to their username.
+	subjects map[string]string
+	// sessionIDs maps the IDs of the sessions opened at the provider to the username of their user.
+	sessionIDs map[string]string
+	// loggedOutAt is the time at which the provider ended the sessions of a user, by username.
+	loggedOutAt map[string]time.Time
+	// logoutTokenIDs are the IDs of the logout tokens already received, with their expiry, so a token can't be replayed.
+	logoutTokenIDs map[string]time.Time
+}
+
+func newOIDCSessions() *oidcSessions {
+	return &oidcSessions{
+		subjects:       make(map[string]string),
+		sessionIDs:     make(map[string]string),
+		loggedOutAt:    make(map[string]time.Time),
+		logoutTokenIDs: make(map[string]time.Time),
+	}
+}
+
+func (s *oidcSessions) save(subject, sessionID, username string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(subject) > 0 {
+		s.subjects[subject] = username
+	}
+	if len(sessionID) > 0 {
+		s.sessionIDs[sessionID] = username
+	}
+}
+
+// logout ends the sessions of the user designated by the session ID or, when it is unknown, by the subject.
+// As the Perses sessions are not stored, all the sessions of the user opened until now are ended.
+// It returns the username of the user, or false when no user matches.
+func (s *oidcSessions) logout(subject, sessionID string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	username, ok := s.sessionIDs[sessionID]
+	if ok {
+		delete(s.sessionIDs, sessionID)
+	} else {
+		username, ok = s.subjects[subject]
+	}
+	if ok {
+		s.loggedOutAt[username] = time.Now()
+	}
+	return username, ok
+}
+
+// isLoggedOut returns true when the provider ended the sessions of the user after the given session was opened.
+func (s *oidcSessions) isLoggedOut(username string, openedAt time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	loggedOutAt, ok := s.loggedOutAt[username]
+	return ok && openedAt.Before(loggedOutAt)
+}
+
+// useLogoutToken records the ID of a logout token. It returns false when the token has already been used.
+func (s *oidcSessions) useLogoutToken(id string, expiry time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	for tokenID, tokenExpiry := range s.logoutTokenIDs {
+		if tokenExpiry.Before(now) {
+			delete(s.logoutTokenIDs, tokenID)
+		}
+	}
+	if _, ok := s.logoutTokenIDs[id]; ok {
+		return false
+	}
+	s.logoutTokenIDs[id] = expiry
+	return true
+}
+
+// backChannelLogout is the http handler receiving the logout tokens that the provider sends to end the sessions
+// of its users, as described by https://openid.net/specs/openid-connect-backchannel-1_0.html.
+func (e *oIDCEndpoint) backChannelLogout(ctx echo.Context) error {
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	claims, err := verifyLogoutToken(ctx.Request().Context(), ctx.FormValue("logout_token"), e.relyingParty.IDTokenVerifier())
+	if err != nil {
+		e.logWithError(err).Warn("Invalid logout token")
+		return &oidc.Error{ErrorType: oidc.InvalidRequest, Description: err.Error()}
+	}
+	if !e.sessions.useLogoutToken(claims.JWTID, claims.Expiration.AsTime()) {
+		err = fmt.Errorf("the logout token %q has already been used", claims.JWTID)
+		e.logWithError(err).Warn("Logout token replayed")
+		return &oidc.Error{ErrorType: oidc.InvalidRequest, Description: err.Error()}
+	}
+	if username, ok := e.sessions.logout(claims.Subject, claims.SessionID); ok {
+		e.providerTokensMutex.Lock()
+		delete(e.providerTokens, username)
+		e.providerTokensMutex.Unlock()
+	}
+	return ctx.NoContent(http.StatusOK)
+}
+
+// checkSession returns an unauthorized error when the provider ended the sessions of the user after the given session was opened.
+func (e *oIDCEndpoint) checkSession(username string, openedAt time.Time) error {
+	if e.sessions != nil && e.sessions.isLoggedOut(username, openedAt) {
+		return apiinterface.HandleUnauthorizedError("the session has been ended by the identity provider")
+	}
+	return nil
+}
+
+// logoutTokenSignature receives the algorithm of the signature once checked, which isn't needed for a logout token.
+type logoutTokenSignature struct{}
+
+func (logoutTokenSignature) SetSignatureAlgorithm(_ jose.SignatureAlgorithm) {}
+
+// verifyLogoutToken validates the logout token with the keys and the client of the ID tokens, as required by
+// https://openid.net/specs/openid-connect-backchannel-1_0.html#Validation
+func verifyLogoutToken(ctx context.Context, token string, v *rp.IDTokenVerifier) (*oidc.LogoutTokenClaims, error) {
+	if len(token) == 0 {
+		return nil, errors.New("the logout token is missing")
+	}
+	claims := &oidc.LogoutTokenClaims{}
+	payload, err := oidc.ParseToken(token, claims)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != v.Issuer {
+		return nil, fmt.Errorf("%w: Expected: %s, got: %s", oidc.ErrIssuerInvalid, v.Issuer, claims.Issuer)
+	}
+	if !slices.Contains(claims.Audience, v.ClientID) {
+		return nil, fmt.Errorf("%w: Audience must contain client_id %q", oidc.ErrAudience, v.ClientID)
+	}
+	if err = oidc.CheckSignature(ctx, token, payload, logoutTokenSignature{}, v.SupportedSignAlgs, v.KeySet); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if claims.IssuedAt == 0 {
+		return nil, oidc.ErrIatMissing
+	}
+	if claims.IssuedAt.AsTime().After(now.Add(v.Offset)) {
+		return nil, oidc.ErrIatInFuture
+	}
+	if claims.Expiration == 0 || !now.Add(v.Offset).Before(claims.Expiration.AsTime()) {
+		return nil, oidc.ErrExpired
+	}
+	if len(claims.JWTID) == 0 {
+		return nil, errors.New("the logout token must contain a jti claim")
+	}
+	if _, ok := claims.Events[backChannelLogoutEvent]; !ok {
+		return nil, fmt.Errorf("the logout token must contain the event %q", backChannelLogoutEvent)
+	}
+	if _, ok := claims.Claims["nonce"]; ok {
+		return nil, errors.New("the logout token must not contain a nonce claim")
+	}
+	if len(claims.Subject) == 0 && len(claims.SessionID) == 0 {
+		return nil, errors.New("the logout token must contain a sub or a sid claim")
+	}
+	return claims, nil
+}
@@ -0,0 +1,193 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zitadel/oidc/v3/pkg/client/rp"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"golang.org/x/oauth2"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// staticKeySet is the key set of a provider having a single key.
+type staticKeySet struct {
+	key *rsa.PublicKey
+}
+
+func (s staticKeySet) VerifySignature(_ context.Context, jws *jose.JSONWebSignature) ([]byte, error) {
+	return jws.Verify(s.key)
+}
+
+func newLogoutToken(t *testing.T, key *rsa.PrivateKey, claims *oidc.LogoutTokenClaims) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithType("logout+jwt"))
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signature, err := signer.Sign(payload)
+	require.NoError(t, err)
+	token, err := signature.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func newLogoutTokenClaims(jwtID, subject, sessionID string) *oidc.LogoutTokenClaims {
+	return oidc.NewLogoutTokenClaims(testIssuer, subject, oidc.Audience{"perses"}, time.Now().Add(time.Minute), jwtID, sessionID, 0)
+}
+
+func TestVerifyLogoutToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	verifier := rp.NewIDTokenVerifier(testIssuer, "perses", staticKeySet{key: &key.PublicKey})
+
+	tests := []struct {
+		name          string
+		claims        func(claims *oidc.LogoutTokenClaims)
+		expectedError string
+	}{
+		{
+			name:   "valid",
+			claims: func(_ *oidc.LogoutTokenClaims) {},
+		},
+		{
+			name:          "other issuer",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.Issuer = "https://other.example.com" },
+			expectedError: oidc.ErrIssuerInvalid.Error(),
+		},
+		{
+			name:          "other audience",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.Audience = oidc.Audience{"grafana"} },
+			expectedError: oidc.ErrAudience.Error(),
+		},
+		{
+			name:          "expired",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.Expiration = oidc.FromTime(time.Now().Add(-time.Minute)) },
+			expectedError: oidc.ErrExpired.Error(),
+		},
+		{
+			name:          "without jti",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.JWTID = "" },
+			expectedError: "the logout token must contain a jti claim",
+		},
+		{
+			name:          "without the logout event",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.Events = nil },
+			expectedError: "the logout token must contain the event",
+		},
+		{
+			name:          "with a nonce",
+			claims:        func(claims *oidc.LogoutTokenClaims) { claims.Claims = map[string]any{"nonce": "abc"} },
+			expectedError: "the logout token must not contain a nonce claim",
+		},
+		{
+			name: "without sub and sid",
+			claims: func(claims *oidc.LogoutTokenClaims) {
+				claims.Subject = ""
+				claims.SessionID = ""
+			},
+			expectedError: "the logout token must contain a sub or a sid claim",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := newLogoutTokenClaims("token-1", "sub-jdoe", "session-1")
+			tt.claims(claims)
+			result, err := verifyLogoutToken(context.Background(), newLogoutToken(t, key, claims), verifier)
+			if len(tt.expectedError) > 0 {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "sub-jdoe", result.Subject)
+			assert.Equal(t, "session-1", result.SessionID)
+		})
+	}
+}
+
+func TestBackChannelLogout(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	unknownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	e := &oIDCEndpoint{
+		slugID: "azure",
+		relyingParty: &RelyingPartyWithTokenEndpoint{&mockRelyingPartyWrapper{
+			verifier: rp.NewIDTokenVerifier(testIssuer, "perses", staticKeySet{key: &key.PublicKey}),
+		}},
+		providerTokens: map[string]*oauth2.Token{"jdoe": {RefreshToken: "refresh"}},
+		sessions:       newOIDCSessions(),
+	}
+	openedAt := time.Now().Add(-time.Hour)
+	e.sessions.save("sub-jdoe", "session-1", "jdoe")
+	e.sessions.save("sub-jane", "session-2", "jane")
+
+	logout := func(token string) *httptest.ResponseRecorder {
+		form := url.Values{"logout_token": {token}}
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/providers/oidc/azure/backchannel-logout", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		require.NoError(t, withOAuthErrorMdw(e.backChannelLogout)(echo.New().NewContext(req, rec)))
+		return rec
+	}
+	assertInvalidRequest := func(t *testing.T, rec *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		oauthErr := &api.OAuthError{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), oauthErr))
+		assert.Equal(t, string(oidc.InvalidRequest), oauthErr.ErrorCode)
+	}
+
+	token := newLogoutToken(t, key, newLogoutTokenClaims("token-1", "sub-jdoe", "session-1"))
+
+	t.Run("valid token", func(t *testing.T) {
+		rec := logout(token)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.ErrorIs(t, e.checkSession("jdoe", openedAt), apiinterface.UnauthorizedError)
+		assert.NotContains(t, e.providerTokens, "jdoe")
+		// The sessions opened after the logout are not ended
+		assert.NoError(t, e.checkSession("jdoe", time.Now().Add(time.Second)))
+		// Nor are the sessions of the other users
+		assert.NoError(t, e.checkSession("jane", openedAt))
+	})
+	t.Run("replayed token", func(t *testing.T) {
+		assertInvalidRequest(t, logout(token))
+	})
+	t.Run("token signed by an unknown key", func(t *testing.T) {
+		assertInvalidRequest(t, logout(newLogoutToken(t, unknownKey, newLogoutTokenClaims("token-2", "sub-jane", ""))))
+		assert.NoError(t, e.checkSession("jane", openedAt))
+	})
+	t.Run("token designating the user by its subject", func(t *testing.T) {
+		rec := logout(newLogoutToken(t, key, newLogoutTokenClaims("token-3", "sub-jane", "")))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.ErrorIs(t, e.checkSession("jane", openedAt), apiinterface.UnauthorizedError)
+	})
+}
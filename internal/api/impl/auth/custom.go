@@ -0,0 +1,152 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	pluginAPI "github.com/perses/perses/pkg/plugin/api"
+	"github.com/sirupsen/logrus"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
+	"golang.org/x/oauth2"
+)
+
+// customUserInfo implements [externalUserInfo] for the users authenticated through a custom provider.
+type customUserInfo struct {
+	pluginAPI.UserInfo
+	slugID string
+}
+
+// GetLogin implements [externalUserInfo]
+func (u *customUserInfo) GetLogin() string {
+	return u.Login
+}
+
+// GetProfile implements [externalUserInfo]
+func (u *customUserInfo) GetProfile() externalUserInfoProfile {
+	return externalUserInfoProfile{
+		GivenName:  u.FirstName,
+		FamilyName: u.LastName,
+		Email:      u.Email,
+	}
+}
+
+// GetProviderContext implements [externalUserInfo]
+func (u *customUserInfo) GetProviderContext() v1.OAuthProvider {
+	return v1.OAuthProvider{
+		// There is no issuer for a custom provider, the slug ID is the closest thing identifying it.
+		Issuer:  fmt.Sprintf("%s/%s", utils.AuthnKindCustom, u.slugID),
+		Email:   u.Email,
+		Subject: u.Login,
+	}
+}
+
+type customEndpoint struct {
+	provider        pluginAPI.CustomAuthProvider
+	slugID          string
+	tokenManagement tokenManagement
+	svc             service
+}
+
+func newCustomEndpoint(provider config.CustomProvider, jwt crypto.JWT, dao user.DAO, authz authorization.Authorization) (*customEndpoint, error) {
+	customProvider, ok := pluginAPI.GetAuthProvider(provider.SlugID)
+	if !ok {
+		return nil, fmt.Errorf("no custom auth provider has been registered with the slug_id %q", provider.SlugID)
+	}
+	return &customEndpoint{
+		provider:        customProvider,
+		slugID:          provider.SlugID,
+		tokenManagement: tokenManagement{jwt: jwt},
+		svc:             service{dao: dao, authz: authz},
+	}, nil
+}
+
+func (e *customEndpoint) GetExtraProviderLogoutHandler() echo.HandlerFunc {
+	return nil // The custom providers don't hold any session that would need to be closed
+}
+
+func (e *customEndpoint) GetAuthKind() string {
+	return utils.AuthnKindCustom
+}
+
+func (e *customEndpoint) GetSlugID() string {
+	return e.slugID
+}
+
+func (e *customEndpoint) CollectRoutes(g *route.Group) {
+	g.POST(fmt.Sprintf("/%s/%s/%s", utils.AuthnKindCustom, e.slugID, utils.PathLogin), e.auth, true)
+}
+
+func (e *customEndpoint) auth(ctx echo.Context) error {
+	body := &api.Auth{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	info, err := e.provider.Authenticate(body.Login, body.Password)
+	if err != nil {
+		if errors.Is(err, pluginAPI.ErrInvalidCredentials) {
+			return apiinterface.HandleBadRequestError(pluginAPI.ErrInvalidCredentials.Error())
+		}
+		logrus.WithError(err).Errorf("custom auth provider %q failed to authenticate the user", e.slugID)
+		return apiinterface.InternalError
+	}
+	usr, err := e.svc.syncUser(&customUserInfo{UserInfo: *info, slugID: e.slugID})
+	if err != nil {
+		logrus.WithError(err).Error("failed to sync user")
+		return apiinterface.InternalError
+	}
+	login := usr.GetMetadata().GetName()
+	providerInfo := crypto.ProviderInfo{
+		ProviderKind: utils.AuthnKindCustom,
+		ProviderID:   e.slugID,
+	}
+	accessToken, err := e.tokenManagement.accessToken(login, providerInfo, ctx.SetCookie)
+	if err != nil {
+		return err
+	}
+	refreshToken, err := e.tokenManagement.refreshToken(login, providerInfo, ctx.SetCookie)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, oauth2.Token{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    oidc.BearerToken,
+	})
+}
+
+// refresh asks the custom provider if a new access token can be delivered for the given refresh token.
+// It returns the login the access token must be issued for.
+func (e *customEndpoint) refresh(refreshToken string, login string) (string, error) {
+	info, err := e.provider.RefreshToken(refreshToken)
+	if err != nil {
+		return "", apiinterface.HandleUnauthorizedError(err.Error())
+	}
+	if info != nil && len(info.Login) > 0 {
+		return info.Login, nil
+	}
+	return login, nil
+}
@@ -15,10 +15,17 @@ package auth
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/securecookie"
@@ -77,6 +84,86 @@ func (u *oidcUserInfo) GetProviderContext() v1.OAuthProvider {
 	}
 }
 
+// OIDCDiscoveryError is returned when the OpenID configuration of a provider cannot be retrieved.
+// Next to the underlying error, it gives the information needed to fix the provider's configuration.
+type OIDCDiscoveryError struct {
+	// DiscoveryURL is the URL of the OpenID configuration that was requested.
+	DiscoveryURL string
+	// StatusCode is the status code of the response. It is 0 when no response was received.
+	StatusCode int
+	// Suggestions is a list of possible fixes, depending on the error.
+	Suggestions []string
+	Err         error
+}
+
+func newOIDCDiscoveryError(discoveryURL string, statusCode int, err error) *OIDCDiscoveryError {
+	return &OIDCDiscoveryError{
+		DiscoveryURL: discoveryURL,
+		StatusCode:   statusCode,
+		Suggestions:  discoverySuggestions(statusCode, err),
+		Err:          err,
+	}
+}
+
+func (e *OIDCDiscoveryError) Error() string {
+	msg := fmt.Sprintf("OIDC discovery failed on %q", e.DiscoveryURL)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" with status code %d", e.StatusCode)
+	}
+	msg += fmt.Sprintf(": %s", e.Err)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (suggestions: %s)", strings.Join(e.Suggestions, "; "))
+	}
+	return msg
+}
+
+func (e *OIDCDiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+func discoverySuggestions(statusCode int, err error) []string {
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, oidc.ErrIssuerInvalid):
+		return []string{"check that the `issuer` is exactly the one returned by the discovery, including the trailing slash"}
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthorityErr), errors.As(err, &hostnameErr):
+		return []string{"check the TLS configuration (`http.tls_config`), the certificate of the provider is not trusted"}
+	case errors.As(err, &dnsErr):
+		return []string{"check the host of the `issuer` or of the `discovery_url`, it cannot be resolved"}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return []string{"check the host and the port of the `issuer` or of the `discovery_url`", "check that the provider is reachable from Perses"}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return []string{"check that the provider is reachable from Perses", "increase `http.timeout`"}
+	case statusCode == http.StatusNotFound:
+		return []string{"check the `issuer`, or set the `discovery_url` if the provider doesn't serve its OpenID configuration under /.well-known/openid-configuration"}
+	case statusCode >= http.StatusInternalServerError:
+		return []string{"the provider is failing, try again later or check its status"}
+	case statusCode != 0:
+		return []string{"check the `issuer` and the `discovery_url`"}
+	}
+	return nil
+}
+
+// discoveryStatusRecorder keeps the status code of the response to the discovery request,
+// as it is not part of the error returned by the OIDC library.
+type discoveryStatusRecorder struct {
+	http.RoundTripper
+	discoveryURL string
+	statusCode   int
+}
+
+func (d *discoveryStatusRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := d.RoundTripper.RoundTrip(req)
+	if err == nil && req.URL.String() == d.discoveryURL {
+		d.statusCode = resp.StatusCode
+	}
+	return resp, err
+}
+
 type RelyingPartyWithTokenEndpoint struct {
 	rp.RelyingParty
 }
@@ -107,14 +194,22 @@ func newRelyingParty(provider config.OIDCProvider, override *config.OAuthOverrid
 	if !provider.DisablePKCE {
 		options = append(options, rp.WithPKCE(cookieHandler))
 	}
+	discoveryURL := strings.TrimSuffix(issuer, "/") + oidc.DiscoveryEndpoint
 	if !provider.DiscoveryURL.IsNilOrEmpty() {
-		options = append(options, rp.WithCustomDiscoveryUrl(provider.DiscoveryURL.String()))
+		discoveryURL = provider.DiscoveryURL.String()
+		options = append(options, rp.WithCustomDiscoveryUrl(discoveryURL))
 	}
 
 	httpClient, err := newHTTPClient(provider.HTTP)
 	if err != nil {
 		return nil, err
 	}
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	statusRecorder := &discoveryStatusRecorder{RoundTripper: transport, discoveryURL: discoveryURL}
+	httpClient.Transport = statusRecorder
 	options = append(options, rp.WithHTTPClient(httpClient))
 
 	clientID := provider.ClientID
@@ -137,7 +232,7 @@ func newRelyingParty(provider config.OIDCProvider, override *config.OAuthOverrid
 		redirectURI, scopes, options...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, newOIDCDiscoveryError(discoveryURL, statusRecorder.statusCode, err)
 	}
 	return &RelyingPartyWithTokenEndpoint{relyingParty}, nil
 }
@@ -154,6 +249,17 @@ type oIDCEndpoint struct {
 	svc                    service
 	extraLogoutHandler     echo.HandlerFunc
 	apiPrefix              string
+	claimsMapping          []config.ClaimMapping
+	// tokenRefreshEnabled tells if the tokens delivered by the provider are kept in providerTokens, by username,
+	// to be refreshed when the Perses session is refreshed.
+	tokenRefreshEnabled      bool
+	tokenRefreshBeforeExpiry time.Duration
+	providerTokens           map[string]*oauth2.Token
+	providerTokensMutex      sync.Mutex
+	// refreshProviderToken requests a new token to the provider. It is a field to be replaced in the tests.
+	refreshProviderToken func(ctx context.Context, refreshToken string) (*oauth2.Token, error)
+	// sessions is nil when the back-channel logout is disabled.
+	sessions *oidcSessions
 }
 
 func newOIDCExtraLogoutHandler(provider config.OIDCProvider, rp *RelyingPartyWithTokenEndpoint, apiPrefix string) (echo.HandlerFunc, error) {
@@ -209,19 +315,34 @@ func newOIDCEndpoint(provider config.OIDCProvider, jwt crypto.JWT, dao user.DAO,
 		return nil, err
 	}
 
-	return &oIDCEndpoint{
-		relyingParty:           relyingParty,
-		deviceCodeRelyingParty: deviceCodeRelyingParty,
-		clientCredRelyingParty: clientCredRelyingParty,
-		jwt:                    jwt,
-		tokenManagement:        tokenManagement{jwt: jwt},
-		slugID:                 provider.SlugID,
-		urlParams:              provider.URLParams,
-		issuer:                 provider.Issuer.String(),
-		svc:                    service{dao: dao, authz: authz},
-		extraLogoutHandler:     extraLogoutHandler,
-		apiPrefix:              apiPrefix,
-	}, nil
+	ep := &oIDCEndpoint{
+		relyingParty:             relyingParty,
+		deviceCodeRelyingParty:   deviceCodeRelyingParty,
+		clientCredRelyingParty:   clientCredRelyingParty,
+		jwt:                      jwt,
+		tokenManagement:          tokenManagement{jwt: jwt},
+		slugID:                   provider.SlugID,
+		urlParams:                provider.URLParams,
+		issuer:                   provider.Issuer.String(),
+		svc:                      service{dao: dao, authz: authz},
+		extraLogoutHandler:       extraLogoutHandler,
+		apiPrefix:                apiPrefix,
+		claimsMapping:            provider.ClaimsMapping,
+		tokenRefreshEnabled:      provider.Logout.TokenRefreshEnabled,
+		tokenRefreshBeforeExpiry: time.Duration(provider.Logout.TokenRefreshBeforeExpiry),
+		providerTokens:           make(map[string]*oauth2.Token),
+	}
+	ep.refreshProviderToken = func(ctx context.Context, refreshToken string) (*oauth2.Token, error) {
+		tokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](ctx, relyingParty, refreshToken, "", "")
+		if err != nil {
+			return nil, err
+		}
+		return tokens.Token, nil
+	}
+	if provider.Logout.BackChannelEnabled {
+		ep.sessions = newOIDCSessions()
+	}
+	return ep, nil
 }
 
 func (e *oIDCEndpoint) CollectRoutes(g *route.Group) {
@@ -234,6 +355,11 @@ func (e *oIDCEndpoint) CollectRoutes(g *route.Group) {
 	// Add routes for device code flow and token exchange
 	oidcGroup.POST(fmt.Sprintf("/%s", utils.PathDeviceCode), e.deviceCode, true)
 	oidcGroup.POST(fmt.Sprintf("/%s", utils.PathToken), e.token, true)
+
+	if e.sessions != nil {
+		// Add route for the back-channel logout
+		oidcGroup.POST(fmt.Sprintf("/%s", utils.PathBackChannelLogout), e.backChannelLogout, true)
+	}
 }
 
 func (e *oIDCEndpoint) GetExtraProviderLogoutHandler() echo.HandlerFunc {
@@ -278,14 +404,14 @@ func (e *oIDCEndpoint) auth(ctx echo.Context) error {
 //   - save the user in database if it's a new user, or update it with the collected information
 //   - ultimately, generate a Perses user session with an access and refresh token
 func (e *oIDCEndpoint) codeExchange(ctx echo.Context) error {
-	marshalUserinfo := func(w http.ResponseWriter, r *http.Request, _ *oidc.Tokens[*oidc.IDTokenClaims], state string, _ rp.RelyingParty, info *oidcUserInfo) {
+	marshalUserinfo := func(w http.ResponseWriter, r *http.Request, tokens *oidc.Tokens[*oidc.IDTokenClaims], state string, _ rp.RelyingParty, info *oidcUserInfo) {
 		redirectURI := decodeOAuthState(state)
 
 		setCookie := func(cookie *http.Cookie) {
 			http.SetCookie(w, cookie)
 		}
 
-		if _, err := e.performUserSync(info, setCookie); err != nil {
+		if _, err := e.performUserSync(info, tokens.IDTokenClaims, tokens.Token, setCookie); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			writeResponse(w, []byte(apiinterface.InternalError.Error()))
 			return
@@ -339,6 +465,8 @@ func (e *oIDCEndpoint) token(ctx echo.Context) error {
 	grantType := ctx.FormValue("grant_type")
 
 	var uInfo *oidcUserInfo
+	var idClaims *oidc.IDTokenClaims
+	var providerToken *oauth2.Token
 	switch api.GrantType(grantType) {
 	case api.GrantTypeDeviceCode:
 		deviceCode := ctx.FormValue("device_code")
@@ -348,7 +476,7 @@ func (e *oIDCEndpoint) token(ctx echo.Context) error {
 			e.logWithError(err).Warn("Failed to exchange device code for token")
 			return err
 		}
-		idClaims, err := rp.VerifyTokens[*oidc.IDTokenClaims](ctx.Request().Context(), resp.AccessToken, resp.IDToken, e.deviceCodeRelyingParty.IDTokenVerifier())
+		idClaims, err = rp.VerifyTokens[*oidc.IDTokenClaims](ctx.Request().Context(), resp.AccessToken, resp.IDToken, e.deviceCodeRelyingParty.IDTokenVerifier())
 		if err != nil {
 			e.logWithError(err).Error("Failed to verify token")
 			return err
@@ -358,6 +486,17 @@ func (e *oIDCEndpoint) token(ctx echo.Context) error {
 			e.logWithError(err).Error("Failed to request user info")
 			return err
 		}
+		// The token is refreshed with the main client, so it can't be kept if it was delivered to another one.
+		if e.deviceCodeRelyingParty == e.relyingParty {
+			providerToken = &oauth2.Token{
+				AccessToken:  resp.AccessToken,
+				TokenType:    resp.TokenType,
+				RefreshToken: resp.RefreshToken,
+			}
+			if resp.ExpiresIn > 0 {
+				providerToken.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+			}
+		}
 	case api.GrantTypeClientCredentials:
 		// Extract client_id and client_secret from Authorization header
 		clientID, clientSecret, ok := ctx.Request().BasicAuth()
@@ -377,7 +516,7 @@ func (e *oIDCEndpoint) token(ctx echo.Context) error {
 		return oidc.ErrUnsupportedGrantType()
 	}
 
-	resp, err := e.performUserSync(uInfo, ctx.SetCookie)
+	resp, err := e.performUserSync(uInfo, idClaims, providerToken, ctx.SetCookie)
 	if err != nil {
 		return err
 	}
@@ -385,7 +524,9 @@ func (e *oIDCEndpoint) token(ctx echo.Context) error {
 }
 
 // performUserSync performs user synchronization and generates access and refresh tokens.
-func (e *oIDCEndpoint) performUserSync(userInfo *oidcUserInfo, setCookie func(cookie *http.Cookie)) (*oauth2.Token, error) {
+// The roles resolved from the claims mapping of the ID token, if any, are saved in the tokens.
+// The token delivered by the provider, if any, is kept to be refreshed later.
+func (e *oIDCEndpoint) performUserSync(userInfo *oidcUserInfo, idClaims *oidc.IDTokenClaims, providerToken *oauth2.Token, setCookie func(cookie *http.Cookie)) (*oauth2.Token, error) {
 	// We don´t forget to set the issuer before making any sync in the database.
 	userInfo.issuer = e.issuer
 
@@ -397,9 +538,14 @@ func (e *oIDCEndpoint) performUserSync(userInfo *oidcUserInfo, setCookie func(co
 
 	// Generate and save access and refresh tokens
 	username := usr.GetMetadata().GetName()
+	e.saveProviderToken(username, providerToken)
+	if e.sessions != nil && idClaims != nil {
+		e.sessions.save(idClaims.Subject, idClaims.SessionID, username)
+	}
 	providerInfo := crypto.ProviderInfo{
 		ProviderKind: utils.AuthnKindOIDC,
 		ProviderID:   e.slugID,
+		Roles:        e.mapClaims(idClaims),
 	}
 	accessToken, err := e.tokenManagement.accessToken(username, providerInfo, setCookie)
 	if err != nil {
@@ -419,6 +565,85 @@ func (e *oIDCEndpoint) performUserSync(userInfo *oidcUserInfo, setCookie func(co
 	}, nil
 }
 
+// saveProviderToken keeps the token delivered by the provider to the user, when the token refresh is enabled
+// and the provider gave a refresh token.
+func (e *oIDCEndpoint) saveProviderToken(username string, token *oauth2.Token) {
+	if !e.tokenRefreshEnabled || token == nil || len(token.RefreshToken) == 0 {
+		return
+	}
+	e.providerTokensMutex.Lock()
+	defer e.providerTokensMutex.Unlock()
+	e.providerTokens[username] = token
+}
+
+// refresh is called when the Perses session of the user is refreshed.
+// When the token delivered by the provider is about to expire, it is refreshed.
+// If the provider refuses it, the user must log in again.
+// Nothing is done for a user whose token isn't known, like after a restart of Perses, as the tokens are kept in memory.
+func (e *oIDCEndpoint) refresh(ctx context.Context, username string) error {
+	if !e.tokenRefreshEnabled {
+		return nil
+	}
+	e.providerTokensMutex.Lock()
+	token, ok := e.providerTokens[username]
+	e.providerTokensMutex.Unlock()
+	if !ok || token.Expiry.IsZero() || time.Until(token.Expiry) > e.tokenRefreshBeforeExpiry {
+		return nil
+	}
+	newToken, err := e.refreshProviderToken(ctx, token.RefreshToken)
+	if err != nil {
+		e.providerTokensMutex.Lock()
+		delete(e.providerTokens, username)
+		e.providerTokensMutex.Unlock()
+		e.logWithError(err).Warnf("Failed to refresh the token of the user %q", username)
+		return apiinterface.HandleUnauthorizedError("the session at the OIDC provider has expired, please log in again")
+	}
+	if len(newToken.RefreshToken) == 0 {
+		// The provider may not rotate the refresh token.
+		newToken.RefreshToken = token.RefreshToken
+	}
+	e.saveProviderToken(username, newToken)
+	return nil
+}
+
+// mapClaims returns the roles granted by the claims mapping of the provider to the verified ID token.
+func (e *oIDCEndpoint) mapClaims(idClaims *oidc.IDTokenClaims) []string {
+	if idClaims == nil {
+		return nil
+	}
+	return mapClaimsToRoles(e.claimsMapping, idClaims.Claims)
+}
+
+// mapClaimsToRoles walks the claims mapping and returns the roles of the entries matching the given raw claims,
+// without duplicates.
+// A claim matches if it is a string equal to the expected value, or a list containing it.
+func mapClaimsToRoles(mapping []config.ClaimMapping, claims map[string]any) []string {
+	var roles []string
+	for _, m := range mapping {
+		if !claimHasValue(claims[m.Claim], m.Value) || slices.Contains(roles, m.Role) {
+			continue
+		}
+		roles = append(roles, m.Role)
+	}
+	return roles
+}
+
+func claimHasValue(claim any, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []any:
+		for _, item := range c {
+			if str, ok := item.(string); ok && str == value {
+				return true
+			}
+		}
+	case []string:
+		return slices.Contains(c, value)
+	}
+	return false
+}
+
 // retrieveDeviceAccessToken exchanges the device code for an access token,
 // from the OAuth 2.0 provider.
 // It duplicates the original implementation of OIDC library to make only one query instead of polling.
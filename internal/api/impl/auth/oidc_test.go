@@ -16,19 +16,30 @@ package auth
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/go-jose/go-jose/v4"
 	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/spec/go/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zitadel/oidc/v3/pkg/client/rp"
 	httphelper "github.com/zitadel/oidc/v3/pkg/http"
+	"github.com/zitadel/oidc/v3/pkg/oidc"
 	"golang.org/x/oauth2"
 )
 
@@ -230,6 +241,204 @@ func TestNewOIDCExtraLogoutHandler(t *testing.T) {
 	}
 }
 
+// newTestDiscoveryServer serves the minimal OpenID discovery document required to create a relying party.
+func newTestDiscoveryServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		_, _ = fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+			server.URL, server.URL+"/authorize", server.URL+"/token", server.URL+"/keys")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewRelyingParty_PKCE(t *testing.T) {
+	server := newTestDiscoveryServer(t)
+	for _, test := range []struct {
+		name        string
+		disablePKCE bool
+	}{
+		{name: "PKCE enabled by default", disablePKCE: false},
+		{name: "PKCE disabled", disablePKCE: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			issuer, err := common.ParseURL(server.URL)
+			require.NoError(t, err)
+			provider := config.OIDCProvider{
+				Provider:    config.Provider{SlugID: "test", ClientID: "client"},
+				Issuer:      *issuer,
+				DisablePKCE: test.disablePKCE,
+			}
+			relyingParty, err := newRelyingParty(provider, nil)
+			require.NoError(t, err)
+			assert.Equal(t, !test.disablePKCE, relyingParty.IsPKCE())
+			// The code verifier is stored in the same signed cookies as the state.
+			assert.NotNil(t, relyingParty.CookieHandler())
+		})
+	}
+}
+
+func TestNewRelyingParty_DiscoveryError(t *testing.T) {
+	notFoundServer := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(notFoundServer.Close)
+	wrongIssuerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		_, _ = w.Write([]byte(`{"issuer":"https://another-issuer.example.com"}`))
+	}))
+	t.Cleanup(wrongIssuerServer.Close)
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+
+	for _, test := range []struct {
+		name               string
+		issuer             string
+		discoveryURL       string
+		expectedURL        string
+		expectedStatusCode int
+		expectedSuggestion string
+	}{
+		{
+			name:               "discovery document not found",
+			issuer:             notFoundServer.URL,
+			expectedURL:        notFoundServer.URL + "/.well-known/openid-configuration",
+			expectedStatusCode: http.StatusNotFound,
+			expectedSuggestion: "set the `discovery_url`",
+		},
+		{
+			name:               "custom discovery URL not found",
+			issuer:             notFoundServer.URL,
+			discoveryURL:       notFoundServer.URL + "/custom/openid-configuration",
+			expectedURL:        notFoundServer.URL + "/custom/openid-configuration",
+			expectedStatusCode: http.StatusNotFound,
+			expectedSuggestion: "set the `discovery_url`",
+		},
+		{
+			name:               "issuer mismatch",
+			issuer:             wrongIssuerServer.URL,
+			expectedURL:        wrongIssuerServer.URL + "/.well-known/openid-configuration",
+			expectedStatusCode: http.StatusOK,
+			expectedSuggestion: "including the trailing slash",
+		},
+		{
+			name:               "certificate not trusted",
+			issuer:             tlsServer.URL,
+			expectedURL:        tlsServer.URL + "/.well-known/openid-configuration",
+			expectedSuggestion: "check the TLS configuration",
+		},
+		{
+			name:               "connection refused",
+			issuer:             closedServer.URL,
+			expectedURL:        closedServer.URL + "/.well-known/openid-configuration",
+			expectedSuggestion: "check that the provider is reachable from Perses",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			issuer, err := common.ParseURL(test.issuer)
+			require.NoError(t, err)
+			provider := config.OIDCProvider{
+				Provider: config.Provider{SlugID: "test", ClientID: "client"},
+				Issuer:   *issuer,
+			}
+			if len(test.discoveryURL) > 0 {
+				discoveryURL, parseErr := common.ParseURL(test.discoveryURL)
+				require.NoError(t, parseErr)
+				provider.DiscoveryURL = *discoveryURL
+			}
+			_, err = newRelyingParty(provider, nil)
+			var discoveryErr *OIDCDiscoveryError
+			require.ErrorAs(t, err, &discoveryErr)
+			assert.Equal(t, test.expectedURL, discoveryErr.DiscoveryURL)
+			assert.Equal(t, test.expectedStatusCode, discoveryErr.StatusCode)
+			require.NotEmpty(t, discoveryErr.Suggestions)
+			assert.Contains(t, discoveryErr.Error(), fmt.Sprintf("OIDC discovery failed on %q", test.expectedURL))
+			assert.Contains(t, discoveryErr.Error(), test.expectedSuggestion)
+			assert.NotNil(t, discoveryErr.Unwrap())
+		})
+	}
+}
+
+type fakeProviderInfoAuthorization struct {
+	authorization.Authorization
+	providerInfo crypto.ProviderInfo
+}
+
+func (a *fakeProviderInfoAuthorization) GetProviderInfo(_ echo.Context) (crypto.ProviderInfo, error) {
+	return a.providerInfo, nil
+}
+
+func newTestOIDCEndpoint(t *testing.T, slugID string, endSessionEndpoint string) *oIDCEndpoint {
+	provider := config.OIDCProvider{SlugID: slugID, Logout: config.OIDCLogout{Enabled: true}}
+	relyingParty := &RelyingPartyWithTokenEndpoint{RelyingParty: &mockRelyingPartyWrapper{
+		endSessionEndpoint: endSessionEndpoint,
+		clientID:           slugID + "-client",
+	}}
+	logoutHandler, err := newOIDCExtraLogoutHandler(provider, relyingParty, "")
+	require.NoError(t, err)
+	return &oIDCEndpoint{
+		relyingParty:       relyingParty,
+		slugID:             slugID,
+		extraLogoutHandler: logoutHandler,
+	}
+}
+
+func TestMultipleOIDCProviders(t *testing.T) {
+	azure := newTestOIDCEndpoint(t, "azure", "https://login.microsoftonline.com/logout")
+	okta := newTestOIDCEndpoint(t, "okta", "https://example.okta.com/logout")
+
+	t.Run("each provider has its own callback", func(t *testing.T) {
+		g := &route.Group{}
+		azure.CollectRoutes(g)
+		okta.CollectRoutes(g)
+		require.Len(t, g.Groups, 2)
+		assert.Equal(t, "/oidc/azure", g.Groups[0].Path)
+		assert.Equal(t, "/oidc/okta", g.Groups[1].Path)
+		for _, group := range g.Groups {
+			var paths []string
+			for _, r := range group.Routes {
+				paths = append(paths, r.Path)
+			}
+			assert.Contains(t, paths, "/"+utils.PathCallback)
+		}
+	})
+
+	t.Run("logout uses the provider of the session", func(t *testing.T) {
+		_, jwt, err := crypto.New(config.Security{EncryptionKey: "3132333435363738393031323334353637383930313233343536373839303132"})
+		require.NoError(t, err)
+		for _, test := range []struct {
+			slugID           string
+			expectedLocation string
+		}{
+			{slugID: "azure", expectedLocation: "https://login.microsoftonline.com/logout"},
+			{slugID: "okta", expectedLocation: "https://example.okta.com/logout"},
+		} {
+			ep := &endpoint{
+				endpoints: []authEndpoint{azure, okta},
+				jwt:       jwt,
+				authz: &fakeProviderInfoAuthorization{providerInfo: crypto.ProviderInfo{
+					ProviderKind: utils.AuthnKindOIDC,
+					ProviderID:   test.slugID,
+				}},
+			}
+			req := httptest.NewRequest(http.MethodGet, "/logout", nil)
+			req.Host = "localhost:8080"
+			rec := httptest.NewRecorder()
+			require.NoError(t, ep.logout(echo.New().NewContext(req, rec)))
+			assert.Equal(t, http.StatusFound, rec.Code)
+			location, err := url.Parse(rec.Header().Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedLocation, location.Scheme+"://"+location.Host+location.Path)
+			assert.Equal(t, test.slugID+"-client", location.Query().Get("client_id"))
+		}
+	})
+}
+
 func TestOIDCExtraLogoutHandler(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -308,6 +517,7 @@ func TestOIDCExtraLogoutHandler(t *testing.T) {
 type mockRelyingPartyWrapper struct {
 	endSessionEndpoint string
 	clientID           string
+	verifier           *rp.IDTokenVerifier
 }
 
 func (m *mockRelyingPartyWrapper) GetEndSessionEndpoint() string {
@@ -329,9 +539,191 @@ func (m *mockRelyingPartyWrapper) HttpClient() *http.Client
 func (m *mockRelyingPartyWrapper) GetDeviceAuthorizationEndpoint() string          { return "" }
 func (m *mockRelyingPartyWrapper) GetRevokeEndpoint() string                       { return "" }
 func (m *mockRelyingPartyWrapper) UserinfoEndpoint() string                        { return "" }
-func (m *mockRelyingPartyWrapper) IDTokenVerifier() *rp.IDTokenVerifier            { return nil }
+func (m *mockRelyingPartyWrapper) IDTokenVerifier() *rp.IDTokenVerifier            { return m.verifier }
 func (m *mockRelyingPartyWrapper) Signer() jose.Signer                             { return nil }
 func (m *mockRelyingPartyWrapper) Logger(ctx context.Context) (*slog.Logger, bool) { return nil, false }
 func (m *mockRelyingPartyWrapper) ErrorHandler() func(w http.ResponseWriter, r *http.Request, errorType string, errorDesc string, state string) {
 	return nil
 }
+
+func TestMapClaims(t *testing.T) {
+	e := &oIDCEndpoint{claimsMapping: []config.ClaimMapping{
+		{Claim: "groups", Value: "sre", Role: "admin"},
+		{Claim: "groups", Value: "dev", Role: "editor"},
+		{Claim: "department", Value: "finance", Role: "admin"},
+		{Claim: "roles", Value: "viewer", Role: "viewer"},
+	}}
+	tests := []struct {
+		name          string
+		idToken       string
+		expectedRoles []string
+	}{
+		{
+			name:    "no matching claim",
+			idToken: `{"sub":"jdoe","groups":["ops"],"department":"hr"}`,
+		},
+		{
+			name:    "claim missing",
+			idToken: `{"sub":"jdoe"}`,
+		},
+		{
+			name:          "list claim",
+			idToken:       `{"sub":"jdoe","groups":["ops","dev"]}`,
+			expectedRoles: []string{"editor"},
+		},
+		{
+			name:          "string claim",
+			idToken:       `{"sub":"jdoe","roles":"viewer"}`,
+			expectedRoles: []string{"viewer"},
+		},
+		{
+			name:          "role granted by several claims only once",
+			idToken:       `{"sub":"jdoe","groups":["sre","dev"],"department":"finance"}`,
+			expectedRoles: []string{"admin", "editor"},
+		},
+		{
+			name:    "value of another type",
+			idToken: `{"sub":"jdoe","groups":[42],"department":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idClaims := &oidc.IDTokenClaims{}
+			require.NoError(t, json.Unmarshal([]byte(tt.idToken), idClaims))
+			assert.Equal(t, tt.expectedRoles, e.mapClaims(idClaims))
+		})
+	}
+
+	t.Run("no ID token", func(t *testing.T) {
+		assert.Nil(t, e.mapClaims(nil))
+	})
+}
+
+func TestOIDCEndpointRefresh(t *testing.T) {
+	errRefresh := errors.New("invalid_grant")
+	tests := []struct {
+		name                 string
+		tokenRefreshEnabled  bool
+		token                *oauth2.Token
+		refreshErr           error
+		newToken             *oauth2.Token
+		expectRefresh        bool
+		expectUnauthorized   bool
+		expectedRefreshToken string
+	}{
+		{
+			name:                "disabled",
+			tokenRefreshEnabled: false,
+			token:               &oauth2.Token{RefreshToken: "refresh", Expiry: time.Now().Add(10 * time.Second)},
+		},
+		{
+			name:                "unknown user",
+			tokenRefreshEnabled: true,
+		},
+		{
+			name:                 "token far from expiry",
+			tokenRefreshEnabled:  true,
+			token:                &oauth2.Token{RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+			expectedRefreshToken: "refresh",
+		},
+		{
+			name:                 "refresh success",
+			tokenRefreshEnabled:  true,
+			token:                &oauth2.Token{RefreshToken: "refresh", Expiry: time.Now().Add(10 * time.Second)},
+			newToken:             &oauth2.Token{AccessToken: "new", RefreshToken: "new-refresh", Expiry: time.Now().Add(time.Hour)},
+			expectRefresh:        true,
+			expectedRefreshToken: "new-refresh",
+		},
+		{
+			name:                 "refresh token not rotated",
+			tokenRefreshEnabled:  true,
+			token:                &oauth2.Token{RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)},
+			newToken:             &oauth2.Token{AccessToken: "new", Expiry: time.Now().Add(time.Hour)},
+			expectRefresh:        true,
+			expectedRefreshToken: "refresh",
+		},
+		{
+			name:                "refresh failure",
+			tokenRefreshEnabled: true,
+			token:               &oauth2.Token{RefreshToken: "refresh", Expiry: time.Now().Add(10 * time.Second)},
+			refreshErr:          errRefresh,
+			expectRefresh:       true,
+			expectUnauthorized:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshed := false
+			e := &oIDCEndpoint{
+				slugID:                   "azure",
+				tokenRefreshEnabled:      tt.tokenRefreshEnabled,
+				tokenRefreshBeforeExpiry: time.Minute,
+				providerTokens:           make(map[string]*oauth2.Token),
+				refreshProviderToken: func(_ context.Context, refreshToken string) (*oauth2.Token, error) {
+					refreshed = true
+					assert.Equal(t, "refresh", refreshToken)
+					return tt.newToken, tt.refreshErr
+				},
+			}
+			if tt.token != nil {
+				e.providerTokens["jdoe"] = tt.token
+			}
+			err := e.refresh(context.Background(), "jdoe")
+			assert.Equal(t, tt.expectRefresh, refreshed)
+			if tt.expectUnauthorized {
+				assert.ErrorIs(t, err, apiinterface.UnauthorizedError)
+				assert.NotContains(t, e.providerTokens, "jdoe")
+				return
+			}
+			require.NoError(t, err)
+			if len(tt.expectedRefreshToken) > 0 {
+				assert.Equal(t, tt.expectedRefreshToken, e.providerTokens["jdoe"].RefreshToken)
+			}
+		})
+	}
+}
+
+func TestRefreshWithOIDCProviderToken(t *testing.T) {
+	_, jwt, err := crypto.New(config.Security{EncryptionKey: "3132333435363738393031323334353637383930313233343536373839303132"})
+	require.NoError(t, err)
+	refreshToken, err := jwt.SignedRefreshToken("jdoe", crypto.ProviderInfo{ProviderKind: utils.AuthnKindOIDC, ProviderID: "azure"})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name       string
+		refreshErr error
+		status     int
+	}{
+		{name: "provider token refreshed", status: http.StatusOK},
+		{name: "provider token refused", refreshErr: errors.New("invalid_grant"), status: http.StatusUnauthorized},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			oidcEp := &oIDCEndpoint{
+				slugID:                   "azure",
+				tokenRefreshEnabled:      true,
+				tokenRefreshBeforeExpiry: time.Minute,
+				providerTokens: map[string]*oauth2.Token{
+					"jdoe": {RefreshToken: "refresh", Expiry: time.Now().Add(10 * time.Second)},
+				},
+				refreshProviderToken: func(_ context.Context, _ string) (*oauth2.Token, error) {
+					return &oauth2.Token{AccessToken: "new", Expiry: time.Now().Add(time.Hour)}, test.refreshErr
+				},
+			}
+			ep := &endpoint{
+				endpoints:       []authEndpoint{oidcEp},
+				jwt:             jwt,
+				tokenManagement: tokenManagement{jwt: jwt},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+			req.AddCookie(jwt.CreateRefreshTokenCookie(refreshToken))
+			rec := httptest.NewRecorder()
+			err := ep.refresh(echo.New().NewContext(req, rec))
+			if test.status != http.StatusOK {
+				assert.Equal(t, test.status, apiinterface.HandleError(err).(*echo.HTTPError).Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/dashboard"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type request struct {
+	A *v1.Dashboard `json:"a"`
+	B *v1.Dashboard `json:"b"`
+}
+
+type endpoint struct{}
+
+func New() route.Endpoint {
+	return &endpoint{}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.Group(fmt.Sprintf("/%s", utils.PathDashboard)).POST("/compare", e.CompareDashboards, true)
+}
+
+func (e *endpoint) CompareDashboards(ctx echo.Context) error {
+	body := &request{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if body.A == nil || body.B == nil {
+		return apiinterface.HandleBadRequestError("the dashboards \"a\" and \"b\" to compare are both required")
+	}
+	result, err := dashboard.Compare(body.A, body.B)
+	if err != nil {
+		logrus.WithError(err).Error("unable to compare the dashboards")
+		return apiinterface.InternalError
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,63 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/jsonpatch"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type diffRequest struct {
+	Left  *v1.Dashboard `json:"left"`
+	Right *v1.Dashboard `json:"right"`
+}
+
+type diffEndpoint struct{}
+
+// NewDiff returns the endpoint computing the JSON Patch between two dashboards. It is served under /api/v1.
+func NewDiff() route.Endpoint {
+	return &diffEndpoint{}
+}
+
+func (e *diffEndpoint) CollectRoutes(g *route.Group) {
+	g.Group(fmt.Sprintf("/%s", utils.PathDashboard)).POST("/diff", e.DiffDashboards, true)
+}
+
+func (e *diffEndpoint) DiffDashboards(ctx echo.Context) error {
+	body := &diffRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if body.Left == nil || body.Right == nil {
+		return apiinterface.HandleBadRequestError("the dashboards \"left\" and \"right\" to diff are both required")
+	}
+	operations, err := jsonpatch.Diff(body.Left, body.Right)
+	if err != nil {
+		logrus.WithError(err).Error("unable to diff the dashboards")
+		return apiinterface.InternalError
+	}
+	if operations == nil {
+		// The response is always an array, even when the dashboards are identical.
+		operations = []jsonpatch.Operation{}
+	}
+	return ctx.JSON(http.StatusOK, operations)
+}
@@ -0,0 +1,131 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/jsonpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cpuPanel = `{"kind": "Panel", "spec": {"display": {"name": "CPU"}, "plugin": {"kind": "TimeSeriesChart", "spec": {}}}}`
+	jobVar   = `{"kind": "TextVariable", "spec": {"name": "job", "value": "api"}}`
+	envVar   = `{"kind": "TextVariable", "spec": {"name": "env", "value": "prod"}}`
+)
+
+func dashboardJSON(panels string, variables string, timeRange string) string {
+	return fmt.Sprintf(`{
+  "kind": "Dashboard",
+  "metadata": {"name": "test", "project": "perses"},
+  "spec": {"panels": %s, "variables": %s, "layouts": [], %s}
+}`, panels, variables, timeRange)
+}
+
+func diff(t *testing.T, body string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/dashboards/diff", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	err := (&diffEndpoint{}).DiffDashboards(echo.New().NewContext(req, rec))
+	return rec, err
+}
+
+func TestDiffDashboards(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		left   string
+		right  string
+		result []jsonpatch.Operation
+	}{
+		{
+			title:  "identical dashboards",
+			left:   dashboardJSON(`{"cpu": `+cpuPanel+`}`, `[`+jobVar+`]`, `"duration": "1h"`),
+			right:  dashboardJSON(`{"cpu": `+cpuPanel+`}`, `[`+jobVar+`]`, `"duration": "1h"`),
+			result: []jsonpatch.Operation{},
+		},
+		{
+			title: "panel added to an empty panels map",
+			left:  dashboardJSON(`{}`, `[]`, `"duration": "1h"`),
+			right: dashboardJSON(`{"cpu": `+cpuPanel+`}`, `[]`, `"duration": "1h"`),
+			result: []jsonpatch.Operation{
+				{Op: jsonpatch.OpAdd, Path: "/spec/panels/cpu"},
+			},
+		},
+		{
+			title: "every panel removed",
+			left:  dashboardJSON(`{"cpu": `+cpuPanel+`}`, `[]`, `"duration": "1h"`),
+			right: dashboardJSON(`{}`, `[]`, `"duration": "1h"`),
+			result: []jsonpatch.Operation{
+				{Op: jsonpatch.OpRemove, Path: "/spec/panels/cpu"},
+			},
+		},
+		{
+			title: "last variable removed",
+			left:  dashboardJSON(`{}`, `[`+jobVar+`,`+envVar+`]`, `"duration": "1h"`),
+			right: dashboardJSON(`{}`, `[`+jobVar+`]`, `"duration": "1h"`),
+			result: []jsonpatch.Operation{
+				{Op: jsonpatch.OpRemove, Path: "/spec/variables/1"},
+			},
+		},
+		{
+			title: "every variable removed",
+			left:  dashboardJSON(`{}`, `[`+jobVar+`]`, `"duration": "1h"`),
+			right: dashboardJSON(`{}`, `[]`, `"duration": "1h"`),
+			result: []jsonpatch.Operation{
+				{Op: jsonpatch.OpRemove, Path: "/spec/variables"},
+			},
+		},
+		{
+			title: "time range changed",
+			left:  dashboardJSON(`{}`, `[]`, `"duration": "1h"`),
+			right: dashboardJSON(`{}`, `[]`, `"duration": "6h", "refreshInterval": "30s"`),
+			result: []jsonpatch.Operation{
+				{Op: jsonpatch.OpReplace, Path: "/spec/duration", Value: "6h"},
+				{Op: jsonpatch.OpAdd, Path: "/spec/refreshInterval", Value: "30s"},
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			rec, err := diff(t, fmt.Sprintf(`{"left": %s, "right": %s}`, test.left, test.right))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var result []jsonpatch.Operation
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			require.Len(t, result, len(test.result))
+			for i, operation := range test.result {
+				assert.Equal(t, operation.Op, result[i].Op)
+				assert.Equal(t, operation.Path, result[i].Path)
+				if operation.Value != nil {
+					assert.Equal(t, operation.Value, result[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffDashboards_MissingDashboard(t *testing.T) {
+	_, err := diff(t, fmt.Sprintf(`{"left": %s}`, dashboardJSON(`{}`, `[]`, `"duration": "1h"`)))
+	assert.ErrorIs(t, err, apiinterface.BadRequestError)
+}
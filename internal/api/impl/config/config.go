@@ -14,27 +14,142 @@
 package configendpoint
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/plugin"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/sirupsen/logrus"
 )
 
+// corsUpdater applies a new CORS configuration to the running server.
+type corsUpdater interface {
+	Update(conf config.CORSConfig)
+}
+
+// pluginUpdater reloads the plugins with a new filter or new archive paths.
+type pluginUpdater interface {
+	UpdateFilter(filter plugin.Filter) error
+	UpdateArchivePaths(paths []string, downloadTimeout time.Duration) error
+}
+
 type endpoint struct {
-	cfg config.Config
+	mutex sync.RWMutex
+	cfg   config.Config
+	// configFile is the path of the file the configuration has been read from.
+	// It is empty when the configuration only comes from the environment, in which case the changes are not persisted.
+	configFile string
+	authz      authorization.Authorization
+	cors       corsUpdater
+	plugin     pluginUpdater
+	readonly   bool
 }
 
-func New(cfg config.Config) route.Endpoint {
+func New(cfg config.Config, configFile string, authz authorization.Authorization, cors corsUpdater, plg pluginUpdater) route.Endpoint {
 	return &endpoint{
-		cfg: cfg,
+		cfg:        cfg,
+		configFile: configFile,
+		authz:      authz,
+		cors:       cors,
+		plugin:     plg,
+		readonly:   cfg.Security.Readonly,
 	}
 }
 
 func (e *endpoint) CollectRoutes(g *route.Group) {
 	g.GET("/config", e.getConfig, true)
+	admin := g.Group("/admin")
+	admin.GET("/config", e.getAdminConfig, false)
+	if !e.readonly {
+		admin.PATCH("/config", e.patchConfig, false)
+	}
 }
 
 func (e *endpoint) getConfig(ctx echo.Context) error {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return ctx.JSON(http.StatusOK, e.cfg)
+}
+
+func (e *endpoint) getAdminConfig(ctx echo.Context) error {
+	if err := e.checkAdmin(ctx); err != nil {
+		return err
+	}
+	return e.getConfig(ctx)
+}
+
+// patchConfig applies a JSON Merge Patch (RFC 7396) on the mutable fields of the configuration.
+func (e *endpoint) patchConfig(ctx echo.Context) error {
+	if err := e.checkAdmin(ctx); err != nil {
+		return err
+	}
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	patch := map[string]any{}
+	if unmarshalErr := json.Unmarshal(body, &patch); unmarshalErr != nil {
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("the body must be a JSON object: %s", unmarshalErr))
+	}
+	if immutableErr := checkMutablePaths(patch, nil); immutableErr != nil {
+		return apiinterface.HandleBadRequestError(immutableErr.Error())
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	newCfg, err := applyPatch(e.cfg, patch)
+	if err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+	if len(e.configFile) > 0 {
+		if persistErr := persistPatch(e.configFile, withoutDeprecatedFields(patch)); persistErr != nil {
+			logrus.WithError(persistErr).Errorf("unable to save the configuration in %q", e.configFile)
+			return apiinterface.InternalError
+		}
+	} else {
+		logrus.Warn("the configuration has been updated but is not backed by a file, the changes will be lost on restart")
+	}
+	previous := e.cfg
+	e.cfg = newCfg
+	if applyErr := e.apply(previous, newCfg); applyErr != nil {
+		return applyErr
+	}
 	return ctx.JSON(http.StatusOK, e.cfg)
 }
+
+// apply makes the server use the new configuration, so the changes take effect without restarting.
+func (e *endpoint) apply(previous config.Config, current config.Config) error {
+	e.cors.Update(current.Security.CORS)
+	filter := plugin.NewFilter(current.Plugin)
+	if !plugin.NewFilter(previous.Plugin).Equal(filter) {
+		if err := e.plugin.UpdateFilter(filter); err != nil {
+			logrus.WithError(err).Error("unable to reload the plugins with the new lists of enabled and disabled plugins")
+			return apiinterface.InternalError
+		}
+	}
+	if !slices.Equal(previous.Plugin.ArchivePaths, current.Plugin.ArchivePaths) || previous.Plugin.DownloadTimeout != current.Plugin.DownloadTimeout {
+		if err := e.plugin.UpdateArchivePaths(current.Plugin.ArchivePaths, time.Duration(current.Plugin.DownloadTimeout)); err != nil {
+			logrus.WithError(err).Error("unable to reload the plugins from the new archive paths")
+			return apiinterface.InternalError
+		}
+	}
+	return nil
+}
+
+func (e *endpoint) checkAdmin(ctx echo.Context) error {
+	if !e.authz.HasPermission(ctx, role.WildcardAction, v1.WildcardProject, role.WildcardScope) {
+		return apiinterface.HandleForbiddenError("only administrators can manage the server configuration")
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configendpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfigFile = `security:
+  # the key used to encrypt the secrets
+  encryption_key: "=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"
+database:
+  file:
+    folder: ./dev/data
+plugin:
+  path: ./plugins
+  archive_paths:
+    - ./plugins-archive
+`
+
+type fakeAuthorization struct {
+	authorization.Authorization
+	isAdmin bool
+}
+
+func (f *fakeAuthorization) HasPermission(_ echo.Context, _ v1Role.Action, _ string, _ v1Role.Scope) bool {
+	return f.isAdmin
+}
+
+type fakeCORS struct {
+	updates []config.CORSConfig
+}
+
+func (f *fakeCORS) Update(conf config.CORSConfig) {
+	f.updates = append(f.updates, conf)
+}
+
+type fakePlugin struct {
+	filters      []plugin.Filter
+	archivePaths [][]string
+}
+
+func (f *fakePlugin) UpdateFilter(filter plugin.Filter) error {
+	f.filters = append(f.filters, filter)
+	return nil
+}
+
+func (f *fakePlugin) UpdateArchivePaths(paths []string, _ time.Duration) error {
+	f.archivePaths = append(f.archivePaths, paths)
+	return nil
+}
+
+func newTestEndpoint(t *testing.T, isAdmin bool) (*endpoint, string) {
+	return newTestEndpointWithFile(t, isAdmin, "config.yaml", testConfigFile)
+}
+
+func newTestEndpointWithFile(t *testing.T, isAdmin bool, name string, content string) (*endpoint, string) {
+	configFile := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+	cfg := config.Config{
+		Security: config.Security{
+			EncryptionKey: secret.Hidden("=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"),
+		},
+		Plugin: config.Plugin{
+			Path:         "./plugins",
+			ArchivePaths: []string{"./plugins-archive"},
+		},
+	}
+	return New(cfg, configFile, &fakeAuthorization{isAdmin: isAdmin}, &fakeCORS{}, &fakePlugin{}).(*endpoint), configFile
+}
+
+func newTestContext(method string, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/api/admin/config", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func readConfigFile(t *testing.T, configFile string) map[string]any {
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	result := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(data, &result))
+	return result
+}
+
+func TestGetAdminConfig(t *testing.T) {
+	ept, _ := newTestEndpoint(t, true)
+	ctx, rec := newTestContext(http.MethodGet, "")
+	require.NoError(t, ept.getAdminConfig(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "=tW$56zytgB")
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "<secret>", result["security"].(map[string]any)["encryption_key"])
+}
+
+func TestGetAdminConfig_Forbidden(t *testing.T) {
+	ept, _ := newTestEndpoint(t, false)
+	ctx, _ := newTestContext(http.MethodGet, "")
+	err := ept.getAdminConfig(ctx)
+	assert.True(t, errors.Is(err, apiinterface.ForbiddenError))
+}
+
+func TestPatchConfig(t *testing.T) {
+	ept, configFile := newTestEndpoint(t, true)
+	ctx, rec := newTestContext(http.MethodPatch, `{
+  "security": {"cors": {"enable": true, "allow_origins": ["https://perses.dev"]}},
+  "plugin": {"disabled": ["Tempo"]}
+}`)
+	require.NoError(t, ept.patchConfig(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The configuration in memory is updated
+	assert.True(t, ept.cfg.Security.CORS.Enable)
+	assert.Equal(t, []string{"https://perses.dev"}, ept.cfg.Security.CORS.AllowOrigins)
+	assert.Equal(t, []string{"tempo"}, ept.cfg.Plugin.Disabled)
+	assert.Equal(t, []string{"./plugins-archive"}, ept.cfg.Plugin.ArchivePaths)
+	assert.Equal(t, secret.Hidden("=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"), ept.cfg.Security.EncryptionKey)
+
+	// The configuration file is updated without losing the secrets
+	persisted := readConfigFile(t, configFile)
+	security := persisted["security"].(map[string]any)
+	assert.Equal(t, "=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc", security["encryption_key"])
+	assert.Equal(t, true, security["cors"].(map[string]any)["enable"])
+	assert.Equal(t, []any{"Tempo"}, persisted["plugin"].(map[string]any)["disabled"])
+	assert.Equal(t, "./dev/data", persisted["database"].(map[string]any)["file"].(map[string]any)["folder"])
+
+	// Only the patched keys are changed, the comments are kept
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# the key used to encrypt the secrets")
+
+	// The changes are applied to the running server
+	assert.Equal(t, []config.CORSConfig{ept.cfg.Security.CORS}, ept.cors.(*fakeCORS).updates)
+	assert.Equal(t, []plugin.Filter{{Disabled: []string{"tempo"}}}, ept.plugin.(*fakePlugin).filters)
+	assert.Empty(t, ept.plugin.(*fakePlugin).archivePaths)
+
+	// The result is still valid for the configuration loader
+	_, err = config.Resolve(configFile)
+	assert.NoError(t, err)
+}
+
+func TestPatchConfig_JSONFile(t *testing.T) {
+	ept, configFile := newTestEndpointWithFile(t, true, "config.json", `{
+  "security": {
+    "encryption_key": "=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"
+  },
+  "plugin": {
+    "path": "./plugins",
+    "archive_paths": ["./plugins-archive"]
+  }
+}
+`)
+	ctx, _ := newTestContext(http.MethodPatch, `{"plugin": {"disabled": ["Tempo"]}}`)
+	require.NoError(t, ept.patchConfig(ctx))
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "security": {"encryption_key": "=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc"},
+  "plugin": {"path": "./plugins", "archive_paths": ["./plugins-archive"], "disabled": ["Tempo"]}
+}`, string(data))
+	// the keys keep their order
+	assert.Less(t, strings.Index(string(data), "security"), strings.Index(string(data), "plugin"))
+}
+
+func TestPatchConfig_RemoveField(t *testing.T) {
+	ept, configFile := newTestEndpoint(t, true)
+	ept.cfg.Plugin.Enabled = []string{"prometheus"}
+	ctx, _ := newTestContext(http.MethodPatch, `{"plugin": {"enabled": null, "disabled": ["tempo"]}}`)
+	require.NoError(t, ept.patchConfig(ctx))
+	assert.Empty(t, ept.cfg.Plugin.Enabled)
+	assert.Equal(t, []string{"tempo"}, ept.cfg.Plugin.Disabled)
+	assert.NotContains(t, readConfigFile(t, configFile)["plugin"], "enabled")
+}
+
+func TestPatchConfig_ArchivePaths(t *testing.T) {
+	ept, configFile := newTestEndpointWithFile(t, true, "config.yaml", `plugin:
+  path: ./plugins
+  archive_path: ./plugins-archive
+`)
+	ctx, _ := newTestContext(http.MethodPatch, `{"plugin": {"archive_paths": ["./plugins-archive", "https://example.com/tempo.tar.gz"]}}`)
+	require.NoError(t, ept.patchConfig(ctx))
+	assert.Equal(t, []string{"./plugins-archive", "https://example.com/tempo.tar.gz"}, ept.cfg.Plugin.ArchivePaths)
+	// a remote archive gets the default download timeout
+	assert.Equal(t, config.DefaultPluginDownloadTimeout, time.Duration(ept.cfg.Plugin.DownloadTimeout))
+	assert.Equal(t, [][]string{ept.cfg.Plugin.ArchivePaths}, ept.plugin.(*fakePlugin).archivePaths)
+	assert.Empty(t, ept.plugin.(*fakePlugin).filters)
+
+	// the deprecated archive_path is dropped, so it isn't added again to archive_paths on restart
+	persisted := readConfigFile(t, configFile)["plugin"].(map[string]any)
+	assert.NotContains(t, persisted, "archive_path")
+	assert.Equal(t, []any{"./plugins-archive", "https://example.com/tempo.tar.gz"}, persisted["archive_paths"])
+}
+
+func TestPatchConfig_NoFileSystemCheck(t *testing.T) {
+	ept, _ := newTestEndpoint(t, true)
+	// The plugin path doesn't exist, it would be rejected if the whole plugin configuration was verified again.
+	ept.cfg.Plugin.Path = common.NonEmptyString(filepath.Join(t.TempDir(), "plugins"))
+	ctx, _ := newTestContext(http.MethodPatch, `{"plugin": {"enabled": ["Prometheus"]}}`)
+	require.NoError(t, ept.patchConfig(ctx))
+	assert.Equal(t, []string{"prometheus"}, ept.cfg.Plugin.Enabled)
+}
+
+func TestPatchConfig_Rejected(t *testing.T) {
+	testSuite := []struct {
+		title string
+		patch string
+		err   error
+	}{
+		{title: "immutable field", patch: `{"security": {"readonly": true}}`, err: apiinterface.BadRequestError},
+		{title: "immutable section", patch: `{"database": {"file": {"folder": "/tmp"}}}`, err: apiinterface.BadRequestError},
+		{title: "field only read at startup", patch: `{"plugin": {"path": "/tmp/plugins"}}`, err: apiinterface.BadRequestError},
+		{title: "removing a parent of mutable fields", patch: `{"security": null}`, err: apiinterface.BadRequestError},
+		{title: "not an object", patch: `["security"]`, err: apiinterface.BadRequestError},
+		{title: "invalid value", patch: `{"security": {"cors": {"enable": "yes"}}}`, err: apiinterface.BadRequestError},
+		{title: "enabled and disabled together", patch: `{"plugin": {"enabled": ["a"], "disabled": ["b"]}}`, err: apiinterface.BadRequestError},
+		{title: "no archive path", patch: `{"plugin": {"archive_paths": null}}`, err: apiinterface.BadRequestError},
+		{title: "invalid archive URL", patch: `{"plugin": {"archive_paths": ["https://"]}}`, err: apiinterface.BadRequestError},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			ept, configFile := newTestEndpoint(t, true)
+			ctx, _ := newTestContext(http.MethodPatch, test.patch)
+			err := ept.patchConfig(ctx)
+			assert.True(t, errors.Is(err, test.err), "unexpected error %v", err)
+			assert.False(t, ept.cfg.Security.Readonly)
+			assert.False(t, ept.cfg.Security.CORS.Enable)
+			data, readErr := os.ReadFile(configFile)
+			require.NoError(t, readErr)
+			assert.Equal(t, testConfigFile, string(data))
+		})
+	}
+}
+
+func TestPatchConfig_Forbidden(t *testing.T) {
+	ept, configFile := newTestEndpoint(t, false)
+	ctx, _ := newTestContext(http.MethodPatch, `{"security": {"cors": {"enable": true}}}`)
+	err := ept.patchConfig(ctx)
+	assert.True(t, errors.Is(err, apiinterface.ForbiddenError))
+	assert.False(t, ept.cfg.Security.CORS.Enable)
+	data, readErr := os.ReadFile(configFile)
+	require.NoError(t, readErr)
+	assert.Equal(t, testConfigFile, string(data))
+}
+
+func TestMergePatch(t *testing.T) {
+	// Examples taken from the appendix A of the RFC 7396
+	testSuite := []struct {
+		target string
+		patch  string
+		result string
+	}{
+		{target: `{"a":"b"}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"b":"c"}`, result: `{"a":"b","b":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"a":null}`, result: `{}`},
+		{target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, result: `{"b":"c"}`},
+		{target: `{"a":["b"]}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+		{target: `{"a":"c"}`, patch: `{"a":["b"]}`, result: `{"a":["b"]}`},
+		{target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, result: `{"a":{"b":"d"}}`},
+		{target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, result: `{"a":[1]}`},
+		{target: `{"e":null}`, patch: `{"a":1}`, result: `{"a":1,"e":null}`},
+		{target: `[1,2]`, patch: `{"a":"b","c":null}`, result: `{"a":"b"}`},
+		{target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, result: `{"a":{"bb":{}}}`},
+	}
+	for _, test := range testSuite {
+		t.Run(test.target+" + "+test.patch, func(t *testing.T) {
+			var target, patch any
+			require.NoError(t, json.Unmarshal([]byte(test.target), &target))
+			require.NoError(t, json.Unmarshal([]byte(test.patch), &patch))
+			result, err := json.Marshal(mergePatch(target, patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, test.result, string(result))
+		})
+	}
+}
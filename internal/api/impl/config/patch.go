@@ -0,0 +1,318 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configendpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"gopkg.in/yaml.v3"
+)
+
+// mutablePaths lists the fields of the configuration that can be updated at runtime, as they are applied to the running
+// server. Any other field requires to edit the configuration file and to restart Perses.
+var mutablePaths = [][]string{
+	{"security", "cors"},
+	{"plugin", "enabled"},
+	{"plugin", "disabled"},
+	{"plugin", "archive_paths"},
+}
+
+// mutableConfig is the subset of the configuration matching mutablePaths.
+type mutableConfig struct {
+	Security mutableSecurity `json:"security"`
+	Plugin   mutablePlugin   `json:"plugin"`
+}
+
+type mutableSecurity struct {
+	CORS config.CORSConfig `json:"cors"`
+}
+
+type mutablePlugin struct {
+	Enabled      []string `json:"enabled,omitempty"`
+	Disabled     []string `json:"disabled,omitempty"`
+	ArchivePaths []string `json:"archive_paths,omitempty"`
+}
+
+// checkMutablePaths returns an error if the patch is modifying a field that is not listed in mutablePaths.
+func checkMutablePaths(patch map[string]any, parent []string) error {
+	for key, value := range patch {
+		path := append(slices.Clone(parent), key)
+		if slices.ContainsFunc(mutablePaths, func(mutablePath []string) bool { return slices.Equal(mutablePath, path) }) {
+			continue
+		}
+		isParent := slices.ContainsFunc(mutablePaths, func(mutablePath []string) bool {
+			return len(mutablePath) > len(path) && slices.Equal(mutablePath[:len(path)], path)
+		})
+		subPatch, isObject := value.(map[string]any)
+		if !isParent || !isObject {
+			return fmt.Errorf("the field %q cannot be updated at runtime", strings.Join(path, "."))
+		}
+		if err := checkMutablePaths(subPatch, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyPatch returns a copy of the configuration with the patch applied.
+// The patch must have been checked with checkMutablePaths beforehand.
+func applyPatch(cfg config.Config, patch map[string]any) (config.Config, error) {
+	current := mutableConfig{
+		Security: mutableSecurity{CORS: cfg.Security.CORS},
+		Plugin: mutablePlugin{
+			Enabled:      cfg.Plugin.Enabled,
+			Disabled:     cfg.Plugin.Disabled,
+			ArchivePaths: cfg.Plugin.ArchivePaths,
+		},
+	}
+	data, err := json.Marshal(current)
+	if err != nil {
+		return cfg, err
+	}
+	var document map[string]any
+	if unmarshalErr := json.Unmarshal(data, &document); unmarshalErr != nil {
+		return cfg, unmarshalErr
+	}
+	data, err = json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return cfg, err
+	}
+	var updated mutableConfig
+	if unmarshalErr := json.Unmarshal(data, &updated); unmarshalErr != nil {
+		return cfg, unmarshalErr
+	}
+
+	newCfg := cfg
+	newCfg.Security.CORS = updated.Security.CORS
+	plugin := cfg.Plugin
+	plugin.Enabled = updated.Plugin.Enabled
+	plugin.Disabled = updated.Plugin.Disabled
+	plugin.ArchivePaths = updated.Plugin.ArchivePaths
+	// Only the patched fields are verified. Verify would also check the rest of the plugin configuration against the
+	// file system, while it has been verified at startup and cannot change.
+	if verifyErr := plugin.VerifyFilter(); verifyErr != nil {
+		return cfg, verifyErr
+	}
+	if len(plugin.ArchivePaths) == 0 {
+		return cfg, fmt.Errorf("the 'archive_paths' attribute can not be empty")
+	}
+	if verifyErr := plugin.VerifyArchivePaths(); verifyErr != nil {
+		return cfg, verifyErr
+	}
+	newCfg.Plugin = plugin
+	return newCfg, nil
+}
+
+// withoutDeprecatedFields returns the patch to write in the configuration file. When the archive paths are patched, the
+// deprecated archive_path is removed from the file too, otherwise it would be added again to the new list on restart.
+func withoutDeprecatedFields(patch map[string]any) map[string]any {
+	pluginPatch, isObject := patch["plugin"].(map[string]any)
+	if !isObject {
+		return patch
+	}
+	if _, ok := pluginPatch["archive_paths"]; !ok {
+		return patch
+	}
+	result := maps.Clone(patch)
+	pluginPatch = maps.Clone(pluginPatch)
+	pluginPatch["archive_path"] = nil
+	result["plugin"] = pluginPatch
+	return result
+}
+
+// mergePatch applies the patch on the target following the JSON Merge Patch algorithm described in RFC 7396.
+// The target is modified in place when it is an object.
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// persistPatch applies the patch on the configuration file, touching only the keys of the patch.
+// The rest of the file, i.e. the comments, the order of the keys, the secrets and the values not coming from the file,
+// is kept as it is. A JSON file is written back as JSON.
+func persistPatch(configFile string, patch map[string]any) error {
+	info, err := os.Stat(configFile)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(configFile) //nolint: gosec
+	if err != nil {
+		return err
+	}
+	var document yaml.Node
+	if unmarshalErr := yaml.Unmarshal(data, &document); unmarshalErr != nil {
+		return unmarshalErr
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		// empty file
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if patchErr := mergePatchNode(document.Content[0], patch); patchErr != nil {
+		return patchErr
+	}
+	if strings.EqualFold(filepath.Ext(configFile), ".json") {
+		data, err = encodeJSON(document.Content[0])
+	} else {
+		data, err = encodeYAML(&document)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configFile, data, info.Mode().Perm())
+}
+
+// mergePatchNode applies the patch on the YAML node following the JSON Merge Patch algorithm described in RFC 7396,
+// like mergePatch. The nodes of the keys that are not patched are left untouched, comments included.
+func mergePatchNode(node *yaml.Node, patch map[string]any) error {
+	if node.Kind != yaml.MappingNode {
+		*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: node.HeadComment, LineComment: node.LineComment}
+	}
+	keys := slices.Sorted(maps.Keys(patch))
+	for _, key := range keys {
+		value := patch[key]
+		index := mappingValueIndex(node, key)
+		if value == nil {
+			if index >= 0 {
+				node.Content = slices.Delete(node.Content, index-1, index+1)
+			}
+			continue
+		}
+		if index < 0 {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &yaml.Node{})
+			index = len(node.Content) - 1
+		}
+		valueNode := node.Content[index]
+		if subPatch, isObject := value.(map[string]any); isObject {
+			if err := mergePatchNode(valueNode, subPatch); err != nil {
+				return err
+			}
+			continue
+		}
+		newNode := &yaml.Node{}
+		if err := newNode.Encode(value); err != nil {
+			return err
+		}
+		newNode.HeadComment = valueNode.HeadComment
+		newNode.LineComment = valueNode.LineComment
+		newNode.FootComment = valueNode.FootComment
+		node.Content[index] = newNode
+	}
+	return nil
+}
+
+// mappingValueIndex returns the index of the node holding the value of the key in the content of the mapping node,
+// or -1 when the key is not found. The content of a mapping node alternates the keys and their values.
+func mappingValueIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func encodeYAML(document *yaml.Node) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeJSON writes the YAML node as an indented JSON document, keeping the order of the keys.
+func encodeJSON(node *yaml.Node) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writeJSONNode(&buffer, node); err != nil {
+		return nil, err
+	}
+	var result bytes.Buffer
+	if err := json.Indent(&result, buffer.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	result.WriteByte('\n')
+	return result.Bytes(), nil
+}
+
+func writeJSONNode(buffer *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeJSONNode(buffer, node.Alias)
+	case yaml.MappingNode:
+		buffer.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buffer.Write(key)
+			buffer.WriteByte(':')
+			if err := writeJSONNode(buffer, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	case yaml.SequenceNode:
+		buffer.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSONNode(buffer, item); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buffer.Write(data)
+	}
+	return nil
+}
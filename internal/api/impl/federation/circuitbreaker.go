@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federation
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops sending requests to a peer once it failed failureThreshold times in a row.
+// After openDuration, a single request is let through: the breaker is closed again if it succeeds, or re-opened if it
+// fails.
+type circuitBreaker struct {
+	mutex               sync.Mutex
+	failureThreshold    int
+	openDuration        time.Duration
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpen            bool
+	now                 func() time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		now:              time.Now,
+	}
+}
+
+// allow returns true if a request can be sent to the peer.
+func (c *circuitBreaker) allow() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.consecutiveFailures < c.failureThreshold {
+		return true
+	}
+	if c.halfOpen || c.now().Sub(c.openedAt) < c.openDuration {
+		return false
+	}
+	// The breaker is half-open: only the current request is let through to probe the peer.
+	c.halfOpen = true
+	return true
+}
+
+func (c *circuitBreaker) success() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.consecutiveFailures = 0
+	c.halfOpen = false
+}
+
+func (c *circuitBreaker) failure() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.consecutiveFailures++
+	c.halfOpen = false
+	if c.consecutiveFailures >= c.failureThreshold {
+		c.openedAt = c.now()
+	}
+}
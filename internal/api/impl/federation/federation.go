@@ -0,0 +1,130 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/common/async"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/httpclient"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+type peer struct {
+	config.PeerConfig
+	breaker *circuitBreaker
+}
+
+type endpoint struct {
+	peers  []*peer
+	client *http.Client
+}
+
+func New(cfg config.Federation) route.Endpoint {
+	peers := make([]*peer, 0, len(cfg.Peers))
+	for _, p := range cfg.Peers {
+		peers = append(peers, &peer{
+			PeerConfig: p,
+			breaker:    newCircuitBreaker(cfg.FailureThreshold, time.Duration(cfg.OpenDuration)),
+		})
+	}
+	return &endpoint{
+		peers:  peers,
+		client: httpclient.NewHTTPClient(httpclient.WithTimeout(time.Duration(cfg.Timeout))),
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.Group("/federation").GET("/dashboards", e.listDashboards, false)
+}
+
+// listDashboards returns the dashboards of every peer, with their project prefixed by the name of the peer.
+// The query parameters are forwarded to the peers. A peer that fails to answer is skipped, so the result only contains
+// the dashboards of the available peers.
+func (e *endpoint) listDashboards(ctx echo.Context) error {
+	requests := make([]async.Future[[]map[string]any], 0, len(e.peers))
+	for _, p := range e.peers {
+		requests = append(requests, async.Async(func() ([]map[string]any, error) {
+			return e.listPeerDashboards(ctx.Request().Context(), p, ctx.Request().URL.RawQuery)
+		}))
+	}
+	result := []map[string]any{}
+	for i, request := range requests {
+		dashboards, err := request.Await()
+		if err != nil {
+			logrus.WithError(err).Warnf("unable to list the dashboards of the federation peer %q", e.peers[i].Name)
+			continue
+		}
+		result = append(result, dashboards...)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) listPeerDashboards(ctx context.Context, p *peer, rawQuery string) ([]map[string]any, error) {
+	if !p.breaker.allow() {
+		return nil, fmt.Errorf("the peer failed too many times, it is not requested for now")
+	}
+	dashboards, err := e.requestPeer(ctx, p, "/api/v1/dashboards", rawQuery)
+	if err != nil {
+		// A request canceled by the client doesn't tell anything about the health of the peer.
+		if ctx.Err() == nil {
+			p.breaker.failure()
+		}
+		return nil, err
+	}
+	p.breaker.success()
+	for _, dashboard := range dashboards {
+		metadata, ok := dashboard["metadata"].(map[string]any)
+		if !ok {
+			continue
+		}
+		metadata["project"] = fmt.Sprintf("%s/%v", p.Name, metadata["project"])
+	}
+	return dashboards, nil
+}
+
+func (e *endpoint) requestPeer(ctx context.Context, p *peer, path string, rawQuery string) ([]map[string]any, error) {
+	url := p.URL + path
+	if len(rawQuery) > 0 {
+		url += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+	if len(p.Token) > 0 {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+string(p.Token))
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	var result []map[string]any
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&result); decodeErr != nil {
+		return nil, fmt.Errorf("unable to decode the response: %w", decodeErr)
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockPeer(t *testing.T, token string, projects ...string) (*httptest.Server, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/api/v1/dashboards" || r.Header.Get(echo.HeaderAuthorization) != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		dashboards := make([]map[string]any, 0, len(projects))
+		for _, project := range projects {
+			dashboards = append(dashboards, map[string]any{
+				"kind":     "Dashboard",
+				"metadata": map[string]any{"name": "overview", "project": project},
+			})
+		}
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(dashboards))
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+func listDashboards(t *testing.T, e *endpoint) []map[string]any {
+	req := httptest.NewRequest(http.MethodGet, "/api/federation/dashboards", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, e.listDashboards(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var result []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return result
+}
+
+func projectsOf(dashboards []map[string]any) []string {
+	projects := make([]string, 0, len(dashboards))
+	for _, dashboard := range dashboards {
+		projects = append(projects, dashboard["metadata"].(map[string]any)["project"].(string))
+	}
+	return projects
+}
+
+func newTestFederation(peers ...config.PeerConfig) config.Federation {
+	cfg := config.Federation{Peers: peers, OpenDuration: common.Duration(time.Hour)}
+	if err := cfg.Verify(); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func TestListDashboards(t *testing.T) {
+	east, _ := newMockPeer(t, "east-token", "perses", "monitoring")
+	west, _ := newMockPeer(t, "west-token", "perses")
+	cfg := newTestFederation(
+		config.PeerConfig{Name: "east", URL: east.URL, Token: secret.Hidden("east-token")},
+		config.PeerConfig{Name: "west", URL: west.URL, Token: secret.Hidden("west-token")},
+	)
+	e := New(cfg).(*endpoint)
+	assert.Equal(t, []string{"east/perses", "east/monitoring", "west/perses"}, projectsOf(listDashboards(t, e)))
+}
+
+func TestListDashboardsSkipsFailingPeer(t *testing.T) {
+	healthy, _ := newMockPeer(t, "token", "perses")
+	failing, failingCalls := newMockPeer(t, "another-token", "perses")
+	cfg := newTestFederation(
+		config.PeerConfig{Name: "healthy", URL: healthy.URL, Token: secret.Hidden("token")},
+		config.PeerConfig{Name: "failing", URL: failing.URL, Token: secret.Hidden("wrong-token")},
+	)
+	e := New(cfg).(*endpoint)
+	for i := 0; i < config.DefaultFederationFailureThreshold+2; i++ {
+		assert.Equal(t, []string{"healthy/perses"}, projectsOf(listDashboards(t, e)))
+	}
+	// Once the breaker is open, the failing peer is no longer requested.
+	assert.Equal(t, int32(config.DefaultFederationFailureThreshold), failingCalls.Load())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := newCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	assert.True(t, breaker.allow())
+	breaker.failure()
+	assert.True(t, breaker.allow())
+	breaker.failure()
+	assert.False(t, breaker.allow())
+
+	// After the open duration, a single request is let through.
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.allow())
+	assert.False(t, breaker.allow())
+	breaker.failure()
+	assert.False(t, breaker.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.allow())
+	breaker.success()
+	assert.True(t, breaker.allow())
+	assert.True(t, breaker.allow())
+}
@@ -0,0 +1,49 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginupdate
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/internal/api/route"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type endpoint struct {
+	checker plugin.UpdateChecker
+	authz   authorization.Authorization
+}
+
+func New(checker plugin.UpdateChecker, authz authorization.Authorization) route.Endpoint {
+	return &endpoint{
+		checker: checker,
+		authz:   authz,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.Group("/admin/plugins").GET("/updates", e.listUpdates, false)
+}
+
+func (e *endpoint) listUpdates(ctx echo.Context) error {
+	if !e.authz.HasPermission(ctx, role.WildcardAction, v1.WildcardProject, role.WildcardScope) {
+		return apiinterface.HandleForbiddenError("only administrators can list the plugin updates")
+	}
+	return ctx.JSON(http.StatusOK, e.checker.Updates())
+}
@@ -0,0 +1,297 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	dashboardModel "github.com/perses/perses/pkg/model/api/v1/dashboard"
+	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	datasourceSpec "github.com/perses/spec/go/datasource"
+	"github.com/sirupsen/logrus"
+)
+
+// annotationQueryPath is the path of the Prometheus API running the annotation queries.
+// The events are found in the series over the time range, so they are range queries.
+var annotationQueryPath = prometheusQueryPaths[dashboardModel.QueryHintRange]
+
+const (
+	pathAnnotation  = "annotations"
+	paramAnnotation = "annotation"
+	// maxAnnotationPoints is the number of points per series requested at most to the datasource.
+	maxAnnotationPoints = 1000
+)
+
+type annotationEndpoint struct {
+	*endpoint
+}
+
+// NewAnnotationEndpoint returns the endpoint running the annotation queries of the dashboards.
+// The queries are sent to the datasources through the same proxy as the panels.
+func NewAnnotationEndpoint(cfg config.DatasourceConfig, apiCfg config.API, dashboardDAO dashboard.DAO, secretDAO secret.DAO, globalSecretDAO globalsecret.DAO,
+	dtsDAO datasource.DAO, globalDtsDAO globaldatasource.DAO, crypto crypto.Crypto, authz authorization.Authorization, transports *TransportPool) route.Endpoint {
+	return &annotationEndpoint{
+		endpoint: newEndpoint(cfg, apiCfg, dashboardDAO, secretDAO, globalSecretDAO, dtsDAO, globalDtsDAO, crypto, authz, transports),
+	}
+}
+
+func (e *annotationEndpoint) CollectRoutes(g *route.Group) {
+	g.POST(fmt.Sprintf("/%s/:%s/%s/:%s/%s/:%s/query", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, pathAnnotation, paramAnnotation), e.queryAnnotation, false)
+}
+
+func (e *annotationEndpoint) queryAnnotation(ctx echo.Context) error {
+	projectName := ctx.Param(utils.ParamProject)
+	body := &api.AnnotationQueryRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return err
+	}
+	if err := e.checkPermission(ctx, projectName, role.DashboardScope, role.ReadAction); err != nil {
+		return err
+	}
+	annotation, err := e.getDashboardAnnotation(projectName, ctx.Param(utils.ParamDashboard), ctx.Param(paramAnnotation))
+	if err != nil {
+		return err
+	}
+	pr, err := e.annotationProxy(ctx, projectName, annotation.Datasource)
+	if err != nil {
+		return err
+	}
+	step := max(body.End.Sub(body.Start)/maxAnnotationPoints, time.Second)
+	result, err := runAnnotationQuery(ctx, pr, annotation, body, step)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, annotationEvents(annotation.Name, result, step))
+}
+
+// datasourceSelector references the datasource of an annotation plugin.
+type datasourceSelector struct {
+	// Kind is the plugin kind of the datasource, like `PrometheusDatasource`. It is checked when set.
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
+// annotationQuery is the query held by the plugin of an annotation of the dashboard.
+type annotationQuery struct {
+	Name       string
+	Datasource datasourceSelector
+	Query      string
+}
+
+// annotationPluginSpec is the part of the spec of the annotation plugins read to run their query.
+type annotationPluginSpec struct {
+	Datasource *datasourceSelector `json:"datasource"`
+	Query      string              `json:"query"`
+}
+
+// getDashboardAnnotation returns the query of the annotation of the dashboard with the given name.
+func (e *annotationEndpoint) getDashboardAnnotation(projectName string, dashboardName string, name string) (annotationQuery, error) {
+	db, err := e.dashboard.Get(projectName, dashboardName)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return annotationQuery{}, apiinterface.HandleNotFoundError(fmt.Sprintf("unable to find the dashboard %q", dashboardName))
+		}
+		logrus.WithError(err).Errorf("unable to find the dashboard %q, something wrong with the database", dashboardName)
+		return annotationQuery{}, apiinterface.InternalError
+	}
+	for _, annotation := range db.Spec.Annotations {
+		if annotation.Display.Name != name {
+			continue
+		}
+		data, marshalErr := json.Marshal(annotation.Plugin.Spec)
+		if marshalErr != nil {
+			return annotationQuery{}, apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the spec of the annotation %q: %s", name, marshalErr))
+		}
+		spec := &annotationPluginSpec{}
+		if jsonErr := json.Unmarshal(data, spec); jsonErr != nil {
+			return annotationQuery{}, apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the spec of the annotation %q: %s", name, jsonErr))
+		}
+		if spec.Datasource == nil || len(spec.Datasource.Name) == 0 || len(spec.Query) == 0 {
+			return annotationQuery{}, apiinterface.HandleBadRequestError(fmt.Sprintf("the annotation %q of kind %q doesn't define a named datasource and a query", name, annotation.Plugin.Kind))
+		}
+		return annotationQuery{Name: name, Datasource: *spec.Datasource, Query: spec.Query}, nil
+	}
+	return annotationQuery{}, apiinterface.HandleNotFoundError(fmt.Sprintf("unable to find the annotation %q in the dashboard %q", name, dashboardName))
+}
+
+// annotationProxy returns the proxy to the datasource of the annotation query.
+// The datasource is looked up in the project first, then in the global datasources.
+func (e *annotationEndpoint) annotationProxy(ctx echo.Context, projectName string, selector datasourceSelector) (proxy, error) {
+	var spec datasourceSpec.Spec
+	var retrieveSecret func(name string) (*v1.SecretSpec, error)
+	err := apiinterface.HandleNotFoundError(fmt.Sprintf("unable to find the datasource %q of the annotation query", selector.Name))
+	if !e.cfg.Project.Disable {
+		if permErr := e.checkPermission(ctx, projectName, role.DatasourceScope, role.ReadAction); permErr != nil {
+			return nil, permErr
+		}
+		spec, err = e.getProjectDatasource(projectName, selector.Name)
+		retrieveSecret = func(name string) (*v1.SecretSpec, error) {
+			return e.getProjectSecret(projectName, selector.Name, name)
+		}
+	}
+	if errors.Is(err, apiinterface.NotFoundError) && !e.cfg.Global.Disable {
+		if permErr := e.checkPermission(ctx, v1.WildcardProject, role.GlobalDatasourceScope, role.ReadAction); permErr != nil {
+			return nil, permErr
+		}
+		var dts *v1.GlobalDatasource
+		if dts, err = e.getGlobalDatasource(selector.Name); err == nil {
+			spec = dts.Spec
+			projectName = ""
+		}
+		retrieveSecret = func(name string) (*v1.SecretSpec, error) {
+			return e.getGlobalSecret(selector.Name, name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(selector.Kind) > 0 && selector.Kind != spec.Plugin.Kind {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("the datasource %q is of kind %q, not %q", selector.Name, spec.Plugin.Kind, selector.Kind))
+	}
+	if !datasourcev1.IsPrometheusCompatible(spec.Plugin.Kind) {
+		return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("the datasource %q is of kind %q, annotation queries are only supported by the Prometheus compatible datasources", selector.Name, spec.Plugin.Kind))
+	}
+	return newProxy(selector.Name, projectName, spec, annotationQueryPath, e.crypto, e.dedup, e.apiCfg.GetMaxQueryTimeRange(projectName, selector.Name), e.transports, nil, e.tokens, nil, e.clientCert, retrieveSecret)
+}
+
+// annotationSeries is a series of the matrix returned by the Prometheus range queries.
+type annotationSeries struct {
+	Metric map[string]string `json:"metric"`
+	// Values are the points of the series, as pairs of a timestamp in seconds and a value.
+	Values [][2]any `json:"values"`
+}
+
+type annotationQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string             `json:"resultType"`
+		Result     []annotationSeries `json:"result"`
+	} `json:"data"`
+}
+
+// responseBuffer is the http.ResponseWriter keeping the response of the datasource, so it can be read before answering.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseBuffer) Header() http.Header {
+	return r.header
+}
+
+func (r *responseBuffer) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(data)
+}
+
+func (r *responseBuffer) WriteHeader(status int) {
+	r.status = status
+}
+
+// runAnnotationQuery sends the range query of the annotation to the datasource and returns the series it answered.
+func runAnnotationQuery(ctx echo.Context, pr proxy, annotation annotationQuery, body *api.AnnotationQueryRequest, step time.Duration) ([]annotationSeries, error) {
+	params := url.Values{
+		"query": {annotation.Query},
+		"start": {formatQueryTime(body.Start)},
+		"end":   {formatQueryTime(body.End)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	req, err := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, annotationQueryPath+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &responseBuffer{header: make(http.Header)}
+	if serveErr := pr.serve(ctx.Echo().NewContext(req, res)); serveErr != nil {
+		return nil, serveErr
+	}
+	if res.status != http.StatusOK {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("the datasource answered the annotation query %q with the status %d", annotation.Name, res.status))
+	}
+	response := &annotationQueryResponse{}
+	if jsonErr := json.Unmarshal(res.body.Bytes(), response); jsonErr != nil || response.Data.ResultType != "matrix" {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("the datasource didn't answer the annotation query %q with a matrix", annotation.Name))
+	}
+	return response.Data.Result, nil
+}
+
+func formatQueryTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
+
+// annotationEvents turns the series into events. A series becomes an event every time it starts again,
+// i.e. at its first point and at every point following a gap longer than the step.
+// The events are labelled with the labels of their series, or with the name of the annotation query when there are none.
+func annotationEvents(name string, series []annotationSeries, step time.Duration) []api.AnnotationEvent {
+	events := []api.AnnotationEvent{}
+	for _, s := range series {
+		label := seriesLabel(s.Metric)
+		if len(label) == 0 {
+			label = name
+		}
+		previous := math.Inf(-1)
+		for _, value := range s.Values {
+			timestamp, ok := value[0].(float64)
+			if !ok {
+				continue
+			}
+			if timestamp-previous > step.Seconds() {
+				events = append(events, api.AnnotationEvent{Timestamp: int64(math.Round(timestamp * 1000)), Label: label})
+			}
+			previous = timestamp
+		}
+	}
+	slices.SortStableFunc(events, func(a, b api.AnnotationEvent) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+	return events
+}
+
+func seriesLabel(metric map[string]string) string {
+	labels := make([]string, 0, len(metric))
+	for k, v := range metric {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	}
+	slices.Sort(labels)
+	return strings.Join(labels, ", ")
+}
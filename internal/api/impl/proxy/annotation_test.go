@@ -0,0 +1,179 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDatasourceDAO struct {
+	datasource.DAO
+	datasources map[string]*v1.Datasource
+}
+
+func (d *fakeDatasourceDAO) Get(_ string, name string) (*v1.Datasource, error) {
+	if dts, ok := d.datasources[name]; ok {
+		return dts, nil
+	}
+	return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+}
+
+type fakeDashboardDAO struct {
+	dashboard.DAO
+	dashboards map[string]*v1.Dashboard
+}
+
+func (d *fakeDashboardDAO) Get(_ string, name string) (*v1.Dashboard, error) {
+	if db, ok := d.dashboards[name]; ok {
+		return db, nil
+	}
+	return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+}
+
+type fakeGlobalDatasourceDAO struct {
+	globaldatasource.DAO
+	datasources map[string]*v1.GlobalDatasource
+}
+
+func (d *fakeGlobalDatasourceDAO) Get(name string) (*v1.GlobalDatasource, error) {
+	if dts, ok := d.datasources[name]; ok {
+		return dts, nil
+	}
+	return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+}
+
+type disabledAuthorization struct {
+	authorization.Authorization
+}
+
+func (d *disabledAuthorization) IsEnabled() bool {
+	return false
+}
+
+func TestAnnotationEvents(t *testing.T) {
+	series := []annotationSeries{
+		{
+			Metric: map[string]string{"version": "v2", "app": "api"},
+			// the deployment lasts two steps, stops, then starts again
+			Values: [][2]any{{float64(1700000000), "1"}, {float64(1700000060), "1"}, {float64(1700000300), "1"}},
+		},
+		{
+			Values: [][2]any{{float64(1700000030.5), "1"}},
+		},
+	}
+	assert.Equal(t, []api.AnnotationEvent{
+		{Timestamp: 1700000000000, Label: "app=api, version=v2"},
+		{Timestamp: 1700000030500, Label: "deployments"},
+		{Timestamp: 1700000300000, Label: "app=api, version=v2"},
+	}, annotationEvents("deployments", series, time.Minute))
+}
+
+func TestQueryAnnotation(t *testing.T) {
+	// mock of the Prometheus range query API, answering a deployment running twice in the time range.
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, "deployment_running", r.URL.Query().Get("query"))
+		assert.Equal(t, "1700000000", r.URL.Query().Get("start"))
+		assert.Equal(t, "1700003600", r.URL.Query().Get("end"))
+		assert.Equal(t, "3.6", r.URL.Query().Get("step"))
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		_, err := w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"app":"api"},"values":[[1700000000,"1"],[1700000003,"1"],[1700001000,"1"]]}]}}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(prometheus.Close)
+
+	dts := &v1.Datasource{Spec: newPrometheusCompatibleDatasourceSpec(t, datasourcev1.PrometheusDatasourceKind, prometheus.URL, datasourcev1.PrometheusQueryExtensions{})}
+	globalDts := &v1.GlobalDatasource{Spec: newPrometheusCompatibleDatasourceSpec(t, datasourcev1.ThanosDatasourceKind, prometheus.URL, datasourcev1.PrometheusQueryExtensions{})}
+	globalDts.Metadata.Name = "thanos"
+	db := &v1.Dashboard{}
+	require.NoError(t, json.Unmarshal([]byte(`{"panels": {}, "layouts": [], "duration": "1h", "annotations": [
+		{"display": {"name": "deployments"}, "plugin": {"kind": "PrometheusAnnotation", "spec": {"datasource": {"kind": "PrometheusDatasource", "name": "prometheus"}, "query": "deployment_running"}}},
+		{"display": {"name": "releases"}, "plugin": {"kind": "PrometheusAnnotation", "spec": {"datasource": {"name": "thanos"}, "query": "deployment_running"}}},
+		{"display": {"name": "incidents"}, "plugin": {"kind": "PrometheusAnnotation", "spec": {"datasource": {"name": "loki"}, "query": "ALERTS"}}},
+		{"display": {"name": "traces"}, "plugin": {"kind": "PrometheusAnnotation", "spec": {"datasource": {"kind": "TempoDatasource", "name": "prometheus"}, "query": "up"}}},
+		{"display": {"name": "empty"}, "plugin": {"kind": "PrometheusAnnotation", "spec": {}}}
+	]}`), &db.Spec))
+	e := &annotationEndpoint{endpoint: &endpoint{
+		dashboard: &fakeDashboardDAO{dashboards: map[string]*v1.Dashboard{"overview": db}},
+		dts:       &fakeDatasourceDAO{datasources: map[string]*v1.Datasource{"prometheus": dts}},
+		globalDTS: &fakeGlobalDatasourceDAO{datasources: map[string]*v1.GlobalDatasource{"thanos": globalDts}},
+		authz:     &disabledAuthorization{},
+	}}
+
+	query := func(dashboardName string, annotation string, body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/projects/perses/dashboards/"+dashboardName+"/annotations/"+annotation+"/query", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+		ctx.SetParamNames(utils.ParamProject, utils.ParamDashboard, paramAnnotation)
+		ctx.SetParamValues("perses", dashboardName, annotation)
+		return rec, e.queryAnnotation(ctx)
+	}
+	const timeRange = `{"start":"2023-11-14T22:13:20Z","end":"2023-11-14T23:13:20Z"}`
+	expected := `[{"timestamp":1700000000000,"label":"app=api"},{"timestamp":1700001000000,"label":"app=api"}]`
+
+	t.Run("project datasource", func(t *testing.T) {
+		rec, err := query("overview", "deployments", timeRange)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, expected, rec.Body.String())
+	})
+	t.Run("global datasource", func(t *testing.T) {
+		rec, err := query("overview", "releases", timeRange)
+		require.NoError(t, err)
+		assert.JSONEq(t, expected, rec.Body.String())
+	})
+	t.Run("unknown datasource", func(t *testing.T) {
+		_, err := query("overview", "incidents", timeRange)
+		assert.ErrorContains(t, err, `unable to forward the request to the datasource "loki"`)
+	})
+	t.Run("datasource of another kind", func(t *testing.T) {
+		_, err := query("overview", "traces", timeRange)
+		assert.ErrorContains(t, err, `the datasource "prometheus" is of kind "PrometheusDatasource", not "TempoDatasource"`)
+	})
+	t.Run("annotation without query", func(t *testing.T) {
+		_, err := query("overview", "empty", timeRange)
+		assert.ErrorContains(t, err, `the annotation "empty" of kind "PrometheusAnnotation" doesn't define a named datasource and a query`)
+	})
+	t.Run("unknown annotation", func(t *testing.T) {
+		_, err := query("overview", "outages", timeRange)
+		assert.ErrorContains(t, err, `unable to find the annotation "outages" in the dashboard "overview"`)
+	})
+	t.Run("unknown dashboard", func(t *testing.T) {
+		_, err := query("home", "deployments", timeRange)
+		assert.ErrorContains(t, err, `unable to find the dashboard "home"`)
+	})
+	t.Run("invalid time range", func(t *testing.T) {
+		_, err := query("overview", "deployments", `{"start":"2023-11-14T23:13:20Z","end":"2023-11-14T22:13:20Z"}`)
+		assert.Error(t, err)
+	})
+}
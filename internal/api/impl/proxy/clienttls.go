@@ -0,0 +1,104 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+// clientCertificate is the certificate the HTTP proxy presents to the datasources requiring mutual TLS.
+// The key pair is read again from the disk whenever one of its files is modified, so a rotated certificate is used by
+// the next TLS handshake without restarting Perses, including on the connections of the pooled transports.
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	mutex    sync.Mutex
+	cert     *tls.Certificate
+	// certModTime and keyModTime are the modification times of the files the current certificate was loaded from.
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newClientCertificate(cfg *config.DatasourceClientTLS) *clientCertificate {
+	if cfg == nil {
+		return nil
+	}
+	return &clientCertificate{
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		caFile:   cfg.CAFile,
+	}
+}
+
+// apply configures the TLS config to present the client certificate, unless it already has its own, and to verify the
+// datasource with the CA file, unless it already has its own CA.
+func (c *clientCertificate) apply(tlsConfig *tls.Config) error {
+	if len(c.caFile) > 0 && tlsConfig.RootCAs == nil {
+		data, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return fmt.Errorf("unable to read the CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no CA certificate found in %q", c.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(tlsConfig.Certificates) > 0 || tlsConfig.GetClientCertificate != nil {
+		return nil
+	}
+	// load the key pair right away, so a wrong configuration is reported before any request is sent
+	if _, err := c.get(nil); err != nil {
+		return err
+	}
+	tlsConfig.GetClientCertificate = c.get
+	return nil
+}
+
+// get returns the client certificate, loaded again from the disk when the files have changed since the last load.
+// If the new key pair cannot be loaded, e.g. because the certificate has been rotated but not the key yet, the previous
+// certificate is kept.
+func (c *clientCertificate) get(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	certInfo, certErr := os.Stat(c.certFile)
+	keyInfo, keyErr := os.Stat(c.keyFile)
+	if certErr == nil && keyErr == nil && c.cert != nil &&
+		certInfo.ModTime().Equal(c.certModTime) && keyInfo.ModTime().Equal(c.keyModTime) {
+		return c.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		if c.cert != nil {
+			logrus.WithError(err).Warning("unable to reload the datasource client certificate, the previous one is kept")
+			return c.cert, nil
+		}
+		return nil, fmt.Errorf("unable to load the datasource client certificate: %w", err)
+	}
+	c.cert = &cert
+	if certErr == nil && keyErr == nil {
+		c.certModTime = certInfo.ModTime()
+		c.keyModTime = keyInfo.ModTime()
+	}
+	return c.cert, nil
+}
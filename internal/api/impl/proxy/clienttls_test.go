@@ -0,0 +1,147 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// writeClientCertificate writes a key pair signed by the CA with the given common name, and sets the modification time
+// of the files to modTime.
+func (ca *testCA) writeClientCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestClientCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	ca.writeClientCertificate(t, certFile, keyFile, "perses", time.Now().Add(-time.Minute))
+
+	get := func(transport *http.Transport) (string, error) {
+		defer transport.CloseIdleConnections()
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// without the client certificate, the handshake is refused
+	withoutCert := newTransport()
+	withoutCert.TLSClientConfig = &tls.Config{RootCAs: x509.NewCertPool(), MinVersion: tls.VersionTLS12}
+	withoutCert.TLSClientConfig.RootCAs.AddCert(server.Certificate())
+	_, err := get(withoutCert)
+	assert.Error(t, err)
+
+	h := &httpProxy{clientCert: newClientCertificate(&config.DatasourceClientTLS{CertFile: certFile, KeyFile: keyFile, CAFile: caFile})}
+	tlsConfig, err := h.prepareTLSConfig()
+	require.NoError(t, err)
+	transport := newTransport()
+	transport.TLSClientConfig = tlsConfig
+	name, err := get(transport)
+	require.NoError(t, err)
+	assert.Equal(t, "perses", name)
+
+	// the rotated certificate is used by the next connection of the same transport
+	ca.writeClientCertificate(t, certFile, keyFile, "perses-rotated", time.Now())
+	name, err = get(transport)
+	require.NoError(t, err)
+	assert.Equal(t, "perses-rotated", name)
+
+	// a key pair that can't be loaded doesn't replace the previous certificate
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0600))
+	name, err = get(transport)
+	require.NoError(t, err)
+	assert.Equal(t, "perses-rotated", name)
+}
+
+func TestClientCertificate_InvalidFiles(t *testing.T) {
+	h := &httpProxy{clientCert: newClientCertificate(&config.DatasourceClientTLS{CertFile: "missing.crt", KeyFile: "missing.key"})}
+	_, err := h.prepareTLSConfig()
+	assert.ErrorContains(t, err, "unable to load the datasource client certificate")
+}
@@ -29,7 +29,7 @@ import (
 func (e *endpoint) proxyGlobalDatasource(ctx echo.Context, datasourceName string, spec datasource.Spec) error {
 	path := ctx.Param("*")
 
-	pr, err := newProxy(datasourceName, "", spec, path, e.crypto, func(name string) (*v1.SecretSpec, error) {
+	pr, err := newProxy(datasourceName, "", spec, path, e.crypto, e.dedup, e.apiCfg.GetMaxQueryTimeRange("", datasourceName), e.transports, e.exemplars, e.tokens, e.cfg.Transforms, e.clientCert, func(name string) (*v1.SecretSpec, error) {
 		return e.getGlobalSecret(datasourceName, name)
 	})
 	if err != nil {
@@ -29,7 +29,7 @@ import (
 func (e *endpoint) proxyDashboardDatasource(ctx echo.Context, projectName, dtsName string, spec datasource.Spec) error {
 	path := ctx.Param("*")
 
-	pr, err := newProxy(dtsName, projectName, spec, path, e.crypto, func(name string) (*v1.SecretSpec, error) {
+	pr, err := newProxy(dtsName, projectName, spec, path, e.crypto, e.dedup, e.apiCfg.GetMaxQueryTimeRange(projectName, dtsName), e.transports, e.exemplars, e.tokens, e.cfg.Transforms, e.clientCert, func(name string) (*v1.SecretSpec, error) {
 		return e.getProjectSecret(projectName, dtsName, name)
 	})
 	if err != nil {
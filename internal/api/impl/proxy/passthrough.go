@@ -0,0 +1,135 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+var _ = json.Unmarshaler(&passthroughBody{})
+
+// passthroughBody describes the call to make to the datasource through the passthrough endpoint.
+type passthroughBody struct {
+	Path   string     `json:"path"`
+	Method string     `json:"method,omitempty"`
+	Params url.Values `json:"params,omitempty"`
+}
+
+func (p *passthroughBody) UnmarshalJSON(data []byte) error {
+	type Alias passthroughBody
+	aux := Alias{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Path) == 0 || aux.Path[0] != '/' {
+		return fmt.Errorf("the field 'path' must be an absolute path")
+	}
+	// cleaning the path prevents a path like /api/v1/../../admin from escaping the allowed patterns
+	aux.Path = path.Clean(aux.Path)
+
+	if aux.Method == "" {
+		aux.Method = http.MethodGet
+	}
+
+	if aux.Method != http.MethodGet && aux.Method != http.MethodPost && aux.Method != http.MethodPut && aux.Method != http.MethodDelete {
+		return fmt.Errorf("invalid method %q", aux.Method)
+	}
+
+	*p = passthroughBody(aux)
+	return nil
+}
+
+type passthroughEndpoint struct {
+	*endpoint
+}
+
+// NewPassthroughEndpoint returns the endpoint letting the plugins call any path of a project datasource allowed by
+// the configuration, with the same authentication, caching and limits as the datasource proxy.
+func NewPassthroughEndpoint(cfg config.DatasourceConfig, apiCfg config.API, dashboardDAO dashboard.DAO, secretDAO secret.DAO, globalSecretDAO globalsecret.DAO,
+	dtsDAO datasource.DAO, globalDtsDAO globaldatasource.DAO, crypto crypto.Crypto, authz authorization.Authorization, transports *TransportPool) route.Endpoint {
+	return &passthroughEndpoint{
+		endpoint: newEndpoint(cfg, apiCfg, dashboardDAO, secretDAO, globalSecretDAO, dtsDAO, globalDtsDAO, crypto, authz, transports),
+	}
+}
+
+func (e *passthroughEndpoint) CollectRoutes(g *route.Group) {
+	if e.cfg.Project.Disable {
+		return
+	}
+	g.POST(fmt.Sprintf("/%s/:%s/%s/:%s/proxy", utils.PathProject, utils.ParamProject, utils.PathDatasource, utils.ParamName), e.passthrough, false)
+}
+
+func (e *passthroughEndpoint) passthrough(ctx echo.Context) error {
+	projectName := ctx.Param(utils.ParamProject)
+	body := &passthroughBody{}
+	if err := ctx.Bind(body); err != nil {
+		return err
+	}
+
+	if !e.isPathAllowed(body.Path) {
+		return apiinterface.HandleForbiddenError(fmt.Sprintf("the path %q is not allowed to be called through the datasource proxy", body.Path))
+	}
+
+	if err := e.checkPermission(ctx, projectName, role.DatasourceScope, role.ReadAction); err != nil {
+		return err
+	}
+
+	dtsName := ctx.Param(utils.ParamName)
+	dts, err := e.getProjectDatasource(projectName, dtsName)
+	if err != nil {
+		return err
+	}
+
+	req := ctx.Request()
+	req.Method = body.Method
+	req.URL.RawQuery = body.Params.Encode()
+	req.Body = nil
+	req.ContentLength = 0
+	req.Header.Del(echo.HeaderContentType)
+	ctx.SetRequest(req)
+
+	ctx.SetParamNames("*")
+	ctx.SetParamValues(body.Path)
+	return e.proxyProjectDatasource(ctx, projectName, dtsName, dts)
+}
+
+func (e *passthroughEndpoint) isPathAllowed(p string) bool {
+	for _, pattern := range e.cfg.AllowedProxyPaths {
+		// the patterns are checked when the configuration is loaded
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassthrough(t *testing.T) {
+	// mock of the Prometheus API returning the values of a label.
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/label/job/values", r.URL.Path)
+		assert.Equal(t, []string{"up", "node_load1"}, r.URL.Query()["match[]"])
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		_, err := w.Write([]byte(`{"status":"success","data":["node","prometheus"]}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(prometheus.Close)
+
+	dts := &v1.Datasource{Spec: newPrometheusCompatibleDatasourceSpec(t, datasourcev1.PrometheusDatasourceKind, prometheus.URL, datasourcev1.PrometheusQueryExtensions{})}
+	e := &passthroughEndpoint{endpoint: &endpoint{
+		cfg:   config.DatasourceConfig{AllowedProxyPaths: []string{"/api/v1/labels", "/api/v1/label/*/values"}},
+		dts:   &fakeDatasourceDAO{datasources: map[string]*v1.Datasource{"prometheus": dts}},
+		authz: &disabledAuthorization{},
+	}}
+
+	passthrough := func(body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/projects/perses/datasources/prometheus/proxy", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+		ctx.SetParamNames(utils.ParamProject, utils.ParamName)
+		ctx.SetParamValues("perses", "prometheus")
+		return rec, e.passthrough(ctx)
+	}
+
+	t.Run("allowed path", func(t *testing.T) {
+		rec, err := passthrough(`{"path":"/api/v1/label/job/values","method":"GET","params":{"match[]":["up","node_load1"]}}`)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"success","data":["node","prometheus"]}`, rec.Body.String())
+	})
+	t.Run("method defaults to GET", func(t *testing.T) {
+		rec, err := passthrough(`{"path":"/api/v1/label/job/values","params":{"match[]":["up","node_load1"]}}`)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("disallowed path", func(t *testing.T) {
+		_, err := passthrough(`{"path":"/api/v1/admin/tsdb/delete_series","method":"POST"}`)
+		assert.ErrorIs(t, err, apiinterface.ForbiddenError)
+	})
+	t.Run("path escaping an allowed pattern", func(t *testing.T) {
+		_, err := passthrough(`{"path":"/api/v1/label/../admin/tsdb/delete_series","method":"POST"}`)
+		assert.ErrorIs(t, err, apiinterface.ForbiddenError)
+	})
+	t.Run("relative path", func(t *testing.T) {
+		_, err := passthrough(`{"path":"api/v1/labels"}`)
+		assert.Error(t, err)
+	})
+	t.Run("invalid method", func(t *testing.T) {
+		_, err := passthrough(`{"path":"/api/v1/labels","method":"PATCH"}`)
+		assert.Error(t, err)
+	})
+	t.Run("nothing allowed", func(t *testing.T) {
+		e.cfg.AllowedProxyPaths = nil
+		_, err := passthrough(`{"path":"/api/v1/labels"}`)
+		assert.ErrorIs(t, err, apiinterface.ForbiddenError)
+	})
+}
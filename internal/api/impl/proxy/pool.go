@@ -0,0 +1,108 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/sirupsen/logrus"
+)
+
+type pooledTransport struct {
+	transport *http.Transport
+	lastUsed  time.Time
+}
+
+// TransportPool keeps one HTTP transport per datasource so the connections to the datasource are reused between requests.
+// As a timer task, it closes the connections of the transports that have not been used for longer than the TTL,
+// to avoid sending a request over a connection the datasource (or a load balancer in between) has already dropped.
+type TransportPool struct {
+	async.SimpleTask
+	mutex      sync.Mutex
+	ttl        time.Duration
+	transports map[string]*pooledTransport
+	now        func() time.Time
+}
+
+func NewTransportPool(ttl time.Duration) *TransportPool {
+	return &TransportPool{
+		ttl:        ttl,
+		transports: make(map[string]*pooledTransport),
+		now:        time.Now,
+	}
+}
+
+func (p *TransportPool) String() string {
+	return "datasource proxy idle connections cleaner"
+}
+
+func (p *TransportPool) Execute(_ context.Context, _ context.CancelFunc) error {
+	if evicted := p.evictIdle(); evicted > 0 {
+		logrus.Debugf("%d idle datasource transport(s) have been closed", evicted)
+	}
+	return nil
+}
+
+// get returns the transport registered for the given key, or creates it with the function build.
+func (p *TransportPool) get(key string, build func() (*http.Transport, error)) (*http.Transport, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if pooled, ok := p.transports[key]; ok {
+		pooled.lastUsed = p.now()
+		return pooled.transport, nil
+	}
+	transport, err := build()
+	if err != nil {
+		return nil, err
+	}
+	// Connections left idle are closed by the transport itself once the TTL is reached.
+	transport.IdleConnTimeout = p.ttl
+	p.transports[key] = &pooledTransport{transport: transport, lastUsed: p.now()}
+	return transport, nil
+}
+
+// evictIdle closes the idle connections of every transport not used for longer than the TTL and removes it from the pool.
+// Connections still in use are not interrupted; they are closed by the transport once they become idle.
+func (p *TransportPool) evictIdle() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	evicted := 0
+	for key, pooled := range p.transports {
+		if p.now().Sub(pooled.lastUsed) < p.ttl {
+			continue
+		}
+		pooled.transport.CloseIdleConnections()
+		delete(p.transports, key)
+		evicted++
+	}
+	return evicted
+}
+
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   true,
+	}
+}
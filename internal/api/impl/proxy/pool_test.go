@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportPool_EvictIdle(t *testing.T) {
+	var opened, closed atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			opened.Add(1)
+		case http.StateClosed:
+			closed.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	now := time.Now()
+	pool := NewTransportPool(time.Minute)
+	pool.now = func() time.Time { return now }
+	build := func() (*http.Transport, error) { return newTransport(), nil }
+
+	sendRequest := func() {
+		transport, err := pool.get("prometheus", build)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// the connection is reused while the transport is in use
+	sendRequest()
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, 0, pool.evictIdle())
+	sendRequest()
+	assert.Equal(t, int32(1), opened.Load())
+	assert.Equal(t, int32(0), closed.Load())
+
+	// once the TTL is reached, the stale connection is closed and a new one is opened by the next request
+	now = now.Add(time.Minute)
+	assert.Equal(t, 1, pool.evictIdle())
+	assert.Eventually(t, func() bool { return closed.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	sendRequest()
+	assert.Equal(t, int32(2), opened.Load())
+}
+
+func TestTransportPool_IdleConnTimeout(t *testing.T) {
+	pool := NewTransportPool(10 * time.Second)
+	transport, err := pool.get("prometheus", func() (*http.Transport, error) { return newTransport(), nil })
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, transport.IdleConnTimeout)
+
+	same, err := pool.get("prometheus", func() (*http.Transport, error) { return newTransport(), nil })
+	require.NoError(t, err)
+	assert.Same(t, transport, same)
+}
@@ -14,7 +14,10 @@
 package proxy
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"database/sql"
 	"encoding/json"
@@ -25,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,19 +38,23 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
 	"github.com/perses/perses/internal/api/crypto"
+	datasourceImpl "github.com/perses/perses/internal/api/impl/v1/datasource"
 	apiinterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
 	"github.com/perses/perses/internal/api/interface/v1/globalsecret"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/internal/httpclient"
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
 	"github.com/perses/perses/pkg/model/api/v1/role"
 	secretModel "github.com/perses/perses/pkg/model/api/v1/secret"
+	pluginAPI "github.com/perses/perses/pkg/plugin/api"
 	"github.com/perses/spec/go/common"
 	datasourceSpec "github.com/perses/spec/go/datasource"
 	datasourceHTTP "github.com/perses/spec/go/datasource/proxy/http"
@@ -59,6 +67,12 @@ import (
 const (
 	datasourceFieldLog = "datasource"
 	projectFieldLog    = "project"
+	// exemplarsPath is the path of the Prometheus API returning the exemplars of a query.
+	exemplarsPath = "/api/v1/query_exemplars"
+	// exemplarsCacheTTL is short, as a panel displaying exemplars is requesting them every time it is refreshed.
+	exemplarsCacheTTL = 5 * time.Second
+	// urlTemplateVariablePrefix is the prefix of the query parameters holding the values of the variables of a URL template.
+	urlTemplateVariablePrefix = "var-"
 )
 
 // projectForLog returns a meaningful log value for the project field.
@@ -124,6 +138,7 @@ const unsavedDatasourceDefaultName = "unsaved-datasource"
 
 type endpoint struct {
 	cfg          config.DatasourceConfig
+	apiCfg       config.API
 	dashboard    dashboard.DAO
 	secret       secret.DAO
 	globalSecret globalsecret.DAO
@@ -131,12 +146,32 @@ type endpoint struct {
 	globalDTS    globaldatasource.DAO
 	crypto       crypto.Crypto
 	authz        authorization.Authorization
+	dedup        *datasourceImpl.QueryDeduplicator
+	transports   *TransportPool
+	exemplars    *datasourceImpl.QueryCache
+	tokens       *datasourceImpl.TokenCache
+	clientCert   *clientCertificate
+	// audit records the queries sent to the saved datasources. It is nil when the query inspector is disabled.
+	audit queryaudit.Service
 }
 
-func New(cfg config.DatasourceConfig, dashboardDAO dashboard.DAO, secretDAO secret.DAO, globalSecretDAO globalsecret.DAO,
-	dtsDAO datasource.DAO, globalDtsDAO globaldatasource.DAO, crypto crypto.Crypto, authz authorization.Authorization) route.Endpoint {
+// New returns the endpoint of the datasource proxies. audit is optional and is nil when the queries are not recorded.
+func New(cfg config.DatasourceConfig, apiCfg config.API, dashboardDAO dashboard.DAO, secretDAO secret.DAO, globalSecretDAO globalsecret.DAO,
+	dtsDAO datasource.DAO, globalDtsDAO globaldatasource.DAO, crypto crypto.Crypto, authz authorization.Authorization, transports *TransportPool, audit queryaudit.Service) route.Endpoint {
+	e := newEndpoint(cfg, apiCfg, dashboardDAO, secretDAO, globalSecretDAO, dtsDAO, globalDtsDAO, crypto, authz, transports)
+	e.audit = audit
+	return e
+}
+
+func newEndpoint(cfg config.DatasourceConfig, apiCfg config.API, dashboardDAO dashboard.DAO, secretDAO secret.DAO, globalSecretDAO globalsecret.DAO,
+	dtsDAO datasource.DAO, globalDtsDAO globaldatasource.DAO, crypto crypto.Crypto, authz authorization.Authorization, transports *TransportPool) *endpoint {
+	var dedup *datasourceImpl.QueryDeduplicator
+	if cfg.DeduplicateQueries {
+		dedup = datasourceImpl.NewQueryDeduplicator()
+	}
 	return &endpoint{
 		cfg:          cfg,
+		apiCfg:       apiCfg,
 		dashboard:    dashboardDAO,
 		secret:       secretDAO,
 		globalSecret: globalSecretDAO,
@@ -144,28 +179,38 @@ func New(cfg config.DatasourceConfig, dashboardDAO dashboard.DAO, secretDAO secr
 		globalDTS:    globalDtsDAO,
 		crypto:       crypto,
 		authz:        authz,
+		dedup:        dedup,
+		transports:   transports,
+		exemplars:    datasourceImpl.NewQueryCache(exemplarsCacheTTL),
+		tokens:       datasourceImpl.NewTokenCache(),
+		clientCert:   newClientCertificate(cfg.ClientTLS),
 	}
 }
 
 func (e *endpoint) CollectRoutes(g *route.Group) {
+	// middlewares applied to the routes of the saved datasources only
+	var saved []echo.MiddlewareFunc
+	if e.audit != nil {
+		saved = append(saved, auditQuery(e.audit))
+	}
 	if !e.cfg.Global.Disable {
-		g.ANY(fmt.Sprintf("/%s/:%s/*", utils.PathGlobalDatasource, utils.ParamName), e.proxySavedGlobalDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s/*", utils.PathGlobalDatasource, utils.ParamName), e.proxySavedGlobalDatasource, false, saved...)
 		// allow direct datasource queries without extra path (e.g., from ClickHouse)
-		g.ANY(fmt.Sprintf("/%s/:%s", utils.PathGlobalDatasource, utils.ParamName), e.proxySavedGlobalDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s", utils.PathGlobalDatasource, utils.ParamName), e.proxySavedGlobalDatasource, false, saved...)
 
 		g.POST(fmt.Sprintf("/%s/%s/*", utils.PathUnsaved, utils.PathGlobalDatasource), e.proxyUnsavedGlobalDatasource, false)
 	}
 	if !e.cfg.Project.Disable {
-		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/*", utils.PathProject, utils.ParamProject, utils.PathDatasource, utils.ParamName), e.proxySavedProjectDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/*", utils.PathProject, utils.ParamProject, utils.PathDatasource, utils.ParamName), e.proxySavedProjectDatasource, false, saved...)
 		// allow direct datasource queries without extra path (e.g., from ClickHouse)
-		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s", utils.PathProject, utils.ParamProject, utils.PathDatasource, utils.ParamName), e.proxySavedProjectDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s", utils.PathProject, utils.ParamProject, utils.PathDatasource, utils.ParamName), e.proxySavedProjectDatasource, false, saved...)
 
 		g.POST(fmt.Sprintf("/%s/%s/:%s/%s/*", utils.PathUnsaved, utils.PathProject, utils.ParamProject, utils.PathDatasource), e.proxyUnsavedProjectDatasource, false)
 	}
 	if !e.cfg.DisableLocal {
-		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/%s/:%s/*", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, utils.PathDatasource, utils.ParamName), e.proxySavedDashboardDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/%s/:%s/*", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, utils.PathDatasource, utils.ParamName), e.proxySavedDashboardDatasource, false, saved...)
 		// allow direct datasource queries without extra path (e.g., from ClickHouse)
-		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/%s/:%s", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, utils.PathDatasource, utils.ParamName), e.proxySavedDashboardDatasource, false)
+		g.ANY(fmt.Sprintf("/%s/:%s/%s/:%s/%s/:%s", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, utils.PathDatasource, utils.ParamName), e.proxySavedDashboardDatasource, false, saved...)
 
 		g.POST(fmt.Sprintf("/%s/%s/:%s/%s/:%s/%s/*", utils.PathUnsaved, utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamDashboard, utils.PathDatasource), e.proxyUnsavedDashboardDatasource, false)
 	}
@@ -194,7 +239,7 @@ type proxy interface {
 	serve(c echo.Context) error
 }
 
-func newProxy(datasourceName, projectName string, spec datasourceSpec.Spec, path string, crypto crypto.Crypto, retrieveSecret func(name string) (*v1.SecretSpec, error)) (proxy, error) {
+func newProxy(datasourceName, projectName string, spec datasourceSpec.Spec, path string, crypto crypto.Crypto, dedup *datasourceImpl.QueryDeduplicator, maxQueryTimeRange time.Duration, transports *TransportPool, exemplars *datasourceImpl.QueryCache, tokens *datasourceImpl.TokenCache, transforms []config.DatasourceTransform, clientCert *clientCertificate, retrieveSecret func(name string) (*v1.SecretSpec, error)) (proxy, error) {
 	cfg, kind, err := datasourcev1.ValidateAndExtract(spec.Plugin.Spec)
 	if err != nil {
 		logrus.WithError(err).WithFields(map[string]interface{}{
@@ -226,11 +271,39 @@ func newProxy(datasourceName, projectName string, spec datasourceSpec.Spec, path
 				return nil, apiinterface.InternalError
 			}
 		}
+		queryParams, queryErr := datasourcev1.QueryExtensions(spec.Plugin.Kind, spec.Plugin.Spec)
+		if queryErr != nil {
+			logrus.WithError(queryErr).WithFields(map[string]interface{}{
+				datasourceFieldLog: datasourceName,
+				projectFieldLog:    projectForLog(projectName),
+			}).Error("unable to read the query extensions in the datasource spec")
+			return nil, echo.NewHTTPError(http.StatusBadGateway, "unable to read the query extensions")
+		}
+		urlTemplate, templateErr := datasourcev1.ExtractURLTemplate(spec.Plugin.Spec)
+		if templateErr != nil {
+			logrus.WithError(templateErr).WithFields(map[string]interface{}{
+				datasourceFieldLog: datasourceName,
+				projectFieldLog:    projectForLog(projectName),
+			}).Error("unable to read the URL template in the datasource spec")
+			return nil, echo.NewHTTPError(http.StatusBadGateway, "unable to read the URL template")
+		}
 		return &httpProxy{
-			config:         httpConfig,
-			datasourceName: datasourceName,
-			path:           path,
-			secret:         scrt,
+			config:             httpConfig,
+			datasourceName:     datasourceName,
+			projectName:        projectName,
+			path:               path,
+			secret:             scrt,
+			dedup:              dedup,
+			queryParams:        queryParams,
+			maxQueryTimeRange:  maxQueryTimeRange,
+			transports:         transports,
+			exemplars:          exemplars,
+			tokens:             tokens,
+			transforms:         transformsOf(spec.Plugin.Kind, transforms),
+			convertOpenMetrics: datasourcev1.IsPrometheusCompatible(spec.Plugin.Kind),
+			prometheusAPI:      datasourcev1.IsPrometheusCompatible(spec.Plugin.Kind),
+			urlTemplate:        urlTemplate,
+			clientCert:         clientCert,
 		}, nil
 	case datasourceSQL.ProxyKindName:
 		sqlConfig := cfg.(*datasourceSQL.Config)
@@ -263,7 +336,30 @@ type httpProxy struct {
 	config         *datasourceHTTP.Config
 	secret         *v1.SecretSpec
 	datasourceName string
+	projectName    string
 	path           string
+	// dedup is nil when the deduplication of the queries is disabled.
+	dedup *datasourceImpl.QueryDeduplicator
+	// queryParams are added to the query of the request when it doesn't set them already.
+	queryParams url.Values
+	// maxQueryTimeRange is the longest time range a query can cover. It is not checked when zero.
+	maxQueryTimeRange time.Duration
+	// transports is nil when every request must use its own transport.
+	transports *TransportPool
+	// exemplars caches the responses of the Prometheus exemplars API. It is nil when the responses are not cached.
+	exemplars *datasourceImpl.QueryCache
+	// tokens caches the access tokens of the OAuth secrets. It is nil when a new token is requested for every request.
+	tokens *datasourceImpl.TokenCache
+	// transforms are applied, in order, to the successful responses of the datasource.
+	transforms []config.DatasourceTransform
+	// convertOpenMetrics is true when the responses in the OpenMetrics text format are converted to JSON time series.
+	convertOpenMetrics bool
+	// prometheusAPI is true when the datasource speaks the Prometheus HTTP API, so the path can be chosen from the query hint.
+	prometheusAPI bool
+	// urlTemplate replaces the URL of the config once resolved with the variables of the request. It is empty when not used.
+	urlTemplate datasourcev1.URLTemplate
+	// clientCert is presented to the datasource when it requires mutual TLS. It is nil when not configured.
+	clientCert *clientCertificate
 }
 
 func (h *httpProxy) logWithDefaultEntry() *logrus.Entry {
@@ -277,6 +373,16 @@ func (h *httpProxy) serve(c echo.Context) error {
 	req := c.Request()
 	res := c.Response()
 
+	if len(h.urlTemplate) > 0 {
+		if err := h.resolveURLTemplate(req); err != nil {
+			return apiinterface.HandleBadRequestError(err.Error())
+		}
+	}
+
+	if err := h.resolveQueryPath(req); err != nil {
+		return apiinterface.HandleBadRequestError(err.Error())
+	}
+
 	isAllowed := false
 	for _, allowedEndpoint := range h.config.AllowedEndpoints {
 		if allowedEndpoint.Method == req.Method && len(allowedEndpoint.EndpointPattern.FindAllString(h.path, -1)) > 0 {
@@ -289,6 +395,10 @@ func (h *httpProxy) serve(c echo.Context) error {
 		return apiinterface.HandleForbiddenError(fmt.Sprintf("you are not allowed to use this endpoint %q with the HTTP method %s", h.path, req.Method))
 	}
 
+	if err := h.checkQueryTimeRange(req); err != nil {
+		return err
+	}
+
 	if err := h.prepareRequest(c); err != nil {
 		h.logWithDefaultEntry().WithError(err).Error("unable to prepare the HTTP request")
 		return apiinterface.InternalError
@@ -311,6 +421,18 @@ func (h *httpProxy) serve(c echo.Context) error {
 	if transportErr != nil {
 		return transportErr
 	}
+	if h.convertOpenMetrics {
+		reverseProxy.ModifyResponse = h.convertOpenMetricsResponse
+	}
+	if len(h.transforms) > 0 {
+		return h.serveTransformed(c, reverseProxy, &proxyErr)
+	}
+	if h.exemplars != nil && req.Method == http.MethodGet && strings.HasSuffix(h.path, exemplarsPath) {
+		return h.serveCached(c, reverseProxy, &proxyErr)
+	}
+	if h.dedup != nil && (req.Method == http.MethodGet || req.Method == http.MethodPost) {
+		return h.serveDeduplicated(c, reverseProxy, &proxyErr)
+	}
 	// Reverse proxy request.
 	reverseProxy.ServeHTTP(res, req)
 	// Return any error handled during proxying request.
@@ -327,6 +449,202 @@ func (h *httpProxy) serve(c echo.Context) error {
 	return nil
 }
 
+// resolveURLTemplate replaces the URL of the datasource with the URL template resolved with the variables of the request.
+// The variables are sent as query parameters prefixed by urlTemplateVariablePrefix, e.g. `var-cluster=prod`.
+// They are removed from the request forwarded to the datasource.
+func (h *httpProxy) resolveURLTemplate(req *http.Request) error {
+	query := req.URL.Query()
+	values := make(map[string]string)
+	for key := range query {
+		if name, ok := strings.CutPrefix(key, urlTemplateVariablePrefix); ok {
+			values[name] = query.Get(key)
+			query.Del(key)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+	resolved, err := h.urlTemplate.Resolve(values)
+	if err != nil {
+		return err
+	}
+	h.config.URL = &common.URL{URL: resolved}
+	return nil
+}
+
+// serveDeduplicated sends the request to the datasource only if no identical request is already in flight.
+// Otherwise, it waits for the response of the request in flight and sends it back.
+func (h *httpProxy) serveDeduplicated(c echo.Context, reverseProxy *httputil.ReverseProxy, proxyErr *error) error {
+	req := c.Request()
+	var body []byte
+	if req.Body != nil {
+		var readErr error
+		body, readErr = io.ReadAll(req.Body)
+		if readErr != nil {
+			return apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the body of the request: %s", readErr))
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key, err := h.queryKey(req, body)
+	if err != nil {
+		return err
+	}
+	response, shared, err := h.dedup.Do(key, func() (*datasourceImpl.QueryResponse, error) {
+		return recordQuery(reverseProxy, req, proxyErr)
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if shared {
+		h.logWithDefaultEntry().WithField("path", h.path).Debug("response shared with identical requests")
+	}
+	return response.Write(c.Response())
+}
+
+// serveCached sends back the response kept in the cache for an identical request, or sends the request to the datasource.
+// Only the successful responses are cached.
+func (h *httpProxy) serveCached(c echo.Context, reverseProxy *httputil.ReverseProxy, proxyErr *error) error {
+	req := c.Request()
+	key, err := h.queryKey(req, nil)
+	if err != nil {
+		return err
+	}
+	if response, ok := h.exemplars.Get(key); ok {
+		h.logWithDefaultEntry().WithField("path", h.path).Debug("response served from the cache")
+		return response.Write(c.Response())
+	}
+	response, err := recordQuery(reverseProxy, req, proxyErr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		h.exemplars.Set(key, response)
+	}
+	return response.Write(c.Response())
+}
+
+// serveTransformed applies the transforms to the successful response of the datasource before sending it back.
+// The responses of a datasource with transforms are neither deduplicated nor cached.
+func (h *httpProxy) serveTransformed(c echo.Context, reverseProxy *httputil.ReverseProxy, proxyErr *error) error {
+	req := c.Request()
+	// The transforms are working on the raw body, so it must not be compressed by the datasource.
+	req.Header.Del(echo.HeaderAcceptEncoding)
+	response, err := recordQuery(reverseProxy, req, proxyErr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		if transformErr := h.transform(response); transformErr != nil {
+			return transformErr
+		}
+	}
+	return response.Write(c.Response())
+}
+
+func (h *httpProxy) transform(response *datasourceImpl.QueryResponse) error {
+	body := json.RawMessage(response.Body)
+	for _, t := range h.transforms {
+		plugin, ok := pluginAPI.GetTransformPlugin(t.Plugin)
+		if !ok {
+			h.logWithDefaultEntry().Errorf("no transform plugin registered with the name %q", t.Plugin)
+			return apiinterface.InternalError
+		}
+		rawConfig, err := json.Marshal(t.Config)
+		if err != nil {
+			h.logWithDefaultEntry().WithError(err).Errorf("unable to marshal the config of the transform plugin %q", t.Plugin)
+			return apiinterface.InternalError
+		}
+		body, err = plugin.Transform(body, rawConfig)
+		if err != nil {
+			h.logWithDefaultEntry().WithError(err).Errorf("the transform plugin %q failed", t.Plugin)
+			return echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("unable to transform the response of the datasource with the plugin %q", t.Plugin))
+		}
+	}
+	response.Body = body
+	response.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	response.Header.Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+	return nil
+}
+
+// convertOpenMetricsResponse replaces a body in the OpenMetrics text format, like the one of the /metrics endpoint,
+// by its conversion to the Perses time series model in JSON.
+func (h *httpProxy) convertOpenMetricsResponse(res *http.Response) error {
+	if !datasourceImpl.IsOpenMetrics(res.Header.Get(echo.HeaderContentType)) {
+		return nil
+	}
+	var reader io.Reader = res.Body
+	switch res.Header.Get(echo.HeaderContentEncoding) {
+	case "":
+	case "gzip":
+		gzipReader, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return fmt.Errorf("unable to decompress the OpenMetrics response: %w", err)
+		}
+		reader = gzipReader
+	default:
+		// the body cannot be decoded, so it is sent back as it is
+		return nil
+	}
+	data, err := io.ReadAll(reader)
+	_ = res.Body.Close()
+	if err != nil {
+		return fmt.Errorf("unable to read the OpenMetrics response: %w", err)
+	}
+	series, err := datasourceImpl.ConvertOpenMetrics(data, time.Now())
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(series)
+	if err != nil {
+		return err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Del(echo.HeaderContentEncoding)
+	res.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	res.Header.Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+	return nil
+}
+
+// transformsOf returns the transforms that apply to the given kind of datasource.
+func transformsOf(datasourceKind string, transforms []config.DatasourceTransform) []config.DatasourceTransform {
+	var result []config.DatasourceTransform
+	for _, t := range transforms {
+		if t.DatasourceKind == datasourceKind {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// queryKey identifies the request sent to the datasource, so identical requests can share the same response.
+func (h *httpProxy) queryKey(req *http.Request, body []byte) (string, error) {
+	// The complete config is part of the key, as two local datasources can share the same name but not the same config.
+	rawConfig, err := json.Marshal(h.config)
+	if err != nil {
+		h.logWithDefaultEntry().WithError(err).Error("unable to marshal the datasource config")
+		return "", apiinterface.InternalError
+	}
+	return datasourceImpl.QueryKey(
+		[]byte(h.projectName),
+		[]byte(h.datasourceName),
+		rawConfig,
+		[]byte(req.Method),
+		[]byte(h.path),
+		[]byte(req.URL.RawQuery),
+		[]byte(req.Header.Get(echo.HeaderContentType)),
+		[]byte(req.Header.Get(echo.HeaderAcceptEncoding)),
+		body,
+	), nil
+}
+
+func recordQuery(reverseProxy *httputil.ReverseProxy, req *http.Request, proxyErr *error) (*datasourceImpl.QueryResponse, error) {
+	recorder := datasourceImpl.NewQueryRecorder()
+	reverseProxy.ServeHTTP(recorder, req)
+	if *proxyErr != nil {
+		return nil, *proxyErr
+	}
+	return recorder.Response(), nil
+}
+
 func (h *httpProxy) prepareRequest(c echo.Context) error {
 	req := c.Request()
 	// We have to modify the HOST of the request to match the host of the targetURL
@@ -359,6 +677,15 @@ func (h *httpProxy) prepareRequest(c echo.Context) error {
 			req.Header.Set(k, v)
 		}
 	}
+	if len(h.queryParams) > 0 {
+		query := req.URL.Query()
+		for k, v := range h.queryParams {
+			if !query.Has(k) {
+				query[k] = v
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+	}
 	return h.setupAuthentication(req)
 }
 
@@ -397,18 +724,6 @@ func (h *httpProxy) setupAuthentication(req *http.Request) error {
 // getToken exchanges the client credentials for an access token,
 // from the OAuth 2.0 provider.
 func (h *httpProxy) getToken(ctx context.Context, oauth *secretModel.OAuth) (*oauth2.Token, error) {
-	transport, err := h.prepareTransport()
-	if err != nil {
-		return nil, err
-	}
-
-	httpClient := http.Client{
-		Transport: transport,
-	}
-
-	// add our http client with tls config
-	newCtx := context.WithValue(ctx, oauth2.HTTPClient, httpClient)
-
 	clientSecret, err := oauth.GetClientSecret()
 	if err != nil {
 		return nil, fmt.Errorf("unable to get client secret: %s", err)
@@ -424,44 +739,74 @@ func (h *httpProxy) getToken(ctx context.Context, oauth *secretModel.OAuth) (*oa
 		AuthStyle:      oauth2.AuthStyle(oauth.AuthStyle),
 	}
 
-	// Use the Token method to retrieve the token
-	token, err := conf.Token(newCtx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token: %w", err)
+	fetch := func() (*oauth2.Token, error) {
+		transport, transportErr := h.prepareTransport()
+		if transportErr != nil {
+			return nil, transportErr
+		}
+		httpClient := httpclient.NewHTTPClient(httpclient.WithTransport(transport))
+		// add our http client with tls config
+		newCtx := context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+
+		// Use the Token method to retrieve the token
+		token, tokenErr := conf.Token(newCtx)
+		if tokenErr != nil {
+			return nil, fmt.Errorf("failed to get token: %w", tokenErr)
+		}
+		if !token.Valid() {
+			// APIs like GitHub might return an invalid token without error
+			return nil, errors.New("invalid token received")
+		}
+		return token, nil
 	}
-
-	if !token.Valid() {
-		// APIs like GitHub might return an invalid token without error
-		return nil, errors.New("invalid token received")
+	if h.tokens == nil {
+		return fetch()
 	}
-
-	return token, err
+	return h.tokens.Token(datasourceImpl.TokenKey(conf), fetch)
 }
 
 func (h *httpProxy) prepareTransport() (*http.Transport, error) {
-	tlsConfig, err := h.prepareTLSConfig()
-	if err != nil {
-		h.logWithDefaultEntry().WithError(err).Error("unable to build the tls config")
-		return nil, echo.NewHTTPError(http.StatusBadGateway, "unable build the tls config")
-	}
-	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 10 * time.Second,
-		IdleConnTimeout:     90 * time.Second,
-		ForceAttemptHTTP2:   true,
-		TLSClientConfig:     tlsConfig,
-	}, nil
+	build := func() (*http.Transport, error) {
+		tlsConfig, err := h.prepareTLSConfig()
+		if err != nil {
+			h.logWithDefaultEntry().WithError(err).Error("unable to build the tls config")
+			return nil, echo.NewHTTPError(http.StatusBadGateway, "unable build the tls config")
+		}
+		transport := newTransport()
+		transport.TLSClientConfig = tlsConfig
+		return transport, nil
+	}
+	if h.transports == nil {
+		return build()
+	}
+	return h.transports.get(h.transportKey(), build)
+}
+
+// transportKey identifies the transport that can be shared by the requests sent to the same datasource with the same TLS config.
+func (h *httpProxy) transportKey() string {
+	key := fmt.Sprintf("%s/%s/%s", h.projectName, h.datasourceName, h.config.URL.String())
+	if h.secret != nil && h.secret.TLSConfig != nil {
+		if data, err := json.Marshal(h.secret.TLSConfig); err == nil {
+			key = fmt.Sprintf("%s/%x", key, sha256.Sum256(data))
+		}
+	}
+	return key
 }
 
 func (h *httpProxy) prepareTLSConfig() (*tls.Config, error) {
-	if h.secret == nil {
-		return &tls.Config{MinVersion: tls.VersionTLS12, MaxVersion: tls.VersionTLS13}, nil
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, MaxVersion: tls.VersionTLS13}
+	if h.secret != nil {
+		var err error
+		if tlsConfig, err = h.secret.TLSConfig.BuildTLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if h.clientCert != nil {
+		if err := h.clientCert.apply(tlsConfig); err != nil {
+			return nil, err
+		}
 	}
-	return h.secret.TLSConfig.BuildTLSConfig()
+	return tlsConfig, nil
 }
 
 type sqlQuery struct {
@@ -14,9 +14,26 @@
 package proxy
 
 import (
+	"compress/gzip"
 	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync/atomic"
 	"testing"
 
+	"github.com/labstack/echo/v4"
+	datasourceImpl "github.com/perses/perses/internal/api/impl/v1/datasource"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api/config"
+	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
+	pluginAPI "github.com/perses/perses/pkg/plugin/api"
+	"github.com/perses/spec/go/common"
+	datasourceSpec "github.com/perses/spec/go/datasource"
+	datasourceHTTP "github.com/perses/spec/go/datasource/proxy/http"
 	datasourceSQL "github.com/perses/spec/go/datasource/proxy/sql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -256,3 +273,361 @@ func TestSQLProxy_sqlOpen(t *testing.T) {
 		})
 	}
 }
+
+func newPrometheusCompatibleDatasourceSpec(t *testing.T, kind string, url string, extensions datasourcev1.PrometheusQueryExtensions) datasourceSpec.Spec {
+	proxyURL, err := common.ParseURL(url)
+	require.NoError(t, err)
+	data, err := json.Marshal(&datasourcev1.ThanosDataSource{
+		Prometheus: datasourcev1.Prometheus{
+			Proxy: &datasourceHTTP.Proxy{
+				Kind: "HTTPProxy",
+				Spec: datasourceHTTP.Config{URL: proxyURL},
+			},
+		},
+		PrometheusQueryExtensions: extensions,
+	})
+	require.NoError(t, err)
+	var pluginSpec map[string]any
+	require.NoError(t, json.Unmarshal(data, &pluginSpec))
+	return datasourceSpec.Spec{
+		Plugin: common.Plugin{
+			Kind: kind,
+			Spec: pluginSpec,
+		},
+	}
+}
+
+func TestHTTPProxy_QueryExtensions(t *testing.T) {
+	// mock of the Thanos Query API, returning the query parameters it received.
+	thanos := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(r.URL.Query()))
+	}))
+	t.Cleanup(thanos.Close)
+	enabled, disabled := true, false
+
+	testSuite := []struct {
+		name     string
+		kind     string
+		query    string
+		expected map[string][]string
+	}{
+		{
+			name:  "thanos extensions added",
+			kind:  datasourcev1.ThanosDatasourceKind,
+			query: "query=up",
+			expected: map[string][]string{
+				"query":            {"up"},
+				"dedup":            {"true"},
+				"partial_response": {"false"},
+			},
+		},
+		{
+			name:  "parameters set by the client are kept",
+			kind:  datasourcev1.CortexDatasourceKind,
+			query: "query=up&dedup=false",
+			expected: map[string][]string{
+				"query":            {"up"},
+				"dedup":            {"false"},
+				"partial_response": {"false"},
+			},
+		},
+		{
+			name:  "extensions ignored for a Prometheus datasource",
+			kind:  "PrometheusDatasource",
+			query: "query=up",
+			expected: map[string][]string{
+				"query": {"up"},
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.name, func(t *testing.T) {
+			spec := newPrometheusCompatibleDatasourceSpec(t, test.kind, thanos.URL, datasourcev1.PrometheusQueryExtensions{
+				Dedup:           &enabled,
+				PartialResponse: &disabled,
+			})
+			p, err := newProxy("thanos", "perses", spec, "/api/v1/query_range", nil, nil, 0, nil, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/thanos/api/v1/query_range?"+test.query, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var received map[string][]string
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &received))
+			assert.Equal(t, test.expected, received)
+		})
+	}
+}
+
+func TestHTTPProxy_URLTemplate(t *testing.T) {
+	// mock of the Prometheus API of a cluster, returning the query parameters it received.
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(r.URL.Query()))
+	}))
+	t.Cleanup(prometheus.Close)
+	prometheusURL, err := url.Parse(prometheus.URL)
+	require.NoError(t, err)
+
+	// The default URL is not reachable, so the request can only succeed if the template is used.
+	spec := newPrometheusCompatibleDatasourceSpec(t, "PrometheusDatasource", "http://127.0.0.1:1", datasourcev1.PrometheusQueryExtensions{})
+	spec.Plugin.Spec.(map[string]any)["proxy"].(map[string]any)["spec"].(map[string]any)["urlTemplate"] = "http://{host}:{port}"
+
+	t.Run("variables substituted", func(t *testing.T) {
+		p, err := newProxy("prometheus", "perses", spec, "/api/v1/query", nil, nil, 0, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/proxy/projects/perses/datasources/prometheus/api/v1/query?query=up&var-host=%s&var-port=%s", prometheusURL.Hostname(), prometheusURL.Port()), nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var received map[string][]string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &received))
+		// the variables are not forwarded to the datasource
+		assert.Equal(t, map[string][]string{"query": {"up"}}, received)
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		p, err := newProxy("prometheus", "perses", spec, "/api/v1/query", nil, nil, 0, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/api/v1/query?query=up&var-host=127.0.0.1", nil)
+		err = p.serve(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.ErrorIs(t, err, apiinterface.BadRequestError)
+		assert.ErrorContains(t, err, `missing value for the variable "port" of the URL template`)
+	})
+}
+
+func TestHTTPProxy_ExemplarsCache(t *testing.T) {
+	// mock of the Prometheus exemplars API, counting the requests it received.
+	var calls atomic.Int32
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_exemplars", r.URL.Path)
+		calls.Add(1)
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		_, err := w.Write([]byte(`{"status":"success","data":[{"seriesLabels":{"__name__":"http_request_duration_seconds_bucket"},"exemplars":[{"labels":{"trace_id":"` + r.URL.Query().Get("query") + `"},"value":"0.5","timestamp":1600096945.479}]}]}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(prometheus.Close)
+	spec := newPrometheusCompatibleDatasourceSpec(t, "PrometheusDatasource", prometheus.URL, datasourcev1.PrometheusQueryExtensions{})
+	cache := datasourceImpl.NewQueryCache(exemplarsCacheTTL)
+
+	query := func(path, rawQuery string) *httptest.ResponseRecorder {
+		p, err := newProxy("prometheus", "perses", spec, path, nil, nil, 0, nil, cache, nil, nil, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus"+path+"?"+rawQuery, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	first := query("/api/v1/query_exemplars", "query=up&start=1600096900&end=1600096950")
+	second := query("/api/v1/query_exemplars", "query=up&start=1600096900&end=1600096950")
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, echo.MIMEApplicationJSON, second.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, second.Body.String(), `"trace_id":"up"`)
+
+	// the parameters are passed through and are part of the cache key
+	other := query("/api/v1/query_exemplars", "query=rate(up[5m])&start=1600096900&end=1600096950")
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Contains(t, other.Body.String(), `"trace_id":"rate(up[5m])"`)
+}
+
+func TestHTTPProxy_OpenMetrics(t *testing.T) {
+	const payload = `# TYPE up gauge
+up{job="perses"} 1 1700000000
+# TYPE http_requests counter
+http_requests_total{code="200"} 1027 1700000000.5
+# EOF
+`
+	// mock of a /metrics endpoint, compressing the payload when asked to.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		if r.Header.Get(echo.HeaderAcceptEncoding) != "gzip" {
+			_, _ = w.Write([]byte(payload))
+			return
+		}
+		w.Header().Set(echo.HeaderContentEncoding, "gzip")
+		gzipWriter := gzip.NewWriter(w)
+		_, _ = gzipWriter.Write([]byte(payload))
+		_ = gzipWriter.Close()
+	}))
+	t.Cleanup(server.Close)
+	const converted = `{"series":[
+		{"name":"up","labels":{"job":"perses"},"values":[[1700000000000,1]]},
+		{"name":"http_requests_total","labels":{"code":"200"},"values":[[1700000000500,1027]]}
+	]}`
+
+	testSuite := []struct {
+		name           string
+		datasourceKind string
+		acceptEncoding string
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "converted",
+			datasourceKind: "PrometheusDatasource",
+			expectedType:   echo.MIMEApplicationJSON,
+			expectedBody:   converted,
+		},
+		{
+			name:           "compressed response converted",
+			datasourceKind: "ThanosDatasource",
+			acceptEncoding: "gzip",
+			expectedType:   echo.MIMEApplicationJSON,
+			expectedBody:   converted,
+		},
+		{
+			name:           "not converted for other datasources",
+			datasourceKind: "LokiDatasource",
+			expectedType:   "application/openmetrics-text; version=1.0.0; charset=utf-8",
+			expectedBody:   payload,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.name, func(t *testing.T) {
+			spec := newPrometheusCompatibleDatasourceSpec(t, test.datasourceKind, server.URL, datasourcev1.PrometheusQueryExtensions{})
+			p, err := newProxy("prometheus", "perses", spec, "/metrics", nil, nil, 0, nil, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/metrics", nil)
+			if len(test.acceptEncoding) > 0 {
+				req.Header.Set(echo.HeaderAcceptEncoding, test.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, test.expectedType, rec.Header().Get(echo.HeaderContentType))
+			assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+			if test.expectedType == echo.MIMEApplicationJSON {
+				assert.JSONEq(t, test.expectedBody, rec.Body.String())
+			} else {
+				assert.Equal(t, test.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+// reverseTransform reverses the order of the samples of the series returned by a Prometheus range query.
+type reverseTransform struct {
+	receivedConfig json.RawMessage
+}
+
+func (r *reverseTransform) Transform(input json.RawMessage, config json.RawMessage) (json.RawMessage, error) {
+	r.receivedConfig = config
+	var response struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Values [][2]any          `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(input, &response); err != nil {
+		return nil, err
+	}
+	for _, series := range response.Data.Result {
+		slices.Reverse(series.Values)
+	}
+	return json.Marshal(response)
+}
+
+func TestHTTPProxy_Transforms(t *testing.T) {
+	// mock of the Prometheus query_range API, returning a single series or an error.
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(echo.HeaderAcceptEncoding))
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if r.URL.Query().Get("query") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"missing query"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up"},"values":[[1,"1"],[2,"0"],[3,"1"]]}]}}`))
+	}))
+	t.Cleanup(prometheus.Close)
+	transform := &reverseTransform{}
+	require.NoError(t, pluginAPI.RegisterTransformPlugin("reverse", transform))
+	t.Cleanup(func() { pluginAPI.UnregisterTransformPlugin("reverse") })
+	spec := newPrometheusCompatibleDatasourceSpec(t, "PrometheusDatasource", prometheus.URL, datasourcev1.PrometheusQueryExtensions{})
+
+	testSuite := []struct {
+		name         string
+		query        string
+		transforms   []config.DatasourceTransform
+		expectedCode int
+		expectedBody string
+		// expectedConfig is the config received by the last transform applied
+		expectedConfig string
+	}{
+		{
+			name:  "series reversed",
+			query: "query=up",
+			transforms: []config.DatasourceTransform{
+				{DatasourceKind: "PrometheusDatasource", Plugin: "reverse", Config: map[string]any{"order": "desc"}},
+			},
+			expectedCode:   http.StatusOK,
+			expectedBody:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up"},"values":[[3,"1"],[2,"0"],[1,"1"]]}]}}`,
+			expectedConfig: `{"order":"desc"}`,
+		},
+		{
+			name:  "transforms applied in order",
+			query: "query=up",
+			transforms: []config.DatasourceTransform{
+				{DatasourceKind: "PrometheusDatasource", Plugin: "reverse"},
+				{DatasourceKind: "PrometheusDatasource", Plugin: "reverse"},
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up"},"values":[[1,"1"],[2,"0"],[3,"1"]]}]}}`,
+		},
+		{
+			name:  "transform of another datasource kind ignored",
+			query: "query=up",
+			transforms: []config.DatasourceTransform{
+				{DatasourceKind: "LokiDatasource", Plugin: "reverse"},
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up"},"values":[[1,"1"],[2,"0"],[3,"1"]]}]}}`,
+		},
+		{
+			name:  "error response not transformed",
+			query: "",
+			transforms: []config.DatasourceTransform{
+				{DatasourceKind: "PrometheusDatasource", Plugin: "reverse"},
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"status":"error","error":"missing query"}`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := newProxy("prometheus", "perses", spec, "/api/v1/query_range", nil, nil, 0, nil, nil, nil, test.transforms, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/api/v1/query_range?"+test.query, nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+			rec := httptest.NewRecorder()
+			require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+			assert.Equal(t, test.expectedCode, rec.Code)
+			assert.JSONEq(t, test.expectedBody, rec.Body.String())
+			if len(test.expectedConfig) > 0 {
+				assert.JSONEq(t, test.expectedConfig, string(transform.receivedConfig))
+			}
+		})
+	}
+}
+
+func TestHTTPProxy_TransformNotRegistered(t *testing.T) {
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(prometheus.Close)
+	spec := newPrometheusCompatibleDatasourceSpec(t, "PrometheusDatasource", prometheus.URL, datasourcev1.PrometheusQueryExtensions{})
+	transforms := []config.DatasourceTransform{{DatasourceKind: "PrometheusDatasource", Plugin: "unknown"}}
+	p, err := newProxy("prometheus", "perses", spec, "/api/v1/query", nil, nil, 0, nil, nil, nil, transforms, nil, nil)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/api/v1/query?query=up", nil)
+	assert.Equal(t, apiinterface.InternalError, p.serve(echo.New().NewContext(req, httptest.NewRecorder())))
+}
@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// auditQuery is a middleware recording the requests sent to the saved datasources, with the status and the latency of
+// their response. The record is written once the response is sent, so the database doesn't slow down the proxy.
+func auditQuery(audit queryaudit.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			// The error is ignored, the proxy reports it to the client if the parameters cannot be read.
+			params, _ := queryParams(req)
+			err := next(c)
+			spec := v1.QueryAuditSpec{
+				Project:    c.Param(utils.ParamProject),
+				Dashboard:  c.Param(utils.ParamDashboard),
+				Datasource: c.Param(utils.ParamName),
+				Method:     req.Method,
+				Path:       "/" + c.Param("*"),
+				Query:      params.Get("query"),
+				Params:     params,
+				LatencyMs:  time.Since(start).Milliseconds(),
+				Status:     responseStatus(c, err),
+				Time:       start,
+			}
+			go func() {
+				if recordErr := audit.Record(spec); recordErr != nil {
+					logrus.WithError(recordErr).WithField(datasourceFieldLog, spec.Datasource).Error("unable to record the datasource query")
+				}
+			}()
+			return err
+		}
+	}
+}
+
+// responseStatus returns the status code of the response, including when the handler returns an error that is
+// converted to a response later by the error middleware.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(apiinterface.HandleError(err), &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
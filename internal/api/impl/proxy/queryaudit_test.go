@@ -0,0 +1,111 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueryAudit struct {
+	queryaudit.Service
+	records chan v1.QueryAuditSpec
+}
+
+func (f *fakeQueryAudit) Record(spec v1.QueryAuditSpec) error {
+	f.records <- spec
+	return nil
+}
+
+func TestAuditQuery(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		req     *http.Request
+		handler echo.HandlerFunc
+		query   string
+		status  int
+	}{
+		{
+			title: "GET query",
+			req:   httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/api/v1/query?query=up", nil),
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			query:  "up",
+			status: http.StatusOK,
+		},
+		{
+			title: "POST form query",
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/proxy/projects/perses/datasources/prometheus/api/v1/query", strings.NewReader("query=rate(http_requests_total[5m])"))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+				return req
+			}(),
+			handler: func(c echo.Context) error {
+				// the body must still be available to the proxy
+				params, err := c.FormParams()
+				if err != nil || params.Get("query") != "rate(http_requests_total[5m])" {
+					return c.NoContent(http.StatusBadRequest)
+				}
+				return c.NoContent(http.StatusOK)
+			},
+			query:  "rate(http_requests_total[5m])",
+			status: http.StatusOK,
+		},
+		{
+			title: "query rejected with an error",
+			req:   httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus/api/v1/query?query=up", nil),
+			handler: func(_ echo.Context) error {
+				return apiinterface.HandleForbiddenError("missing permission")
+			},
+			query:  "up",
+			status: http.StatusForbidden,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			audit := &fakeQueryAudit{records: make(chan v1.QueryAuditSpec, 1)}
+			c := echo.New().NewContext(test.req, httptest.NewRecorder())
+			c.SetParamNames(utils.ParamProject, utils.ParamName, "*")
+			c.SetParamValues("perses", "prometheus", "api/v1/query")
+			_ = auditQuery(audit)(test.handler)(c)
+
+			var spec v1.QueryAuditSpec
+			select {
+			case spec = <-audit.records:
+			case <-time.After(time.Second):
+				t.Fatal("the query has not been recorded")
+			}
+			assert.Equal(t, "perses", spec.Project)
+			assert.Equal(t, "prometheus", spec.Datasource)
+			assert.Equal(t, test.req.Method, spec.Method)
+			assert.Equal(t, "/api/v1/query", spec.Path)
+			assert.Equal(t, test.query, spec.Query)
+			require.Contains(t, spec.Params, "query")
+			assert.Equal(t, test.status, spec.Status)
+			assert.False(t, spec.Time.IsZero())
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+
+	dashboardModel "github.com/perses/perses/pkg/model/api/v1/dashboard"
+)
+
+// queryHintParam is the query parameter of the proxied requests holding the query hint.
+const queryHintParam = "queryHint"
+
+// prometheusQueryPaths are the paths of the Prometheus API to use for each query hint.
+// Prometheus doesn't stream the results of a query, so there is no path for QueryHintStream.
+var prometheusQueryPaths = map[dashboardModel.QueryHint]string{
+	dashboardModel.QueryHintInstant: "/api/v1/query",
+	dashboardModel.QueryHintRange:   "/api/v1/query_range",
+}
+
+// prometheusQueryPath returns the path of the Prometheus API running the queries with the given hint.
+func prometheusQueryPath(hint dashboardModel.QueryHint) (string, error) {
+	path, ok := prometheusQueryPaths[hint.OrDefault()]
+	if !ok {
+		return "", fmt.Errorf("the %q queries are not supported by the Prometheus API", hint)
+	}
+	return path, nil
+}
+
+// resolveQueryPath sets the path of the requests sent to a Prometheus compatible datasource without a path.
+// The path is the one of the Prometheus API matching the query hint of the request, a range query by default.
+// The hint is removed from the request forwarded to the datasource. The requests with a path are forwarded as they are.
+func (h *httpProxy) resolveQueryPath(req *http.Request) error {
+	if !h.prometheusAPI || h.path != "/" {
+		return nil
+	}
+	query := req.URL.Query()
+	hint, err := dashboardModel.ParseQueryHint(query.Get(queryHintParam))
+	if err != nil {
+		return err
+	}
+	path, err := prometheusQueryPath(hint)
+	if err != nil {
+		return err
+	}
+	query.Del(queryHintParam)
+	req.URL.RawQuery = query.Encode()
+	h.path = path
+	return nil
+}
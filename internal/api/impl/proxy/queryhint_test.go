@@ -0,0 +1,121 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	dashboardModel "github.com/perses/perses/pkg/model/api/v1/dashboard"
+	datasourcev1 "github.com/perses/perses/pkg/model/api/v1/datasource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusQueryPath(t *testing.T) {
+	testSuite := []struct {
+		hint    dashboardModel.QueryHint
+		path    string
+		isError bool
+	}{
+		{hint: dashboardModel.QueryHintInstant, path: "/api/v1/query"},
+		{hint: dashboardModel.QueryHintRange, path: "/api/v1/query_range"},
+		{hint: "", path: "/api/v1/query_range"},
+		{hint: dashboardModel.QueryHintStream, isError: true},
+		{hint: "batch", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(string(test.hint), func(t *testing.T) {
+			path, err := prometheusQueryPath(test.hint)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.path, path)
+		})
+	}
+}
+
+func TestHTTPProxy_QueryHint(t *testing.T) {
+	// mock of the Prometheus API, returning the path and the query parameters it received.
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"path": r.URL.Path, "query": r.URL.Query()}))
+	}))
+	t.Cleanup(prometheus.Close)
+	spec := newPrometheusCompatibleDatasourceSpec(t, "PrometheusDatasource", prometheus.URL, datasourcev1.PrometheusQueryExtensions{})
+
+	testSuite := []struct {
+		title    string
+		path     string
+		rawQuery string
+		expected string
+	}{
+		{
+			title:    "instant hint",
+			rawQuery: "query=up&queryHint=instant",
+			expected: "/api/v1/query",
+		},
+		{
+			title:    "range hint",
+			rawQuery: "query=up&queryHint=range",
+			expected: "/api/v1/query_range",
+		},
+		{
+			title:    "no hint is a range query",
+			rawQuery: "query=up",
+			expected: "/api/v1/query_range",
+		},
+		{
+			title:    "path given by the request",
+			path:     "/api/v1/labels",
+			rawQuery: "queryHint=instant",
+			expected: "/api/v1/labels",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			p, err := newProxy("prometheus", "perses", spec, test.path, nil, nil, 0, nil, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus"+test.path+"?"+test.rawQuery, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, p.serve(echo.New().NewContext(req, rec)))
+			var received struct {
+				Path  string              `json:"path"`
+				Query map[string][]string `json:"query"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &received))
+			assert.Equal(t, test.expected, received.Path)
+			if len(test.path) == 0 {
+				// the hint is not forwarded to the datasource
+				assert.Equal(t, map[string][]string{"query": {"up"}}, received.Query)
+			}
+		})
+	}
+
+	for _, hint := range []string{"stream", "batch"} {
+		t.Run("rejected hint "+hint, func(t *testing.T) {
+			p, err := newProxy("prometheus", "perses", spec, "", nil, nil, 0, nil, nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/proxy/projects/perses/datasources/prometheus?query=up&queryHint="+hint, nil)
+			err = p.serve(echo.New().NewContext(req, httptest.NewRecorder()))
+			assert.ErrorIs(t, err, apiinterface.BadRequestError)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/spec/go/common"
+)
+
+// checkQueryTimeRange rejects the query when the time range between its parameters start and end is longer than
+// maxQueryTimeRange. The parameters are read from the URL and, for a form, from the body.
+// A query without start and end, or with values that cannot be parsed, is left to the datasource to handle.
+func (h *httpProxy) checkQueryTimeRange(req *http.Request) error {
+	if h.maxQueryTimeRange <= 0 {
+		return nil
+	}
+	params, err := queryParams(req)
+	if err != nil {
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("unable to read the query parameters: %s", err))
+	}
+	start, startErr := parseQueryTime(params.Get("start"))
+	end, endErr := parseQueryTime(params.Get("end"))
+	if startErr != nil || endErr != nil {
+		return nil
+	}
+	if timeRange := end.Sub(start); timeRange > h.maxQueryTimeRange {
+		return apiinterface.HandleBadRequestError(fmt.Sprintf("the time range of the query (%s) exceeds the maximum allowed for the datasource %q (%s)",
+			common.Duration(timeRange), h.datasourceName, common.Duration(h.maxQueryTimeRange)))
+	}
+	return nil
+}
+
+// queryParams returns the parameters of the URL, merged with the ones of the body when it is a form.
+// The body is restored, so it can still be sent to the datasource.
+func queryParams(req *http.Request) (url.Values, error) {
+	params := req.URL.Query()
+	if req.Body == nil || req.Method != http.MethodPost {
+		return params, nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType)); err != nil || mediaType != echo.MIMEApplicationForm {
+		return params, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range form {
+		params[k] = append(v, params[k]...)
+	}
+	return params, nil
+}
+
+// parseQueryTime parses a time the way the Prometheus HTTP API does: either a unix timestamp in seconds or an RFC3339 date.
+func parseQueryTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if t, err := strconv.ParseFloat(value, 64); err == nil {
+		s, ns := math.Modf(t)
+		return time.Unix(int64(s), int64(math.Round(ns*float64(time.Second)))), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,92 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProxy_checkQueryTimeRange(t *testing.T) {
+	testSuite := []struct {
+		title       string
+		method      string
+		query       string
+		form        string
+		expectError bool
+	}{
+		{
+			title:  "within the limit",
+			method: http.MethodGet,
+			query:  "query=up&start=1700000000&end=1700003600&step=15",
+		},
+		{
+			title:  "at the limit",
+			method: http.MethodGet,
+			query:  "query=up&start=2024-01-01T00:00:00Z&end=2024-01-31T00:00:00Z",
+		},
+		{
+			title:       "over the limit",
+			method:      http.MethodGet,
+			query:       "query=up&start=2024-01-01T00:00:00Z&end=2024-01-31T00:00:00.5Z",
+			expectError: true,
+		},
+		{
+			title:       "over the limit in a form",
+			method:      http.MethodPost,
+			form:        "query=up&start=1600000000&end=1700000000",
+			expectError: true,
+		},
+		{
+			title:  "instant query",
+			method: http.MethodGet,
+			query:  "query=up&time=1700000000",
+		},
+		{
+			title:  "unparsable time left to the datasource",
+			method: http.MethodGet,
+			query:  "query=up&start=yesterday&end=1700000000",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			h := &httpProxy{datasourceName: "prometheus", maxQueryTimeRange: 30 * 24 * time.Hour}
+			req := httptest.NewRequest(test.method, "/api/v1/query_range?"+test.query, strings.NewReader(test.form))
+			if len(test.form) > 0 {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			}
+			err := h.checkQueryTimeRange(req)
+			if test.expectError {
+				assert.True(t, errors.Is(err, apiinterface.BadRequestError))
+				assert.Contains(t, err.Error(), "exceeds the maximum allowed")
+			} else {
+				assert.NoError(t, err)
+			}
+			// The body must still be readable to be sent to the datasource.
+			body, readErr := io.ReadAll(req.Body)
+			require.NoError(t, readErr)
+			assert.Equal(t, test.form, string(body))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryinspector
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	"github.com/perses/perses/internal/api/route"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/perses/spec/go/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+type endpoint struct {
+	audit queryaudit.Service
+	authz authorization.Authorization
+	now   func() time.Time
+}
+
+func New(audit queryaudit.Service, authz authorization.Authorization) route.Endpoint {
+	return &endpoint{
+		audit: audit,
+		authz: authz,
+		now:   time.Now,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	g.Group("/admin").GET("/query-audit", e.list, false)
+}
+
+func (e *endpoint) list(ctx echo.Context) error {
+	if !e.authz.HasPermission(ctx, role.WildcardAction, v1.WildcardProject, role.WildcardScope) {
+		return apiinterface.HandleForbiddenError("only administrators can list the datasource queries")
+	}
+	q, err := e.parseQuery(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := e.audit.List(q)
+	if err != nil {
+		logrus.WithError(err).Error("unable to list the datasource queries")
+		return apiinterface.InternalError
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) parseQuery(ctx echo.Context) (*queryaudit.ListQuery, error) {
+	q := &queryaudit.ListQuery{
+		Query: queryaudit.Query{Datasource: ctx.QueryParam("datasource")},
+		Page:  1,
+		Size:  defaultPageSize,
+	}
+	if since := ctx.QueryParam("since"); len(since) > 0 {
+		d, err := common.ParseDuration(since)
+		if err != nil {
+			return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid since %q: %s", since, err))
+		}
+		q.Since = e.now().Add(-time.Duration(d))
+	}
+	if page := ctx.QueryParam("page"); len(page) > 0 {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid page %q, it must be a positive integer", page))
+		}
+		q.Page = value
+	}
+	if size := ctx.QueryParam("size"); len(size) > 0 {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < 1 || value > maxPageSize {
+			return nil, apiinterface.HandleBadRequestError(fmt.Sprintf("invalid size %q, it must be an integer between 1 and %d", size, maxPageSize))
+		}
+		q.Size = value
+	}
+	return q, nil
+}
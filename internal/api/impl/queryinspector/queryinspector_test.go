@@ -0,0 +1,117 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryinspector
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiinterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	v1Role "github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthorization struct {
+	authorization.Authorization
+	isAdmin bool
+}
+
+func (f *fakeAuthorization) HasPermission(_ echo.Context, _ v1Role.Action, _ string, _ v1Role.Scope) bool {
+	return f.isAdmin
+}
+
+type fakeQueryAudit struct {
+	queryaudit.Service
+	query *queryaudit.ListQuery
+}
+
+func (f *fakeQueryAudit) List(q *queryaudit.ListQuery) (*queryaudit.List, error) {
+	f.query = q
+	return &queryaudit.List{Page: q.Page, Size: q.Size}, nil
+}
+
+func TestList(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	testSuite := []struct {
+		title   string
+		url     string
+		isAdmin bool
+		query   *queryaudit.ListQuery
+		err     error
+	}{
+		{
+			title:   "default pagination",
+			url:     "/api/admin/query-audit",
+			isAdmin: true,
+			query:   &queryaudit.ListQuery{Page: 1, Size: defaultPageSize},
+		},
+		{
+			title:   "every parameter",
+			url:     "/api/admin/query-audit?datasource=prometheus&since=1h&page=3&size=20",
+			isAdmin: true,
+			query:   &queryaudit.ListQuery{Query: queryaudit.Query{Datasource: "prometheus"}, Since: now.Add(-time.Hour), Page: 3, Size: 20},
+		},
+		{
+			title:   "invalid since",
+			url:     "/api/admin/query-audit?since=yesterday",
+			isAdmin: true,
+			err:     apiinterface.BadRequestError,
+		},
+		{
+			title:   "invalid page",
+			url:     "/api/admin/query-audit?page=0",
+			isAdmin: true,
+			err:     apiinterface.BadRequestError,
+		},
+		{
+			title:   "page size too large",
+			url:     "/api/admin/query-audit?size=5000",
+			isAdmin: true,
+			err:     apiinterface.BadRequestError,
+		},
+		{
+			title: "not an administrator",
+			url:   "/api/admin/query-audit",
+			err:   apiinterface.ForbiddenError,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			audit := &fakeQueryAudit{}
+			ept := New(audit, &fakeAuthorization{isAdmin: test.isAdmin}).(*endpoint)
+			ept.now = func() time.Time { return now }
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, test.url, nil), rec)
+			err := ept.list(ctx)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				assert.Nil(t, audit.query)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.query, audit.query)
+			result := &queryaudit.List{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), result))
+			assert.Equal(t, test.query.Page, result.Page)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/brunoga/deep"
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	"github.com/perses/perses/internal/api/crypto"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type service struct {
+	apikey.Service
+	dao   apikey.DAO
+	authz authorization.Authorization
+}
+
+func NewService(dao apikey.DAO, authz authorization.Authorization) apikey.Service {
+	return &service{
+		dao:   dao,
+		authz: authz,
+	}
+}
+
+func (s *service) Create(ctx echo.Context, entity *v1.APIKey) (*v1.PublicAPIKey, error) {
+	copyEntity, err := deep.Copy(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	if err := s.checkScopes(ctx, copyEntity.Metadata.Project, copyEntity.Spec); err != nil {
+		return nil, err
+	}
+	return s.create(copyEntity)
+}
+
+// checkScopes verifies the user holds every permission granted by the scopes of the key in the project.
+// Otherwise, a user allowed to create API keys could get a key with more permissions than their own.
+func (s *service) checkScopes(ctx echo.Context, project string, spec v1.APIKeySpec) error {
+	if !s.authz.IsEnabled() {
+		return nil
+	}
+	for _, permission := range spec.Permissions() {
+		for _, action := range permission.Actions {
+			for _, scope := range permission.Scopes {
+				if !s.authz.HasPermission(ctx, action, project, scope) {
+					return apiInterface.HandleForbiddenError(fmt.Sprintf("cannot grant the scope '%s:%s', you don't have this permission in '%s' project", action, scope, project))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *service) create(entity *v1.APIKey) (*v1.PublicAPIKey, error) {
+	// Update the time contains in the entity
+	entity.Metadata.CreateNow()
+	// The secret is always generated by the server, whatever the hash sent in the request.
+	key, hashedSecret, err := crypto.GenerateAPIKey(entity.Metadata.Project, entity.Metadata.Name)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to generate the API key")
+		return nil, apiInterface.InternalError
+	}
+	entity.Spec.HashedSecret = hashedSecret
+	if err := s.dao.Create(entity); err != nil {
+		return nil, err
+	}
+	result := v1.NewPublicAPIKey(entity)
+	result.Spec.Key = key
+	return result, nil
+}
+
+func (s *service) Update(ctx echo.Context, entity *v1.APIKey, parameters apiInterface.Parameters) (*v1.PublicAPIKey, error) {
+	copyEntity, err := deep.Copy(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	if err := s.checkScopes(ctx, parameters.Project, copyEntity.Spec); err != nil {
+		return nil, err
+	}
+	return s.update(copyEntity, parameters)
+}
+
+func (s *service) update(entity *v1.APIKey, parameters apiInterface.Parameters) (*v1.PublicAPIKey, error) {
+	if entity.Metadata.Name != parameters.Name {
+		logrus.Debugf("name in APIKey %q and name from the http request: %q don't match", entity.Metadata.Name, parameters.Name)
+		return nil, apiInterface.HandleBadRequestError("metadata.name and the name in the http path request don't match")
+	}
+	if len(entity.Metadata.Project) == 0 {
+		entity.Metadata.Project = parameters.Project
+	} else if entity.Metadata.Project != parameters.Project {
+		logrus.Debugf("project in APIKey %q and project from the http request %q don't match", entity.Metadata.Project, parameters.Project)
+		return nil, apiInterface.HandleBadRequestError("metadata.project and the project name in the http path request don't match")
+	}
+	// find the previous version of the APIKey
+	oldEntity, err := s.dao.Get(parameters.Project, parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	entity.Metadata.Update(oldEntity.Metadata)
+	// The secret cannot be changed by an update. The key must be deleted and created again to get a new secret.
+	entity.Spec.HashedSecret = oldEntity.Spec.HashedSecret
+	if updateErr := s.dao.Update(entity); updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to perform the update of the APIKey %q, something wrong with the database", entity.Metadata.Name)
+		return nil, updateErr
+	}
+	return v1.NewPublicAPIKey(entity), nil
+}
+
+func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	return s.dao.Delete(parameters.Project, parameters.Name)
+}
+
+func (s *service) Get(parameters apiInterface.Parameters) (*v1.PublicAPIKey, error) {
+	key, err := s.dao.Get(parameters.Project, parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	return v1.NewPublicAPIKey(key), nil
+}
+
+func (s *service) List(q *apikey.Query, params apiInterface.Parameters) ([]*v1.PublicAPIKey, error) {
+	query, err := manageQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	l, err := s.dao.List(query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.PublicAPIKey, 0, len(l))
+	for _, key := range l {
+		result = append(result, v1.NewPublicAPIKey(key))
+	}
+	return result, nil
+}
+
+func (s *service) RawList(_ *apikey.Query, _ apiInterface.Parameters) ([]json.RawMessage, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *service) MetadataList(q *apikey.Query, params apiInterface.Parameters) ([]api.Entity, error) {
+	query, err := manageQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.dao.MetadataList(query)
+}
+
+func (s *service) RawMetadataList(q *apikey.Query, params apiInterface.Parameters) ([]json.RawMessage, error) {
+	query, err := manageQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.dao.RawMetadataList(query)
+}
+
+func manageQuery(q *apikey.Query, params apiInterface.Parameters) (*apikey.Query, error) {
+	// Query is copied because it can be modified by the toolbox.go: listWhenPermissionIsActivated(...) and need to `q` need to keep initial value
+	query, err := deep.Copy(q)
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy the query: %w", err)
+	}
+	if len(query.Project) == 0 {
+		query.Project = params.Project
+	}
+	return query, nil
+}
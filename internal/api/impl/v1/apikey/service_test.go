@@ -0,0 +1,123 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	databaseFile "github.com/perses/perses/internal/api/database/file"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuthorization struct {
+	authorization.Authorization
+	// permissions lists the actions granted on each scope of the project "perses".
+	permissions map[role.Scope][]role.Action
+}
+
+func (a *fakeAuthorization) IsEnabled() bool {
+	return true
+}
+
+func (a *fakeAuthorization) HasPermission(_ echo.Context, requestAction role.Action, requestProject string, requestScope role.Scope) bool {
+	if requestProject != "perses" {
+		return false
+	}
+	for _, action := range a.permissions[requestScope] {
+		if action == requestAction {
+			return true
+		}
+	}
+	return false
+}
+
+func newAPIKey(scopes ...string) *v1.APIKey {
+	return &v1.APIKey{
+		Kind:     v1.KindAPIKey,
+		Metadata: *v1.NewProjectMetadata("perses", "ci"),
+		Spec:     v1.APIKeySpec{Scopes: scopes},
+	}
+}
+
+func TestCreateChecksScopes(t *testing.T) {
+	s := NewService(NewDAO(&databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}), &fakeAuthorization{
+		permissions: map[role.Scope][]role.Action{
+			role.APIKeyScope:    {role.CreateAction},
+			role.DashboardScope: {role.ReadAction, role.CreateAction},
+		},
+	})
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	testSuite := []struct {
+		title  string
+		scopes []string
+		err    bool
+	}{
+		{
+			title:  "scopes held by the user",
+			scopes: []string{"read:Dashboard", "create:Dashboard"},
+		},
+		{
+			title:  "action not held by the user",
+			scopes: []string{"read:Dashboard", "delete:Dashboard"},
+			err:    true,
+		},
+		{
+			title:  "scope not held by the user",
+			scopes: []string{"read:Variable"},
+			err:    true,
+		},
+		{
+			title:  "wildcard",
+			scopes: []string{"*:*"},
+			err:    true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := s.Create(ctx, newAPIKey(test.scopes...))
+			if test.err {
+				assert.True(t, errors.Is(err, apiInterface.ForbiddenError))
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, result.Spec.Key)
+			assert.NoError(t, s.Delete(ctx, apiInterface.Parameters{Project: "perses", Name: "ci"}))
+		})
+	}
+}
+
+func TestUpdateChecksScopes(t *testing.T) {
+	s := NewService(NewDAO(&databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}), &fakeAuthorization{
+		permissions: map[role.Scope][]role.Action{
+			role.DashboardScope: {role.ReadAction},
+		},
+	})
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPut, "/", nil), httptest.NewRecorder())
+	parameters := apiInterface.Parameters{Project: "perses", Name: "ci"}
+	_, err := s.Create(ctx, newAPIKey("read:Dashboard"))
+	assert.NoError(t, err)
+	_, err = s.Update(ctx, newAPIKey("read:Dashboard", "update:Dashboard"), parameters)
+	assert.True(t, errors.Is(err, apiInterface.ForbiddenError))
+	_, err = s.Update(ctx, newAPIKey("read:Dashboard"), parameters)
+	assert.NoError(t, err)
+}
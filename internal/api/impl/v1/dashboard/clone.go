@@ -0,0 +1,97 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/toolbox"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+const cloneSuffix = "-copy"
+
+type cloneRequest struct {
+	// Name is the name of the new dashboard. When empty, the name of the original dashboard suffixed by "-copy" is used.
+	Name string `json:"name"`
+}
+
+type cloneEndpoint struct {
+	service       dashboard.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
+}
+
+// NewCloneEndpoint returns the endpoint creating a dashboard from a copy of an existing one.
+func NewCloneEndpoint(service dashboard.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+	return &cloneEndpoint{
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (e *cloneEndpoint) CollectRoutes(g *route.Group) {
+	if e.readonly {
+		return
+	}
+	g.POST(fmt.Sprintf("/%s/:%s/%s/:%s/clone", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamName), e.Clone, false)
+}
+
+func (e *cloneEndpoint) Clone(ctx echo.Context) error {
+	body := &cloneRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiInterface.HandleBadRequestError(err.Error())
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if err := e.checkPermission(ctx, parameters.Project); err != nil {
+		return err
+	}
+	name := body.Name
+	if len(name) == 0 {
+		name = parameters.Name + cloneSuffix
+	}
+	if !e.caseSensitive {
+		name = strings.ToLower(name)
+	}
+	clone, err := e.service.Clone(ctx, parameters, name)
+	if err != nil {
+		return err
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, clone.Metadata.Project, utils.PathDashboard, clone.Metadata.Name))
+	return ctx.JSON(http.StatusCreated, clone)
+}
+
+// checkPermission verifies the user can read the original dashboard and create the clone in the same project.
+func (e *cloneEndpoint) checkPermission(ctx echo.Context, project string) error {
+	if !e.authz.IsEnabled() {
+		return nil
+	}
+	for _, action := range []role.Action{role.ReadAction, role.CreateAction} {
+		if !e.authz.HasPermission(ctx, action, project, role.DashboardScope) {
+			return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", action, project, role.DashboardScope))
+		}
+	}
+	return nil
+}
@@ -29,12 +29,14 @@ import (
 
 type endpoint struct {
 	toolbox  toolbox.Toolbox[*v1.Dashboard, *dashboard.Query]
+	authz    authorization.Authorization
 	readonly bool
 }
 
 func NewEndpoint(service dashboard.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
 	return &endpoint{
 		toolbox:  toolbox.New[*v1.Dashboard, *v1.Dashboard, *dashboard.Query](service, authz, v1.KindDashboard, caseSensitive),
+		authz:    authz,
 		readonly: readonly,
 	}
 }
@@ -73,5 +75,7 @@ func (e *endpoint) Get(ctx echo.Context) error {
 
 func (e *endpoint) List(ctx echo.Context) error {
 	q := &dashboard.Query{}
+	// Without a username (authorization disabled or anonymous request), the favorites are not flagged.
+	q.User, _ = e.authz.GetUsername(ctx)
 	return e.toolbox.List(ctx, q)
 }
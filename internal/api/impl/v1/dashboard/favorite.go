@@ -0,0 +1,86 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// partialDashboard is the metadata of a dashboard flagged as a favorite of the user listing the dashboards.
+type partialDashboard struct {
+	v1.PartialProjectEntity
+	Favorited bool `json:"favorited,omitempty" yaml:"favorited,omitempty"`
+}
+
+// favorites returns the dashboards marked as favorite by the user, indexed by favoriteKey.
+// Failing to get them doesn't fail the list: the dashboards are just not flagged.
+func (s *service) favorites(user string) map[string]bool {
+	if len(user) == 0 {
+		return nil
+	}
+	list, err := s.favoriteDAO.List(user)
+	if err != nil {
+		logrus.WithError(err).Errorf("unable to get the favorite dashboards of the user %q", user)
+		return nil
+	}
+	result := make(map[string]bool, len(list))
+	for _, favorite := range list {
+		result[favoriteKey(favorite.Spec.ProjectName, favorite.Spec.DashboardName)] = true
+	}
+	return result
+}
+
+func favoriteKey(project string, dashboard string) string {
+	return project + "/" + dashboard
+}
+
+// flagPartialFavorites replaces the metadata of the dashboards marked as favorite by a partialDashboard.
+func flagPartialFavorites(favorites map[string]bool, list []api.Entity) []api.Entity {
+	if len(favorites) == 0 {
+		return list
+	}
+	for i, entity := range list {
+		partial, ok := entity.(*v1.PartialProjectEntity)
+		if ok && favorites[favoriteKey(partial.Metadata.Project, partial.Metadata.Name)] {
+			list[i] = &partialDashboard{PartialProjectEntity: *partial, Favorited: true}
+		}
+	}
+	return list
+}
+
+// flagRawFavorites adds the field "favorited" to the dashboards of the list marked as favorite.
+func flagRawFavorites(favorites map[string]bool, list []json.RawMessage) []json.RawMessage {
+	if len(favorites) == 0 {
+		return list
+	}
+	for i, row := range list {
+		if !favorites[favoriteKey(gjson.GetBytes(row, "metadata.project").String(), gjson.GetBytes(row, "metadata.name").String())] {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(row, &fields); err != nil {
+			continue
+		}
+		fields["favorited"] = json.RawMessage("true")
+		if data, err := json.Marshal(fields); err == nil {
+			list[i] = data
+		}
+	}
+	return list
+}
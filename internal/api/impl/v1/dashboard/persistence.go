@@ -20,6 +20,7 @@ import (
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
 	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/tidwall/gjson"
 )
 
 type dao struct {
@@ -43,6 +44,10 @@ func (d *dao) Update(entity *v1.Dashboard) error {
 	return d.client.Upsert(entity)
 }
 
+func (d *dao) UpdateIfVersion(entity *v1.Dashboard, version uint64) error {
+	return d.client.UpdateIfVersion(entity, version)
+}
+
 func (d *dao) Delete(project string, name string) error {
 	return d.client.Delete(d.kind, v1.NewProjectMetadata(project, name))
 }
@@ -51,31 +56,104 @@ func (d *dao) DeleteAll(project string) error {
 	return d.client.DeleteByQuery(&dashboard.Query{Project: project})
 }
 
+// Get returns the dashboard, unless it is in the trash.
 func (d *dao) Get(project string, name string) (*v1.Dashboard, error) {
+	entity, err := d.get(project, name)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Metadata.DeletedAt != nil {
+		return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+// GetDeleted returns the dashboard only if it is in the trash.
+func (d *dao) GetDeleted(project string, name string) (*v1.Dashboard, error) {
+	entity, err := d.get(project, name)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Metadata.DeletedAt == nil {
+		return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+func (d *dao) get(project string, name string) (*v1.Dashboard, error) {
 	entity := &v1.Dashboard{}
 	return entity, d.client.Get(d.kind, v1.NewProjectMetadata(project, name), entity)
 }
 
 func (d *dao) List(q *dashboard.Query) ([]*v1.Dashboard, error) {
+	list, err := d.list(q)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.Dashboard, 0, len(list))
+	for _, entity := range list {
+		if entity.Metadata.DeletedAt == nil {
+			result = append(result, entity)
+		}
+	}
+	return result, nil
+}
+
+func (d *dao) ListDeleted(q *dashboard.Query) ([]*v1.Dashboard, error) {
+	list, err := d.list(q)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.Dashboard, 0, len(list))
+	for _, entity := range list {
+		if entity.Metadata.DeletedAt != nil {
+			result = append(result, entity)
+		}
+	}
+	return result, nil
+}
+
+func (d *dao) list(q *dashboard.Query) ([]*v1.Dashboard, error) {
 	var result []*v1.Dashboard
 	err := d.client.Query(q, &result)
 	return result, err
 }
 
 func (d *dao) RawList(q *dashboard.Query) ([]json.RawMessage, error) {
-	return d.client.RawQuery(q)
+	list, err := d.client.RawQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	return withoutRawDeleted(list), nil
 }
 
 func (d *dao) MetadataList(q *dashboard.Query) ([]api.Entity, error) {
-	var list []*v1.PartialProjectEntity
+	var list []*v1.PartialDashboard
 	err := d.client.Query(q, &list)
 	result := make([]api.Entity, 0, len(list))
 	for _, el := range list {
-		result = append(result, el)
+		if el.Metadata.DeletedAt == nil {
+			result = append(result, el)
+		}
 	}
 	return result, err
 }
 
 func (d *dao) RawMetadataList(q *dashboard.Query) ([]json.RawMessage, error) {
-	return d.client.RawMetadataQuery(q, d.kind)
+	list, err := d.client.RawMetadataQuery(q, d.kind)
+	if err != nil {
+		return nil, err
+	}
+	return withoutRawDeleted(list), nil
+}
+
+// withoutRawDeleted removes the dashboards in the trash from the list, without decoding them completely.
+func withoutRawDeleted(list []json.RawMessage) []json.RawMessage {
+	result := make([]json.RawMessage, 0, len(list))
+	for _, row := range list {
+		if !gjson.GetBytes(row, "metadata.deletedAt").Exists() {
+			result = append(result, row)
+		}
+	}
+	return result
 }
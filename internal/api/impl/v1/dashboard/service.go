@@ -16,75 +16,208 @@ package dashboard
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/brunoga/deep"
 	"github.com/labstack/echo/v4"
+	"github.com/perses/common/set"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
 	"github.com/perses/perses/internal/api/interface/v1/globalvariable"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
+	"github.com/perses/perses/internal/api/jsonpatch"
 	"github.com/perses/perses/internal/api/plugin/schema"
+	"github.com/perses/perses/internal/api/utils"
 	"github.com/perses/perses/internal/api/validate"
 	"github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	dashboardModel "github.com/perses/perses/pkg/model/api/v1/dashboard"
+	dashboardSpec "github.com/perses/spec/go/dashboard"
 	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
 )
 
 type service struct {
 	dashboard.Service
 	dao                 dashboard.DAO
+	history             dashboardhistory.Service
+	favoriteDAO         userfavorite.DAO
 	globalVarDAO        globalvariable.DAO
 	projectVarDAO       variable.DAO
+	templatePolicyDAO   templatepolicy.DAO
+	templateBindingDAO  templatepolicybinding.DAO
 	sch                 schema.Schema
 	isDatasourceDisable bool
 	isVariableDisable   bool
 	customRules         []*config.CustomLintRule
+	minRefreshInterval  time.Duration
 }
 
-func NewService(cfg config.Config, dao dashboard.DAO, globalVarDAO globalvariable.DAO, projectVarDAO variable.DAO, sch schema.Schema) dashboard.Service {
+func NewService(cfg config.Config, dao dashboard.DAO, history dashboardhistory.Service, favoriteDAO userfavorite.DAO, globalVarDAO globalvariable.DAO, projectVarDAO variable.DAO,
+	templatePolicyDAO templatepolicy.DAO, templateBindingDAO templatepolicybinding.DAO, sch schema.Schema) dashboard.Service {
 	return &service{
 		dao:                 dao,
+		history:             history,
+		favoriteDAO:         favoriteDAO,
 		globalVarDAO:        globalVarDAO,
 		projectVarDAO:       projectVarDAO,
+		templatePolicyDAO:   templatePolicyDAO,
+		templateBindingDAO:  templateBindingDAO,
 		sch:                 sch,
 		isDatasourceDisable: cfg.Datasource.DisableLocal,
 		isVariableDisable:   cfg.Variable.DisableLocal,
 		customRules:         cfg.Dashboard.CustomLintRules,
+		minRefreshInterval:  time.Duration(cfg.API.MinRefreshInterval),
 	}
 }
 
-func (s *service) Create(_ echo.Context, entity *v1.Dashboard) (*v1.Dashboard, error) {
+func (s *service) Create(ctx echo.Context, entity *v1.Dashboard) (*v1.Dashboard, error) {
 	copyEntity, err := deep.Copy(entity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy entity: %w", err)
 	}
-	return s.create(copyEntity)
+	return s.create(ctx, copyEntity)
 }
 
-func (s *service) create(entity *v1.Dashboard) (*v1.Dashboard, error) {
+func (s *service) create(ctx echo.Context, entity *v1.Dashboard) (*v1.Dashboard, error) {
 	// verify this new dashboard passes the validation
 	if err := s.Validate(entity); err != nil {
 		return nil, err
 	}
+	if err := s.validateTemplatePolicies(entity); err != nil {
+		return nil, err
+	}
+
+	// A dashboard in the trash doesn't prevent the creation of a new one with the same name, it is deleted instead.
+	trashed, err := s.dao.GetDeleted(entity.Metadata.Project, entity.Metadata.Name)
+	if err != nil && !databaseModel.IsKeyNotFound(err) {
+		return nil, err
+	}
+	if trashed != nil {
+		if purgeErr := s.purge(trashed); purgeErr != nil {
+			return nil, purgeErr
+		}
+	}
 
 	// Update the time contains in the entity
 	entity.Metadata.CreateNow()
+	entity.Favorited = false
+	entity.Metadata.ResourceVersion = resourceVersion(entity.Metadata)
 	if err := s.dao.Create(entity); err != nil {
 		return nil, err
 	}
+	s.recordHistory(ctx, entity)
 	return entity, nil
 }
 
-func (s *service) Update(_ echo.Context, entity *v1.Dashboard, parameters apiInterface.Parameters) (*v1.Dashboard, error) {
+func (s *service) Clone(ctx echo.Context, parameters apiInterface.Parameters, name string) (*v1.Dashboard, error) {
+	if err := common.ValidateID(name); err != nil {
+		return nil, apiInterface.HandleBadRequestError(err.Error())
+	}
+	original, err := s.dao.Get(parameters.Project, parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := deep.Copy(original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	clone.Metadata.Name = name
+	clone.Metadata.ResourceVersion = ""
+	rewriteSelfLinks(&clone.Spec, parameters.Project, original.Metadata.Name, name)
+	return s.create(ctx, clone)
+}
+
+// rewriteSelfLinks makes the links pointing to the dashboard "from" point to the dashboard "to" instead,
+// so the links of a cloned dashboard referencing the original one reference the clone.
+func rewriteSelfLinks(spec *dashboardSpec.Spec, project string, from string, to string) {
+	pattern := regexp.MustCompile(fmt.Sprintf(`(/%s/%s/%s/)%s([/?#]|$)`, utils.PathProject, regexp.QuoteMeta(project), utils.PathDashboard, regexp.QuoteMeta(from)))
+	replacement := fmt.Sprintf("${1}%s${2}", to)
+	for i := range spec.Links {
+		spec.Links[i].URL = pattern.ReplaceAllString(spec.Links[i].URL, replacement)
+	}
+	for _, panel := range spec.Panels {
+		if panel == nil {
+			continue
+		}
+		for i := range panel.Spec.Links {
+			panel.Spec.Links[i].URL = pattern.ReplaceAllString(panel.Spec.Links[i].URL, replacement)
+		}
+	}
+}
+
+func (s *service) Update(ctx echo.Context, entity *v1.Dashboard, parameters apiInterface.Parameters) (*v1.Dashboard, error) {
 	copyEntity, err := deep.Copy(entity)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy entity: %w", err)
 	}
-	return s.update(copyEntity, parameters)
+	return s.update(ctx, copyEntity, parameters)
 }
 
-func (s *service) update(entity *v1.Dashboard, parameters apiInterface.Parameters) (*v1.Dashboard, error) {
+func (s *service) update(ctx echo.Context, entity *v1.Dashboard, parameters apiInterface.Parameters) (*v1.Dashboard, error) {
+	oldEntity, err := s.validateUpdate(entity, parameters)
+	if err != nil {
+		return nil, err
+	}
+	// A dashboard saved before the resource versions were introduced doesn't have one, it gets one with this update.
+	if len(entity.Metadata.ResourceVersion) == 0 && len(oldEntity.Metadata.ResourceVersion) > 0 {
+		logrus.Debugf("the update of the dashboard %q doesn't provide the resource version", parameters.Name)
+		return nil, apiInterface.HandlePreconditionRequiredError(fmt.Sprintf("metadata.resourceVersion is required to update the dashboard %q, get the latest version and retry", parameters.Name))
+	}
+	entity.Metadata.Update(oldEntity.Metadata)
+	entity.Metadata.ResourceVersion = resourceVersion(entity.Metadata)
+	entity.Favorited = false
+	// The resource version matches the dashboard read, the database verifies nobody modified it since.
+	updateErr := s.dao.UpdateIfVersion(entity, oldEntity.Metadata.Version)
+	if databaseModel.IsVersionMismatch(updateErr) {
+		logrus.Debugf("the dashboard %q has been modified during its update", parameters.Name)
+		return nil, apiInterface.HandleVersionConflictError(fmt.Sprintf("the dashboard %q has been modified since the resource version %q, get the latest version and retry", parameters.Name, resourceVersion(oldEntity.Metadata)))
+	}
+	if updateErr != nil {
+		logrus.WithError(updateErr).Errorf("unable to perform the update of the dashboard %q, something wrong with the database", entity.Metadata.Name)
+		return nil, updateErr
+	}
+	s.recordHistory(ctx, entity)
+	return entity, nil
+}
+
+// recordHistory appends the dashboard to its history. The dashboard is already saved at this point,
+// so a failure is only logged rather than failing the request.
+func (s *service) recordHistory(ctx echo.Context, entity *v1.Dashboard) {
+	if err := s.history.Record(ctx, entity); err != nil {
+		logrus.WithError(err).Errorf("unable to record the version %d of the dashboard %q in its history", entity.Metadata.Version, entity.Metadata.Name)
+	}
+}
+
+func (s *service) DiffUpdate(_ echo.Context, entity *v1.Dashboard, parameters apiInterface.Parameters) ([]jsonpatch.Operation, error) {
+	copyEntity, err := deep.Copy(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy entity: %w", err)
+	}
+	oldEntity, err := s.validateUpdate(copyEntity, parameters)
+	if err != nil {
+		return nil, err
+	}
+	// Nothing is persisted, so the fields managed by the server keep their current value and are not part of the diff.
+	copyEntity.Metadata.CreatedAt = oldEntity.Metadata.CreatedAt
+	copyEntity.Metadata.UpdatedAt = oldEntity.Metadata.UpdatedAt
+	copyEntity.Metadata.Version = oldEntity.Metadata.Version
+	copyEntity.Metadata.ResourceVersion = oldEntity.Metadata.ResourceVersion
+	return jsonpatch.Diff(oldEntity, copyEntity)
+}
+
+// validateUpdate verifies the dashboard can replace the current one and returns the current one.
+func (s *service) validateUpdate(entity *v1.Dashboard, parameters apiInterface.Parameters) (*v1.Dashboard, error) {
 	if entity.Metadata.Name != parameters.Name {
 		logrus.Debugf("name in dashboard %q and name from the http request %q don't match", entity.Metadata.Name, parameters.Name)
 		return nil, apiInterface.HandleBadRequestError("metadata.name and the name in the http path request don't match")
@@ -100,22 +233,117 @@ func (s *service) update(entity *v1.Dashboard, parameters apiInterface.Parameter
 	if err := s.Validate(entity); err != nil {
 		return nil, err
 	}
+	if err := s.validateTemplatePolicies(entity); err != nil {
+		return nil, err
+	}
 
 	// find the previous version of the dashboard
 	oldEntity, err := s.dao.Get(parameters.Project, parameters.Name)
 	if err != nil {
 		return nil, err
 	}
-	entity.Metadata.Update(oldEntity.Metadata)
+	// When provided, the resource version must match: the dashboard must not have been modified since it has been read.
+	if len(entity.Metadata.ResourceVersion) > 0 && entity.Metadata.ResourceVersion != oldEntity.Metadata.ResourceVersion {
+		logrus.Debugf("resource version %q of the dashboard %q doesn't match the current one %q", entity.Metadata.ResourceVersion, parameters.Name, oldEntity.Metadata.ResourceVersion)
+		return nil, apiInterface.HandleVersionConflictError(fmt.Sprintf("the dashboard %q has been modified since the resource version %q, get the latest version and retry", parameters.Name, entity.Metadata.ResourceVersion))
+	}
+	return oldEntity, nil
+}
+
+// resourceVersion computes the resource version of the dashboard. As the version of the metadata, it changes with
+// every update.
+func resourceVersion(metadata v1.DashboardMetadata) string {
+	return strconv.FormatUint(metadata.Version, 10)
+}
+
+// validateTemplatePolicies verifies the dashboard complies with every template policy bound to its project.
+// All the violations are reported at once, so the user doesn't have to fix them one by one.
+func (s *service) validateTemplatePolicies(entity *v1.Dashboard) error {
+	bindings, err := s.templateBindingDAO.List(&templatepolicybinding.Query{})
+	if err != nil {
+		return err
+	}
+	var violations []string
+	for _, binding := range bindings {
+		if !binding.Spec.Has(entity.Metadata.Project) {
+			continue
+		}
+		policy, getErr := s.templatePolicyDAO.Get(binding.Spec.Policy)
+		if getErr != nil {
+			if databaseModel.IsKeyNotFound(getErr) {
+				logrus.Warnf("template policy %q referenced by the binding %q doesn't exist", binding.Spec.Policy, binding.Metadata.Name)
+				continue
+			}
+			return getErr
+		}
+		for _, violation := range policy.Spec.Check(entity.Metadata.Labels) {
+			violations = append(violations, fmt.Sprintf("template policy %q: %s", policy.Metadata.Name, violation))
+		}
+	}
+	if len(violations) > 0 {
+		return apiInterface.HandleUnprocessableEntityError(fmt.Sprintf("dashboard doesn't comply with the template policies of the project %q: %s", entity.Metadata.Project, strings.Join(violations, "; ")))
+	}
+	return nil
+}
+
+// Delete moves the dashboard to the trash. It is permanently deleted by PurgeTrash, once the retention is over.
+func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
+	entity, err := s.dao.Get(parameters.Project, parameters.Name)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	entity.Metadata.DeletedAt = &now
+	return s.dao.Update(entity)
+}
+
+func (s *service) ListTrash(project string) ([]*v1.Dashboard, error) {
+	return s.dao.ListDeleted(&dashboard.Query{Project: project})
+}
+
+func (s *service) Restore(parameters apiInterface.Parameters) (*v1.Dashboard, error) {
+	entity, err := s.dao.GetDeleted(parameters.Project, parameters.Name)
+	if err != nil {
+		return nil, err
+	}
+	entity.Metadata.DeletedAt = nil
 	if updateErr := s.dao.Update(entity); updateErr != nil {
-		logrus.WithError(updateErr).Errorf("unable to perform the update of the dashboard %q, something wrong with the database", entity.Metadata.Name)
 		return nil, updateErr
 	}
 	return entity, nil
 }
 
-func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) error {
-	return s.dao.Delete(parameters.Project, parameters.Name)
+func (s *service) PurgeTrash(deletedBefore time.Time) error {
+	list, err := s.dao.ListDeleted(&dashboard.Query{})
+	if err != nil {
+		return err
+	}
+	for _, entity := range list {
+		if entity.Metadata.DeletedAt.Before(deletedBefore) {
+			if purgeErr := s.purge(entity); purgeErr != nil {
+				return purgeErr
+			}
+		}
+	}
+	return nil
+}
+
+// purge deletes permanently the dashboard, its history and its favorites, so a new dashboard with the same name
+// starts a new history and isn't a favorite of anyone.
+// The favorites are kept while the dashboard is in the trash, so they are back when the dashboard is restored.
+func (s *service) purge(entity *v1.Dashboard) error {
+	if err := s.dao.Delete(entity.Metadata.Project, entity.Metadata.Name); err != nil {
+		return err
+	}
+	if err := s.history.Delete(entity.Metadata.Project, entity.Metadata.Name); err != nil {
+		logrus.WithError(err).Errorf("unable to delete the history of the dashboard %q", entity.Metadata.Name)
+	}
+	// The favorites are stored apart from the dashboards, so they have to be removed with the dashboard.
+	if err := s.favoriteDAO.DeleteDashboard(entity.Metadata.Project, entity.Metadata.Name); err != nil {
+		logrus.WithError(err).Errorf("unable to remove the dashboard %q from the favorites", entity.Metadata.Name)
+	}
+	logrus.Debugf("dashboard %q of the project %q has been purged from the trash", entity.Metadata.Name, entity.Metadata.Project)
+	return nil
 }
 
 func (s *service) Get(parameters apiInterface.Parameters) (*v1.Dashboard, error) {
@@ -123,35 +351,103 @@ func (s *service) Get(parameters apiInterface.Parameters) (*v1.Dashboard, error)
 }
 
 func (s *service) List(q *dashboard.Query, params apiInterface.Parameters) ([]*v1.Dashboard, error) {
-	query, err := manageQuery(q, params)
+	query, selector, err := manageQuery(q, params)
 	if err != nil {
 		return nil, err
 	}
-	return s.dao.List(query)
+	list, err := s.dao.List(query)
+	if err != nil {
+		return nil, err
+	}
+	favorites := s.favorites(q.User)
+	result := make([]*v1.Dashboard, 0, len(list))
+	for _, entity := range list {
+		if selector.Matches(entity.Metadata.Tags) {
+			entity.Favorited = favorites[favoriteKey(entity.Metadata.Project, entity.Metadata.Name)]
+			result = append(result, entity)
+		}
+	}
+	return result, nil
 }
 
 func (s *service) RawList(q *dashboard.Query, params apiInterface.Parameters) ([]json.RawMessage, error) {
-	query, err := manageQuery(q, params)
+	query, selector, err := manageQuery(q, params)
 	if err != nil {
 		return nil, err
 	}
-	return s.dao.RawList(query)
+	list, err := s.dao.RawList(query)
+	if err != nil {
+		return nil, err
+	}
+	return flagRawFavorites(s.favorites(q.User), filterRawByTags(selector, list)), nil
 }
 
 func (s *service) MetadataList(q *dashboard.Query, params apiInterface.Parameters) ([]api.Entity, error) {
-	query, err := manageQuery(q, params)
+	query, selector, err := manageQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.dao.MetadataList(query)
 	if err != nil {
 		return nil, err
 	}
-	return s.dao.MetadataList(query)
+	result := make([]api.Entity, 0, len(list))
+	for _, entity := range list {
+		if metadata, ok := entity.GetMetadata().(*v1.ProjectMetadata); ok && selector.Matches(metadata.Tags) {
+			result = append(result, entity)
+		}
+	}
+	return flagPartialFavorites(s.favorites(q.User), result), nil
 }
 
 func (s *service) RawMetadataList(q *dashboard.Query, params apiInterface.Parameters) ([]json.RawMessage, error) {
-	query, err := manageQuery(q, params)
+	query, selector, err := manageQuery(q, params)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.dao.RawMetadataList(query)
+	if err != nil {
+		return nil, err
+	}
+	return flagRawFavorites(s.favorites(q.User), filterRawByTags(selector, list)), nil
+}
+
+func (s *service) ListTags(project string) ([]string, error) {
+	list, err := s.dao.RawMetadataList(&dashboard.Query{Project: project})
 	if err != nil {
 		return nil, err
 	}
-	return s.dao.RawMetadataList(query)
+	tags := set.New[string]()
+	for _, row := range list {
+		tags.Merge(rawTags(row))
+	}
+	if len(tags) == 0 {
+		// The response is always an array, even when no dashboard is tagged.
+		return []string{}, nil
+	}
+	return tags.TransformAsSlice(), nil
+}
+
+// filterRawByTags keeps the rows of the dashboards matching the selector, without decoding them completely.
+func filterRawByTags(selector v1.TagSelector, list []json.RawMessage) []json.RawMessage {
+	if selector.IsEmpty() {
+		return list
+	}
+	result := make([]json.RawMessage, 0, len(list))
+	for _, row := range list {
+		if selector.Matches(rawTags(row)) {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+func rawTags(row json.RawMessage) set.Set[string] {
+	tags := set.New[string]()
+	for _, tag := range gjson.GetBytes(row, "metadata.tags").Array() {
+		tags.Add(tag.String())
+	}
+	return tags
 }
 
 func (s *service) Validate(entity *v1.Dashboard) error {
@@ -171,6 +467,9 @@ func (s *service) Validate(entity *v1.Dashboard) error {
 	if err := validate.DashboardWithCustomRules(entity, s.customRules); err != nil {
 		return apiInterface.HandleBadRequestError(err.Error())
 	}
+	if _, err := dashboardModel.NewRefreshInterval(entity.Spec.RefreshInterval, s.minRefreshInterval); err != nil {
+		return apiInterface.HandleBadRequestError(err.Error())
+	}
 	if s.isDatasourceDisable {
 		if len(entity.Spec.Datasources) > 0 {
 			return apiInterface.HandleBadRequestError("local datasource cannot be used as it has been disabled in the configuration")
@@ -195,14 +494,18 @@ func (s *service) collectGlobalVariables() ([]*v1.GlobalVariable, error) {
 	return s.globalVarDAO.List(&globalvariable.Query{})
 }
 
-func manageQuery(q *dashboard.Query, params apiInterface.Parameters) (*dashboard.Query, error) {
+func manageQuery(q *dashboard.Query, params apiInterface.Parameters) (*dashboard.Query, v1.TagSelector, error) {
+	selector, err := v1.ParseTagSelector(q.Tags, q.TagOperator)
+	if err != nil {
+		return nil, v1.TagSelector{}, apiInterface.HandleBadRequestError(err.Error())
+	}
 	// Query is copied because it can be modified by the toolbox.go: listWhenPermissionIsActivated(...) and need to `q` need to keep initial value
 	query, err := deep.Copy(q)
 	if err != nil {
-		return nil, fmt.Errorf("unable to copy the query: %w", err)
+		return nil, v1.TagSelector{}, fmt.Errorf("unable to copy the query: %w", err)
 	}
 	if len(query.Project) == 0 {
 		query.Project = params.Project
 	}
-	return query, nil
+	return query, selector, nil
 }
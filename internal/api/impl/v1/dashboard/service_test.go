@@ -0,0 +1,486 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	databaseFile "github.com/perses/perses/internal/api/database/file"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	globalVariableImpl "github.com/perses/perses/internal/api/impl/v1/globalvariable"
+	templatePolicyBindingImpl "github.com/perses/perses/internal/api/impl/v1/templatepolicybinding"
+	userFavoriteImpl "github.com/perses/perses/internal/api/impl/v1/userfavorite"
+	variableImpl "github.com/perses/perses/internal/api/impl/v1/variable"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicy"
+	"github.com/perses/perses/internal/api/interface/v1/templatepolicybinding"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	dashboardSpec "github.com/perses/spec/go/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTemplatePolicyDAO struct {
+	templatepolicy.DAO
+	policies map[string]*v1.TemplatePolicy
+}
+
+func (d *fakeTemplatePolicyDAO) Get(name string) (*v1.TemplatePolicy, error) {
+	if policy, ok := d.policies[name]; ok {
+		return policy, nil
+	}
+	return nil, &databaseModel.Error{Key: name, Code: databaseModel.ErrorCodeNotFound}
+}
+
+type fakeTemplatePolicyBindingDAO struct {
+	templatepolicybinding.DAO
+	bindings []*v1.TemplatePolicyBinding
+}
+
+func (d *fakeTemplatePolicyBindingDAO) List(_ *templatepolicybinding.Query) ([]*v1.TemplatePolicyBinding, error) {
+	return d.bindings, nil
+}
+
+func newTestDashboard(project string, labels map[string]string) *v1.Dashboard {
+	return &v1.Dashboard{
+		Kind: v1.KindDashboard,
+		Metadata: v1.DashboardMetadata{
+			ProjectMetadata: v1.ProjectMetadata{
+				Metadata: v1.Metadata{
+					Name:   "test",
+					Labels: labels,
+				},
+				ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+					Project: project,
+				},
+			},
+		},
+	}
+}
+
+func TestValidateTemplatePolicies(t *testing.T) {
+	s := &service{
+		templatePolicyDAO: &fakeTemplatePolicyDAO{policies: map[string]*v1.TemplatePolicy{
+			"standard": {
+				Kind:     v1.KindTemplatePolicy,
+				Metadata: v1.Metadata{Name: "standard"},
+				Spec: v1.TemplatePolicySpec{
+					AllowedTemplates: []string{"service", "database"},
+					RequiredLabels:   map[string]string{"team": ""},
+				},
+			},
+		}},
+		templateBindingDAO: &fakeTemplatePolicyBindingDAO{bindings: []*v1.TemplatePolicyBinding{
+			{
+				Kind:     v1.KindTemplatePolicyBinding,
+				Metadata: v1.Metadata{Name: "standard"},
+				Spec:     v1.TemplatePolicyBindingSpec{Policy: "standard", Projects: []string{"perses"}},
+			},
+			{
+				Kind:     v1.KindTemplatePolicyBinding,
+				Metadata: v1.Metadata{Name: "dangling"},
+				Spec:     v1.TemplatePolicyBindingSpec{Policy: "unknown", Projects: []string{"perses"}},
+			},
+		}},
+	}
+	testSuite := []struct {
+		title     string
+		dashboard *v1.Dashboard
+		err       string
+	}{
+		{
+			title:     "allowed template",
+			dashboard: newTestDashboard("perses", map[string]string{v1.TemplateLabel: "service", "team": "infra"}),
+		},
+		{
+			title:     "disallowed template",
+			dashboard: newTestDashboard("perses", map[string]string{v1.TemplateLabel: "custom"}),
+			err: `unprocessable entity: dashboard doesn't comply with the template policies of the project "perses": ` +
+				`template policy "standard": template "custom" is not allowed, use one of the templates ["service" "database"]; ` +
+				`template policy "standard": label "team" is required`,
+		},
+		{
+			title:     "no policy bound to the project",
+			dashboard: newTestDashboard("other", nil),
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := s.validateTemplatePolicies(test.dashboard)
+			if len(test.err) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, apiInterface.UnprocessableEntity))
+			assert.Equal(t, test.err, err.Error())
+		})
+	}
+}
+
+type fakeDashboardDAO struct {
+	dashboard.DAO
+	rows []json.RawMessage
+}
+
+func (d *fakeDashboardDAO) RawList(_ *dashboard.Query) ([]json.RawMessage, error) {
+	return d.rows, nil
+}
+
+func (d *fakeDashboardDAO) RawMetadataList(_ *dashboard.Query) ([]json.RawMessage, error) {
+	return d.rows, nil
+}
+
+func TestListByTags(t *testing.T) {
+	s := &service{dao: &fakeDashboardDAO{rows: []json.RawMessage{
+		json.RawMessage(`{"metadata":{"name":"payments","tags":["sre","team-payments"]}}`),
+		json.RawMessage(`{"metadata":{"name":"checkout","tags":["team-payments"]}}`),
+		json.RawMessage(`{"metadata":{"name":"node"}}`),
+	}}}
+	testSuite := []struct {
+		title  string
+		query  *dashboard.Query
+		result []string
+		err    string
+	}{
+		{
+			title:  "no tag",
+			query:  &dashboard.Query{},
+			result: []string{"payments", "checkout", "node"},
+		},
+		{
+			title:  "all the tags",
+			query:  &dashboard.Query{Tags: []string{"sre,team-payments"}},
+			result: []string{"payments"},
+		},
+		{
+			title:  "any of the tags",
+			query:  &dashboard.Query{Tags: []string{"sre", "team-payments"}, TagOperator: "or"},
+			result: []string{"payments", "checkout"},
+		},
+		{
+			title: "invalid operator",
+			query: &dashboard.Query{Tags: []string{"sre"}, TagOperator: "xor"},
+			err:   `bad request: invalid tag operator "xor", expected "and" or "or"`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			list, err := s.RawList(test.query, apiInterface.Parameters{Project: "perses"})
+			if len(test.err) > 0 {
+				assert.True(t, errors.Is(err, apiInterface.BadRequestError))
+				assert.Equal(t, test.err, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			names := make([]string, 0, len(list))
+			for _, row := range list {
+				var entity struct {
+					Metadata struct {
+						Name string `json:"name"`
+					} `json:"metadata"`
+				}
+				assert.NoError(t, json.Unmarshal(row, &entity))
+				names = append(names, entity.Metadata.Name)
+			}
+			assert.Equal(t, test.result, names)
+		})
+	}
+}
+
+func TestListTags(t *testing.T) {
+	s := &service{dao: &fakeDashboardDAO{rows: []json.RawMessage{
+		json.RawMessage(`{"metadata":{"name":"payments","tags":["team-payments","sre"]}}`),
+		json.RawMessage(`{"metadata":{"name":"checkout","tags":["team-payments"]}}`),
+	}}}
+	tags, err := s.ListTags("perses")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sre", "team-payments"}, tags)
+
+	s = &service{dao: &fakeDashboardDAO{}}
+	tags, err = s.ListTags("perses")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, tags)
+}
+
+func TestRewriteSelfLinks(t *testing.T) {
+	spec := &dashboardSpec.Spec{
+		Links: []dashboardSpec.Link{
+			{URL: "/projects/perses/dashboards/node"},
+			{URL: "https://perses.dev/projects/perses/dashboards/node?var-job=api#panel"},
+			{URL: "/projects/perses/dashboards/node-exporter"},
+			{URL: "/projects/other/dashboards/node"},
+		},
+		Panels: map[string]*dashboardSpec.Panel{
+			"cpu": {
+				Kind: "Panel",
+				Spec: dashboardSpec.PanelSpec{
+					Links: []dashboardSpec.Link{{URL: "/projects/perses/dashboards/node/"}},
+				},
+			},
+		},
+	}
+	rewriteSelfLinks(spec, "perses", "node", "node-copy")
+	assert.Equal(t, []dashboardSpec.Link{
+		{URL: "/projects/perses/dashboards/node-copy"},
+		{URL: "https://perses.dev/projects/perses/dashboards/node-copy?var-job=api#panel"},
+		{URL: "/projects/perses/dashboards/node-exporter"},
+		{URL: "/projects/other/dashboards/node"},
+	}, spec.Links)
+	assert.Equal(t, "/projects/perses/dashboards/node-copy/", spec.Panels["cpu"].Spec.Links[0].URL)
+}
+
+type fakeHistoryService struct {
+	dashboardhistory.Service
+	deleted []string
+}
+
+func (h *fakeHistoryService) Delete(_ string, dashboard string) error {
+	h.deleted = append(h.deleted, dashboard)
+	return nil
+}
+
+func (h *fakeHistoryService) Record(_ echo.Context, _ *v1.Dashboard) error {
+	return nil
+}
+
+func TestTrashLifecycle(t *testing.T) {
+	history := &fakeHistoryService{}
+	persesDAO := &databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}
+	s := &service{
+		dao:         NewDAO(persesDAO),
+		history:     history,
+		favoriteDAO: userFavoriteImpl.NewDAO(persesDAO),
+	}
+	parameters := apiInterface.Parameters{Project: "perses", Name: "node"}
+	entity := &v1.Dashboard{
+		Kind:     v1.KindDashboard,
+		Metadata: *v1.NewDashboardMetadata("perses", "node"),
+		Spec:     dashboardSpec.Spec{Duration: "1h"},
+	}
+	entity.Metadata.CreateNow()
+	assert.NoError(t, s.dao.Create(entity))
+
+	// Deleting the dashboard moves it to the trash.
+	assert.NoError(t, s.Delete(nil, parameters))
+	_, err := s.Get(parameters)
+	assert.True(t, databaseModel.IsKeyNotFound(err))
+	list, err := s.List(&dashboard.Query{}, apiInterface.Parameters{Project: "perses"})
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	trash, err := s.ListTrash("perses")
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.NotNil(t, trash[0].Metadata.DeletedAt)
+
+	// Restoring the dashboard takes it out of the trash.
+	restored, err := s.Restore(parameters)
+	assert.NoError(t, err)
+	assert.Nil(t, restored.Metadata.DeletedAt)
+	_, err = s.Get(parameters)
+	assert.NoError(t, err)
+	trash, err = s.ListTrash("perses")
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	_, err = s.Restore(parameters)
+	assert.True(t, databaseModel.IsKeyNotFound(err))
+
+	// Only the dashboards deleted before the retention limit are purged.
+	assert.NoError(t, s.Delete(nil, parameters))
+	assert.NoError(t, s.PurgeTrash(time.Now().Add(-time.Hour)))
+	trash, err = s.ListTrash("perses")
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Empty(t, history.deleted)
+
+	assert.NoError(t, s.PurgeTrash(time.Now().Add(time.Second)))
+	trash, err = s.ListTrash("perses")
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	_, err = s.Restore(parameters)
+	assert.True(t, databaseModel.IsKeyNotFound(err))
+	assert.Equal(t, []string{"node"}, history.deleted)
+}
+
+func TestPurgeRemovesFavorites(t *testing.T) {
+	persesDAO := &databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}
+	favoriteDAO := userFavoriteImpl.NewDAO(persesDAO)
+	s := &service{
+		dao:         NewDAO(persesDAO),
+		history:     &fakeHistoryService{},
+		favoriteDAO: favoriteDAO,
+	}
+	for _, name := range []string{"node", "cpu"} {
+		entity := &v1.Dashboard{
+			Kind:     v1.KindDashboard,
+			Metadata: *v1.NewDashboardMetadata("perses", name),
+			Spec:     dashboardSpec.Spec{Duration: "1h"},
+		}
+		entity.Metadata.CreateNow()
+		assert.NoError(t, s.dao.Create(entity))
+		assert.NoError(t, favoriteDAO.Create(v1.NewUserFavorite("alice", "perses", name)))
+	}
+	assert.NoError(t, favoriteDAO.Create(v1.NewUserFavorite("bob", "perses", "node")))
+
+	// The favorites are kept while the dashboard is in the trash, so they are back if it is restored.
+	assert.NoError(t, s.Delete(nil, apiInterface.Parameters{Project: "perses", Name: "node"}))
+	favorites, err := favoriteDAO.List("bob")
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 1)
+
+	assert.NoError(t, s.PurgeTrash(time.Now().Add(time.Minute)))
+	favorites, err = favoriteDAO.List("alice")
+	assert.NoError(t, err)
+	if assert.Len(t, favorites, 1) {
+		assert.Equal(t, "cpu", favorites[0].Spec.DashboardName)
+	}
+	favorites, err = favoriteDAO.List("bob")
+	assert.NoError(t, err)
+	assert.Empty(t, favorites)
+}
+
+func TestListFlagsFavorites(t *testing.T) {
+	persesDAO := &databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}
+	favoriteDAO := userFavoriteImpl.NewDAO(persesDAO)
+	s := &service{
+		dao:         NewDAO(persesDAO),
+		favoriteDAO: favoriteDAO,
+	}
+	for _, name := range []string{"node", "cpu"} {
+		entity := &v1.Dashboard{
+			Kind:     v1.KindDashboard,
+			Metadata: *v1.NewDashboardMetadata("perses", name),
+			Spec:     dashboardSpec.Spec{Duration: "1h"},
+		}
+		entity.Metadata.CreateNow()
+		assert.NoError(t, s.dao.Create(entity))
+	}
+	assert.NoError(t, favoriteDAO.Create(v1.NewUserFavorite("alice", "perses", "node")))
+	parameters := apiInterface.Parameters{Project: "perses"}
+	expected := map[string]bool{"node": true, "cpu": false}
+
+	list, err := s.List(&dashboard.Query{User: "alice"}, parameters)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	for _, entity := range list {
+		assert.Equal(t, expected[entity.Metadata.Name], entity.Favorited, entity.Metadata.Name)
+	}
+
+	metadataList, err := s.MetadataList(&dashboard.Query{User: "alice"}, parameters)
+	assert.NoError(t, err)
+	assert.Len(t, metadataList, 2)
+	for _, entity := range metadataList {
+		_, favorited := entity.(*partialDashboard)
+		assert.Equal(t, expected[entity.GetMetadata().GetName()], favorited, entity.GetMetadata().GetName())
+	}
+
+	for _, listRaw := range []func(*dashboard.Query, apiInterface.Parameters) ([]json.RawMessage, error){s.RawList, s.RawMetadataList} {
+		rawList, rawErr := listRaw(&dashboard.Query{User: "alice"}, parameters)
+		assert.NoError(t, rawErr)
+		assert.Len(t, rawList, 2)
+		for _, row := range rawList {
+			var entity struct {
+				Metadata  v1.ProjectMetadata `json:"metadata"`
+				Favorited bool               `json:"favorited"`
+			}
+			assert.NoError(t, json.Unmarshal(row, &entity))
+			assert.Equal(t, expected[entity.Metadata.Name], entity.Favorited, entity.Metadata.Name)
+		}
+	}
+
+	// Without a user, nothing is flagged.
+	list, err = s.List(&dashboard.Query{}, parameters)
+	assert.NoError(t, err)
+	for _, entity := range list {
+		assert.False(t, entity.Favorited)
+	}
+}
+
+// racingDashboardDAO simulates another instance of Perses updating the dashboard right after it has been read.
+type racingDashboardDAO struct {
+	dashboard.DAO
+	raced bool
+}
+
+func (d *racingDashboardDAO) Get(project string, name string) (*v1.Dashboard, error) {
+	entity, err := d.DAO.Get(project, name)
+	if err != nil || d.raced {
+		return entity, err
+	}
+	d.raced = true
+	concurrent, err := d.DAO.Get(project, name)
+	if err != nil {
+		return nil, err
+	}
+	concurrent.Metadata.Update(entity.Metadata)
+	concurrent.Metadata.ResourceVersion = resourceVersion(concurrent.Metadata)
+	return entity, d.DAO.Update(concurrent)
+}
+
+func TestUpdateResourceVersion(t *testing.T) {
+	persesDAO := &databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension}
+	dao := &racingDashboardDAO{DAO: NewDAO(persesDAO), raced: true}
+	s := &service{
+		dao:                dao,
+		history:            &fakeHistoryService{},
+		globalVarDAO:       globalVariableImpl.NewDAO(persesDAO),
+		projectVarDAO:      variableImpl.NewDAO(persesDAO),
+		templateBindingDAO: templatePolicyBindingImpl.NewDAO(persesDAO),
+	}
+	parameters := apiInterface.Parameters{Project: "perses", Name: "node"}
+	newDashboard := func(resourceVersion string) *v1.Dashboard {
+		entity := &v1.Dashboard{
+			Kind:     v1.KindDashboard,
+			Metadata: *v1.NewDashboardMetadata("perses", "node"),
+			Spec:     dashboardSpec.Spec{Duration: "1h"},
+		}
+		entity.Metadata.ResourceVersion = resourceVersion
+		return entity
+	}
+	created, err := s.create(nil, newDashboard(""))
+	assert.NoError(t, err)
+	assert.Equal(t, "0", created.Metadata.ResourceVersion)
+
+	updated, err := s.Update(nil, newDashboard("0"), parameters)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", updated.Metadata.ResourceVersion)
+
+	// the resource version read is no longer the current one
+	_, err = s.Update(nil, newDashboard("0"), parameters)
+	assert.True(t, errors.Is(err, apiInterface.VersionConflictError))
+
+	// the dashboard is modified by someone else between the verification of the resource version and the update
+	dao.raced = false
+	_, err = s.Update(nil, newDashboard("1"), parameters)
+	assert.True(t, errors.Is(err, apiInterface.VersionConflictError))
+	current, err := s.dao.Get("perses", "node")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", current.Metadata.ResourceVersion)
+
+	// the resource version is required
+	_, err = s.Update(nil, newDashboard(""), parameters)
+	assert.True(t, errors.Is(err, apiInterface.PreconditionRequired))
+
+	// a dashboard saved before the resource versions were introduced can be updated without one, and gets one
+	current.Metadata.ResourceVersion = ""
+	assert.NoError(t, s.dao.Update(current))
+	updated, err = s.Update(nil, newDashboard(""), parameters)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", updated.Metadata.ResourceVersion)
+}
@@ -0,0 +1,64 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type tagEndpoint struct {
+	service dashboard.Service
+	authz   authorization.Authorization
+}
+
+// NewTagEndpoint returns the endpoint listing the tags of the dashboards of a project.
+func NewTagEndpoint(service dashboard.Service, authz authorization.Authorization) route.Endpoint {
+	return &tagEndpoint{
+		service: service,
+		authz:   authz,
+	}
+}
+
+func (e *tagEndpoint) CollectRoutes(g *route.Group) {
+	g.GET(fmt.Sprintf("/%s", utils.PathTag), e.ListTags, false)
+	g.GET(fmt.Sprintf("/%s/:%s/%s", utils.PathProject, utils.ParamProject, utils.PathTag), e.ListTags, false)
+}
+
+func (e *tagEndpoint) ListTags(ctx echo.Context) error {
+	project := utils.GetProjectParameter(ctx)
+	if len(project) == 0 {
+		project = ctx.QueryParam(utils.ParamProject)
+	}
+	if len(project) == 0 {
+		return apiInterface.HandleBadRequestError("the project is required to list the tags")
+	}
+	// The tags are part of the dashboards, so reading them requires to be able to read the dashboards.
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.ReadAction, project, role.DashboardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, project, role.DashboardScope))
+	}
+	result, err := e.service.ListTags(project)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,85 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/toolbox"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type trashEndpoint struct {
+	service       dashboard.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
+}
+
+// NewTrashEndpoint returns the endpoints listing the deleted dashboards of a project and restoring them.
+func NewTrashEndpoint(service dashboard.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+	return &trashEndpoint{
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (e *trashEndpoint) CollectRoutes(g *route.Group) {
+	group := g.Group(fmt.Sprintf("/%s/:%s/%s", utils.PathProject, utils.ParamProject, utils.PathTrash))
+	if !e.readonly {
+		group.POST(fmt.Sprintf("/:%s/restore", utils.ParamName), e.Restore, false)
+	}
+	group.GET("", e.List, false)
+}
+
+func (e *trashEndpoint) List(ctx echo.Context) error {
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if err := e.checkPermission(ctx, role.ReadAction, parameters.Project); err != nil {
+		return err
+	}
+	result, err := e.service.ListTrash(parameters.Project)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *trashEndpoint) Restore(ctx echo.Context) error {
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	// Restoring a dashboard brings it back in the project, like a creation.
+	if err := e.checkPermission(ctx, role.CreateAction, parameters.Project); err != nil {
+		return err
+	}
+	result, err := e.service.Restore(parameters)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *trashEndpoint) checkPermission(ctx echo.Context, action role.Action, project string) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, action, project, role.DashboardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", action, project, role.DashboardScope))
+	}
+	return nil
+}
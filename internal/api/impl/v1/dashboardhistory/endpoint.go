@@ -0,0 +1,114 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardhistory
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+	paramVersion = "version"
+)
+
+type endpoint struct {
+	service dashboardhistory.Service
+	authz   authorization.Authorization
+}
+
+func NewEndpoint(service dashboardhistory.Service, authz authorization.Authorization) route.Endpoint {
+	return &endpoint{
+		service: service,
+		authz:   authz,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	group := g.Group(fmt.Sprintf("/%s/:%s/%s/:%s/%s", utils.PathProject, utils.ParamProject, utils.PathDashboard, utils.ParamName, utils.PathHistory))
+	group.GET("", e.list, false)
+	group.GET(fmt.Sprintf("/:%s", paramVersion), e.get, false)
+}
+
+func (e *endpoint) list(ctx echo.Context) error {
+	if err := e.checkPermission(ctx); err != nil {
+		return err
+	}
+	q, err := parseListQuery(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := e.service.List(q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) get(ctx echo.Context) error {
+	if err := e.checkPermission(ctx); err != nil {
+		return err
+	}
+	version, err := strconv.ParseUint(ctx.Param(paramVersion), 10, 64)
+	if err != nil {
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("invalid version %q, it must be a positive integer", ctx.Param(paramVersion)))
+	}
+	result, err := e.service.Get(utils.GetProjectParameter(ctx), utils.GetNameParameter(ctx), version)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// checkPermission verifies the user can read the dashboards of the project, as the history reveals their content.
+func (e *endpoint) checkPermission(ctx echo.Context) error {
+	project := utils.GetProjectParameter(ctx)
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.ReadAction, project, role.DashboardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, project, role.DashboardScope))
+	}
+	return nil
+}
+
+func parseListQuery(ctx echo.Context) (*dashboardhistory.ListQuery, error) {
+	q := &dashboardhistory.ListQuery{
+		Project:   utils.GetProjectParameter(ctx),
+		Dashboard: utils.GetNameParameter(ctx),
+		Limit:     defaultLimit,
+	}
+	if limit := ctx.QueryParam("limit"); len(limit) > 0 {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > maxLimit {
+			return nil, apiInterface.HandleBadRequestError(fmt.Sprintf("invalid limit %q, it must be an integer between 1 and %d", limit, maxLimit))
+		}
+		q.Limit = value
+	}
+	if before := ctx.QueryParam("before"); len(before) > 0 {
+		value, err := strconv.ParseUint(before, 10, 64)
+		if err != nil {
+			return nil, apiInterface.HandleBadRequestError(fmt.Sprintf("invalid cursor %q", before))
+		}
+		q.Before = &value
+	}
+	return q, nil
+}
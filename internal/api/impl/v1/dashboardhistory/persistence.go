@@ -0,0 +1,89 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardhistory
+
+import (
+	"strings"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type dao struct {
+	dashboardhistory.DAO
+	client databaseModel.DAO
+	kind   v1.Kind
+}
+
+func NewDAO(persesDAO databaseModel.DAO) dashboardhistory.DAO {
+	return &dao{
+		client: persesDAO,
+		kind:   v1.KindDashboardHistory,
+	}
+}
+
+func (d *dao) AppendHistory(entity *v1.DashboardHistory) error {
+	return d.client.Create(entity)
+}
+
+func (d *dao) ListHistory(project string, dashboard string) ([]*v1.DashboardHistory, error) {
+	name := d.dashboardName(dashboard)
+	var records []*v1.DashboardHistory
+	if err := d.client.Query(&dashboardhistory.Query{Project: project, NamePrefix: v1.DashboardHistoryNamePrefix(name)}, &records); err != nil {
+		return nil, err
+	}
+	// The prefix is a hash of the name of the dashboard, so it doesn't strictly guarantee the records are the ones of this dashboard.
+	result := make([]*v1.DashboardHistory, 0, len(records))
+	for _, record := range records {
+		if record.Spec.Dashboard == name {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (d *dao) GetHistory(project string, dashboard string, version uint64) (*v1.DashboardHistory, error) {
+	name := d.dashboardName(dashboard)
+	metadata := v1.NewProjectMetadata(project, v1.DashboardHistoryName(name, version))
+	entity := &v1.DashboardHistory{}
+	if err := d.client.Get(d.kind, metadata, entity); err != nil {
+		return nil, err
+	}
+	if entity.Spec.Dashboard != name {
+		return nil, &databaseModel.Error{Key: metadata.Name, Code: databaseModel.ErrorCodeNotFound}
+	}
+	return entity, nil
+}
+
+func (d *dao) DeleteHistoryVersion(project string, dashboard string, version uint64) error {
+	metadata := v1.NewProjectMetadata(project, v1.DashboardHistoryName(d.dashboardName(dashboard), version))
+	return d.client.Delete(d.kind, metadata)
+}
+
+func (d *dao) DeleteHistory(project string, dashboard string) error {
+	q := &dashboardhistory.Query{Project: project}
+	if len(dashboard) > 0 {
+		q.NamePrefix = v1.DashboardHistoryNamePrefix(d.dashboardName(dashboard))
+	}
+	return d.client.DeleteByQuery(q)
+}
+
+// dashboardName returns the name of the dashboard as it is stored, which is lowercase when the database is not case-sensitive.
+func (d *dao) dashboardName(dashboard string) string {
+	if d.client.IsCaseSensitive() {
+		return dashboard
+	}
+	return strings.ToLower(dashboard)
+}
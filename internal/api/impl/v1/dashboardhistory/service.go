@@ -0,0 +1,129 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardhistory
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type service struct {
+	dashboardhistory.Service
+	dao   dashboardhistory.DAO
+	authz authorization.Authorization
+	// maxVersions is the number of versions kept in the history of each dashboard. 0 keeps every version.
+	maxVersions int
+}
+
+func NewService(dao dashboardhistory.DAO, authz authorization.Authorization, maxVersions int) dashboardhistory.Service {
+	return &service{
+		dao:         dao,
+		authz:       authz,
+		maxVersions: maxVersions,
+	}
+}
+
+func (s *service) Record(ctx echo.Context, entity *v1.Dashboard) error {
+	if err := s.dao.AppendHistory(v1.NewDashboardHistory(entity, s.actor(ctx))); err != nil {
+		return err
+	}
+	// The version is recorded, so failing to remove the oldest ones only delays it to the next update.
+	if err := s.trim(entity.Metadata.Project, entity.Metadata.Name); err != nil {
+		logrus.WithError(err).Errorf("unable to remove the oldest versions from the history of the dashboard %q", entity.Metadata.Name)
+	}
+	return nil
+}
+
+// trim removes the oldest versions of the history of the dashboard beyond maxVersions.
+func (s *service) trim(project string, dashboard string) error {
+	if s.maxVersions <= 0 {
+		return nil
+	}
+	records, err := s.dao.ListHistory(project, dashboard)
+	if err != nil || len(records) <= s.maxVersions {
+		return err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Spec.Version < records[j].Spec.Version
+	})
+	for _, record := range records[:len(records)-s.maxVersions] {
+		if deleteErr := s.dao.DeleteHistoryVersion(project, dashboard, record.Spec.Version); deleteErr != nil && !databaseModel.IsKeyNotFound(deleteErr) {
+			return deleteErr
+		}
+	}
+	return nil
+}
+
+// actor returns the user who made the request. The context is nil when the dashboard is not modified through the API.
+func (s *service) actor(ctx echo.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	username, err := s.authz.GetUsername(ctx)
+	if err != nil {
+		logrus.WithError(err).Debug("unable to get the user recorded in the history of the dashboard")
+		return ""
+	}
+	return username
+}
+
+func (s *service) List(q *dashboardhistory.ListQuery) (*dashboardhistory.List, error) {
+	records, err := s.dao.ListHistory(q.Project, q.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Spec.Version > records[j].Spec.Version
+	})
+	result := &dashboardhistory.List{Items: []*v1.DashboardHistoryEntry{}}
+	for _, record := range records {
+		if q.Before != nil && record.Spec.Version >= *q.Before {
+			continue
+		}
+		if len(result.Items) == q.Limit {
+			// There is at least one more entry, so the client can ask for the next page.
+			result.Next = strconv.FormatUint(result.Items[len(result.Items)-1].Version, 10)
+			break
+		}
+		entry := record.Spec.DashboardHistoryEntry
+		// The snapshots are only returned one by one, to keep the list light.
+		entry.Content = nil
+		result.Items = append(result.Items, &entry)
+	}
+	return result, nil
+}
+
+func (s *service) Get(project string, dashboard string, version uint64) (*v1.DashboardHistoryEntry, error) {
+	record, err := s.dao.GetHistory(project, dashboard, version)
+	if err != nil {
+		return nil, err
+	}
+	if record.Spec.Content != nil {
+		// The resource version of a past version is outdated: sending it back in an update would always be rejected.
+		// It is also hidden from the users who can't read it.
+		record.Spec.Content.Metadata.ResourceVersion = ""
+	}
+	return &record.Spec.DashboardHistoryEntry, nil
+}
+
+func (s *service) Delete(project string, dashboard string) error {
+	return s.dao.DeleteHistory(project, dashboard)
+}
@@ -0,0 +1,201 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardhistory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDAO struct {
+	dashboardhistory.DAO
+	records []*v1.DashboardHistory
+}
+
+func (d *fakeDAO) AppendHistory(entity *v1.DashboardHistory) error {
+	d.records = append(d.records, entity)
+	return nil
+}
+
+func (d *fakeDAO) ListHistory(project string, dashboard string) ([]*v1.DashboardHistory, error) {
+	var result []*v1.DashboardHistory
+	for _, record := range d.records {
+		if record.Metadata.Project == project && record.Spec.Dashboard == dashboard {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (d *fakeDAO) GetHistory(project string, dashboard string, version uint64) (*v1.DashboardHistory, error) {
+	for _, record := range d.records {
+		if record.Metadata.Project == project && record.Spec.Dashboard == dashboard && record.Spec.Version == version {
+			return record, nil
+		}
+	}
+	return nil, &databaseModel.Error{Key: v1.DashboardHistoryName(dashboard, version), Code: databaseModel.ErrorCodeNotFound}
+}
+
+func (d *fakeDAO) DeleteHistoryVersion(project string, dashboard string, version uint64) error {
+	for i, record := range d.records {
+		if record.Metadata.Project == project && record.Spec.Dashboard == dashboard && record.Spec.Version == version {
+			d.records = append(d.records[:i], d.records[i+1:]...)
+			return nil
+		}
+	}
+	return &databaseModel.Error{Key: v1.DashboardHistoryName(dashboard, version), Code: databaseModel.ErrorCodeNotFound}
+}
+
+type fakeAuthorization struct {
+	authorization.Authorization
+	username string
+}
+
+func (a *fakeAuthorization) GetUsername(_ echo.Context) (string, error) {
+	return a.username, nil
+}
+
+func newTestDashboard(name string, version uint64) *v1.Dashboard {
+	return &v1.Dashboard{
+		Kind: v1.KindDashboard,
+		Metadata: v1.DashboardMetadata{
+			ProjectMetadata: v1.ProjectMetadata{
+				Metadata: v1.Metadata{
+					Name:      name,
+					Version:   version,
+					UpdatedAt: time.Date(2024, 1, 1, 0, 0, int(version), 0, time.UTC),
+				},
+				ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+					Project: "perses",
+				},
+			},
+			ResourceVersion: strconv.FormatUint(version, 10),
+		},
+	}
+}
+
+func newTestService(t *testing.T) *service {
+	s := &service{
+		dao:   &fakeDAO{},
+		authz: &fakeAuthorization{username: "jane"},
+	}
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPut, "/", nil), httptest.NewRecorder())
+	for version := uint64(0); version < 5; version++ {
+		require.NoError(t, s.Record(ctx, newTestDashboard("foo", version)))
+	}
+	require.NoError(t, s.Record(nil, newTestDashboard("bar", 0)))
+	return s
+}
+
+func TestList(t *testing.T) {
+	s := newTestService(t)
+	before := uint64(2)
+	testSuite := []struct {
+		title    string
+		query    *dashboardhistory.ListQuery
+		versions []uint64
+		next     string
+	}{
+		{
+			title:    "every version",
+			query:    &dashboardhistory.ListQuery{Project: "perses", Dashboard: "foo", Limit: 50},
+			versions: []uint64{4, 3, 2, 1, 0},
+		},
+		{
+			title:    "first page",
+			query:    &dashboardhistory.ListQuery{Project: "perses", Dashboard: "foo", Limit: 2},
+			versions: []uint64{4, 3},
+			next:     "3",
+		},
+		{
+			title:    "last page",
+			query:    &dashboardhistory.ListQuery{Project: "perses", Dashboard: "foo", Limit: 2, Before: &before},
+			versions: []uint64{1, 0},
+		},
+		{
+			title:    "unknown dashboard",
+			query:    &dashboardhistory.ListQuery{Project: "perses", Dashboard: "unknown", Limit: 50},
+			versions: []uint64{},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := s.List(test.query)
+			require.NoError(t, err)
+			versions := []uint64{}
+			for _, item := range result.Items {
+				assert.Nil(t, item.Content)
+				assert.Equal(t, "jane", item.Actor)
+				versions = append(versions, item.Version)
+			}
+			assert.Equal(t, test.versions, versions)
+			assert.Equal(t, test.next, result.Next)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	s := newTestService(t)
+
+	entry, err := s.Get("perses", "foo", 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), entry.Version)
+	assert.Equal(t, "jane", entry.Actor)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 3, 0, time.UTC), entry.Time)
+	require.NotNil(t, entry.Content)
+	assert.Equal(t, "foo", entry.Content.Metadata.Name)
+	assert.Empty(t, entry.Content.Metadata.ResourceVersion)
+
+	// Without a request, the actor is unknown.
+	entry, err = s.Get("perses", "bar", 0)
+	require.NoError(t, err)
+	assert.Empty(t, entry.Actor)
+
+	_, err = s.Get("perses", "foo", 5)
+	assert.True(t, databaseModel.IsKeyNotFound(err))
+}
+
+func TestRecordTrimsHistory(t *testing.T) {
+	dao := &fakeDAO{}
+	s := &service{
+		dao:         dao,
+		authz:       &fakeAuthorization{username: "jane"},
+		maxVersions: 3,
+	}
+	for version := uint64(0); version < 5; version++ {
+		require.NoError(t, s.Record(nil, newTestDashboard("foo", version)))
+	}
+	require.NoError(t, s.Record(nil, newTestDashboard("bar", 0)))
+
+	var versions []uint64
+	for _, record := range dao.records {
+		if record.Spec.Dashboard == "foo" {
+			versions = append(versions, record.Spec.Version)
+		}
+	}
+	assert.ElementsMatch(t, []uint64{2, 3, 4}, versions)
+	_, err := s.Get("perses", "bar", 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardtemplate
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/toolbox"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// saveQueryParam is the query parameter asking to create the dashboard instantiated from the template.
+const saveQueryParam = "save"
+
+type instantiateRequest struct {
+	// Name is the name of the dashboard. When empty, the name of the template is used.
+	Name string `json:"name"`
+	// Parameters are the values of the parameters of the template.
+	Parameters map[string]any `json:"parameters"`
+}
+
+type instantiateEndpoint struct {
+	service       dashboardtemplate.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
+}
+
+// NewInstantiateEndpoint returns the endpoint building a dashboard from a template.
+func NewInstantiateEndpoint(service dashboardtemplate.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+	return &instantiateEndpoint{
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (e *instantiateEndpoint) CollectRoutes(g *route.Group) {
+	g.POST(fmt.Sprintf("/%s/:%s/%s/:%s/instantiate", utils.PathProject, utils.ParamProject, utils.PathDashboardTemplate, utils.ParamName), e.Instantiate, false)
+}
+
+func (e *instantiateEndpoint) Instantiate(ctx echo.Context) error {
+	body := &instantiateRequest{}
+	if err := ctx.Bind(body); err != nil {
+		return apiInterface.HandleBadRequestError(err.Error())
+	}
+	save := ctx.QueryParam(saveQueryParam) == "true"
+	if save && e.readonly {
+		return apiInterface.HandleForbiddenError("the dashboard cannot be saved as the server is in readonly mode")
+	}
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if err := e.checkPermission(ctx, parameters.Project, save); err != nil {
+		return err
+	}
+	name := body.Name
+	if len(name) == 0 {
+		name = parameters.Name
+	}
+	if !e.caseSensitive {
+		name = strings.ToLower(name)
+	}
+	result, err := e.service.Instantiate(ctx, parameters, name, body.Parameters, save)
+	if err != nil {
+		return err
+	}
+	if !save {
+		return ctx.JSON(http.StatusOK, result)
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/%s/%s/%s/%s", utils.APIV1Prefix, utils.PathProject, result.Metadata.Project, utils.PathDashboard, result.Metadata.Name))
+	return ctx.JSON(http.StatusCreated, result)
+}
+
+// checkPermission verifies the user can read the template and, when the dashboard is saved, create it in the same project.
+func (e *instantiateEndpoint) checkPermission(ctx echo.Context, project string, save bool) error {
+	if !e.authz.IsEnabled() {
+		return nil
+	}
+	if !e.authz.HasPermission(ctx, role.ReadAction, project, role.DashboardTemplateScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, project, role.DashboardTemplateScope))
+	}
+	if save && !e.authz.HasPermission(ctx, role.CreateAction, project, role.DashboardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.CreateAction, project, role.DashboardScope))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"sync"
+	"time"
+)
+
+type cachedResponse struct {
+	response  *QueryResponse
+	expiresAt time.Time
+}
+
+// QueryCache keeps the responses of the datasource queries in memory for a short duration.
+// It is meant for the queries that are sent very often with the same parameters, like the exemplars of a panel.
+type QueryCache struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	responses map[string]cachedResponse
+	now       func() time.Time
+}
+
+func NewQueryCache(ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		ttl:       ttl,
+		responses: make(map[string]cachedResponse),
+		now:       time.Now,
+	}
+}
+
+// Get returns the response cached for the given key, if it has not expired yet.
+func (c *QueryCache) Get(key string) (*QueryResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, ok := c.responses[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(cached.expiresAt) {
+		delete(c.responses, key)
+		return nil, false
+	}
+	return cached.response, true
+}
+
+// Set caches the response for the given key. The expired responses are removed at the same time.
+func (c *QueryCache) Set(key string, response *QueryResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	for k, cached := range c.responses {
+		if !now.Before(cached.expiresAt) {
+			delete(c.responses, k)
+		}
+	}
+	c.responses[key] = cachedResponse{response: response, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,43 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCache(t *testing.T) {
+	now := time.Now()
+	cache := NewQueryCache(5 * time.Second)
+	cache.now = func() time.Time { return now }
+	key := QueryKey([]byte("prometheus"), []byte(http.MethodGet), []byte("/api/v1/query_exemplars"), []byte("query=up"))
+
+	_, ok := cache.Get(key)
+	assert.False(t, ok)
+
+	cache.Set(key, &QueryResponse{StatusCode: http.StatusOK, Body: []byte(`{"status":"success"}`)})
+	now = now.Add(4 * time.Second)
+	response, ok := cache.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, `{"status":"success"}`, string(response.Body))
+
+	now = now.Add(time.Second)
+	_, ok = cache.Get(key)
+	assert.False(t, ok)
+	assert.Empty(t, cache.responses)
+}
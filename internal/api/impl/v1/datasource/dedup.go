@@ -0,0 +1,103 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// QueryResponse is the response of a datasource query, kept in memory to be shared with the identical queries.
+type QueryResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Write copies the response in the given writer.
+func (r *QueryResponse) Write(w http.ResponseWriter) error {
+	for k, values := range r.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(r.StatusCode)
+	_, err := w.Write(r.Body)
+	return err
+}
+
+// QueryRecorder is an http.ResponseWriter recording the response of a datasource query.
+type QueryRecorder struct {
+	response QueryResponse
+	buffer   bytes.Buffer
+}
+
+func NewQueryRecorder() *QueryRecorder {
+	return &QueryRecorder{response: QueryResponse{StatusCode: http.StatusOK, Header: make(http.Header)}}
+}
+
+func (r *QueryRecorder) Header() http.Header {
+	return r.response.Header
+}
+
+func (r *QueryRecorder) Write(b []byte) (int, error) {
+	return r.buffer.Write(b)
+}
+
+func (r *QueryRecorder) WriteHeader(statusCode int) {
+	r.response.StatusCode = statusCode
+}
+
+// Response returns what has been recorded so far.
+func (r *QueryRecorder) Response() *QueryResponse {
+	r.response.Body = r.buffer.Bytes()
+	return &r.response
+}
+
+// QueryKey computes the key identifying a query. Every part that can change the result of the query must be given.
+func QueryKey(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// The length is written before each part to avoid two different lists of parts producing the same key.
+		h.Write([]byte{byte(len(part) >> 24), byte(len(part) >> 16), byte(len(part) >> 8), byte(len(part))})
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// QueryDeduplicator ensures identical queries received at the same time are sent only once to the datasource.
+// The queries arriving while the first one is in flight are waiting for it and receive the same response.
+type QueryDeduplicator struct {
+	group singleflight.Group
+}
+
+func NewQueryDeduplicator() *QueryDeduplicator {
+	return &QueryDeduplicator{}
+}
+
+// Do executes the query if no identical query is in flight, otherwise it waits for the response of the one in flight.
+// The boolean returned is true when the response has been shared with another caller.
+func (d *QueryDeduplicator) Do(key string, query func() (*QueryResponse, error)) (*QueryResponse, bool, error) {
+	result, err, shared := d.group.Do(key, func() (any, error) {
+		return query()
+	})
+	if err != nil {
+		return nil, shared, err
+	}
+	return result.(*QueryResponse), shared, nil
+}
@@ -0,0 +1,93 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDeduplicator(t *testing.T) {
+	const nbQueries = 100
+	dedup := NewQueryDeduplicator()
+	key := QueryKey([]byte("prometheus"), []byte(http.MethodPost), []byte("/api/v1/query_range"), []byte("query=up"))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	mockDatasource := func() (*QueryResponse, error) {
+		calls.Add(1)
+		<-release
+		return &QueryResponse{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/json"}}, Body: []byte(`{"status":"success"}`)}, nil
+	}
+
+	var started sync.WaitGroup
+	var done sync.WaitGroup
+	responses := make([]*QueryResponse, nbQueries)
+	for i := range nbQueries {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			response, _, err := dedup.Do(key, mockDatasource)
+			assert.NoError(t, err)
+			responses[i] = response
+		}()
+	}
+	started.Wait()
+	// give some time to every goroutine to join the query in flight before the datasource answers
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, response := range responses {
+		assert.Equal(t, `{"status":"success"}`, string(response.Body))
+	}
+}
+
+func TestQueryDeduplicator_DifferentQueries(t *testing.T) {
+	dedup := NewQueryDeduplicator()
+	var calls atomic.Int32
+	query := func() (*QueryResponse, error) {
+		calls.Add(1)
+		return &QueryResponse{StatusCode: http.StatusOK}, nil
+	}
+	_, _, err := dedup.Do(QueryKey([]byte("query=up")), query)
+	assert.NoError(t, err)
+	_, _, err = dedup.Do(QueryKey([]byte("query=down")), query)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestQueryKey(t *testing.T) {
+	assert.Equal(t, QueryKey([]byte("a"), []byte("b")), QueryKey([]byte("a"), []byte("b")))
+	assert.NotEqual(t, QueryKey([]byte("ab"), []byte("")), QueryKey([]byte("a"), []byte("b")))
+}
+
+func TestQueryRecorder(t *testing.T) {
+	recorder := NewQueryRecorder()
+	recorder.Header().Set("Content-Type", "application/json")
+	recorder.WriteHeader(http.StatusAccepted)
+	_, err := recorder.Write([]byte(`{}`))
+	assert.NoError(t, err)
+	response := recorder.Response()
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.Equal(t, `{}`, string(response.Body))
+}
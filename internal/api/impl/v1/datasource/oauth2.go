@@ -0,0 +1,87 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenRefreshMargin is how long before its expiry an access token is renewed,
+// so it doesn't expire while the request using it is on its way to the datasource.
+const tokenRefreshMargin = 30 * time.Second
+
+// TokenCache keeps in memory the access tokens obtained with the OAuth 2.0 client credentials flow,
+// so the token endpoint is not called for every request sent to a datasource.
+type TokenCache struct {
+	mutex  sync.Mutex
+	tokens map[string]*oauth2.Token
+	now    func() time.Time
+}
+
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		tokens: make(map[string]*oauth2.Token),
+		now:    time.Now,
+	}
+}
+
+// TokenKey identifies the tokens obtained with the given config. Every field takes part in it,
+// so a token is not reused once the client secret or the scopes of the datasource have changed.
+func TokenKey(conf *clientcredentials.Config) string {
+	data, _ := json.Marshal(conf)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Token returns the access token cached for the given key.
+// When there is none, or when it is about to expire, a new one is obtained with fetch and cached.
+// The errors are not cached, so the next call tries again.
+func (c *TokenCache) Token(key string, fetch func() (*oauth2.Token, error)) (*oauth2.Token, error) {
+	c.mutex.Lock()
+	token, ok := c.tokens[key]
+	c.mutex.Unlock()
+	if ok && c.isFresh(token) {
+		return token, nil
+	}
+
+	// The lock is not held while fetching, to not block the other datasources on a slow token endpoint.
+	token, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k, cached := range c.tokens {
+		if !c.isFresh(cached) {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = token
+	return token, nil
+}
+
+// isFresh tells whether the token can still be used. Like in the oauth2 package, a token without expiry never expires.
+func (c *TokenCache) isFresh(token *oauth2.Token) bool {
+	if token.Expiry.IsZero() {
+		return true
+	}
+	return c.now().Add(tokenRefreshMargin).Before(token.Expiry)
+}
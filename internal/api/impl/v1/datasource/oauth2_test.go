@@ -0,0 +1,99 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newTestTokenServer mocks an OAuth 2.0 token endpoint, issuing a new token valid for one hour for each request.
+// The client "broken" is answered with an error.
+func newTestTokenServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		clientID, _, _ := r.BasicAuth()
+		if clientID == "broken" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"%s-%d","token_type":"Bearer","expires_in":3600}`, clientID, n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestTokenCache(t *testing.T) {
+	server, calls := newTestTokenServer(t)
+	now := time.Now()
+	cache := NewTokenCache()
+	cache.now = func() time.Time { return now }
+	token := func(clientID string) (*oauth2.Token, error) {
+		conf := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: "secret",
+			TokenURL:     server.URL,
+			Scopes:       []string{"metrics.read"},
+			AuthStyle:    oauth2.AuthStyleInHeader,
+		}
+		return cache.Token(TokenKey(conf), func() (*oauth2.Token, error) {
+			return conf.Token(context.Background())
+		})
+	}
+
+	t.Run("token reused while valid", func(t *testing.T) {
+		first, err := token("perses")
+		require.NoError(t, err)
+		now = now.Add(30 * time.Minute)
+		second, err := token("perses")
+		require.NoError(t, err)
+		assert.Equal(t, first.AccessToken, second.AccessToken)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("token renewed before its expiry", func(t *testing.T) {
+		now = now.Add(29*time.Minute + 45*time.Second)
+		renewed, err := token("perses")
+		require.NoError(t, err)
+		assert.Equal(t, "perses-2", renewed.AccessToken)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("one token per client", func(t *testing.T) {
+		other, err := token("grafana")
+		require.NoError(t, err)
+		assert.Equal(t, "grafana-3", other.AccessToken)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("errors not cached", func(t *testing.T) {
+		_, err := token("broken")
+		assert.Error(t, err)
+		_, err = token("broken")
+		assert.Error(t, err)
+		assert.Equal(t, int32(5), calls.Load())
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenMetricsContentType is the media type of the OpenMetrics text exposition format.
+const OpenMetricsContentType = "application/openmetrics-text"
+
+// IsOpenMetrics returns true when the Content-Type header describes a payload in the OpenMetrics text format.
+func IsOpenMetrics(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == OpenMetricsContentType
+}
+
+// TimeSeriesValue is a sample of a time series. It is encoded in JSON as [<timestamp in ms>, <value>],
+// the value being null when it is not a finite number.
+type TimeSeriesValue struct {
+	Timestamp int64
+	Value     float64
+}
+
+func (v TimeSeriesValue) MarshalJSON() ([]byte, error) {
+	var value any = v.Value
+	if math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+		value = nil
+	}
+	return json.Marshal([]any{v.Timestamp, value})
+}
+
+// TimeSeries is a series of the Perses time series model, as used by the time series panels.
+type TimeSeries struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Values []TimeSeriesValue `json:"values"`
+}
+
+type TimeSeriesData struct {
+	Series []TimeSeries `json:"series"`
+}
+
+// ConvertOpenMetrics parses a payload in the OpenMetrics text format and converts it to the Perses time series model.
+// Every metric point becomes a series named after the metric (including the suffix like _total or _bucket).
+// The points without timestamp are considered as taken at the given time.
+// The metadata (# TYPE, # HELP, # UNIT) and the exemplars are ignored.
+func ConvertOpenMetrics(data []byte, now time.Time) (*TimeSeriesData, error) {
+	result := &TimeSeriesData{Series: []TimeSeries{}}
+	// index of the series in the result by their identity (name and labels).
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if line == "# EOF" {
+			break
+		}
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		series, value, err := parseOpenMetricsSample(line, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OpenMetrics sample at line %d: %w", lineNumber, err)
+		}
+		key := seriesKey(series)
+		if i, ok := index[key]; ok {
+			result.Series[i].Values = append(result.Series[i].Values, value)
+			continue
+		}
+		series.Values = []TimeSeriesValue{value}
+		index[key] = len(result.Series)
+		result.Series = append(result.Series, series)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseOpenMetricsSample parses a line like `name{label="value"} 1.5 1700000000.123 # {trace_id="abc"} 1.0`.
+func parseOpenMetricsSample(line string, now time.Time) (TimeSeries, TimeSeriesValue, error) {
+	var series TimeSeries
+	nameEnd := strings.IndexAny(line, "{ ")
+	if nameEnd <= 0 {
+		return series, TimeSeriesValue{}, fmt.Errorf("missing metric name or value")
+	}
+	series.Name = line[:nameEnd]
+	rest := line[nameEnd:]
+	if strings.HasPrefix(rest, "{") {
+		labels, remaining, err := parseOpenMetricsLabels(rest[1:])
+		if err != nil {
+			return series, TimeSeriesValue{}, err
+		}
+		series.Labels = labels
+		rest = remaining
+	}
+	// the exemplar is separated from the value (and the timestamp) by " # "
+	if exemplarStart := strings.Index(rest, " # "); exemplarStart >= 0 {
+		rest = rest[:exemplarStart]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return series, TimeSeriesValue{}, fmt.Errorf("expected a value and an optional timestamp after the metric %q", series.Name)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return series, TimeSeriesValue{}, fmt.Errorf("invalid value %q", fields[0])
+	}
+	timestamp := now.UnixMilli()
+	if len(fields) == 2 {
+		seconds, parseErr := strconv.ParseFloat(fields[1], 64)
+		if parseErr != nil {
+			return series, TimeSeriesValue{}, fmt.Errorf("invalid timestamp %q", fields[1])
+		}
+		timestamp = int64(math.Round(seconds * 1000))
+	}
+	return series, TimeSeriesValue{Timestamp: timestamp, Value: value}, nil
+}
+
+// parseOpenMetricsLabels parses the labels following the opening brace and returns what follows the closing brace.
+func parseOpenMetricsLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		nameEnd := strings.Index(s, "=")
+		if nameEnd <= 0 {
+			return nil, "", fmt.Errorf("invalid label in %q", s)
+		}
+		name := strings.TrimSpace(s[:nameEnd])
+		s = s[nameEnd+1:]
+		if !strings.HasPrefix(s, `"`) {
+			return nil, "", fmt.Errorf("the value of the label %q must be quoted", name)
+		}
+		value, remaining, err := parseQuotedLabelValue(s[1:])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid value of the label %q: %w", name, err)
+		}
+		labels[name] = value
+		s = strings.TrimLeft(remaining, " ")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		} else if !strings.HasPrefix(s, "}") {
+			return nil, "", fmt.Errorf("expected ',' or '}' after the label %q", name)
+		}
+	}
+}
+
+// parseQuotedLabelValue reads a label value up to its closing quote, handling the escaped characters \\, \" and \n.
+func parseQuotedLabelValue(s string) (string, string, error) {
+	var value strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return value.String(), s[i+1:], nil
+		case '\\':
+			if i+1 == len(s) {
+				return "", "", fmt.Errorf("unterminated escape sequence")
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case '\\', '"':
+				value.WriteByte(s[i])
+			default:
+				return "", "", fmt.Errorf("unknown escape sequence \\%c", s[i])
+			}
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("missing closing quote")
+}
+
+func seriesKey(series TimeSeries) string {
+	names := make([]string, 0, len(series.Labels))
+	for name := range series.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	key.WriteString(series.Name)
+	for _, name := range names {
+		// the values are quoted, so a label value cannot be mistaken for another label
+		key.WriteString("," + name + "=" + strconv.Quote(series.Labels[name]))
+	}
+	return key.String()
+}
@@ -0,0 +1,133 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOpenMetrics(t *testing.T) {
+	assert.True(t, IsOpenMetrics("application/openmetrics-text"))
+	assert.True(t, IsOpenMetrics("application/openmetrics-text; version=1.0.0; charset=utf-8"))
+	assert.False(t, IsOpenMetrics("text/plain; version=0.0.4"))
+	assert.False(t, IsOpenMetrics("application/json"))
+	assert.False(t, IsOpenMetrics(""))
+}
+
+func TestConvertOpenMetrics(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	testSuite := []struct {
+		title   string
+		payload string
+		result  []TimeSeries
+	}{
+		{
+			title: "counter and gauge without timestamp",
+			payload: `# TYPE http_requests counter
+# HELP http_requests The number of HTTP requests.
+http_requests_total{method="GET",code="200"} 1027
+http_requests_created{method="GET",code="200"} 1.6e9
+# TYPE temperature gauge
+# UNIT temperature celsius
+temperature 21.5
+# EOF
+`,
+			result: []TimeSeries{
+				{Name: "http_requests_total", Labels: map[string]string{"method": "GET", "code": "200"}, Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 1027}}},
+				{Name: "http_requests_created", Labels: map[string]string{"method": "GET", "code": "200"}, Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 1.6e9}}},
+				{Name: "temperature", Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 21.5}}},
+			},
+		},
+		{
+			title: "histogram with timestamps and exemplars",
+			payload: `# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 8 1699999990.5 # {trace_id="KOO5S4vxi0o"} 0.067 1699999990.1
+latency_seconds_bucket{le="+Inf"} 10 1699999990.5
+latency_seconds_count 10 1699999990.5
+latency_seconds_sum 1.2 1699999990.5
+# EOF
+`,
+			result: []TimeSeries{
+				{Name: "latency_seconds_bucket", Labels: map[string]string{"le": "0.1"}, Values: []TimeSeriesValue{{Timestamp: 1699999990500, Value: 8}}},
+				{Name: "latency_seconds_bucket", Labels: map[string]string{"le": "+Inf"}, Values: []TimeSeriesValue{{Timestamp: 1699999990500, Value: 10}}},
+				{Name: "latency_seconds_count", Values: []TimeSeriesValue{{Timestamp: 1699999990500, Value: 10}}},
+				{Name: "latency_seconds_sum", Values: []TimeSeriesValue{{Timestamp: 1699999990500, Value: 1.2}}},
+			},
+		},
+		{
+			title: "points of the same series are grouped",
+			payload: `up{instance="a",job="perses"} 1 1700000000
+up{job="perses",instance="a"} 0 1700000015
+up{instance="b",job="perses"} 1 1700000000
+# EOF
+`,
+			result: []TimeSeries{
+				{Name: "up", Labels: map[string]string{"instance": "a", "job": "perses"}, Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 1}, {Timestamp: 1700000015000, Value: 0}}},
+				{Name: "up", Labels: map[string]string{"instance": "b", "job": "perses"}, Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 1}}},
+			},
+		},
+		{
+			title: "escaped label values",
+			payload: `info{path="C:\\perses",quote="say \"hi\"",multiline="a\nb",brace="}"} 1
+# EOF
+`,
+			result: []TimeSeries{
+				{Name: "info", Labels: map[string]string{"path": `C:\perses`, "quote": `say "hi"`, "multiline": "a\nb", "brace": "}"}, Values: []TimeSeriesValue{{Timestamp: 1700000000000, Value: 1}}},
+			},
+		},
+		{
+			title:   "empty payload",
+			payload: "# EOF\n",
+			result:  []TimeSeries{},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			data, err := ConvertOpenMetrics([]byte(test.payload), now)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, data.Series)
+		})
+	}
+}
+
+func TestConvertOpenMetricsError(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		payload string
+	}{
+		{title: "missing value", payload: "up\n"},
+		{title: "invalid value", payload: "up one\n"},
+		{title: "invalid timestamp", payload: "up 1 yesterday\n"},
+		{title: "unquoted label value", payload: "up{job=perses} 1\n"},
+		{title: "unclosed labels", payload: "up{job=\"perses\" 1\n"},
+		{title: "missing closing quote", payload: "up{job=\"perses} 1\n"},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := ConvertOpenMetrics([]byte(test.payload), time.Now())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTimeSeriesValue_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal([]TimeSeriesValue{{Timestamp: 1000, Value: 1.5}, {Timestamp: 2000, Value: math.NaN()}, {Timestamp: 3000, Value: math.Inf(1)}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[[1000, 1.5], [2000, null], [3000, null]]`, string(data))
+}
@@ -108,11 +108,15 @@ func (s *service) List(q *datasource.Query, params apiInterface.Parameters) ([]*
 	if err != nil {
 		return nil, err
 	}
+	selector, err := v1.ParseLabelSelector(query.Labels)
+	if err != nil {
+		return nil, apiInterface.HandleBadRequestError(err.Error())
+	}
 	dtsList, err := s.dao.List(query)
 	if err != nil {
 		return nil, err
 	}
-	return v1.FilterDatasource(query.Kind, query.Default, dtsList), nil
+	return v1.FilterDatasourceByLabels(selector, v1.FilterDatasource(query.Kind, query.Default, dtsList)), nil
 }
 
 func (s *service) RawList(_ *datasource.Query, _ apiInterface.Parameters) ([]json.RawMessage, error) {
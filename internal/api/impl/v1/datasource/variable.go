@@ -0,0 +1,173 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// VariableValue is one of the values a DatasourceVariable can take.
+type VariableValue struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// VariableResolver returns the values of a DatasourceVariable, which are the datasources of a given plugin kind.
+type VariableResolver interface {
+	// Resolve returns the datasources of the project having the given plugin kind.
+	// When withGlobal is true, the global datasources are returned as well, unless a project datasource has the same name.
+	Resolve(project string, pluginKind string, withGlobal bool) ([]VariableValue, error)
+}
+
+type cachedValues struct {
+	values    []VariableValue
+	expiresAt time.Time
+}
+
+type variableResolver struct {
+	dao       datasource.DAO
+	globalDAO globaldatasource.DAO
+	ttl       time.Duration
+	now       func() time.Time
+	mutex     sync.Mutex
+	cache     map[string]cachedValues
+}
+
+func NewVariableResolver(dao datasource.DAO, globalDAO globaldatasource.DAO, ttl time.Duration) VariableResolver {
+	return &variableResolver{
+		dao:       dao,
+		globalDAO: globalDAO,
+		ttl:       ttl,
+		now:       time.Now,
+		cache:     make(map[string]cachedValues),
+	}
+}
+
+func (r *variableResolver) Resolve(project string, pluginKind string, withGlobal bool) ([]VariableValue, error) {
+	key := fmt.Sprintf("%s/%s/%t", project, pluginKind, withGlobal)
+	r.mutex.Lock()
+	cached, ok := r.cache[key]
+	r.mutex.Unlock()
+	if ok && r.now().Before(cached.expiresAt) {
+		return cached.values, nil
+	}
+	values, err := r.list(project, pluginKind, withGlobal)
+	if err != nil {
+		return nil, err
+	}
+	r.mutex.Lock()
+	r.cache[key] = cachedValues{values: values, expiresAt: r.now().Add(r.ttl)}
+	r.mutex.Unlock()
+	return values, nil
+}
+
+func (r *variableResolver) list(project string, pluginKind string, withGlobal bool) ([]VariableValue, error) {
+	valueByName := make(map[string]VariableValue)
+	if withGlobal {
+		globalList, err := r.globalDAO.List(&globaldatasource.Query{Kind: pluginKind})
+		if err != nil {
+			return nil, err
+		}
+		for _, dts := range v1.FilterDatasource(pluginKind, nil, globalList) {
+			valueByName[dts.Metadata.Name] = newVariableValue(dts)
+		}
+	}
+	if len(project) > 0 {
+		list, err := r.dao.List(&datasource.Query{Project: project, Kind: pluginKind})
+		if err != nil {
+			return nil, err
+		}
+		// The project datasources come last as they take precedence over the global ones having the same name.
+		for _, dts := range v1.FilterDatasource(pluginKind, nil, list) {
+			valueByName[dts.Metadata.Name] = newVariableValue(dts)
+		}
+	}
+	values := make([]VariableValue, 0, len(valueByName))
+	for _, value := range valueByName {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Value < values[j].Value
+	})
+	return values, nil
+}
+
+func newVariableValue(dts v1.DatasourceInterface) VariableValue {
+	name := dts.GetMetadata().GetName()
+	value := VariableValue{Value: name, Label: name}
+	if display := dts.GetDatasourceSpec().Display; display != nil && len(display.Name) > 0 {
+		value.Label = display.Name
+	}
+	return value
+}
+
+type variableEndpoint struct {
+	resolver       VariableResolver
+	authz          authorization.Authorization
+	projectDisable bool
+	globalDisable  bool
+}
+
+// NewVariableEndpoint returns the endpoint used by the DatasourceVariable to list the datasources of a given plugin kind.
+func NewVariableEndpoint(cfg config.DatasourceConfig, resolver VariableResolver, authz authorization.Authorization) route.Endpoint {
+	return &variableEndpoint{
+		resolver:       resolver,
+		authz:          authz,
+		projectDisable: cfg.Project.Disable,
+		globalDisable:  cfg.Global.Disable,
+	}
+}
+
+func (e *variableEndpoint) CollectRoutes(g *route.Group) {
+	if e.projectDisable && e.globalDisable {
+		return
+	}
+	g.GET(fmt.Sprintf("/%s/:%s/datasourcevariable", utils.PathProject, utils.ParamProject), e.Resolve, false)
+}
+
+func (e *variableEndpoint) Resolve(ctx echo.Context) error {
+	project := utils.GetProjectParameter(ctx)
+	pluginKind := ctx.QueryParam("kind")
+	if len(pluginKind) == 0 {
+		return apiInterface.HandleBadRequestError("the query parameter 'kind' is required")
+	}
+	if !e.projectDisable {
+		if !e.authz.HasPermission(ctx, role.ReadAction, project, role.DatasourceScope) {
+			return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, project, role.DatasourceScope))
+		}
+	} else {
+		project = ""
+	}
+	withGlobal := !e.globalDisable && e.authz.HasPermission(ctx, role.ReadAction, v1.WildcardProject, role.GlobalDatasourceScope)
+	values, err := e.resolver.Resolve(project, pluginKind, withGlobal)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, values)
+}
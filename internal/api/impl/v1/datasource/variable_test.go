@@ -0,0 +1,174 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/globaldatasource"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/spec/go/common"
+	datasourceSpec "github.com/perses/spec/go/datasource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDAO struct {
+	datasource.DAO
+	list  []*v1.Datasource
+	calls int
+}
+
+func (d *fakeDAO) List(q *datasource.Query) ([]*v1.Datasource, error) {
+	d.calls++
+	var result []*v1.Datasource
+	for _, dts := range d.list {
+		if dts.Metadata.Project == q.Project {
+			result = append(result, dts)
+		}
+	}
+	return result, nil
+}
+
+type fakeGlobalDAO struct {
+	globaldatasource.DAO
+	list []*v1.GlobalDatasource
+}
+
+func (d *fakeGlobalDAO) List(_ *globaldatasource.Query) ([]*v1.GlobalDatasource, error) {
+	return d.list, nil
+}
+
+func newDatasource(project string, name string, pluginKind string, displayName string) *v1.Datasource {
+	dts := &v1.Datasource{
+		Kind: v1.KindDatasource,
+		Metadata: v1.ProjectMetadata{
+			Metadata: v1.Metadata{
+				Name: name,
+			},
+			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+				Project: project,
+			},
+		},
+		Spec: datasourceSpec.Spec{
+			Plugin: common.Plugin{Kind: pluginKind},
+		},
+	}
+	if len(displayName) > 0 {
+		dts.Spec.Display = &common.Display{Name: displayName}
+	}
+	return dts
+}
+
+func newGlobalDatasource(name string, pluginKind string) *v1.GlobalDatasource {
+	return &v1.GlobalDatasource{
+		Kind:     v1.KindGlobalDatasource,
+		Metadata: v1.Metadata{Name: name},
+		Spec: datasourceSpec.Spec{
+			Plugin: common.Plugin{Kind: pluginKind},
+		},
+	}
+}
+
+func TestVariableResolver(t *testing.T) {
+	dao := &fakeDAO{list: []*v1.Datasource{
+		newDatasource("perses", "prom", "PrometheusDatasource", "Prometheus"),
+		newDatasource("perses", "tempo", "TempoDatasource", ""),
+		newDatasource("perses", "thanos", "PrometheusDatasource", ""),
+		newDatasource("other", "other-prom", "PrometheusDatasource", ""),
+	}}
+	globalDAO := &fakeGlobalDAO{list: []*v1.GlobalDatasource{
+		newGlobalDatasource("global-prom", "PrometheusDatasource"),
+		newGlobalDatasource("global-tempo", "TempoDatasource"),
+		newGlobalDatasource("thanos", "PrometheusDatasource"),
+	}}
+	testSuite := []struct {
+		title      string
+		project    string
+		pluginKind string
+		withGlobal bool
+		result     []VariableValue
+	}{
+		{
+			title:      "project datasources of the given kind",
+			project:    "perses",
+			pluginKind: "PrometheusDatasource",
+			result: []VariableValue{
+				{Value: "prom", Label: "Prometheus"},
+				{Value: "thanos", Label: "thanos"},
+			},
+		},
+		{
+			title:      "project and global datasources of the given kind",
+			project:    "perses",
+			pluginKind: "TempoDatasource",
+			withGlobal: true,
+			result: []VariableValue{
+				{Value: "global-tempo", Label: "global-tempo"},
+				{Value: "tempo", Label: "tempo"},
+			},
+		},
+		{
+			title:      "project datasource takes precedence over the global one",
+			project:    "perses",
+			pluginKind: "PrometheusDatasource",
+			withGlobal: true,
+			result: []VariableValue{
+				{Value: "global-prom", Label: "global-prom"},
+				{Value: "prom", Label: "Prometheus"},
+				{Value: "thanos", Label: "thanos"},
+			},
+		},
+		{
+			title:      "no datasource of the given kind",
+			project:    "perses",
+			pluginKind: "LokiDatasource",
+			withGlobal: true,
+			result:     []VariableValue{},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			resolver := NewVariableResolver(dao, globalDAO, time.Minute)
+			values, err := resolver.Resolve(test.project, test.pluginKind, test.withGlobal)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, values)
+		})
+	}
+}
+
+func TestVariableResolverCache(t *testing.T) {
+	dao := &fakeDAO{list: []*v1.Datasource{newDatasource("perses", "prom", "PrometheusDatasource", "")}}
+	resolver := NewVariableResolver(dao, &fakeGlobalDAO{}, time.Minute).(*variableResolver)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	_, err := resolver.Resolve("perses", "PrometheusDatasource", false)
+	require.NoError(t, err)
+	_, err = resolver.Resolve("perses", "PrometheusDatasource", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dao.calls)
+
+	// another kind is not served from the cache
+	_, err = resolver.Resolve("perses", "TempoDatasource", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dao.calls)
+
+	now = now.Add(2 * time.Minute)
+	_, err = resolver.Resolve("perses", "PrometheusDatasource", false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, dao.calls)
+}
@@ -16,6 +16,7 @@ package ephemeraldashboard
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/brunoga/deep"
 	"github.com/labstack/echo/v4"
@@ -27,23 +28,26 @@ import (
 	"github.com/perses/perses/internal/api/validate"
 	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	dashboardModel "github.com/perses/perses/pkg/model/api/v1/dashboard"
 	"github.com/sirupsen/logrus"
 )
 
 type service struct {
 	ephemeraldashboard.Service
-	dao           ephemeraldashboard.DAO
-	globalVarDAO  globalvariable.DAO
-	projectVarDAO variable.DAO
-	sch           schema.Schema
+	dao                ephemeraldashboard.DAO
+	globalVarDAO       globalvariable.DAO
+	projectVarDAO      variable.DAO
+	sch                schema.Schema
+	minRefreshInterval time.Duration
 }
 
-func NewService(dao ephemeraldashboard.DAO, globalVarDAO globalvariable.DAO, projectVarDAO variable.DAO, sch schema.Schema) ephemeraldashboard.Service {
+func NewService(dao ephemeraldashboard.DAO, globalVarDAO globalvariable.DAO, projectVarDAO variable.DAO, sch schema.Schema, minRefreshInterval time.Duration) ephemeraldashboard.Service {
 	return &service{
-		dao:           dao,
-		globalVarDAO:  globalVarDAO,
-		projectVarDAO: projectVarDAO,
-		sch:           sch,
+		dao:                dao,
+		globalVarDAO:       globalVarDAO,
+		projectVarDAO:      projectVarDAO,
+		sch:                sch,
+		minRefreshInterval: minRefreshInterval,
 	}
 }
 
@@ -161,6 +165,9 @@ func (s *service) Validate(entity *v1.EphemeralDashboard) error {
 	if err := validate.DashboardSpecWithVars(entity.Spec.Spec, s.sch, projectVars, globalVars); err != nil {
 		return apiInterface.HandleBadRequestError(err.Error())
 	}
+	if _, err := dashboardModel.NewRefreshInterval(entity.Spec.RefreshInterval, s.minRefreshInterval); err != nil {
+		return apiInterface.HandleBadRequestError(err.Error())
+	}
 	return nil
 }
 
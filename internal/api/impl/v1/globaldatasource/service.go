@@ -99,11 +99,15 @@ func (s *service) Get(parameters apiInterface.Parameters) (*v1.GlobalDatasource,
 }
 
 func (s *service) List(q *globaldatasource.Query, _ apiInterface.Parameters) ([]*v1.GlobalDatasource, error) {
+	selector, err := v1.ParseLabelSelector(q.Labels)
+	if err != nil {
+		return nil, apiInterface.HandleBadRequestError(err.Error())
+	}
 	dtsList, err := s.dao.List(q)
 	if err != nil {
 		return nil, err
 	}
-	return v1.FilterDatasource(q.Kind, q.Default, dtsList), nil
+	return v1.FilterDatasourceByLabels(selector, v1.FilterDatasource(q.Kind, q.Default, dtsList)), nil
 }
 
 func (s *service) MetadataList(_ *globaldatasource.Query, _ apiInterface.Parameters) ([]api.Entity, error) {
@@ -15,6 +15,8 @@ package plugin
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	apiinterface "github.com/perses/perses/internal/api/interface"
@@ -40,6 +42,7 @@ func NewEndpoint(svc plugin.Plugin, enableDev bool) route.Endpoint {
 func (e *endpoint) CollectRoutes(g *route.Group) {
 	group := g.Group("/plugins")
 	group.GET("", e.List, true)
+	group.GET("/installed", e.ListInstalled, true)
 	if e.enableDev {
 		devGroup := group.Group("/dev")
 		devGroup.POST("", e.PushDevPlugin, true)
@@ -57,6 +60,17 @@ func (e *endpoint) List(ctx echo.Context) error {
 	return ctx.Blob(http.StatusOK, "application/json", d)
 }
 
+// ListInstalled returns the plugins installed, optionally filtered by their kind with the query parameter `kind`.
+func (e *endpoint) ListInstalled(ctx echo.Context) error {
+	result := e.svc.ListInstalled()
+	if kind := ctx.QueryParam("kind"); len(kind) > 0 {
+		result = slices.DeleteFunc(result, func(info pluginModel.PluginInfo) bool {
+			return !strings.EqualFold(string(info.Kind), kind)
+		})
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
 func (e *endpoint) PushDevPlugin(ctx echo.Context) error {
 	var list []v1.PluginInDevelopment
 	if err := ctx.Bind(&list); err != nil {
@@ -0,0 +1,87 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/plugin"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	pluginModel "github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule creates a plugin module in the folder. Its plugins must be of the kind Explore, which doesn't require any schema.
+func writeModule(t *testing.T, folder string, name string, version string, plugins string) {
+	modulePath := filepath.Join(folder, name)
+	require.NoError(t, os.MkdirAll(modulePath, 0o750))
+	manifest := fmt.Sprintf(`{"id":%q,"name":%q,"metaData":{"buildInfo":{"buildVersion":%q}}}`, name, name, version)
+	require.NoError(t, os.WriteFile(filepath.Join(modulePath, plugin.ManifestFileName), []byte(manifest), 0o600))
+	pkg := fmt.Sprintf(`{"perses":{"plugins":%s}}`, plugins)
+	require.NoError(t, os.WriteFile(filepath.Join(modulePath, plugin.PackageJSONFile), []byte(pkg), 0o600))
+}
+
+func TestListInstalled(t *testing.T) {
+	folder := t.TempDir()
+	writeModule(t, folder, "foo", "0.1.0", `[{"kind":"Explore","spec":{"name":"FooExplorer"}}]`)
+	writeModule(t, folder, "bar", "1.2.0", `[{"kind":"Explore","spec":{"name":"BarExplorer"}},{"kind":"Explore","spec":{"name":"BarLogs"}}]`)
+	svc := plugin.New(config.Plugin{Path: common.NonEmptyString(folder)})
+	require.NoError(t, svc.Load())
+
+	barExplorer := pluginModel.PluginInfo{Name: "BarExplorer", Module: "bar", Version: "1.2.0", Kind: pluginModel.KindExplore, Path: filepath.Join(folder, "bar"), Enabled: true}
+	barLogs := pluginModel.PluginInfo{Name: "BarLogs", Module: "bar", Version: "1.2.0", Kind: pluginModel.KindExplore, Path: filepath.Join(folder, "bar"), Enabled: true}
+	fooExplorer := pluginModel.PluginInfo{Name: "FooExplorer", Module: "foo", Version: "0.1.0", Kind: pluginModel.KindExplore, Path: filepath.Join(folder, "foo"), Enabled: true}
+	testSuite := []struct {
+		title  string
+		url    string
+		result []pluginModel.PluginInfo
+	}{
+		{
+			title:  "every plugin",
+			url:    "/api/v1/plugins/installed",
+			result: []pluginModel.PluginInfo{barExplorer, barLogs, fooExplorer},
+		},
+		{
+			title:  "filtered by kind",
+			url:    "/api/v1/plugins/installed?kind=explore",
+			result: []pluginModel.PluginInfo{barExplorer, barLogs, fooExplorer},
+		},
+		{
+			title:  "no plugin of the kind",
+			url:    "/api/v1/plugins/installed?kind=panel",
+			result: []pluginModel.PluginInfo{},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			ept := NewEndpoint(svc, false).(*endpoint)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, test.url, nil), rec)
+			require.NoError(t, ept.ListInstalled(ctx))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var result []pluginModel.PluginInfo
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
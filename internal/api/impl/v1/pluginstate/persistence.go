@@ -0,0 +1,52 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginstate
+
+import (
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type dao struct {
+	pluginstate.DAO
+	client databaseModel.DAO
+	kind   v1.Kind
+}
+
+func NewDAO(persesDAO databaseModel.DAO) pluginstate.DAO {
+	return &dao{
+		client: persesDAO,
+		kind:   v1.KindPluginState,
+	}
+}
+
+func (d *dao) Upsert(entity *v1.PluginState) error {
+	return d.client.Upsert(entity)
+}
+
+func (d *dao) Delete(module string, key string) error {
+	return d.client.Delete(d.kind, v1.NewProjectMetadata(module, key))
+}
+
+func (d *dao) Get(module string, key string) (*v1.PluginState, error) {
+	entity := &v1.PluginState{}
+	return entity, d.client.Get(d.kind, v1.NewProjectMetadata(module, key), entity)
+}
+
+func (d *dao) List(q *pluginstate.Query) ([]*v1.PluginState, error) {
+	var result []*v1.PluginState
+	err := d.client.Query(q, &result)
+	return result, err
+}
@@ -17,25 +17,34 @@ package project
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/project"
 	"github.com/perses/perses/internal/api/route"
 	"github.com/perses/perses/internal/api/toolbox"
 	"github.com/perses/perses/internal/api/utils"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
 )
 
 type endpoint struct {
-	toolbox  toolbox.Toolbox[*v1.Project, *project.Query]
-	readonly bool
+	toolbox       toolbox.Toolbox[*v1.Project, *project.Query]
+	service       project.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
 }
 
 func NewEndpoint(service project.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
 	return &endpoint{
-		toolbox:  toolbox.New[*v1.Project, *v1.Project, *project.Query](service, authz, v1.KindProject, caseSensitive),
-		readonly: readonly,
+		toolbox:       toolbox.New[*v1.Project, *v1.Project, *project.Query](service, authz, v1.KindProject, caseSensitive),
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
 	}
 }
 
@@ -49,6 +58,7 @@ func (e *endpoint) CollectRoutes(g *route.Group) {
 	}
 	group.GET("", e.List, false)
 	group.GET(fmt.Sprintf("/:%s", utils.ParamName), e.Get, false)
+	group.GET(fmt.Sprintf("/:%s/%s", utils.ParamName, utils.PathStats), e.Stats, false)
 }
 
 func (e *endpoint) Create(ctx echo.Context) error {
@@ -73,3 +83,17 @@ func (e *endpoint) List(ctx echo.Context) error {
 	q := &project.Query{}
 	return e.toolbox.List(ctx, q)
 }
+
+func (e *endpoint) Stats(ctx echo.Context) error {
+	parameters := toolbox.ExtractParameters(ctx, e.caseSensitive)
+	if e.authz.IsEnabled() {
+		if ok := e.authz.HasPermission(ctx, role.ReadAction, parameters.Name, role.ProjectScope); !ok {
+			return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, parameters.Name, role.ProjectScope))
+		}
+	}
+	stats, err := e.service.Stats(parameters.Name)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, stats)
+}
@@ -21,13 +21,17 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/perses/perses/internal/api/authorization"
 	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/apikey"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardhistory"
+	"github.com/perses/perses/internal/api/interface/v1/dashboardtemplate"
 	"github.com/perses/perses/internal/api/interface/v1/datasource"
 	"github.com/perses/perses/internal/api/interface/v1/folder"
 	"github.com/perses/perses/internal/api/interface/v1/project"
 	"github.com/perses/perses/internal/api/interface/v1/role"
 	"github.com/perses/perses/internal/api/interface/v1/rolebinding"
 	"github.com/perses/perses/internal/api/interface/v1/secret"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/internal/api/interface/v1/variable"
 	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
@@ -41,32 +45,46 @@ type service struct {
 	folderDAO      folder.DAO
 	datasourceDAO  datasource.DAO
 	dashboardDAO   dashboard.DAO
+	historyDAO     dashboardhistory.DAO
+	templateDAO    dashboardtemplate.DAO
+	favoriteDAO    userfavorite.DAO
 	roleDAO        role.DAO
 	roleBindingDAO rolebinding.DAO
 	secretDAO      secret.DAO
 	variableDAO    variable.DAO
+	apiKeyDAO      apikey.DAO
 	authz          authorization.Authorization
+	stats          *statsCache
 }
 
 func NewService(dao project.DAO,
 	folderDAO folder.DAO,
 	datasourceDAO datasource.DAO,
 	dashboardDAO dashboard.DAO,
+	historyDAO dashboardhistory.DAO,
+	templateDAO dashboardtemplate.DAO,
+	favoriteDAO userfavorite.DAO,
 	roleDAO role.DAO,
 	roleBindingDAO rolebinding.DAO,
 	secretDAO secret.DAO,
 	variableDAO variable.DAO,
+	apiKeyDAO apikey.DAO,
 	authz authorization.Authorization) project.Service {
 	return &service{
 		dao:            dao,
 		folderDAO:      folderDAO,
 		datasourceDAO:  datasourceDAO,
 		dashboardDAO:   dashboardDAO,
+		historyDAO:     historyDAO,
+		templateDAO:    templateDAO,
+		favoriteDAO:    favoriteDAO,
 		roleDAO:        roleDAO,
 		roleBindingDAO: roleBindingDAO,
 		secretDAO:      secretDAO,
 		variableDAO:    variableDAO,
+		apiKeyDAO:      apiKeyDAO,
 		authz:          authz,
+		stats:          newStatsCache(statsCacheTTL),
 	}
 }
 
@@ -158,6 +176,18 @@ func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) err
 		logrus.WithError(err).Error("unable to delete all dashboards")
 		return err
 	}
+	if err := s.historyDAO.DeleteHistory(projectName, ""); err != nil {
+		logrus.WithError(err).Error("unable to delete the history of the dashboards")
+		return err
+	}
+	if err := s.favoriteDAO.DeleteDashboard(projectName, ""); err != nil {
+		logrus.WithError(err).Error("unable to remove the dashboards from the favorites")
+		return err
+	}
+	if err := s.templateDAO.DeleteAll(projectName); err != nil {
+		logrus.WithError(err).Error("unable to delete all dashboard templates")
+		return err
+	}
 	if err := s.datasourceDAO.DeleteAll(projectName); err != nil {
 		logrus.WithError(err).Error("unable to delete all datasources")
 		return err
@@ -178,6 +208,10 @@ func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) err
 		logrus.WithError(err).Error("unable to delete all roles")
 		return err
 	}
+	if err := s.apiKeyDAO.DeleteAll(projectName); err != nil {
+		logrus.WithError(err).Error("unable to delete all API keys")
+		return err
+	}
 	if s.authz.IsEnabled() {
 		if err := s.authz.RefreshPermissions(); err != nil {
 			return err
@@ -0,0 +1,118 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/datasource"
+	"github.com/perses/perses/internal/api/interface/v1/variable"
+	"github.com/perses/perses/pkg/model/api"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+// statsCacheTTL is the duration during which the stats of a project are served from the cache.
+const statsCacheTTL = 30 * time.Second
+
+type cachedStats struct {
+	stats     *v1.ProjectStats
+	expiredAt time.Time
+}
+
+// statsCache is keeping the stats computed per project to avoid listing every resource on each call.
+type statsCache struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	items map[string]cachedStats
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{
+		ttl:   ttl,
+		items: make(map[string]cachedStats),
+	}
+}
+
+func (c *statsCache) get(project string) (*v1.ProjectStats, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	item, ok := c.items[project]
+	if !ok || time.Now().After(item.expiredAt) {
+		return nil, false
+	}
+	return item.stats, true
+}
+
+func (c *statsCache) set(project string, stats *v1.ProjectStats) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.items[project] = cachedStats{stats: stats, expiredAt: time.Now().Add(c.ttl)}
+}
+
+func (s *service) Stats(name string) (*v1.ProjectStats, error) {
+	if stats, ok := s.stats.get(name); ok {
+		return stats, nil
+	}
+	project, err := s.dao.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	stats := &v1.ProjectStats{
+		CreatedAt:      project.Metadata.CreatedAt,
+		LastModifiedAt: project.Metadata.UpdatedAt,
+	}
+	dashboards, err := s.dashboardDAO.MetadataList(&dashboard.Query{Project: name})
+	if err != nil {
+		return nil, err
+	}
+	stats.DashboardCount = len(dashboards)
+	stats.LastModifiedAt = lastModifiedAt(stats.LastModifiedAt, dashboards)
+
+	datasources, err := s.datasourceDAO.List(&datasource.Query{Project: name})
+	if err != nil {
+		return nil, err
+	}
+	stats.DatasourceCount = len(datasources)
+	for _, dts := range datasources {
+		if dts.Metadata.UpdatedAt.After(stats.LastModifiedAt) {
+			stats.LastModifiedAt = dts.Metadata.UpdatedAt
+		}
+	}
+
+	variables, err := s.variableDAO.MetadataList(&variable.Query{Project: name})
+	if err != nil {
+		return nil, err
+	}
+	stats.VariableCount = len(variables)
+	stats.LastModifiedAt = lastModifiedAt(stats.LastModifiedAt, variables)
+
+	s.stats.set(name, stats)
+	return stats, nil
+}
+
+// lastModifiedAt returns the most recent date between the one given and the update date of each entity.
+func lastModifiedAt(current time.Time, entities []api.Entity) time.Time {
+	for _, entity := range entities {
+		metadata, ok := entity.GetMetadata().(*v1.ProjectMetadata)
+		if !ok {
+			continue
+		}
+		if metadata.UpdatedAt.After(current) {
+			current = metadata.UpdatedAt
+		}
+	}
+	return current
+}
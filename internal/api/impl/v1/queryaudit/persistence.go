@@ -0,0 +1,41 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryaudit
+
+import (
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type dao struct {
+	queryaudit.DAO
+	client databaseModel.DAO
+}
+
+func NewDAO(persesDAO databaseModel.DAO) queryaudit.DAO {
+	return &dao{
+		client: persesDAO,
+	}
+}
+
+func (d *dao) Create(entity *v1.QueryAudit) error {
+	return d.client.Create(entity)
+}
+
+func (d *dao) List(q *queryaudit.Query) ([]*v1.QueryAudit, error) {
+	var result []*v1.QueryAudit
+	err := d.client.Query(q, &result)
+	return result, err
+}
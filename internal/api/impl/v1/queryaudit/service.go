@@ -0,0 +1,65 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryaudit
+
+import (
+	"sort"
+
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type service struct {
+	queryaudit.Service
+	dao queryaudit.DAO
+}
+
+func NewService(dao queryaudit.DAO) queryaudit.Service {
+	return &service{
+		dao: dao,
+	}
+}
+
+func (s *service) Record(spec v1.QueryAuditSpec) error {
+	return s.dao.Create(v1.NewQueryAudit(spec))
+}
+
+func (s *service) List(q *queryaudit.ListQuery) (*queryaudit.List, error) {
+	records, err := s.dao.List(&q.Query)
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]*v1.QueryAudit, 0, len(records))
+	for _, record := range records {
+		if q.Since.IsZero() || !record.Spec.Time.Before(q.Since) {
+			matching = append(matching, record)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Spec.Time.After(matching[j].Spec.Time)
+	})
+	result := &queryaudit.List{
+		Items: []*v1.QueryAudit{},
+		Total: len(matching),
+		Page:  q.Page,
+		Size:  q.Size,
+	}
+	start := (q.Page - 1) * q.Size
+	if start >= len(matching) {
+		return result, nil
+	}
+	end := min(start+q.Size, len(matching))
+	result.Items = matching[start:end]
+	return result, nil
+}
@@ -0,0 +1,117 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryaudit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perses/perses/internal/api/interface/v1/queryaudit"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDAO struct {
+	queryaudit.DAO
+	records []*v1.QueryAudit
+}
+
+func (d *memoryDAO) Create(entity *v1.QueryAudit) error {
+	d.records = append(d.records, entity)
+	return nil
+}
+
+func (d *memoryDAO) List(q *queryaudit.Query) ([]*v1.QueryAudit, error) {
+	var result []*v1.QueryAudit
+	for _, record := range d.records {
+		if len(q.Datasource) == 0 || record.Metadata.Project == q.Datasource {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func TestService(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	dao := &memoryDAO{}
+	svc := NewService(dao)
+	for i := range 5 {
+		require.NoError(t, svc.Record(v1.QueryAuditSpec{
+			Project:    "perses",
+			Datasource: "prometheus",
+			Method:     "GET",
+			Path:       "/api/v1/query",
+			Query:      "up",
+			LatencyMs:  int64(i),
+			Status:     200,
+			Time:       now.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	require.NoError(t, svc.Record(v1.QueryAuditSpec{Datasource: "thanos", Status: 502, Time: now}))
+	require.Len(t, dao.records, 6)
+	assert.Equal(t, string(v1.KindQueryAudit), dao.records[0].Kind)
+	assert.Equal(t, "prometheus", dao.records[0].Metadata.Project)
+	assert.NotEqual(t, dao.records[0].Metadata.Name, dao.records[5].Metadata.Name)
+
+	testSuite := []struct {
+		title     string
+		query     queryaudit.ListQuery
+		total     int
+		latencyMs []int64
+	}{
+		{
+			title:     "first page, most recent first",
+			query:     queryaudit.ListQuery{Query: queryaudit.Query{Datasource: "prometheus"}, Page: 1, Size: 2},
+			total:     5,
+			latencyMs: []int64{4, 3},
+		},
+		{
+			title:     "last page",
+			query:     queryaudit.ListQuery{Query: queryaudit.Query{Datasource: "prometheus"}, Page: 3, Size: 2},
+			total:     5,
+			latencyMs: []int64{0},
+		},
+		{
+			title:     "page after the last one",
+			query:     queryaudit.ListQuery{Query: queryaudit.Query{Datasource: "prometheus"}, Page: 4, Size: 2},
+			total:     5,
+			latencyMs: []int64{},
+		},
+		{
+			title:     "since",
+			query:     queryaudit.ListQuery{Query: queryaudit.Query{Datasource: "prometheus"}, Since: now.Add(3 * time.Minute), Page: 1, Size: 10},
+			total:     2,
+			latencyMs: []int64{4, 3},
+		},
+		{
+			title:     "every datasource",
+			query:     queryaudit.ListQuery{Page: 1, Size: 10},
+			total:     6,
+			latencyMs: []int64{4, 3, 2, 1, 0, 0},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := svc.List(&test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.total, result.Total)
+			latencyMs := []int64{}
+			for _, item := range result.Items {
+				latencyMs = append(latencyMs, item.Spec.LatencyMs)
+			}
+			assert.Equal(t, test.latencyMs, latencyMs)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/perses/perses/internal/api/crypto"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
 	"github.com/perses/perses/pkg/model/api"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/sirupsen/logrus"
@@ -30,14 +31,16 @@ import (
 
 type service struct {
 	user.Service
-	dao   user.DAO
-	authz authorization.Authorization
+	dao         user.DAO
+	favoriteDAO userfavorite.DAO
+	authz       authorization.Authorization
 }
 
-func NewService(dao user.DAO, authz authorization.Authorization) user.Service {
+func NewService(dao user.DAO, favoriteDAO userfavorite.DAO, authz authorization.Authorization) user.Service {
 	return &service{
-		dao:   dao,
-		authz: authz,
+		dao:         dao,
+		favoriteDAO: favoriteDAO,
+		authz:       authz,
 	}
 }
 
@@ -126,6 +129,9 @@ func (s *service) Delete(_ echo.Context, parameters apiInterface.Parameters) err
 	if err != nil {
 		return err
 	}
+	if deleteErr := s.favoriteDAO.DeleteUser(parameters.Name); deleteErr != nil {
+		logrus.WithError(deleteErr).Errorf("unable to delete the favorites of the user %q", parameters.Name)
+	}
 	// Refreshing RBAC cache as the user's associated role may be updated, which can add or remove permissions.
 	if err := s.authz.RefreshPermissions(); err != nil {
 		logrus.WithError(err).Error("failed to refresh RBAC cache")
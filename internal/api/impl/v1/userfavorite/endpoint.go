@@ -0,0 +1,184 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfavorite
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/perses/perses/internal/api/authorization"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
+	"github.com/perses/perses/internal/api/route"
+	"github.com/perses/perses/internal/api/toolbox"
+	"github.com/perses/perses/internal/api/utils"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+// currentUser can be used in place of the name of the user in the path to designate the authenticated user.
+const currentUser = "me"
+
+// dashboardRef is the body of the POST requests adding a dashboard to the favorites.
+type dashboardRef struct {
+	Project   string `json:"project"`
+	Dashboard string `json:"dashboard"`
+}
+
+type endpoint struct {
+	service       userfavorite.Service
+	authz         authorization.Authorization
+	readonly      bool
+	caseSensitive bool
+}
+
+func NewEndpoint(service userfavorite.Service, authz authorization.Authorization, readonly bool, caseSensitive bool) route.Endpoint {
+	return &endpoint{
+		service:       service,
+		authz:         authz,
+		readonly:      readonly,
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (e *endpoint) CollectRoutes(g *route.Group) {
+	group := g.Group(fmt.Sprintf("/%s/:%s/%s", utils.PathUser, utils.ParamName, utils.PathFavorite))
+	if !e.readonly {
+		group.PUT(fmt.Sprintf("/:%s/:%s", utils.ParamProject, utils.ParamDashboard), e.add, false)
+		group.POST("", e.addFromBody, false)
+		group.DELETE(fmt.Sprintf("/:%s/:%s", utils.ParamProject, utils.ParamDashboard), e.remove, false)
+	}
+	group.GET("", e.list, false)
+
+	// The favorites of the authenticated user are also available under /api/v1/user/favorites.
+	currentUserGroup := g.Group(fmt.Sprintf("/%s/%s", utils.PathCurrentUser, utils.PathFavorite))
+	if !e.readonly {
+		currentUserGroup.POST("", e.addFromBody, false)
+		currentUserGroup.DELETE(fmt.Sprintf("/:%s/:%s", utils.ParamProject, utils.ParamDashboard), e.remove, false)
+	}
+	currentUserGroup.GET("", e.list, false)
+}
+
+func (e *endpoint) list(ctx echo.Context) error {
+	user, err := e.extractUser(ctx)
+	if err != nil {
+		return err
+	}
+	list, err := e.service.List(user)
+	if err != nil {
+		return err
+	}
+	if !e.authz.IsEnabled() {
+		return ctx.JSON(http.StatusOK, list)
+	}
+	// The user may have lost the permission to read some of the dashboards since they were added to the favorites.
+	result := make([]*v1.PartialProjectEntity, 0, len(list))
+	for _, entity := range list {
+		if e.authz.HasPermission(ctx, role.ReadAction, entity.Metadata.Project, role.DashboardScope) {
+			result = append(result, entity)
+		}
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (e *endpoint) add(ctx echo.Context) error {
+	user, err := e.extractUser(ctx)
+	if err != nil {
+		return err
+	}
+	project, dashboard := e.extractDashboard(ctx)
+	return e.addDashboard(ctx, user, project, dashboard)
+}
+
+func (e *endpoint) addFromBody(ctx echo.Context) error {
+	user, err := e.extractUser(ctx)
+	if err != nil {
+		return err
+	}
+	ref := dashboardRef{}
+	if bindErr := ctx.Bind(&ref); bindErr != nil {
+		return apiInterface.HandleBadRequestError(bindErr.Error())
+	}
+	if len(ref.Project) == 0 || len(ref.Dashboard) == 0 {
+		return apiInterface.HandleBadRequestError("project and dashboard cannot be empty")
+	}
+	if !e.caseSensitive {
+		ref.Project = strings.ToLower(ref.Project)
+		ref.Dashboard = strings.ToLower(ref.Dashboard)
+	}
+	return e.addDashboard(ctx, user, ref.Project, ref.Dashboard)
+}
+
+func (e *endpoint) addDashboard(ctx echo.Context, user string, project string, dashboard string) error {
+	if e.authz.IsEnabled() && !e.authz.HasPermission(ctx, role.ReadAction, project, role.DashboardScope) {
+		return apiInterface.HandleForbiddenError(fmt.Sprintf("missing '%s' permission in '%s' project for '%s' kind", role.ReadAction, project, role.DashboardScope))
+	}
+	if addErr := e.service.Add(user, project, dashboard); addErr != nil {
+		return addErr
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (e *endpoint) remove(ctx echo.Context) error {
+	user, err := e.extractUser(ctx)
+	if err != nil {
+		return err
+	}
+	project, dashboard := e.extractDashboard(ctx)
+	if removeErr := e.service.Remove(user, project, dashboard); removeErr != nil {
+		return removeErr
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// extractUser returns the user of the path, who must be the authenticated user when the authorization is enabled.
+// The paths without a user, and the user "me", resolve to the authenticated user.
+func (e *endpoint) extractUser(ctx echo.Context) (string, error) {
+	// Like for the permissions, the name of the user can be encoded in the path.
+	user, err := url.PathUnescape(toolbox.ExtractParameters(ctx, e.caseSensitive).Name)
+	if err != nil {
+		return "", apiInterface.HandleBadRequestError(err.Error())
+	}
+	isCurrentUser := len(user) == 0 || user == currentUser
+	if !e.authz.IsEnabled() {
+		if isCurrentUser {
+			return "", apiInterface.HandleUnauthorizedError("authentication is required to manage the favorites of the current user")
+		}
+		return user, nil
+	}
+	username, err := e.authz.GetUsername(ctx)
+	if err != nil || len(username) == 0 {
+		return "", apiInterface.HandleUnauthorizedError("failed to retrieve username from context")
+	}
+	if isCurrentUser {
+		return username, nil
+	}
+	if username != user {
+		return "", apiInterface.HandleForbiddenError("you can only manage your own favorites")
+	}
+	return user, nil
+}
+
+func (e *endpoint) extractDashboard(ctx echo.Context) (string, string) {
+	project := utils.GetProjectParameter(ctx)
+	dashboard := ctx.Param(utils.ParamDashboard)
+	if !e.caseSensitive {
+		project = strings.ToLower(project)
+		dashboard = strings.ToLower(dashboard)
+	}
+	return project, dashboard
+}
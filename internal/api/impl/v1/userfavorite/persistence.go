@@ -0,0 +1,106 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfavorite
+
+import (
+	"strings"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type dao struct {
+	userfavorite.DAO
+	client databaseModel.DAO
+	kind   v1.Kind
+}
+
+func NewDAO(persesDAO databaseModel.DAO) userfavorite.DAO {
+	return &dao{
+		client: persesDAO,
+		kind:   v1.KindUserFavorite,
+	}
+}
+
+func (d *dao) Create(entity *v1.UserFavorite) error {
+	entity.Spec.UserName = d.storedName(entity.Spec.UserName)
+	entity.Spec.ProjectName = d.storedName(entity.Spec.ProjectName)
+	entity.Spec.DashboardName = d.storedName(entity.Spec.DashboardName)
+	entity.Metadata.Name = v1.UserFavoriteName(entity.Spec.UserName, entity.Spec.ProjectName, entity.Spec.DashboardName)
+	return d.client.Create(entity)
+}
+
+func (d *dao) Delete(user string, project string, dashboard string) error {
+	metadata := v1.NewMetadata(v1.UserFavoriteName(d.storedName(user), d.storedName(project), d.storedName(dashboard)))
+	return d.client.Delete(d.kind, metadata)
+}
+
+func (d *dao) List(user string) ([]*v1.UserFavorite, error) {
+	name := d.storedName(user)
+	var records []*v1.UserFavorite
+	if err := d.client.Query(&userfavorite.Query{NamePrefix: v1.UserFavoriteNamePrefix(name)}, &records); err != nil {
+		return nil, err
+	}
+	// The prefix is a hash of the name of the user, so it doesn't strictly guarantee the records are the ones of this user.
+	result := make([]*v1.UserFavorite, 0, len(records))
+	for _, record := range records {
+		if record.Spec.UserName == name {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (d *dao) DeleteUser(user string) error {
+	records, err := d.List(user)
+	if err != nil {
+		return err
+	}
+	return d.deleteRecords(records)
+}
+
+func (d *dao) DeleteDashboard(project string, dashboard string) error {
+	projectName := d.storedName(project)
+	dashboardName := d.storedName(dashboard)
+	// The names of the records are derived from the user first, so the favorites of a dashboard can't be found by prefix.
+	var records []*v1.UserFavorite
+	if err := d.client.Query(&userfavorite.Query{}, &records); err != nil {
+		return err
+	}
+	var toDelete []*v1.UserFavorite
+	for _, record := range records {
+		if record.Spec.ProjectName == projectName && (len(dashboard) == 0 || record.Spec.DashboardName == dashboardName) {
+			toDelete = append(toDelete, record)
+		}
+	}
+	return d.deleteRecords(toDelete)
+}
+
+func (d *dao) deleteRecords(records []*v1.UserFavorite) error {
+	for _, record := range records {
+		if err := d.client.Delete(d.kind, &record.Metadata); err != nil && !databaseModel.IsKeyNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// storedName returns the name as it is stored, which is lowercase when the database is not case-sensitive.
+func (d *dao) storedName(name string) string {
+	if d.client.IsCaseSensitive() {
+		return name
+	}
+	return strings.ToLower(name)
+}
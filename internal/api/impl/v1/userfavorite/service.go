@@ -0,0 +1,104 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfavorite
+
+import (
+	"fmt"
+	"sort"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/interface/v1/user"
+	"github.com/perses/perses/internal/api/interface/v1/userfavorite"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/sirupsen/logrus"
+)
+
+type service struct {
+	userfavorite.Service
+	dao          userfavorite.DAO
+	userDAO      user.DAO
+	dashboardDAO dashboard.DAO
+}
+
+func NewService(dao userfavorite.DAO, userDAO user.DAO, dashboardDAO dashboard.DAO) userfavorite.Service {
+	return &service{
+		dao:          dao,
+		userDAO:      userDAO,
+		dashboardDAO: dashboardDAO,
+	}
+}
+
+func (s *service) Add(user string, project string, dashboard string) error {
+	if _, err := s.userDAO.Get(user); err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return apiInterface.HandleNotFoundError(fmt.Sprintf("user %q doesn't exist", user))
+		}
+		return err
+	}
+	if _, err := s.dashboardDAO.Get(project, dashboard); err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return apiInterface.HandleNotFoundError(fmt.Sprintf("dashboard %q doesn't exist in the project %q", dashboard, project))
+		}
+		return err
+	}
+	entity := v1.NewUserFavorite(user, project, dashboard)
+	entity.Metadata.CreateNow()
+	if err := s.dao.Create(entity); err != nil && !databaseModel.IsKeyConflict(err) {
+		logrus.WithError(err).Errorf("unable to add the dashboard %q to the favorites of the user %q", dashboard, user)
+		return err
+	}
+	// Adding a dashboard that is already a favorite changes nothing.
+	return nil
+}
+
+func (s *service) Remove(user string, project string, dashboard string) error {
+	if err := s.dao.Delete(user, project, dashboard); err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return apiInterface.HandleNotFoundError(fmt.Sprintf("dashboard %q of the project %q is not a favorite of the user %q", dashboard, project, user))
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) List(user string) ([]*v1.PartialProjectEntity, error) {
+	favorites, err := s.dao.List(user)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.PartialProjectEntity, 0, len(favorites))
+	for _, favorite := range favorites {
+		entity, getErr := s.dashboardDAO.Get(favorite.Spec.ProjectName, favorite.Spec.DashboardName)
+		if getErr != nil {
+			if databaseModel.IsKeyNotFound(getErr) {
+				// The favorites are removed with the dashboard, so it can only be a dashboard that is being deleted.
+				continue
+			}
+			return nil, getErr
+		}
+		result = append(result, &v1.PartialProjectEntity{
+			Kind:     v1.KindDashboard,
+			Metadata: entity.Metadata.ProjectMetadata,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Metadata.Project != result[j].Metadata.Project {
+			return result[i].Metadata.Project < result[j].Metadata.Project
+		}
+		return result[i].Metadata.Name < result[j].Metadata.Name
+	})
+	return result, nil
+}
@@ -0,0 +1,99 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfavorite
+
+import (
+	"errors"
+	"testing"
+
+	databaseFile "github.com/perses/perses/internal/api/database/file"
+	dashboardImpl "github.com/perses/perses/internal/api/impl/v1/dashboard"
+	userImpl "github.com/perses/perses/internal/api/impl/v1/user"
+	apiInterface "github.com/perses/perses/internal/api/interface"
+	"github.com/perses/perses/pkg/model/api/config"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	dashboardSpec "github.com/perses/spec/go/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(t *testing.T) *service {
+	persesDAO := &databaseFile.DAO{Folder: t.TempDir(), Extension: config.JSONExtension, CaseSensitive: true}
+	s := &service{
+		dao:          NewDAO(persesDAO),
+		userDAO:      userImpl.NewDAO(persesDAO),
+		dashboardDAO: dashboardImpl.NewDAO(persesDAO),
+	}
+	usr := &v1.User{Kind: v1.KindUser, Metadata: *v1.NewMetadata("alice")}
+	usr.Metadata.CreateNow()
+	assert.NoError(t, s.userDAO.Create(usr))
+	for _, name := range []string{"node", "cpu"} {
+		entity := &v1.Dashboard{
+			Kind:     v1.KindDashboard,
+			Metadata: *v1.NewDashboardMetadata("perses", name),
+			Spec:     dashboardSpec.Spec{Duration: "1h"},
+		}
+		entity.Metadata.CreateNow()
+		assert.NoError(t, s.dashboardDAO.Create(entity))
+	}
+	return s
+}
+
+func TestFavorites(t *testing.T) {
+	s := newTestService(t)
+
+	assert.NoError(t, s.Add("alice", "perses", "node"))
+	assert.NoError(t, s.Add("alice", "perses", "cpu"))
+	// Adding a favorite twice changes nothing.
+	assert.NoError(t, s.Add("alice", "perses", "node"))
+
+	list, err := s.List("alice")
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, v1.KindDashboard, list[0].Kind)
+		assert.Equal(t, "cpu", list[0].Metadata.Name)
+		assert.Equal(t, "perses", list[0].Metadata.Project)
+		assert.False(t, list[0].Metadata.CreatedAt.IsZero())
+		assert.Equal(t, "node", list[1].Metadata.Name)
+	}
+
+	assert.NoError(t, s.Remove("alice", "perses", "node"))
+	list, err = s.List("alice")
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "cpu", list[0].Metadata.Name)
+	}
+}
+
+func TestFavoritesNotFound(t *testing.T) {
+	s := newTestService(t)
+
+	err := s.Add("alice", "perses", "unknown")
+	assert.True(t, errors.Is(err, apiInterface.NotFoundError))
+	err = s.Add("bob", "perses", "node")
+	assert.True(t, errors.Is(err, apiInterface.NotFoundError))
+	err = s.Remove("alice", "perses", "node")
+	assert.True(t, errors.Is(err, apiInterface.NotFoundError))
+
+	// Once the dashboard is deleted, it can't be added to the favorites anymore.
+	assert.NoError(t, s.Add("alice", "perses", "node"))
+	assert.NoError(t, s.dashboardDAO.Delete("perses", "node"))
+	assert.NoError(t, s.dao.DeleteDashboard("perses", "node"))
+	err = s.Add("alice", "perses", "node")
+	assert.True(t, errors.Is(err, apiInterface.NotFoundError))
+	err = s.Remove("alice", "perses", "node")
+	assert.True(t, errors.Is(err, apiInterface.NotFoundError))
+	list, err := s.List("alice")
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
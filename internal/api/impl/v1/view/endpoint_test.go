@@ -20,6 +20,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/perses/perses/internal/api/authorization"
@@ -30,6 +31,7 @@ import (
 	"github.com/labstack/echo/v4"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/internal/api/interface/v1/dashboard"
+	"github.com/perses/perses/internal/api/jsonpatch"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/role"
 	promclient "github.com/prometheus/client_model/go"
@@ -140,6 +142,30 @@ func (*mockDashboardService) RawMetadataList(_ *dashboard.Query, _ apiInterface.
 	panic("unimplemented")
 }
 
+func (*mockDashboardService) DiffUpdate(_ echo.Context, _ *v1.Dashboard, _ apiInterface.Parameters) ([]jsonpatch.Operation, error) {
+	panic("unimplemented")
+}
+
+func (*mockDashboardService) Clone(_ echo.Context, _ apiInterface.Parameters, _ string) (*v1.Dashboard, error) {
+	panic("unimplemented")
+}
+
+func (*mockDashboardService) ListTrash(_ string) ([]*v1.Dashboard, error) {
+	panic("unimplemented")
+}
+
+func (*mockDashboardService) Restore(_ apiInterface.Parameters) (*v1.Dashboard, error) {
+	panic("unimplemented")
+}
+
+func (*mockDashboardService) PurgeTrash(_ time.Time) error {
+	panic("unimplemented")
+}
+
+func (*mockDashboardService) ListTags(_ string) ([]string, error) {
+	panic("unimplemented")
+}
+
 func TestEndpoint(t *testing.T) {
 	endpoint := NewEndpoint(NewMetricsViewService(), &testRBAC{true}, &mockDashboardService{&v1.Dashboard{}}).(*endpoint)
 
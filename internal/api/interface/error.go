@@ -36,10 +36,13 @@ var (
 	InternalError        = &PersesError{message: "internal server error"}
 	NotFoundError        = &PersesError{message: "document not found"}
 	ConflictError        = &PersesError{message: "document already exists"}
+	VersionConflictError = &PersesError{message: "document has been modified in the meantime"}
 	BadRequestError      = &PersesError{message: "bad request"}
 	UnauthorizedError    = &PersesError{message: "unauthorized"}
 	ForbiddenError       = &PersesError{message: "forbidden access"}
 	UnsupportedMediaType = &PersesError{message: "unsupported media type"}
+	UnprocessableEntity  = &PersesError{message: "unprocessable entity"}
+	PreconditionRequired = &PersesError{message: "precondition required"}
 )
 
 const (
@@ -68,6 +71,9 @@ func HandleError(err error) error {
 	if errors.Is(err, ConflictError) {
 		return echo.NewHTTPError(http.StatusConflict, err.Error())
 	}
+	if errors.Is(err, VersionConflictError) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if errors.Is(err, NotFoundError) {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
@@ -83,6 +89,12 @@ func HandleError(err error) error {
 	if errors.Is(err, UnsupportedMediaType) {
 		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
 	}
+	if errors.Is(err, UnprocessableEntity) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	if errors.Is(err, PreconditionRequired) {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, err.Error())
+	}
 
 	var HTTPError *echo.HTTPError
 	if errors.As(err, &HTTPError) {
@@ -110,6 +122,18 @@ func HandleForbiddenError(msg string) error {
 	return handleErrorMsg(msg, ForbiddenError)
 }
 
+func HandleVersionConflictError(msg string) error {
+	return handleErrorMsg(msg, VersionConflictError)
+}
+
+func HandleUnprocessableEntityError(msg string) error {
+	return handleErrorMsg(msg, UnprocessableEntity)
+}
+
+func HandlePreconditionRequiredError(msg string) error {
+	return handleErrorMsg(msg, PreconditionRequired)
+}
+
 func ProjectDoesNotExistErrorMessage(projectName string) string {
 	return projectDoesNotExistPrefix + projectName + projectDoesNotExistSuffix
 }
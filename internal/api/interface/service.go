@@ -18,6 +18,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/jsonpatch"
 	"github.com/perses/perses/pkg/model/api"
 )
 
@@ -36,3 +37,10 @@ type Service[T api.Entity, K api.Entity, V databaseModel.Query] interface {
 	MetadataList(query V, parameters Parameters) ([]api.Entity, error)
 	RawMetadataList(query V, parameters Parameters) ([]json.RawMessage, error)
 }
+
+// DiffService is implemented by the services able to tell what an update would change, without persisting it.
+// The toolbox relies on it to serve the updates in dry-run mode.
+type DiffService[T api.Entity] interface {
+	// DiffUpdate validates the entity like Update does, then returns the JSON Patch to apply to the current entity to get the new one.
+	DiffUpdate(ctx echo.Context, entity T, parameters Parameters) ([]jsonpatch.Operation, error)
+}
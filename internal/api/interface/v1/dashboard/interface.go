@@ -15,7 +15,9 @@ package dashboard
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	databaseModel "github.com/perses/perses/internal/api/database/model"
 	apiInterface "github.com/perses/perses/internal/api/interface"
 	"github.com/perses/perses/pkg/model/api"
@@ -31,6 +33,13 @@ type Query struct {
 	// The value can come from the path of the URL or from the query parameter
 	Project      string `param:"project" query:"project"`
 	MetadataOnly bool   `query:"metadata_only"`
+	// Tags is a list of tags, comma separated or repeated, used to filter the Dashboard list.
+	Tags []string `query:"tags"`
+	// TagOperator defines whether the dashboards must have all the Tags ("and", the default) or at least one of them ("or").
+	TagOperator string `query:"tag_op"`
+	// User is the name of the user listing the dashboards. The dashboards marked as favorite by the user are flagged
+	// in the list. It is set by the endpoint, not by the request.
+	User string `json:"-"`
 }
 
 func (q *Query) GetMetadataOnlyQueryParam() bool {
@@ -48,10 +57,18 @@ func (q *Query) IsRawMetadataQueryAllowed() bool {
 type DAO interface {
 	Create(entity *v1.Dashboard) error
 	Update(entity *v1.Dashboard) error
+	// UpdateIfVersion updates the dashboard only if the stored one still has the given version of metadata.
+	UpdateIfVersion(entity *v1.Dashboard, version uint64) error
+	// Delete removes the dashboard permanently, whether it is in the trash or not.
 	Delete(project string, name string) error
 	DeleteAll(project string) error
+	// Get returns the dashboard, unless it is in the trash. Like the lists, it ignores the dashboards in the trash.
 	Get(project string, name string) (*v1.Dashboard, error)
+	// GetDeleted returns the dashboard only if it is in the trash.
+	GetDeleted(project string, name string) (*v1.Dashboard, error)
 	List(q *Query) ([]*v1.Dashboard, error)
+	// ListDeleted returns the dashboards in the trash.
+	ListDeleted(q *Query) ([]*v1.Dashboard, error)
 	RawList(q *Query) ([]json.RawMessage, error)
 	MetadataList(q *Query) ([]api.Entity, error)
 	RawMetadataList(q *Query) ([]json.RawMessage, error)
@@ -59,5 +76,16 @@ type DAO interface {
 
 type Service interface {
 	apiInterface.Service[*v1.Dashboard, *v1.Dashboard, *Query]
+	apiInterface.DiffService[*v1.Dashboard]
 	Validate(entity *v1.Dashboard) error
+	// Clone creates a copy of the dashboard described by the parameters, named after the given name.
+	Clone(ctx echo.Context, parameters apiInterface.Parameters, name string) (*v1.Dashboard, error)
+	// ListTrash returns the dashboards of the project that have been deleted but can still be restored.
+	ListTrash(project string) ([]*v1.Dashboard, error)
+	// Restore moves the dashboard described by the parameters out of the trash.
+	Restore(parameters apiInterface.Parameters) (*v1.Dashboard, error)
+	// PurgeTrash permanently deletes the dashboards moved to the trash before the given time.
+	PurgeTrash(deletedBefore time.Time) error
+	// ListTags returns the sorted list of the distinct tags of the dashboards of the project.
+	ListTags(project string) ([]string, error)
 }
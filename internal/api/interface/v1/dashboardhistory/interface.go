@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboardhistory
+
+import (
+	"github.com/labstack/echo/v4"
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type Query struct {
+	databaseModel.Query
+	// Project is the exact name of the project of the dashboard.
+	Project string
+	// NamePrefix is the prefix of the records to return, see v1.DashboardHistoryNamePrefix.
+	NamePrefix string
+}
+
+func (q *Query) GetMetadataOnlyQueryParam() bool {
+	return false
+}
+
+func (q *Query) IsRawQueryAllowed() bool {
+	return false
+}
+
+func (q *Query) IsRawMetadataQueryAllowed() bool {
+	return false
+}
+
+// ListQuery is the query of the endpoint listing the history of a dashboard.
+type ListQuery struct {
+	Project   string
+	Dashboard string
+	// Limit is the maximum number of entries to return.
+	Limit int
+	// Before excludes the versions greater than or equal to it. It is ignored when nil.
+	Before *uint64
+}
+
+// List is a page of the history of a dashboard, from the most recent version to the oldest.
+type List struct {
+	Items []*v1.DashboardHistoryEntry `json:"items"`
+	// Next is the cursor to pass with the parameter "before" to get the next page. It is empty on the last page.
+	Next string `json:"next,omitempty"`
+}
+
+// DAO is the append-only storage of the history of the dashboards.
+type DAO interface {
+	// AppendHistory records a new version. Recording the same version twice is a conflict.
+	AppendHistory(entity *v1.DashboardHistory) error
+	// ListHistory returns every recorded version of the dashboard, in no particular order.
+	ListHistory(project string, dashboard string) ([]*v1.DashboardHistory, error)
+	GetHistory(project string, dashboard string, version uint64) (*v1.DashboardHistory, error)
+	// DeleteHistoryVersion removes a single version from the history of the dashboard.
+	DeleteHistoryVersion(project string, dashboard string, version uint64) error
+	// DeleteHistory removes the history of the dashboard, or of every dashboard of the project when dashboard is empty.
+	DeleteHistory(project string, dashboard string) error
+}
+
+type Service interface {
+	// Record appends the current version of the dashboard to its history. The user of the request is the actor.
+	// The oldest versions beyond the configured maximum are then removed from the history.
+	Record(ctx echo.Context, entity *v1.Dashboard) error
+	List(q *ListQuery) (*List, error)
+	Get(project string, dashboard string, version uint64) (*v1.DashboardHistoryEntry, error)
+	Delete(project string, dashboard string) error
+}
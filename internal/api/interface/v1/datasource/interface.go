@@ -31,6 +31,8 @@ type Query struct {
 	Kind string `query:"kind"`
 	// Default will filter the list of datasource and return only the default datasource, whatever the kind of the datasource is.
 	Default *bool `query:"default"`
+	// Labels is a list of label selectors like env=prod or env!=dev. Only the datasources matching all of them are returned.
+	Labels []string `query:"label"`
 }
 
 func (q *Query) GetMetadataOnlyQueryParam() bool {
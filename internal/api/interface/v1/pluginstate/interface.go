@@ -0,0 +1,47 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginstate
+
+import (
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type Query struct {
+	databaseModel.Query
+	// NamePrefix is a prefix of the keys that is used to filter the list of the PluginStates.
+	// NamePrefix can be empty in case you want to return all the entries of the plugin module.
+	NamePrefix string
+	// Module is the exact name of the plugin module owning the entries.
+	Module string
+}
+
+func (q *Query) GetMetadataOnlyQueryParam() bool {
+	return false
+}
+
+func (q *Query) IsRawQueryAllowed() bool {
+	return false
+}
+
+func (q *Query) IsRawMetadataQueryAllowed() bool {
+	return false
+}
+
+type DAO interface {
+	Upsert(entity *v1.PluginState) error
+	Delete(module string, key string) error
+	Get(module string, key string) (*v1.PluginState, error)
+	List(q *Query) ([]*v1.PluginState, error)
+}
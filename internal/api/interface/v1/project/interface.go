@@ -55,4 +55,6 @@ type DAO interface {
 
 type Service interface {
 	apiInterface.Service[*v1.Project, *v1.Project, *Query]
+	// Stats returns a summary of the resources contained in the given project.
+	Stats(name string) (*v1.ProjectStats, error)
 }
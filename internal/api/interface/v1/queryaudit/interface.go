@@ -0,0 +1,70 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queryaudit
+
+import (
+	"time"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type Query struct {
+	databaseModel.Query
+	// Datasource is the exact name of the datasource the queries have been sent to.
+	// Datasource can be empty in case you want to return the queries of every datasource.
+	Datasource string
+}
+
+func (q *Query) GetMetadataOnlyQueryParam() bool {
+	return false
+}
+
+func (q *Query) IsRawQueryAllowed() bool {
+	return false
+}
+
+func (q *Query) IsRawMetadataQueryAllowed() bool {
+	return false
+}
+
+// ListQuery is the query of the admin endpoint listing the recorded queries.
+type ListQuery struct {
+	Query
+	// Since excludes the queries received before this time. It is ignored when zero.
+	Since time.Time
+	// Page is the number, starting from 1, of the page to return.
+	Page int
+	// Size is the number of records per page.
+	Size int
+}
+
+// List is a page of recorded queries, from the most recent to the oldest.
+type List struct {
+	Items []*v1.QueryAudit `json:"items"`
+	// Total is the number of records matching the query, across every page.
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Size  int `json:"size"`
+}
+
+type DAO interface {
+	Create(entity *v1.QueryAudit) error
+	List(q *Query) ([]*v1.QueryAudit, error)
+}
+
+type Service interface {
+	Record(spec v1.QueryAuditSpec) error
+	List(q *ListQuery) (*List, error)
+}
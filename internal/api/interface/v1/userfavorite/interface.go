@@ -0,0 +1,60 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfavorite
+
+import (
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type Query struct {
+	databaseModel.Query
+	// NamePrefix is the prefix of the records to return, see v1.UserFavoriteNamePrefix.
+	NamePrefix string
+}
+
+func (q *Query) GetMetadataOnlyQueryParam() bool {
+	return false
+}
+
+func (q *Query) IsRawQueryAllowed() bool {
+	return false
+}
+
+func (q *Query) IsRawMetadataQueryAllowed() bool {
+	return false
+}
+
+// DAO is the storage of the favorites. It is independent of the storage of the dashboards, so the favorites of a
+// dashboard must be removed with DeleteDashboard when the dashboard is deleted.
+type DAO interface {
+	// Create records a new favorite. Recording the same favorite twice is a conflict.
+	Create(entity *v1.UserFavorite) error
+	Delete(user string, project string, dashboard string) error
+	// List returns the favorites of the user, in no particular order.
+	List(user string) ([]*v1.UserFavorite, error)
+	// DeleteUser removes the favorites of the user.
+	DeleteUser(user string) error
+	// DeleteDashboard removes the dashboard from the favorites of every user, or every dashboard of the project when
+	// dashboard is empty.
+	DeleteDashboard(project string, dashboard string) error
+}
+
+type Service interface {
+	// Add marks the dashboard as favorite for the user. The user and the dashboard must exist.
+	Add(user string, project string, dashboard string) error
+	Remove(user string, project string, dashboard string) error
+	// List returns the metadata of the dashboards marked as favorite by the user.
+	List(user string) ([]*v1.PartialProjectEntity, error)
+}
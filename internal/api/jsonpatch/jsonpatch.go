@@ -0,0 +1,147 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpatch computes the difference between two JSON documents as a JSON Patch (RFC 6902).
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Operation is a single operation of a JSON Patch.
+type Operation struct {
+	Op    string `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// MarshalJSON keeps the value of an add or a replace operation even when it is null,
+// as the RFC requires it for these operations.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{Op: o.Op, Path: o.Path, Value: o.Value})
+}
+
+// Diff returns the operations to apply to the JSON form of from to get the JSON form of to.
+// The operations are sorted in a deterministic order. It is empty when both documents are identical.
+func Diff(from, to any) ([]Operation, error) {
+	fromDoc, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+	return diff("", fromDoc, toDoc), nil
+}
+
+// normalize converts a value to its generic JSON representation (map[string]any, []any, string, float64, bool, nil).
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	if unmarshalErr := json.Unmarshal(data, &result); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return result, nil
+}
+
+func diff(path string, from, to any) []Operation {
+	switch fromValue := from.(type) {
+	case map[string]any:
+		if toValue, ok := to.(map[string]any); ok {
+			return diffObject(path, fromValue, toValue)
+		}
+	case []any:
+		if toValue, ok := to.([]any); ok {
+			return diffArray(path, fromValue, toValue)
+		}
+	}
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	return []Operation{{Op: OpReplace, Path: path, Value: to}}
+}
+
+func diffObject(path string, from, to map[string]any) []Operation {
+	var operations []Operation
+	keys := make([]string, 0, len(from))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		toValue, ok := to[key]
+		if !ok {
+			operations = append(operations, Operation{Op: OpRemove, Path: path + "/" + escape(key)})
+			continue
+		}
+		operations = append(operations, diff(path+"/"+escape(key), from[key], toValue)...)
+	}
+
+	keys = keys[:0]
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		operations = append(operations, Operation{Op: OpAdd, Path: path + "/" + escape(key), Value: to[key]})
+	}
+	return operations
+}
+
+// diffArray compares the items at the same index. The extra items are removed starting from the end of the array,
+// so the indexes of the operations remain valid when they are applied in order.
+func diffArray(path string, from, to []any) []Operation {
+	var operations []Operation
+	common := min(len(from), len(to))
+	for i := 0; i < common; i++ {
+		operations = append(operations, diff(path+"/"+strconv.Itoa(i), from[i], to[i])...)
+	}
+	for i := len(from) - 1; i >= common; i-- {
+		operations = append(operations, Operation{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+	}
+	for i := common; i < len(to); i++ {
+		operations = append(operations, Operation{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: to[i]})
+	}
+	return operations
+}
+
+// escape encodes a key as a JSON Pointer reference token (RFC 6901).
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
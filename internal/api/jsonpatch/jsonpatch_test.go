@@ -0,0 +1,106 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		from   string
+		to     string
+		result []Operation
+	}{
+		{
+			title:  "identical documents",
+			from:   `{"a":1,"b":[1,2],"c":{"d":"e"}}`,
+			to:     `{"c":{"d":"e"},"b":[1,2],"a":1}`,
+			result: nil,
+		},
+		{
+			title: "object fields added, removed and replaced",
+			from:  `{"a":1,"b":"x","c":{"d":true}}`,
+			to:    `{"b":"y","c":{"d":false,"e":null},"f":[]}`,
+			result: []Operation{
+				{Op: OpRemove, Path: "/a"},
+				{Op: OpReplace, Path: "/b", Value: "y"},
+				{Op: OpReplace, Path: "/c/d", Value: false},
+				{Op: OpAdd, Path: "/c/e", Value: nil},
+				{Op: OpAdd, Path: "/f", Value: []any{}},
+			},
+		},
+		{
+			title: "array shrinking",
+			from:  `{"a":[1,2,3,4]}`,
+			to:    `{"a":[1,5]}`,
+			result: []Operation{
+				{Op: OpReplace, Path: "/a/1", Value: float64(5)},
+				{Op: OpRemove, Path: "/a/3"},
+				{Op: OpRemove, Path: "/a/2"},
+			},
+		},
+		{
+			title: "array growing",
+			from:  `{"a":[1]}`,
+			to:    `{"a":[1,2,3]}`,
+			result: []Operation{
+				{Op: OpAdd, Path: "/a/1", Value: float64(2)},
+				{Op: OpAdd, Path: "/a/2", Value: float64(3)},
+			},
+		},
+		{
+			title: "type changed",
+			from:  `{"a":{"b":1}}`,
+			to:    `{"a":[1]}`,
+			result: []Operation{
+				{Op: OpReplace, Path: "/a", Value: []any{float64(1)}},
+			},
+		},
+		{
+			title: "keys escaped",
+			from:  `{"a/b":1,"c~d":1}`,
+			to:    `{"a/b":2,"c~d":2}`,
+			result: []Operation{
+				{Op: OpReplace, Path: "/a~1b", Value: float64(2)},
+				{Op: OpReplace, Path: "/c~0d", Value: float64(2)},
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			var from, to any
+			require.NoError(t, json.Unmarshal([]byte(test.from), &from))
+			require.NoError(t, json.Unmarshal([]byte(test.to), &to))
+			result, err := Diff(from, to)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestOperationMarshalJSON(t *testing.T) {
+	data, err := json.Marshal([]Operation{
+		{Op: OpAdd, Path: "/a", Value: nil},
+		{Op: OpRemove, Path: "/b"},
+		{Op: OpReplace, Path: "/c", Value: "d"},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},{"op":"replace","path":"/c","value":"d"}]`, string(data))
+}
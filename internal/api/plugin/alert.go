@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/internal/httpclient"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const extractionFailureAlertName = "PluginExtractionFailure"
+
+// A counter for the total number of plugin archives that couldn't be extracted.
+var extractionFailureCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: utils.MetricNamespace,
+	Name:      "plugin_extraction_failures_total",
+	Help:      "The total number of plugin archives that couldn't be extracted",
+})
+
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(extractionFailureCounter)
+}
+
+// extractionFailureAlert is the payload sent to the webhook.
+// The field Text (and Channel when set) makes it directly usable with a Slack incoming webhook.
+type extractionFailureAlert struct {
+	Alert     string    `json:"alert"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+	Channel   string    `json:"channel,omitempty"`
+}
+
+var alertClient = httpclient.NewHTTPClient(httpclient.WithTimeout(10 * time.Second))
+
+// NotifyExtractionFailure sends an alert to the webhook configured to report the error that occurred while extracting
+// the plugin archives. Nothing is sent when no alert is configured.
+func NotifyExtractionFailure(conf *config.WebhookAlert, extractionErr error) error {
+	if conf == nil || extractionErr == nil {
+		return nil
+	}
+	payload, err := json.Marshal(extractionFailureAlert{
+		Alert:     extractionFailureAlertName,
+		Error:     extractionErr.Error(),
+		Timestamp: time.Now().UTC(),
+		Text:      fmt.Sprintf("Perses failed to extract the plugin archives: %s", extractionErr),
+		Channel:   conf.SlackChannel,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := alertClient.Post(conf.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("unable to send the alert %s: %w", extractionFailureAlertName, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unable to send the alert %s: the webhook answered with the status %d", extractionFailureAlertName, resp.StatusCode)
+	}
+	return nil
+}
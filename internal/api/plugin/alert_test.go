@@ -0,0 +1,86 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyExtractionFailure(t *testing.T) {
+	var received extractionFailureAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := &config.WebhookAlert{WebhookURL: server.URL, SlackChannel: "#perses-alerts"}
+	require.NoError(t, NotifyExtractionFailure(conf, errors.New("unable to unzip plugin archive \"foo.tar.gz\"")))
+	assert.Equal(t, extractionFailureAlertName, received.Alert)
+	assert.Equal(t, "unable to unzip plugin archive \"foo.tar.gz\"", received.Error)
+	assert.Equal(t, "#perses-alerts", received.Channel)
+	assert.Contains(t, received.Text, "foo.tar.gz")
+	assert.False(t, received.Timestamp.IsZero())
+}
+
+func TestNotifyExtractionFailure_WebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	assert.Error(t, NotifyExtractionFailure(&config.WebhookAlert{WebhookURL: server.URL}, errors.New("failure")))
+}
+
+func TestNotifyExtractionFailure_NotConfigured(t *testing.T) {
+	assert.NoError(t, NotifyExtractionFailure(nil, errors.New("failure")))
+}
+
+func TestUnzipAll_CountsFailures(t *testing.T) {
+	archiveFolder := t.TempDir()
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := []byte("{}")
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "foo/package.json", Mode: 0o600, Size: int64(len(content))}))
+	_, err := tarWriter.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(archiveFolder, "foo.tar.gz"), buffer.Bytes(), 0o600))
+
+	// The target folder being a file, the content of the archive cannot be written.
+	targetFolder := filepath.Join(t.TempDir(), "plugins")
+	require.NoError(t, os.WriteFile(targetFolder, nil, 0o600))
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder}
+
+	before := testutil.ToFloat64(extractionFailureCounter)
+	assert.Error(t, a.unzipAll())
+	assert.Equal(t, before+1, testutil.ToFloat64(extractionFailureCounter))
+}
@@ -15,23 +15,60 @@ package plugin
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/mholt/archives"
 	"github.com/perses/perses/internal/api/archive"
+	"github.com/perses/perses/pkg/model/api/config"
 	"github.com/sirupsen/logrus"
 )
 
+var errArchiveTooLarge = errors.New("the archive is too large once extracted")
+
 type arch struct {
 	folders      []string
 	targetFolder string
+	// extractionTimeout is the maximum duration of the extraction of an archive. Zero means no limit.
+	extractionTimeout time.Duration
+	// maxArchiveSize is the maximum number of bytes extracted from an archive. Zero means no limit.
+	maxArchiveSize int64
+	// downloadTimeout is the maximum duration of the download of an archive referenced by a URL. Zero means no limit.
+	downloadTimeout time.Duration
+	// httpClient downloads the archives referenced by a URL. http.DefaultClient is used when nil.
+	httpClient *http.Client
+	// signingKeyPath is the path to the public key verifying the signature of the archives. Empty means no verification.
+	signingKeyPath string
+	// signingKey is the key loaded from signingKeyPath during the current extraction.
+	signingKey *ecdsa.PublicKey
+	// extracted maps the name of the plugin folders written during the current extraction to the archive they come from.
+	extracted map[string]string
 }
 
 func (a *arch) unzipAll() error {
+	a.extracted = make(map[string]string)
+	a.signingKey = nil
+	if len(a.signingKeyPath) > 0 {
+		key, err := loadSigningKey(a.signingKeyPath)
+		if err != nil {
+			return fmt.Errorf("unable to load the key verifying the signature of the plugin archives: %w", err)
+		}
+		a.signingKey = key
+	}
 	for _, folder := range a.folders {
+		if config.IsRemoteArchivePath(folder) {
+			if unzipErr := a.unzipRemote(folder); unzipErr != nil {
+				extractionFailureCounter.Inc()
+				return unzipErr
+			}
+			continue
+		}
 		files, err := os.ReadDir(folder)
 		if err != nil {
 			return fmt.Errorf("unable to read directory %s: %w", folder, err)
@@ -43,6 +80,7 @@ func (a *arch) unzipAll() error {
 			}
 
 			if unzipErr := a.unzip(folder, file.Name()); unzipErr != nil {
+				extractionFailureCounter.Inc()
 				return fmt.Errorf("unable to unzip plugin archive %q: %w", file.Name(), unzipErr)
 			}
 		}
@@ -57,6 +95,9 @@ func (a *arch) unzip(folder string, archiveFileName string) error {
 	}
 	logrus.Debugf("unzipping archive %s", archiveFileName)
 	archiveName := archive.ExtractArchiveName(archiveFileName)
+	if previous, ok := a.extracted[archiveName]; ok {
+		return fmt.Errorf("the plugin %q is already provided by the archive %q", archiveName, previous)
+	}
 	archiveFile := filepath.Join(folder, archiveFileName)
 	stream, archiveOpenErr := os.Open(archiveFile) //nolint: gosec
 	defer func() {
@@ -67,20 +108,104 @@ func (a *arch) unzip(folder string, archiveFileName string) error {
 	if archiveOpenErr != nil {
 		return fmt.Errorf("unable to open archive file %q", archiveFile)
 	}
-	format, newStream, identifyErr := archives.Identify(context.Background(), archiveFile, stream)
+	if a.signingKey != nil {
+		// a file left by a previous extraction is not covered by the signature of this archive
+		if removeErr := os.RemoveAll(filepath.Join(a.targetFolder, archiveName)); removeErr != nil {
+			return fmt.Errorf("unable to remove the previous extraction of the archive %q: %w", archiveFile, removeErr)
+		}
+	}
+	if extractErr := a.extract(archiveFile, archiveName, stream); extractErr != nil {
+		return extractErr
+	}
+	if a.signingKey != nil {
+		if verifyErr := verifyArchiveSignature(filepath.Join(a.targetFolder, archiveName), a.signingKey); verifyErr != nil {
+			// a tampered plugin must not be loaded
+			if removeErr := os.RemoveAll(filepath.Join(a.targetFolder, archiveName)); removeErr != nil {
+				logrus.WithError(removeErr).Errorf("unable to remove the files extracted from the archive %q", archiveFile)
+			}
+			return fmt.Errorf("invalid signature: %w", verifyErr)
+		}
+	}
+	manifest, manifestErr := readBundleManifest(filepath.Join(a.targetFolder, archiveName))
+	if manifestErr != nil {
+		return fmt.Errorf("unable to read the manifest of the bundle: %w", manifestErr)
+	}
+	if manifest != nil {
+		return a.unpackBundle(archiveName, archiveFileName, manifest)
+	}
+	a.extracted[archiveName] = archiveFileName
+	return nil
+}
+
+// extract writes the content of the archive in the target folder, within the extraction timeout.
+func (a *arch) extract(archiveFile string, archiveName string, stream io.Reader) error {
+	ctx := context.Background()
+	if a.extractionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.extractionTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	// The extraction runs in its own goroutine, as a read blocked on a slow filesystem doesn't return when the context is done.
+	done := make(chan error, 1)
+	go func() {
+		done <- a.identifyAndExtract(ctx, archiveFile, archiveName, &contextReader{ctx: ctx, reader: stream})
+	}()
+	select {
+	case err := <-done:
+		logrus.Debugf("archive %s extracted in %s", archiveFile, time.Since(start))
+		return err
+	case <-ctx.Done():
+		elapsed := time.Since(start).Round(time.Millisecond)
+		// The extraction stops at its next read or write, it must be over before its files are removed,
+		// so the plugin loader never sees a partially extracted plugin.
+		<-done
+		if removeErr := os.RemoveAll(filepath.Join(a.targetFolder, archiveName)); removeErr != nil {
+			logrus.WithError(removeErr).Errorf("unable to remove the files extracted from the archive %q", archiveFile)
+		}
+		return fmt.Errorf("the extraction of the archive %q has been aborted after %s: %w", archiveFile, elapsed, ctx.Err())
+	}
+}
+
+func (a *arch) identifyAndExtract(ctx context.Context, archiveFile string, archiveName string, stream io.Reader) error {
+	format, newStream, identifyErr := archives.Identify(ctx, archiveFile, stream)
 	if identifyErr != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		logrus.WithError(identifyErr).Errorf("unable to identify the type of the archive %q. Skipping it.", archiveFile)
 		return nil
 	}
 	if ex, ok := format.(archives.Extractor); ok {
-		if extractErr := ex.Extract(context.Background(), newStream, a.extractArchiveFileHandler(archiveName)); extractErr != nil {
+		if extractErr := ex.Extract(ctx, newStream, a.extractArchiveFileHandler(archiveName)); extractErr != nil {
+			if errors.Is(extractErr, errArchiveTooLarge) {
+				// nothing partially extracted from a suspicious archive is kept
+				if removeErr := os.RemoveAll(filepath.Join(a.targetFolder, archiveName)); removeErr != nil {
+					logrus.WithError(removeErr).Errorf("unable to remove the files extracted from the archive %q", archiveFile)
+				}
+			}
 			return fmt.Errorf("unable to extract the archive file: %w", extractErr)
 		}
 	}
 	return nil
 }
 
+// contextReader stops reading the archive once the context is done, so an aborted extraction doesn't keep going.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
 func (a *arch) extractArchiveFileHandler(archiveName string) archives.FileHandler {
+	// written is the number of bytes extracted from the archive so far
+	var written int64
 	return func(_ context.Context, f archives.FileInfo) error {
 		if f.IsDir() {
 			return nil
@@ -98,10 +223,19 @@ func (a *arch) extractArchiveFileHandler(archiveName string) archives.FileHandle
 				logrus.WithError(closeErr).Error("unable to close archive file stream")
 			}
 		}()
-		respBytes, err := io.ReadAll(stream)
+		var reader io.Reader = stream
+		if a.maxArchiveSize > 0 {
+			// reading one byte more than allowed is enough to know the limit is exceeded
+			reader = io.LimitReader(stream, a.maxArchiveSize-written+1)
+		}
+		respBytes, err := io.ReadAll(reader)
 		if err != nil {
 			return fmt.Errorf("unable to read the file %q: %w", f.NameInArchive, err)
 		}
+		written += int64(len(respBytes))
+		if a.maxArchiveSize > 0 && written > a.maxArchiveSize {
+			return fmt.Errorf("%w, it exceeds the limit of %d bytes", errArchiveTooLarge, a.maxArchiveSize)
+		}
 		if writeErr := os.WriteFile(filepath.Join(a.targetFolder, archiveName, f.NameInArchive), respBytes, 0644); writeErr != nil { // nolint: gosec
 			return fmt.Errorf("unable to write the file %q: %w", f.NameInArchive, writeErr)
 		}
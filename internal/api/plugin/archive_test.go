@@ -0,0 +1,100 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingReader simulates an archive stored on a filesystem that doesn't answer: a read blocks until unblock is closed.
+type blockingReader struct {
+	unblock chan struct{}
+}
+
+func (r *blockingReader) Read(_ []byte) (int, error) {
+	<-r.unblock
+	return 0, context.Canceled
+}
+
+func TestExtract_Timeout(t *testing.T) {
+	reader := &blockingReader{unblock: make(chan struct{})}
+	// the filesystem answers again long after the timeout
+	time.AfterFunc(200*time.Millisecond, func() { close(reader.unblock) })
+	a := &arch{targetFolder: t.TempDir(), extractionTimeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	err := a.extract("plugins-archive/slow.tar.gz", "slow", reader)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "plugins-archive/slow.tar.gz")
+	assert.Contains(t, err.Error(), "aborted after")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExtract_TimeoutRemovesPartialExtraction(t *testing.T) {
+	// the first file of the archive is extracted, then the archive stops answering
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+	content := `{"name":"slow"}`
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: PackageJSONFile, Mode: 0o600, Size: int64(len(content))}))
+	_, err := tarWriter.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Flush())
+	blocking := &blockingReader{unblock: make(chan struct{})}
+	time.AfterFunc(200*time.Millisecond, func() { close(blocking.unblock) })
+	targetFolder := t.TempDir()
+	a := &arch{targetFolder: targetFolder, extractionTimeout: 100 * time.Millisecond}
+
+	err = a.extract("plugins-archive/slow.tar", "slow", io.MultiReader(bytes.NewReader(buffer.Bytes()), blocking))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoDirExists(t, filepath.Join(targetFolder, "slow"))
+}
+
+func TestUnzipAll_WithinTimeout(t *testing.T) {
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "prometheus.tar.gz", pluginFiles("."))
+
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder, extractionTimeout: time.Minute}
+	require.NoError(t, a.unzipAll())
+	assert.FileExists(t, filepath.Join(targetFolder, "prometheus", PackageJSONFile))
+}
+
+func TestUnzipAll_ArchiveTooLarge(t *testing.T) {
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	files := pluginFiles(".")
+	// highly compressible content, taking far more space once extracted than in the archive
+	files["bomb.js"] = strings.Repeat("0", 64*1024)
+	writeTarGz(t, archiveFolder, "prometheus.tar.gz", files)
+
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder, maxArchiveSize: 32 * 1024}
+	err := a.unzipAll()
+	assert.ErrorIs(t, err, errArchiveTooLarge)
+	assert.NoDirExists(t, filepath.Join(targetFolder, "prometheus"))
+
+	a.maxArchiveSize = 128 * 1024
+	require.NoError(t, a.unzipAll())
+	assert.FileExists(t, filepath.Join(targetFolder, "prometheus", "bomb.js"))
+}
@@ -0,0 +1,93 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BundleManifestFileName is the manifest at the root of a plugin bundle.
+// A bundle is an archive containing several plugins, each of them in a sub-folder named after the plugin.
+// It allows distributing all the plugins as a single file, on an air-gapped system for example.
+const BundleManifestFileName = "bundle.json"
+
+// BundleManifest lists the plugins contained in a bundle.
+type BundleManifest struct {
+	// Plugins are the names of the sub-folders of the bundle containing a plugin.
+	Plugins []string `json:"plugins"`
+}
+
+// readBundleManifest returns the manifest present in the folder, or nil when the folder is not a bundle.
+func readBundleManifest(folder string) (*BundleManifest, error) {
+	data, err := os.ReadFile(filepath.Join(folder, BundleManifestFileName)) //nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	manifest := &BundleManifest{}
+	if unmarshalErr := json.Unmarshal(data, manifest); unmarshalErr != nil {
+		return nil, fmt.Errorf("invalid %s: %w", BundleManifestFileName, unmarshalErr)
+	}
+	return manifest, nil
+}
+
+func isValidBundlePluginName(name string) bool {
+	return len(name) > 0 && name != "." && name != ".." && filepath.Base(name) == name
+}
+
+// unpackBundle moves every plugin of the bundle extracted in the folder bundleName to the plugin folder,
+// then removes what remains of the bundle.
+// The plugins that are missing or invalid are skipped, so they don't prevent the other plugins of the bundle from being used.
+// A plugin already provided by another archive is a conflict that stops the extraction.
+func (a *arch) unpackBundle(bundleName string, archiveFileName string, manifest *BundleManifest) error {
+	bundleFolder := filepath.Join(a.targetFolder, bundleName)
+	defer func() {
+		if removeErr := os.RemoveAll(bundleFolder); removeErr != nil {
+			logrus.WithError(removeErr).Errorf("unable to remove the folder of the bundle %q", archiveFileName)
+		}
+	}()
+	for _, name := range manifest.Plugins {
+		if !isValidBundlePluginName(name) || name == bundleName {
+			logrus.Errorf("plugin %q of the bundle %q has an invalid name and is skipped", name, archiveFileName)
+			continue
+		}
+		if previous, ok := a.extracted[name]; ok {
+			return fmt.Errorf("the plugin %q of the bundle %q is already provided by the archive %q", name, archiveFileName, previous)
+		}
+		pluginFolder := filepath.Join(bundleFolder, name)
+		if validErr := IsRequiredFileExists(pluginFolder, pluginFolder, pluginFolder); validErr != nil {
+			logrus.WithError(validErr).Errorf("plugin %q of the bundle %q is not valid and is skipped", name, archiveFileName)
+			continue
+		}
+		targetFolder := filepath.Join(a.targetFolder, name)
+		// remove the version extracted at a previous start, if any
+		if removeErr := os.RemoveAll(targetFolder); removeErr != nil {
+			return fmt.Errorf("unable to remove the previous version of the plugin %q: %w", name, removeErr)
+		}
+		if renameErr := os.Rename(pluginFolder, targetFolder); renameErr != nil {
+			return fmt.Errorf("unable to move the plugin %q out of the bundle %q: %w", name, archiveFileName, renameErr)
+		}
+		a.extracted[name] = archiveFileName
+		logrus.Debugf("plugin %q extracted from the bundle %q", name, archiveFileName)
+	}
+	return nil
+}
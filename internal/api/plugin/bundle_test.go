@@ -0,0 +1,145 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTarGz writes an archive containing the given files (path in the archive -> content) in the folder.
+func writeTarGz(t *testing.T, folder string, archiveName string, files map[string]string) {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(folder, archiveName), buffer.Bytes(), 0o600))
+}
+
+// pluginFiles returns the minimal files of a valid plugin, located in the given folder of the archive.
+func pluginFiles(folder string) map[string]string {
+	return map[string]string{
+		filepath.Join(folder, PackageJSONFile):  `{"name":"` + folder + `"}`,
+		filepath.Join(folder, ManifestFileName): `{}`,
+	}
+}
+
+func bundleFiles(manifest string, plugins ...string) map[string]string {
+	files := map[string]string{BundleManifestFileName: manifest}
+	for _, name := range plugins {
+		for path, content := range pluginFiles(name) {
+			files[path] = content
+		}
+	}
+	return files
+}
+
+func TestUnzipAll_Bundle(t *testing.T) {
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "all-plugins.tar.gz", bundleFiles(`{"plugins":["prometheus","tempo"]}`, "prometheus", "tempo"))
+	// a plugin extracted at a previous start is replaced
+	require.NoError(t, os.MkdirAll(filepath.Join(targetFolder, "tempo"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(targetFolder, "tempo", "stale.js"), nil, 0o600))
+
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder}
+	require.NoError(t, a.unzipAll())
+
+	for _, name := range []string{"prometheus", "tempo"} {
+		assert.FileExists(t, filepath.Join(targetFolder, name, PackageJSONFile))
+		assert.FileExists(t, filepath.Join(targetFolder, name, ManifestFileName))
+	}
+	assert.NoFileExists(t, filepath.Join(targetFolder, "tempo", "stale.js"))
+	assert.NoDirExists(t, filepath.Join(targetFolder, "all-plugins"))
+}
+
+func TestUnzipAll_PartiallyInvalidBundle(t *testing.T) {
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	files := bundleFiles(`{"plugins":["prometheus","missing","no-manifest","../escaped"]}`, "prometheus", "not-listed")
+	files[filepath.Join("no-manifest", PackageJSONFile)] = `{"name":"no-manifest"}`
+	writeTarGz(t, archiveFolder, "all-plugins.tar.gz", files)
+
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder}
+	require.NoError(t, a.unzipAll())
+
+	entries, err := os.ReadDir(targetFolder)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "prometheus", entries[0].Name())
+	assert.NoDirExists(t, filepath.Join(filepath.Dir(targetFolder), "escaped"))
+}
+
+func TestUnzipAll_BundleInvalidManifest(t *testing.T) {
+	archiveFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "all-plugins.tar.gz", bundleFiles(`{"plugins":`, "prometheus"))
+	a := &arch{folders: []string{archiveFolder}, targetFolder: t.TempDir()}
+	assert.Error(t, a.unzipAll())
+}
+
+func TestUnzipAll_BundleConflicts(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		archives map[string]map[string]string
+	}{
+		{
+			title: "plugin of a bundle also provided as an archive",
+			archives: map[string]map[string]string{
+				"all-plugins.tar.gz": bundleFiles(`{"plugins":["prometheus"]}`, "prometheus"),
+				"prometheus.tar.gz": {
+					PackageJSONFile:  `{"name":"prometheus"}`,
+					ManifestFileName: `{}`,
+				},
+			},
+		},
+		{
+			title: "plugin provided by two bundles",
+			archives: map[string]map[string]string{
+				"bundle-a.tar.gz": bundleFiles(`{"plugins":["prometheus"]}`, "prometheus"),
+				"bundle-b.tar.gz": bundleFiles(`{"plugins":["prometheus","tempo"]}`, "prometheus", "tempo"),
+			},
+		},
+		{
+			title: "plugin listed twice in a bundle",
+			archives: map[string]map[string]string{
+				"all-plugins.tar.gz": bundleFiles(`{"plugins":["prometheus","prometheus"]}`, "prometheus"),
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			archiveFolder := t.TempDir()
+			for name, files := range test.archives {
+				writeTarGz(t, archiveFolder, name, files)
+			}
+			a := &arch{folders: []string{archiveFolder}, targetFolder: t.TempDir()}
+			err := a.unzipAll()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `"prometheus"`)
+		})
+	}
+}
@@ -23,6 +23,11 @@ import (
 )
 
 func (p *pluginFile) LoadDevPlugin(plugins []v1.PluginInDevelopment) error {
+	for _, plg := range plugins {
+		if !p.isDevPlugin(plg.Name) {
+			return apiinterface.HandleBadRequestError(fmt.Sprintf("plugin %q is not allowed to be loaded in development mode", plg.Name))
+		}
+	}
 	for _, plg := range plugins {
 		devURL := plg.URL
 		// We are reading the manifest from the dev server, just to ensure it is present and reachable.
@@ -0,0 +1,127 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/perses/perses/internal/api/archive"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+// unzipRemote downloads the archive referenced by the URL in a temporary folder and extracts it like a local archive.
+func (a *arch) unzipRemote(archiveURL string) error {
+	tmpDir, err := os.MkdirTemp("", "perses-plugin-archive-*")
+	if err != nil {
+		return fmt.Errorf("unable to create the temporary folder receiving the archive: %w", err)
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
+			logrus.WithError(removeErr).Errorf("unable to remove the temporary folder %q", tmpDir)
+		}
+	}()
+	archiveFileName, err := a.download(archiveURL, tmpDir)
+	if err != nil {
+		return fmt.Errorf("unable to download the plugin archive %q: %w", archiveURL, err)
+	}
+	return a.unzip(tmpDir, archiveFileName)
+}
+
+// download writes the archive referenced by the URL in the folder and returns the name of the file written.
+// When the URL has a sha256 query parameter, the digest of the archive downloaded must match it.
+func (a *arch) download(archiveURL string, folder string) (string, error) {
+	u, err := url.Parse(archiveURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	expectedDigest := query.Get(config.ArchiveDigestQueryParam)
+	query.Del(config.ArchiveDigestQueryParam)
+	u.RawQuery = query.Encode()
+	if u.Scheme == "s3" {
+		// There is no AWS client here: the object is fetched from the HTTPS endpoint of the bucket,
+		// so it must be public, or the URL presigned.
+		u.Scheme = "https"
+		u.Host = u.Host + ".s3.amazonaws.com"
+	}
+	archiveFileName := path.Base(u.Path)
+	if !archive.IsArchiveFile(archiveFileName) {
+		return "", fmt.Errorf("%q is not the name of a supported archive", archiveFileName)
+	}
+
+	ctx := context.Background()
+	if a.downloadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.downloadTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	client := a.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("unable to close the response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	file, err := os.Create(filepath.Join(folder, archiveFileName)) //nolint: gosec
+	if err != nil {
+		return "", err
+	}
+	var body io.Reader = resp.Body
+	if a.maxArchiveSize > 0 {
+		// an archive is never larger than its content once extracted
+		body = io.LimitReader(resp.Body, a.maxArchiveSize+1)
+	}
+	hash := sha256.New()
+	written, copyErr := io.Copy(io.MultiWriter(file, hash), body)
+	if closeErr := file.Close(); closeErr != nil && copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		return "", copyErr
+	}
+	if a.maxArchiveSize > 0 && written > a.maxArchiveSize {
+		return "", fmt.Errorf("%w, it exceeds the limit of %d bytes", errArchiveTooLarge, a.maxArchiveSize)
+	}
+	if len(expectedDigest) > 0 {
+		if digest := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(digest, expectedDigest) {
+			return "", fmt.Errorf("the sha256 digest of the archive is %s, %s was expected", digest, expectedDigest)
+		}
+	}
+	return archiveFileName, nil
+}
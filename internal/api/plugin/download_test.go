@@ -0,0 +1,108 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newArchiveServer serves over HTTPS the archive prometheus.tar.gz and returns it with its sha256 digest.
+func newArchiveServer(t *testing.T) (*httptest.Server, string) {
+	archiveFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "prometheus.tar.gz", pluginFiles("."))
+	data, err := os.ReadFile(filepath.Join(archiveFolder, "prometheus.tar.gz"))
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/archives/prometheus.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, hex.EncodeToString(sum[:])
+}
+
+func TestUnzipAll_RemoteArchive(t *testing.T) {
+	server, digest := newArchiveServer(t)
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{
+			name: "without digest",
+			url:  server.URL + "/archives/prometheus.tar.gz",
+		},
+		{
+			name: "with the right digest",
+			url:  server.URL + "/archives/prometheus.tar.gz?sha256=" + digest,
+		},
+		{
+			name:    "with a wrong digest",
+			url:     server.URL + "/archives/prometheus.tar.gz?sha256=e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			wantErr: "was expected",
+		},
+		{
+			name:    "archive not found",
+			url:     server.URL + "/archives/tempo.tar.gz",
+			wantErr: "unexpected status code 404",
+		},
+		{
+			name:    "not an archive",
+			url:     server.URL + "/archives/prometheus.txt",
+			wantErr: "is not the name of a supported archive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetFolder := t.TempDir()
+			a := &arch{folders: []string{tt.url}, targetFolder: targetFolder, downloadTimeout: time.Minute, httpClient: server.Client()}
+			err := a.unzipAll()
+			if len(tt.wantErr) > 0 {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.NoDirExists(t, filepath.Join(targetFolder, "prometheus"))
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(targetFolder, "prometheus", PackageJSONFile))
+		})
+	}
+}
+
+func TestDownload_Timeout(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	a := &arch{downloadTimeout: 50 * time.Millisecond, httpClient: server.Client()}
+	_, err := a.download(server.URL+"/prometheus.tar.gz", t.TempDir())
+	assert.ErrorContains(t, err, "context deadline exceeded")
+}
@@ -0,0 +1,120 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+
+	databaseModel "github.com/perses/perses/internal/api/database/model"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
+	"github.com/perses/perses/internal/api/plugin/tree"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/perses/perses/pkg/plugin/api"
+	"github.com/sirupsen/logrus"
+)
+
+// kvStore is the implementation of api.PluginKVStore relying on the database.
+// Every entry is stored with the name of the plugin module as namespace, which isolates the modules from each other.
+type kvStore struct {
+	module string
+	dao    pluginstate.DAO
+}
+
+func newKVStore(module string, dao pluginstate.DAO) api.PluginKVStore {
+	return &kvStore{
+		module: module,
+		dao:    dao,
+	}
+}
+
+func (s *kvStore) Get(key string) ([]byte, error) {
+	if err := common.ValidateID(key); err != nil {
+		return nil, fmt.Errorf("invalid key %q: %w", key, err)
+	}
+	entity, err := s.dao.Get(s.module, key)
+	if err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return nil, api.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return entity.Spec.Value, nil
+}
+
+func (s *kvStore) Set(key string, value []byte) error {
+	if err := common.ValidateID(key); err != nil {
+		return fmt.Errorf("invalid key %q: %w", key, err)
+	}
+	return s.dao.Upsert(v1.NewPluginState(s.module, key, value))
+}
+
+func (s *kvStore) Delete(key string) error {
+	if err := common.ValidateID(key); err != nil {
+		return fmt.Errorf("invalid key %q: %w", key, err)
+	}
+	if err := s.dao.Delete(s.module, key); err != nil {
+		if databaseModel.IsKeyNotFound(err) {
+			return api.ErrKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *kvStore) List() ([]string, error) {
+	list, err := s.dao.List(&pluginstate.Query{Module: s.module})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(list))
+	for _, entity := range list {
+		keys = append(keys, entity.Metadata.Name)
+	}
+	return keys, nil
+}
+
+// injectKVStores gives to the consumers registered with api.RegisterKVStoreConsumer the key-value store of their plugin
+// module. Only the modules newly loaded are considered, so a consumer is not called again when the plugins are reloaded.
+func (p *pluginFile) injectKVStores(previous, current tree.Tree[*Loaded]) {
+	if p.kvDAO == nil {
+		return
+	}
+	alreadyLoaded := make(map[string]bool)
+	for _, versions := range previous {
+		for _, loaded := range versions {
+			alreadyLoaded[loaded.Module.Metadata.Name] = true
+		}
+	}
+	injected := make(map[string]bool)
+	for _, versions := range current {
+		for version, loaded := range versions {
+			name := loaded.Module.Metadata.Name
+			if version == plugin.LatestVersion || alreadyLoaded[name] || injected[name] {
+				continue
+			}
+			if loaded.Module.Status != nil && !loaded.Module.Status.IsLoaded {
+				continue
+			}
+			consumer, ok := api.GetKVStoreConsumer(name)
+			if !ok {
+				continue
+			}
+			logrus.Debugf("injecting the key-value store of the plugin module %q", name)
+			consumer(newKVStore(name, p.kvDAO))
+			injected[name] = true
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	databaseFile "github.com/perses/perses/internal/api/database/file"
+	pluginStateImpl "github.com/perses/perses/internal/api/impl/v1/pluginstate"
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/plugin/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKVStoreDAO(t *testing.T) pluginstate.DAO {
+	return pluginStateImpl.NewDAO(&databaseFile.DAO{
+		Folder:    t.TempDir(),
+		Extension: config.JSONExtension,
+	})
+}
+
+func TestKVStore(t *testing.T) {
+	store := newKVStore("anomaly", newKVStoreDAO(t))
+
+	_, err := store.Get("baseline")
+	assert.ErrorIs(t, err, api.ErrKeyNotFound)
+	assert.ErrorIs(t, store.Delete("baseline"), api.ErrKeyNotFound)
+
+	require.NoError(t, store.Set("baseline", []byte("42")))
+	value, err := store.Get("baseline")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("42"), value)
+
+	require.NoError(t, store.Set("baseline", []byte("43")))
+	value, err = store.Get("baseline")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("43"), value)
+
+	keys, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"baseline"}, keys)
+
+	require.NoError(t, store.Delete("baseline"))
+	keys, err = store.List()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	assert.Error(t, store.Set("invalid/key", []byte("1")))
+}
+
+func TestKVStore_NamespaceIsolation(t *testing.T) {
+	dao := newKVStoreDAO(t)
+	anomaly := newKVStore("anomaly", dao)
+	forecast := newKVStore("forecast", dao)
+
+	require.NoError(t, anomaly.Set("state", []byte("anomaly")))
+	require.NoError(t, forecast.Set("state", []byte("forecast")))
+	require.NoError(t, forecast.Set("model", []byte("arima")))
+
+	value, err := anomaly.Get("state")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("anomaly"), value)
+	_, err = anomaly.Get("model")
+	assert.ErrorIs(t, err, api.ErrKeyNotFound)
+
+	keys, err := anomaly.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"state"}, keys)
+	keys, err = forecast.List()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"state", "model"}, keys)
+
+	require.NoError(t, anomaly.Delete("state"))
+	value, err = forecast.Get("state")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("forecast"), value)
+}
+
+func TestKVStore_ConcurrentAccess(t *testing.T) {
+	store := newKVStore("anomaly", newKVStoreDAO(t))
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			value := []byte(fmt.Sprintf("value-%d", i))
+			if assert.NoError(t, store.Set(key, value)) {
+				result, err := store.Get(key)
+				assert.NoError(t, err)
+				assert.Equal(t, value, result)
+			}
+		}()
+	}
+	wg.Wait()
+	keys, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, keys, workers)
+}
+
+func TestLoad_InjectKVStore(t *testing.T) {
+	folder := t.TempDir()
+	writePluginFixture(t, folder, "foo")
+	writePluginFixture(t, folder, "bar")
+
+	var stores []api.PluginKVStore
+	require.NoError(t, api.RegisterKVStoreConsumer("foo", func(store api.PluginKVStore) {
+		stores = append(stores, store)
+	}))
+	defer api.UnregisterKVStoreConsumer("foo")
+
+	p := NewWithKVStore(config.Plugin{Path: common.NonEmptyString(folder)}, newKVStoreDAO(t))
+	require.NoError(t, p.Load())
+	require.Len(t, stores, 1)
+	require.NoError(t, stores[0].Set("counter", []byte("1")))
+
+	// reloading the plugins doesn't inject the store again
+	require.NoError(t, p.Load())
+	assert.Len(t, stores, 1)
+}
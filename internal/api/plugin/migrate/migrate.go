@@ -189,6 +189,7 @@ func convertToPlugin(migrateValue cue.Value) (*common.Plugin, bool, error) {
 
 type Migration interface {
 	Load(pluginPath string, module v1.PluginModule) error
+	Unload(module v1.PluginModule)
 	LoadDevPlugin(pluginPath string, module v1.PluginModule) error
 	UnLoadDevPlugin(module v1.PluginModule)
 	Migrate(grafanaDashboard *SimplifiedDashboard, useDefaultDatasource bool) (*v1.Dashboard, error)
@@ -228,6 +229,14 @@ func (m *completeMigration) LoadDevPlugin(pluginPath string, module v1.PluginMod
 	return m.devMig.load(pluginPath, module)
 }
 
+func (m *completeMigration) Unload(module v1.PluginModule) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, plg := range module.Spec.Plugins {
+		m.mig.remove(plg.Kind, plg.Spec.Name)
+	}
+}
+
 func (m *completeMigration) UnLoadDevPlugin(module v1.PluginModule) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -241,10 +250,12 @@ func (m *completeMigration) Migrate(grafanaDashboard *SimplifiedDashboard, useDe
 	defer m.mutex.RUnlock()
 	result := &v1.Dashboard{
 		Kind: v1.KindDashboard,
-		Metadata: v1.ProjectMetadata{
-			Metadata: v1.Metadata{
-				Name: grafanaDashboard.UID,
-				Tags: set.New(grafanaDashboard.Tags...),
+		Metadata: v1.DashboardMetadata{
+			ProjectMetadata: v1.ProjectMetadata{
+				Metadata: v1.Metadata{
+					Name: grafanaDashboard.UID,
+					Tags: set.New(grafanaDashboard.Tags...),
+				},
 			},
 		},
 		Spec: dashboard.Spec{
@@ -24,6 +24,7 @@ import (
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	testUtils "github.com/perses/perses/internal/test"
 	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,7 +34,7 @@ const testDataFolder = "testdata"
 // LoadTestPlugins is a helper function that loads the dummy plugins from testdata
 func LoadTestPlugins() Plugin {
 	cfg := config.Plugin{
-		Path:        filepath.Join("migrate", testDataFolder, "plugins"),
+		Path:        common.NonEmptyString(filepath.Join("migrate", testDataFolder, "plugins")),
 		ArchivePath: "unused",
 	}
 	pluginService := New(cfg)
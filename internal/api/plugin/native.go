@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goplugin "plugin"
+
+	"github.com/perses/perses/pkg/plugin/api"
+	"github.com/sirupsen/logrus"
+)
+
+const nativePluginExtension = ".so"
+
+// loadNativePlugins opens the Go plugins found among the given files of the plugin folder and registers them.
+// A shared library cannot be unloaded, so each of them is registered only once, even if the plugins are reloaded.
+func (p *pluginFile) loadNativePlugins(files []os.DirEntry) {
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != nativePluginExtension {
+			continue
+		}
+		libraryPath := filepath.Join(p.path, f.Name())
+		if p.nativeLoaded[libraryPath] {
+			continue
+		}
+		library, err := goplugin.Open(libraryPath)
+		if err != nil {
+			logrus.WithError(err).Errorf("unable to open the native plugin %q", libraryPath)
+			continue
+		}
+		if registerErr := registerNativePlugin(library.Lookup); registerErr != nil {
+			logrus.WithError(registerErr).Errorf("unable to register the native plugin %q", libraryPath)
+			continue
+		}
+		p.nativeLoaded[libraryPath] = true
+	}
+}
+
+// registerNativePlugin looks up the symbol exported by a Go plugin and registers it.
+// The lookup function is the Lookup method of the opened plugin.
+func registerNativePlugin(lookup func(string) (goplugin.Symbol, error)) error {
+	symbol, err := lookup(api.NativePluginSymbol)
+	if err != nil {
+		return err
+	}
+	var nativePlugin api.NativePlugin
+	switch s := symbol.(type) {
+	case *api.NativePlugin:
+		// the symbol is a variable declared with the interface type
+		nativePlugin = *s
+	case api.NativePlugin:
+		nativePlugin = s
+	}
+	if nativePlugin == nil {
+		return fmt.Errorf("the symbol %q doesn't implement the NativePlugin interface", api.NativePluginSymbol)
+	}
+	logrus.Infof("registering the native plugin %q", nativePlugin.Name())
+	return nativePlugin.Register()
+}
@@ -0,0 +1,121 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goplugin "plugin"
+	"testing"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/plugin/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNativePlugin struct {
+	registered  int
+	registerErr error
+}
+
+func (f *fakeNativePlugin) Name() string {
+	return "fake"
+}
+
+func (f *fakeNativePlugin) Register() error {
+	f.registered++
+	return f.registerErr
+}
+
+func lookupReturning(symbol goplugin.Symbol, err error) func(string) (goplugin.Symbol, error) {
+	return func(name string) (goplugin.Symbol, error) {
+		if name != api.NativePluginSymbol {
+			return nil, errors.New("symbol not found")
+		}
+		return symbol, err
+	}
+}
+
+func TestRegisterNativePlugin(t *testing.T) {
+	t.Run("value implementing the interface", func(t *testing.T) {
+		nativePlugin := &fakeNativePlugin{}
+		assert.NoError(t, registerNativePlugin(lookupReturning(nativePlugin, nil)))
+		assert.Equal(t, 1, nativePlugin.registered)
+	})
+	t.Run("variable declared with the interface type", func(t *testing.T) {
+		fake := &fakeNativePlugin{}
+		var nativePlugin api.NativePlugin = fake
+		assert.NoError(t, registerNativePlugin(lookupReturning(&nativePlugin, nil)))
+		assert.Equal(t, 1, fake.registered)
+	})
+	t.Run("symbol not implementing the interface", func(t *testing.T) {
+		notAPlugin := "perses"
+		assert.Error(t, registerNativePlugin(lookupReturning(&notAPlugin, nil)))
+	})
+	t.Run("nil interface variable", func(t *testing.T) {
+		var nativePlugin api.NativePlugin
+		assert.Error(t, registerNativePlugin(lookupReturning(&nativePlugin, nil)))
+	})
+	t.Run("missing symbol", func(t *testing.T) {
+		assert.Error(t, registerNativePlugin(lookupReturning(nil, errors.New("symbol PersesPlugin not found"))))
+	})
+	t.Run("registration failure", func(t *testing.T) {
+		nativePlugin := &fakeNativePlugin{registerErr: errors.New("boom")}
+		assert.Error(t, registerNativePlugin(lookupReturning(nativePlugin, nil)))
+	})
+}
+
+func TestLoad_NativePlugin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building a Go plugin is slow")
+	}
+	if testing.CoverMode() != "" {
+		t.Skip("a Go plugin cannot be loaded by a binary built with different flags")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build the test plugin")
+	}
+	pluginPath := t.TempDir()
+	libraryPath := filepath.Join(pluginPath, "nativetest.so")
+	build := exec.Command(goBin, "build", "-buildmode=plugin", "-o", libraryPath, "./testdata/nativeplugin") //nolint: gosec
+	if output, buildErr := build.CombinedOutput(); buildErr != nil {
+		t.Skipf("unable to build the test plugin, Go plugins are likely not supported on this platform: %s", output)
+	}
+	// a file with the right extension that is not a Go plugin must not prevent the others from being loaded
+	require.NoError(t, os.WriteFile(filepath.Join(pluginPath, "invalid.so"), []byte("not a plugin"), 0600))
+	defer api.UnregisterKVStoreConsumer("nativetest")
+
+	p := New(config.Plugin{Path: common.NonEmptyString(pluginPath), EnableNativePlugins: true}).(*pluginFile)
+	require.NoError(t, p.Load())
+	_, ok := api.GetKVStoreConsumer("nativetest")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]bool{libraryPath: true}, p.nativeLoaded)
+
+	// Reloading the plugins doesn't register the native plugin a second time, which would fail.
+	require.NoError(t, p.Load())
+	assert.Equal(t, map[string]bool{libraryPath: true}, p.nativeLoaded)
+}
+
+func TestLoad_NativePluginsDisabled(t *testing.T) {
+	pluginPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pluginPath, "invalid.so"), []byte("not a plugin"), 0600))
+	p := New(config.Plugin{Path: common.NonEmptyString(pluginPath)}).(*pluginFile)
+	require.NoError(t, p.Load())
+	assert.Empty(t, p.nativeLoaded)
+}
@@ -39,6 +39,8 @@ type NPMPackage struct {
 }
 
 type BuildInfo struct {
+	// Version is checked when the plugin is loaded, so a plugin with an invalid version is reported with an error
+	// status instead of being ignored.
 	Version string `json:"buildVersion"`
 	Name    string `json:"buildName"`
 }
@@ -14,6 +14,7 @@
 package plugin
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -21,7 +22,9 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/perses/perses/internal/api/interface/v1/pluginstate"
 	"github.com/perses/perses/internal/api/plugin/migrate"
 	"github.com/perses/perses/internal/api/plugin/schema"
 	"github.com/perses/perses/internal/api/plugin/tree"
@@ -29,9 +32,10 @@ import (
 	"github.com/perses/perses/internal/test"
 	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/prometheus/common/version"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/mod/semver"
 )
 
 const pluginFileName = "plugin-modules.json"
@@ -45,13 +49,51 @@ type Loaded struct {
 	LocalPath string
 }
 
+// Filter gathers the lists of the configuration deciding which plugins are loaded.
+type Filter struct {
+	Enabled   []string
+	Disabled  []string
+	AllowList []string
+	DenyList  []string
+}
+
+// NewFilter returns the filter defined by the plugin configuration.
+func NewFilter(cfg config.Plugin) Filter {
+	return Filter{
+		Enabled:   cfg.Enabled,
+		Disabled:  cfg.Disabled,
+		AllowList: cfg.AllowList,
+		DenyList:  cfg.DenyList,
+	}
+}
+
+func (f Filter) Equal(other Filter) bool {
+	return slices.Equal(f.Enabled, other.Enabled) &&
+		slices.Equal(f.Disabled, other.Disabled) &&
+		slices.Equal(f.AllowList, other.AllowList) &&
+		slices.Equal(f.DenyList, other.DenyList)
+}
+
 type Plugin interface {
 	Load() error
+	// UpdateFilter replaces the lists of enabled, disabled, allowed and denied plugins and reloads the plugins accordingly.
+	// Plugins that are no longer allowed are unloaded, while the ones newly allowed are loaded.
+	UpdateFilter(filter Filter) error
+	// UpdateArchivePaths replaces the folders and the URLs of the archives, then extracts them and reloads the plugins
+	// like Reload. The plugins extracted from an archive no longer listed are kept.
+	UpdateArchivePaths(paths []string, downloadTimeout time.Duration) error
 	LoadDevPlugin(plugins []v1.PluginInDevelopment) error
 	RefreshDevPlugin(metadata plugin.ModuleMetadata) error
 	UnLoadDevPlugin(metadata plugin.ModuleMetadata) error
 	List() ([]byte, error)
+	// ListInstalled returns the plugins of the modules loaded, sorted by name, kind and version.
+	ListInstalled() []plugin.PluginInfo
 	UnzipArchives() error
+	// Reload extracts the archives again and loads the plugins, so the plugins added, updated or removed are taken into
+	// account without restarting Perses. It waits for the plugins in use to be released.
+	Reload() error
+	// Acquire marks the plugins as in use, so a reload doesn't replace their files until the function returned is called.
+	Acquire() (release func())
 	GetLoadedPlugin(name, version, registry string) (*Loaded, bool)
 	Schema() schema.Schema
 	Migration() migrate.Migration
@@ -64,7 +106,7 @@ type Plugin interface {
 func StrictLoad() Plugin {
 	projectPath := test.GetRepositoryPath()
 	cfg := config.Plugin{
-		Path:         filepath.Join(projectPath, config.DefaultPluginPath),
+		Path:         common.NonEmptyString(filepath.Join(projectPath, config.DefaultPluginPath)),
 		ArchivePaths: []string{filepath.Join(projectPath, config.DefaultArchivePluginPath)},
 	}
 	pluginService := New(cfg)
@@ -78,21 +120,42 @@ func StrictLoad() Plugin {
 }
 
 func New(cfg config.Plugin) Plugin {
+	signingKeyPath := cfg.SigningKeyPath
+	if cfg.SkipSignatureVerification {
+		signingKeyPath = ""
+	}
 	return &pluginFile{
-		path: cfg.Path,
+		path: string(cfg.Path),
 		archibal: &arch{
-			folders:      cfg.ArchivePaths,
-			targetFolder: cfg.Path,
+			folders:           cfg.ArchivePaths,
+			targetFolder:      string(cfg.Path),
+			extractionTimeout: time.Duration(cfg.ExtractionTimeout),
+			maxArchiveSize:    cfg.MaxArchiveSize,
+			downloadTimeout:   time.Duration(cfg.DownloadTimeout),
+			signingKeyPath:    signingKeyPath,
 		},
-		enabled:   cfg.Enabled,
-		disabled:  cfg.Disabled,
-		sch:       schema.New(),
-		mig:       migrate.New(),
-		loaded:    make(tree.Tree[*Loaded]),
-		devLoaded: make(tree.Tree[*Loaded]),
+		enabled:      cfg.Enabled,
+		disabled:     cfg.Disabled,
+		allowList:    cfg.AllowList,
+		denyList:     cfg.DenyList,
+		isDevPlugin:  cfg.IsDevPlugin,
+		enableNative: cfg.EnableNativePlugins,
+		nativeLoaded: make(map[string]bool),
+		sch:          schema.New(),
+		mig:          migrate.New(),
+		loaded:       make(tree.Tree[*Loaded]),
+		devLoaded:    make(tree.Tree[*Loaded]),
 	}
 }
 
+// NewWithKVStore returns a Plugin service that, when loading a plugin module, gives to the consumer registered for
+// it with api.RegisterKVStoreConsumer a key-value store persisted with the given DAO.
+func NewWithKVStore(cfg config.Plugin, kvDAO pluginstate.DAO) Plugin {
+	p := New(cfg).(*pluginFile)
+	p.kvDAO = kvDAO
+	return p
+}
+
 type pluginFile struct {
 	// path is the local path where the plugins are stored.
 	path string
@@ -104,6 +167,16 @@ type pluginFile struct {
 	enabled []string
 	// disabled is the list of plugin or module that will be dropped when loading them from the file system. If empty, all plugins/modules will be loaded.
 	disabled []string
+	// allowList is the list of the modules that can be loaded. If empty, any module can be loaded.
+	allowList []string
+	// denyList is the list of the modules rejected, whatever allowList contains.
+	denyList []string
+	// isDevPlugin tells whether a plugin is allowed to be loaded from its development server.
+	isDevPlugin func(name string) bool
+	// enableNative activates the loading of the Go plugins (.so files) stored at the root of the plugin folder.
+	enableNative bool
+	// nativeLoaded is the set of the Go plugins already registered, identified by their path.
+	nativeLoaded map[string]bool
 	// archibal is the archive service used only to extract the plugin files from the archive.
 	archibal *arch
 	// sch is the service used to load and provide the schema of the plugin.
@@ -112,8 +185,14 @@ type pluginFile struct {
 	// mig is the service used to load and provide the migration schema of the plugin.
 	// This service is used when migrating the plugin from Grafana to Perses.
 	mig migrate.Migration
+	// kvDAO is used to persist the key-value store of the plugin modules. It is nil when no store is provided.
+	kvDAO pluginstate.DAO
 	// mutex will protect the loaded map.
 	mutex sync.RWMutex
+	// inUse is held for reading while the files of the plugins are used, and for writing while they are reloaded.
+	inUse sync.RWMutex
+	// loadMutex ensures the plugins are not loaded concurrently, and protects the lists of the filter as well as nativeLoaded.
+	loadMutex sync.Mutex
 }
 
 func (p *pluginFile) List() ([]byte, error) {
@@ -130,6 +209,38 @@ func (p *pluginFile) List() ([]byte, error) {
 	return os.ReadFile(pluginFilePath) //nolint: gosec
 }
 
+func (p *pluginFile) ListInstalled() []plugin.PluginInfo {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	result := make([]plugin.PluginInfo, 0)
+	for _, versions := range tree.Merge(p.loaded, p.devLoaded) {
+		for version, loaded := range versions {
+			if version == plugin.LatestVersion {
+				continue
+			}
+			module := loaded.Module
+			for _, plg := range module.Spec.Plugins {
+				result = append(result, plugin.PluginInfo{
+					Name:    plg.Spec.Name,
+					Module:  module.Metadata.Name,
+					Version: module.Metadata.Version,
+					Kind:    plg.Kind,
+					Path:    loaded.LocalPath,
+					Enabled: module.Status == nil || module.Status.IsLoaded,
+				})
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b plugin.PluginInfo) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Version, b.Version),
+		)
+	})
+	return result
+}
+
 func (p *pluginFile) GetLoadedPlugin(name, version, registry string) (*Loaded, bool) {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
@@ -153,11 +264,46 @@ func (p *pluginFile) UnzipArchives() error {
 	return p.archibal.unzipAll()
 }
 
+func (p *pluginFile) Reload() error {
+	p.inUse.Lock()
+	defer p.inUse.Unlock()
+	logrus.Info("reloading the plugins")
+	return p.reload()
+}
+
+func (p *pluginFile) UpdateArchivePaths(paths []string, downloadTimeout time.Duration) error {
+	p.inUse.Lock()
+	defer p.inUse.Unlock()
+	logrus.Info("the plugin archive paths have changed, reloading the plugins")
+	p.archibal.folders = paths
+	p.archibal.downloadTimeout = downloadTimeout
+	return p.reload()
+}
+
+// reload extracts the archives and loads the plugins. The caller must hold inUse.
+func (p *pluginFile) reload() error {
+	if err := p.UnzipArchives(); err != nil {
+		return err
+	}
+	return p.Load()
+}
+
+func (p *pluginFile) Acquire() func() {
+	p.inUse.RLock()
+	return sync.OnceFunc(p.inUse.RUnlock)
+}
+
 func (p *pluginFile) Load() error {
+	p.loadMutex.Lock()
+	defer p.loadMutex.Unlock()
 	files, err := os.ReadDir(p.path)
 	if err != nil {
 		return err
 	}
+	if p.enableNative {
+		p.loadNativePlugins(files)
+	}
+	loaded := make(tree.Tree[*Loaded])
 	for _, f := range files {
 		if !f.IsDir() {
 			// we are only interested in the plugin folder, so any files at the root of the plugin folder can be skipped
@@ -174,13 +320,56 @@ func (p *pluginFile) Load() error {
 			Module:         *pluginModule,
 			LocalPath:      pluginPath,
 		}
-		p.mutex.Lock()
-		p.loaded.Add(pluginModule.Metadata.Name, pluginModule.Metadata, pluginLoaded)
-		p.mutex.Unlock()
+		loaded.Add(pluginModule.Metadata.Name, pluginModule.Metadata, pluginLoaded)
 	}
+	p.mutex.Lock()
+	previous := p.loaded
+	p.loaded = loaded
+	p.mutex.Unlock()
+	p.unloadRemoved(previous, loaded)
+	p.injectKVStores(previous, loaded)
 	return p.storeLoadedList()
 }
 
+func (p *pluginFile) UpdateFilter(filter Filter) error {
+	p.loadMutex.Lock()
+	p.enabled = filter.Enabled
+	p.disabled = filter.Disabled
+	p.allowList = filter.AllowList
+	p.denyList = filter.DenyList
+	p.loadMutex.Unlock()
+	return p.Load()
+}
+
+// unloadRemoved removes the schemas and the migration scripts of the plugins present in the previous tree but not in
+// the current one. It happens when the plugins have been filtered out after a change of the configuration.
+func (p *pluginFile) unloadRemoved(previous, current tree.Tree[*Loaded]) {
+	for _, versions := range previous {
+		for version, previousLoaded := range versions {
+			if version == plugin.LatestVersion {
+				continue
+			}
+			removed := previousLoaded.Module
+			removed.Spec.Plugins = nil
+			currentLoaded, ok := current.Get(previousLoaded.Module.Metadata.Name, previousLoaded.Module.Metadata)
+			for _, plg := range previousLoaded.Module.Spec.Plugins {
+				isKept := ok && slices.ContainsFunc(currentLoaded.Module.Spec.Plugins, func(current plugin.Plugin) bool {
+					return current.Kind == plg.Kind && current.Spec.Name == plg.Spec.Name
+				})
+				if !isKept {
+					removed.Spec.Plugins = append(removed.Spec.Plugins, plg)
+				}
+			}
+			if len(removed.Spec.Plugins) == 0 {
+				continue
+			}
+			logrus.Infof("unloading %d plugin(s) of the module %q as they are filtered out", len(removed.Spec.Plugins), removed.Metadata.Name)
+			p.sch.Unload(removed)
+			p.mig.Unload(removed)
+		}
+	}
+}
+
 func (p *pluginFile) loadSinglePlugin(file os.DirEntry, pluginPath string) *v1.PluginModule {
 	if validErr := IsRequiredFileExists(pluginPath, pluginPath, pluginPath); validErr != nil {
 		logrus.WithError(validErr).Errorf("folder %q is not a valid plugin and is skipped. Missing mandatory files", file.Name())
@@ -198,10 +387,6 @@ func (p *pluginFile) loadSinglePlugin(file os.DirEntry, pluginPath string) *v1.P
 		InDev:    false,
 	}
 
-	version := manifest.Metadata.BuildInfo.Version
-	if !strings.HasPrefix(version, "v") {
-		version = fmt.Sprintf("v%s", version)
-	}
 	pluginModule := &v1.PluginModule{
 		Kind: v1.PluginModuleKind,
 		Metadata: plugin.ModuleMetadata{
@@ -211,13 +396,25 @@ func (p *pluginFile) loadSinglePlugin(file os.DirEntry, pluginPath string) *v1.P
 		Status: pluginStatus,
 	}
 
-	if !semver.IsValid(version) {
-		logrus.Errorf("plugin %q does not follow the semver convention for its version %q", manifest.Name, version)
+	if _, versionErr := common.NewSemver(manifest.Metadata.BuildInfo.Version); versionErr != nil {
+		logrus.WithError(versionErr).Errorf("plugin %q does not follow the semver convention for its version %q", manifest.Name, manifest.Metadata.BuildInfo.Version)
 		pluginStatus.IsLoaded = false
 		pluginStatus.Error = "invalid plugin version, must follow semver convention"
 		return pluginModule
 	}
 
+	moduleName := strings.ToLower(manifest.Name)
+	if slices.Contains(p.denyList, moduleName) {
+		pluginStatus.IsLoaded = false
+		pluginStatus.Error = "plugin is in the deny list"
+		logrus.Errorf("plugin %q is in the deny list and is rejected", manifest.Name)
+		return pluginModule
+	}
+	if len(p.allowList) > 0 && !slices.Contains(p.allowList, moduleName) {
+		logrus.Warnf("plugin %q is not in the allow list and is skipped", manifest.Name)
+		return nil
+	}
+
 	npmPackageData, readErr := ReadPackage(pluginPath)
 	if readErr != nil {
 		pluginStatus.IsLoaded = false
@@ -232,6 +429,16 @@ func (p *pluginFile) loadSinglePlugin(file os.DirEntry, pluginPath string) *v1.P
 		return nil
 	}
 
+	if minVersion := pluginModule.Spec.MinPersesVersion; minVersion != nil {
+		// Development builds don't carry a valid version, in which case the requirement cannot be checked.
+		if persesVersion, err := common.NewSemver(version.Version); err == nil && persesVersion.Compare(*minVersion) < 0 {
+			pluginStatus.IsLoaded = false
+			pluginStatus.Error = fmt.Sprintf("plugin requires Perses %s or later", minVersion)
+			logrus.Errorf("plugin %q requires Perses %s or later, current version is %s", manifest.Name, minVersion, persesVersion)
+			return pluginModule
+		}
+	}
+
 	if IsSchemaRequired(pluginModule.Spec) {
 		if pluginSchemaLoadErr := p.sch.Load(pluginPath, *pluginModule); pluginSchemaLoadErr != nil {
 			pluginStatus.IsLoaded = false
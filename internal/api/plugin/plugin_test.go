@@ -14,11 +14,20 @@
 package plugin
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/plugin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilter(t *testing.T) {
@@ -346,3 +355,130 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+// writePluginFixture creates a minimal plugin module containing a single Explore plugin, which doesn't require any schema.
+func writePluginFixture(t *testing.T, folder string, name string) {
+	pluginPath := filepath.Join(folder, name)
+	require.NoError(t, os.MkdirAll(pluginPath, 0o750))
+	manifest := fmt.Sprintf(`{"id":%q,"name":%q,"metaData":{"buildInfo":{"buildVersion":"0.1.0"}}}`, name, name)
+	require.NoError(t, os.WriteFile(filepath.Join(pluginPath, ManifestFileName), []byte(manifest), 0o600))
+	pkg := fmt.Sprintf(`{"perses":{"plugins":[{"kind":"Explore","spec":{"name":"%sExplorer"}}]}}`, name)
+	require.NoError(t, os.WriteFile(filepath.Join(pluginPath, PackageJSONFile), []byte(pkg), 0o600))
+}
+
+func TestLoad_InvalidVersion(t *testing.T) {
+	folder := t.TempDir()
+	writePluginFixture(t, folder, "foo")
+	writePluginFixture(t, folder, "bar")
+	manifest := `{"id":"bar","name":"bar","metaData":{"buildInfo":{"buildVersion":"not-a-version"}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "bar", ManifestFileName), []byte(manifest), 0o600))
+
+	p := New(config.Plugin{Path: common.NonEmptyString(folder)})
+	require.NoError(t, p.Load())
+
+	// the plugin is not loaded, but it is still listed with the reason
+	list, err := p.List()
+	require.NoError(t, err)
+	var modules []struct {
+		Metadata plugin.ModuleMetadata `json:"metadata"`
+		Status   plugin.ModuleStatus   `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal(list, &modules))
+	statuses := make(map[string]plugin.ModuleStatus)
+	for _, module := range modules {
+		statuses[module.Metadata.Name] = module.Status
+	}
+	assert.True(t, statuses["foo"].IsLoaded)
+	if assert.Contains(t, statuses, "bar") {
+		assert.False(t, statuses["bar"].IsLoaded)
+		assert.Equal(t, "invalid plugin version, must follow semver convention", statuses["bar"].Error)
+	}
+}
+
+func TestUpdateFilter(t *testing.T) {
+	folder := t.TempDir()
+	writePluginFixture(t, folder, "foo")
+	writePluginFixture(t, folder, "bar")
+	p := New(config.Plugin{Path: common.NonEmptyString(folder), Enabled: []string{"foo"}})
+	require.NoError(t, p.Load())
+	isLoaded := func(name string) bool {
+		_, ok := p.GetLoadedPlugin(name, "", "")
+		return ok
+	}
+	assert.True(t, isLoaded("foo"))
+	assert.False(t, isLoaded("bar"))
+
+	// expanding the list of enabled plugins loads the new ones
+	require.NoError(t, p.UpdateFilter(Filter{Enabled: []string{"foo", "bar"}}))
+	assert.True(t, isLoaded("foo"))
+	assert.True(t, isLoaded("bar"))
+
+	// denying a plugin unloads it while keeping the others
+	require.NoError(t, p.UpdateFilter(Filter{Disabled: []string{"foo"}}))
+	assert.False(t, isLoaded("foo"))
+	assert.True(t, isLoaded("bar"))
+
+	list, err := p.List()
+	require.NoError(t, err)
+	assert.NotContains(t, string(list), `"name":"foo"`)
+	assert.Contains(t, string(list), `"name":"bar"`)
+}
+
+func TestLoadDevPlugin_DevPlugins(t *testing.T) {
+	folder := t.TempDir()
+	writePluginFixture(t, folder, "foo")
+	writePluginFixture(t, folder, "bar")
+	// the development server serves the files of the plugins from the folder
+	devServer := httptest.NewServer(http.StripPrefix("/plugins", http.FileServer(http.Dir(folder))))
+	defer devServer.Close()
+	devPlugin := func(name string) v1.PluginInDevelopment {
+		return v1.PluginInDevelopment{Name: name, Version: "0.2.0", URL: common.MustParseURL(devServer.URL)}
+	}
+	isInDev := func(p Plugin, name string) bool {
+		loaded, ok := p.GetLoadedPlugin(name, "", "")
+		require.True(t, ok)
+		return loaded.DevEnvironment != nil
+	}
+
+	p := New(config.Plugin{Path: common.NonEmptyString(folder), DevPlugins: []string{"Foo"}})
+	require.NoError(t, p.Load())
+	require.NoError(t, p.LoadDevPlugin([]v1.PluginInDevelopment{devPlugin("foo")}))
+	assert.Error(t, p.LoadDevPlugin([]v1.PluginInDevelopment{devPlugin("bar")}))
+	assert.True(t, isInDev(p, "foo"))
+	assert.False(t, isInDev(p, "bar"))
+
+	// without dev_plugins, enable_dev allows every plugin to be loaded from its development server
+	p = New(config.Plugin{Path: common.NonEmptyString(folder), EnableDev: true})
+	require.NoError(t, p.Load())
+	require.NoError(t, p.LoadDevPlugin([]v1.PluginInDevelopment{devPlugin("foo"), devPlugin("bar")}))
+	assert.True(t, isInDev(p, "foo"))
+	assert.True(t, isInDev(p, "bar"))
+}
+
+func TestLoad_AllowDenyList(t *testing.T) {
+	folder := t.TempDir()
+	for _, name := range []string{"allowed", "denied", "both", "neither"} {
+		writePluginFixture(t, folder, name)
+	}
+	p := New(config.Plugin{
+		Path:      common.NonEmptyString(folder),
+		AllowList: []string{"allowed", "both"},
+		DenyList:  []string{"denied", "both"},
+	})
+	require.NoError(t, p.Load())
+
+	loaded, ok := p.GetLoadedPlugin("allowed", "", "")
+	require.True(t, ok)
+	assert.True(t, loaded.Module.Status.IsLoaded)
+
+	// the deny list takes precedence over the allow list
+	for _, name := range []string{"denied", "both"} {
+		loaded, ok = p.GetLoadedPlugin(name, "", "")
+		require.True(t, ok)
+		assert.False(t, loaded.Module.Status.IsLoaded)
+		assert.Equal(t, "plugin is in the deny list", loaded.Module.Status.Error)
+	}
+
+	_, ok = p.GetLoadedPlugin("neither", "", "")
+	assert.False(t, ok)
+}
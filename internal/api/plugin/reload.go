@@ -0,0 +1,102 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/perses/common/async"
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/sirupsen/logrus"
+)
+
+// NewFilterReloadTask returns a task reading the configuration again to apply any change made to the lists of enabled,
+// disabled, allowed and denied plugins, without having to restart Perses.
+func NewFilterReloadTask(plg Plugin, configFile string, cfg config.Plugin) async.SimpleTask {
+	return &filterReloadTask{
+		plugin:     plg,
+		configFile: configFile,
+		filter:     NewFilter(cfg),
+	}
+}
+
+type filterReloadTask struct {
+	async.SimpleTask
+	plugin     Plugin
+	configFile string
+	filter     Filter
+}
+
+func (t *filterReloadTask) Execute(_ context.Context, _ context.CancelFunc) error {
+	conf, err := config.Resolve(t.configFile)
+	if err != nil {
+		// The previous filters are kept until the configuration is valid again.
+		logrus.WithError(err).Errorf("unable to read the configuration %q to reload the plugin filters", t.configFile)
+		return nil
+	}
+	filter := NewFilter(conf.Plugin)
+	if t.filter.Equal(filter) {
+		return nil
+	}
+	logrus.Info("the lists of enabled/disabled/allowed/denied plugins have changed, reloading the plugins")
+	if err := t.plugin.UpdateFilter(filter); err != nil {
+		logrus.WithError(err).Error("unable to reload the plugins")
+		return nil
+	}
+	t.filter = filter
+	return nil
+}
+
+func (t *filterReloadTask) String() string {
+	return "plugin filter reload"
+}
+
+// NewSignalReloadTask returns a task reloading the plugins every time Perses receives the signal SIGHUP.
+func NewSignalReloadTask(plg Plugin) async.SimpleTask {
+	return &signalReloadTask{
+		plugin:  plg,
+		signals: []os.Signal{syscall.SIGHUP},
+	}
+}
+
+type signalReloadTask struct {
+	async.SimpleTask
+	plugin  Plugin
+	signals []os.Signal
+}
+
+func (t *signalReloadTask) Execute(ctx context.Context, _ context.CancelFunc) error {
+	sigChannel := make(chan os.Signal, 1)
+	signal.Notify(sigChannel, t.signals...)
+	defer signal.Stop(sigChannel)
+	for {
+		select {
+		case sig := <-sigChannel:
+			logrus.Infof("signal received: %s", sig)
+			if err := t.plugin.Reload(); err != nil {
+				logrus.WithError(err).Error("unable to reload the plugins")
+			}
+		case <-ctx.Done():
+			logrus.Debugf("task '%s' has been canceled", t.String())
+			return nil
+		}
+	}
+}
+
+func (t *signalReloadTask) String() string {
+	return "plugin signal reload"
+}
@@ -0,0 +1,161 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePluginArchive writes in the folder the archive of a plugin containing a single Explore plugin, with the given version.
+func writePluginArchive(t *testing.T, folder string, name string, version string) {
+	writeTarGz(t, folder, name+".tar.gz", map[string]string{
+		ManifestFileName: fmt.Sprintf(`{"id":%q,"name":%q,"metaData":{"buildInfo":{"buildVersion":%q}}}`, name, name, version),
+		PackageJSONFile:  fmt.Sprintf(`{"perses":{"plugins":[{"kind":"Explore","spec":{"name":"%sExplorer"}}]}}`, name),
+	})
+}
+
+func TestReload(t *testing.T) {
+	archiveFolder := t.TempDir()
+	pluginFolder := t.TempDir()
+	writePluginArchive(t, archiveFolder, "foo", "0.1.0")
+	p := New(config.Plugin{Path: common.NonEmptyString(pluginFolder), ArchivePaths: []string{archiveFolder}})
+	require.NoError(t, p.UnzipArchives())
+	require.NoError(t, p.Load())
+	loadedVersion := func(name string) string {
+		loaded, ok := p.GetLoadedPlugin(name, "", "")
+		if !ok {
+			return ""
+		}
+		return loaded.Module.Metadata.Version
+	}
+	assert.Equal(t, "0.1.0", loadedVersion("foo"))
+
+	// the archive of foo is updated and a new plugin is installed
+	writePluginArchive(t, archiveFolder, "foo", "0.2.0")
+	writePluginArchive(t, archiveFolder, "bar", "1.0.0")
+	require.NoError(t, p.Reload())
+	assert.Equal(t, "0.2.0", loadedVersion("foo"))
+	assert.Equal(t, "1.0.0", loadedVersion("bar"))
+	loaded, ok := p.GetLoadedPlugin("foo", "", "")
+	require.True(t, ok)
+	manifest, err := os.ReadFile(filepath.Join(loaded.LocalPath, ManifestFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), `"buildVersion":"0.2.0"`)
+}
+
+func TestReload_WaitsForPluginsInUse(t *testing.T) {
+	archiveFolder := t.TempDir()
+	writePluginArchive(t, archiveFolder, "foo", "0.1.0")
+	p := New(config.Plugin{Path: common.NonEmptyString(t.TempDir()), ArchivePaths: []string{archiveFolder}})
+
+	release := p.Acquire()
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Reload()
+	}()
+	select {
+	case <-done:
+		t.Fatal("the plugins have been reloaded while in use")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	// releasing twice has no effect
+	release()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the plugins have not been reloaded once released")
+	}
+	_, ok := p.GetLoadedPlugin("foo", "", "")
+	assert.True(t, ok)
+}
+
+func TestUpdateArchivePaths(t *testing.T) {
+	archiveFolder := t.TempDir()
+	otherArchiveFolder := t.TempDir()
+	writePluginArchive(t, archiveFolder, "foo", "0.1.0")
+	writePluginArchive(t, otherArchiveFolder, "bar", "1.0.0")
+	p := New(config.Plugin{Path: common.NonEmptyString(t.TempDir()), ArchivePaths: []string{archiveFolder}})
+	require.NoError(t, p.UnzipArchives())
+	require.NoError(t, p.Load())
+	_, ok := p.GetLoadedPlugin("bar", "", "")
+	assert.False(t, ok)
+
+	require.NoError(t, p.UpdateArchivePaths([]string{archiveFolder, otherArchiveFolder}, 0))
+	_, ok = p.GetLoadedPlugin("foo", "", "")
+	assert.True(t, ok)
+	_, ok = p.GetLoadedPlugin("bar", "", "")
+	assert.True(t, ok)
+}
+
+func TestFilterReloadTask(t *testing.T) {
+	pluginFolder := t.TempDir()
+	writePluginFixture(t, pluginFolder, "foo")
+	writePluginFixture(t, pluginFolder, "bar")
+	cfg := config.Plugin{Path: common.NonEmptyString(pluginFolder)}
+	p := New(cfg)
+	require.NoError(t, p.Load())
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(filter string) {
+		content := fmt.Sprintf(`security:
+  encryption_key: "=tW$56zytgB&3jN2E%%7-+qrGZE?v6LCc"
+database:
+  file:
+    folder: %q
+plugin:
+  path: %q
+%s`, t.TempDir(), pluginFolder, filter)
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0o600))
+	}
+	status := func(name string) string {
+		loaded, ok := p.GetLoadedPlugin(name, "", "")
+		if !ok {
+			return "skipped"
+		}
+		if !loaded.Module.Status.IsLoaded {
+			return "rejected"
+		}
+		return "loaded"
+	}
+	task := NewFilterReloadTask(p, configFile, cfg)
+
+	// adding an allow list skips the other plugins
+	writeConfig("  allow_list: [foo]\n")
+	require.NoError(t, task.Execute(context.Background(), nil))
+	assert.Equal(t, "loaded", status("foo"))
+	assert.Equal(t, "skipped", status("bar"))
+
+	// expanding the allow list loads the plugins newly allowed, and the deny list rejects the ones it contains
+	writeConfig("  allow_list: [foo, bar]\n  deny_list: [foo]\n")
+	require.NoError(t, task.Execute(context.Background(), nil))
+	assert.Equal(t, "rejected", status("foo"))
+	assert.Equal(t, "loaded", status("bar"))
+
+	// removing the lists loads every plugin again
+	writeConfig("")
+	require.NoError(t, task.Execute(context.Background(), nil))
+	assert.Equal(t, "loaded", status("foo"))
+	assert.Equal(t, "loaded", status("bar"))
+}
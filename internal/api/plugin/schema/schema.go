@@ -129,6 +129,7 @@ func getPlugin(plugins []plugin.Plugin, kind string) *plugin.Plugin {
 
 type Schema interface {
 	Load(pluginPath string, module v1.PluginModule) error
+	Unload(module v1.PluginModule)
 	LoadDevPlugin(pluginPath string, module v1.PluginModule) error
 	UnloadDevPlugin(module v1.PluginModule)
 	ValidateDatasource(plugin common.Plugin, dtsName string) error
@@ -161,6 +162,14 @@ func (s *completeSchema) Load(pluginPath string, module v1.PluginModule) error {
 	return s.sch.load(pluginPath, module)
 }
 
+func (s *completeSchema) Unload(module v1.PluginModule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, p := range module.Spec.Plugins {
+		s.sch.remove(p.Kind, p.Spec.Name, module.Metadata)
+	}
+}
+
 func (s *completeSchema) LoadDevPlugin(pluginPath string, module v1.PluginModule) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -176,12 +176,14 @@ func TestValidatePanels(t *testing.T) {
 	invalidDatasourceMismatchQuery := loadQueriesFromJSON("testdata/samples/queries/invalid_datasource_mismatch_query.json", t)
 	invalidUnwantedFieldQuery := loadQueriesFromJSON("testdata/samples/queries/invalid_unwanted_field_query.json", t)
 
-	metadata := v1.ProjectMetadata{
-		Metadata: v1.Metadata{
-			Name: "SimpleDashboard",
-		},
-		ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
-			Project: "perses",
+	metadata := v1.DashboardMetadata{
+		ProjectMetadata: v1.ProjectMetadata{
+			Metadata: v1.Metadata{
+				Name: "SimpleDashboard",
+			},
+			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+				Project: "perses",
+			},
 		},
 	}
 
@@ -329,12 +331,14 @@ func TestValidateDashboardVariables(t *testing.T) {
 	validSecondVariable := loadPluginFromJSON("testdata/samples/variables/valid_second_variable.json", t)
 	invalidUnknownVariable := loadPluginFromJSON("testdata/samples/variables/invalid_unknown_variable.json", t)
 
-	metadata := v1.ProjectMetadata{
-		Metadata: v1.Metadata{
-			Name: "SimpleDashboard",
-		},
-		ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
-			Project: "perses",
+	metadata := v1.DashboardMetadata{
+		ProjectMetadata: v1.ProjectMetadata{
+			Metadata: v1.Metadata{
+				Name: "SimpleDashboard",
+			},
+			ProjectMetadataWrapper: v1.ProjectMetadataWrapper{
+				Project: "perses",
+			},
 		},
 	}
 
@@ -0,0 +1,157 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DigestListFileName is the file, at the root of an archive, listing the SHA-256 digest of every other file of the
+// archive, in the format of the `sha256sum` command: one line per file, the hexadecimal digest followed by the path of
+// the file relative to the root of the archive.
+// It can be produced with `find . -type f ! -name 'SHA256SUMS*' -exec sha256sum {} + > SHA256SUMS`.
+const DigestListFileName = "SHA256SUMS"
+
+// SignatureFileName is the file, at the root of an archive, holding the signature of the digest list of the archive.
+// It is the ASN.1 DER encoded ECDSA signature of the SHA-256 digest of the file SHA256SUMS, as produced by
+// `openssl dgst -sha256 -sign <private key> -out SHA256SUMS.sig SHA256SUMS`.
+const SignatureFileName = "SHA256SUMS.sig"
+
+// loadSigningKey reads the PEM encoded ECDSA public key used to verify the signature of the archives.
+func loadSigningKey(path string) (*ecdsa.PublicKey, error) {
+	data, err := os.ReadFile(path) //nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %q", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the key in %q is not an ECDSA public key", path)
+	}
+	return ecdsaKey, nil
+}
+
+// verifyArchiveSignature checks the digest list of the archive extracted in the folder has been signed with the key,
+// and that the extracted files are exactly the ones of the list: a file missing from the list, or whose content doesn't
+// match its digest, is rejected.
+func verifyArchiveSignature(folder string, key *ecdsa.PublicKey) error {
+	signature, err := os.ReadFile(filepath.Join(folder, SignatureFileName)) //nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("the signature file %s is missing", SignatureFileName)
+		}
+		return err
+	}
+	digestList, err := os.ReadFile(filepath.Join(folder, DigestListFileName)) //nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("the digest list %s is missing", DigestListFileName)
+		}
+		return err
+	}
+	digest := sha256.Sum256(digestList)
+	if !ecdsa.VerifyASN1(key, digest[:], signature) {
+		return fmt.Errorf("the signature of %s doesn't match the signing key", DigestListFileName)
+	}
+	digests, err := parseDigestList(digestList)
+	if err != nil {
+		return err
+	}
+	walkErr := filepath.WalkDir(folder, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(folder, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(relativePath)
+		if name == DigestListFileName || name == SignatureFileName {
+			return nil
+		}
+		expected, ok := digests[name]
+		if !ok {
+			return fmt.Errorf("the file %q is not listed in %s", name, DigestListFileName)
+		}
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("the file %q is not a regular file", name)
+		}
+		content, err := os.ReadFile(filePath) //nolint: gosec
+		if err != nil {
+			return err
+		}
+		if actual := sha256.Sum256(content); hex.EncodeToString(actual[:]) != expected {
+			return fmt.Errorf("the file %q doesn't match its digest in %s", name, DigestListFileName)
+		}
+		delete(digests, name)
+		return nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	if len(digests) > 0 {
+		missing := make([]string, 0, len(digests))
+		for name := range digests {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("the files listed in %s are missing: %s", DigestListFileName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// parseDigestList returns the digests of the list, indexed by the path of the files.
+func parseDigestList(data []byte) (map[string]string, error) {
+	digests := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		digest, name, found := strings.Cut(line, " ")
+		// sha256sum prefixes the path with '*' when the file is read in binary mode.
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*")
+		if !found || len(name) == 0 || len(digest) != 2*sha256.Size {
+			return nil, fmt.Errorf("invalid line %d in %s", i+1, DigestListFileName)
+		}
+		if _, err := hex.DecodeString(digest); err != nil {
+			return nil, fmt.Errorf("invalid digest at the line %d in %s", i+1, DigestListFileName)
+		}
+		name = path.Clean(name)
+		if _, ok := digests[name]; ok {
+			return nil, fmt.Errorf("the file %q is listed twice in %s", name, DigestListFileName)
+		}
+		digests[name] = strings.ToLower(digest)
+	}
+	return digests, nil
+}
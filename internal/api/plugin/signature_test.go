@@ -0,0 +1,201 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/perses/perses/pkg/model/api/config"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSigningKey generates an ECDSA key and writes its public part in the folder. It returns the private key and the path to the public key.
+func writeSigningKey(t *testing.T, folder string, name string) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyPath := filepath.Join(folder, name)
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return key, keyPath
+}
+
+// signFiles adds to the files the digest list of every file and its signature with the key.
+func signFiles(t *testing.T, key *ecdsa.PrivateKey, files map[string]string) map[string]string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var digestList strings.Builder
+	for _, name := range names {
+		digest := sha256.Sum256([]byte(files[name]))
+		// the paths are written like `find . -type f -exec sha256sum {} +` does
+		fmt.Fprintf(&digestList, "%s  ./%s\n", hex.EncodeToString(digest[:]), name)
+	}
+	digest := sha256.Sum256([]byte(digestList.String()))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	files[DigestListFileName] = digestList.String()
+	files[SignatureFileName] = string(signature)
+	return files
+}
+
+// signedPluginFiles returns the files of a valid plugin signed with the key.
+func signedPluginFiles(t *testing.T, key *ecdsa.PrivateKey) map[string]string {
+	return signFiles(t, key, pluginFiles("."))
+}
+
+func TestUnzipAll_Signature(t *testing.T) {
+	keyFolder := t.TempDir()
+	key, keyPath := writeSigningKey(t, keyFolder, "perses.pub")
+	otherKey, _ := writeSigningKey(t, keyFolder, "other.pub")
+
+	tampered := signedPluginFiles(t, key)
+	tampered[PackageJSONFile] = `{"name":"malicious"}`
+	unlisted := signedPluginFiles(t, key)
+	unlisted["__mf/js/malicious.js"] = "alert(1)"
+	missing := signedPluginFiles(t, key)
+	delete(missing, PackageJSONFile)
+	noDigestList := signedPluginFiles(t, key)
+	delete(noDigestList, DigestListFileName)
+
+	testSuite := []struct {
+		title   string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			title: "valid signature",
+			files: signedPluginFiles(t, key),
+		},
+		{
+			title: "valid signature of a plugin with assets",
+			files: signFiles(t, key, map[string]string{
+				PackageJSONFile:            `{"name":"prometheus"}`,
+				ManifestFileName:           `{}`,
+				"__mf/js/prometheus.js":    "console.log('prometheus')",
+				"__mf/css/prometheus.css":  "body {}",
+				"__mf/img/prometheus.svg":  "<svg/>",
+				"schemas/prometheus.cue":   "package model",
+				"schemas/prometheus/q.cue": "package model",
+			}),
+		},
+		{
+			title:   "signed with another key",
+			files:   signedPluginFiles(t, otherKey),
+			wantErr: "doesn't match the signing key",
+		},
+		{
+			title:   "missing signature",
+			files:   pluginFiles("."),
+			wantErr: "the signature file SHA256SUMS.sig is missing",
+		},
+		{
+			title:   "missing digest list",
+			files:   noDigestList,
+			wantErr: "the digest list SHA256SUMS is missing",
+		},
+		{
+			title:   "tampered file",
+			files:   tampered,
+			wantErr: `the file "package.json" doesn't match its digest in SHA256SUMS`,
+		},
+		{
+			title:   "file not listed",
+			files:   unlisted,
+			wantErr: `the file "__mf/js/malicious.js" is not listed in SHA256SUMS`,
+		},
+		{
+			title:   "listed file missing",
+			files:   missing,
+			wantErr: "the files listed in SHA256SUMS are missing: package.json",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			archiveFolder := t.TempDir()
+			targetFolder := t.TempDir()
+			writeTarGz(t, archiveFolder, "prometheus.tar.gz", test.files)
+
+			a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder, signingKeyPath: keyPath}
+			err := a.unzipAll()
+			if len(test.wantErr) > 0 {
+				assert.ErrorContains(t, err, test.wantErr)
+				assert.NoDirExists(t, filepath.Join(targetFolder, "prometheus"))
+				return
+			}
+			require.NoError(t, err)
+			assert.FileExists(t, filepath.Join(targetFolder, "prometheus", ManifestFileName))
+		})
+	}
+}
+
+func TestUnzipAll_SignatureIgnoresPreviousExtraction(t *testing.T) {
+	key, keyPath := writeSigningKey(t, t.TempDir(), "perses.pub")
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "prometheus.tar.gz", signedPluginFiles(t, key))
+	// a file extracted from a previous version of the archive is not part of the signed files
+	require.NoError(t, os.MkdirAll(filepath.Join(targetFolder, "prometheus"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(targetFolder, "prometheus", "stale.js"), nil, 0o600))
+
+	a := &arch{folders: []string{archiveFolder}, targetFolder: targetFolder, signingKeyPath: keyPath}
+	require.NoError(t, a.unzipAll())
+	assert.NoFileExists(t, filepath.Join(targetFolder, "prometheus", "stale.js"))
+}
+
+func TestParseDigestList(t *testing.T) {
+	digest := strings.Repeat("ab", sha256.Size)
+	digests, err := parseDigestList([]byte(digest + "  ./package.json\n" + strings.ToUpper(digest) + " *__mf/js/main.js\n\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"package.json": digest, "__mf/js/main.js": digest}, digests)
+
+	_, err = parseDigestList([]byte("abcd  package.json\n"))
+	assert.EqualError(t, err, "invalid line 1 in SHA256SUMS")
+	_, err = parseDigestList([]byte(digest + "  package.json\n" + digest + "  ./package.json\n"))
+	assert.EqualError(t, err, `the file "package.json" is listed twice in SHA256SUMS`)
+}
+
+func TestUnzipArchives_SkipSignatureVerification(t *testing.T) {
+	_, keyPath := writeSigningKey(t, t.TempDir(), "perses.pub")
+	archiveFolder := t.TempDir()
+	targetFolder := t.TempDir()
+	writeTarGz(t, archiveFolder, "prometheus.tar.gz", pluginFiles("."))
+
+	cfg := config.Plugin{
+		Path:           common.NonEmptyString(targetFolder),
+		ArchivePaths:   []string{archiveFolder},
+		SigningKeyPath: keyPath,
+	}
+	assert.ErrorContains(t, New(cfg).UnzipArchives(), "the signature file SHA256SUMS.sig is missing")
+
+	cfg.SkipSignatureVerification = true
+	require.NoError(t, New(cfg).UnzipArchives())
+	assert.FileExists(t, filepath.Join(targetFolder, "prometheus", ManifestFileName))
+}
@@ -0,0 +1,31 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main is a Go plugin used by the tests of the native plugin loader.
+// It is built on the fly with `go build -buildmode=plugin`.
+package main
+
+import "github.com/perses/perses/pkg/plugin/api"
+
+type nativeTestPlugin struct{}
+
+func (p *nativeTestPlugin) Name() string {
+	return "native-test"
+}
+
+func (p *nativeTestPlugin) Register() error {
+	return api.RegisterKVStoreConsumer("nativetest", func(_ api.PluginKVStore) {})
+}
+
+// PersesPlugin is the symbol looked up by Perses.
+var PersesPlugin api.NativePlugin = &nativeTestPlugin{}
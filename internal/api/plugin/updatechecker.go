@@ -0,0 +1,163 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/perses/perses/internal/httpclient"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/sirupsen/logrus"
+)
+
+// Update describes a loaded plugin for which the registry knows a newer version.
+type Update struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"currentVersion"`
+	LatestVersion  string `json:"latestVersion"`
+}
+
+// UpdateChecker is a task comparing the version of the loaded plugins with the latest version published in a registry.
+type UpdateChecker interface {
+	async.SimpleTask
+	// Updates returns the updates found during the last successful check.
+	Updates() []Update
+}
+
+func NewUpdateChecker(plg Plugin, registryURL string) UpdateChecker {
+	return &updateChecker{
+		plugin:      plg,
+		registryURL: registryURL,
+		client:      httpclient.NewHTTPClient(httpclient.WithTimeout(30 * time.Second)),
+		updates:     []Update{},
+	}
+}
+
+type updateChecker struct {
+	async.SimpleTask
+	plugin      Plugin
+	registryURL string
+	client      *http.Client
+	mutex       sync.RWMutex
+	updates     []Update
+}
+
+func (u *updateChecker) Execute(ctx context.Context, _ context.CancelFunc) error {
+	latest, err := u.fetchLatestVersions(ctx)
+	if err != nil {
+		// The updates found previously are kept until the registry is reachable again.
+		logrus.WithError(err).Errorf("unable to check the plugin registry %q for updates", u.registryURL)
+		return nil
+	}
+	data, err := u.plugin.List()
+	if err != nil {
+		logrus.WithError(err).Error("unable to list the loaded plugins to check for updates")
+		return nil
+	}
+	var modules []v1.PluginModule
+	if unmarshalErr := json.Unmarshal(data, &modules); unmarshalErr != nil {
+		logrus.WithError(unmarshalErr).Error("unable to decode the list of loaded plugins to check for updates")
+		return nil
+	}
+	updates := findUpdates(modules, latest)
+	u.mutex.Lock()
+	u.updates = updates
+	u.mutex.Unlock()
+	if len(updates) > 0 {
+		available := make([]string, 0, len(updates))
+		for _, update := range updates {
+			available = append(available, fmt.Sprintf("%s: %s -> %s", update.Name, update.CurrentVersion, update.LatestVersion))
+		}
+		logrus.WithField("updates", available).Warn("new versions of plugins are available")
+	}
+	return nil
+}
+
+func (u *updateChecker) Updates() []Update {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.updates
+}
+
+func (u *updateChecker) String() string {
+	return "plugin update checker"
+}
+
+// fetchLatestVersions queries the registry, expected to return a JSON list of module metadata, each of them holding
+// the latest version available of a plugin module.
+func (u *updateChecker) fetchLatestVersions(ctx context.Context) ([]plugin.ModuleMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.registryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	var latest []plugin.ModuleMetadata
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&latest); decodeErr != nil {
+		return nil, fmt.Errorf("unable to decode the response: %w", decodeErr)
+	}
+	return latest, nil
+}
+
+func findUpdates(modules []v1.PluginModule, latest []plugin.ModuleMetadata) []Update {
+	latestVersions := make(map[string]common.Semver, len(latest))
+	for _, metadata := range latest {
+		version, err := common.NewSemver(metadata.Version)
+		if err != nil {
+			logrus.WithError(err).Debugf("the registry returned an invalid version for the plugin %q", metadata.Name)
+			continue
+		}
+		if previous, ok := latestVersions[metadata.Name]; !ok || version.Compare(previous) > 0 {
+			latestVersions[metadata.Name] = version
+		}
+	}
+	updates := []Update{}
+	for _, module := range modules {
+		latestVersion, ok := latestVersions[module.Metadata.Name]
+		if !ok {
+			continue
+		}
+		currentVersion, err := common.NewSemver(module.Metadata.Version)
+		if err != nil {
+			continue
+		}
+		if latestVersion.Compare(currentVersion) > 0 {
+			updates = append(updates, Update{
+				Name:           module.Metadata.Name,
+				CurrentVersion: module.Metadata.Version,
+				LatestVersion:  latestVersion.String(),
+			})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Name < updates[j].Name
+	})
+	return updates
+}
@@ -0,0 +1,100 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listOnlyPlugin struct {
+	Plugin
+	modules []v1.PluginModule
+}
+
+func (p *listOnlyPlugin) List() ([]byte, error) {
+	return json.Marshal(p.modules)
+}
+
+func newLoadedModule(name, version string) v1.PluginModule {
+	return v1.PluginModule{
+		Kind:     "PluginModule",
+		Metadata: plugin.ModuleMetadata{Name: name, Version: version},
+	}
+}
+
+func newMockRegistry(t *testing.T, latest []plugin.ModuleMetadata) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(latest))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUpdateChecker(t *testing.T) {
+	registry := newMockRegistry(t, []plugin.ModuleMetadata{
+		{Name: "prometheus", Version: "0.52.0"},
+		{Name: "prometheus", Version: "0.53.1"},
+		{Name: "tempo", Version: "0.50.0"},
+		{Name: "timeserieschart", Version: "0.10.0"},
+		{Name: "unknown", Version: "1.0.0"},
+		{Name: "table", Version: "not-a-version"},
+	})
+	plg := &listOnlyPlugin{modules: []v1.PluginModule{
+		newLoadedModule("prometheus", "0.51.0"),
+		newLoadedModule("tempo", "0.50.0"),
+		newLoadedModule("timeserieschart", "v0.9.2"),
+		newLoadedModule("table", "0.9.0"),
+	}}
+	checker := NewUpdateChecker(plg, registry.URL)
+	assert.Empty(t, checker.Updates())
+
+	require.NoError(t, checker.Execute(context.Background(), nil))
+	assert.Equal(t, []Update{
+		{Name: "prometheus", CurrentVersion: "0.51.0", LatestVersion: "0.53.1"},
+		{Name: "timeserieschart", CurrentVersion: "v0.9.2", LatestVersion: "0.10.0"},
+	}, checker.Updates())
+}
+
+func TestUpdateCheckerKeepsUpdatesWhenRegistryFails(t *testing.T) {
+	var failing atomic.Bool
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode([]plugin.ModuleMetadata{{Name: "prometheus", Version: "0.53.0"}}))
+	}))
+	t.Cleanup(registry.Close)
+	plg := &listOnlyPlugin{modules: []v1.PluginModule{newLoadedModule("prometheus", "0.51.0")}}
+	checker := NewUpdateChecker(plg, registry.URL)
+
+	require.NoError(t, checker.Execute(context.Background(), nil))
+	expected := []Update{{Name: "prometheus", CurrentVersion: "0.51.0", LatestVersion: "0.53.0"}}
+	assert.Equal(t, expected, checker.Updates())
+
+	failing.Store(true)
+	require.NoError(t, checker.Execute(context.Background(), nil))
+	assert.Equal(t, expected, checker.Updates())
+}
@@ -201,6 +201,22 @@ func (p *provisioning) getService(object modelAPI.Entity, parameters apiInterfac
 			func() (modelAPI.Entity, error) {
 				return svc.Update(nil, entity, parameters)
 			}, nil
+	case *modelV1.TemplatePolicy:
+		svc := p.serviceManager.GetTemplatePolicy()
+		return func() (modelAPI.Entity, error) {
+				return svc.Create(nil, entity)
+			},
+			func() (modelAPI.Entity, error) {
+				return svc.Update(nil, entity, parameters)
+			}, nil
+	case *modelV1.TemplatePolicyBinding:
+		svc := p.serviceManager.GetTemplatePolicyBinding()
+		return func() (modelAPI.Entity, error) {
+				return svc.Create(nil, entity)
+			},
+			func() (modelAPI.Entity, error) {
+				return svc.Update(nil, entity, parameters)
+			}, nil
 	case *modelV1.User:
 		svc := p.serviceManager.GetUser()
 		return func() (modelAPI.Entity, error) {
@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// MIMEApplicationPersesJSON is the media type used to ask for a specific version of the API with the Accept header,
+	// like `application/vnd.perses+json; version=2`.
+	MIMEApplicationPersesJSON = "application/vnd.perses+json"
+	// DefaultVersion is the version of the API used when the request doesn't ask for any.
+	DefaultVersion = 1
+)
+
+// VersionedHandler dispatches the requests to the handler of the API version asked in the Accept header.
+// The requests not asking for any version are sent to the handler of the DefaultVersion.
+// The requests asking for a version without handler are rejected with the status code 406.
+type VersionedHandler map[int]echo.HandlerFunc
+
+func (v VersionedHandler) Handle(ctx echo.Context) error {
+	version, err := RequestedVersion(ctx.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	handler, ok := v[version]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotAcceptable, fmt.Sprintf("version %d of the API is not supported by this endpoint", version))
+	}
+	return handler(ctx)
+}
+
+// RequestedVersion returns the version of the API asked with the parameter `version` of the media type
+// MIMEApplicationPersesJSON in the Accept header. It returns DefaultVersion when the header doesn't contain this media type.
+func RequestedVersion(req *http.Request) (int, error) {
+	for _, accept := range req.Header.Values(echo.HeaderAccept) {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mediaType != MIMEApplicationPersesJSON {
+				continue
+			}
+			version, ok := params["version"]
+			if !ok {
+				return DefaultVersion, nil
+			}
+			v, err := strconv.Atoi(version)
+			if err != nil || v < 1 {
+				return 0, fmt.Errorf("invalid API version %q in the Accept header", version)
+			}
+			return v, nil
+		}
+	}
+	return DefaultVersion, nil
+}
@@ -0,0 +1,109 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedHandler(t *testing.T) {
+	handler := VersionedHandler{
+		1: func(ctx echo.Context) error { return ctx.String(http.StatusOK, "v1") },
+		2: func(ctx echo.Context) error { return ctx.String(http.StatusOK, "v2") },
+	}
+	testSuite := []struct {
+		title          string
+		accept         []string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			title:          "no Accept header falls back to v1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v1",
+		},
+		{
+			title:          "generic media type falls back to v1",
+			accept:         []string{"application/json"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v1",
+		},
+		{
+			title:          "vendor media type without version falls back to v1",
+			accept:         []string{"application/vnd.perses+json"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v1",
+		},
+		{
+			title:          "version 1",
+			accept:         []string{"application/vnd.perses+json; version=1"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v1",
+		},
+		{
+			title:          "version 2",
+			accept:         []string{"application/vnd.perses+json; version=2"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v2",
+		},
+		{
+			title:          "version 2 among other media types",
+			accept:         []string{"text/html, application/vnd.perses+json;version=2;q=0.9"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v2",
+		},
+		{
+			title:          "version 2 in a second Accept header",
+			accept:         []string{"application/json", "application/vnd.perses+json; version=2"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "v2",
+		},
+		{
+			title:          "unsupported version",
+			accept:         []string{"application/vnd.perses+json; version=3"},
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			title:          "invalid version",
+			accept:         []string{"application/vnd.perses+json; version=two"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			title:          "negative version",
+			accept:         []string{"application/vnd.perses+json; version=-1"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/", handler.Handle)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, accept := range test.accept {
+				req.Header.Add(echo.HeaderAccept, accept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if len(test.expectedBody) > 0 {
+				assert.Equal(t, test.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sse manages the Server-Sent Events streams opened by the clients, so they can be drained when the server stops.
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perses/common/async"
+	"github.com/sirupsen/logrus"
+)
+
+// EventShutdown is the event sent to every stream when the server stops. The clients are expected to close the
+// stream and to reconnect later, possibly to another instance.
+const EventShutdown = "shutdown"
+
+// ErrShuttingDown is returned when a stream is opened while the server is stopping.
+// The handler should answer with the status code 503.
+var ErrShuttingDown = errors.New("the server is shutting down")
+
+func NewHub(gracefulShutdownTimeout time.Duration) *Hub {
+	return &Hub{
+		timeout: gracefulShutdownTimeout,
+		streams: make(map[*Stream]struct{}),
+	}
+}
+
+// Hub keeps track of the open streams. It is run as a task, and when the server stops, it rejects the new streams,
+// sends the event `shutdown` to the open ones and waits for the clients to close them. The streams still open after
+// the timeout are closed by the server.
+type Hub struct {
+	async.Task
+	timeout  time.Duration
+	mutex    sync.Mutex
+	stopping bool
+	streams  map[*Stream]struct{}
+	active   sync.WaitGroup
+}
+
+func (h *Hub) String() string {
+	return "server-sent events hub"
+}
+
+func (h *Hub) Initialize() error {
+	return nil
+}
+
+func (h *Hub) Execute(ctx context.Context, _ context.CancelFunc) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (h *Hub) Finalize() error {
+	h.Shutdown()
+	return nil
+}
+
+// Open starts a stream on the response. The handler must call Stream.Close once the stream is over, and must stop
+// when the request context or Stream.Done is closed.
+func (h *Hub) Open(w http.ResponseWriter, r *http.Request) (*Stream, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("the response writer doesn't support the streaming")
+	}
+	stream := &Stream{
+		hub:     h,
+		writer:  w,
+		flusher: flusher,
+		done:    make(chan struct{}),
+	}
+	// The stream is locked until the headers are sent, as the shutdown event can be sent as soon as the stream is registered.
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	h.mutex.Lock()
+	if h.stopping {
+		h.mutex.Unlock()
+		return nil, ErrShuttingDown
+	}
+	h.streams[stream] = struct{}{}
+	h.active.Add(1)
+	h.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	logrus.Debugf("server-sent events stream opened for %s", r.URL.Path)
+	return stream, nil
+}
+
+// Shutdown rejects the new streams and drains the open ones. It returns once every stream is closed, or when the
+// graceful shutdown timeout is reached, after forcing the remaining streams to close.
+func (h *Hub) Shutdown() {
+	h.mutex.Lock()
+	h.stopping = true
+	streams := make([]*Stream, 0, len(h.streams))
+	for stream := range h.streams {
+		streams = append(streams, stream)
+	}
+	h.mutex.Unlock()
+
+	for _, stream := range streams {
+		if err := stream.Send(EventShutdown, "the server is shutting down"); err != nil {
+			logrus.WithError(err).Debug("unable to send the shutdown event")
+		}
+	}
+	drained := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return
+	case <-time.After(h.timeout):
+	}
+
+	h.mutex.Lock()
+	logrus.Warningf("%d server-sent events streams still open after %s, closing them", len(h.streams), h.timeout)
+	for stream := range h.streams {
+		stream.forceClose()
+	}
+	h.mutex.Unlock()
+}
+
+func (h *Hub) remove(stream *Stream) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, ok := h.streams[stream]; ok {
+		delete(h.streams, stream)
+		h.active.Done()
+	}
+}
+
+// Stream is a Server-Sent Events stream opened by a client.
+type Stream struct {
+	hub     *Hub
+	writer  http.ResponseWriter
+	flusher http.Flusher
+	// mutex serializes the writes, and prevents any write once the handler has closed the stream.
+	mutex     sync.Mutex
+	closed    bool
+	done      chan struct{}
+	forceOnce sync.Once
+}
+
+// Send writes an event on the stream. A multi-line data is sent as several data lines.
+func (s *Stream) Send(event string, data string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return errors.New("the stream is closed")
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "event: %s\n", event)
+	for line := range strings.SplitSeq(data, "\n") {
+		fmt.Fprintf(&builder, "data: %s\n", line)
+	}
+	builder.WriteString("\n")
+	if _, err := s.writer.Write([]byte(builder.String())); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Done is closed when the server forces the stream to close.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unregisters the stream from the hub.
+func (s *Stream) Close() {
+	s.mutex.Lock()
+	s.closed = true
+	s.mutex.Unlock()
+	s.hub.remove(s)
+}
+
+func (s *Stream) forceClose() {
+	s.forceOnce.Do(func() {
+		close(s.done)
+	})
+}
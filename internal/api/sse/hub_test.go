@@ -0,0 +1,121 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamServer(hub *Hub) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := hub.Open(w, r)
+		if errors.Is(err, ErrShuttingDown) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer stream.Close()
+		select {
+		case <-r.Context().Done():
+		case <-stream.Done():
+		}
+	}))
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestShutdownSendsEvent(t *testing.T) {
+	hub := NewHub(5 * time.Second)
+	server := newStreamServer(hub)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		hub.Shutdown()
+		close(shutdownDone)
+	}()
+	assert.Equal(t, []string{"event: shutdown\n", "data: the server is shutting down\n"}, readEvent(t, bufio.NewReader(resp.Body)))
+
+	// The shutdown waits for the client to close the stream.
+	select {
+	case <-shutdownDone:
+		t.Fatal("the shutdown didn't wait for the stream to be closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, resp.Body.Close())
+	select {
+	case <-shutdownDone:
+	case <-time.After(time.Second):
+		t.Fatal("the shutdown didn't end once the stream was closed")
+	}
+
+	// New streams are rejected.
+	rejected, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer rejected.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, rejected.StatusCode)
+}
+
+func TestShutdownForcesStreams(t *testing.T) {
+	hub := NewHub(50 * time.Millisecond)
+	server := newStreamServer(hub)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	hub.Shutdown()
+	// The stream is closed by the server after the timeout, even if the client is still connected.
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "event: shutdown\ndata: the server is shutting down\n\n", string(body))
+}
+
+func TestSendMultiLineData(t *testing.T) {
+	hub := NewHub(time.Second)
+	recorder := httptest.NewRecorder()
+	stream, err := hub.Open(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.NoError(t, stream.Send("update", "line1\nline2"))
+	stream.Close()
+	assert.Error(t, stream.Send("update", "after close"))
+	assert.Equal(t, "event: update\ndata: line1\ndata: line2\n\n", recorder.Body.String())
+}
@@ -29,6 +29,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// dryRunQueryParam is the query parameter asking to compute what an update would change instead of applying it.
+const dryRunQueryParam = "dry-run"
+
 func ExtractParameters(ctx echo.Context, caseSensitive bool) apiInterface.Parameters {
 	project := utils.GetProjectParameter(ctx)
 	name := utils.GetNameParameter(ctx)
@@ -167,6 +170,9 @@ func (t *toolbox[T, K, V]) Update(ctx echo.Context, entity T) error {
 	if err := t.checkPermission(ctx, entity, parameters, role.UpdateAction); err != nil {
 		return err
 	}
+	if ctx.QueryParam(dryRunQueryParam) == "true" {
+		return t.diffUpdate(ctx, entity, parameters)
+	}
 	newEntity, err := t.service.Update(ctx, entity, parameters)
 	if err != nil {
 		return err
@@ -174,6 +180,23 @@ func (t *toolbox[T, K, V]) Update(ctx echo.Context, entity T) error {
 	return ctx.JSON(http.StatusOK, newEntity)
 }
 
+// diffUpdate answers the updates in dry-run mode with the JSON Patch the update would apply, or with no content when
+// the update wouldn't change anything.
+func (t *toolbox[T, K, V]) diffUpdate(ctx echo.Context, entity T, parameters apiInterface.Parameters) error {
+	diffService, ok := t.service.(apiInterface.DiffService[T])
+	if !ok {
+		return apiInterface.HandleBadRequestError(fmt.Sprintf("dry-run is not supported for the kind %q", t.kind))
+	}
+	patch, err := diffService.DiffUpdate(ctx, entity, parameters)
+	if err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+	return ctx.JSON(http.StatusOK, patch)
+}
+
 func (t *toolbox[T, K, V]) Delete(ctx echo.Context) error {
 	parameters := ExtractParameters(ctx, t.caseSensitive)
 	if err := t.checkPermission(ctx, nil, parameters, role.DeleteAction); err != nil {
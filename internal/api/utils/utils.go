@@ -20,50 +20,61 @@ import (
 )
 
 const (
-	ParamDashboard         = "dashboard"
-	ParamName              = "name"
-	ParamProject           = "project"
-	APIPrefix              = "/api"
-	PathAuth               = "auth"
-	PathAuthProviders      = "auth/providers"
-	PathLogin              = "login"
-	PathCallback           = "callback"
-	PathLogout             = "logout"
-	PathRefresh            = "refresh"
-	PathDeviceCode         = "device/code"
-	PathToken              = "token"
-	AuthnKindNative        = "native"
-	AuthnKindOIDC          = "oidc"
-	AuthnKindOAuth         = "oauth"
-	AuthnKindKubernetes    = "kubernetes"
-	APIV1Prefix            = "/api/v1"
-	PathDashboard          = "dashboards"
-	PathDatasource         = "datasources"
-	PathEphemeralDashboard = "ephemeraldashboards"
-	PathFolder             = "folders"
-	PathGlobalDatasource   = "globaldatasources"
-	PathGlobalRole         = "globalroles"
-	PathGlobalRoleBinding  = "globalrolebindings"
-	PathGlobalSecret       = "globalsecrets"
-	PathGlobalVariable     = "globalvariables"
-	PathProject            = "projects"
-	PathRole               = "roles"
-	PathRoleBinding        = "rolebindings"
-	PathSecret             = "secrets"
-	PathUnsaved            = "unsaved"
-	PathUser               = "users"
-	PathCurrentUser        = "user"
-	PathVariable           = "variables"
-	PathView               = "view"
-	PathWhoAmI             = "whoami"
-	ContextKeyAnonymous    = "anonymous"
+	ParamDashboard            = "dashboard"
+	ParamName                 = "name"
+	ParamProject              = "project"
+	APIPrefix                 = "/api"
+	PathAuth                  = "auth"
+	PathAuthProviders         = "auth/providers"
+	PathLogin                 = "login"
+	PathCallback              = "callback"
+	PathLogout                = "logout"
+	PathRefresh               = "refresh"
+	PathDeviceCode            = "device/code"
+	PathToken                 = "token"
+	PathBackChannelLogout     = "backchannel-logout"
+	AuthnKindNative           = "native"
+	AuthnKindOIDC             = "oidc"
+	AuthnKindOAuth            = "oauth"
+	AuthnKindKubernetes       = "kubernetes"
+	AuthnKindCustom           = "custom"
+	APIV1Prefix               = "/api/v1"
+	PathAPIKey                = "apikeys"
+	PathDashboard             = "dashboards"
+	PathDashboardTemplate     = "dashboardtemplates"
+	PathDatasource            = "datasources"
+	PathEphemeralDashboard    = "ephemeraldashboards"
+	PathFolder                = "folders"
+	PathGlobalDatasource      = "globaldatasources"
+	PathGlobalRole            = "globalroles"
+	PathGlobalRoleBinding     = "globalrolebindings"
+	PathGlobalSecret          = "globalsecrets"
+	PathGlobalVariable        = "globalvariables"
+	PathHistory               = "history"
+	PathProject               = "projects"
+	PathRole                  = "roles"
+	PathRoleBinding           = "rolebindings"
+	PathSecret                = "secrets"
+	PathStats                 = "stats"
+	PathTag                   = "tags"
+	PathTemplatePolicy        = "templatepolicies"
+	PathTemplatePolicyBinding = "templatepolicybindings"
+	PathTrash                 = "trash"
+	PathUnsaved               = "unsaved"
+	PathUser                  = "users"
+	PathCurrentUser           = "user"
+	PathVariable              = "variables"
+	PathView                  = "view"
+	PathWhoAmI                = "whoami"
+	PathFavorite              = "favorites"
+	ContextKeyAnonymous       = "anonymous"
 )
 
 const MetricNamespace = "perses"
 
 // ProjectResourcePathList is containing the list of the resource path that is part of a project.
 var ProjectResourcePathList = []string{
-	PathDashboard, PathDatasource, PathFolder, PathRole, PathRoleBinding, PathSecret, PathVariable,
+	PathAPIKey, PathDashboard, PathDashboardTemplate, PathDatasource, PathFolder, PathRole, PathRoleBinding, PathSecret, PathVariable,
 }
 
 func GetNameParameter(ctx echo.Context) string {
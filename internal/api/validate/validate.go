@@ -108,10 +108,43 @@ func validateVariableNames(variables []dashboard.Variable) error {
 	return nil
 }
 
+// validatePanelGroups verifies the panels referenced by the groups, i.e. the grid layouts of the dashboard, exist
+// and are referenced once per group. A panel can still be displayed in several groups.
+func validatePanelGroups(spec dashboard.Spec) error {
+	for i, layout := range spec.Layouts {
+		gridSpec, ok := layout.Spec.(*dashboard.GridLayoutSpec)
+		if !ok {
+			continue
+		}
+		group := fmt.Sprintf("%d", i)
+		if gridSpec.Display != nil && len(gridSpec.Display.Title) > 0 {
+			group = fmt.Sprintf("%q", gridSpec.Display.Title)
+		}
+		panels := make(map[string]bool, len(gridSpec.Items))
+		for _, item := range gridSpec.Items {
+			if item.Content == nil || len(item.Content.Path) == 0 {
+				return fmt.Errorf("group %s has an item without panel reference", group)
+			}
+			name := item.Content.Path[len(item.Content.Path)-1]
+			if _, exists := spec.Panels[name]; !exists {
+				return fmt.Errorf("group %s references the panel %q that doesn't exist", group, name)
+			}
+			if panels[name] {
+				return fmt.Errorf("group %s references the panel %q several times", group, name)
+			}
+			panels[name] = true
+		}
+	}
+	return nil
+}
+
 func validateDatasourcePlugin(plugin common.Plugin, name string, sch schema.Schema) error {
 	if _, _, err := datasource.ValidateAndExtract(plugin.Spec); err != nil {
 		return err
 	}
+	if _, err := datasource.ExtractURLTemplate(plugin.Spec); err != nil {
+		return err
+	}
 	return sch.ValidateDatasource(plugin, name)
 }
 
@@ -119,6 +152,9 @@ func validateDashboardSpec(spec dashboard.Spec, sch schema.Schema) error {
 	if err := validateVariableNames(spec.Variables); err != nil {
 		return err
 	}
+	if err := validatePanelGroups(spec); err != nil {
+		return err
+	}
 
 	if sch != nil {
 		if err := sch.ValidateDashboardVariables(spec.Variables); err != nil {
@@ -21,6 +21,9 @@ import (
 	testUtils "github.com/perses/perses/internal/test"
 	"github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	commonSpec "github.com/perses/spec/go/common"
+	"github.com/perses/spec/go/dashboard"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,7 +46,7 @@ func TestDashboardSpec(t *testing.T) {
 	projectPath := testUtils.GetRepositoryPath()
 
 	pl := plugin.New(config.Plugin{
-		Path:        filepath.Join(projectPath, config.DefaultPluginPath),
+		Path:        common.NonEmptyString(filepath.Join(projectPath, config.DefaultPluginPath)),
 		ArchivePath: filepath.Join(projectPath, config.DefaultArchivePluginPath),
 	})
 	if err := pl.UnzipArchives(); err != nil {
@@ -69,6 +72,60 @@ func TestDashboardSpec(t *testing.T) {
 	}
 }
 
+func TestValidatePanelGroups(t *testing.T) {
+	grid := func(title string, panels ...string) dashboard.Layout {
+		spec := &dashboard.GridLayoutSpec{}
+		if len(title) > 0 {
+			spec.Display = &dashboard.GridLayoutDisplay{Title: title}
+		}
+		for _, panel := range panels {
+			spec.Items = append(spec.Items, dashboard.GridItem{
+				Content: &commonSpec.JSONRef{Ref: "#/spec/panels/" + panel, Path: []string{"spec", "panels", panel}},
+			})
+		}
+		return dashboard.Layout{Kind: dashboard.KindGridLayout, Spec: spec}
+	}
+	panels := map[string]*dashboard.Panel{"cpu": {}, "load": {}, "memory": {}}
+	testSuite := []struct {
+		title   string
+		layouts []dashboard.Layout
+		err     string
+	}{
+		{
+			title:   "valid groups",
+			layouts: []dashboard.Layout{grid("CPU", "cpu", "load"), grid("Memory", "memory")},
+		},
+		{
+			title:   "empty group",
+			layouts: []dashboard.Layout{grid("Network")},
+		},
+		{
+			title:   "panel displayed in two groups",
+			layouts: []dashboard.Layout{grid("CPU", "cpu"), grid("Overview", "cpu", "memory")},
+		},
+		{
+			title:   "unknown panel",
+			layouts: []dashboard.Layout{grid("CPU", "cpu"), grid("", "disk")},
+			err:     `group 1 references the panel "disk" that doesn't exist`,
+		},
+		{
+			title:   "panel twice in a group",
+			layouts: []dashboard.Layout{grid("CPU", "cpu", "cpu")},
+			err:     `group "CPU" references the panel "cpu" several times`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := validatePanelGroups(dashboard.Spec{Panels: panels, Layouts: test.layouts})
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestDatasource(t *testing.T) {
 
 	testSuite := []struct {
@@ -91,7 +148,7 @@ func TestDatasource(t *testing.T) {
 	projectPath := testUtils.GetRepositoryPath()
 
 	pl := plugin.New(config.Plugin{
-		Path:        filepath.Join(projectPath, config.DefaultPluginPath),
+		Path:        common.NonEmptyString(filepath.Join(projectPath, config.DefaultPluginPath)),
 		ArchivePath: filepath.Join(projectPath, config.DefaultArchivePluginPath),
 	})
 	if err := pl.UnzipArchives(); err != nil {
@@ -0,0 +1,216 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup defines the archive produced by the command `percli backup` and read by the command `percli restore`.
+// The archive is a gzipped tarball containing JSON files, and a manifest holding the checksum of each of them.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/perses/perses/internal/api/utils"
+)
+
+const (
+	ManifestFile = "manifest.json"
+	ConfigFile   = "config.json"
+	PluginsFile  = "plugins.json"
+	ProjectsFile = "resources/projects.json"
+	// maxFileSize protects against an archive claiming to contain a huge file.
+	maxFileSize = 512 << 20
+)
+
+// GlobalResources are the global resources saved in the archive, in the order they must be restored.
+// The users and the secrets are not part of it, since the API doesn't return their sensitive data.
+var GlobalResources = []string{
+	utils.PathGlobalDatasource,
+	utils.PathGlobalVariable,
+	utils.PathGlobalRole,
+	utils.PathGlobalRoleBinding,
+}
+
+// ProjectResources are the resources of a project saved in the archive, in the order they must be restored.
+var ProjectResources = []string{
+	utils.PathDatasource,
+	utils.PathVariable,
+	utils.PathDashboard,
+	utils.PathFolder,
+	utils.PathRole,
+	utils.PathRoleBinding,
+}
+
+func GlobalResourceFile(resource string) string {
+	return path.Join("resources", "global", resource+".json")
+}
+
+func ProjectResourceFile(project, resource string) string {
+	return path.Join("resources", "projects", project, resource+".json")
+}
+
+type Manifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Files associates the path of every file of the archive, except the manifest, to its SHA-256 checksum.
+	Files map[string]string `json:"files"`
+}
+
+type Archive struct {
+	Manifest Manifest
+	Files    map[string][]byte
+}
+
+func New(createdAt time.Time) *Archive {
+	return &Archive{
+		Manifest: Manifest{
+			CreatedAt: createdAt,
+			Files:     make(map[string]string),
+		},
+		Files: make(map[string][]byte),
+	}
+}
+
+func (a *Archive) Add(name string, data []byte) {
+	a.Files[name] = data
+	a.Manifest.Files[name] = checksum(data)
+}
+
+// Get returns the content of the file, or nil if the archive doesn't contain it.
+func (a *Archive) Get(name string) []byte {
+	return a.Files[name]
+}
+
+// Write writes the archive, starting with its manifest.
+func (a *Archive) Write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(a.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if writeErr := writeFile(tarWriter, ManifestFile, manifest, a.Manifest.CreatedAt); writeErr != nil {
+		return writeErr
+	}
+	names := make([]string, 0, len(a.Files))
+	for name := range a.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if writeErr := writeFile(tarWriter, name, a.Files[name], a.Manifest.CreatedAt); writeErr != nil {
+			return writeErr
+		}
+	}
+	if closeErr := tarWriter.Close(); closeErr != nil {
+		return closeErr
+	}
+	return gzipWriter.Close()
+}
+
+// Read reads the archive and verifies its integrity: every file must be listed in the manifest with the right checksum,
+// and every file listed in the manifest must be present.
+func Read(r io.Reader) (*Archive, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the archive is not a gzip file: %w", err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	files := make(map[string][]byte)
+	for {
+		header, nextErr := tarReader.Next()
+		if errors.Is(nextErr, io.EOF) {
+			break
+		}
+		if nextErr != nil {
+			return nil, fmt.Errorf("unable to read the archive: %w", nextErr)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxFileSize {
+			return nil, fmt.Errorf("the file %q of the archive is too big", header.Name)
+		}
+		data, readErr := io.ReadAll(io.LimitReader(tarReader, maxFileSize))
+		if readErr != nil {
+			return nil, fmt.Errorf("unable to read the file %q of the archive: %w", header.Name, readErr)
+		}
+		files[header.Name] = data
+	}
+	rawManifest, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("the archive doesn't contain the file %s", ManifestFile)
+	}
+	delete(files, ManifestFile)
+	var manifest Manifest
+	if unmarshalErr := json.Unmarshal(rawManifest, &manifest); unmarshalErr != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, unmarshalErr)
+	}
+	for name, data := range files {
+		expected, listed := manifest.Files[name]
+		if !listed {
+			return nil, fmt.Errorf("the file %q is not listed in the manifest of the archive", name)
+		}
+		if checksum(data) != expected {
+			return nil, fmt.Errorf("the checksum of the file %q doesn't match the one in the manifest, the archive is corrupted", name)
+		}
+	}
+	for name := range manifest.Files {
+		if _, present := files[name]; !present {
+			return nil, fmt.Errorf("the file %q listed in the manifest is missing from the archive", name)
+		}
+	}
+	return &Archive{Manifest: manifest, Files: files}, nil
+}
+
+func writeFile(w *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := w.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// ResourceName returns the name of a resource saved in the archive.
+func ResourceName(raw json.RawMessage) (string, error) {
+	var resource struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return "", err
+	}
+	if len(resource.Metadata.Name) == 0 {
+		return "", fmt.Errorf("resource without name")
+	}
+	return resource.Metadata.Name, nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
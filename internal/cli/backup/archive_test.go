@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive := New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	archive.Add(ProjectsFile, []byte(`[{"kind":"Project","metadata":{"name":"perses"}}]`))
+	archive.Add(ProjectResourceFile("perses", "dashboards"), []byte(`[]`))
+	buffer := &bytes.Buffer{}
+	require.NoError(t, archive.Write(buffer))
+
+	read, err := Read(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, archive, read)
+}
+
+func TestReadCorruptedArchive(t *testing.T) {
+	testSuite := []struct {
+		title         string
+		tamper        func(a *Archive)
+		expectedError string
+	}{
+		{
+			title: "content modified",
+			tamper: func(a *Archive) {
+				a.Files[ProjectsFile] = []byte(`[]`)
+			},
+			expectedError: `the checksum of the file "resources/projects.json" doesn't match the one in the manifest, the archive is corrupted`,
+		},
+		{
+			title: "file missing",
+			tamper: func(a *Archive) {
+				delete(a.Files, ProjectsFile)
+			},
+			expectedError: `the file "resources/projects.json" listed in the manifest is missing from the archive`,
+		},
+		{
+			title: "file added",
+			tamper: func(a *Archive) {
+				a.Files[ConfigFile] = []byte(`{}`)
+			},
+			expectedError: `the file "config.json" is not listed in the manifest of the archive`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			archive := New(time.Now())
+			archive.Add(ProjectsFile, []byte(`[{"kind":"Project","metadata":{"name":"perses"}}]`))
+			test.tamper(archive)
+			buffer := &bytes.Buffer{}
+			require.NoError(t, archive.Write(buffer))
+			_, err := Read(buffer)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+
+	_, err := Read(bytes.NewBufferString("not an archive"))
+	assert.Error(t, err)
+}
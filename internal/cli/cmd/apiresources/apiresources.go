@@ -0,0 +1,150 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiresources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/internal/cli/resource"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	wideOutput  = "wide"
+	apiV1Prefix = "/api/v1"
+)
+
+// verbs is the list of actions the API supports for every resource.
+var verbs = []string{"create", "get", "list", "update", "delete"}
+
+type apiResource struct {
+	Kind        modelV1.Kind    `json:"kind" yaml:"kind"`
+	ShortName   string          `json:"shortName,omitempty" yaml:"shortName,omitempty"`
+	Namespaced  bool            `json:"namespaced" yaml:"namespaced"`
+	APIEndpoint string          `json:"apiEndpoint" yaml:"apiEndpoint"`
+	Verbs       []string        `json:"verbs" yaml:"verbs"`
+	Example     json.RawMessage `json:"-" yaml:"-"`
+}
+
+func newAPIResource(kind modelV1.Kind) apiResource {
+	namespaced := !modelV1.IsGlobal(kind)
+	endpoint := fmt.Sprintf("%s/%s", apiV1Prefix, modelV1.PluralKindMap[kind])
+	metadata := map[string]string{"name": "<name>"}
+	if namespaced {
+		endpoint = fmt.Sprintf("%s/projects/{project}/%s", apiV1Prefix, modelV1.PluralKindMap[kind])
+		metadata["project"] = "<project>"
+	}
+	example, _ := json.Marshal(map[string]any{
+		"kind":     kind,
+		"metadata": metadata,
+		"spec":     map[string]any{},
+	})
+	return apiResource{
+		Kind:        kind,
+		ShortName:   resource.GetShortTerm(kind),
+		Namespaced:  namespaced,
+		APIEndpoint: endpoint,
+		Verbs:       verbs,
+		Example:     example,
+	}
+}
+
+type option struct {
+	persesCMD.Option
+	writer    io.Writer
+	errWriter io.Writer
+	output    string
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'api-resources'")
+	}
+	return nil
+}
+
+func (o *option) Validate() error {
+	switch o.output {
+	case "", wideOutput, output.JSONOutput, output.YAMLOutput:
+		return nil
+	default:
+		return fmt.Errorf("--output must be %q, %q or %q", wideOutput, output.JSONOutput, output.YAMLOutput)
+	}
+}
+
+func (o *option) Execute() error {
+	var list []apiResource
+	for _, kind := range resource.Kinds() {
+		list = append(list, newAPIResource(kind))
+	}
+	if o.output == output.JSONOutput || o.output == output.YAMLOutput {
+		return output.Handle(o.writer, o.output, list)
+	}
+	column := []string{"KIND", "SHORTNAME", "NAMESPACED", "APIENDPOINT", "VERBS"}
+	if o.output == wideOutput {
+		column = append(column, "EXAMPLE")
+	}
+	data := make([][]string, 0, len(list))
+	for _, res := range list {
+		line := []string{
+			string(res.Kind),
+			res.ShortName,
+			strconv.FormatBool(res.Namespaced),
+			res.APIEndpoint,
+			strings.Join(res.Verbs, ","),
+		}
+		if o.output == wideOutput {
+			line = append(line, string(res.Example))
+		}
+		data = append(data, line)
+	}
+	return output.HandlerTable(o.writer, column, data)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "api-resources",
+		Short: "Print the resource types supported by the API.",
+		Long: `Print the resource types supported by the API.
+For each of them, it displays if the resource is scoped to a project, the API endpoint and the actions available.`,
+		Example: `
+# Print the supported resource types
+percli api-resources
+
+# Print the supported resource types with an example of their JSON structure
+percli api-resources -o wide
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Format of the output: wide, json or yaml (default is a table).")
+	return cmd
+}
@@ -0,0 +1,69 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiresources
+
+import (
+	"bytes"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIResourcesCMD(t *testing.T) {
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "args not supported",
+			Args:            []string{"dashboard"},
+			IsErrorExpected: true,
+			ExpectedMessage: "no args are supported by the command 'api-resources'",
+		},
+		{
+			Title:           "output not supported",
+			Args:            []string{"-o", "xml"},
+			IsErrorExpected: true,
+			ExpectedMessage: `--output must be "wide", "json" or "yaml"`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
+
+func TestAPIResourcesCMD_AllKinds(t *testing.T) {
+	for _, args := range [][]string{{}, {"-o", "wide"}, {"-o", "json"}} {
+		buffer := bytes.NewBufferString("")
+		cmd := NewCMD()
+		cmd.SetOut(buffer)
+		cmd.SetErr(buffer)
+		cmd.SetArgs(args)
+		if assert.NoError(t, cmd.Execute()) {
+			for kind, plural := range modelV1.PluralKindMap {
+				assert.Contains(t, buffer.String(), string(kind))
+				assert.Contains(t, buffer.String(), "/"+plural)
+			}
+		}
+	}
+}
+
+func TestNewAPIResource(t *testing.T) {
+	dashboard := newAPIResource(modelV1.KindDashboard)
+	assert.True(t, dashboard.Namespaced)
+	assert.Equal(t, "/api/v1/projects/{project}/dashboards", dashboard.APIEndpoint)
+	assert.JSONEq(t, `{"kind":"Dashboard","metadata":{"name":"<name>","project":"<project>"},"spec":{}}`, string(dashboard.Example))
+
+	project := newAPIResource(modelV1.KindProject)
+	assert.False(t, project.Namespaced)
+	assert.Equal(t, "/api/v1/projects", project.APIEndpoint)
+	assert.JSONEq(t, `{"kind":"Project","metadata":{"name":"<name>"},"spec":{}}`, string(project.Example))
+}
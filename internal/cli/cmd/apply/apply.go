@@ -40,10 +40,14 @@ type option struct {
 	opt.DirectoryOption
 	forceCreate   bool
 	createProject bool
+	source        string
+	extStr        []string
 	writer        io.Writer
 	errWriter     io.Writer
 	apiClient     api.ClientInterface
 	entities      []modelAPI.Entity
+	// evaluate returns the JSON produced by the Jsonnet file. It is evaluateJsonnet, unless replaced by the tests.
+	evaluate func(file string, extStr []string) ([]byte, error)
 }
 
 func (o *option) Complete(args []string) error {
@@ -53,6 +57,21 @@ func (o *option) Complete(args []string) error {
 	if len(o.Directory) == 0 && len(o.File) == 0 {
 		return fmt.Errorf("you need to set the flag --directory or --file for this command")
 	}
+	switch o.source {
+	case sourceFile:
+		if len(o.extStr) > 0 {
+			return fmt.Errorf("the flag --ext-str can only be used with --source %s", sourceJsonnet)
+		}
+	case sourceJsonnet:
+		if len(o.File) == 0 {
+			return fmt.Errorf("--source %s requires the flag --file", sourceJsonnet)
+		}
+		if err := validateExtStr(o.extStr); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid source %q, it must be either %q or %q", o.source, sourceFile, sourceJsonnet)
+	}
 
 	// Then, if no particular project has been specified through a flag, let's grab the one defined in the CLI config.
 	// In this particular case, we don't use the Complete method of the ProjectOption because
@@ -97,7 +116,11 @@ func (o *option) Execute() error {
 
 func (o *option) setEntities() error {
 	var err error
-	o.entities, err = file.UnmarshalEntities(o.File, o.Directory)
+	if o.source == sourceJsonnet {
+		o.entities, err = o.unmarshalJsonnetDashboards()
+	} else {
+		o.entities, err = file.UnmarshalEntities(o.File, o.Directory)
+	}
 	if err != nil {
 		return err
 	}
@@ -107,6 +130,24 @@ func (o *option) setEntities() error {
 	return nil
 }
 
+// unmarshalJsonnetDashboards evaluates the Jsonnet file, which must produce one or a list of Perses dashboards.
+func (o *option) unmarshalJsonnetDashboards() ([]modelAPI.Entity, error) {
+	data, err := o.evaluate(o.File, o.extStr)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := file.UnmarshalEntitiesFromData(o.File, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if kind := modelV1.Kind(entity.GetKind()); kind != modelV1.KindDashboard {
+			return nil, fmt.Errorf("the Jsonnet file %q must produce dashboards only, got %q %q", o.File, kind, entity.GetMetadata().GetName())
+		}
+	}
+	return entities, nil
+}
+
 func (o *option) applyEntity() error {
 	for _, entity := range o.entities {
 		kind := modelV1.Kind(entity.GetKind())
@@ -182,10 +223,10 @@ func (o *option) SetErrWriter(errWriter io.Writer) {
 }
 
 func NewCMD() *cobra.Command {
-	o := &option{}
+	o := &option{evaluate: evaluateJsonnet}
 	cmd := &cobra.Command{
 		Use:   "apply (-f [FILENAME] | -d [DIRECTORY_NAME])",
-		Short: "Create or update resources through a file. JSON or YAML format supported",
+		Short: "Create or update resources through a file. JSON, YAML or Jsonnet format supported",
 		Example: `
 # Create/update the resources from the file resources.json to the remote Perses server.
 percli apply -f ./resources.json
@@ -195,6 +236,9 @@ percli apply -d ./
 
 # Apply the JSON passed into stdin to the remote Perses server.
 cat ./resources.json | percli apply -f -
+
+# Evaluate a Jsonnet file producing Perses dashboards (the jsonnet CLI must be installed) and apply the result.
+percli apply --source jsonnet -f ./dashboards.jsonnet --ext-str cluster=prod
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return persesCMD.Run(o, cmd, args)
@@ -205,6 +249,8 @@ cat ./resources.json | percli apply -f -
 	opt.AddDirectoryFlags(cmd, &o.DirectoryOption)
 	opt.MarkFileAndDirFlagsAsXOR(cmd)
 	cmd.Flags().BoolVarP(&o.forceCreate, "force", "", false, "If present, the command creates the resource even if the projects are not consistent - it prioritizes the JSON file")
+	cmd.Flags().StringVar(&o.source, "source", sourceFile, fmt.Sprintf("Format of the file. Use %q to evaluate a Jsonnet file producing Perses dashboards", sourceJsonnet))
+	cmd.Flags().StringArrayVar(&o.extStr, "ext-str", nil, "External variable passed to the Jsonnet file, in the format key=value. Can be repeated")
 	cmd.Flags().BoolVar(&o.createProject, "create-project", false, "If present, the command creates the resolved target project when a resource apply fails because the project does not exist, then retries once")
 	return cmd
 }
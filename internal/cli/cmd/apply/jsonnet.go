@@ -0,0 +1,66 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	sourceFile    = "file"
+	sourceJsonnet = "jsonnet"
+)
+
+func validateExtStr(extStr []string) error {
+	for _, v := range extStr {
+		key, _, found := strings.Cut(v, "=")
+		if !found || len(key) == 0 {
+			return fmt.Errorf("invalid external variable %q, it must be in the format key=value", v)
+		}
+	}
+	return nil
+}
+
+// jsonnetArgs returns the arguments of the jsonnet CLI evaluating the file with the external variables.
+func jsonnetArgs(file string, extStr []string) []string {
+	args := make([]string, 0, 2*len(extStr)+1)
+	for _, v := range extStr {
+		args = append(args, "--ext-str", v)
+	}
+	return append(args, file)
+}
+
+// evaluateJsonnet returns the JSON produced by the Jsonnet file.
+// NB: like the `dac build` command does with cue, the evaluation is delegated to the jsonnet CLI that must be installed.
+func evaluateJsonnet(file string, extStr []string) ([]byte, error) {
+	// #nosec is needed as the file and the external variables are provided by the user
+	cmd := exec.Command("jsonnet", jsonnetArgs(file, extStr)...) // #nosec
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, jsonnetError(file, err)
+	}
+	return data, nil
+}
+
+// jsonnetError returns the error printed by the jsonnet CLI when the evaluation failed.
+func jsonnetError(file string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to evaluate %s: %s", file, string(exitErr.Stderr))
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/perses/perses/internal/cli/opt"
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	fakeapi "github.com/perses/perses/pkg/client/fake/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skipWithoutJsonnet skips the tests evaluating the Jsonnet files with the jsonnet CLI, when it is not installed.
+// The other tests replace the CLI by fakeJsonnet.
+func skipWithoutJsonnet(t *testing.T) {
+	if _, err := exec.LookPath("jsonnet"); err != nil {
+		t.Skip("the jsonnet CLI is not installed")
+	}
+}
+
+func TestApplyCMD_JsonnetFlags(t *testing.T) {
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "unknown source",
+			Args:            []string{"-f", "dashboards.libsonnet", "--source", "cue"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: `invalid source "cue", it must be either "file" or "jsonnet"`,
+		},
+		{
+			Title:           "external variable without jsonnet",
+			Args:            []string{"-f", "../../test/sample_resources/single_resource.json", "--ext-str", "project=perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: "the flag --ext-str can only be used with --source jsonnet",
+		},
+		{
+			Title:           "jsonnet with a directory",
+			Args:            []string{"-d", "testdata/jsonnet", "--source", "jsonnet"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: "--source jsonnet requires the flag --file",
+		},
+		{
+			Title:           "invalid external variable",
+			Args:            []string{"-f", "testdata/jsonnet/dashboards.jsonnet", "--source", "jsonnet", "--ext-str", "perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: `invalid external variable "perses", it must be in the format key=value`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
+
+func TestApplyCMD_Jsonnet(t *testing.T) {
+	skipWithoutJsonnet(t)
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "apply the dashboards of a mixin",
+			Args:            []string{"-f", "testdata/jsonnet/dashboards.jsonnet", "--source", "jsonnet", "--ext-str", "project=perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: false,
+			ExpectedMessage: `object "Dashboard" "node-cpu" has been applied in the project "perses"
+object "Dashboard" "node-overview" has been applied in the project "perses"
+`,
+		},
+		{
+			Title:                "missing external variable",
+			Args:                 []string{"-f", "testdata/jsonnet/dashboards.jsonnet", "--source", "jsonnet"},
+			APIClient:            fakeapi.New(),
+			IsErrorExpected:      true,
+			ExpectedRegexMessage: `failed to evaluate testdata/jsonnet/dashboards.jsonnet: .*Undefined external variable: project`,
+		},
+		{
+			Title:           "jsonnet producing something else than a dashboard",
+			Args:            []string{"-f", "testdata/jsonnet/not_a_dashboard.jsonnet", "--source", "jsonnet", "--ext-str", "project=perses"},
+			APIClient:       fakeapi.New(),
+			IsErrorExpected: true,
+			ExpectedMessage: `the Jsonnet file "testdata/jsonnet/not_a_dashboard.jsonnet" must produce dashboards only, got "Folder" "node"`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
+
+// fakeJsonnet returns an evaluator producing the content of the output file, as if it was the result of the
+// evaluation, so the tests don't need the jsonnet CLI.
+func fakeJsonnet(t *testing.T, output string, expectedExtStr []string) func(string, []string) ([]byte, error) {
+	return func(_ string, extStr []string) ([]byte, error) {
+		assert.Equal(t, expectedExtStr, extStr)
+		return os.ReadFile(filepath.Join("testdata", "jsonnet", output))
+	}
+}
+
+func TestUnmarshalJsonnetDashboards(t *testing.T) {
+	o := &option{
+		FileOption: opt.FileOption{File: filepath.Join("testdata", "jsonnet", "dashboards.jsonnet")},
+		source:     sourceJsonnet,
+		extStr:     []string{"project=perses"},
+		evaluate:   fakeJsonnet(t, "dashboards.json", []string{"project=perses"}),
+	}
+	entities, err := o.unmarshalJsonnetDashboards()
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+
+	cpu, ok := entities[0].(*modelV1.Dashboard)
+	require.True(t, ok)
+	assert.Equal(t, "node-cpu", cpu.Metadata.Name)
+	assert.Equal(t, "perses", cpu.Metadata.Project)
+	assert.Equal(t, "Node CPU", cpu.Spec.Display.Name)
+	assert.Equal(t, `CPU usage of job="node"`, cpu.Spec.Display.Description)
+	assert.Equal(t, "6h", string(cpu.Spec.Duration))
+
+	overview, ok := entities[1].(*modelV1.Dashboard)
+	require.True(t, ok)
+	assert.Equal(t, "node-overview", overview.Metadata.Name)
+	assert.Equal(t, "Node Overview", overview.Spec.Display.Name)
+}
+
+func TestUnmarshalJsonnetDashboards_Errors(t *testing.T) {
+	file := filepath.Join("testdata", "jsonnet", "not_a_dashboard.jsonnet")
+	o := &option{
+		FileOption: opt.FileOption{File: file},
+		source:     sourceJsonnet,
+		evaluate: func(_ string, _ []string) ([]byte, error) {
+			return []byte(`{"kind": "Folder", "metadata": {"name": "node", "project": "perses"}, "spec": {"items": []}}`), nil
+		},
+	}
+	_, err := o.unmarshalJsonnetDashboards()
+	assert.EqualError(t, err, `the Jsonnet file "testdata/jsonnet/not_a_dashboard.jsonnet" must produce dashboards only, got "Folder" "node"`)
+
+	o.evaluate = func(file string, _ []string) ([]byte, error) {
+		return nil, jsonnetError(file, &exec.ExitError{Stderr: []byte("RUNTIME ERROR: Undefined external variable: project")})
+	}
+	_, err = o.unmarshalJsonnetDashboards()
+	assert.EqualError(t, err, "failed to evaluate testdata/jsonnet/not_a_dashboard.jsonnet: RUNTIME ERROR: Undefined external variable: project")
+}
+
+func TestJsonnetArgs(t *testing.T) {
+	assert.Equal(t, []string{"dashboards.jsonnet"}, jsonnetArgs("dashboards.jsonnet", nil))
+	assert.Equal(t,
+		[]string{"--ext-str", "project=perses", "--ext-str", "cluster=prod=eu", "dashboards.jsonnet"},
+		jsonnetArgs("dashboards.jsonnet", []string{"project=perses", "cluster=prod=eu"}),
+	)
+}
+
+func TestJsonnetError(t *testing.T) {
+	err := jsonnetError("dashboards.jsonnet", &exec.ExitError{Stderr: []byte("STATIC ERROR: dashboards.jsonnet:1:7: unexpected end of file.")})
+	assert.EqualError(t, err, "failed to evaluate dashboards.jsonnet: STATIC ERROR: dashboards.jsonnet:1:7: unexpected end of file.")
+	assert.Equal(t, exec.ErrNotFound, jsonnetError("dashboards.jsonnet", exec.ErrNotFound))
+}
+
+// TestEvaluateJsonnet verifies the output used by fakeJsonnet is the one of the jsonnet CLI.
+func TestEvaluateJsonnet(t *testing.T) {
+	skipWithoutJsonnet(t)
+	data, err := evaluateJsonnet(filepath.Join("testdata", "jsonnet", "dashboards.jsonnet"), []string{"project=perses"})
+	require.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "jsonnet", "dashboards.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(data))
+}
@@ -0,0 +1,41 @@
+// A mixin in the style of the Prometheus monitoring mixins, exposing its dashboards as Perses resources.
+{
+  _config+:: {
+    nodeExporterSelector: 'job="node"',
+    project: std.extVar('project'),
+  },
+
+  persesDashboards+:: {
+    'node-overview.json': {
+      kind: 'Dashboard',
+      metadata: {
+        name: 'node-overview',
+        project: $._config.project,
+      },
+      spec: {
+        display: {
+          name: 'Node Overview',
+        },
+        duration: '1h',
+        panels: {},
+        layouts: [],
+      },
+    },
+    'node-cpu.json': {
+      kind: 'Dashboard',
+      metadata: {
+        name: 'node-cpu',
+        project: $._config.project,
+      },
+      spec: {
+        display: {
+          name: 'Node CPU',
+          description: 'CPU usage of %s' % $._config.nodeExporterSelector,
+        },
+        duration: '6h',
+        panels: {},
+        layouts: [],
+      },
+    },
+  },
+}
@@ -0,0 +1,178 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/internal/cli/backup"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/perseshttp"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	persesCMD.Option
+	writer    io.Writer
+	errWriter io.Writer
+	output    string
+	apiClient api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'backup'")
+	}
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.output) == 0 {
+		return fmt.Errorf("you need to set the flag --output for this command")
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	archive := backup.New(time.Now().UTC())
+	projects, err := o.list("", utils.PathProject)
+	if err != nil {
+		return fmt.Errorf("unable to list the projects: %w", err)
+	}
+	if addErr := addList(archive, backup.ProjectsFile, projects); addErr != nil {
+		return addErr
+	}
+	for _, resource := range backup.GlobalResources {
+		if addErr := o.addResources(archive, "", resource, backup.GlobalResourceFile(resource)); addErr != nil {
+			return addErr
+		}
+	}
+	for _, rawProject := range projects {
+		name, nameErr := backup.ResourceName(rawProject)
+		if nameErr != nil {
+			return nameErr
+		}
+		for _, resource := range backup.ProjectResources {
+			if addErr := o.addResources(archive, name, resource, backup.ProjectResourceFile(name, resource)); addErr != nil {
+				return addErr
+			}
+		}
+	}
+	// The configuration and the plugins are saved for reference, they are not restored.
+	// The secrets of the configuration are already hidden by the API.
+	if addErr := o.addRaw(archive, backup.ConfigFile, o.apiClient.RESTClient().Get().APIVersion("").Resource("config")); addErr != nil {
+		return addErr
+	}
+	if addErr := o.addRaw(archive, backup.PluginsFile, o.apiClient.RESTClient().Get().Resource("plugins")); addErr != nil {
+		return addErr
+	}
+
+	f, err := os.Create(o.output)
+	if err != nil {
+		return err
+	}
+	if writeErr := archive.Write(f); writeErr != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write the archive: %w", writeErr)
+	}
+	if closeErr := f.Close(); closeErr != nil {
+		return closeErr
+	}
+	return output.HandleString(o.writer, fmt.Sprintf("backup of %d project(s) saved in %s", len(projects), o.output))
+}
+
+func (o *option) list(project, resource string) ([]json.RawMessage, error) {
+	var result []json.RawMessage
+	err := o.apiClient.RESTClient().Get().Project(project).Resource(resource).Do().Object(&result)
+	return result, err
+}
+
+// addResources saves the list of the given resource. A resource that is not served by the API, such as the roles when
+// the native authorization is disabled, is skipped.
+func (o *option) addResources(archive *backup.Archive, project, resource, fileName string) error {
+	list, err := o.list(project, resource)
+	if errors.Is(err, perseshttp.RequestNotFoundError) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to list the %s of the project %q: %w", resource, project, err)
+	}
+	return addList(archive, fileName, list)
+}
+
+func (o *option) addRaw(archive *backup.Archive, fileName string, req *perseshttp.Request) error {
+	var data json.RawMessage
+	err := req.Do().Object(&data)
+	if errors.Is(err, perseshttp.RequestNotFoundError) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the content of %s: %w", fileName, err)
+	}
+	archive.Add(fileName, data)
+	return nil
+}
+
+func addList(archive *backup.Archive, fileName string, list []json.RawMessage) error {
+	if list == nil {
+		list = []json.RawMessage{}
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	archive.Add(fileName, data)
+	return nil
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Save the projects, their resources and the global resources in an archive",
+		Long: `Save the projects, their resources and the global resources in a tar.gz archive that can be restored with the command 'restore'.
+The users and the secrets are not saved, as the API doesn't return their sensitive data.
+The configuration of the server and the list of the plugins are saved for reference.`,
+		Example: `
+percli backup --output perses-backup.tar.gz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&o.output, "output", o.output, "Path of the archive to create.")
+	return cmd
+}
@@ -0,0 +1,101 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"fmt"
+	"io"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/spf13/cobra"
+)
+
+const (
+	bashShell       = "bash"
+	zshShell        = "zsh"
+	fishShell       = "fish"
+	powershellShell = "powershell"
+)
+
+var supportedShells = []string{bashShell, zshShell, fishShell, powershellShell}
+
+type option struct {
+	persesCMD.Option
+	writer    io.Writer
+	errWriter io.Writer
+	shell     string
+	root      *cobra.Command
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("please specify the shell: %s, %s, %s or %s", bashShell, zshShell, fishShell, powershellShell)
+	}
+	o.shell = args[0]
+	return nil
+}
+
+func (o *option) Validate() error {
+	for _, shell := range supportedShells {
+		if o.shell == shell {
+			return nil
+		}
+	}
+	return fmt.Errorf("shell %q not supported, it must be %s, %s, %s or %s", o.shell, bashShell, zshShell, fishShell, powershellShell)
+}
+
+func (o *option) Execute() error {
+	switch o.shell {
+	case bashShell:
+		return o.root.GenBashCompletionV2(o.writer, true)
+	case zshShell:
+		return o.root.GenZshCompletion(o.writer)
+	case fishShell:
+		return o.root.GenFishCompletion(o.writer, true)
+	default:
+		return o.root.GenPowerShellCompletionWithDesc(o.writer)
+	}
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     "Generate the autocompletion script for the given shell",
+		ValidArgs: supportedShells,
+		Example: `
+# Load the completion in the current bash session.
+source <(percli completion bash)
+
+# Load the completion for every new zsh session.
+percli completion zsh > "${fpath[1]}/_percli"
+
+# Load the completion for every new fish session.
+percli completion fish > ~/.config/fish/completions/percli.fish
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.root = cmd.Root()
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,92 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRootCMD() *cobra.Command {
+	root := &cobra.Command{Use: "percli"}
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.AddCommand(NewCMD())
+	return root
+}
+
+func TestCompletionCMD(t *testing.T) {
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "no shell",
+			Args:            []string{"completion"},
+			IsErrorExpected: true,
+			ExpectedMessage: "please specify the shell: bash, zsh, fish or powershell",
+		},
+		{
+			Title:           "unknown shell",
+			Args:            []string{"completion", "tcsh"},
+			IsErrorExpected: true,
+			ExpectedMessage: `shell "tcsh" not supported, it must be bash, zsh, fish or powershell`,
+		},
+		{
+			Title:                "bash",
+			Args:                 []string{"completion", "bash"},
+			ExpectedRegexMessage: `# bash completion V2 for percli`,
+		},
+		{
+			Title:                "zsh",
+			Args:                 []string{"completion", "zsh"},
+			ExpectedRegexMessage: `#compdef percli`,
+		},
+		{
+			Title:                "fish",
+			Args:                 []string{"completion", "fish"},
+			ExpectedRegexMessage: `# fish completion for percli`,
+		},
+		{
+			Title:                "powershell",
+			Args:                 []string{"completion", "powershell"},
+			ExpectedRegexMessage: `# powershell completion for percli`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, newRootCMD, testSuite)
+}
+
+// TestCompletionScriptSyntax checks the generated scripts are parsed by the shells available on the machine.
+func TestCompletionScriptSyntax(t *testing.T) {
+	for _, shell := range []string{bashShell, zshShell, fishShell} {
+		t.Run(shell, func(t *testing.T) {
+			shellPath, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s is not installed", shell)
+			}
+			script := bytes.NewBufferString("")
+			root := newRootCMD()
+			root.SetOut(script)
+			root.SetArgs([]string{"completion", shell})
+			assert.NoError(t, root.Execute())
+
+			// -n only reads the script, without running it.
+			check := exec.Command(shellPath, "-n")
+			check.Stdin = script
+			output, err := check.CombinedOutput()
+			assert.NoError(t, err, string(output))
+		})
+	}
+}
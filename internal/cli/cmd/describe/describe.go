@@ -102,6 +102,26 @@ func (o *option) SetErrWriter(errWriter io.Writer) {
 	o.errWriter = errWriter
 }
 
+// completeName completes the name of the resource once its type is given.
+func completeName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	kind, err := resource.GetKind(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	switch kind {
+	case modelV1.KindDashboard:
+		return opt.CompleteDashboards(cmd, args, toComplete)
+	case modelV1.KindDatasource:
+		return opt.CompleteDatasources(cmd, args, toComplete)
+	case modelV1.KindProject:
+		return opt.CompleteProjects(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
 func NewCMD() *cobra.Command {
 	o := &option{}
 	cmd := &cobra.Command{
@@ -117,6 +137,7 @@ percli describe dashboard nodeExporter -ojson
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return persesCMD.Run(o, cmd, args)
 		},
+		ValidArgsFunction: completeName,
 	}
 	opt.AddOutputFlags(cmd, &o.OutputOption)
 	opt.AddProjectFlags(cmd, &o.ProjectOption)
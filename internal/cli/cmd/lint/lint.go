@@ -29,6 +29,7 @@ import (
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/spf13/cobra"
 )
 
@@ -53,7 +54,7 @@ func (o *option) Complete(args []string) error {
 	if len(o.pluginPath) > 0 {
 		pl := plugin.New(apiConfig.Plugin{
 			ArchivePath: o.pluginPath,
-			Path:        o.pluginPath,
+			Path:        modelCommon.NonEmptyString(o.pluginPath),
 		})
 		if err := pl.UnzipArchives(); err != nil {
 			return err
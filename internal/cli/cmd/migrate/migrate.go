@@ -30,6 +30,7 @@ import (
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/spec/go/dashboard"
 	"github.com/spf13/cobra"
 )
@@ -97,7 +98,7 @@ func (o *option) Complete(args []string) error {
 	o.completeInput()
 	if len(o.pluginPath) > 0 {
 		pl := plugin.New(apiConfig.Plugin{
-			Path: o.pluginPath,
+			Path: modelCommon.NonEmptyString(o.pluginPath),
 		})
 		if err := pl.Load(); err != nil {
 			return err
@@ -35,6 +35,7 @@ import (
 	persesCMD "github.com/perses/perses/internal/cli/cmd"
 	"github.com/perses/perses/internal/cli/config"
 	"github.com/perses/perses/pkg/client/api"
+	apiConfig "github.com/perses/perses/pkg/model/api/config"
 	v1 "github.com/perses/perses/pkg/model/api/v1"
 	"github.com/perses/perses/pkg/model/api/v1/common"
 	pluginModel "github.com/perses/perses/pkg/model/api/v1/plugin"
@@ -121,6 +122,9 @@ type option struct {
 	apiClient  api.ClientInterface
 	writer     io.Writer
 	errWriter  io.Writer
+
+	// remotePluginConfig is the plugin configuration of the remote server, telling which plugins it accepts in development.
+	remotePluginConfig apiConfig.Plugin
 }
 
 func (o *option) Complete(args []string) error {
@@ -160,9 +164,10 @@ func (o *option) Validate() error {
 	if err != nil {
 		return err
 	}
-	if !cfg.Plugin.EnableDev {
+	if !cfg.Plugin.IsDevEnabled() {
 		return errors.New("remote server is not configured to receive plugin in development")
 	}
+	o.remotePluginConfig = cfg.Plugin
 	return nil
 }
 
@@ -204,6 +209,10 @@ func (o *option) prepareAllPlugins() ([]*devserver, []*v1.PluginInDevelopment, e
 			logrus.WithError(err).Errorf("failed to prepare plugin %q", pluginName)
 			continue
 		}
+		if !o.remotePluginConfig.IsDevPlugin(cfg.Name) {
+			logrus.Warnf("plugin %q is skipped, the remote server doesn't accept it in development", cfg.Name)
+			continue
+		}
 		servers = append(servers, s)
 		pluginInDev = append(pluginInDev, cfg)
 	}
@@ -0,0 +1,103 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"io"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type createOption struct {
+	persesCMD.Option
+	opt.ServerOption
+	writer      io.Writer
+	errWriter   io.Writer
+	name        string
+	displayName string
+	apiClient   api.ClientInterface
+}
+
+func (o *createOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'project create'")
+	}
+	apiClient, err := o.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *createOption) Validate() error {
+	return common.ValidateID(o.name)
+}
+
+func (o *createOption) Execute() error {
+	project := &modelV1.Project{
+		Kind: modelV1.KindProject,
+		Metadata: modelV1.Metadata{
+			Name: o.name,
+		},
+	}
+	if len(o.displayName) > 0 {
+		project.Spec.Display = &common.Display{Name: o.displayName}
+	}
+	if _, err := o.apiClient.V1().Project().Create(project); err != nil {
+		return err
+	}
+	return output.HandleString(o.writer, fmt.Sprintf("project %q has been created", o.name))
+}
+
+func (o *createOption) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *createOption) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func newCreateCMD() *cobra.Command {
+	o := &createOption{}
+	cmd := &cobra.Command{
+		Use:   "create --name [NAME]",
+		Short: "Create a project",
+		Example: `
+# Create the project 'myapp'
+percli project create --name myapp --display-name "My Application"
+
+# Create the project on another Perses server than the one used by default
+percli project create --name myapp --server https://perses.example.com --token ${TOKEN}
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddServerFlags(cmd, &o.ServerOption)
+	cmd.Flags().StringVar(&o.name, "name", "", "Name of the project")
+	cmd.Flags().StringVar(&o.displayName, "display-name", "", "Name of the project displayed in the UI")
+	if err := cmd.MarkFlagRequired("name"); err != nil {
+		logrus.Panic(err)
+	}
+	return cmd
+}
@@ -0,0 +1,125 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/internal/cli/service"
+	"github.com/perses/perses/pkg/client/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// projectContent is the list of resources checked before deleting a project, as they are deleted with it.
+var projectContent = []modelV1.Kind{
+	modelV1.KindDashboard,
+	modelV1.KindDashboardTemplate,
+	modelV1.KindDatasource,
+	modelV1.KindFolder,
+	modelV1.KindSecret,
+	modelV1.KindVariable,
+}
+
+type deleteOption struct {
+	persesCMD.Option
+	opt.ServerOption
+	writer      io.Writer
+	errWriter   io.Writer
+	projectName string
+	force       bool
+	apiClient   api.ClientInterface
+}
+
+func (o *deleteOption) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("the name of the project to delete must be the only argument of the command 'project delete'")
+	}
+	o.projectName = args[0]
+	apiClient, err := o.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *deleteOption) Validate() error {
+	return nil
+}
+
+func (o *deleteOption) Execute() error {
+	if !o.force {
+		if err := o.checkEmpty(); err != nil {
+			return err
+		}
+	}
+	if err := o.apiClient.V1().Project().Delete(o.projectName); err != nil {
+		return err
+	}
+	return output.HandleString(o.writer, fmt.Sprintf("project %q has been deleted", o.projectName))
+}
+
+// checkEmpty returns an error when the project still contains resources.
+func (o *deleteOption) checkEmpty() error {
+	var content []string
+	for _, kind := range projectContent {
+		svc, err := service.New(kind, o.projectName, o.apiClient)
+		if err != nil {
+			return err
+		}
+		resources, err := svc.ListResource("")
+		if err != nil {
+			return err
+		}
+		if len(resources) > 0 {
+			content = append(content, fmt.Sprintf("%s: %d", modelV1.PluralKindMap[kind], len(resources)))
+		}
+	}
+	if len(content) > 0 {
+		return fmt.Errorf("project %q is not empty (%s), use the flag --force to delete it anyway", o.projectName, strings.Join(content, ", "))
+	}
+	return nil
+}
+
+func (o *deleteOption) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *deleteOption) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func newDeleteCMD() *cobra.Command {
+	o := &deleteOption{}
+	cmd := &cobra.Command{
+		Use:   "delete [NAME]",
+		Short: "Delete a project. It must be empty unless --force is used",
+		Example: `
+# Delete the project 'myapp' and every resource it contains
+percli project delete myapp --force
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddServerFlags(cmd, &o.ServerOption)
+	cmd.Flags().BoolVar(&o.force, "force", false, "If present, the project is deleted even if it still contains resources")
+	return cmd
+}
@@ -0,0 +1,98 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"fmt"
+	"io"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/internal/cli/service"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+	"github.com/spf13/cobra"
+)
+
+type listOption struct {
+	persesCMD.Option
+	opt.ServerOption
+	opt.OutputOption
+	writer         io.Writer
+	errWriter      io.Writer
+	projectService service.Service
+}
+
+func (o *listOption) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'project list'")
+	}
+	// Like for the `get` command, the default output is a table, so the output is only completed when set by the user.
+	if len(o.Output) > 0 {
+		if outputErr := o.OutputOption.Complete(); outputErr != nil {
+			return outputErr
+		}
+	}
+	apiClient, err := o.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	svc, err := service.New(modelV1.KindProject, "", apiClient)
+	if err != nil {
+		return err
+	}
+	o.projectService = svc
+	return nil
+}
+
+func (o *listOption) Validate() error {
+	return nil
+}
+
+func (o *listOption) Execute() error {
+	projects, err := o.projectService.ListResource("")
+	if err != nil {
+		return err
+	}
+	if len(o.Output) > 0 {
+		return output.Handle(o.writer, o.Output, projects)
+	}
+	return output.HandlerTable(o.writer, o.projectService.GetColumHeader(), o.projectService.BuildMatrix(projects))
+}
+
+func (o *listOption) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *listOption) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func newListCMD() *cobra.Command {
+	o := &listOption{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the projects",
+		Example: `
+# List the projects as a JSON object
+percli project list -ojson
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddServerFlags(cmd, &o.ServerOption)
+	opt.AddOutputFlags(cmd, &o.OutputOption)
+	return cmd
+}
@@ -90,7 +90,8 @@ func NewCMD() *cobra.Command {
 		Long: `Select a project as a default project to use for later.
 The project to be used is stored in the configuration file located at ${USERHOME}/.perses/config.
 
-If no project is specified in the command line, it will instead display the current project used.`,
+If no project is specified in the command line, it will instead display the current project used.
+The subcommands create, list and delete manage the projects of the server.`,
 		Example: `
 # Switch to 'myapp' project
 percli project myapp
@@ -102,5 +103,8 @@ percli project
 			return persesCMD.Run(o, cmd, args)
 		},
 	}
+	cmd.AddCommand(newCreateCMD())
+	cmd.AddCommand(newListCMD())
+	cmd.AddCommand(newDeleteCMD())
 	return cmd
 }
@@ -0,0 +1,184 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockServer is a fake Perses API recording the requests it received.
+// The project "full" contains a dashboard and a folder, every other project is empty.
+type mockServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []string
+	tokens   []string
+}
+
+func newMockServer(t *testing.T) *mockServer {
+	m := &mockServer{}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mutex.Lock()
+		m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+		m.tokens = append(m.tokens, r.Header.Get("Authorization"))
+		m.mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(body)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/projects":
+			_, _ = w.Write([]byte(`[{"kind":"Project","metadata":{"name":"demo"}},{"kind":"Project","metadata":{"name":"full"}}]`))
+		case r.URL.Path == "/api/v1/projects/full/dashboards":
+			_, _ = w.Write([]byte(`[{"kind":"Dashboard","metadata":{"name":"node","project":"full"},"spec":{"display":{"name":"Node"},"duration":"1h","panels":{},"layouts":[]}}]`))
+		case r.URL.Path == "/api/v1/projects/full/folders":
+			_, _ = w.Write([]byte(`[{"kind":"Folder","metadata":{"name":"infra","project":"full"},"spec":{"items":[{"kind":"Dashboard","name":"node"}]}}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *mockServer) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = nil
+	m.tokens = nil
+}
+
+func TestProjectCreateCMD(t *testing.T) {
+	server := newMockServer(t)
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "missing name",
+			Args:            []string{"--server", server.URL},
+			IsErrorExpected: true,
+			ExpectedMessage: `required flag(s) "name" not set`,
+		},
+		{
+			Title:           "not connected to any API",
+			Args:            []string{"--name", "demo"},
+			IsErrorExpected: true,
+			ExpectedMessage: "you are not connected to any API",
+		},
+		{
+			Title:                "invalid server",
+			Args:                 []string{"--name", "demo", "--server", "://perses"},
+			IsErrorExpected:      true,
+			ExpectedRegexMessage: "^invalid value set to the flag --server",
+		},
+		{
+			Title:           "create a project",
+			Args:            []string{"--name", "demo", "--display-name", "Demo", "--server", server.URL, "--token", "secret"},
+			IsErrorExpected: false,
+			ExpectedMessage: "project \"demo\" has been created\n",
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, newCreateCMD, testSuite)
+	assert.Equal(t, []string{"POST /api/v1/projects"}, server.requests)
+	assert.Equal(t, []string{"Bearer secret"}, server.tokens)
+}
+
+func TestProjectListCMD(t *testing.T) {
+	server := newMockServer(t)
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "use args",
+			Args:            []string{"demo"},
+			IsErrorExpected: true,
+			ExpectedMessage: "no args are supported by the command 'project list'",
+		},
+		{
+			Title:                "list the projects",
+			Args:                 []string{"--server", server.URL, "-ojson"},
+			IsErrorExpected:      false,
+			ExpectedRegexMessage: `^\[\{"kind":"Project","metadata":\{"name":"demo".*\},\{"kind":"Project","metadata":\{"name":"full".*\}\]`,
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, newListCMD, testSuite)
+	assert.Equal(t, []string{"GET /api/v1/projects"}, server.requests)
+}
+
+func TestProjectDeleteCMD(t *testing.T) {
+	server := newMockServer(t)
+	testSuite := []struct {
+		cmdTest.Suite
+		expectedRequests []string
+	}{
+		{
+			Suite: cmdTest.Suite{
+				Title:           "missing name",
+				Args:            []string{"--server", server.URL},
+				IsErrorExpected: true,
+				ExpectedMessage: "the name of the project to delete must be the only argument of the command 'project delete'",
+			},
+		},
+		{
+			Suite: cmdTest.Suite{
+				Title:           "delete an empty project",
+				Args:            []string{"demo", "--server", server.URL},
+				IsErrorExpected: false,
+				ExpectedMessage: "project \"demo\" has been deleted\n",
+			},
+			expectedRequests: []string{
+				"GET /api/v1/projects/demo/dashboards",
+				"GET /api/v1/projects/demo/datasources",
+				"GET /api/v1/projects/demo/folders",
+				"GET /api/v1/projects/demo/secrets",
+				"GET /api/v1/projects/demo/variables",
+				"DELETE /api/v1/projects/demo",
+			},
+		},
+		{
+			Suite: cmdTest.Suite{
+				Title:           "project not empty",
+				Args:            []string{"full", "--server", server.URL},
+				IsErrorExpected: true,
+				ExpectedMessage: `project "full" is not empty (dashboards: 1, folders: 1), use the flag --force to delete it anyway`,
+			},
+			expectedRequests: []string{
+				"GET /api/v1/projects/full/dashboards",
+				"GET /api/v1/projects/full/datasources",
+				"GET /api/v1/projects/full/folders",
+				"GET /api/v1/projects/full/secrets",
+				"GET /api/v1/projects/full/variables",
+			},
+		},
+		{
+			Suite: cmdTest.Suite{
+				Title:           "force the deletion",
+				Args:            []string{"full", "--server", server.URL, "--force"},
+				IsErrorExpected: false,
+				ExpectedMessage: "project \"full\" has been deleted\n",
+			},
+			expectedRequests: []string{"DELETE /api/v1/projects/full"},
+		},
+	}
+	for _, test := range testSuite {
+		server.reset()
+		cmdTest.ExecuteSuiteTest(t, newDeleteCMD, []cmdTest.Suite{test.Suite})
+		assert.Equal(t, test.expectedRequests, server.requests, test.Title)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/opt"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+	csvFormat   = "csv"
+)
+
+var columns = []string{"METRIC", "TIMESTAMP", "VALUE"}
+
+// queryParameters are the query parameters of the Prometheus HTTP API.
+type queryParameters struct {
+	expr  string
+	start time.Time
+	end   time.Time
+	step  time.Duration
+}
+
+func (q *queryParameters) isRange() bool {
+	return !q.start.IsZero()
+}
+
+func (q *queryParameters) GetValues() url.Values {
+	values := url.Values{}
+	values.Set("query", q.expr)
+	if q.isRange() {
+		values.Set("start", formatTimestamp(q.start))
+		values.Set("end", formatTimestamp(q.end))
+		values.Set("step", strconv.FormatFloat(q.step.Seconds(), 'f', -1, 64))
+	} else {
+		values.Set("time", formatTimestamp(q.end))
+	}
+	return values
+}
+
+type queryResponse struct {
+	Status string    `json:"status"`
+	Data   queryData `json:"data"`
+}
+
+type queryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]any          `json:"values,omitempty"`
+	Value  *[2]any           `json:"value,omitempty"`
+}
+
+type option struct {
+	persesCMD.Option
+	opt.ProjectOption
+	writer     io.Writer
+	errWriter  io.Writer
+	datasource string
+	expr       string
+	start      string
+	end        string
+	step       string
+	format     string
+	params     *queryParameters
+	apiClient  api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'query'")
+	}
+	if projectErr := o.ProjectOption.Complete(); projectErr != nil {
+		return projectErr
+	}
+	now := time.Now()
+	o.params = &queryParameters{expr: o.expr, end: now}
+	if len(o.end) > 0 {
+		end, err := parseTime(o.end, now)
+		if err != nil {
+			return fmt.Errorf("invalid --end: %w", err)
+		}
+		o.params.end = end
+	}
+	if len(o.start) > 0 {
+		start, err := parseTime(o.start, now)
+		if err != nil {
+			return fmt.Errorf("invalid --start: %w", err)
+		}
+		o.params.start = start
+	}
+	step, err := model.ParseDuration(o.step)
+	if err != nil {
+		return fmt.Errorf("invalid --step: %w", err)
+	}
+	o.params.step = time.Duration(step)
+
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+func (o *option) Validate() error {
+	if len(o.datasource) == 0 {
+		return fmt.Errorf("--datasource is required")
+	}
+	if len(o.expr) == 0 {
+		return fmt.Errorf("--expr is required")
+	}
+	if o.format != tableFormat && o.format != jsonFormat && o.format != csvFormat {
+		return fmt.Errorf("--format must be %q, %q or %q", tableFormat, jsonFormat, csvFormat)
+	}
+	if o.params.isRange() {
+		if !o.params.start.Before(o.params.end) {
+			return fmt.Errorf("--start must be before --end")
+		}
+		if o.params.step <= 0 {
+			return fmt.Errorf("--step must be greater than 0")
+		}
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	endpoint := "query"
+	if o.params.isRange() {
+		endpoint = "query_range"
+	}
+	response := &queryResponse{}
+	err := o.apiClient.RESTClient().Get().
+		APIPrefix("/proxy").
+		APIVersion("").
+		Project(o.Project).
+		Resource("datasources").
+		Name(fmt.Sprintf("%s/api/v1/%s", o.datasource, endpoint)).
+		Query(o.params).
+		Do().
+		Object(response)
+	if err != nil {
+		return err
+	}
+	if o.format == jsonFormat {
+		return output.Handle(o.writer, output.JSONOutput, response.Data)
+	}
+	data, err := buildMatrix(response.Data)
+	if err != nil {
+		return err
+	}
+	if o.format == csvFormat {
+		csvWriter := csv.NewWriter(o.writer)
+		if writeErr := csvWriter.WriteAll(append([][]string{columns}, data...)); writeErr != nil {
+			return writeErr
+		}
+		return nil
+	}
+	return output.HandlerTable(o.writer, columns, data)
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+// buildMatrix flattens the result of the query with one line per sample.
+func buildMatrix(data queryData) ([][]string, error) {
+	switch data.ResultType {
+	case string(model.ValMatrix), string(model.ValVector):
+		var result []series
+		if err := json.Unmarshal(data.Result, &result); err != nil {
+			return nil, fmt.Errorf("unable to decode the query result: %w", err)
+		}
+		var lines [][]string
+		for _, s := range result {
+			metric := formatMetric(s.Metric)
+			samples := s.Values
+			if s.Value != nil {
+				samples = append(samples, *s.Value)
+			}
+			for _, sample := range samples {
+				line, err := formatSample(metric, sample)
+				if err != nil {
+					return nil, err
+				}
+				lines = append(lines, line)
+			}
+		}
+		return lines, nil
+	case string(model.ValScalar), string(model.ValString):
+		var sample [2]any
+		if err := json.Unmarshal(data.Result, &sample); err != nil {
+			return nil, fmt.Errorf("unable to decode the query result: %w", err)
+		}
+		line, err := formatSample("", sample)
+		if err != nil {
+			return nil, err
+		}
+		return [][]string{line}, nil
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", data.ResultType)
+	}
+}
+
+func formatSample(metric string, sample [2]any) ([]string, error) {
+	timestamp, ok := sample[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid timestamp %v", sample[0])
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %v", sample[1])
+	}
+	seconds := int64(timestamp)
+	nanoseconds := int64((timestamp - float64(seconds)) * float64(time.Second))
+	return []string{metric, time.Unix(seconds, nanoseconds).UTC().Format(time.RFC3339), value}, nil
+}
+
+// formatMetric prints the labels of a series the way Prometheus does: name{label="value", ...}.
+func formatMetric(labels map[string]string) string {
+	name := labels[model.MetricNameLabel]
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if key != model.MetricNameLabel {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return name
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return fmt.Sprintf("%s{%s}", name, strings.Join(pairs, ", "))
+}
+
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
+
+// parseTime accepts an RFC3339 date, a unix timestamp, "now" or a time relative to now such as "now-1h".
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "now" {
+		return now, nil
+	}
+	if relative, ok := strings.CutPrefix(value, "now-"); ok {
+		duration, err := model.ParseDuration(relative)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-time.Duration(duration)), nil
+	}
+	if timestamp, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(timestamp * 1000)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{
+		step:   "15s",
+		format: tableFormat,
+	}
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Run a query against a datasource through the Perses proxy",
+		Long: `Run a query against a Prometheus-compatible datasource of a project, through the proxy of the Perses server.
+Without --start, an instant query is executed at --end (now by default). Otherwise, a range query is executed between --start and --end.`,
+		Example: `
+# Get the current value of the metric "up" using the datasource "prometheus" of the current project.
+percli query --datasource prometheus --expr up
+
+# Get the last hour of a query with a point every minute, as CSV.
+percli query -p my_project --datasource prometheus --expr 'rate(http_requests_total[5m])' --start now-1h --step 1m --format csv
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	opt.AddProjectFlags(cmd, &o.ProjectOption)
+	cmd.Flags().StringVar(&o.datasource, "datasource", o.datasource, "Name of the project datasource to query.")
+	if err := cmd.RegisterFlagCompletionFunc("datasource", opt.CompleteDatasources); err != nil {
+		logrus.Panic(err)
+	}
+	cmd.Flags().StringVar(&o.expr, "expr", o.expr, "The query to run.")
+	cmd.Flags().StringVar(&o.start, "start", o.start, "Start of a range query. Can be an RFC3339 date, a unix timestamp, or relative to now like 'now-1h'.")
+	cmd.Flags().StringVar(&o.end, "end", o.end, "End of a range query, or time of an instant query. Same format as --start. Default to now.")
+	cmd.Flags().StringVar(&o.step, "step", o.step, "Resolution step of a range query.")
+	cmd.Flags().StringVar(&o.format, "format", o.format, "Output format: table, json or csv.")
+	return cmd
+}
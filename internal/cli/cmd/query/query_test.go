@@ -0,0 +1,144 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/config"
+	"github.com/perses/spec/go/common"
+)
+
+const (
+	rangeResult   = `{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"prometheus"},"values":[[1700000000,"1"],[1700000060,"0"]]}]}`
+	instantResult = `{"resultType":"vector","result":[{"metric":{"__name__":"up","instance":"localhost:9090"},"value":[1700000000.5,"1"]}]}`
+)
+
+// newMockServer mimics the Perses proxy in front of a Prometheus datasource named "prometheus" in the project "perses".
+func newMockServer(t *testing.T) api.ClientInterface {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/proxy/projects/perses/datasources/prometheus/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("query") != "up" || q.Get("start") != "1700000000" || q.Get("end") != "1700000060" || q.Get("step") != "60" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"unexpected query parameters"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":` + rangeResult + `}`))
+	})
+	mux.HandleFunc("/proxy/projects/perses/datasources/prometheus/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != "up" || r.URL.Query().Get("time") != "1700000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"unexpected query parameters"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":` + instantResult + `}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	restClient, err := config.NewRESTClient(config.RestConfigClient{URL: common.MustParseURL(server.URL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api.NewWithClient(restClient)
+}
+
+func TestQueryCMD(t *testing.T) {
+	apiClient := newMockServer(t)
+	rangeArgs := []string{"-p", "perses", "--datasource", "prometheus", "--expr", "up", "--start", "1700000000", "--end", "1700000060", "--step", "1m"}
+	testSuite := []cmdTest.Suite{
+		{
+			Title:           "missing project",
+			Args:            []string{"--datasource", "prometheus", "--expr", "up"},
+			APIClient:       apiClient,
+			IsErrorExpected: true,
+			ExpectedMessage: "project is not defined. Please set it using the flag --project or using the command perses project <project_name>",
+		},
+		{
+			Title:           "not connected to any API",
+			Args:            []string{"-p", "perses", "--datasource", "prometheus", "--expr", "up"},
+			IsErrorExpected: true,
+			ExpectedMessage: "you are not connected to any API",
+		},
+		{
+			Title:           "missing datasource",
+			Args:            []string{"-p", "perses", "--expr", "up"},
+			APIClient:       apiClient,
+			IsErrorExpected: true,
+			ExpectedMessage: "--datasource is required",
+		},
+		{
+			Title:           "missing expression",
+			Args:            []string{"-p", "perses", "--datasource", "prometheus"},
+			APIClient:       apiClient,
+			IsErrorExpected: true,
+			ExpectedMessage: "--expr is required",
+		},
+		{
+			Title:           "unknown format",
+			Args:            append(rangeArgs, "--format", "xml"),
+			APIClient:       apiClient,
+			IsErrorExpected: true,
+			ExpectedMessage: `--format must be "table", "json" or "csv"`,
+		},
+		{
+			Title:           "start after end",
+			Args:            []string{"-p", "perses", "--datasource", "prometheus", "--expr", "up", "--start", "now", "--end", "now-1h"},
+			APIClient:       apiClient,
+			IsErrorExpected: true,
+			ExpectedMessage: "--start must be before --end",
+		},
+		{
+			Title:           "range query as json",
+			Args:            append(rangeArgs, "--format", "json"),
+			APIClient:       apiClient,
+			ExpectedMessage: rangeResult + "\n",
+		},
+		{
+			Title:     "range query as csv",
+			Args:      append(rangeArgs, "--format", "csv"),
+			APIClient: apiClient,
+			ExpectedMessage: `METRIC,TIMESTAMP,VALUE
+"up{job=""prometheus""}",2023-11-14T22:13:20Z,1
+"up{job=""prometheus""}",2023-11-14T22:14:20Z,0
+`,
+		},
+		{
+			Title:                "range query as table",
+			Args:                 rangeArgs,
+			APIClient:            apiClient,
+			ExpectedRegexMessage: `(?s)METRIC\s+TIMESTAMP\s+VALUE.*up\{job="prometheus"\}\s+2023-11-14T22:13:20Z\s+1.*up\{job="prometheus"\}\s+2023-11-14T22:14:20Z\s+0`,
+		},
+		{
+			Title:     "instant query as csv",
+			Args:      []string{"-p", "perses", "--datasource", "prometheus", "--expr", "up", "--end", "2023-11-14T22:13:20Z", "--format", "csv"},
+			APIClient: apiClient,
+			ExpectedMessage: `METRIC,TIMESTAMP,VALUE
+"up{instance=""localhost:9090""}",2023-11-14T22:13:20Z,1
+`,
+		},
+		{
+			Title:                "error returned by the proxy",
+			Args:                 []string{"-p", "perses", "--datasource", "unknown", "--expr", "up"},
+			APIClient:            apiClient,
+			IsErrorExpected:      true,
+			ExpectedRegexMessage: "StatusCode: 404",
+		},
+	}
+	cmdTest.ExecuteSuiteTest(t, NewCMD, testSuite)
+}
@@ -0,0 +1,152 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/perses/perses/internal/api/utils"
+	"github.com/perses/perses/internal/cli/backup"
+	persesCMD "github.com/perses/perses/internal/cli/cmd"
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/internal/cli/output"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/perseshttp"
+	"github.com/spf13/cobra"
+)
+
+type option struct {
+	persesCMD.Option
+	writer    io.Writer
+	errWriter io.Writer
+	input     string
+	archive   *backup.Archive
+	apiClient api.ClientInterface
+}
+
+func (o *option) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("no args are supported by the command 'restore'")
+	}
+	if len(o.input) == 0 {
+		return fmt.Errorf("you need to set the flag --input for this command")
+	}
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	f, err := os.Open(o.input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	o.archive, err = backup.Read(f)
+	return err
+}
+
+func (o *option) Validate() error {
+	if o.archive.Get(backup.ProjectsFile) == nil {
+		return fmt.Errorf("the archive %s doesn't contain the list of the projects", o.input)
+	}
+	return nil
+}
+
+func (o *option) Execute() error {
+	projects, err := o.restoreFile("", utils.PathProject, backup.ProjectsFile)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, resource := range backup.GlobalResources {
+		restored, restoreErr := o.restoreFile("", resource, backup.GlobalResourceFile(resource))
+		if restoreErr != nil {
+			return restoreErr
+		}
+		count += len(restored)
+	}
+	for _, project := range projects {
+		for _, resource := range backup.ProjectResources {
+			restored, restoreErr := o.restoreFile(project, resource, backup.ProjectResourceFile(project, resource))
+			if restoreErr != nil {
+				return restoreErr
+			}
+			count += len(restored)
+		}
+	}
+	return output.HandleString(o.writer, fmt.Sprintf("%d project(s) and %d resource(s) restored from %s", len(projects), count, o.input))
+}
+
+// restoreFile creates or updates every resource of the given file and returns their names.
+// A file that is not in the archive is skipped.
+func (o *option) restoreFile(project, resource, fileName string) ([]string, error) {
+	data := o.archive.Get(fileName)
+	if data == nil {
+		return nil, nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid file %s in the archive: %w", fileName, err)
+	}
+	names := make([]string, 0, len(list))
+	for _, raw := range list {
+		name, err := backup.ResourceName(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource in the file %s of the archive: %w", fileName, err)
+		}
+		if upsertErr := o.upsert(project, resource, name, raw); upsertErr != nil {
+			return nil, fmt.Errorf("unable to restore %s %q: %w", resource, name, upsertErr)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (o *option) upsert(project, resource, name string, raw json.RawMessage) error {
+	createErr := o.apiClient.RESTClient().Post().Project(project).Resource(resource).Body(raw).Do().Error()
+	if createErr == nil || !errors.Is(createErr, perseshttp.ConflictError) {
+		return createErr
+	}
+	return o.apiClient.RESTClient().Put().Project(project).Resource(resource).Name(name).Body(raw).Do().Error()
+}
+
+func (o *option) SetWriter(writer io.Writer) {
+	o.writer = writer
+}
+
+func (o *option) SetErrWriter(errWriter io.Writer) {
+	o.errWriter = errWriter
+}
+
+func NewCMD() *cobra.Command {
+	o := &option{}
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the resources saved in an archive created with the command 'backup'",
+		Long: `Restore the projects, their resources and the global resources saved in an archive created with the command 'backup'.
+The integrity of the archive is verified before anything is restored. Existing resources are overwritten.`,
+		Example: `
+percli restore --input perses-backup.tar.gz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return persesCMD.Run(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&o.input, "input", o.input, "Path of the archive to restore.")
+	return cmd
+}
@@ -0,0 +1,186 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/perses/perses/internal/cli/backup"
+	backupCMD "github.com/perses/perses/internal/cli/cmd/backup"
+	cmdTest "github.com/perses/perses/internal/cli/test"
+	"github.com/perses/perses/pkg/client/api"
+	"github.com/perses/perses/pkg/client/config"
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer stores the resources by collection path, such as "projects" or "projects/perses/dashboards".
+// The resources not listed in served answer 404, like the roles when the native authorization is disabled.
+type fakeServer struct {
+	mutex     sync.Mutex
+	served    map[string]bool
+	resources map[string][]json.RawMessage
+}
+
+func newFakeServer(t *testing.T, resources map[string][]json.RawMessage) (*fakeServer, api.ClientInterface) {
+	f := &fakeServer{
+		served:    map[string]bool{"projects": true, "globaldatasources": true, "globalvariables": true, "datasources": true, "dashboards": true},
+		resources: resources,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/config", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"security":{"encryption_key":"<secret>"}}`))
+	})
+	mux.HandleFunc("/api/v1/plugins", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/v1/", f.serve)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	restClient, err := config.NewRESTClient(config.RestConfigClient{URL: common.MustParseURL(server.URL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, api.NewWithClient(restClient)
+}
+
+func (f *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	collection := strings.Join(segments, "/")
+	name := ""
+	if len(segments) == 2 || len(segments) == 4 {
+		collection = strings.Join(segments[:len(segments)-1], "/")
+		name = segments[len(segments)-1]
+	}
+	resource := segments[len(segments)-1]
+	if len(name) > 0 {
+		resource = segments[len(segments)-2]
+	}
+	if !f.served[resource] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		list := f.resources[collection]
+		if list == nil {
+			list = []json.RawMessage{}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if f.index(collection, body) >= 0 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.resources[collection] = append(f.resources[collection], body)
+		_, _ = w.Write(body)
+	case http.MethodPut:
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		i := f.index(collection, body)
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.resources[collection][i] = body
+		_, _ = w.Write(body)
+	}
+}
+
+func (f *fakeServer) index(collection string, raw json.RawMessage) int {
+	name, _ := backup.ResourceName(raw)
+	for i, existing := range f.resources[collection] {
+		if existingName, _ := backup.ResourceName(existing); existingName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	source := map[string][]json.RawMessage{
+		"projects": {
+			json.RawMessage(`{"kind":"Project","metadata":{"name":"perses"}}`),
+			json.RawMessage(`{"kind":"Project","metadata":{"name":"empty"}}`),
+		},
+		"globaldatasources": {
+			json.RawMessage(`{"kind":"GlobalDatasource","metadata":{"name":"prometheus"},"spec":{"default":true,"plugin":{"kind":"PrometheusDatasource","spec":{}}}}`),
+		},
+		"projects/perses/dashboards": {
+			json.RawMessage(`{"kind":"Dashboard","metadata":{"name":"overview","project":"perses"},"spec":{"duration":"1h"}}`),
+			json.RawMessage(`{"kind":"Dashboard","metadata":{"name":"details","project":"perses"},"spec":{"duration":"6h"}}`),
+		},
+	}
+	_, sourceClient := newFakeServer(t, source)
+	cmdTest.ExecuteSuiteTest(t, backupCMD.NewCMD, []cmdTest.Suite{
+		{
+			Title:           "backup",
+			Args:            []string{"--output", archivePath},
+			APIClient:       sourceClient,
+			ExpectedMessage: "backup of 2 project(s) saved in " + archivePath + "\n",
+		},
+	})
+
+	// The target already contains one of the dashboards, with a different content, which must be overwritten.
+	target, targetClient := newFakeServer(t, map[string][]json.RawMessage{
+		"projects": {
+			json.RawMessage(`{"kind":"Project","metadata":{"name":"perses"}}`),
+		},
+		"projects/perses/dashboards": {
+			json.RawMessage(`{"kind":"Dashboard","metadata":{"name":"details","project":"perses"},"spec":{"duration":"5m"}}`),
+		},
+	})
+	cmdTest.ExecuteSuiteTest(t, NewCMD, []cmdTest.Suite{
+		{
+			Title:           "restore",
+			Args:            []string{"--input", archivePath},
+			APIClient:       targetClient,
+			ExpectedMessage: "2 project(s) and 3 resource(s) restored from " + archivePath + "\n",
+		},
+		{
+			Title:                "missing archive",
+			Args:                 []string{"--input", filepath.Join(t.TempDir(), "missing.tar.gz")},
+			APIClient:            targetClient,
+			IsErrorExpected:      true,
+			ExpectedRegexMessage: "no such file or directory",
+		},
+	})
+
+	assert.ElementsMatch(t, jsonStrings(source["projects"]), jsonStrings(target.resources["projects"]))
+	assert.ElementsMatch(t, jsonStrings(source["globaldatasources"]), jsonStrings(target.resources["globaldatasources"]))
+	assert.ElementsMatch(t, jsonStrings(source["projects/perses/dashboards"]), jsonStrings(target.resources["projects/perses/dashboards"]))
+}
+
+// jsonStrings normalizes the resources, so they can be compared whatever their formatting.
+func jsonStrings(list []json.RawMessage) []string {
+	result := make([]string, 0, len(list))
+	for _, raw := range list {
+		var value any
+		_ = json.Unmarshal(raw, &value)
+		data, _ := json.Marshal(value)
+		result = append(result, string(data))
+	}
+	return result
+}
@@ -98,6 +98,16 @@ func UnmarshalEntitiesFromFile(file string) ([]modelAPI.Entity, error) {
 	return u.unmarshal()
 }
 
+// UnmarshalEntitiesFromData extracts the Perses resources from data that has been produced from the given file,
+// like the result of the evaluation of a Jsonnet file.
+func UnmarshalEntitiesFromData(file string, data []byte) ([]modelAPI.Entity, error) {
+	u := &unmarshaller{file: file}
+	if err := u.decode(data, json.Unmarshal(data, &json.RawMessage{}) == nil); err != nil {
+		return nil, err
+	}
+	return u.unmarshalEntities()
+}
+
 func visit(dir string) ([]string, error) {
 	var files []string
 	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
@@ -176,6 +186,10 @@ func (u *unmarshaller) read() error {
 	if err != nil {
 		return err
 	}
+	return u.decode(data, isJSON)
+}
+
+func (u *unmarshaller) decode(data []byte, isJSON bool) error {
 	u.isJSON = isJSON
 
 	var objects []map[string]any
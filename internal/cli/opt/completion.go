@@ -0,0 +1,73 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"github.com/perses/perses/internal/cli/config"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// CompleteProjects is the completion function returning the projects of the server starting with the value being completed.
+func CompleteProjects(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := apiClient.V1().Project().List(toComplete)
+	return completionNames(list, err)
+}
+
+// CompleteDatasources is the completion function returning the datasources of the project set with the flag --project,
+// or of the current project.
+func CompleteDatasources(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := apiClient.V1().Datasource(completionProject(cmd)).List(toComplete)
+	return completionNames(list, err)
+}
+
+// CompleteDashboards is the completion function returning the dashboards of the project set with the flag --project,
+// or of the current project.
+func CompleteDashboards(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	apiClient, err := config.Global.GetAPIClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := apiClient.V1().Dashboard(completionProject(cmd)).List(toComplete)
+	return completionNames(list, err)
+}
+
+func completionProject(cmd *cobra.Command) string {
+	if flag := cmd.Flags().Lookup("project"); flag != nil && len(flag.Value.String()) > 0 {
+		return flag.Value.String()
+	}
+	return config.Global.Project
+}
+
+func completionNames[T modelAPI.Entity](list []T, err error) ([]string, cobra.ShellCompDirective) {
+	if err != nil {
+		// Nothing can be printed during a completion, so the error is only visible in debug mode.
+		logrus.WithError(err).Debug("unable to get the list of resources to complete")
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(list))
+	for _, entity := range list {
+		names = append(names, entity.GetMetadata().GetName())
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,88 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/pkg/client/api"
+	clientConfig "github.com/perses/perses/pkg/client/config"
+	"github.com/perses/spec/go/common"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCompletionServer returns a fake API answering the list of every resource, and the list of the requests it received.
+func newCompletionServer(t *testing.T) *[]string {
+	var mutex sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.URL.RequestURI())
+		mutex.Unlock()
+		_, _ = w.Write([]byte(`[{"kind":"Project","metadata":{"name":"demo"}},{"kind":"Project","metadata":{"name":"dev"}}]`))
+	}))
+	t.Cleanup(server.Close)
+	restClient, err := clientConfig.NewRESTClient(clientConfig.RestConfigClient{URL: common.MustParseURL(server.URL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Global = &config.Config{}
+	config.Global.Project = "perses"
+	config.Global.SetAPIClient(api.NewWithClient(restClient))
+	return &requests
+}
+
+func newCompletionCMD(project string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	o := &ProjectOption{}
+	AddProjectFlags(cmd, o)
+	if len(project) > 0 {
+		_ = cmd.Flags().Set("project", project)
+	}
+	return cmd
+}
+
+func TestCompleteProjects(t *testing.T) {
+	requests := newCompletionServer(t)
+	names, directive := CompleteProjects(newCompletionCMD(""), nil, "de")
+	assert.Equal(t, []string{"demo", "dev"}, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.Equal(t, []string{"/api/v1/projects?name=de"}, *requests)
+}
+
+func TestCompleteDatasources(t *testing.T) {
+	requests := newCompletionServer(t)
+	// without --project, the current project is used.
+	_, _ = CompleteDatasources(newCompletionCMD(""), nil, "")
+	_, _ = CompleteDatasources(newCompletionCMD("other"), nil, "prom")
+	assert.Equal(t, []string{"/api/v1/projects/perses/datasources", "/api/v1/projects/other/datasources?name=prom"}, *requests)
+}
+
+func TestCompleteDashboards(t *testing.T) {
+	requests := newCompletionServer(t)
+	_, _ = CompleteDashboards(newCompletionCMD("other"), nil, "node")
+	assert.Equal(t, []string{"/api/v1/projects/other/dashboards?name=node"}, *requests)
+}
+
+func TestCompleteWithoutServer(t *testing.T) {
+	config.Global = &config.Config{}
+	names, directive := CompleteProjects(newCompletionCMD(""), nil, "")
+	assert.Empty(t, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
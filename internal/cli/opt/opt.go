@@ -104,4 +104,7 @@ func (o *ProjectOption) Complete() error {
 
 func AddProjectFlags(cmd *cobra.Command, o *ProjectOption) {
 	cmd.Flags().StringVarP(&o.Project, "project", "p", o.Project, "If present, the project scope for this CLI request")
+	if err := cmd.RegisterFlagCompletionFunc("project", CompleteProjects); err != nil {
+		logrus.Panic(err)
+	}
 }
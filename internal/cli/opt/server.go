@@ -0,0 +1,69 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"fmt"
+
+	"github.com/perses/perses/internal/cli/config"
+	"github.com/perses/perses/pkg/client/api"
+	clientConfig "github.com/perses/perses/pkg/client/config"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+	"github.com/spf13/cobra"
+)
+
+// ServerOption is used by the commands that can target another Perses server than the one stored in the CLI config,
+// which is useful when a script is managing several instances.
+type ServerOption struct {
+	Server string
+	Token  string
+}
+
+// GetAPIClient returns the client of the server set with --server, or the one of the CLI config otherwise.
+// When --token is set, it replaces the authentication defined in the CLI config.
+func (o *ServerOption) GetAPIClient() (api.ClientInterface, error) {
+	if len(o.Server) == 0 && len(o.Token) == 0 {
+		return config.Global.GetAPIClient()
+	}
+	restConfig := config.Global.RestClientConfig
+	if len(o.Server) > 0 {
+		url, err := common.ParseURL(o.Server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value set to the flag --server: %w", err)
+		}
+		// The rest of the CLI config is about the server stored in it, so it is not kept.
+		restConfig = clientConfig.RestConfigClient{URL: url}
+	}
+	if restConfig.URL == nil {
+		return nil, fmt.Errorf("you are not connected to any API")
+	}
+	if len(o.Token) > 0 {
+		restConfig.NativeAuth = nil
+		restConfig.OAuth = nil
+		restConfig.BasicAuth = nil
+		restConfig.K8sAuth = nil
+		restConfig.Authorization = secret.NewBearerToken(o.Token)
+	}
+	restClient, err := clientConfig.NewRESTClient(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewWithClient(restClient), nil
+}
+
+func AddServerFlags(cmd *cobra.Command, o *ServerOption) {
+	cmd.Flags().StringVar(&o.Server, "server", "", "URL of the Perses server to use instead of the one stored in the CLI config")
+	cmd.Flags().StringVar(&o.Token, "token", "", "Bearer token used to authenticate against the Perses server")
+}
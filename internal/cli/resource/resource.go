@@ -39,6 +39,14 @@ var resources = []resource{
 			"dashs",
 		},
 	},
+	{
+		kind:      modelV1.KindDashboardTemplate,
+		shortTerm: "dtpl",
+		aliases: []string{
+			"dashboardTemplates",
+			"dtpls",
+		},
+	},
 	{
 		kind:      modelV1.KindDatasource,
 		shortTerm: "dts",
@@ -128,6 +136,20 @@ var resources = []resource{
 			"scrt",
 		},
 	},
+	{
+		kind:      modelV1.KindTemplatePolicy,
+		shortTerm: "tpl",
+		aliases: []string{
+			"templatePolicies",
+		},
+	},
+	{
+		kind:      modelV1.KindTemplatePolicyBinding,
+		shortTerm: "tplb",
+		aliases: []string{
+			"templatePolicyBindings",
+		},
+	},
 	{
 		kind:      modelV1.KindUser,
 		shortTerm: "usr",
@@ -199,6 +221,25 @@ func reverseResourceAliases() map[string]modelV1.Kind {
 	return result
 }
 
+// Kinds returns the list of every kind of resource managed by the CLI, in the order they are registered.
+func Kinds() []modelV1.Kind {
+	result := make([]modelV1.Kind, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, r.kind)
+	}
+	return result
+}
+
+// GetShortTerm returns the short name of the given kind. It returns an empty string if the kind has no short name.
+func GetShortTerm(kind modelV1.Kind) string {
+	for _, r := range resources {
+		if r.kind == kind {
+			return r.shortTerm
+		}
+	}
+	return ""
+}
+
 // FormatMessage formats the available resources that the user can use
 func FormatMessage() string {
 	var result []string
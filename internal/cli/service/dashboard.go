@@ -30,7 +30,17 @@ func (d *dashboard) CreateResource(entity modelAPI.Entity) (modelAPI.Entity, err
 }
 
 func (d *dashboard) UpdateResource(entity modelAPI.Entity) (modelAPI.Entity, error) {
-	return d.apiClient.Update(entity.(*modelV1.Dashboard))
+	dash := entity.(*modelV1.Dashboard)
+	if len(dash.Metadata.ResourceVersion) == 0 {
+		// The server requires the resource version to update a dashboard. Applying a file replaces the current
+		// dashboard anyway, so the current resource version is used.
+		current, err := d.apiClient.Get(dash.Metadata.Name)
+		if err != nil {
+			return nil, err
+		}
+		dash.Metadata.ResourceVersion = current.Metadata.ResourceVersion
+	}
+	return d.apiClient.Update(dash)
 }
 
 func (d *dashboard) ListResource(prefix string) ([]modelAPI.Entity, error) {
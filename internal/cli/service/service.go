@@ -72,6 +72,10 @@ func New(kind modelV1.Kind, projectName string, apiClient api.ClientInterface) (
 		return &datasource{
 			apiClient: apiClient.V1().Datasource(projectName),
 		}, nil
+	case modelV1.KindDashboardTemplate:
+		return &dashboardTemplate{
+			apiClient: apiClient.V1().DashboardTemplate(projectName),
+		}, nil
 	case modelV1.KindEphemeralDashboard:
 		return &ephemeralDashboard{
 			apiClient: apiClient.V1().EphemeralDashboard(projectName),
@@ -116,6 +120,14 @@ func New(kind modelV1.Kind, projectName string, apiClient api.ClientInterface) (
 		return &secret{
 			apiClient: apiClient.V1().Secret(projectName),
 		}, nil
+	case modelV1.KindTemplatePolicy:
+		return &templatePolicy{
+			apiClient: apiClient.V1().TemplatePolicy(),
+		}, nil
+	case modelV1.KindTemplatePolicyBinding:
+		return &templatePolicyBinding{
+			apiClient: apiClient.V1().TemplatePolicyBinding(),
+		}, nil
 	case modelV1.KindUser:
 		return &user{
 			apiClient: apiClient.V1().User(),
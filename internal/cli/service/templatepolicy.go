@@ -0,0 +1,70 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+
+	"github.com/perses/perses/internal/cli/output"
+	v1 "github.com/perses/perses/pkg/client/api/v1"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type templatePolicy struct {
+	Service
+	apiClient v1.TemplatePolicyInterface
+}
+
+func (t *templatePolicy) CreateResource(entity modelAPI.Entity) (modelAPI.Entity, error) {
+	return t.apiClient.Create(entity.(*modelV1.TemplatePolicy))
+}
+
+func (t *templatePolicy) UpdateResource(entity modelAPI.Entity) (modelAPI.Entity, error) {
+	return t.apiClient.Update(entity.(*modelV1.TemplatePolicy))
+}
+
+func (t *templatePolicy) ListResource(prefix string) ([]modelAPI.Entity, error) {
+	return convertToEntityIfNoError(t.apiClient.List(prefix))
+}
+
+func (t *templatePolicy) GetResource(name string) (modelAPI.Entity, error) {
+	return t.apiClient.Get(name)
+}
+
+func (t *templatePolicy) DeleteResource(name string) error {
+	return t.apiClient.Delete(name)
+}
+
+func (t *templatePolicy) BuildMatrix(hits []modelAPI.Entity) [][]string {
+	var data [][]string
+	for _, hit := range hits {
+		entity := hit.(*modelV1.TemplatePolicy)
+		line := []string{
+			entity.Metadata.Name,
+			output.FormatAge(entity.Metadata.UpdatedAt),
+			strings.Join(entity.Spec.AllowedTemplates, ","),
+		}
+		data = append(data, line)
+	}
+	return data
+}
+
+func (t *templatePolicy) GetColumHeader() []string {
+	return []string{
+		nameColumnHeader,
+		ageColumnHeader,
+		"ALLOWED TEMPLATES",
+	}
+}
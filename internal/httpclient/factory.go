@@ -0,0 +1,81 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient provides the factory to use for any HTTP client created by Perses.
+// It ensures every outgoing request is traced and carries the ID of the request that triggered it.
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type options struct {
+	transport http.RoundTripper
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+type ClientOption func(*options)
+
+// WithTransport sets the base transport of the client. By default, a clone of http.DefaultTransport is used.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTLSConfig sets the TLS config of the base transport, typically to provide a client certificate (mTLS).
+// It is ignored when the base transport set with WithTransport is not an *http.Transport.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithTimeout sets the time limit of the requests made by the client. By default, there is no timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// NewHTTPClient returns an HTTP client whose transport propagates the request ID stored in the context of each request
+// and is instrumented with OpenTelemetry.
+func NewHTTPClient(opts ...ClientOption) *http.Client {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	base := o.transport
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if o.tlsConfig != nil {
+		if t, ok := base.(*http.Transport); ok {
+			t = t.Clone()
+			t.TLSClientConfig = o.tlsConfig
+			base = t
+		} else {
+			logrus.Warnf("TLS config ignored as the transport %T is not an *http.Transport", base)
+		}
+	}
+	return &http.Client{
+		Timeout:   o.timeout,
+		Transport: otelhttp.NewTransport(&requestIDTransport{next: base}),
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type countingTransport struct {
+	calls int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewHTTPClient_TransportChain(t *testing.T) {
+	base := &countingTransport{}
+	client := NewHTTPClient(WithTransport(base), WithTimeout(5*time.Second))
+	assert.Equal(t, 5*time.Second, client.Timeout)
+	assert.IsType(t, &otelhttp.Transport{}, client.Transport)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, 1, base.calls)
+}
+
+func TestNewHTTPClient_RequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := NewHTTPClient()
+
+	send := func(ctx context.Context, header string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		if len(header) > 0 {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	send(WithRequestID(context.Background(), "abc"), "")
+	send(context.Background(), "")
+	send(WithRequestID(context.Background(), "abc"), "set-by-caller")
+	assert.Equal(t, []string{"abc", "", "set-by-caller"}, received)
+}
+
+func TestNewHTTPClient_TLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// without the certificate of the server, the request fails
+	_, err := NewHTTPClient().Get(server.URL)
+	assert.Error(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	resp, err := NewHTTPClient(WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})).Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
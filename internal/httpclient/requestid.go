@@ -0,0 +1,53 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the ID of the request.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context holding the given request ID.
+// Any request made with this context by a client created with NewHTTPClient will carry it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in the context, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// requestIDTransport sets the header RequestIDHeader with the request ID found in the context of the request.
+// A header already set by the caller is kept.
+type requestIDTransport struct {
+	next http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := RequestIDFromContext(req.Context())
+	if len(requestID) == 0 || len(req.Header.Get(RequestIDHeader)) > 0 {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request, so we work on a copy.
+	clone := req.Clone(req.Context())
+	clone.Header.Set(RequestIDHeader, requestID)
+	return t.next.RoundTrip(clone)
+}
@@ -22,6 +22,7 @@ import (
 type ClientInterface interface {
 	RESTClient() *perseshttp.RESTClient
 	Dashboard(project string) DashboardInterface
+	DashboardTemplate(project string) DashboardTemplateInterface
 	Datasource(project string) DatasourceInterface
 	EphemeralDashboard(project string) EphemeralDashboardInterface
 	Folder(project string) FolderInterface
@@ -36,6 +37,8 @@ type ClientInterface interface {
 	Role(project string) RoleInterface
 	RoleBinding(project string) RoleBindingInterface
 	Secret(project string) SecretInterface
+	TemplatePolicy() TemplatePolicyInterface
+	TemplatePolicyBinding() TemplatePolicyBindingInterface
 	User() UserInterface
 	Variable(project string) VariableInterface
 }
@@ -59,6 +62,10 @@ func (c *client) Dashboard(project string) DashboardInterface {
 	return newDashboard(c.restClient, project)
 }
 
+func (c *client) DashboardTemplate(project string) DashboardTemplateInterface {
+	return newDashboardTemplate(c.restClient, project)
+}
+
 func (c *client) Datasource(project string) DatasourceInterface {
 	return newDatasource(c.restClient, project)
 }
@@ -115,6 +122,14 @@ func (c *client) Secret(project string) SecretInterface {
 	return newSecret(c.restClient, project)
 }
 
+func (c *client) TemplatePolicy() TemplatePolicyInterface {
+	return newTemplatePolicy(c.restClient)
+}
+
+func (c *client) TemplatePolicyBinding() TemplatePolicyBindingInterface {
+	return newTemplatePolicyBinding(c.restClient)
+}
+
 func (c *client) User() UserInterface {
 	return newUser(c.restClient)
 }
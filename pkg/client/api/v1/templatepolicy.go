@@ -0,0 +1,98 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated. DO NOT EDIT
+
+package v1
+
+import (
+	"github.com/perses/perses/pkg/client/perseshttp"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+const templatePolicyResource = "templatepolicies"
+
+type TemplatePolicyInterface interface {
+	Create(entity *v1.TemplatePolicy) (*v1.TemplatePolicy, error)
+	Update(entity *v1.TemplatePolicy) (*v1.TemplatePolicy, error)
+	Delete(name string) error
+	// Get is returning a unique TemplatePolicy.
+	// As such name is the exact value of TemplatePolicy.metadata.name. It cannot be empty.
+	// If you want to perform a research by prefix, please use the method List
+	Get(name string) (*v1.TemplatePolicy, error)
+	// prefix is a prefix of the TemplatePolicy.metadata.name to search for.
+	// It can be empty in case you want to get the full list of TemplatePolicy available
+	List(prefix string) ([]*v1.TemplatePolicy, error)
+}
+
+type templatePolicy struct {
+	TemplatePolicyInterface
+	client *perseshttp.RESTClient
+}
+
+func newTemplatePolicy(client *perseshttp.RESTClient) TemplatePolicyInterface {
+	return &templatePolicy{
+		client: client,
+	}
+}
+
+func (c *templatePolicy) Create(entity *v1.TemplatePolicy) (*v1.TemplatePolicy, error) {
+	result := &v1.TemplatePolicy{}
+	err := c.client.Post().
+		Resource(templatePolicyResource).
+		Body(entity).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicy) Update(entity *v1.TemplatePolicy) (*v1.TemplatePolicy, error) {
+	result := &v1.TemplatePolicy{}
+	err := c.client.Put().
+		Resource(templatePolicyResource).
+		Name(entity.Metadata.Name).
+		Body(entity).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicy) Delete(name string) error {
+	return c.client.Delete().
+		Resource(templatePolicyResource).
+		Name(name).
+		Do().
+		Error()
+}
+
+func (c *templatePolicy) Get(name string) (*v1.TemplatePolicy, error) {
+	result := &v1.TemplatePolicy{}
+	err := c.client.Get().
+		Resource(templatePolicyResource).
+		Name(name).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicy) List(prefix string) ([]*v1.TemplatePolicy, error) {
+	var result []*v1.TemplatePolicy
+	err := c.client.Get().
+		Resource(templatePolicyResource).
+		Query(&query{
+			name: prefix,
+		}).
+		Do().
+		Object(&result)
+	return result, err
+}
@@ -0,0 +1,98 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated. DO NOT EDIT
+
+package v1
+
+import (
+	"github.com/perses/perses/pkg/client/perseshttp"
+	v1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+const templatePolicyBindingResource = "templatepolicybindings"
+
+type TemplatePolicyBindingInterface interface {
+	Create(entity *v1.TemplatePolicyBinding) (*v1.TemplatePolicyBinding, error)
+	Update(entity *v1.TemplatePolicyBinding) (*v1.TemplatePolicyBinding, error)
+	Delete(name string) error
+	// Get is returning a unique TemplatePolicyBinding.
+	// As such name is the exact value of TemplatePolicyBinding.metadata.name. It cannot be empty.
+	// If you want to perform a research by prefix, please use the method List
+	Get(name string) (*v1.TemplatePolicyBinding, error)
+	// prefix is a prefix of the TemplatePolicyBinding.metadata.name to search for.
+	// It can be empty in case you want to get the full list of TemplatePolicyBinding available
+	List(prefix string) ([]*v1.TemplatePolicyBinding, error)
+}
+
+type templatePolicyBinding struct {
+	TemplatePolicyBindingInterface
+	client *perseshttp.RESTClient
+}
+
+func newTemplatePolicyBinding(client *perseshttp.RESTClient) TemplatePolicyBindingInterface {
+	return &templatePolicyBinding{
+		client: client,
+	}
+}
+
+func (c *templatePolicyBinding) Create(entity *v1.TemplatePolicyBinding) (*v1.TemplatePolicyBinding, error) {
+	result := &v1.TemplatePolicyBinding{}
+	err := c.client.Post().
+		Resource(templatePolicyBindingResource).
+		Body(entity).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicyBinding) Update(entity *v1.TemplatePolicyBinding) (*v1.TemplatePolicyBinding, error) {
+	result := &v1.TemplatePolicyBinding{}
+	err := c.client.Put().
+		Resource(templatePolicyBindingResource).
+		Name(entity.Metadata.Name).
+		Body(entity).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicyBinding) Delete(name string) error {
+	return c.client.Delete().
+		Resource(templatePolicyBindingResource).
+		Name(name).
+		Do().
+		Error()
+}
+
+func (c *templatePolicyBinding) Get(name string) (*v1.TemplatePolicyBinding, error) {
+	result := &v1.TemplatePolicyBinding{}
+	err := c.client.Get().
+		Resource(templatePolicyBindingResource).
+		Name(name).
+		Do().
+		Object(result)
+	return result, err
+}
+
+func (c *templatePolicyBinding) List(prefix string) ([]*v1.TemplatePolicyBinding, error) {
+	var result []*v1.TemplatePolicyBinding
+	err := c.client.Get().
+		Resource(templatePolicyBindingResource).
+		Query(&query{
+			name: prefix,
+		}).
+		Do().
+		Object(&result)
+	return result, err
+}
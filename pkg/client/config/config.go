@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/perses/perses/internal/httpclient"
 	"github.com/perses/perses/pkg/client/perseshttp"
 	"github.com/perses/perses/pkg/client/transport"
 	"github.com/perses/perses/pkg/model/api"
@@ -127,10 +128,10 @@ func NewRESTClient(config RestConfigClient) (*perseshttp.RESTClient, error) {
 	}
 	var httpClient *http.Client
 
-	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
-		Transport: roundTripper,
-		Timeout:   connectionTimeout,
-	})
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpclient.NewHTTPClient(
+		httpclient.WithTransport(roundTripper),
+		httpclient.WithTimeout(connectionTimeout),
+	))
 	if config.BasicAuth != nil {
 		c := oauth2.Config{}
 		password, getPasswordErr := config.BasicAuth.GetPassword()
@@ -183,10 +184,10 @@ func NewRESTClient(config RestConfigClient) (*perseshttp.RESTClient, error) {
 	}
 
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Transport: roundTripper,
-			Timeout:   connectionTimeout,
-		}
+		httpClient = httpclient.NewHTTPClient(
+			httpclient.WithTransport(roundTripper),
+			httpclient.WithTimeout(connectionTimeout),
+		)
 	}
 
 	return &perseshttp.RESTClient{
@@ -35,6 +35,10 @@ func (c *client) Dashboard(_ string) v1.DashboardInterface {
 	return &dashboard{}
 }
 
+func (c *client) DashboardTemplate(_ string) v1.DashboardTemplateInterface {
+	return &dashboardTemplate{}
+}
+
 func (c *client) EphemeralDashboard(_ string) v1.EphemeralDashboardInterface {
 	return &ephemeralDashboard{}
 }
@@ -37,12 +37,14 @@ func (d *dashboard) Get(name string) (*modelV1.Dashboard, error) {
 	return &modelV1.Dashboard{
 
 		Kind: modelV1.KindDashboard,
-		Metadata: modelV1.ProjectMetadata{
-			Metadata: modelV1.Metadata{
-				Name: name,
-			},
-			ProjectMetadataWrapper: modelV1.ProjectMetadataWrapper{
-				Project: d.project,
+		Metadata: modelV1.DashboardMetadata{
+			ProjectMetadata: modelV1.ProjectMetadata{
+				Metadata: modelV1.Metadata{
+					Name: name,
+				},
+				ProjectMetadataWrapper: modelV1.ProjectMetadataWrapper{
+					Project: d.project,
+				},
 			},
 		},
 		Spec: dashboardSpec.Spec{},
@@ -0,0 +1,39 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakev1
+
+import (
+	v1 "github.com/perses/perses/pkg/client/api/v1"
+	modelV1 "github.com/perses/perses/pkg/model/api/v1"
+)
+
+type dashboardTemplate struct {
+	v1.DashboardTemplateInterface
+}
+
+func (e *dashboardTemplate) Create(entity *modelV1.DashboardTemplate) (*modelV1.DashboardTemplate, error) {
+	return entity, nil
+}
+func (e *dashboardTemplate) Update(entity *modelV1.DashboardTemplate) (*modelV1.DashboardTemplate, error) {
+	return entity, nil
+}
+func (e *dashboardTemplate) Delete(_ string) error {
+	return nil
+}
+func (e *dashboardTemplate) Get(_ string) (*modelV1.DashboardTemplate, error) {
+	return nil, nil
+}
+func (e *dashboardTemplate) List(_ string) ([]*modelV1.DashboardTemplate, error) {
+	return make([]*modelV1.DashboardTemplate, 0), nil
+}
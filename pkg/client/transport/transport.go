@@ -17,6 +17,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/perses/perses/internal/httpclient"
 	"github.com/perses/perses/pkg/client/api/auth"
 	"github.com/perses/perses/pkg/client/perseshttp"
 	modelAPI "github.com/perses/perses/pkg/model/api"
@@ -62,7 +63,7 @@ func (t *tokenManager) getToken() (*oauth2.Token, error) {
 func New(baseURL *common.URL, base http.RoundTripper, nativeAuth modelAPI.Auth) http.RoundTripper {
 	client := auth.New(&perseshttp.RESTClient{
 		BaseURL: baseURL,
-		Client:  &http.Client{Transport: base},
+		Client:  httpclient.NewHTTPClient(httpclient.WithTransport(base)),
 	})
 	return &transport{
 		tokenManager: &tokenManager{authClient: client, auth: nativeAuth},
@@ -0,0 +1,56 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AnnotationQueryRequest is the request used to get the events of an annotation of a dashboard over a time range.
+type AnnotationQueryRequest struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (a *AnnotationQueryRequest) UnmarshalJSON(data []byte) error {
+	var tmp AnnotationQueryRequest
+	type plain AnnotationQueryRequest
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*a = tmp
+	return nil
+}
+
+func (a *AnnotationQueryRequest) validate() error {
+	if a.Start.IsZero() || a.End.IsZero() {
+		return errors.New("start and end of the time range are mandatory")
+	}
+	if !a.End.After(a.Start) {
+		return errors.New("end of the time range must be after its start")
+	}
+	return nil
+}
+
+// AnnotationEvent is an event returned by an annotation query.
+type AnnotationEvent struct {
+	// Timestamp is the time of the event, in milliseconds
+	Timestamp int64  `json:"timestamp"`
+	Label     string `json:"label"`
+}
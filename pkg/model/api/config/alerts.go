@@ -0,0 +1,45 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type WebhookAlert struct {
+	// WebhookURL is the URL the alert is sent to with a POST request.
+	WebhookURL string `json:"webhook_url" yaml:"webhook_url"`
+	// SlackChannel is the channel to post the alert in, when the webhook is a Slack incoming webhook.
+	SlackChannel string `json:"slack_channel,omitempty" yaml:"slack_channel,omitempty"`
+}
+
+func (w *WebhookAlert) Verify() error {
+	if len(w.WebhookURL) == 0 {
+		return fmt.Errorf("webhook_url is required when an alert is configured")
+	}
+	u, err := url.Parse(w.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook_url %q: %w", w.WebhookURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook_url %q: the scheme must be http or https", w.WebhookURL)
+	}
+	return nil
+}
+
+type Alerts struct {
+	// PluginExtractionFailure is the alert sent when a plugin archive cannot be extracted.
+	PluginExtractionFailure *WebhookAlert `json:"plugin_extraction_failure,omitempty" yaml:"plugin_extraction_failure,omitempty"`
+}
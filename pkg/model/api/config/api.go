@@ -0,0 +1,126 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/perses/spec/go/common"
+)
+
+const (
+	DefaultMaxQueryTimeRange       = 30 * 24 * time.Hour
+	DefaultGracefulShutdownTimeout = 10 * time.Second
+	// maxGracefulShutdownTimeout is the time the HTTP server waits for the requests to end when it stops.
+	maxGracefulShutdownTimeout = 30 * time.Second
+)
+
+type API struct {
+	// DeprecatedEndpoints maps the path of a deprecated endpoint to the path of the endpoint replacing it.
+	// A request on a deprecated path (or on a sub-path of it) is served by the new endpoint,
+	// and the response tells the client the path is deprecated.
+	// Paths must include the API prefix if one is configured.
+	// Example: "/api/v1/old": "/api/v1/new"
+	DeprecatedEndpoints map[string]string `json:"deprecated_endpoints,omitempty" yaml:"deprecated_endpoints,omitempty"`
+	// DeprecatedEndpointsSunset is the date after which the deprecated endpoints are no longer served.
+	// Once reached, the deprecated paths answer with a 410 Gone status.
+	// If not set, the deprecated endpoints are served indefinitely.
+	DeprecatedEndpointsSunset *time.Time `json:"deprecated_endpoints_sunset,omitempty" yaml:"deprecated_endpoints_sunset,omitempty"`
+	// MinRefreshInterval is the lowest refresh interval a dashboard can define.
+	// Saving a dashboard with a lower refresh interval is rejected. Leave empty to accept any refresh interval.
+	MinRefreshInterval common.Duration `json:"min_refresh_interval,omitempty" yaml:"min_refresh_interval,omitempty"`
+	// MaxQueryTimeRange is the longest time range (end - start) a query sent through the datasource proxy can cover.
+	// A query covering a longer time range is rejected.
+	MaxQueryTimeRange common.Duration `json:"max_query_time_range,omitempty" yaml:"max_query_time_range,omitempty"`
+	// MaxQueryTimeRangeOverrides replaces MaxQueryTimeRange for some datasources.
+	// The key is "<project>/<datasource>" for a datasource defined in a project or in a dashboard, and "<datasource>"
+	// for a global datasource.
+	MaxQueryTimeRangeOverrides map[string]common.Duration `json:"max_query_time_range_overrides,omitempty" yaml:"max_query_time_range_overrides,omitempty"`
+	// AutoTLSRedirect, when TLS is configured, redirects permanently the requests received over HTTP to HTTPS.
+	// The health check endpoint is never redirected.
+	AutoTLSRedirect bool `json:"auto_tls_redirect,omitempty" yaml:"auto_tls_redirect,omitempty"`
+	// GracefulShutdownTimeout is the time given to the clients to close their Server-Sent Events streams when the server stops.
+	// The streams still open after this delay are closed by the server.
+	GracefulShutdownTimeout common.Duration `json:"graceful_shutdown_timeout,omitempty" yaml:"graceful_shutdown_timeout,omitempty"`
+	// EnableQueryInspector records every query sent to a saved datasource through the proxy in the database, so the
+	// administrators can list them with the endpoint /api/admin/query-audit.
+	EnableQueryInspector bool `json:"enable_query_inspector,omitempty" yaml:"enable_query_inspector,omitempty"`
+	// MIMETypeOverrides maps a file extension to the MIME type used to serve the static files (UI and plugins) having it.
+	// The extensions not listed here use the default MIME type of the system.
+	// Example: ".wasm": "application/wasm"
+	MIMETypeOverrides map[string]string `json:"mime_type_overrides,omitempty" yaml:"mime_type_overrides,omitempty"`
+}
+
+// GetMaxQueryTimeRange returns the longest time range a query can cover for the given datasource.
+// The project is empty for a global datasource.
+func (a *API) GetMaxQueryTimeRange(project, datasource string) time.Duration {
+	key := datasource
+	if len(project) > 0 {
+		key = project + "/" + datasource
+	}
+	if override, ok := a.MaxQueryTimeRangeOverrides[key]; ok {
+		return time.Duration(override)
+	}
+	return time.Duration(a.MaxQueryTimeRange)
+}
+
+func (a *API) Verify() error {
+	for oldPath, newPath := range a.DeprecatedEndpoints {
+		if !strings.HasPrefix(oldPath, "/") {
+			return fmt.Errorf("deprecated endpoint %q must start with a '/'", oldPath)
+		}
+		if !strings.HasPrefix(newPath, "/") {
+			return fmt.Errorf("the endpoint replacing %q must start with a '/', got %q", oldPath, newPath)
+		}
+		if oldPath == newPath {
+			return fmt.Errorf("deprecated endpoint %q cannot be replaced by itself", oldPath)
+		}
+	}
+	if a.MaxQueryTimeRange <= 0 {
+		a.MaxQueryTimeRange = common.Duration(DefaultMaxQueryTimeRange)
+	}
+	for key, override := range a.MaxQueryTimeRangeOverrides {
+		if override <= 0 {
+			return fmt.Errorf("the max query time range of the datasource %q must be positive", key)
+		}
+	}
+	if a.GracefulShutdownTimeout <= 0 {
+		a.GracefulShutdownTimeout = common.Duration(DefaultGracefulShutdownTimeout)
+	}
+	if time.Duration(a.GracefulShutdownTimeout) > maxGracefulShutdownTimeout {
+		return fmt.Errorf("graceful_shutdown_timeout cannot exceed %s, the time the HTTP server waits for the requests to end", maxGracefulShutdownTimeout)
+	}
+	if len(a.MIMETypeOverrides) > 0 {
+		overrides := make(map[string]string, len(a.MIMETypeOverrides))
+		for ext, mimeType := range a.MIMETypeOverrides {
+			if len(ext) == 0 || ext == "." {
+				return fmt.Errorf("the extension overridden by the MIME type %q cannot be empty", mimeType)
+			}
+			if _, _, err := mime.ParseMediaType(mimeType); err != nil {
+				return fmt.Errorf("invalid MIME type %q for the extension %q: %w", mimeType, ext, err)
+			}
+			// Extensions are compared in lower case and with their leading dot, like filepath.Ext returns them.
+			ext = strings.ToLower(ext)
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			overrides[ext] = mimeType
+		}
+		a.MIMETypeOverrides = overrides
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIGetMaxQueryTimeRange(t *testing.T) {
+	api := API{
+		MaxQueryTimeRangeOverrides: map[string]common.Duration{
+			"perses/thanos": common.Duration(365 * 24 * time.Hour),
+			"prometheus":    common.Duration(7 * 24 * time.Hour),
+		},
+	}
+	require.NoError(t, api.Verify())
+	assert.Equal(t, DefaultMaxQueryTimeRange, api.GetMaxQueryTimeRange("perses", "prometheus"))
+	assert.Equal(t, 365*24*time.Hour, api.GetMaxQueryTimeRange("perses", "thanos"))
+	assert.Equal(t, 7*24*time.Hour, api.GetMaxQueryTimeRange("", "prometheus"))
+	assert.Equal(t, DefaultMaxQueryTimeRange, api.GetMaxQueryTimeRange("", "thanos"))
+}
+
+func TestAPIVerifyGracefulShutdownTimeout(t *testing.T) {
+	api := API{}
+	require.NoError(t, api.Verify())
+	assert.Equal(t, common.Duration(DefaultGracefulShutdownTimeout), api.GracefulShutdownTimeout)
+
+	api = API{GracefulShutdownTimeout: common.Duration(time.Minute)}
+	assert.EqualError(t, api.Verify(), "graceful_shutdown_timeout cannot exceed 30s, the time the HTTP server waits for the requests to end")
+}
+
+func TestAPIVerifyMIMETypeOverrides(t *testing.T) {
+	api := API{MIMETypeOverrides: map[string]string{
+		"wasm":  "application/wasm",
+		".MJS":  "text/javascript; charset=utf-8",
+		".json": "application/json",
+	}}
+	require.NoError(t, api.Verify())
+	assert.Equal(t, map[string]string{
+		".wasm": "application/wasm",
+		".mjs":  "text/javascript; charset=utf-8",
+		".json": "application/json",
+	}, api.MIMETypeOverrides)
+
+	api = API{MIMETypeOverrides: map[string]string{".js": "not a mime type"}}
+	assert.Error(t, api.Verify())
+
+	api = API{MIMETypeOverrides: map[string]string{".": "text/plain"}}
+	assert.EqualError(t, api.Verify(), `the extension overridden by the MIME type "text/plain" cannot be empty`)
+}
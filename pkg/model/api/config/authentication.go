@@ -29,6 +29,8 @@ const (
 	DefaultAccessTokenTTL  = time.Minute * 15
 	DefaultRefreshTokenTTL = time.Hour * 24
 	DefaultProviderTimeout = time.Minute * 1
+	// DefaultTokenRefreshBeforeExpiry is the remaining lifetime of the provider's token under which it is refreshed.
+	DefaultTokenRefreshBeforeExpiry = time.Minute * 1
 )
 
 type OAuthOverride struct {
@@ -38,6 +40,12 @@ type OAuthOverride struct {
 	Scopes           []string      `json:"scopes" yaml:"scopes"`
 }
 
+func (o OAuthOverride) Sanitize() OAuthOverride {
+	o.ClientID = redactHidden(o.ClientID)
+	o.ClientSecret = redactHidden(o.ClientSecret)
+	return o
+}
+
 func (o *OAuthOverride) Verify() error {
 	if len(o.ClientSecret) > 0 && len(o.ClientSecretFile) > 0 {
 		return errors.New("only one of `client_secret` or `client_secret_file` can be set")
@@ -88,6 +96,11 @@ func (h HTTP) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (h HTTP) Sanitize() HTTP {
+	h.TLSConfig = sanitizeTLSConfig(h.TLSConfig)
+	return h
+}
+
 func (h *HTTP) Verify() error {
 	if h.Timeout == 0 {
 		h.Timeout = common.Duration(DefaultProviderTimeout)
@@ -108,6 +121,15 @@ type Provider struct {
 	HTTP              HTTP           `json:"http" yaml:"http"`
 }
 
+func (p Provider) Sanitize() Provider {
+	p.ClientID = redactHidden(p.ClientID)
+	p.ClientSecret = redactHidden(p.ClientSecret)
+	p.DeviceCode = sanitizePointer(p.DeviceCode)
+	p.ClientCredentials = sanitizePointer(p.ClientCredentials)
+	p.HTTP = p.HTTP.Sanitize()
+	return p
+}
+
 func (p *Provider) Verify() error {
 	if p.SlugID == "" {
 		return errors.New("provider's `slug_id` is mandatory")
@@ -138,15 +160,57 @@ type K8sAuthnProvider struct {
 type OIDCLogout struct {
 	Enabled                 bool   `json:"enabled" yaml:"enabled"`
 	LogoutRedirectParamName string `json:"logout_redirect_param_name,omitempty" yaml:"logout_redirect_param_name,omitempty"`
+	// TokenRefreshEnabled makes Perses keep the tokens delivered by the provider and refresh them when the user
+	// refreshes its session, so the session ends as soon as the provider refuses to refresh the token.
+	TokenRefreshEnabled bool `json:"token_refresh_enabled,omitempty" yaml:"token_refresh_enabled,omitempty"`
+	// TokenRefreshBeforeExpiry is the remaining lifetime of the provider's token under which it is refreshed.
+	TokenRefreshBeforeExpiry common.Duration `json:"token_refresh_before_expiry,omitempty" yaml:"token_refresh_before_expiry,omitempty"`
+	// BackChannelEnabled lets the provider end the sessions of its users by sending a logout token to Perses,
+	// as described by the OpenID Connect Back-Channel Logout specification.
+	BackChannelEnabled bool `json:"back_channel_enabled,omitempty" yaml:"back_channel_enabled,omitempty"`
+}
+
+func (l *OIDCLogout) Verify() error {
+	if l.TokenRefreshEnabled && l.TokenRefreshBeforeExpiry == 0 {
+		l.TokenRefreshBeforeExpiry = common.Duration(DefaultTokenRefreshBeforeExpiry)
+	}
+	return nil
+}
+
+// ClaimMapping grants the global role Role to the users whose ID token has the claim Claim equal to Value.
+// When the claim is a list, like `groups`, the role is granted if one of its items is equal to Value.
+type ClaimMapping struct {
+	Claim string `json:"claim" yaml:"claim"`
+	Value string `json:"value" yaml:"value"`
+	Role  string `json:"role" yaml:"role"`
+}
+
+func (c *ClaimMapping) Verify() error {
+	if len(c.Claim) == 0 {
+		return errors.New("claims mapping's `claim` is mandatory")
+	}
+	if len(c.Value) == 0 {
+		return errors.New("claims mapping's `value` is mandatory")
+	}
+	if len(c.Role) == 0 {
+		return errors.New("claims mapping's `role` is mandatory")
+	}
+	return nil
 }
 
 type OIDCProvider struct {
-	Provider     `json:",inline" yaml:",inline"`
-	Issuer       common.URL        `json:"issuer" yaml:"issuer"`
-	DiscoveryURL common.URL        `json:"discovery_url,omitempty" yaml:"discovery_url,omitempty"`
-	URLParams    map[string]string `json:"url_params,omitempty" yaml:"url_params,omitempty"`
-	DisablePKCE  bool              `json:"disable_pkce" yaml:"disable_pkce"`
-	Logout       OIDCLogout        `json:"logout" yaml:"logout"`
+	Provider      `json:",inline" yaml:",inline"`
+	Issuer        common.URL        `json:"issuer" yaml:"issuer"`
+	DiscoveryURL  common.URL        `json:"discovery_url,omitempty" yaml:"discovery_url,omitempty"`
+	URLParams     map[string]string `json:"url_params,omitempty" yaml:"url_params,omitempty"`
+	DisablePKCE   bool              `json:"disable_pkce" yaml:"disable_pkce"`
+	Logout        OIDCLogout        `json:"logout" yaml:"logout"`
+	ClaimsMapping []ClaimMapping    `json:"claims_mapping,omitempty" yaml:"claims_mapping,omitempty"`
+}
+
+func (p OIDCProvider) Sanitize() OIDCProvider {
+	p.Provider = p.Provider.Sanitize()
+	return p
 }
 
 func (p *OIDCProvider) Verify() error {
@@ -165,6 +229,11 @@ type OAuthProvider struct {
 	CustomLoginProperty string     `json:"custom_login_property,omitempty" yaml:"custom_login_property,omitempty"`
 }
 
+func (p OAuthProvider) Sanitize() OAuthProvider {
+	p.Provider = p.Provider.Sanitize()
+	return p
+}
+
 func (p *OAuthProvider) Verify() error {
 	if p.AuthURL.IsNilOrEmpty() {
 		return errors.New("provider's `auth_url` is mandatory")
@@ -178,12 +247,37 @@ func (p *OAuthProvider) Verify() error {
 	return nil
 }
 
+// CustomProvider references an authentication provider registered in the Go code through the plugin API
+// (see github.com/perses/perses/pkg/plugin/api.RegisterAuthProvider).
+type CustomProvider struct {
+	SlugID string `json:"slug_id" yaml:"slug_id"`
+	Name   string `json:"name" yaml:"name"`
+}
+
+func (p *CustomProvider) Verify() error {
+	if p.SlugID == "" {
+		return errors.New("provider's `slug_id` is mandatory")
+	}
+	if p.Name == "" {
+		return errors.New("provider's `name` is mandatory")
+	}
+	return nil
+}
+
 type AuthenticationProviders struct {
 	EnableNative bool `json:"enable_native" yaml:"enable_native"`
 	// +optional
 	KubernetesProvider K8sAuthnProvider `json:"kubernetes,omitzero" yaml:"kubernetes,omitempty"`
 	OAuth              []OAuthProvider  `json:"oauth,omitempty" yaml:"oauth,omitempty"`
 	OIDC               []OIDCProvider   `json:"oidc,omitempty" yaml:"oidc,omitempty"`
+	// Custom is the list of authentication providers implemented through the plugin API.
+	Custom []CustomProvider `json:"custom,omitempty" yaml:"custom,omitempty"`
+}
+
+func (p AuthenticationProviders) Sanitize() AuthenticationProviders {
+	p.OAuth = sanitizeList(p.OAuth)
+	p.OIDC = sanitizeList(p.OIDC)
+	return p
 }
 
 func (p *AuthenticationProviders) Verify() error {
@@ -203,6 +297,14 @@ func (p *AuthenticationProviders) Verify() error {
 			return fmt.Errorf("several OAuth providers exist with the same slug_id %q", prov.SlugID)
 		}
 	}
+	var tmpCustomSlugIDs []string
+	for _, prov := range p.Custom {
+		var ok bool
+		tmpCustomSlugIDs, ok = appendIfMissing(tmpCustomSlugIDs, prov.SlugID)
+		if !ok {
+			return fmt.Errorf("several custom providers exist with the same slug_id %q", prov.SlugID)
+		}
+	}
 	return nil
 }
 
@@ -220,6 +322,11 @@ type AuthenticationConfig struct {
 	Providers AuthenticationProviders `json:"providers" yaml:"providers"`
 }
 
+func (a AuthenticationConfig) Sanitize() AuthenticationConfig {
+	a.Providers = a.Providers.Sanitize()
+	return a
+}
+
 func (a *AuthenticationConfig) Verify() error {
 	if a.AccessTokenTTL == 0 {
 		a.AccessTokenTTL = common.Duration(DefaultAccessTokenTTL)
@@ -88,6 +88,28 @@ client_secret: "secretthing"
 	assert.Equal(t, "http://localhost:8181", c.Issuer.String())
 }
 
+// TestOIDCProvider_VerifyClaimsMapping makes sure every entry of the claims mapping is verified by the config Resolver
+func TestOIDCProvider_VerifyClaimsMapping(t *testing.T) {
+	testYamlInput := `
+slug_id: "azure"
+name: "Azure AD"
+client_id: "secretthing"
+issuer: "http://localhost:4200"
+claims_mapping:
+  - claim: "groups"
+    value: "sre"
+    role: "admin"
+  - claim: "groups"
+    value: "dev"
+`
+
+	err := config.NewResolver[OIDCProvider]().
+		SetConfigData([]byte(testYamlInput)).
+		Resolve(&OIDCProvider{}).
+		Verify()
+	assert.ErrorContains(t, err, "claims mapping's `role` is mandatory")
+}
+
 func TestAppendIfMissing(t *testing.T) {
 	slice := []string{"test1", "test2"}
 	slice, ok1 := appendIfMissing(slice, "test1")
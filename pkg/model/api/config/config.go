@@ -14,6 +14,7 @@
 package config
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -53,6 +54,8 @@ type Config struct {
 	// With this config, it will be served with the path <api_prefix>/api
 	// Example: "/perses"
 	APIPrefix string `json:"api_prefix,omitempty" yaml:"api_prefix,omitempty"`
+	// API contains the configuration about the behavior of the API endpoints.
+	API API `json:"api,omitempty" yaml:"api,omitempty"`
 	// Security contains any configuration that changes the API behavior like the endpoints exposed or if the permissions are activated.
 	Security Security `json:"security,omitempty" yaml:"security,omitempty"`
 	// Database contains the different configuration depending on the database you want to use
@@ -79,6 +82,19 @@ type Config struct {
 	Frontend Frontend `json:"frontend,omitempty" yaml:"frontend,omitempty"`
 	// Plugin contains the config for runtime plugins.
 	Plugin Plugin `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	// Alerts contains the configuration of the alerts Perses sends to notify its operators about a failure.
+	Alerts Alerts `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+	// Federation contains the list of peer Perses instances whose resources are aggregated by this one.
+	Federation Federation `json:"federation,omitempty" yaml:"federation,omitempty"`
+}
+
+// Sanitize returns a copy of the config without any credential, safe to be logged.
+func (c Config) Sanitize() Config {
+	c.Security = c.Security.Sanitize()
+	c.Database = c.Database.Sanitize()
+	c.Datasource = c.Datasource.Sanitize()
+	c.Federation = c.Federation.Sanitize()
+	return c
 }
 
 func (c *Config) Verify() error {
@@ -93,6 +109,16 @@ func (c *Config) Verify() error {
 	if c.Schemas != nil {
 		logrus.Warn("'schemas' is deprecated. Please remove it from your config")
 	}
+	if c.Database.Stateless != nil {
+		if len(c.Provisioning.Folders) > 0 {
+			return fmt.Errorf("provisioning cannot be used with the stateless database, the resources must be put in the folder of the stateless database")
+		}
+		if c.EphemeralDashboard.Enable {
+			return fmt.Errorf("ephemeral dashboards cannot be used with the stateless database")
+		}
+		// Nothing can be saved, so the endpoints modifying the resources are removed.
+		c.Security.Readonly = true
+	}
 	if len(c.APIPrefix) > 0 && !strings.HasPrefix(c.APIPrefix, "/") {
 		c.APIPrefix = "/" + c.APIPrefix
 	}
@@ -42,6 +42,7 @@ func TestJSONMarshalConfig(t *testing.T) {
 			title: "empty config",
 			cfg:   Config{},
 			jason: `{
+  "api": {},
   "security": {
     "readonly": false,
     "cookie": {
@@ -92,13 +93,19 @@ func TestJSONMarshalConfig(t *testing.T) {
   },
   "plugin": {
     "enable_dev": false
-  }
+  },
+  "alerts": {},
+  "federation": {}
 }`,
 		},
 		{
 			title: "default config",
 			cfg:   defaultConfig(),
 			jason: `{
+  "api": {
+    "max_query_time_range": "30d",
+    "graceful_shutdown_timeout": "10s"
+  },
   "security": {
     "readonly": false,
     "cookie": {
@@ -138,7 +145,9 @@ func TestJSONMarshalConfig(t *testing.T) {
     "project": {
       "disable": false
     },
-    "disable_local": false
+    "disable_local": false,
+    "variable_cache_ttl": "30s",
+    "idle_connection_ttl": "1m30s"
   },
   "variable": {
     "global": {
@@ -164,8 +173,11 @@ func TestJSONMarshalConfig(t *testing.T) {
     "archive_paths": [
       "plugins-archive"
     ],
+    "max_archive_size": 524288000,
     "enable_dev": false
-  }
+  },
+  "alerts": {},
+  "federation": {}
 }`,
 		},
 	}
@@ -270,6 +282,14 @@ func TestUnmarshalJSONConfig(t *testing.T) {
   }
 }`,
 			result: Config{
+				API: API{
+					MaxQueryTimeRange:       common.Duration(DefaultMaxQueryTimeRange),
+					GracefulShutdownTimeout: common.Duration(DefaultGracefulShutdownTimeout),
+				},
+				Datasource: DatasourceConfig{
+					VariableCacheTTL:  common.Duration(DefaultDatasourceVariableCacheTTL),
+					IdleConnectionTTL: common.Duration(DefaultDatasourceIdleConnectionTTL),
+				},
 				Security: Security{
 					Readonly:      false,
 					EncryptionKey: "=tW$56zytgB&3jN2E%7-+qrGZE?v6LCc",
@@ -319,6 +339,7 @@ func TestUnmarshalJSONConfig(t *testing.T) {
 						Folder:    "dev/local_db",
 						Extension: "json",
 					},
+					SlowQueryThreshold: common.Duration(defaultSlowQueryThreshold),
 				},
 				Frontend: Frontend{
 					ImportantDashboards: []dashboardSelector{
@@ -436,6 +457,14 @@ plugin:
   archive_path: "custom/plugins/archive"
 `,
 			result: Config{
+				API: API{
+					MaxQueryTimeRange:       common.Duration(DefaultMaxQueryTimeRange),
+					GracefulShutdownTimeout: common.Duration(DefaultGracefulShutdownTimeout),
+				},
+				Datasource: DatasourceConfig{
+					VariableCacheTTL:  common.Duration(DefaultDatasourceVariableCacheTTL),
+					IdleConnectionTTL: common.Duration(DefaultDatasourceIdleConnectionTTL),
+				},
 				Security: Security{
 					Readonly: false,
 					Cookie: Cookie{
@@ -493,6 +522,7 @@ plugin:
 						Folder:    "dev/local_db",
 						Extension: "json",
 					},
+					SlowQueryThreshold: common.Duration(defaultSlowQueryThreshold),
 				},
 				Frontend: Frontend{
 					ImportantDashboards: []dashboardSelector{
@@ -512,8 +542,9 @@ plugin:
 					Information: "# Hello World\n## File Database setup",
 				},
 				Plugin: Plugin{
-					Path:         "custom/plugins",
-					ArchivePaths: []string{"custom/plugins/archive"},
+					Path:           "custom/plugins",
+					ArchivePaths:   []string{"custom/plugins/archive"},
+					MaxArchiveSize: DefaultPluginMaxArchiveSize,
 				},
 				Provisioning: ProvisioningConfig{
 					Folders: []string{
@@ -521,6 +552,10 @@ plugin:
 					},
 					Interval: common.Duration(defaultInterval),
 				},
+				Dashboard: DashboardConfig{
+					TrashRetentionDuration: common.Duration(DefaultTrashRetentionDuration),
+					TrashCleanupInterval:   common.Duration(DefaultTrashCleanupInterval),
+				},
 				EphemeralDashboard: EphemeralDashboard{
 					Enable:          false,
 					CleanupInterval: common.Duration(2 * time.Hour),
@@ -22,6 +22,7 @@ import (
 	"github.com/PaesslerAG/gval"
 	"github.com/PaesslerAG/jsonpath"
 	"github.com/google/cel-go/cel"
+	"github.com/perses/spec/go/common"
 )
 
 type CustomLintRule struct {
@@ -114,11 +115,34 @@ func (c *CustomLintRule) evaluateAndLoadCELExpression() error {
 	return nil
 }
 
+const (
+	DefaultTrashRetentionDuration = 30 * 24 * time.Hour
+	DefaultTrashCleanupInterval   = time.Hour
+)
+
 type DashboardConfig struct {
 	CustomLintRules []*CustomLintRule `json:"custom_lint_rules,omitempty" yaml:"custom_lint_rules,omitempty"`
+	// TrashRetentionDuration is how long a deleted dashboard is kept in the trash of its project, where it can be
+	// restored, before being permanently deleted.
+	TrashRetentionDuration common.Duration `json:"trash_retention_duration,omitempty" yaml:"trash_retention_duration,omitempty"`
+	// TrashCleanupInterval is the interval at which the dashboards kept in the trash for longer than
+	// TrashRetentionDuration are permanently deleted.
+	TrashCleanupInterval common.Duration `json:"trash_cleanup_interval,omitempty" yaml:"trash_cleanup_interval,omitempty"`
+	// MaxHistoryVersions is the number of versions kept in the history of each dashboard. When a new version is
+	// recorded, the oldest ones beyond this number are deleted. 0 keeps every version.
+	MaxHistoryVersions int `json:"max_history_versions,omitempty" yaml:"max_history_versions,omitempty"`
 }
 
 func (c *DashboardConfig) Verify() error {
+	if c.TrashRetentionDuration <= 0 {
+		c.TrashRetentionDuration = common.Duration(DefaultTrashRetentionDuration)
+	}
+	if c.TrashCleanupInterval <= 0 {
+		c.TrashCleanupInterval = common.Duration(DefaultTrashCleanupInterval)
+	}
+	if c.MaxHistoryVersions < 0 {
+		return fmt.Errorf("max_history_versions cannot be negative")
+	}
 	ruleName := make(map[string]struct{})
 	for _, rule := range c.CustomLintRules {
 		if _, ok := ruleName[rule.Name]; ok {
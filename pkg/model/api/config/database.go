@@ -23,7 +23,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const defaultFileDBFolder = "./local_db"
+const (
+	defaultFileDBFolder       = "./local_db"
+	defaultSlowQueryThreshold = 500 * time.Millisecond
+)
 
 type FileExtension string
 
@@ -110,6 +113,14 @@ type SQL struct {
 	CaseSensitive  bool `json:"case_sensitive" yaml:"case_sensitive"`
 }
 
+func (s SQL) Sanitize() SQL {
+	s.TLSConfig = sanitizePublicTLSConfig(s.TLSConfig)
+	s.User = redactHidden(s.User)
+	s.Password = redactHidden(s.Password)
+	s.Addr = redactHidden(s.Addr)
+	return s
+}
+
 func (s *SQL) Verify() error {
 	if len(s.DBName) == 0 {
 		return fmt.Errorf("db_name must be specified")
@@ -151,13 +162,38 @@ func (s *SQL) Verify() error {
 	return nil
 }
 
+// Stateless is a database that doesn't persist anything. The resources are loaded at startup from the given folder,
+// and Perses is started in readonly mode.
+type Stateless struct {
+	// Folder contains the resources to serve, in JSON or YAML. The folder is read recursively and never modified.
+	Folder string `json:"folder" yaml:"folder"`
+	// +kubebuilder:validation:Optional
+	CaseSensitive bool `json:"case_sensitive" yaml:"case_sensitive"`
+}
+
+func (s *Stateless) Verify() error {
+	if len(s.Folder) == 0 {
+		return fmt.Errorf("the folder of the stateless database must be specified")
+	}
+	return nil
+}
+
 type Database struct {
-	File *File `json:"file,omitempty" yaml:"file,omitempty"`
-	SQL  *SQL  `json:"sql,omitempty" yaml:"sql,omitempty"`
+	File      *File      `json:"file,omitempty" yaml:"file,omitempty"`
+	SQL       *SQL       `json:"sql,omitempty" yaml:"sql,omitempty"`
+	Stateless *Stateless `json:"stateless,omitempty" yaml:"stateless,omitempty"`
+	// SlowQueryThreshold is the duration above which a database operation is logged as slow.
+	// Default value is 500ms.
+	SlowQueryThreshold common.Duration `json:"slow_query_threshold,omitempty" yaml:"slow_query_threshold,omitempty"`
+}
+
+func (d Database) Sanitize() Database {
+	d.SQL = sanitizePointer(d.SQL)
+	return d
 }
 
 func (d *Database) Verify() error {
-	if d.File == nil && d.SQL == nil {
+	if d.File == nil && d.SQL == nil && d.Stateless == nil {
 		logrus.Debug("no database has been specified, therefore a file system database is used")
 		d.File = &File{
 			Folder: defaultFileDBFolder,
@@ -166,5 +202,11 @@ func (d *Database) Verify() error {
 	if d.File != nil && d.SQL != nil {
 		return fmt.Errorf("you cannot tel to Perses to use SQL and the filesystem at the same time")
 	}
+	if d.Stateless != nil && (d.File != nil || d.SQL != nil) {
+		return fmt.Errorf("the stateless database cannot be used with another database")
+	}
+	if d.SlowQueryThreshold <= 0 {
+		d.SlowQueryThreshold = common.Duration(defaultSlowQueryThreshold)
+	}
 	return nil
 }
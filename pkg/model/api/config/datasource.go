@@ -13,7 +13,18 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/perses/spec/go/common"
+)
+
+const (
+	DefaultDatasourceVariableCacheTTL  = 30 * time.Second
+	DefaultDatasourceIdleConnectionTTL = 90 * time.Second
+)
 
 type GlobalDatasourceConfig struct {
 	// Disable is used to disable the global datasource feature.
@@ -26,6 +37,11 @@ type GlobalDatasourceConfig struct {
 	Discovery []GlobalDatasourceDiscovery `json:"discovery,omitempty" yaml:"discovery,omitempty"`
 }
 
+func (c GlobalDatasourceConfig) Sanitize() GlobalDatasourceConfig {
+	c.Discovery = sanitizeList(c.Discovery)
+	return c
+}
+
 func (c *GlobalDatasourceConfig) Verify() error {
 	if c.Disable && len(c.Discovery) > 0 {
 		return fmt.Errorf("the global datasource is disabled, you cannot use the discovery feature")
@@ -39,10 +55,88 @@ type ProjectDatasourceConfig struct {
 	Disable bool `json:"disable" yaml:"disable"`
 }
 
+// DatasourceTransform applies a TransformPlugin to the responses of the datasources of a given kind, when they go through the proxy.
+type DatasourceTransform struct {
+	// DatasourceKind is the kind of the datasource plugin the transform applies to, e.g. PrometheusDatasource.
+	DatasourceKind string `json:"datasource_kind" yaml:"datasource_kind"`
+	// Plugin is the name the TransformPlugin has been registered with.
+	Plugin string `json:"plugin" yaml:"plugin"`
+	// Config is given to the TransformPlugin, encoded in JSON.
+	Config map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+func (t *DatasourceTransform) Verify() error {
+	if len(t.DatasourceKind) == 0 {
+		return fmt.Errorf("datasource_kind of a datasource transform cannot be empty")
+	}
+	if len(t.Plugin) == 0 {
+		return fmt.Errorf("plugin of the datasource transform for %q cannot be empty", t.DatasourceKind)
+	}
+	return nil
+}
+
+// DatasourceClientTLS is the client certificate the HTTP proxy presents to the datasources requiring mutual TLS.
+type DatasourceClientTLS struct {
+	// CertFile is the path to the PEM encoded client certificate.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	// KeyFile is the path to the PEM encoded private key of the client certificate.
+	KeyFile string `json:"key_file" yaml:"key_file"`
+	// CAFile is the path to the PEM encoded CA certificates used to verify the certificate of the datasources.
+	CAFile string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+}
+
+func (c *DatasourceClientTLS) Verify() error {
+	if len(c.CertFile) == 0 || len(c.KeyFile) == 0 {
+		return fmt.Errorf("datasource.client_tls requires both cert_file and key_file")
+	}
+	return nil
+}
+
 type DatasourceConfig struct {
 	Global  GlobalDatasourceConfig  `json:"global" yaml:"global"`
 	Project ProjectDatasourceConfig `json:"project" yaml:"project"`
 	// DisableLocal when used is preventing the possibility to add a datasource directly in the dashboard spec.
 	// It will also disable the associated proxy.
 	DisableLocal bool `json:"disable_local" yaml:"disable_local"`
+	// DeduplicateQueries when used is sending only once to the datasource the identical queries received at the same time.
+	// The response is then shared with every client that sent the query.
+	DeduplicateQueries bool `json:"deduplicate_queries,omitempty" yaml:"deduplicate_queries,omitempty"`
+	// VariableCacheTTL is how long the list of datasources returned to resolve a DatasourceVariable is kept in memory.
+	VariableCacheTTL common.Duration `json:"variable_cache_ttl,omitempty" yaml:"variable_cache_ttl,omitempty"`
+	// IdleConnectionTTL is how long the proxy keeps the connections to a datasource open while they are not used.
+	IdleConnectionTTL common.Duration `json:"idle_connection_ttl,omitempty" yaml:"idle_connection_ttl,omitempty"`
+	// Transforms are applied, in order, to the responses of the datasources received by the HTTP proxy.
+	Transforms []DatasourceTransform `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+	// AllowedProxyPaths are the glob patterns, like `/api/v1/label/*/values`, of the datasource paths that can be
+	// called through the passthrough endpoint of the project datasources. The passthrough is refused when empty.
+	AllowedProxyPaths []string `json:"allowed_proxy_paths,omitempty" yaml:"allowed_proxy_paths,omitempty"`
+	// ClientTLS is the client certificate presented by the HTTP proxy to the datasources. The certificate configured
+	// in the secret of a datasource takes precedence.
+	ClientTLS *DatasourceClientTLS `json:"client_tls,omitempty" yaml:"client_tls,omitempty"`
+}
+
+func (c DatasourceConfig) Sanitize() DatasourceConfig {
+	c.Global = c.Global.Sanitize()
+	return c
+}
+
+func (c *DatasourceConfig) Verify() error {
+	if c.VariableCacheTTL < 0 {
+		return fmt.Errorf("datasource.variable_cache_ttl cannot be negative")
+	}
+	if c.VariableCacheTTL == 0 {
+		c.VariableCacheTTL = common.Duration(DefaultDatasourceVariableCacheTTL)
+	}
+	if c.IdleConnectionTTL < 0 {
+		return fmt.Errorf("datasource.idle_connection_ttl cannot be negative")
+	}
+	if c.IdleConnectionTTL == 0 {
+		c.IdleConnectionTTL = common.Duration(DefaultDatasourceIdleConnectionTTL)
+	}
+	for _, pattern := range c.AllowedProxyPaths {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q in datasource.allowed_proxy_paths: %w", pattern, err)
+		}
+	}
+	return nil
 }
@@ -28,6 +28,32 @@ type HTTPDiscovery struct {
 	config.RestConfigClient `json:",inline" yaml:",inline"`
 }
 
+func (d HTTPDiscovery) Sanitize() HTTPDiscovery {
+	client := d.RestConfigClient
+	if client.NativeAuth != nil {
+		nativeAuth := *client.NativeAuth
+		nativeAuth.Password = redactString(nativeAuth.Password)
+		client.NativeAuth = &nativeAuth
+	}
+	if client.OAuth != nil {
+		oauth := *client.OAuth
+		oauth.ClientSecret = redactString(oauth.ClientSecret)
+		client.OAuth = &oauth
+	}
+	if client.BasicAuth != nil {
+		basicAuth := *client.BasicAuth
+		basicAuth.Password = redactString(basicAuth.Password)
+		client.BasicAuth = &basicAuth
+	}
+	if client.Authorization != nil {
+		authorization := *client.Authorization
+		authorization.Credentials = redactString(authorization.Credentials)
+		client.Authorization = &authorization
+	}
+	client.TLSConfig = sanitizeTLSConfig(client.TLSConfig)
+	return HTTPDiscovery{RestConfigClient: client}
+}
+
 func (d HTTPDiscovery) MarshalYAML() (any, error) {
 	cfg := config.NewPublicRestConfigClient(&d.RestConfigClient)
 	return cfg, nil
@@ -102,6 +128,11 @@ type GlobalDatasourceDiscovery struct {
 	KubernetesDiscovery *KubernetesDiscovery `json:"kubernetes_sd,omitempty" yaml:"kubernetes_sd,omitempty"`
 }
 
+func (g GlobalDatasourceDiscovery) Sanitize() GlobalDatasourceDiscovery {
+	g.HTTPDiscovery = sanitizePointer(g.HTTPDiscovery)
+	return g
+}
+
 func (g *GlobalDatasourceDiscovery) Verify() error {
 	if len(g.Name) == 0 {
 		return fmt.Errorf("global datasource discovery name is empty")
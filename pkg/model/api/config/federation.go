@@ -0,0 +1,110 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/perses/spec/go/common"
+)
+
+const (
+	DefaultFederationTimeout          = 10 * time.Second
+	DefaultFederationFailureThreshold = 3
+	DefaultFederationOpenDuration     = 30 * time.Second
+)
+
+type PeerConfig struct {
+	// Name identifies the peer in the aggregated resources. By default, it is the host of the URL.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// URL is the URL of the peer Perses, including its API prefix if any.
+	URL string `json:"url" yaml:"url"`
+	// Token is sent as a bearer token to authenticate the requests made to the peer.
+	Token secret.Hidden `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+func (p PeerConfig) Sanitize() PeerConfig {
+	p.Token = redactHidden(p.Token)
+	return p
+}
+
+func (p *PeerConfig) Verify() error {
+	if len(p.URL) == 0 {
+		return fmt.Errorf("url is required for a federation peer")
+	}
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return fmt.Errorf("invalid federation peer url %q: %w", p.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid federation peer url %q: the scheme must be http or https", p.URL)
+	}
+	p.URL = strings.TrimSuffix(p.URL, "/")
+	if len(p.Name) == 0 {
+		p.Name = u.Host
+	}
+	if strings.Contains(p.Name, "/") {
+		return fmt.Errorf("invalid federation peer name %q: it cannot contain '/'", p.Name)
+	}
+	return nil
+}
+
+type Federation struct {
+	// Peers is the list of Perses instances whose resources are aggregated by this one.
+	Peers []PeerConfig `json:"peers,omitempty" yaml:"peers,omitempty"`
+	// Timeout is the maximum duration of a request made to a peer.
+	Timeout common.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// FailureThreshold is the number of consecutive failed requests after which a peer is no longer requested
+	// during OpenDuration.
+	FailureThreshold int `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+	// OpenDuration is the duration during which a failing peer is no longer requested.
+	OpenDuration common.Duration `json:"open_duration,omitempty" yaml:"open_duration,omitempty"`
+}
+
+func (f Federation) Sanitize() Federation {
+	f.Peers = sanitizeList(f.Peers)
+	return f
+}
+
+func (f *Federation) Verify() error {
+	if len(f.Peers) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(f.Peers))
+	for i := range f.Peers {
+		// The resolver verifies a struct before its fields, so the peers must be verified here to set their default name
+		// before looking for duplicates.
+		if err := f.Peers[i].Verify(); err != nil {
+			return err
+		}
+		if names[f.Peers[i].Name] {
+			return fmt.Errorf("the federation peer name %q is used more than once", f.Peers[i].Name)
+		}
+		names[f.Peers[i].Name] = true
+	}
+	if f.Timeout <= 0 {
+		f.Timeout = common.Duration(DefaultFederationTimeout)
+	}
+	if f.FailureThreshold <= 0 {
+		f.FailureThreshold = DefaultFederationFailureThreshold
+	}
+	if f.OpenDuration <= 0 {
+		f.OpenDuration = common.Duration(DefaultFederationOpenDuration)
+	}
+	return nil
+}
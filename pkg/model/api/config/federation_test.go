@@ -0,0 +1,34 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederationVerify(t *testing.T) {
+	cfg := Federation{Peers: []PeerConfig{{URL: "https://perses.east.example.com/"}}}
+	require.NoError(t, cfg.Verify())
+	assert.Equal(t, "perses.east.example.com", cfg.Peers[0].Name)
+	assert.Equal(t, "https://perses.east.example.com", cfg.Peers[0].URL)
+
+	duplicated := Federation{Peers: []PeerConfig{
+		{URL: "https://perses.example.com"},
+		{URL: "https://perses.example.com/other"},
+	}}
+	assert.Error(t, duplicated.Verify())
+}
@@ -14,10 +14,17 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/spec/go/common"
 	"github.com/sirupsen/logrus"
 )
 
@@ -31,23 +38,94 @@ var (
 	DefaultArchivePluginPathInContainer = "/etc/perses/plugins-archive"
 )
 
+const (
+	DefaultPluginUpdateCheckInterval = 24 * time.Hour
+	DefaultPluginDownloadTimeout     = 5 * time.Minute
+	// DefaultPluginMaxArchiveSize is 500 MiB
+	DefaultPluginMaxArchiveSize int64 = 500 * 1024 * 1024
+	// ArchiveDigestQueryParam is the query parameter of an archive URL giving the expected SHA-256 digest of the archive.
+	ArchiveDigestQueryParam = "sha256"
+)
+
+// IsRemoteArchivePath tells whether an entry of `archive_paths` is the URL of an archive rather than a local folder.
+func IsRemoteArchivePath(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "s3://")
+}
+
+func verifyRemoteArchivePath(path string) error {
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	if len(u.Host) == 0 {
+		return fmt.Errorf("the host is missing")
+	}
+	if digest := u.Query().Get(ArchiveDigestQueryParam); len(digest) > 0 {
+		if decoded, decodeErr := hex.DecodeString(digest); decodeErr != nil || len(decoded) != 32 {
+			return fmt.Errorf("the %s digest %q is not a hexadecimal SHA-256 digest", ArchiveDigestQueryParam, digest)
+		}
+	}
+	return nil
+}
+
 func isFileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
+// checkWritable verifies that a file can be created in the directory. When the directory doesn't exist yet, its closest
+// existing parent is checked instead, as the directory is created when the archives are extracted.
+func checkWritable(path string) error {
+	dir := path
+	for !isFileExists(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".perses-write-check-*")
+	if err != nil {
+		return err
+	}
+	if closeErr := f.Close(); closeErr != nil {
+		return closeErr
+	}
+	return os.Remove(f.Name())
+}
+
 type Plugin struct {
 	// Path is the path to the directory containing the runtime plugins
-	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Path modelCommon.NonEmptyString `json:"path,omitempty" yaml:"path,omitempty"`
 	// ArchivePath is the path to the directory containing the archived plugins
 	// When Perses is starting, it will extract the content of the archive in the folder specified in the `folder` attribute.
 	// DEPRECATED: This attribute is deprecated and will be removed in a future version. It is still supported for backward compatibility, but it is recommended to use the `archive_paths` attribute instead.
 	ArchivePath string `json:"archive_path,omitempty" yaml:"archive_path,omitempty"`
 	// ArchivePaths is the list of paths to the directories containing the archived plugins. It allows to specify multiple directories for the archived plugins.
 	// When Perses is starting, it will extract any archive found in the folders specified in this attribute in the folder specified in the `path` attribute.
+	// An entry starting with `https://` or `s3://` is the URL of a single archive, downloaded at startup. The expected
+	// SHA-256 digest of the archive can be appended as the `sha256` query parameter.
 	ArchivePaths []string `json:"archive_paths,omitempty" yaml:"archive_paths,omitempty"`
-	// DevEnvironment is the configuration to use when developing a plugin
+	// DownloadTimeout is the maximum time given to the download of each archive referenced by a URL in ArchivePaths.
+	DownloadTimeout common.Duration `json:"download_timeout,omitempty" yaml:"download_timeout,omitempty"`
+	// ExtractionTimeout is the maximum time given to the extraction of each archive. When it is exceeded, the extraction
+	// is aborted and Perses fails to start. Leave empty to not limit the extraction time.
+	ExtractionTimeout common.Duration `json:"extraction_timeout,omitempty" yaml:"extraction_timeout,omitempty"`
+	// MaxArchiveSize is the maximum size, in bytes, of the content of an archive once extracted. The extraction of an
+	// archive exceeding it is aborted, so a malicious archive can't fill the disk.
+	MaxArchiveSize int64 `json:"max_archive_size,omitempty" yaml:"max_archive_size,omitempty"`
+	// SigningKeyPath is the path to the PEM encoded ECDSA public key verifying the plugin archives. When set, every
+	// archive must contain a file SHA256SUMS listing the digest of each of its files and a file SHA256SUMS.sig holding
+	// the signature of this list. Otherwise, or when a file doesn't match the list, the extraction of the archive fails.
+	SigningKeyPath string `json:"signing_key_path,omitempty" yaml:"signing_key_path,omitempty"`
+	// SkipSignatureVerification disables the verification of the signature of the archives. Only meant for local development.
+	SkipSignatureVerification bool `json:"skip_signature_verification,omitempty" yaml:"skip_signature_verification,omitempty"`
+	// EnableDev allows every plugin to be loaded from its development server, instead of from the `path` directory.
+	// It is ignored when DevPlugins is set.
 	EnableDev bool `json:"enable_dev" yaml:"enable_dev"`
+	// DevPlugins is the list of plugins allowed to be loaded from their development server. The other plugins are
+	// always loaded from the `path` directory.
+	DevPlugins []string `json:"dev_plugins,omitempty" yaml:"dev_plugins,omitempty"`
 	// Enabled is a list of plugin activated. Leave empty if you want to activate all plugins found in the `path` directory.
 	// If not empty, only the plugins whose name is in this list will be activated.
 	// The name can be the name of the plugin or the name of the module. For example, you can put `Prometheus` to enable the Prometheus module that contains query, variables and datasource plugin.
@@ -58,6 +136,42 @@ type Plugin struct {
 	// The name can be the name of the plugin or the name of the module. For example, you can put `Prometheus` to disable the Prometheus module that contains query, variables and datasource plugin.
 	// Use either Enabled or Disabled. Both can not be used at the same time.
 	Disabled []string `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// AllowList is the list of the plugin modules that can be loaded, identified by the name of their manifest.
+	// When not empty, the other modules are skipped. Unlike Enabled, it is meant to prevent unknown plugins from being
+	// introduced through the archives.
+	AllowList []string `json:"allow_list,omitempty" yaml:"allow_list,omitempty"`
+	// DenyList is the list of the plugin modules that are rejected, identified by the name of their manifest.
+	// A module present in both lists is rejected: DenyList takes precedence over AllowList.
+	DenyList []string `json:"deny_list,omitempty" yaml:"deny_list,omitempty"`
+	// FilterReloadInterval is the interval at which the configuration file is read again to apply any change made to the `enabled`, `disabled`, `allow_list` and `deny_list` lists.
+	// The plugins newly filtered out are unloaded, and the ones newly allowed are loaded. Leave empty to only apply these lists at startup.
+	FilterReloadInterval common.Duration `json:"filter_reload_interval,omitempty" yaml:"filter_reload_interval,omitempty"`
+	// EnableNativePlugins activates the loading of the Go plugins (.so files) found at the root of the `path` directory.
+	// Each of them must export a `PersesPlugin` symbol implementing the NativePlugin interface of the package pkg/plugin/api.
+	EnableNativePlugins bool `json:"enable_native_plugins,omitempty" yaml:"enable_native_plugins,omitempty"`
+	// RegistryURL is the URL of a plugin registry listing the latest version of each plugin.
+	// When set, Perses regularly checks it to report the loaded plugins that have a newer version available.
+	RegistryURL string `json:"registry_url,omitempty" yaml:"registry_url,omitempty"`
+	// UpdateCheckInterval is the interval at which the registry is checked for new plugin versions.
+	UpdateCheckInterval common.Duration `json:"update_check_interval,omitempty" yaml:"update_check_interval,omitempty"`
+	// UpdateCheckSchedule is the cron expression scheduling the checks of the registry, like `0 3 * * *`.
+	// It replaces UpdateCheckInterval, so both cannot be used at the same time.
+	UpdateCheckSchedule modelCommon.CronExpression `json:"update_check_schedule,omitempty" yaml:"update_check_schedule,omitempty"`
+}
+
+// IsDevEnabled tells whether at least one plugin can be loaded from its development server.
+func (p *Plugin) IsDevEnabled() bool {
+	return p.EnableDev || len(p.DevPlugins) > 0
+}
+
+// IsDevPlugin tells whether the plugin can be loaded from its development server.
+func (p *Plugin) IsDevPlugin(name string) bool {
+	if len(p.DevPlugins) == 0 {
+		return p.EnableDev
+	}
+	return slices.ContainsFunc(p.DevPlugins, func(devPlugin string) bool {
+		return strings.EqualFold(devPlugin, name)
+	})
 }
 
 func (p *Plugin) Verify() error {
@@ -68,11 +182,14 @@ func (p *Plugin) Verify() error {
 	// The fact the path where the plugin is stored exists is more relevant.
 	if len(p.Path) == 0 {
 		if isFileExists(DefaultPluginPathInContainer) {
-			p.Path = DefaultPluginPathInContainer
+			p.Path = modelCommon.NonEmptyString(DefaultPluginPathInContainer)
 		} else {
-			p.Path = DefaultPluginPath
+			p.Path = modelCommon.NonEmptyString(DefaultPluginPath)
 		}
 	}
+	if err := checkWritable(string(p.Path)); err != nil {
+		return fmt.Errorf("the plugin path %q is not writable, so the plugin archives can not be extracted in it: %w", p.Path, err)
+	}
 	if len(p.ArchivePath) > 0 {
 		logrus.Warn("the 'archive_path' attribute is deprecated and will be removed in a future version. Please use the 'archive_paths' attribute instead")
 		p.ArchivePaths = append(p.ArchivePaths, p.ArchivePath)
@@ -85,18 +202,89 @@ func (p *Plugin) Verify() error {
 			p.ArchivePaths = append(p.ArchivePaths, DefaultArchivePluginPath)
 		}
 	}
+	if err := p.VerifyArchivePaths(); err != nil {
+		return err
+	}
+	if len(p.SigningKeyPath) > 0 {
+		if p.SkipSignatureVerification {
+			logrus.Warn("the signature of the plugin archives is not verified, 'skip_signature_verification' should only be used for local development")
+		} else if !isFileExists(p.SigningKeyPath) {
+			return fmt.Errorf("the signing key %q does not exist", p.SigningKeyPath)
+		}
+	}
+	if p.ExtractionTimeout < 0 {
+		return fmt.Errorf("the 'extraction_timeout' attribute can not be negative")
+	}
+	if p.MaxArchiveSize < 0 {
+		return fmt.Errorf("the 'max_archive_size' attribute can not be negative")
+	}
+	if p.MaxArchiveSize == 0 {
+		p.MaxArchiveSize = DefaultPluginMaxArchiveSize
+	}
+	if len(p.RegistryURL) > 0 {
+		if _, err := url.ParseRequestURI(p.RegistryURL); err != nil {
+			return fmt.Errorf("invalid plugin registry_url: %w", err)
+		}
+		if len(p.UpdateCheckSchedule) > 0 && p.UpdateCheckInterval > 0 {
+			return fmt.Errorf("the 'update_check_interval' and 'update_check_schedule' attributes can not be used at the same time. Please use either one of them")
+		}
+		if len(p.UpdateCheckSchedule) == 0 && p.UpdateCheckInterval <= 0 {
+			p.UpdateCheckInterval = common.Duration(DefaultPluginUpdateCheckInterval)
+		}
+	}
+	for _, devPlugin := range p.DevPlugins {
+		if len(strings.TrimSpace(devPlugin)) == 0 {
+			return fmt.Errorf("the 'dev_plugins' attribute can not contain an empty plugin name")
+		}
+	}
+	return p.VerifyFilter()
+}
+
+// VerifyArchivePaths checks the URLs of the remote archives, and sets the default download timeout when there is at
+// least one of them. Unlike Verify, it doesn't access the file system.
+func (p *Plugin) VerifyArchivePaths() error {
+	hasRemoteArchive := false
+	for _, archivePath := range p.ArchivePaths {
+		if !IsRemoteArchivePath(archivePath) {
+			continue
+		}
+		hasRemoteArchive = true
+		if err := verifyRemoteArchivePath(archivePath); err != nil {
+			return fmt.Errorf("invalid plugin archive URL %q: %w", archivePath, err)
+		}
+	}
+	if p.DownloadTimeout < 0 {
+		return fmt.Errorf("the 'download_timeout' attribute can not be negative")
+	}
+	if hasRemoteArchive && p.DownloadTimeout == 0 {
+		p.DownloadTimeout = common.Duration(DefaultPluginDownloadTimeout)
+	}
+	return nil
+}
+
+// VerifyFilter checks the lists of enabled, disabled, allowed and denied plugins, and puts them in lowercase.
+// Unlike Verify, it doesn't access the file system.
+func (p *Plugin) VerifyFilter() error {
+	for _, list := range []*[]string{&p.AllowList, &p.DenyList} {
+		for i, name := range *list {
+			if len(strings.TrimSpace(name)) == 0 {
+				return fmt.Errorf("the 'allow_list' and 'deny_list' attributes can not contain an empty plugin name")
+			}
+			(*list)[i] = strings.ToLower(name)
+		}
+	}
 	if len(p.Enabled) > 0 && len(p.Disabled) > 0 {
 		return fmt.Errorf("the 'activated' and 'deactivated' attributes can not be used at the same time. Please use either one of them")
 	}
 	if len(p.Enabled) > 0 {
-		newEnabled := make([]string, len(p.Enabled))
+		newEnabled := make([]string, 0, len(p.Enabled))
 		for _, s := range p.Enabled {
 			newEnabled = append(newEnabled, strings.ToLower(s))
 		}
 		p.Enabled = newEnabled
 	}
 	if len(p.Disabled) > 0 {
-		newDisabled := make([]string, len(p.Disabled))
+		newDisabled := make([]string, 0, len(p.Disabled))
 		for _, s := range p.Disabled {
 			newDisabled = append(newDisabled, strings.ToLower(s))
 		}
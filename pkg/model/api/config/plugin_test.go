@@ -0,0 +1,118 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginVerifyWritablePath(t *testing.T) {
+	dir := t.TempDir()
+	p := Plugin{Path: modelCommon.NonEmptyString(dir)}
+	require.NoError(t, p.Verify())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the folder is created when the archives are extracted, so it doesn't need to exist yet
+	p = Plugin{Path: modelCommon.NonEmptyString(filepath.Join(dir, "not", "created"))}
+	assert.NoError(t, p.Verify())
+}
+
+func TestPluginVerifyReadOnlyPath(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("the permissions of the files are not enforced for root")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.Chmod(dir, 0o500))
+	t.Cleanup(func() {
+		_ = os.Chmod(dir, 0o700)
+	})
+	p := Plugin{Path: modelCommon.NonEmptyString(dir)}
+	assert.ErrorContains(t, p.Verify(), "is not writable")
+
+	p = Plugin{Path: modelCommon.NonEmptyString(filepath.Join(dir, "plugins"))}
+	assert.ErrorContains(t, p.Verify(), "is not writable")
+}
+
+func TestPluginVerifyRemoteArchivePaths(t *testing.T) {
+	digest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	p := Plugin{
+		Path:         modelCommon.NonEmptyString(t.TempDir()),
+		ArchivePaths: []string{"plugins-archive", "https://example.com/prometheus.tar.gz?sha256=" + digest, "s3://bucket/tempo.zip"},
+	}
+	require.NoError(t, p.Verify())
+	assert.Equal(t, common.Duration(DefaultPluginDownloadTimeout), p.DownloadTimeout)
+
+	p = Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), ArchivePaths: []string{"plugins-archive"}}
+	require.NoError(t, p.Verify())
+	assert.Zero(t, p.DownloadTimeout)
+
+	p = Plugin{
+		Path:         modelCommon.NonEmptyString(t.TempDir()),
+		ArchivePaths: []string{"https://example.com/prometheus.tar.gz?sha256=abc"},
+	}
+	assert.ErrorContains(t, p.Verify(), "is not a hexadecimal SHA-256 digest")
+
+	p = Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), ArchivePaths: []string{"s3:///prometheus.tar.gz"}}
+	assert.ErrorContains(t, p.Verify(), "the host is missing")
+}
+
+func TestPluginIsDevPlugin(t *testing.T) {
+	p := Plugin{EnableDev: true}
+	assert.True(t, p.IsDevEnabled())
+	assert.True(t, p.IsDevPlugin("prometheus"))
+
+	p = Plugin{DevPlugins: []string{"Prometheus"}}
+	assert.True(t, p.IsDevEnabled())
+	assert.True(t, p.IsDevPlugin("prometheus"))
+	assert.False(t, p.IsDevPlugin("tempo"))
+
+	// dev_plugins takes precedence over enable_dev
+	p = Plugin{EnableDev: true, DevPlugins: []string{"prometheus"}}
+	assert.False(t, p.IsDevPlugin("tempo"))
+
+	p = Plugin{}
+	assert.False(t, p.IsDevEnabled())
+	assert.False(t, p.IsDevPlugin("prometheus"))
+
+	p = Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), DevPlugins: []string{"prometheus", " "}}
+	assert.EqualError(t, p.Verify(), "the 'dev_plugins' attribute can not contain an empty plugin name")
+}
+
+func TestPluginVerifySigningKeyPath(t *testing.T) {
+	p := Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), SigningKeyPath: filepath.Join(t.TempDir(), "missing.pub")}
+	assert.ErrorContains(t, p.Verify(), "does not exist")
+
+	// the key is not used when the verification is skipped
+	p = Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), SigningKeyPath: filepath.Join(t.TempDir(), "missing.pub"), SkipSignatureVerification: true}
+	assert.NoError(t, p.Verify())
+}
+
+func TestPluginVerifyAllowDenyList(t *testing.T) {
+	p := Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), AllowList: []string{"Prometheus"}, DenyList: []string{"Tempo"}}
+	require.NoError(t, p.Verify())
+	assert.Equal(t, []string{"prometheus"}, p.AllowList)
+	assert.Equal(t, []string{"tempo"}, p.DenyList)
+
+	p = Plugin{Path: modelCommon.NonEmptyString(t.TempDir()), DenyList: []string{""}}
+	assert.EqualError(t, p.Verify(), "the 'allow_list' and 'deny_list' attributes can not contain an empty plugin name")
+}
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+)
+
+// redacted replaces the credentials in a sanitized config.
+const redacted = "<redacted>"
+
+// SecureConfig is implemented by the config structs holding credentials.
+// Sanitize returns a copy of the config where every credential is replaced by "<redacted>",
+// so the copy can be logged or printed (with %+v for example) without leaking any secret.
+// The config the method is called on is left untouched.
+type SecureConfig[T any] interface {
+	Sanitize() T
+}
+
+func redactHidden(value secret.Hidden) secret.Hidden {
+	if len(value) == 0 {
+		return value
+	}
+	return redacted
+}
+
+func redactString(value string) string {
+	if len(value) == 0 {
+		return value
+	}
+	return redacted
+}
+
+func sanitizePointer[T SecureConfig[T]](config *T) *T {
+	if config == nil {
+		return nil
+	}
+	result := (*config).Sanitize()
+	return &result
+}
+
+func sanitizeList[T SecureConfig[T]](list []T) []T {
+	if list == nil {
+		return nil
+	}
+	result := make([]T, 0, len(list))
+	for _, config := range list {
+		result = append(result, config.Sanitize())
+	}
+	return result
+}
+
+func sanitizeTLSConfig(config *secret.TLSConfig) *secret.TLSConfig {
+	if config == nil {
+		return nil
+	}
+	result := *config
+	result.CA = redactString(result.CA)
+	result.Cert = redactString(result.Cert)
+	result.Key = redactString(result.Key)
+	return &result
+}
+
+func sanitizePublicTLSConfig(config *secret.PublicTLSConfig) *secret.PublicTLSConfig {
+	if config == nil {
+		return nil
+	}
+	result := *config
+	result.CA = redactHidden(result.CA)
+	result.Cert = redactHidden(result.Cert)
+	result.Key = redactHidden(result.Key)
+	return &result
+}
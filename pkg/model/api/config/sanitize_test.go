@@ -0,0 +1,114 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	clientConfig "github.com/perses/perses/pkg/client/config"
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/v1/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConfigWithSecrets() Config {
+	provider := Provider{
+		SlugID:            "github",
+		Name:              "GitHub",
+		ClientID:          "provider-client-id",
+		ClientSecret:      "provider-client-secret",
+		DeviceCode:        &OAuthOverride{ClientID: "device-client-id", ClientSecret: "device-client-secret"},
+		ClientCredentials: &OAuthOverride{ClientID: "credentials-client-id", ClientSecret: "credentials-client-secret"},
+		HTTP:              HTTP{TLSConfig: &secret.TLSConfig{Key: "provider-tls-key", ServerName: "github.com"}},
+	}
+	return Config{
+		Security: Security{
+			EncryptionKey: "encryption-key",
+			Authentication: AuthenticationConfig{
+				Providers: AuthenticationProviders{
+					OAuth: []OAuthProvider{{Provider: provider}},
+					OIDC:  []OIDCProvider{{Provider: provider}},
+				},
+			},
+		},
+		Database: Database{
+			SQL: &SQL{
+				User:      "sql-user",
+				Password:  "sql-password",
+				Addr:      "sql-addr",
+				DBName:    "perses",
+				TLSConfig: &secret.PublicTLSConfig{Key: "sql-tls-key"},
+			},
+		},
+		Datasource: DatasourceConfig{
+			Global: GlobalDatasourceConfig{
+				Discovery: []GlobalDatasourceDiscovery{{
+					Name: "http",
+					HTTPDiscovery: &HTTPDiscovery{RestConfigClient: clientConfig.RestConfigClient{
+						NativeAuth:    &modelAPI.Auth{Login: "admin", Password: "discovery-password"},
+						Authorization: &secret.Authorization{Type: "Bearer", Credentials: "discovery-token"},
+					}},
+				}},
+			},
+		},
+		Federation: Federation{
+			Peers: []PeerConfig{{Name: "peer", URL: "https://peer.perses.dev", Token: "peer-token"}},
+		},
+	}
+}
+
+var configSecrets = []string{
+	"provider-client-id", "provider-client-secret", "device-client-id", "device-client-secret",
+	"credentials-client-id", "credentials-client-secret", "provider-tls-key", "encryption-key",
+	"sql-user", "sql-password", "sql-addr", "sql-tls-key", "discovery-password", "discovery-token", "peer-token",
+}
+
+func TestConfig_Sanitize(t *testing.T) {
+	sanitized := newConfigWithSecrets().Sanitize()
+
+	// The nested pointers are printed as addresses by %+v, so they are printed one by one as well.
+	printed := []string{
+		fmt.Sprintf("%+v", sanitized),
+		fmt.Sprintf("%+v", sanitized.Security.Authentication.Providers.OAuth[0].Provider),
+		fmt.Sprintf("%+v", *sanitized.Security.Authentication.Providers.OAuth[0].DeviceCode),
+		fmt.Sprintf("%+v", *sanitized.Security.Authentication.Providers.OIDC[0].ClientCredentials),
+		fmt.Sprintf("%+v", *sanitized.Security.Authentication.Providers.OIDC[0].HTTP.TLSConfig),
+		fmt.Sprintf("%+v", *sanitized.Database.SQL),
+		fmt.Sprintf("%+v", *sanitized.Database.SQL.TLSConfig),
+		fmt.Sprintf("%+v", *sanitized.Datasource.Global.Discovery[0].HTTPDiscovery.NativeAuth),
+		fmt.Sprintf("%+v", *sanitized.Datasource.Global.Discovery[0].HTTPDiscovery.Authorization),
+	}
+	for _, output := range printed {
+		for _, value := range configSecrets {
+			assert.NotContains(t, output, value)
+		}
+	}
+	assert.Equal(t, secret.Hidden(redacted), sanitized.Database.SQL.Password)
+	assert.Equal(t, "perses", sanitized.Database.SQL.DBName)
+	assert.Equal(t, "github.com", sanitized.Security.Authentication.Providers.OAuth[0].HTTP.TLSConfig.ServerName)
+	assert.Equal(t, "admin", sanitized.Datasource.Global.Discovery[0].HTTPDiscovery.NativeAuth.Login)
+}
+
+func TestConfig_SanitizeKeepsOriginal(t *testing.T) {
+	cfg := newConfigWithSecrets()
+	_ = cfg.Sanitize()
+	assert.Equal(t, newConfigWithSecrets(), cfg)
+}
+
+func TestConfig_SanitizeEmptySecret(t *testing.T) {
+	sanitized := Config{Database: Database{SQL: &SQL{DBName: "perses"}}}.Sanitize()
+	assert.Empty(t, sanitized.Database.SQL.Password)
+	assert.Empty(t, sanitized.Security.EncryptionKey)
+}
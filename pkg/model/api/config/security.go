@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/perses/pkg/model/api/v1/secret"
 	"github.com/sirupsen/logrus"
 )
@@ -161,6 +163,19 @@ type Security struct {
 	Authentication AuthenticationConfig `json:"authentication,omitempty" yaml:"authentication,omitempty"`
 	// Configuration for the CORS middleware.
 	CORS CORSConfig `json:"cors,omitempty" yaml:"cors"`
+	// TrustedProxies is the list of the reverse proxies (IP or CIDR) allowed to set the client IP with the header X-Forwarded-For.
+	// When set, the header is ignored if the request doesn't come from one of them.
+	// When empty, the header is always trusted.
+	TrustedProxies []common.IPOrCIDR `json:"trusted_proxies,omitempty" yaml:"trusted_proxies,omitempty"`
+	// JWTAudience is the list of audiences set in the `aud` claim of the tokens issued by Perses.
+	// When set, a token is accepted only if its `aud` claim contains one of them.
+	JWTAudience []string `json:"jwt_audience,omitempty" yaml:"jwt_audience,omitempty"`
+}
+
+func (s Security) Sanitize() Security {
+	s.EncryptionKey = redactHidden(s.EncryptionKey)
+	s.Authentication = s.Authentication.Sanitize()
+	return s
 }
 
 func (s *Security) Verify() error {
@@ -184,9 +199,16 @@ func (s *Security) Verify() error {
 	}
 	s.EncryptionKey = secret.Hidden(hex.EncodeToString([]byte(s.EncryptionKey)))
 
+	for _, audience := range s.JWTAudience {
+		if len(strings.TrimSpace(audience)) == 0 {
+			return errors.New("jwt_audience cannot contain an empty audience")
+		}
+	}
+
 	if s.EnableAuth && !s.Authentication.Providers.EnableNative &&
 		len(s.Authentication.Providers.OIDC) == 0 &&
 		len(s.Authentication.Providers.OAuth) == 0 &&
+		len(s.Authentication.Providers.Custom) == 0 &&
 		!s.Authentication.Providers.KubernetesProvider.Enable {
 		return errors.New("impossible to enable auth if no authentication provider is setup")
 	}
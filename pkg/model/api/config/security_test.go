@@ -344,6 +344,7 @@ security:
 						Folder:    "./local_db",
 						Extension: "yaml",
 					},
+					SlowQueryThreshold: common.Duration(defaultSlowQueryThreshold),
 				},
 				Frontend: Frontend{
 					ImportantDashboards: nil,
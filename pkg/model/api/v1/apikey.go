@@ -0,0 +1,134 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	"github.com/perses/perses/pkg/model/api/v1/role"
+)
+
+type APIKeySpec struct {
+	// HashedSecret is the SHA-256 hash, hex encoded, of the secret of the key.
+	// It is computed by the server when the key is created, the secret itself is never stored.
+	HashedSecret string `json:"hashedSecret,omitempty" yaml:"hashedSecret,omitempty"`
+	// Scopes is the list of the permissions granted to the key in its project.
+	// A scope has the format `<action>:<scope>`, for example `read:Dashboard` or `*:Variable`.
+	Scopes []string `json:"scopes" yaml:"scopes"`
+	// ExpiresAt is the date after which the key is rejected. The key never expires when it is not set.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+}
+
+func (s *APIKeySpec) UnmarshalJSON(data []byte) error {
+	var tmp APIKeySpec
+	type plain APIKeySpec
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *APIKeySpec) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp APIKeySpec
+	type plain APIKeySpec
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *APIKeySpec) validate() error {
+	if len(s.Scopes) == 0 {
+		return fmt.Errorf("scopes cannot be empty")
+	}
+	for _, scope := range s.Scopes {
+		if _, err := parseAPIKeyScope(scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsExpired returns true if the key has an expiration date before the given time.
+func (s *APIKeySpec) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// Permissions returns the permissions granted by the scopes of the key.
+// Invalid scopes are ignored, they are already rejected when the key is decoded.
+func (s *APIKeySpec) Permissions() []*role.Permission {
+	permissions := make([]*role.Permission, 0, len(s.Scopes))
+	for _, scope := range s.Scopes {
+		permission, err := parseAPIKeyScope(scope)
+		if err != nil {
+			continue
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+func parseAPIKeyScope(scope string) (*role.Permission, error) {
+	actionValue, scopeValue, found := strings.Cut(scope, ":")
+	if !found {
+		return nil, fmt.Errorf("invalid scope %q, it must have the format <action>:<scope>", scope)
+	}
+	action, err := role.GetAction(actionValue)
+	if err != nil {
+		return nil, err
+	}
+	kindScope, err := role.GetScope(scopeValue)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsGlobalScope(*kindScope) {
+		return nil, fmt.Errorf("invalid scope %q, an API key cannot be granted a permission on a global resource", scope)
+	}
+	if *kindScope == role.APIKeyScope {
+		return nil, fmt.Errorf("invalid scope %q, an API key cannot manage the API keys", scope)
+	}
+	return &role.Permission{Actions: []role.Action{*action}, Scopes: []role.Scope{*kindScope}}, nil
+}
+
+// APIKey is a key authenticating a service account against the API of a project.
+// Its permissions are limited to its scopes and to the project it belongs to.
+type APIKey struct {
+	Kind     Kind            `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata `json:"metadata" yaml:"metadata"`
+	Spec     APIKeySpec      `json:"spec" yaml:"spec"`
+}
+
+func (a *APIKey) GetMetadata() modelAPI.Metadata {
+	return &a.Metadata
+}
+
+func (a *APIKey) GetKind() string {
+	return string(a.Kind)
+}
+
+func (a *APIKey) GetSpec() any {
+	return a.Spec
+}
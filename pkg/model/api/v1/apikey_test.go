@@ -0,0 +1,90 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/perses/perses/pkg/model/api/v1/role"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalAPIKeySpec(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		jason  string
+		result APIKeySpec
+		err    string
+	}{
+		{
+			title: "valid scopes",
+			jason: `{"scopes": ["read:Dashboard", "*:Variable"]}`,
+			result: APIKeySpec{
+				Scopes: []string{"read:Dashboard", "*:Variable"},
+			},
+		},
+		{
+			title: "empty scopes",
+			jason: `{"scopes": []}`,
+			err:   "scopes cannot be empty",
+		},
+		{
+			title: "scope without action",
+			jason: `{"scopes": ["Dashboard"]}`,
+			err:   `invalid scope "Dashboard", it must have the format <action>:<scope>`,
+		},
+		{
+			title: "global scope",
+			jason: `{"scopes": ["read:GlobalDatasource"]}`,
+			err:   `invalid scope "read:GlobalDatasource", an API key cannot be granted a permission on a global resource`,
+		},
+		{
+			title: "API key scope",
+			jason: `{"scopes": ["create:APIKey"]}`,
+			err:   `invalid scope "create:APIKey", an API key cannot manage the API keys`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result := APIKeySpec{}
+			err := json.Unmarshal([]byte(test.jason), &result)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestAPIKeySpecPermissions(t *testing.T) {
+	spec := APIKeySpec{Scopes: []string{"read:Dashboard", "*:*"}}
+	assert.Equal(t, []*role.Permission{
+		{Actions: []role.Action{role.ReadAction}, Scopes: []role.Scope{role.DashboardScope}},
+		{Actions: []role.Action{role.WildcardAction}, Scopes: []role.Scope{role.WildcardScope}},
+	}, spec.Permissions())
+}
+
+func TestAPIKeySpecIsExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	assert.False(t, (&APIKeySpec{}).IsExpired(now))
+	assert.True(t, (&APIKeySpec{ExpiresAt: &past}).IsExpired(now))
+	assert.True(t, (&APIKeySpec{ExpiresAt: &now}).IsExpired(now))
+	assert.False(t, (&APIKeySpec{ExpiresAt: &future}).IsExpired(now))
+}
@@ -0,0 +1,126 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Base64String is a string holding binary data encoded in standard base64, like a certificate or a private key
+// written in a config file. An error is returned when unmarshalling a value that is not valid base64 from JSON or YAML.
+// The line breaks are ignored, so long values can be split over several lines. An empty value is valid and holds no data.
+type Base64String string
+
+// NewBase64String encodes the given data in base64.
+func NewBase64String(data []byte) Base64String {
+	return Base64String(base64.StdEncoding.EncodeToString(data))
+}
+
+func (s *Base64String) UnmarshalJSON(data []byte) error {
+	var tmp Base64String
+	type plain Base64String
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *Base64String) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp Base64String
+	type plain Base64String
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+// Decode returns the binary data encoded in the string.
+func (s Base64String) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(string(s))
+}
+
+func (s Base64String) validate() error {
+	if _, err := s.Decode(); err != nil {
+		return fmt.Errorf("value is not valid base64: %w", err)
+	}
+	return nil
+}
+
+// PEMBase64String is a Base64String whose decoded data must be one or several PEM blocks,
+// like a chain of CA certificates. An empty value is valid and holds no data.
+type PEMBase64String Base64String
+
+func (s *PEMBase64String) UnmarshalJSON(data []byte) error {
+	var tmp PEMBase64String
+	type plain PEMBase64String
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *PEMBase64String) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp PEMBase64String
+	type plain PEMBase64String
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+// Decode returns the PEM data encoded in the string.
+func (s PEMBase64String) Decode() ([]byte, error) {
+	return Base64String(s).Decode()
+}
+
+func (s PEMBase64String) validate() error {
+	data, err := s.Decode()
+	if err != nil {
+		return fmt.Errorf("value is not valid base64: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return errors.New("decoded value is not valid PEM")
+	}
+	for len(bytes.TrimSpace(rest)) > 0 {
+		if block, rest = pem.Decode(rest); block == nil {
+			return errors.New("decoded value contains data that is not valid PEM")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testCertificate = `-----BEGIN CERTIFICATE-----
+MIIBczCCARmgAwIBAgIUTWDn3A0m4UJKZ9wRZp0W3Ys4zGIwCgYIKoZIzj0EAwIw
+DzENMAsGA1UEAwwEdGVzdDAeFw0yNDAxMDEwMDAwMDBaFw0zNDAxMDEwMDAwMDBa
+MA8xDTALBgNVBAMMBHRlc3QwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNCAAQ6cXSc
+-----END CERTIFICATE-----
+`
+
+type testBase64StringStruct struct {
+	Value    Base64String    `json:"value" yaml:"value"`
+	PEMValue PEMBase64String `json:"pemValue" yaml:"pemValue"`
+}
+
+func TestBase64String_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		data    []byte
+		isError bool
+	}{
+		{title: "empty string", value: "", data: []byte{}},
+		{title: "valid base64", value: "cGVyc2Vz", data: []byte("perses")},
+		{title: "valid base64 split over several lines", value: "cGVy\\nc2Vz", data: []byte("perses")},
+		{title: "invalid characters", value: "perses!", isError: true},
+		{title: "invalid padding", value: "cGVyc2V", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testBase64StringStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"value":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testBase64StringStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`value: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			require.NoError(t, jsonErr)
+			require.NoError(t, yamlErr)
+			assert.Equal(t, jsonResult.Value, yamlResult.Value)
+			data, err := jsonResult.Value.Decode()
+			require.NoError(t, err)
+			assert.Equal(t, test.data, data)
+		})
+	}
+}
+
+func TestPEMBase64String_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   PEMBase64String
+		isError bool
+	}{
+		{title: "empty string", value: ""},
+		{title: "single PEM block", value: PEMBase64String(NewBase64String([]byte(testCertificate)))},
+		{title: "several PEM blocks", value: PEMBase64String(NewBase64String([]byte(testCertificate + "\n" + testCertificate)))},
+		{title: "not PEM", value: PEMBase64String(NewBase64String([]byte("perses"))), isError: true},
+		{title: "PEM followed by garbage", value: PEMBase64String(NewBase64String([]byte(testCertificate + "perses"))), isError: true},
+		{title: "invalid base64", value: "perses!", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testBase64StringStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"pemValue":"`+string(test.value)+`"}`), jsonResult)
+			yamlResult := &testBase64StringStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`pemValue: "`+string(test.value)+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.value, jsonResult.PEMValue)
+			assert.Equal(t, test.value, yamlResult.PEMValue)
+		})
+	}
+}
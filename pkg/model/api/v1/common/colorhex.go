@@ -0,0 +1,108 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+var colorHexRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ColorHex is a CSS color in the hexadecimal format `#RRGGBB` or `#RGB`.
+// The value is normalized to the uppercase format `#RRGGBB` when it is unmarshalled from JSON or YAML.
+type ColorHex string
+
+// NewColorHex checks the given color and returns it normalized.
+func NewColorHex(value string) (ColorHex, error) {
+	c := ColorHex(value)
+	if err := (&c).validate(); err != nil {
+		return "", err
+	}
+	return c, nil
+}
+
+func (c *ColorHex) UnmarshalJSON(bytes []byte) error {
+	var tmp ColorHex
+	type plain ColorHex
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*c = tmp
+	return nil
+}
+
+func (c *ColorHex) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp ColorHex
+	type plain ColorHex
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*c = tmp
+	return nil
+}
+
+func (c ColorHex) String() string {
+	return string(c)
+}
+
+// ToRGBA returns the components of the color. As the format doesn't have any alpha channel, the color is always opaque.
+// The result is only meaningful for a valid color.
+func (c ColorHex) ToRGBA() (r, g, b, a uint8) {
+	var value uint32
+	// The error is ignored, as the value is checked when the color is decoded.
+	_, _ = fmt.Sscanf(string(c.normalize()), "#%06x", &value)
+	return uint8(value >> 16), uint8(value >> 8), uint8(value), math.MaxUint8
+}
+
+// Luminance returns the relative luminance of the color as defined by the WCAG, from 0 for black to 1 for white.
+// It can be used to choose a readable text color over the color.
+func (c ColorHex) Luminance() float64 {
+	r, g, b, _ := c.ToRGBA()
+	return 0.2126*linearize(r) + 0.7152*linearize(g) + 0.0722*linearize(b)
+}
+
+// linearize converts a sRGB component to its linear value.
+func linearize(component uint8) float64 {
+	v := float64(component) / math.MaxUint8
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func (c ColorHex) normalize() ColorHex {
+	value := strings.ToUpper(string(c))
+	if len(value) == 4 {
+		value = string([]byte{'#', value[1], value[1], value[2], value[2], value[3], value[3]})
+	}
+	return ColorHex(value)
+}
+
+func (c *ColorHex) validate() error {
+	if !colorHexRegexp.MatchString(string(*c)) {
+		return fmt.Errorf("invalid color %q, it must be in the format #RRGGBB or #RGB", string(*c))
+	}
+	*c = c.normalize()
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testColorHexStruct struct {
+	Value ColorHex `json:"value" yaml:"value"`
+}
+
+func TestColorHex_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		result  ColorHex
+		isError bool
+	}{
+		{title: "6-digit color", value: "#1F77B4", result: "#1F77B4"},
+		{title: "6-digit lowercase color", value: "#1f77b4", result: "#1F77B4"},
+		{title: "3-digit color", value: "#f0a", result: "#FF00AA"},
+		{title: "named color", value: "red", isError: true},
+		{title: "missing hash", value: "1f77b4", isError: true},
+		{title: "invalid digit", value: "#1g77b4", isError: true},
+		{title: "8-digit color", value: "#1f77b4ff", isError: true},
+		{title: "empty color", value: "", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testColorHexStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"value":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testColorHexStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`value: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Value)
+			assert.Equal(t, test.result, yamlResult.Value)
+		})
+	}
+}
+
+func TestColorHex_ToRGBA(t *testing.T) {
+	r, g, b, a := ColorHex("#1F77B4").ToRGBA()
+	assert.Equal(t, []uint8{0x1F, 0x77, 0xB4, 0xFF}, []uint8{r, g, b, a})
+}
+
+func TestColorHex_Luminance(t *testing.T) {
+	assert.InDelta(t, 0, ColorHex("#000000").Luminance(), 1e-9)
+	assert.InDelta(t, 1, ColorHex("#FFFFFF").Luminance(), 1e-9)
+	assert.InDelta(t, 0.2126, ColorHex("#FF0000").Luminance(), 1e-9)
+}
+
+func TestNewColorHex(t *testing.T) {
+	c, err := NewColorHex("#abc")
+	assert.NoError(t, err)
+	assert.Equal(t, ColorHex("#AABBCC"), c)
+	_, err = NewColorHex("blue")
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/flc1125/go-cron/v4"
+)
+
+// CronExpression is a schedule in the standard cron format, like `0 */6 * * *`.
+// The descriptors like `@daily` or `@every 1h` and a `CRON_TZ=` prefix are accepted as well.
+// An error is returned when unmarshalling an expression that cannot be parsed from JSON or YAML.
+// The expression is parsed by github.com/flc1125/go-cron/v4 (a fork of robfig/cron/v3), the library the cron tasks of
+// github.com/perses/common are scheduled with, so an expression accepted here is always accepted by the scheduler.
+type CronExpression string
+
+// NewCronExpression checks the given expression can be parsed.
+func NewCronExpression(expr string) (CronExpression, error) {
+	c := CronExpression(expr)
+	return c, c.validate()
+}
+
+func (c *CronExpression) UnmarshalJSON(bytes []byte) error {
+	var tmp CronExpression
+	type plain CronExpression
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*c = tmp
+	return nil
+}
+
+func (c *CronExpression) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp CronExpression
+	type plain CronExpression
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*c = tmp
+	return nil
+}
+
+// NextN returns the n next times the schedule is activated after from.
+// It returns nil when the expression cannot be parsed.
+func (c CronExpression) NextN(n int, from time.Time) []time.Time {
+	schedule, err := cron.ParseStandard(string(c))
+	if err != nil {
+		return nil
+	}
+	result := make([]time.Time, 0, n)
+	next := from
+	for range n {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			// the schedule is never activated again, like on a 30th of February
+			break
+		}
+		result = append(result, next)
+	}
+	return result
+}
+
+func (c CronExpression) String() string {
+	return string(c)
+}
+
+func (c CronExpression) validate() error {
+	if _, err := cron.ParseStandard(string(c)); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", string(c), err)
+	}
+	return nil
+}
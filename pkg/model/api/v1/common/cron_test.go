@@ -0,0 +1,109 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testCronExpressionStruct struct {
+	Schedule CronExpression `json:"schedule" yaml:"schedule"`
+}
+
+func TestCronExpression_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		isError bool
+	}{
+		{title: "every minute", value: "* * * * *"},
+		{title: "ranges, lists and steps", value: "0,30 8-18/2 * * MON-FRI"},
+		{title: "descriptor", value: "@daily"},
+		{title: "interval descriptor", value: "@every 1h30m"},
+		{title: "time zone", value: "CRON_TZ=Europe/Paris 0 3 * * *"},
+		{title: "empty expression", value: "", isError: true},
+		{title: "seconds field is not standard", value: "0 0 3 * * *", isError: true},
+		{title: "minute out of range", value: "60 * * * *", isError: true},
+		{title: "unknown descriptor", value: "@fortnightly", isError: true},
+		{title: "not a cron expression", value: "every day", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testCronExpressionStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"schedule":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testCronExpressionStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`schedule: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, CronExpression(test.value), jsonResult.Schedule)
+			assert.Equal(t, CronExpression(test.value), yamlResult.Schedule)
+		})
+	}
+}
+
+func TestCronExpression_NextN(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 13, 30, 0, 0, time.UTC)
+	testSuite := []struct {
+		title    string
+		value    CronExpression
+		n        int
+		expected []time.Time
+	}{
+		{
+			title: "every six hours",
+			value: "0 */6 * * *",
+			n:     3,
+			expected: []time.Time{
+				time.Date(2024, time.January, 31, 18, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.February, 1, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			title: "monthly",
+			value: "@monthly",
+			n:     2,
+			expected: []time.Time{
+				time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			title:    "none",
+			value:    "* * * * *",
+			n:        0,
+			expected: []time.Time{},
+		},
+		{
+			title: "invalid expression",
+			value: "every day",
+			n:     2,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.value.NextN(test.n, from))
+		})
+	}
+}
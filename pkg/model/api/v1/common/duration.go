@@ -16,6 +16,10 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/perses/spec/go/common"
@@ -46,10 +50,27 @@ func (d *Duration) Type() string {
 	return "duration"
 }
 
+var iso8601DurationRegexp = regexp.MustCompile(`^P(?:([0-9]+)Y)?(?:([0-9]+)M)?(?:([0-9]+)W)?(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+)S)?)?$`)
+
+// iso8601DurationUnits are the durations of the units matched by each group of iso8601DurationRegexp.
+var iso8601DurationUnits = []time.Duration{
+	365 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	24 * time.Hour,
+	time.Hour,
+	time.Minute,
+	time.Second,
+}
+
 // ParseDuration parses a string into a time.Duration, assuming that a year
 // always has 365d, a week always has 7d, and a day always has 24h.
+// The ISO 8601 format, like PT1H30M, is accepted as well, a month being 30d.
 // DEPRECATED: this is replaced by the struct github.com/perses/spec/go/common.ParseDuration
 func ParseDuration(s string) (Duration, error) {
+	if isISO8601Duration(s) {
+		return parseISO8601Duration(s)
+	}
 	d, err := common.ParseDuration(s)
 	if err != nil {
 		return Duration(0), err
@@ -57,6 +78,38 @@ func ParseDuration(s string) (Duration, error) {
 	return Duration(d), nil
 }
 
+func isISO8601Duration(s string) bool {
+	return strings.HasPrefix(s, "P")
+}
+
+func parseISO8601Duration(s string) (Duration, error) {
+	matches := iso8601DurationRegexp.FindStringSubmatch(s)
+	// at least one component is required, and the time designator T can't be alone
+	if matches == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("not a valid ISO 8601 duration string: %q", s)
+	}
+	var result time.Duration
+	for i, unit := range iso8601DurationUnits {
+		if len(matches[i+1]) == 0 {
+			continue
+		}
+		v, err := strconv.ParseInt(matches[i+1], 10, 64)
+		if err != nil || v > int64(math.MaxInt64/unit) || result > math.MaxInt64-time.Duration(v)*unit {
+			return 0, fmt.Errorf("duration %q is too large", s)
+		}
+		result += time.Duration(v) * unit
+	}
+	return Duration(result), nil
+}
+
+// NewDuration converts a time.Duration into a Duration.
+// The value is truncated to the millisecond, as it is the smallest unit of the string form.
+// That way, two durations built from equivalent values (e.g. 3600s and 1h) are equal and are always marshaled the same way,
+// using the most compact representation.
+func NewDuration(d time.Duration) Duration {
+	return Duration(d.Truncate(time.Millisecond))
+}
+
 func (d Duration) String() string {
 	var (
 		ms = int64(time.Duration(d) / time.Millisecond)
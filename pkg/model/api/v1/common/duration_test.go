@@ -0,0 +1,137 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testDurationStruct struct {
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+}
+
+func TestNewDuration(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		duration time.Duration
+		expected string
+	}{
+		{title: "zero", duration: 0, expected: "0s"},
+		{title: "seconds converted to hours", duration: 3600 * time.Second, expected: "1h"},
+		{title: "minutes converted to days", duration: 1440 * time.Minute, expected: "1d"},
+		{title: "days converted to weeks", duration: 14 * 24 * time.Hour, expected: "2w"},
+		{title: "days converted to a year", duration: 365 * 24 * time.Hour, expected: "1y"},
+		{title: "days not converted to weeks when inexact", duration: 90 * 24 * time.Hour, expected: "90d"},
+		{title: "mixed units", duration: 90*time.Minute + 500*time.Millisecond, expected: "1h30m500ms"},
+		{title: "sub-millisecond truncated", duration: 1500 * time.Microsecond, expected: "1ms"},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, NewDuration(test.duration).String())
+		})
+	}
+}
+
+func TestNewDuration_EquivalentValuesAreEqual(t *testing.T) {
+	assert.Equal(t, NewDuration(time.Hour), NewDuration(3600*time.Second))
+	assert.Equal(t, NewDuration(time.Millisecond), NewDuration(time.Millisecond+time.Nanosecond))
+}
+
+func TestDuration_RoundTrip(t *testing.T) {
+	durations := []time.Duration{
+		0,
+		time.Millisecond,
+		3600 * time.Second,
+		36 * time.Hour,
+		14 * 24 * time.Hour,
+		90 * 24 * time.Hour,
+		2*365*24*time.Hour + 3*time.Millisecond,
+		1500 * time.Microsecond,
+	}
+	for _, d := range durations {
+		t.Run(d.String(), func(t *testing.T) {
+			initial := testDurationStruct{Timeout: NewDuration(d)}
+
+			jsonData, err := json.Marshal(initial)
+			assert.NoError(t, err)
+			var fromJSON testDurationStruct
+			assert.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+			assert.Equal(t, initial, fromJSON)
+			jsonDataAgain, err := json.Marshal(fromJSON)
+			assert.NoError(t, err)
+			assert.Equal(t, string(jsonData), string(jsonDataAgain))
+
+			yamlData, err := yaml.Marshal(initial)
+			assert.NoError(t, err)
+			var fromYAML testDurationStruct
+			assert.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+			assert.Equal(t, initial, fromYAML)
+			yamlDataAgain, err := yaml.Marshal(fromYAML)
+			assert.NoError(t, err)
+			assert.Equal(t, string(yamlData), string(yamlDataAgain))
+		})
+	}
+}
+
+func TestDuration_UnmarshalNormalizesAliases(t *testing.T) {
+	for _, alias := range []string{"3600s", "60m", "1h"} {
+		var result testDurationStruct
+		assert.NoError(t, json.Unmarshal([]byte(`{"timeout":"`+alias+`"}`), &result))
+		data, err := json.Marshal(result)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"timeout":"1h"}`, string(data))
+	}
+}
+
+func TestParseDuration_ISO8601(t *testing.T) {
+	testSuite := []struct {
+		value    string
+		expected time.Duration
+		isError  bool
+	}{
+		{value: "PT1H30M", expected: 90 * time.Minute},
+		{value: "PT45S", expected: 45 * time.Second},
+		{value: "PT0S", expected: 0},
+		{value: "P1D", expected: 24 * time.Hour},
+		{value: "P2W", expected: 14 * 24 * time.Hour},
+		{value: "P1M", expected: 30 * 24 * time.Hour},
+		{value: "P1Y", expected: 365 * 24 * time.Hour},
+		{value: "P1DT12H", expected: 36 * time.Hour},
+		{value: "P1Y2M3DT4H5M6S", expected: (365+60+3)*24*time.Hour + 4*time.Hour + 5*time.Minute + 6*time.Second},
+		{value: "P", isError: true},
+		{value: "PT", isError: true},
+		{value: "P1DT", isError: true},
+		{value: "PT1H30", isError: true},
+		{value: "PT1M1H", isError: true},
+		{value: "PT1.5S", isError: true},
+		{value: "pt1h", isError: true},
+		{value: "P300Y", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.value, func(t *testing.T) {
+			result, err := ParseDuration(test.value)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, time.Duration(result))
+		})
+	}
+}
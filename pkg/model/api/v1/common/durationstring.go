@@ -13,7 +13,31 @@
 
 package common
 
-import "encoding/json"
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
+// durationStringUnits are the units of a DurationString, from the largest to the smallest.
+var durationStringUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "y", duration: 365 * 24 * time.Hour},
+	{name: "w", duration: 7 * 24 * time.Hour},
+	{name: "d", duration: 24 * time.Hour},
+	{name: "h", duration: time.Hour},
+	{name: "m", duration: time.Minute},
+	{name: "s", duration: time.Second},
+}
+
+// durationStringPattern must stay identical to the kubebuilder marker of DurationString, as controller-gen only reads the marker.
+const durationStringPattern = `^((([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?|P(([0-9]+)Y)?(([0-9]+)M)?(([0-9]+)W)?(([0-9]+)D)?(T(([0-9]+)H)?(([0-9]+)M)?(([0-9]+)S)?)?)$`
 
 // DurationString is a string that represents a duration, such as "1h", "30m", "15s", etc.
 // It is used to unmarshal a duration string from JSON or YAML, and validate that it is a valid duration string.
@@ -23,11 +47,12 @@ import "encoding/json"
 // For example "14d" will be changed to "2w".
 //
 // So, use DurationString instead of Duration when you want to preserve the original input string.
-// If, for any reason, you need to convert the DurationString to a time.Duration, you can use the ParseDuration function.
+// The only exception is a duration in the ISO 8601 format, like "PT1H30M", which is converted to the Perses format "1h30m".
+// If, for any reason, you need to convert the DurationString to a time.Duration, you can use the method Duration.
 //
 // +kubebuilder:validation:Type=string
 // +kubebuilder:validation:Format=duration
-// +kubebuilder:validation:Pattern=`^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$`
+// +kubebuilder:validation:Pattern=`^((([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?|P(([0-9]+)Y)?(([0-9]+)M)?(([0-9]+)W)?(([0-9]+)D)?(T(([0-9]+)H)?(([0-9]+)M)?(([0-9]+)S)?)?)$`
 //
 // DEPRECATED: this is replaced by the struct github.com/perses/spec/go/common.DurationString
 type DurationString string
@@ -58,10 +83,116 @@ func (d *DurationString) UnmarshalYAML(unmarshal func(any) error) error {
 	return nil
 }
 
+// Duration converts the DurationString to a time.Duration. An empty DurationString is a zero duration.
+func (d DurationString) Duration() (time.Duration, error) {
+	if len(d) == 0 {
+		return 0, nil
+	}
+	result, err := ParseDuration(string(d))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(result), nil
+}
+
+// MustDuration is like Duration but panics if the DurationString is invalid.
+// It should only be used in tests, or to initialize a value known at compile time.
+func (d DurationString) MustDuration() time.Duration {
+	result, err := d.Duration()
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Less reports whether the duration d is shorter than the other one.
+func (d DurationString) Less(other DurationString) (bool, error) {
+	result, err := CompareDuration(d, other)
+	return result < 0, err
+}
+
+// Equal reports whether the durations are the same, even if they are written differently, such as "1h" and "60m".
+func (d DurationString) Equal(other DurationString) (bool, error) {
+	result, err := CompareDuration(d, other)
+	return result == 0, err
+}
+
+// CompareDuration returns -1 if the duration a is shorter than b, 0 if they are equal and +1 if a is longer than b.
+func CompareDuration(a, b DurationString) (int, error) {
+	durationA, err := a.Duration()
+	if err != nil {
+		return 0, err
+	}
+	durationB, err := b.Duration()
+	if err != nil {
+		return 0, err
+	}
+	return cmp.Compare(durationA, durationB), nil
+}
+
+// IsZero reports whether the DurationString is not set.
+func (d DurationString) IsZero() bool {
+	return len(d) == 0
+}
+
+// Add returns the sum of the durations, written with the largest unit that represents it exactly.
+// For example "1h" + "30m" gives "90m", and "12h" + "12h" gives "1d". The sum of two zero durations is empty.
+func (d DurationString) Add(other DurationString) (DurationString, error) {
+	a, err := d.Duration()
+	if err != nil {
+		return "", err
+	}
+	b, err := other.Duration()
+	if err != nil {
+		return "", err
+	}
+	if a > math.MaxInt64-b {
+		return "", fmt.Errorf("the sum of %q and %q is too large", d, other)
+	}
+	return formatDurationString(a + b), nil
+}
+
+func formatDurationString(d time.Duration) DurationString {
+	if d == 0 {
+		return ""
+	}
+	for _, unit := range durationStringUnits {
+		if d%unit.duration == 0 {
+			return DurationString(fmt.Sprintf("%d%s", d/unit.duration, unit.name))
+		}
+	}
+	return DurationString(fmt.Sprintf("%dms", d/time.Millisecond))
+}
+
+func (d DurationString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(d))
+}
+
+func (d DurationString) MarshalYAML() (any, error) {
+	return string(d), nil
+}
+
+// JSONSchema returns the schema of a DurationString, with the same validation as the one coming from its kubebuilder markers.
+// It is used by jsonschema.Reflector when generating the schema of a struct having a DurationString field.
+func (DurationString) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:     "string",
+		Format:   "duration",
+		Pattern:  durationStringPattern,
+		Examples: []any{"30s", "5m", "1h", "1d", "1w", "PT1H30M"},
+	}
+}
+
 func (d *DurationString) validate() error {
 	if len(*d) == 0 {
 		return nil
 	}
-	_, err := ParseDuration(string(*d))
-	return err
+	duration, err := ParseDuration(string(*d))
+	if err != nil {
+		return err
+	}
+	if isISO8601Duration(string(*d)) {
+		*d = DurationString(duration.String())
+	}
+	return nil
 }
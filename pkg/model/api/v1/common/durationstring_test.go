@@ -0,0 +1,248 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDurationString_RoundTrip(t *testing.T) {
+	var d DurationString
+	require.NoError(t, json.Unmarshal([]byte(`"15m"`), &d))
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`"15m"`), data)
+
+	var y DurationString
+	require.NoError(t, yaml.Unmarshal([]byte("15m\n"), &y))
+	data, err = yaml.Marshal(y)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("15m\n"), data)
+}
+
+func TestDurationString_MarshalZeroValue(t *testing.T) {
+	var d DurationString
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `""`, string(data))
+
+	data, err = yaml.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, "\"\"\n", string(data))
+}
+
+func TestDurationString_UnmarshalInvalid(t *testing.T) {
+	var d DurationString
+	assert.Error(t, json.Unmarshal([]byte(`"15 minutes"`), &d))
+	assert.Error(t, yaml.Unmarshal([]byte(`15 minutes`), &d))
+}
+
+func TestDurationString_UnmarshalISO8601(t *testing.T) {
+	testSuite := []struct {
+		value    string
+		expected DurationString
+	}{
+		{value: "PT1H30M", expected: "1h30m"},
+		{value: "PT3600S", expected: "1h"},
+		{value: "P1DT12H", expected: "1d12h"},
+		{value: "P2W", expected: "2w"},
+		{value: "PT0S", expected: "0s"},
+	}
+	for _, test := range testSuite {
+		t.Run(test.value, func(t *testing.T) {
+			var fromJSON DurationString
+			require.NoError(t, json.Unmarshal([]byte(`"`+test.value+`"`), &fromJSON))
+			assert.Equal(t, test.expected, fromJSON)
+			data, err := json.Marshal(fromJSON)
+			require.NoError(t, err)
+			assert.Equal(t, `"`+string(test.expected)+`"`, string(data))
+
+			var fromYAML DurationString
+			require.NoError(t, yaml.Unmarshal([]byte(test.value), &fromYAML))
+			assert.Equal(t, test.expected, fromYAML)
+			data, err = yaml.Marshal(fromYAML)
+			require.NoError(t, err)
+			assert.Equal(t, string(test.expected)+"\n", string(data))
+		})
+	}
+	var d DurationString
+	assert.Error(t, json.Unmarshal([]byte(`"PT"`), &d))
+	assert.Error(t, yaml.Unmarshal([]byte(`P1H`), &d))
+}
+
+func TestDurationString_Duration(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		value    DurationString
+		expected time.Duration
+		isError  bool
+	}{
+		{title: "empty string", value: "", expected: 0},
+		{title: "years", value: "1y", expected: 365 * 24 * time.Hour},
+		{title: "weeks", value: "2w", expected: 14 * 24 * time.Hour},
+		{title: "days", value: "3d", expected: 72 * time.Hour},
+		{title: "hours", value: "4h", expected: 4 * time.Hour},
+		{title: "minutes", value: "15m", expected: 15 * time.Minute},
+		{title: "seconds", value: "30s", expected: 30 * time.Second},
+		{title: "milliseconds", value: "500ms", expected: 500 * time.Millisecond},
+		{title: "hours and minutes", value: "1h30m", expected: 90 * time.Minute},
+		{title: "every unit", value: "1y1w1d1h1m1s1ms", expected: (365+7+1)*24*time.Hour + time.Hour + time.Minute + time.Second + time.Millisecond},
+		{title: "units in the wrong order", value: "30m1h", isError: true},
+		{title: "unknown unit", value: "1mo", isError: true},
+		{title: "missing unit", value: "15", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := test.value.Duration()
+			if test.isError {
+				assert.Error(t, err)
+				assert.Panics(t, func() { test.value.MustDuration() })
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.expected, test.value.MustDuration())
+		})
+	}
+}
+
+func TestCompareDuration(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		a       DurationString
+		b       DurationString
+		result  int
+		isError bool
+	}{
+		{title: "same string", a: "5m", b: "5m", result: 0},
+		{title: "hour and minutes", a: "1h", b: "60m", result: 0},
+		{title: "day and hours", a: "1d", b: "24h", result: 0},
+		{title: "week and days", a: "1w", b: "7d", result: 0},
+		{title: "seconds and milliseconds", a: "1s", b: "1000ms", result: 0},
+		{title: "mixed units", a: "1h30m", b: "90m", result: 0},
+		{title: "empty strings", a: "", b: "", result: 0},
+		{title: "shorter", a: "59m", b: "1h", result: -1},
+		{title: "shorter across units", a: "23h", b: "1d", result: -1},
+		{title: "empty is shorter", a: "", b: "1ms", result: -1},
+		{title: "longer", a: "1d1s", b: "24h", result: 1},
+		{title: "longer across units", a: "1y", b: "52w", result: 1},
+		{title: "invalid first duration", a: "1 hour", b: "1h", isError: true},
+		{title: "invalid second duration", a: "1h", b: "1 hour", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := CompareDuration(test.a, test.b)
+			less, lessErr := test.a.Less(test.b)
+			equal, equalErr := test.a.Equal(test.b)
+			if test.isError {
+				assert.Error(t, err)
+				assert.Error(t, lessErr)
+				assert.Error(t, equalErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, lessErr)
+			require.NoError(t, equalErr)
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, test.result < 0, less)
+			assert.Equal(t, test.result == 0, equal)
+		})
+	}
+}
+
+func TestDurationString_IsZero(t *testing.T) {
+	assert.True(t, DurationString("").IsZero())
+	assert.False(t, DurationString("0s").IsZero())
+	assert.False(t, DurationString("1h").IsZero())
+}
+
+func TestDurationString_Add(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		a       DurationString
+		b       DurationString
+		result  DurationString
+		isError bool
+	}{
+		{title: "empty strings", a: "", b: "", result: ""},
+		{title: "add to empty", a: "", b: "15m", result: "15m"},
+		{title: "add empty", a: "15m", b: "", result: "15m"},
+		{title: "same unit", a: "30s", b: "30s", result: "1m"},
+		{title: "whole unit is kept", a: "1h", b: "30m", result: "90m"},
+		{title: "largest unit", a: "12h", b: "12h", result: "1d"},
+		{title: "weeks", a: "6d", b: "24h", result: "1w"},
+		{title: "years", a: "52w", b: "1d", result: "1y"},
+		{title: "milliseconds", a: "1s", b: "500ms", result: "1500ms"},
+		{title: "mixed units", a: "1h30m", b: "1h30m", result: "3h"},
+		{title: "invalid first duration", a: "1 hour", b: "1h", isError: true},
+		{title: "invalid second duration", a: "1h", b: "1 hour", isError: true},
+		{title: "overflow", a: "292y", b: "292y", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			a := test.a
+			result, err := a.Add(test.b)
+			assert.Equal(t, test.a, a)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func fuzzDurationString(ms uint32) DurationString {
+	return formatDurationString(time.Duration(ms) * time.Millisecond)
+}
+
+func FuzzDurationString_AddCommutative(f *testing.F) {
+	f.Add(uint32(0), uint32(0))
+	f.Add(uint32(1500), uint32(60000))
+	f.Add(uint32(3600000), uint32(86400000))
+	f.Fuzz(func(t *testing.T, x, y uint32) {
+		a, b := fuzzDurationString(x), fuzzDurationString(y)
+		ab, err := a.Add(b)
+		require.NoError(t, err)
+		ba, err := b.Add(a)
+		require.NoError(t, err)
+		assert.Equal(t, ab, ba)
+		assert.Equal(t, time.Duration(uint64(x)+uint64(y))*time.Millisecond, ab.MustDuration())
+	})
+}
+
+func FuzzDurationString_AddAssociative(f *testing.F) {
+	f.Add(uint32(0), uint32(0), uint32(0))
+	f.Add(uint32(1500), uint32(60000), uint32(500))
+	f.Add(uint32(3600000), uint32(86400000), uint32(604800000))
+	f.Fuzz(func(t *testing.T, x, y, z uint32) {
+		a, b, c := fuzzDurationString(x), fuzzDurationString(y), fuzzDurationString(z)
+		ab, err := a.Add(b)
+		require.NoError(t, err)
+		left, err := ab.Add(c)
+		require.NoError(t, err)
+		bc, err := b.Add(c)
+		require.NoError(t, err)
+		right, err := a.Add(bc)
+		require.NoError(t, err)
+		assert.Equal(t, left, right)
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HTTPHeaders is a set of custom HTTP headers.
+// The keys are canonicalized when unmarshalling (e.g. "x-scope-orgid" becomes "X-Scope-Orgid"),
+// so two keys only differing by their case are considered as the same header and are rejected.
+type HTTPHeaders map[string]string
+
+func (h *HTTPHeaders) UnmarshalJSON(bytes []byte) error {
+	var tmp map[string]string
+	if err := json.Unmarshal(bytes, &tmp); err != nil {
+		return err
+	}
+	result, err := canonicalizeHeaders(tmp)
+	if err != nil {
+		return err
+	}
+	*h = result
+	return nil
+}
+
+func (h *HTTPHeaders) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp map[string]string
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	result, err := canonicalizeHeaders(tmp)
+	if err != nil {
+		return err
+	}
+	*h = result
+	return nil
+}
+
+// ToHTTPHeader converts the headers into a http.Header that can be added to a request.
+func (h HTTPHeaders) ToHTTPHeader() http.Header {
+	result := make(http.Header, len(h))
+	for key, value := range h {
+		result.Set(key, value)
+	}
+	return result
+}
+
+func canonicalizeHeaders(headers map[string]string) (HTTPHeaders, error) {
+	if headers == nil {
+		return nil, nil
+	}
+	result := make(HTTPHeaders, len(headers))
+	for key, value := range headers {
+		if len(strings.TrimSpace(key)) == 0 {
+			return nil, fmt.Errorf("header name cannot be empty")
+		}
+		if len(strings.TrimSpace(value)) == 0 {
+			return nil, fmt.Errorf("value of the header %q cannot be empty", key)
+		}
+		canonicalKey := http.CanonicalHeaderKey(key)
+		if _, exist := result[canonicalKey]; exist {
+			return nil, fmt.Errorf("header %q is defined more than once", canonicalKey)
+		}
+		result[canonicalKey] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testHTTPHeadersStruct struct {
+	Headers HTTPHeaders `json:"headers" yaml:"headers"`
+}
+
+func TestHTTPHeaders_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		jason  string
+		yamele string
+		result HTTPHeaders
+	}{
+		{
+			title:  "keys are canonicalized",
+			jason:  `{"headers": {"x-scope-orgid": "perses", "AUTHORIZATION": "Bearer token"}}`,
+			yamele: "headers:\n  x-scope-orgid: perses\n  AUTHORIZATION: Bearer token\n",
+			result: HTTPHeaders{"X-Scope-Orgid": "perses", "Authorization": "Bearer token"},
+		},
+		{
+			title:  "no headers",
+			jason:  `{}`,
+			yamele: "{}",
+			result: nil,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testHTTPHeadersStruct{}
+			assert.NoError(t, json.Unmarshal([]byte(test.jason), jsonResult))
+			assert.Equal(t, test.result, jsonResult.Headers)
+			yamlResult := &testHTTPHeadersStruct{}
+			assert.NoError(t, yaml.Unmarshal([]byte(test.yamele), yamlResult))
+			assert.Equal(t, test.result, yamlResult.Headers)
+		})
+	}
+}
+
+func TestHTTPHeaders_UnmarshalError(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		jason  string
+		yamele string
+	}{
+		{
+			title:  "empty key",
+			jason:  `{"headers": {"": "perses"}}`,
+			yamele: "headers:\n  \"\": perses\n",
+		},
+		{
+			title:  "empty value",
+			jason:  `{"headers": {"X-Scope-OrgID": " "}}`,
+			yamele: "headers:\n  X-Scope-OrgID: \" \"\n",
+		},
+		{
+			title:  "same key with a different case",
+			jason:  `{"headers": {"x-scope-orgid": "a", "X-Scope-OrgID": "b"}}`,
+			yamele: "headers:\n  x-scope-orgid: a\n  X-Scope-OrgID: b\n",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal([]byte(test.jason), &testHTTPHeadersStruct{}))
+			assert.Error(t, yaml.Unmarshal([]byte(test.yamele), &testHTTPHeadersStruct{}))
+		})
+	}
+}
+
+func TestHTTPHeaders_ToHTTPHeader(t *testing.T) {
+	headers := HTTPHeaders{"X-Scope-Orgid": "perses", "Authorization": "Bearer token"}
+	expected := http.Header{
+		"X-Scope-Orgid": []string{"perses"},
+		"Authorization": []string{"Bearer token"},
+	}
+	assert.Equal(t, expected, headers.ToHTTPHeader())
+	assert.Equal(t, "perses", headers.ToHTTPHeader().Get("x-scope-orgid"))
+}
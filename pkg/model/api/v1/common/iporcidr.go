@@ -0,0 +1,104 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IPOrCIDR is either a single IP address (v4 or v6) or a network in the CIDR notation, like 10.0.0.0/8.
+// An error is returned when unmarshalling a value that is neither of them.
+// +kubebuilder:validation:Schemaless
+// +kubebuilder:validation:Type=string
+type IPOrCIDR struct {
+	network *net.IPNet
+}
+
+// ParseIPOrCIDR parses the given IP address or CIDR.
+func ParseIPOrCIDR(value string) (IPOrCIDR, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return IPOrCIDR{}, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		return IPOrCIDR{network: network}, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return IPOrCIDR{}, fmt.Errorf("%q is neither an IP address nor a CIDR", value)
+	}
+	// A single IP is a network containing only this IP.
+	bits := 8 * net.IPv6len
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		bits = 8 * net.IPv4len
+	}
+	return IPOrCIDR{network: &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}}, nil
+}
+
+// Contains returns true if the given IP is the IP address, or if it belongs to the network.
+func (i IPOrCIDR) Contains(ip net.IP) bool {
+	return i.network != nil && i.network.Contains(ip)
+}
+
+// IPNet returns the network. A single IP address is returned as a network having a full mask.
+func (i IPOrCIDR) IPNet() *net.IPNet {
+	return i.network
+}
+
+func (i IPOrCIDR) String() string {
+	if i.network == nil {
+		return ""
+	}
+	if ones, bits := i.network.Mask.Size(); ones == bits {
+		return i.network.IP.String()
+	}
+	return i.network.String()
+}
+
+func (i *IPOrCIDR) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return i.set(s)
+}
+
+func (i *IPOrCIDR) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return i.set(s)
+}
+
+func (i IPOrCIDR) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+func (i IPOrCIDR) MarshalYAML() (any, error) {
+	return i.String(), nil
+}
+
+func (i *IPOrCIDR) set(value string) error {
+	result, err := ParseIPOrCIDR(value)
+	if err != nil {
+		return err
+	}
+	*i = result
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testIPOrCIDRStruct struct {
+	Address IPOrCIDR `json:"address" yaml:"address"`
+}
+
+func TestIPOrCIDR_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		result  string
+		isError bool
+	}{
+		{title: "ipv4", value: "192.168.1.10", result: "192.168.1.10"},
+		{title: "ipv6", value: "2001:db8::1", result: "2001:db8::1"},
+		{title: "ipv4 cidr", value: "10.0.0.0/8", result: "10.0.0.0/8"},
+		{title: "cidr is normalized", value: "10.1.2.3/16", result: "10.1.0.0/16"},
+		{title: "ipv6 cidr", value: "2001:db8::/32", result: "2001:db8::/32"},
+		{title: "empty", value: "", isError: true},
+		{title: "hostname", value: "proxy.example.com", isError: true},
+		{title: "ip out of range", value: "256.0.0.1", isError: true},
+		{title: "mask out of range", value: "10.0.0.0/33", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testIPOrCIDRStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"address":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testIPOrCIDRStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`address: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Address.String())
+			assert.Equal(t, test.result, yamlResult.Address.String())
+		})
+	}
+}
+
+func TestIPOrCIDR_Marshal(t *testing.T) {
+	address, err := ParseIPOrCIDR("172.16.0.0/12")
+	assert.NoError(t, err)
+	data, err := json.Marshal(testIPOrCIDRStruct{Address: address})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"address":"172.16.0.0/12"}`, string(data))
+}
+
+func TestIPOrCIDR_Contains(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		address  string
+		ip       string
+		contains bool
+	}{
+		{title: "same ip", address: "192.168.1.10", ip: "192.168.1.10", contains: true},
+		{title: "other ip", address: "192.168.1.10", ip: "192.168.1.11", contains: false},
+		{title: "ipv4 mapped in ipv6", address: "192.168.1.10", ip: "::ffff:192.168.1.10", contains: true},
+		{title: "ip in the network", address: "10.0.0.0/8", ip: "10.20.30.40", contains: true},
+		{title: "ip outside the network", address: "10.0.0.0/8", ip: "11.0.0.1", contains: false},
+		{title: "ipv6 in the network", address: "2001:db8::/32", ip: "2001:db8:1::1", contains: true},
+		{title: "ipv4 in an ipv6 network", address: "2001:db8::/32", ip: "10.0.0.1", contains: false},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			address, err := ParseIPOrCIDR(test.address)
+			assert.NoError(t, err)
+			assert.Equal(t, test.contains, address.Contains(net.ParseIP(test.ip)))
+		})
+	}
+	assert.False(t, IPOrCIDR{}.Contains(net.ParseIP("10.0.0.1")))
+}
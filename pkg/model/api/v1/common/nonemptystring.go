@@ -0,0 +1,72 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// NonEmptyString is a string that cannot be empty or contain only whitespaces.
+// An error is returned when unmarshalling such a value from JSON or YAML.
+// Note that the validation only happens when the field is present, an omitted field stays empty.
+type NonEmptyString string
+
+// NewNonEmptyString checks the given string is not empty or whitespace-only.
+// When trimSpace is true, the leading and trailing whitespaces are removed from the value returned.
+func NewNonEmptyString(value string, trimSpace bool) (NonEmptyString, error) {
+	s := NonEmptyString(value)
+	if trimSpace {
+		s = NonEmptyString(strings.TrimSpace(value))
+	}
+	return s, s.validate()
+}
+
+func (s *NonEmptyString) UnmarshalJSON(bytes []byte) error {
+	var tmp NonEmptyString
+	type plain NonEmptyString
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *NonEmptyString) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp NonEmptyString
+	type plain NonEmptyString
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s NonEmptyString) String() string {
+	return string(s)
+}
+
+func (s NonEmptyString) validate() error {
+	if len(strings.TrimSpace(string(s))) == 0 {
+		return errors.New("value cannot be empty or contain only whitespaces")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testNonEmptyStringStruct struct {
+	Value NonEmptyString `json:"value" yaml:"value"`
+}
+
+func TestNonEmptyString_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		isError bool
+	}{
+		{title: "empty string", value: "", isError: true},
+		{title: "whitespace-only string", value: " \t ", isError: true},
+		{title: "valid string", value: "perses"},
+		{title: "valid string surrounded by whitespaces", value: " perses "},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testNonEmptyStringStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"value":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testNonEmptyStringStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`value: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, NonEmptyString(test.value), jsonResult.Value)
+			assert.Equal(t, NonEmptyString(test.value), yamlResult.Value)
+		})
+	}
+}
+
+func TestNonEmptyString_MissingField(t *testing.T) {
+	result := &testNonEmptyStringStruct{}
+	assert.NoError(t, json.Unmarshal([]byte(`{}`), result))
+	assert.Equal(t, NonEmptyString(""), result.Value)
+}
+
+func TestNewNonEmptyString(t *testing.T) {
+	_, err := NewNonEmptyString("  ", true)
+	assert.Error(t, err)
+	_, err = NewNonEmptyString("", false)
+	assert.Error(t, err)
+
+	s, err := NewNonEmptyString(" perses ", true)
+	assert.NoError(t, err)
+	assert.Equal(t, NonEmptyString("perses"), s)
+
+	s, err = NewNonEmptyString(" perses ", false)
+	assert.NoError(t, err)
+	assert.Equal(t, NonEmptyString(" perses "), s)
+}
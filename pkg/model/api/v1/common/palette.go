@@ -0,0 +1,91 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// PersesPrimary is the default palette of Perses.
+var PersesPrimary = Palette{"#1473E6", "#E66914", "#2AB069", "#D4376E", "#8855D6", "#E6B414", "#14A5B8", "#7A8A99"}
+
+// Colorblind is the Okabe-Ito palette, whose colors can be distinguished with the most common color vision deficiencies.
+var Colorblind = Palette{"#E69F00", "#56B4E9", "#009E73", "#F0E442", "#0072B2", "#D55E00", "#CC79A7", "#000000"}
+
+// Palette is the list of colors given to the series of a dashboard, so a series has the same color in every panel.
+type Palette []ColorHex
+
+func (p *Palette) UnmarshalJSON(bytes []byte) error {
+	var tmp Palette
+	type plain Palette
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*p = tmp
+	return nil
+}
+
+func (p *Palette) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp Palette
+	type plain Palette
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*p = tmp
+	return nil
+}
+
+// IsDefined returns false when the palette doesn't have any color, like its zero value.
+func (p Palette) IsDefined() bool {
+	return len(p) > 0
+}
+
+// ColorFor returns the color of the series at the given index. The colors are reused from the beginning of the palette
+// when the index is greater than the number of colors. An undefined palette returns an empty color.
+func (p Palette) ColorFor(index int) ColorHex {
+	if !p.IsDefined() {
+		return ""
+	}
+	i := index % len(p)
+	if i < 0 {
+		i += len(p)
+	}
+	return p[i]
+}
+
+// Merge returns a new palette made of the colors of p followed by the ones of other, each color appearing only once.
+func (p Palette) Merge(other Palette) Palette {
+	result := make(Palette, 0, len(p)+len(other))
+	for _, color := range slices.Concat(p, other) {
+		if !slices.Contains(result, color) {
+			result = append(result, color)
+		}
+	}
+	return result
+}
+
+func (p Palette) validate() error {
+	if p != nil && len(p) == 0 {
+		return fmt.Errorf("a palette must contain at least one color")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testPaletteStruct struct {
+	Palette Palette `json:"palette" yaml:"palette"`
+}
+
+func TestPalette_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		json    string
+		yaml    string
+		result  Palette
+		isError bool
+	}{
+		{
+			title:  "no palette",
+			json:   `{}`,
+			yaml:   `{}`,
+			result: nil,
+		},
+		{
+			title:  "normalized colors",
+			json:   `{"palette":["#0f0","#ffa500"]}`,
+			yaml:   "palette:\n- '#0f0'\n- '#ffa500'",
+			result: Palette{"#00FF00", "#FFA500"},
+		},
+		{
+			title:   "empty palette",
+			json:    `{"palette":[]}`,
+			yaml:    `palette: []`,
+			isError: true,
+		},
+		{
+			title:   "invalid color",
+			json:    `{"palette":["#0f0","red"]}`,
+			yaml:    "palette:\n- '#0f0'\n- red",
+			isError: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testPaletteStruct{}
+			jsonErr := json.Unmarshal([]byte(test.json), jsonResult)
+			yamlResult := &testPaletteStruct{}
+			yamlErr := yaml.Unmarshal([]byte(test.yaml), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Palette)
+			assert.Equal(t, test.result, yamlResult.Palette)
+		})
+	}
+}
+
+func TestPalette_ColorFor(t *testing.T) {
+	palette := Palette{"#FF0000", "#00FF00", "#0000FF"}
+	testSuite := []struct {
+		index  int
+		result ColorHex
+	}{
+		{index: 0, result: "#FF0000"},
+		{index: 2, result: "#0000FF"},
+		{index: 3, result: "#FF0000"},
+		{index: 7, result: "#00FF00"},
+		{index: -1, result: "#0000FF"},
+	}
+	for _, test := range testSuite {
+		assert.Equal(t, test.result, palette.ColorFor(test.index))
+	}
+	assert.Equal(t, ColorHex(""), Palette(nil).ColorFor(1))
+}
+
+func TestPalette_IsDefined(t *testing.T) {
+	assert.False(t, Palette(nil).IsDefined())
+	assert.False(t, Palette{}.IsDefined())
+	assert.True(t, Palette{"#FF0000"}.IsDefined())
+	assert.True(t, PersesPrimary.IsDefined())
+	assert.True(t, Colorblind.IsDefined())
+}
+
+func TestPalette_Merge(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		a      Palette
+		b      Palette
+		result Palette
+	}{
+		{
+			title:  "undefined palettes",
+			result: Palette{},
+		},
+		{
+			title:  "merge into an undefined palette",
+			b:      Palette{"#FF0000"},
+			result: Palette{"#FF0000"},
+		},
+		{
+			title:  "colors are appended",
+			a:      Palette{"#FF0000", "#00FF00"},
+			b:      Palette{"#0000FF"},
+			result: Palette{"#FF0000", "#00FF00", "#0000FF"},
+		},
+		{
+			title:  "common colors are kept once",
+			a:      Palette{"#FF0000", "#00FF00"},
+			b:      Palette{"#00FF00", "#0000FF", "#FF0000"},
+			result: Palette{"#FF0000", "#00FF00", "#0000FF"},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			a := append(Palette{}, test.a...)
+			assert.Equal(t, test.result, test.a.Merge(test.b))
+			assert.Equal(t, a, append(Palette{}, test.a...))
+		})
+	}
+}
+
+func TestPalette_Builtin(t *testing.T) {
+	for _, palette := range []Palette{PersesPrimary, Colorblind} {
+		for _, color := range palette {
+			normalized, err := NewColorHex(string(color))
+			assert.NoError(t, err)
+			assert.Equal(t, color, normalized)
+		}
+	}
+}
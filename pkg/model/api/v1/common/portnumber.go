@@ -0,0 +1,68 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PortNumber is a TCP/UDP port number. An error is returned when unmarshalling a value outside the range 1-65535.
+type PortNumber uint16
+
+// NewPortNumber checks the given value is a valid port number.
+func NewPortNumber(value int) (PortNumber, error) {
+	if err := validatePortNumber(int64(value)); err != nil {
+		return 0, err
+	}
+	return PortNumber(value), nil
+}
+
+func (p *PortNumber) UnmarshalJSON(bytes []byte) error {
+	// The value is decoded in a larger type to be able to return a proper error when it doesn't fit in an uint16.
+	var tmp int64
+	if err := json.Unmarshal(bytes, &tmp); err != nil {
+		return err
+	}
+	if err := validatePortNumber(tmp); err != nil {
+		return err
+	}
+	*p = PortNumber(tmp)
+	return nil
+}
+
+func (p *PortNumber) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp int64
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	if err := validatePortNumber(tmp); err != nil {
+		return err
+	}
+	*p = PortNumber(tmp)
+	return nil
+}
+
+func (p PortNumber) String() string {
+	return strconv.Itoa(int(p))
+}
+
+func validatePortNumber(value int64) error {
+	if value < 1 || value > math.MaxUint16 {
+		return fmt.Errorf("invalid port number %d, it must be between 1 and %d", value, math.MaxUint16)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testPortNumberStruct struct {
+	Port PortNumber `json:"port" yaml:"port"`
+}
+
+func TestPortNumber_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		result  PortNumber
+		isError bool
+	}{
+		{title: "negative value", value: "-1", isError: true},
+		{title: "zero", value: "0", isError: true},
+		{title: "lower bound", value: "1", result: 1},
+		{title: "common port", value: "8080", result: 8080},
+		{title: "upper bound", value: "65535", result: 65535},
+		{title: "above upper bound", value: "65536", isError: true},
+		{title: "not a number", value: `"http"`, isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testPortNumberStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"port":`+test.value+`}`), jsonResult)
+			yamlResult := &testPortNumberStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`port: `+test.value), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Port)
+			assert.Equal(t, test.result, yamlResult.Port)
+		})
+	}
+}
+
+func TestPortNumber_String(t *testing.T) {
+	assert.Equal(t, "65535", PortNumber(65535).String())
+}
+
+func TestNewPortNumber(t *testing.T) {
+	_, err := NewPortNumber(0)
+	assert.Error(t, err)
+	_, err = NewPortNumber(65536)
+	assert.Error(t, err)
+	p, err := NewPortNumber(443)
+	assert.NoError(t, err)
+	assert.Equal(t, PortNumber(443), p)
+}
@@ -0,0 +1,41 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+)
+
+// GenerateCRDSchema returns the JSON schema of the given type, ready to be embedded in the openAPIV3Schema of a CRD.
+// Every type is inlined, as a CRD schema cannot use references, and the types implementing the method JSONSchema,
+// like DurationString, use the schema they provide.
+func GenerateCRDSchema(resourceType any) json.RawMessage {
+	reflector := &jsonschema.Reflector{
+		DoNotReference: true,
+		ExpandedStruct: true,
+		Anonymous:      true,
+	}
+	schema := reflector.Reflect(resourceType)
+	// The version of the JSON schema specification is not part of a CRD schema.
+	schema.Version = ""
+	data, err := json.Marshal(schema)
+	if err != nil {
+		// A schema is only made of JSON compatible values, so this can only come from a bug in the generation.
+		panic(fmt.Sprintf("unable to marshal the schema of %T: %s", resourceType, err))
+	}
+	return data
+}
@@ -0,0 +1,66 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kubebuilderMarker extracts the value of a kubebuilder validation marker from the given source.
+func kubebuilderMarker(t *testing.T, source []byte, marker string) string {
+	matches := regexp.MustCompile(`// \+kubebuilder:validation:` + marker + "=`?([^`\n]+)`?\n").FindSubmatch(source)
+	require.Len(t, matches, 2, "marker %s not found", marker)
+	return string(matches[1])
+}
+
+func TestDurationStringJSONSchemaMatchesKubebuilderMarkers(t *testing.T) {
+	source, err := os.ReadFile("durationstring.go")
+	require.NoError(t, err)
+	schema := DurationString("").JSONSchema()
+	assert.Equal(t, kubebuilderMarker(t, source, "Type"), schema.Type)
+	assert.Equal(t, kubebuilderMarker(t, source, "Format"), schema.Format)
+	assert.Equal(t, kubebuilderMarker(t, source, "Pattern"), schema.Pattern)
+	for _, example := range schema.Examples {
+		assert.Regexp(t, schema.Pattern, example)
+	}
+}
+
+func TestGenerateCRDSchema(t *testing.T) {
+	type spec struct {
+		Name     string          `json:"name"`
+		Interval DurationString  `json:"interval"`
+		Timeout  *DurationString `json:"timeout,omitempty"`
+	}
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(GenerateCRDSchema(&spec{}), &result))
+
+	assert.NotContains(t, result, "$schema")
+	assert.NotContains(t, result, "$defs")
+	assert.Equal(t, "object", result["type"])
+	assert.Equal(t, []any{"name", "interval"}, result["required"])
+	properties := result["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, properties["name"])
+	for _, field := range []string{"interval", "timeout"} {
+		durationSchema := properties[field].(map[string]any)
+		assert.Equal(t, "string", durationSchema["type"])
+		assert.Equal(t, "duration", durationSchema["format"])
+		assert.Equal(t, durationStringPattern, durationSchema["pattern"])
+	}
+}
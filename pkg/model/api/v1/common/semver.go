@@ -0,0 +1,145 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Semver is a version following the semantic versioning convention (https://semver.org).
+// The leading "v" is optional, so both "1.2.3" and "v1.2.3" are accepted and the value is kept as written.
+type Semver string
+
+// NewSemver checks the given string is a valid semantic version.
+func NewSemver(value string) (Semver, error) {
+	s := Semver(value)
+	return s, s.validate()
+}
+
+func (s *Semver) UnmarshalJSON(bytes []byte) error {
+	var tmp Semver
+	type plain Semver
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s *Semver) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp Semver
+	type plain Semver
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*s = tmp
+	return nil
+}
+
+func (s Semver) String() string {
+	return string(s)
+}
+
+// Compare returns 0 if s == other, -1 if s < other and +1 if s > other, following the semver precedence rules.
+// An invalid version is considered lower than any valid one.
+func (s Semver) Compare(other Semver) int {
+	return semver.Compare(canonicalSemver(string(s)), canonicalSemver(string(other)))
+}
+
+// IsCompatible returns true when the version satisfies the given constraint.
+// A constraint is a list of conditions separated by commas or spaces that must all be satisfied. Each condition is
+// a version optionally prefixed by one of these operators:
+//   - "=" (or no operator), "!=", ">", ">=", "<", "<=" compare the versions.
+//   - "^" accepts any version greater or equal within the same major version (same minor version for v0).
+//   - "~" accepts any version greater or equal within the same minor version.
+//
+// An empty constraint is satisfied by any valid version, an invalid constraint by none.
+func (s Semver) IsCompatible(constraint string) bool {
+	if s.validate() != nil {
+		return false
+	}
+	conditions := strings.FieldsFunc(constraint, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for _, condition := range conditions {
+		if !s.satisfies(condition) {
+			return false
+		}
+	}
+	return true
+}
+
+var constraintOperators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+func (s Semver) satisfies(condition string) bool {
+	operator := ""
+	for _, op := range constraintOperators {
+		if strings.HasPrefix(condition, op) {
+			operator = op
+			break
+		}
+	}
+	target := canonicalSemver(strings.TrimPrefix(condition, operator))
+	if !semver.IsValid(target) {
+		return false
+	}
+	current := canonicalSemver(string(s))
+	cmp := semver.Compare(current, target)
+	switch operator {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "^":
+		if semver.Major(target) == "v0" {
+			return cmp >= 0 && semver.MajorMinor(current) == semver.MajorMinor(target)
+		}
+		return cmp >= 0 && semver.Major(current) == semver.Major(target)
+	case "~":
+		return cmp >= 0 && semver.MajorMinor(current) == semver.MajorMinor(target)
+	default:
+		return cmp == 0
+	}
+}
+
+func (s Semver) validate() error {
+	if !semver.IsValid(canonicalSemver(string(s))) {
+		return fmt.Errorf("%q is not a valid semantic version", string(s))
+	}
+	return nil
+}
+
+// canonicalSemver adds the "v" prefix expected by golang.org/x/mod/semver when it is missing.
+func canonicalSemver(version string) string {
+	if len(version) > 0 && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
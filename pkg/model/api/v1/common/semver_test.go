@@ -0,0 +1,112 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testSemverStruct struct {
+	Version Semver `json:"version" yaml:"version"`
+}
+
+func TestSemver_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   string
+		isError bool
+	}{
+		{title: "full version", value: "1.2.3"},
+		{title: "version with v prefix", value: "v1.2.3"},
+		{title: "pre-release version", value: "0.5.0-beta.1"},
+		{title: "version with build metadata", value: "2.0.0+build.42"},
+		{title: "empty string", value: "", isError: true},
+		{title: "random string", value: "latest", isError: true},
+		{title: "too many numbers", value: "1.2.3.4", isError: true},
+		{title: "leading zero", value: "01.2.3", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testSemverStruct{}
+			jsonErr := json.Unmarshal([]byte(`{"version":"`+test.value+`"}`), jsonResult)
+			yamlResult := &testSemverStruct{}
+			yamlErr := yaml.Unmarshal([]byte(`version: "`+test.value+`"`), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, Semver(test.value), jsonResult.Version)
+			assert.Equal(t, Semver(test.value), yamlResult.Version)
+		})
+	}
+}
+
+func TestSemver_Compare(t *testing.T) {
+	testSuite := []struct {
+		a      Semver
+		b      Semver
+		result int
+	}{
+		{a: "1.2.3", b: "v1.2.3", result: 0},
+		{a: "1.2.3", b: "1.2.4", result: -1},
+		{a: "1.10.0", b: "1.9.0", result: 1},
+		{a: "2.0.0", b: "10.0.0", result: -1},
+		{a: "1.0.0-alpha", b: "1.0.0", result: -1},
+		{a: "1.0.0-alpha.2", b: "1.0.0-alpha.10", result: -1},
+		{a: "1.0.0+build.1", b: "1.0.0+build.2", result: 0},
+	}
+	for _, test := range testSuite {
+		t.Run(string(test.a)+" vs "+string(test.b), func(t *testing.T) {
+			assert.Equal(t, test.result, test.a.Compare(test.b))
+			assert.Equal(t, -test.result, test.b.Compare(test.a))
+		})
+	}
+}
+
+func TestSemver_IsCompatible(t *testing.T) {
+	testSuite := []struct {
+		version    Semver
+		constraint string
+		result     bool
+	}{
+		{version: "1.2.3", constraint: "", result: true},
+		{version: "1.2.3", constraint: "1.2.3", result: true},
+		{version: "1.2.3", constraint: "=v1.2.3", result: true},
+		{version: "1.2.3", constraint: "!=1.2.3", result: false},
+		{version: "1.2.3", constraint: ">=1.2.0", result: true},
+		{version: "1.2.3", constraint: ">1.2.3", result: false},
+		{version: "1.2.3", constraint: ">=1.0.0, <2.0.0", result: true},
+		{version: "2.0.0", constraint: ">=1.0.0 <2.0.0", result: false},
+		{version: "1.9.0", constraint: "^1.2.0", result: true},
+		{version: "2.0.0", constraint: "^1.2.0", result: false},
+		{version: "0.3.1", constraint: "^0.3.0", result: true},
+		{version: "0.4.0", constraint: "^0.3.0", result: false},
+		{version: "1.2.9", constraint: "~1.2.3", result: true},
+		{version: "1.3.0", constraint: "~1.2.3", result: false},
+		{version: "1.2.3", constraint: ">=not-a-version", result: false},
+		{version: "invalid", constraint: "", result: false},
+	}
+	for _, test := range testSuite {
+		t.Run(string(test.version)+" "+test.constraint, func(t *testing.T) {
+			assert.Equal(t, test.result, test.version.IsCompatible(test.constraint))
+		})
+	}
+}
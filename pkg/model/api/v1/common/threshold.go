@@ -0,0 +1,88 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Threshold is a step of a ThresholdList: the values greater than or equal to Value are displayed with Color.
+type Threshold struct {
+	Value float64  `json:"value" yaml:"value"`
+	Color ColorHex `json:"color" yaml:"color"`
+}
+
+// ThresholdList is the list of thresholds used by the panels, like the stat and gauge panels, to color a value.
+// The thresholds must be in ascending order of their value when the list is unmarshalled from JSON or YAML.
+type ThresholdList []Threshold
+
+func (l *ThresholdList) UnmarshalJSON(bytes []byte) error {
+	var tmp ThresholdList
+	type plain ThresholdList
+	if err := json.Unmarshal(bytes, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*l = tmp
+	return nil
+}
+
+func (l *ThresholdList) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp ThresholdList
+	type plain ThresholdList
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := tmp.validate(); err != nil {
+		return err
+	}
+	*l = tmp
+	return nil
+}
+
+// Sort returns a copy of the list in ascending order of the threshold values.
+func (l ThresholdList) Sort() ThresholdList {
+	result := make(ThresholdList, len(l))
+	copy(result, l)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// Lookup returns the threshold with the largest value lower than or equal to v, or nil when v is below every threshold.
+// The list must be sorted.
+func (l ThresholdList) Lookup(v float64) *Threshold {
+	// index of the first threshold greater than v
+	i := sort.Search(len(l), func(i int) bool {
+		return l[i].Value > v
+	})
+	if i == 0 {
+		return nil
+	}
+	return &l[i-1]
+}
+
+func (l ThresholdList) validate() error {
+	for i := 1; i < len(l); i++ {
+		if l[i].Value <= l[i-1].Value {
+			return fmt.Errorf("the thresholds must be in ascending order, but the value %g comes after %g", l[i].Value, l[i-1].Value)
+		}
+	}
+	return nil
+}
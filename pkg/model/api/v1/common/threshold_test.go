@@ -0,0 +1,112 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testThresholdListStruct struct {
+	Thresholds ThresholdList `json:"thresholds" yaml:"thresholds"`
+}
+
+func TestThresholdList_Unmarshal(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		json    string
+		yaml    string
+		result  ThresholdList
+		isError bool
+	}{
+		{
+			title:  "empty list",
+			json:   `{"thresholds":[]}`,
+			yaml:   `thresholds: []`,
+			result: ThresholdList{},
+		},
+		{
+			title:  "ascending thresholds",
+			json:   `{"thresholds":[{"value":0,"color":"#0f0"},{"value":80,"color":"#FFA500"},{"value":90.5,"color":"#ff0000"}]}`,
+			yaml:   "thresholds:\n- value: 0\n  color: '#0f0'\n- value: 80\n  color: '#FFA500'\n- value: 90.5\n  color: '#ff0000'",
+			result: ThresholdList{{Value: 0, Color: "#00FF00"}, {Value: 80, Color: "#FFA500"}, {Value: 90.5, Color: "#FF0000"}},
+		},
+		{
+			title:   "descending thresholds",
+			json:    `{"thresholds":[{"value":90,"color":"#FF0000"},{"value":80,"color":"#FFA500"}]}`,
+			yaml:    "thresholds:\n- value: 90\n  color: '#FF0000'\n- value: 80\n  color: '#FFA500'",
+			isError: true,
+		},
+		{
+			title:   "duplicated value",
+			json:    `{"thresholds":[{"value":80,"color":"#FF0000"},{"value":80,"color":"#FFA500"}]}`,
+			yaml:    "thresholds:\n- value: 80\n  color: '#FF0000'\n- value: 80\n  color: '#FFA500'",
+			isError: true,
+		},
+		{
+			title:   "invalid color",
+			json:    `{"thresholds":[{"value":80,"color":"red"}]}`,
+			yaml:    "thresholds:\n- value: 80\n  color: red",
+			isError: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := &testThresholdListStruct{}
+			jsonErr := json.Unmarshal([]byte(test.json), jsonResult)
+			yamlResult := &testThresholdListStruct{}
+			yamlErr := yaml.Unmarshal([]byte(test.yaml), yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Thresholds)
+			assert.Equal(t, test.result, yamlResult.Thresholds)
+		})
+	}
+}
+
+func TestThresholdList_Sort(t *testing.T) {
+	list := ThresholdList{{Value: 90, Color: "#FF0000"}, {Value: 0, Color: "#00FF00"}, {Value: 80, Color: "#FFA500"}}
+	assert.Equal(t, ThresholdList{{Value: 0, Color: "#00FF00"}, {Value: 80, Color: "#FFA500"}, {Value: 90, Color: "#FF0000"}}, list.Sort())
+	// the list itself is left untouched
+	assert.Equal(t, 90.0, list[0].Value)
+}
+
+func TestThresholdList_Lookup(t *testing.T) {
+	list := ThresholdList{{Value: 0, Color: "#00FF00"}, {Value: 80, Color: "#FFA500"}, {Value: 90, Color: "#FF0000"}}
+	testSuite := []struct {
+		title  string
+		value  float64
+		result *Threshold
+	}{
+		{title: "below every threshold", value: -1},
+		{title: "equal to the first threshold", value: 0, result: &list[0]},
+		{title: "between two thresholds", value: 85, result: &list[1]},
+		{title: "equal to a threshold", value: 90, result: &list[2]},
+		{title: "above every threshold", value: 1000, result: &list[2]},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.result, list.Lookup(test.value))
+		})
+	}
+	assert.Nil(t, ThresholdList{}.Lookup(10))
+}
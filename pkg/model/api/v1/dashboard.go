@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/v1/common"
@@ -135,10 +136,83 @@ func (d *DashboardSpec) validate() error {
 	return nil
 }
 
+func NewDashboardMetadata(project string, name string) *DashboardMetadata {
+	return &DashboardMetadata{
+		ProjectMetadata: *NewProjectMetadata(project, name),
+	}
+}
+
+// DashboardMetadata is the metadata of a dashboard. On top of the project metadata, it holds the fields only the
+// dashboards have.
+type DashboardMetadata struct {
+	ProjectMetadata `json:",inline" yaml:",inline"`
+	// ResourceVersion identifies the state of the dashboard. It changes every time the dashboard is modified and must
+	// be provided in an update, so concurrent modifications don't overwrite each other.
+	// +kubebuilder:validation:Optional
+	ResourceVersion string `json:"resourceVersion,omitempty" yaml:"resourceVersion,omitempty"`
+	// DeletedAt is set when the dashboard has been moved to the trash, from where it can still be restored.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	// +kubebuilder:validation:Optional
+	DeletedAt *time.Time `json:"deletedAt,omitempty" yaml:"deletedAt,omitempty"`
+}
+
+// dashboardMetadataFields holds the fields of DashboardMetadata that ProjectMetadata doesn't have.
+// They are unmarshalled apart, as the custom unmarshal of ProjectMetadata would ignore them.
+type dashboardMetadataFields struct {
+	ResourceVersion string     `json:"resourceVersion,omitempty" yaml:"resourceVersion,omitempty"`
+	DeletedAt       *time.Time `json:"deletedAt,omitempty" yaml:"deletedAt,omitempty"`
+}
+
+func (dm *DashboardMetadata) UnmarshalJSON(data []byte) error {
+	var projectMetadataTmp ProjectMetadata
+	if err := projectMetadataTmp.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var fieldsTmp dashboardMetadataFields
+	if err := json.Unmarshal(data, &fieldsTmp); err != nil {
+		return err
+	}
+	dm.ProjectMetadata = projectMetadataTmp
+	dm.ResourceVersion = fieldsTmp.ResourceVersion
+	dm.DeletedAt = fieldsTmp.DeletedAt
+	return nil
+}
+
+func (dm *DashboardMetadata) UnmarshalYAML(unmarshal func(any) error) error {
+	var projectMetadataTmp ProjectMetadata
+	if err := projectMetadataTmp.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+	var fieldsTmp dashboardMetadataFields
+	if err := unmarshal(&fieldsTmp); err != nil {
+		return err
+	}
+	dm.ProjectMetadata = projectMetadataTmp
+	dm.ResourceVersion = fieldsTmp.ResourceVersion
+	dm.DeletedAt = fieldsTmp.DeletedAt
+	return nil
+}
+
+func (dm *DashboardMetadata) CreateNow() {
+	dm.ProjectMetadata.CreateNow()
+	dm.DeletedAt = nil
+}
+
+func (dm *DashboardMetadata) Update(previous DashboardMetadata) {
+	dm.ProjectMetadata.Update(previous.ProjectMetadata)
+	// a dashboard stays in the trash until it is restored
+	dm.DeletedAt = previous.DeletedAt
+}
+
 type Dashboard struct {
 	Kind     Kind               `json:"kind" yaml:"kind"`
-	Metadata ProjectMetadata    `json:"metadata" yaml:"metadata"`
+	Metadata DashboardMetadata  `json:"metadata" yaml:"metadata"`
 	Spec     dashboardSpec.Spec `json:"spec" yaml:"spec"`
+	// Favorited is true when the dashboard is a favorite of the user listing the dashboards.
+	// It is only set in the lists returned by the API and is never stored.
+	Favorited bool `json:"favorited,omitempty" yaml:"favorited,omitempty"`
 }
 
 func (d *Dashboard) GetMetadata() modelAPI.Metadata {
@@ -0,0 +1,84 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// QueryHint tells how the data of a query is expected to be retrieved, as some datasources use a different API for each case.
+type QueryHint string
+
+const (
+	// QueryHintInstant retrieves a single value per series, at the end of the time range.
+	QueryHintInstant QueryHint = "instant"
+	// QueryHintRange retrieves the values over the whole time range. It is the default hint.
+	QueryHintRange QueryHint = "range"
+	// QueryHintStream retrieves the values continuously, as they are produced.
+	QueryHintStream QueryHint = "stream"
+)
+
+// ParseQueryHint converts a string into a QueryHint. An empty string gives the default hint QueryHintRange.
+func ParseQueryHint(s string) (QueryHint, error) {
+	h := QueryHint(s)
+	if err := (&h).validate(); err != nil {
+		return "", err
+	}
+	return h, nil
+}
+
+func (h *QueryHint) UnmarshalJSON(data []byte) error {
+	var tmp QueryHint
+	type plain QueryHint
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*h = tmp
+	return nil
+}
+
+func (h *QueryHint) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp QueryHint
+	type plain QueryHint
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*h = tmp
+	return nil
+}
+
+// OrDefault returns the hint, or QueryHintRange when it is not set, so the queries written before the hints existed
+// keep being range queries.
+func (h QueryHint) OrDefault() QueryHint {
+	if len(h) == 0 {
+		return QueryHintRange
+	}
+	return h
+}
+
+func (h *QueryHint) validate() error {
+	*h = h.OrDefault()
+	switch *h {
+	case QueryHintInstant, QueryHintRange, QueryHintStream:
+		return nil
+	}
+	return fmt.Errorf("unknown query hint %q, it must be %q, %q or %q", string(*h), QueryHintInstant, QueryHintRange, QueryHintStream)
+}
@@ -0,0 +1,108 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type testQueryHintStruct struct {
+	Hint QueryHint `json:"hint" yaml:"hint"`
+}
+
+func TestUnmarshalQueryHint(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		jason   string
+		yamele  string
+		result  QueryHint
+		isError bool
+	}{
+		{
+			title:  "instant",
+			jason:  `{"hint": "instant"}`,
+			yamele: "hint: instant",
+			result: QueryHintInstant,
+		},
+		{
+			title:  "range",
+			jason:  `{"hint": "range"}`,
+			yamele: "hint: range",
+			result: QueryHintRange,
+		},
+		{
+			title:  "stream",
+			jason:  `{"hint": "stream"}`,
+			yamele: "hint: stream",
+			result: QueryHintStream,
+		},
+		{
+			title:  "empty hint is a range query",
+			jason:  `{"hint": ""}`,
+			yamele: `hint: ""`,
+			result: QueryHintRange,
+		},
+		{
+			title:   "unknown hint",
+			jason:   `{"hint": "batch"}`,
+			yamele:  "hint: batch",
+			isError: true,
+		},
+		{
+			title:   "hint with a different case",
+			jason:   `{"hint": "Instant"}`,
+			yamele:  "hint: Instant",
+			isError: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := testQueryHintStruct{}
+			jsonErr := json.Unmarshal([]byte(test.jason), &jsonResult)
+			yamlResult := testQueryHintStruct{}
+			yamlErr := yaml.Unmarshal([]byte(test.yamele), &yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult.Hint)
+			assert.Equal(t, test.result, yamlResult.Hint)
+		})
+	}
+}
+
+func TestParseQueryHint(t *testing.T) {
+	for _, hint := range []QueryHint{QueryHintInstant, QueryHintRange, QueryHintStream} {
+		result, err := ParseQueryHint(string(hint))
+		assert.NoError(t, err)
+		assert.Equal(t, hint, result)
+	}
+	result, err := ParseQueryHint("")
+	assert.NoError(t, err)
+	assert.Equal(t, QueryHintRange, result)
+	_, err = ParseQueryHint("batch")
+	assert.Error(t, err)
+}
+
+func TestQueryHintOrDefault(t *testing.T) {
+	assert.Equal(t, QueryHintRange, QueryHint("").OrDefault())
+	assert.Equal(t, QueryHintInstant, QueryHintInstant.OrDefault())
+}
@@ -0,0 +1,57 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/perses/spec/go/common"
+)
+
+// RefreshInterval is the refresh interval of a dashboard, checked against the minimum accepted by the server.
+// The minimum is only known by the server, that's why this check cannot happen when the dashboard is unmarshalled.
+type RefreshInterval struct {
+	Value common.DurationString
+	// Minimum is the lowest refresh interval accepted. A zero value means there is no minimum.
+	Minimum time.Duration
+}
+
+// NewRefreshInterval returns the RefreshInterval corresponding to the given value, or an error if it's below the minimum.
+func NewRefreshInterval(value common.DurationString, minimum time.Duration) (RefreshInterval, error) {
+	r := RefreshInterval{Value: value, Minimum: minimum}
+	return r, r.validate()
+}
+
+func (r RefreshInterval) String() string {
+	return string(r.Value)
+}
+
+func (r RefreshInterval) validate() error {
+	if len(r.Value) == 0 {
+		return nil
+	}
+	d, err := common.ParseDuration(string(r.Value))
+	if err != nil {
+		return fmt.Errorf("invalid refresh interval %q: %w", r.Value, err)
+	}
+	// A zero duration disables the automatic refresh, so there is nothing to protect the server from.
+	if d == 0 || r.Minimum <= 0 {
+		return nil
+	}
+	if time.Duration(d) < r.Minimum {
+		return fmt.Errorf("refresh interval %q is below the minimum %q accepted", r.Value, common.Duration(r.Minimum).String())
+	}
+	return nil
+}
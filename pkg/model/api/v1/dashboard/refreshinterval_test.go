@@ -0,0 +1,50 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perses/spec/go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshInterval(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		value   common.DurationString
+		minimum time.Duration
+		isError bool
+	}{
+		{title: "below the minimum", value: "1ms", minimum: 10 * time.Second, isError: true},
+		{title: "at the minimum", value: "10s", minimum: 10 * time.Second},
+		{title: "above the minimum", value: "1m", minimum: 10 * time.Second},
+		{title: "no minimum", value: "1ms"},
+		{title: "empty value", value: "", minimum: 10 * time.Second},
+		{title: "refresh disabled", value: "0s", minimum: 10 * time.Second},
+		{title: "invalid value", value: "often", minimum: 10 * time.Second, isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			r, err := NewRefreshInterval(test.value, test.minimum)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, string(test.value), r.String())
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/perses/spec/go/common"
+)
+
+const relativeTimeNow = "now"
+
+// TimeRange is the time range a dashboard is looked at.
+// Each bound is either an absolute Unix timestamp, or an expression relative to the current time like "now" or "now-1h".
+// Timestamps can be given as numbers or as strings.
+type TimeRange struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+func (t *TimeRange) UnmarshalJSON(data []byte) error {
+	var tmp struct {
+		From any `json:"from"`
+		To   any `json:"to"`
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	return t.set(tmp.From, tmp.To)
+}
+
+func (t *TimeRange) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp struct {
+		From any `yaml:"from"`
+		To   any `yaml:"to"`
+	}
+	if err := unmarshal(&tmp); err != nil {
+		return err
+	}
+	return t.set(tmp.From, tmp.To)
+}
+
+func (t *TimeRange) set(from any, to any) error {
+	var err error
+	result := TimeRange{}
+	if result.From, err = timeRangeBoundToString(from); err != nil {
+		return fmt.Errorf("invalid start of the time range: %w", err)
+	}
+	if result.To, err = timeRangeBoundToString(to); err != nil {
+		return fmt.Errorf("invalid end of the time range: %w", err)
+	}
+	if err = result.Validate(); err != nil {
+		return err
+	}
+	*t = result
+	return nil
+}
+
+// Validate checks both bounds are valid, and that the start is before the end when both are absolute.
+// A relative bound is not compared as its value depends on when the dashboard is looked at.
+func (t *TimeRange) Validate() error {
+	from, fromIsAbsolute, err := parseTimeRangeBound(t.From)
+	if err != nil {
+		return fmt.Errorf("invalid start of the time range: %w", err)
+	}
+	to, toIsAbsolute, err := parseTimeRangeBound(t.To)
+	if err != nil {
+		return fmt.Errorf("invalid end of the time range: %w", err)
+	}
+	if fromIsAbsolute && toIsAbsolute && from >= to {
+		return fmt.Errorf("the start of the time range (%d) must be before its end (%d)", from, to)
+	}
+	return nil
+}
+
+func timeRangeBoundToString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		// JSON numbers are always decoded as float64.
+		if v != float64(int64(v)) {
+			return "", fmt.Errorf("%v is not a Unix timestamp", v)
+		}
+		return strconv.FormatInt(int64(v), 10), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
+
+// parseTimeRangeBound returns the timestamp of an absolute bound. For a relative bound, it only checks the expression.
+func parseTimeRangeBound(bound string) (int64, bool, error) {
+	if len(bound) == 0 {
+		return 0, false, fmt.Errorf("it cannot be empty")
+	}
+	if timestamp, err := strconv.ParseInt(bound, 10, 64); err == nil {
+		if timestamp < 0 {
+			return 0, false, fmt.Errorf("the timestamp %d cannot be negative", timestamp)
+		}
+		return timestamp, true, nil
+	}
+	if bound == relativeTimeNow {
+		return 0, false, nil
+	}
+	offset, ok := strings.CutPrefix(bound, relativeTimeNow+"-")
+	if !ok {
+		return 0, false, fmt.Errorf("%q is neither a Unix timestamp nor an expression like \"now-1h\"", bound)
+	}
+	if _, err := common.ParseDuration(offset); err != nil {
+		return 0, false, fmt.Errorf("invalid duration in %q: %w", bound, err)
+	}
+	return 0, false, nil
+}
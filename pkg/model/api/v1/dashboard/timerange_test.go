@@ -0,0 +1,105 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTimeRange_Validate(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		from    string
+		to      string
+		isError bool
+	}{
+		{title: "absolute time range", from: "1000", to: "2000"},
+		{title: "reversed absolute time range", from: "2000", to: "1000", isError: true},
+		{title: "equal absolute time range", from: "1000", to: "1000", isError: true},
+		{title: "relative time range", from: "now-1h", to: "now"},
+		{title: "relative time range is not compared", from: "now", to: "now-1h"},
+		{title: "absolute start and relative end", from: "1000", to: "now"},
+		{title: "negative timestamp", from: "-1000", to: "now", isError: true},
+		{title: "empty end", from: "now-1h", to: "", isError: true},
+		{title: "unknown expression", from: "yesterday", to: "now", isError: true},
+		{title: "invalid duration", from: "now-1x", to: "now", isError: true},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			timeRange := TimeRange{From: test.from, To: test.to}
+			err := timeRange.Validate()
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTimeRange(t *testing.T) {
+	testSuite := []struct {
+		title   string
+		jason   string
+		yamele  string
+		result  TimeRange
+		isError bool
+	}{
+		{
+			title:  "timestamps as numbers",
+			jason:  `{"from": 1000, "to": 2000}`,
+			yamele: "from: 1000\nto: 2000",
+			result: TimeRange{From: "1000", To: "2000"},
+		},
+		{
+			title:  "relative time range",
+			jason:  `{"from": "now-6h", "to": "now"}`,
+			yamele: "from: now-6h\nto: now",
+			result: TimeRange{From: "now-6h", To: "now"},
+		},
+		{
+			title:   "reversed timestamps",
+			jason:   `{"from": 1000, "to": 500}`,
+			yamele:  "from: 1000\nto: 500",
+			isError: true,
+		},
+		{
+			title:   "decimal timestamp",
+			jason:   `{"from": 1000.5, "to": 2000}`,
+			yamele:  "from: 1000.5\nto: 2000",
+			isError: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			jsonResult := TimeRange{}
+			jsonErr := json.Unmarshal([]byte(test.jason), &jsonResult)
+			yamlResult := TimeRange{}
+			yamlErr := yaml.Unmarshal([]byte(test.yamele), &yamlResult)
+			if test.isError {
+				assert.Error(t, jsonErr)
+				assert.Error(t, yamlErr)
+				return
+			}
+			assert.NoError(t, jsonErr)
+			assert.NoError(t, yamlErr)
+			assert.Equal(t, test.result, jsonResult)
+			assert.Equal(t, test.result, yamlResult)
+		})
+	}
+}
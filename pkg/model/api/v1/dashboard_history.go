@@ -0,0 +1,95 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+// KindDashboardHistory is the kind of the snapshots recorded every time a dashboard is created or updated.
+// Like KindQueryAudit, these records are only written by the server and are not a resource of the API.
+const KindDashboardHistory Kind = "DashboardHistory"
+
+// DashboardHistoryEntry is a version of a dashboard, with who made it and when.
+type DashboardHistoryEntry struct {
+	// Version is the version of the dashboard (metadata.version) this entry records.
+	Version uint64 `json:"version" yaml:"version"`
+	// Actor is the user who made the change. It is empty when the authentication is disabled.
+	Actor string    `json:"actor,omitempty" yaml:"actor,omitempty"`
+	Time  time.Time `json:"time" yaml:"time"`
+	// Content is the dashboard as it was at this version. It is omitted when listing the history.
+	Content *Dashboard `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type DashboardHistorySpec struct {
+	// Dashboard is the name of the dashboard.
+	Dashboard             string `json:"dashboard" yaml:"dashboard"`
+	DashboardHistoryEntry `json:",inline" yaml:",inline"`
+}
+
+// DashboardHistory records a version of a dashboard.
+// The project in the metadata is the one of the dashboard, and the name is derived from the name of the dashboard and
+// the version, so the history of a dashboard can be listed with DashboardHistoryNamePrefix.
+type DashboardHistory struct {
+	// Kind is a plain string since KindDashboardHistory is not a valid Kind to unmarshal.
+	Kind     string               `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata      `json:"metadata" yaml:"metadata"`
+	Spec     DashboardHistorySpec `json:"spec" yaml:"spec"`
+}
+
+// DashboardHistoryNamePrefix returns the prefix shared by the names of the records of the given dashboard.
+// The name of the dashboard is hashed to keep the names of the records short enough to be valid.
+func DashboardHistoryNamePrefix(dashboard string) string {
+	sum := sha256.Sum256([]byte(dashboard))
+	return hex.EncodeToString(sum[:8]) + "-"
+}
+
+// DashboardHistoryName returns the name of the record of the given version of the dashboard.
+// The version is padded, so the records are sorted by version when sorted by name.
+func DashboardHistoryName(dashboard string, version uint64) string {
+	return fmt.Sprintf("%s%020d", DashboardHistoryNamePrefix(dashboard), version)
+}
+
+func NewDashboardHistory(dashboard *Dashboard, actor string) *DashboardHistory {
+	return &DashboardHistory{
+		Kind:     string(KindDashboardHistory),
+		Metadata: *NewProjectMetadata(dashboard.Metadata.Project, DashboardHistoryName(dashboard.Metadata.Name, dashboard.Metadata.Version)),
+		Spec: DashboardHistorySpec{
+			Dashboard: dashboard.Metadata.Name,
+			DashboardHistoryEntry: DashboardHistoryEntry{
+				Version: dashboard.Metadata.Version,
+				Actor:   actor,
+				Time:    dashboard.Metadata.UpdatedAt,
+				Content: dashboard,
+			},
+		},
+	}
+}
+
+func (d *DashboardHistory) GetMetadata() modelAPI.Metadata {
+	return &d.Metadata
+}
+
+func (d *DashboardHistory) GetKind() string {
+	return d.Kind
+}
+
+func (d *DashboardHistory) GetSpec() any {
+	return d.Spec
+}
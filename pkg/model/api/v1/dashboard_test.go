@@ -40,12 +40,14 @@ func TestMarshalDashboard(t *testing.T) {
 			title: "simple dashboard",
 			dashboard: &Dashboard{
 				Kind: KindDashboard,
-				Metadata: ProjectMetadata{
-					Metadata: Metadata{
-						Name: "SimpleDashboard",
-					},
-					ProjectMetadataWrapper: ProjectMetadataWrapper{
-						Project: "perses",
+				Metadata: DashboardMetadata{
+					ProjectMetadata: ProjectMetadata{
+						Metadata: Metadata{
+							Name: "SimpleDashboard",
+						},
+						ProjectMetadataWrapper: ProjectMetadataWrapper{
+							Project: "perses",
+						},
 					},
 				},
 				Spec: dashboard.Spec{
@@ -146,12 +148,14 @@ func TestMarshalDashboard(t *testing.T) {
 			title: "simple dashboard with variable",
 			dashboard: &Dashboard{
 				Kind: KindDashboard,
-				Metadata: ProjectMetadata{
-					Metadata: Metadata{
-						Name: "SimpleDashboard",
-					},
-					ProjectMetadataWrapper: ProjectMetadataWrapper{
-						Project: "perses",
+				Metadata: DashboardMetadata{
+					ProjectMetadata: ProjectMetadata{
+						Metadata: Metadata{
+							Name: "SimpleDashboard",
+						},
+						ProjectMetadataWrapper: ProjectMetadataWrapper{
+							Project: "perses",
+						},
 					},
 				},
 				Spec: dashboard.Spec{
@@ -428,12 +432,14 @@ func TestUnmarshallDashboard(t *testing.T) {
 	}
 	expected := &Dashboard{
 		Kind: KindDashboard,
-		Metadata: ProjectMetadata{
-			Metadata: Metadata{
-				Name: "SimpleDashboard",
-			},
-			ProjectMetadataWrapper: ProjectMetadataWrapper{
-				Project: "perses",
+		Metadata: DashboardMetadata{
+			ProjectMetadata: ProjectMetadata{
+				Metadata: Metadata{
+					Name: "SimpleDashboard",
+				},
+				ProjectMetadataWrapper: ProjectMetadataWrapper{
+					Project: "perses",
+				},
 			},
 		},
 		Spec: dashboard.Spec{
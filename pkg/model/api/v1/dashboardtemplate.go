@@ -0,0 +1,353 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+	dashboardSpec "github.com/perses/spec/go/dashboard"
+	variableSpec "github.com/perses/spec/go/dashboard/variable"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// TemplateParameterType is the type of the value a TemplateParameter accepts.
+type TemplateParameterType string
+
+const (
+	TemplateParameterTypeString  TemplateParameterType = "string"
+	TemplateParameterTypeNumber  TemplateParameterType = "number"
+	TemplateParameterTypeBoolean TemplateParameterType = "boolean"
+	// TemplateParameterTypeArray accepts a list of strings. It can only be used for a list variable.
+	TemplateParameterTypeArray TemplateParameterType = "array"
+)
+
+func (t *TemplateParameterType) UnmarshalJSON(data []byte) error {
+	var tmp TemplateParameterType
+	type plain TemplateParameterType
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplateParameterType) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp TemplateParameterType
+	type plain TemplateParameterType
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplateParameterType) validate() error {
+	switch *t {
+	case TemplateParameterTypeString, TemplateParameterTypeNumber, TemplateParameterTypeBoolean, TemplateParameterTypeArray:
+		return nil
+	default:
+		return fmt.Errorf("unknown parameter type %q, expected %q, %q, %q or %q", *t,
+			TemplateParameterTypeString, TemplateParameterTypeNumber, TemplateParameterTypeBoolean, TemplateParameterTypeArray)
+	}
+}
+
+// TemplateParameter is an input of a DashboardTemplate.
+// Its value replaces the default value of the dashboard variable having the same name.
+type TemplateParameter struct {
+	Name        string                `json:"name" yaml:"name"`
+	Type        TemplateParameterType `json:"type" yaml:"type"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	// Default is the value used when no value is given for the parameter. A parameter without default is required.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Optional
+	Default any `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// schema returns the JSON schema of the value of the parameter.
+func (p *TemplateParameter) schema() map[string]any {
+	result := map[string]any{"type": string(p.Type)}
+	if p.Type == TemplateParameterTypeArray {
+		result["items"] = map[string]any{"type": "string"}
+	}
+	if len(p.Description) > 0 {
+		result["description"] = p.Description
+	}
+	return result
+}
+
+type DashboardTemplateSpecBase struct {
+	Parameters []TemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+func (dtsb *DashboardTemplateSpecBase) UnmarshalJSON(data []byte) error {
+	var tmp DashboardTemplateSpecBase
+	type plain DashboardTemplateSpecBase
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	*dtsb = tmp
+	return nil
+}
+
+func (dtsb *DashboardTemplateSpecBase) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp DashboardTemplateSpecBase
+	type plain DashboardTemplateSpecBase
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	*dtsb = tmp
+	return nil
+}
+
+// ParametersSchema returns the JSON schema the values of the parameters must comply with.
+func (dtsb *DashboardTemplateSpecBase) ParametersSchema() map[string]any {
+	properties := make(map[string]any, len(dtsb.Parameters))
+	required := make([]string, 0, len(dtsb.Parameters))
+	for i := range dtsb.Parameters {
+		parameter := &dtsb.Parameters[i]
+		properties[parameter.Name] = parameter.schema()
+		if parameter.Default == nil {
+			required = append(required, parameter.Name)
+		}
+	}
+	return map[string]any{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// DashboardTemplateSpec is the spec of a dashboard with the parameters needed to instantiate it.
+type DashboardTemplateSpec struct {
+	DashboardTemplateSpecBase `json:",inline" yaml:",inline"`
+	dashboardSpec.Spec        `json:",inline" yaml:",inline"`
+}
+
+// NB custom unmarshalling is required, otherwise by default the parameters
+// wont get unmarshalled because of the embedded struct taking precedence
+
+func (dts *DashboardTemplateSpec) UnmarshalJSON(data []byte) error {
+	var dashboardTemplateSpecBaseTmp DashboardTemplateSpecBase
+	if err := dashboardTemplateSpecBaseTmp.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	var dashboardSpecTmp dashboardSpec.Spec
+	if err := dashboardSpecTmp.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	dts.DashboardTemplateSpecBase = dashboardTemplateSpecBaseTmp
+	dts.Spec = dashboardSpecTmp
+	return nil
+}
+
+func (dts *DashboardTemplateSpec) UnmarshalYAML(unmarshal func(any) error) error {
+	var dashboardTemplateSpecBaseTmp DashboardTemplateSpecBase
+	if err := dashboardTemplateSpecBaseTmp.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+
+	var dashboardSpecTmp dashboardSpec.Spec
+	if err := dashboardSpecTmp.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+
+	dts.DashboardTemplateSpecBase = dashboardTemplateSpecBaseTmp
+	dts.Spec = dashboardSpecTmp
+	return nil
+}
+
+// ValidateParameterValues checks the values against the schema of the parameters.
+// It returns the values completed with the default value of the parameters not provided.
+func (dts *DashboardTemplateSpec) ValidateParameterValues(values map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(dts.Parameters))
+	for name, value := range values {
+		result[name] = value
+	}
+	if err := validateAgainstSchema(dts.ParametersSchema(), result); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	for _, parameter := range dts.Parameters {
+		if _, ok := result[parameter.Name]; !ok {
+			result[parameter.Name] = parameter.Default
+		}
+	}
+	return result, nil
+}
+
+// ApplyParameterValues replaces the default value of the variables by the value of the parameter having the same name.
+// The values must have been validated with ValidateParameterValues. The spec is modified in place.
+func (dts *DashboardTemplateSpec) ApplyParameterValues(values map[string]any) {
+	for _, v := range dts.Variables {
+		value, ok := values[v.Spec.GetName()]
+		if !ok {
+			continue
+		}
+		switch spec := v.Spec.(type) {
+		case *dashboardSpec.ListVariableSpec:
+			if list, isList := value.([]any); isList {
+				spec.DefaultValue = &variableSpec.DefaultValue{SliceValues: stringifyParameterValues(list)}
+			} else {
+				spec.DefaultValue = &variableSpec.DefaultValue{SingleValue: stringifyParameterValue(value)}
+			}
+		case *dashboardSpec.TextVariableSpec:
+			spec.Value = stringifyParameterValue(value)
+		}
+	}
+}
+
+func (dts *DashboardTemplateSpec) validate() error {
+	variables := make(map[string]variableSpec.Kind, len(dts.Variables))
+	for _, v := range dts.Variables {
+		variables[v.Spec.GetName()] = v.Kind
+	}
+	names := make(map[string]bool, len(dts.Parameters))
+	for i := range dts.Parameters {
+		parameter := &dts.Parameters[i]
+		if len(parameter.Name) == 0 {
+			return fmt.Errorf("parameter name cannot be empty")
+		}
+		if names[parameter.Name] {
+			return fmt.Errorf("parameter %q is defined more than once", parameter.Name)
+		}
+		names[parameter.Name] = true
+		if err := parameter.Type.validate(); err != nil {
+			return fmt.Errorf("parameter %q: %w", parameter.Name, err)
+		}
+		kind, ok := variables[parameter.Name]
+		if !ok {
+			return fmt.Errorf("parameter %q doesn't match any variable of the dashboard", parameter.Name)
+		}
+		if parameter.Type == TemplateParameterTypeArray && kind != variableSpec.KindList {
+			return fmt.Errorf("parameter %q of type %q can only be used for a list variable", parameter.Name, TemplateParameterTypeArray)
+		}
+		if parameter.Default != nil {
+			if err := validateAgainstSchema(parameter.schema(), parameter.Default); err != nil {
+				return fmt.Errorf("invalid default value of the parameter %q: %w", parameter.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DashboardTemplate is a reusable dashboard. A new dashboard is created from it by giving a value to its parameters.
+type DashboardTemplate struct {
+	Kind     Kind                  `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata       `json:"metadata" yaml:"metadata"`
+	Spec     DashboardTemplateSpec `json:"spec" yaml:"spec"`
+}
+
+func (d *DashboardTemplate) GetMetadata() modelAPI.Metadata {
+	return &d.Metadata
+}
+
+func (d *DashboardTemplate) GetKind() string {
+	return string(d.Kind)
+}
+
+func (d *DashboardTemplate) GetSpec() any {
+	return d.Spec
+}
+
+func (d *DashboardTemplate) UnmarshalJSON(data []byte) error {
+	var tmp DashboardTemplate
+	type plain DashboardTemplate
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*d = tmp
+	return nil
+}
+
+func (d *DashboardTemplate) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp DashboardTemplate
+	type plain DashboardTemplate
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*d = tmp
+	return nil
+}
+
+func (d *DashboardTemplate) validate() error {
+	if d.Kind != KindDashboardTemplate {
+		return fmt.Errorf("invalid kind: %q for a DashboardTemplate type", d.Kind)
+	}
+	if reflect.DeepEqual(d.Spec, DashboardTemplateSpec{}) {
+		return fmt.Errorf("spec cannot be empty")
+	}
+	if err := d.Spec.validate(); err != nil {
+		return err
+	}
+	return verifyAndSetJSONReferences(d.Spec.Layouts, d.Spec.Panels)
+}
+
+// validateAgainstSchema returns an error listing the reasons why the value doesn't comply with the JSON schema.
+func validateAgainstSchema(schema map[string]any, value any) error {
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+	reasons := make([]string, 0, len(result.Errors()))
+	for _, resultErr := range result.Errors() {
+		reasons = append(reasons, resultErr.String())
+	}
+	return errors.New(strings.Join(reasons, "; "))
+}
+
+func stringifyParameterValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func stringifyParameterValues(values []any) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, stringifyParameterValue(value))
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/dashboard/variable"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDashboardTemplateJSON(parameters string) string {
+	return fmt.Sprintf(`{
+  "kind": "DashboardTemplate",
+  "metadata": {
+    "name": "service",
+    "project": "perses"
+  },
+  "spec": {
+    "parameters": %s,
+    "variables": [
+      {
+        "kind": "ListVariable",
+        "spec": {
+          "name": "env",
+          "allowMultiple": true,
+          "plugin": {
+            "kind": "StaticListVariable",
+            "spec": {
+              "values": ["dev", "prod"]
+            }
+          }
+        }
+      },
+      {
+        "kind": "TextVariable",
+        "spec": {
+          "name": "team",
+          "value": "sre"
+        }
+      }
+    ],
+    "panels": {},
+    "layouts": [],
+    "duration": "1h"
+  }
+}`, parameters)
+}
+
+func TestUnmarshalDashboardTemplate(t *testing.T) {
+	result := &DashboardTemplate{}
+	err := json.Unmarshal([]byte(newTestDashboardTemplateJSON(`[
+      {"name": "env", "type": "array", "description": "The environments to display"},
+      {"name": "team", "type": "string", "default": "payments"}
+    ]`)), result)
+	assert.NoError(t, err)
+	assert.Equal(t, []TemplateParameter{
+		{Name: "env", Type: TemplateParameterTypeArray, Description: "The environments to display"},
+		{Name: "team", Type: TemplateParameterTypeString, Default: "payments"},
+	}, result.Spec.Parameters)
+	assert.Len(t, result.Spec.Variables, 2)
+	assert.Equal(t, "1h", string(result.Spec.Duration))
+}
+
+func TestUnmarshalDashboardTemplateError(t *testing.T) {
+	testSuite := []struct {
+		title      string
+		parameters string
+		err        string
+	}{
+		{
+			title:      "unknown type",
+			parameters: `[{"name": "env", "type": "date"}]`,
+			err:        `unknown parameter type "date", expected "string", "number", "boolean" or "array"`,
+		},
+		{
+			title:      "empty name",
+			parameters: `[{"type": "string"}]`,
+			err:        "parameter name cannot be empty",
+		},
+		{
+			title:      "duplicated parameter",
+			parameters: `[{"name": "team", "type": "string"}, {"name": "team", "type": "number"}]`,
+			err:        `parameter "team" is defined more than once`,
+		},
+		{
+			title:      "no matching variable",
+			parameters: `[{"name": "cluster", "type": "string"}]`,
+			err:        `parameter "cluster" doesn't match any variable of the dashboard`,
+		},
+		{
+			title:      "array for a text variable",
+			parameters: `[{"name": "team", "type": "array"}]`,
+			err:        `parameter "team" of type "array" can only be used for a list variable`,
+		},
+		{
+			title:      "invalid default",
+			parameters: `[{"name": "team", "type": "string", "default": 3}]`,
+			err:        `invalid default value of the parameter "team": (root): Invalid type. Expected: string, given: integer`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result := &DashboardTemplate{}
+			err := json.Unmarshal([]byte(newTestDashboardTemplateJSON(test.parameters)), result)
+			if assert.Error(t, err) {
+				assert.Equal(t, test.err, err.Error())
+			}
+		})
+	}
+}
+
+func TestDashboardTemplateParameterValues(t *testing.T) {
+	spec := &DashboardTemplateSpec{
+		DashboardTemplateSpecBase: DashboardTemplateSpecBase{
+			Parameters: []TemplateParameter{
+				{Name: "env", Type: TemplateParameterTypeArray},
+				{Name: "team", Type: TemplateParameterTypeString, Default: "payments"},
+				{Name: "replicas", Type: TemplateParameterTypeNumber, Default: float64(3)},
+			},
+		},
+	}
+	testSuite := []struct {
+		title  string
+		values map[string]any
+		result map[string]any
+		err    string
+	}{
+		{
+			title:  "defaults applied",
+			values: map[string]any{"env": []any{"prod"}},
+			result: map[string]any{"env": []any{"prod"}, "team": "payments", "replicas": float64(3)},
+		},
+		{
+			title:  "every value given",
+			values: map[string]any{"env": []any{"dev", "prod"}, "team": "sre", "replicas": float64(1)},
+			result: map[string]any{"env": []any{"dev", "prod"}, "team": "sre", "replicas": float64(1)},
+		},
+		{
+			title:  "missing required parameter",
+			values: nil,
+			err:    "invalid parameters: (root): env is required",
+		},
+		{
+			title:  "wrong type",
+			values: map[string]any{"env": "prod"},
+			err:    "invalid parameters: env: Invalid type. Expected: array, given: string",
+		},
+		{
+			title:  "unknown parameter",
+			values: map[string]any{"env": []any{"prod"}, "cluster": "eu"},
+			err:    "invalid parameters: (root): Additional property cluster is not allowed",
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := spec.ValidateParameterValues(test.values)
+			if len(test.err) > 0 {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.err, err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestApplyParameterValues(t *testing.T) {
+	env := &dashboard.ListVariableSpec{Name: "env"}
+	team := &dashboard.TextVariableSpec{Name: "team", TextSpec: variable.TextSpec{Value: "sre"}}
+	replicas := &dashboard.ListVariableSpec{Name: "replicas"}
+	spec := &DashboardTemplateSpec{}
+	spec.Variables = []dashboard.Variable{
+		{Kind: variable.KindList, Spec: env},
+		{Kind: variable.KindText, Spec: team},
+		{Kind: variable.KindList, Spec: replicas},
+	}
+	spec.ApplyParameterValues(map[string]any{"env": []any{"dev", "prod"}, "team": "payments", "replicas": float64(3)})
+	assert.Equal(t, &variable.DefaultValue{SliceValues: []string{"dev", "prod"}}, env.DefaultValue)
+	assert.Equal(t, "payments", team.Value)
+	assert.Equal(t, &variable.DefaultValue{SingleValue: "3"}, replicas.DefaultValue)
+}
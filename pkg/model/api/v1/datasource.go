@@ -35,6 +35,24 @@ func FilterDatasource[T DatasourceInterface](kind string, defaultDTS *bool, list
 	return result
 }
 
+// FilterDatasourceByLabels keeps the datasources whose labels match the selector.
+func FilterDatasourceByLabels[T DatasourceInterface](selector LabelSelector, list []T) []T {
+	if len(selector) == 0 {
+		return list
+	}
+	result := make([]T, 0, len(list))
+	for _, d := range list {
+		var labels map[string]string
+		if metadata, ok := d.GetMetadata().(interface{ GetLabels() map[string]string }); ok {
+			labels = metadata.GetLabels()
+		}
+		if selector.Matches(labels) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
 type DatasourceInterface interface {
 	GetMetadata() modelAPI.Metadata
 	GetDatasourceSpec() datasource.Spec
@@ -14,7 +14,9 @@
 package datasource
 
 import (
+	"encoding/json"
 	"net/url"
+	"strconv"
 
 	"github.com/perses/spec/go/common"
 	"github.com/perses/spec/go/datasource/proxy/http"
@@ -29,6 +31,68 @@ type Prometheus struct {
 	ScrapeInterval *common.Duration `json:"scrapeInterval,omitempty" yaml:"scrapeInterval,omitempty"`
 }
 
+const (
+	PrometheusDatasourceKind = "PrometheusDatasource"
+	ThanosDatasourceKind     = "ThanosDatasource"
+	CortexDatasourceKind     = "CortexDatasource"
+)
+
+// IsPrometheusCompatible returns true when the datasource plugin speaks the Prometheus HTTP API.
+func IsPrometheusCompatible(pluginKind string) bool {
+	return pluginKind == PrometheusDatasourceKind || pluginKind == ThanosDatasourceKind || pluginKind == CortexDatasourceKind
+}
+
+// PrometheusQueryExtensions are the query parameters that Thanos and Cortex accept on top of the Prometheus HTTP API.
+// When set in the datasource, the proxy adds them to the requests that don't already define them.
+type PrometheusQueryExtensions struct {
+	// Dedup activates the deduplication of the series coming from replicated sources.
+	Dedup *bool `json:"dedup,omitempty" yaml:"dedup,omitempty"`
+	// PartialResponse allows a result to be returned even when some of the queried stores are unavailable.
+	PartialResponse *bool `json:"partialResponse,omitempty" yaml:"partialResponse,omitempty"`
+}
+
+// QueryParameters returns the extensions as URL query parameters, as expected by the query API.
+func (p PrometheusQueryExtensions) QueryParameters() url.Values {
+	values := url.Values{}
+	if p.Dedup != nil {
+		values.Set("dedup", strconv.FormatBool(*p.Dedup))
+	}
+	if p.PartialResponse != nil {
+		values.Set("partial_response", strconv.FormatBool(*p.PartialResponse))
+	}
+	return values
+}
+
+// ThanosDataSource is a Prometheus datasource pointing to a Thanos Query API.
+type ThanosDataSource struct {
+	Prometheus                `json:",inline" yaml:",inline"`
+	PrometheusQueryExtensions `json:",inline" yaml:",inline"`
+}
+
+// CortexDataSource is a Prometheus datasource pointing to a Cortex query frontend.
+type CortexDataSource struct {
+	Prometheus                `json:",inline" yaml:",inline"`
+	PrometheusQueryExtensions `json:",inline" yaml:",inline"`
+}
+
+// QueryExtensions returns the query parameters to add to the requests sent to a datasource.
+// Only the Thanos and Cortex datasources define some, it returns nil for any other kind of datasource.
+func QueryExtensions(pluginKind string, pluginSpec any) (url.Values, error) {
+	if pluginKind != ThanosDatasourceKind && pluginKind != CortexDatasourceKind {
+		return nil, nil
+	}
+	data, err := json.Marshal(pluginSpec)
+	if err != nil {
+		return nil, err
+	}
+	// Only the extensions are decoded, the rest of the spec is validated by the plugin schema.
+	var extensions PrometheusQueryExtensions
+	if unmarshalErr := json.Unmarshal(data, &extensions); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return extensions.QueryParameters(), nil
+}
+
 // Postgres is only used for testing purpose.
 // It doesn't reflect the nature of an actual Postgres datasource
 type Postgres struct {
@@ -43,6 +43,8 @@ type configFinder struct {
 	foundKind string
 
 	config any
+	// data is the raw spec of the proxy found.
+	data []byte
 }
 
 func (c *configFinder) find(v reflect.Value) {
@@ -115,6 +117,7 @@ func (c *configFinder) unmarshalConfig(spec reflect.Value) {
 	if c.err != nil {
 		return
 	}
+	c.data = data
 
 	switch c.foundKind {
 	case http.ProxyKindName:
@@ -0,0 +1,135 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/perses/spec/go/datasource/proxy/http"
+)
+
+var (
+	urlTemplateVariableRegexp = regexp.MustCompile(`\{([^{}]*)\}`)
+	urlTemplateNameRegexp     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// urlTemplateValueRegexp restricts the values to what can be part of a hostname or a path segment,
+	// so a value cannot change the structure of the URL (e.g. redirect the request to another host).
+	urlTemplateValueRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+)
+
+// URLTemplate is a URL containing variables between braces, e.g. `https://prometheus-{cluster}.internal:9090`.
+// It is resolved by the proxy with the values of the variables sent along with the request.
+type URLTemplate string
+
+func (u *URLTemplate) UnmarshalJSON(data []byte) error {
+	var tmp URLTemplate
+	type plain URLTemplate
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*u = tmp
+	return nil
+}
+
+func (u *URLTemplate) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp URLTemplate
+	type plain URLTemplate
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*u = tmp
+	return nil
+}
+
+func (u *URLTemplate) validate() error {
+	if len(*u) == 0 {
+		return nil
+	}
+	for _, name := range u.Variables() {
+		if !urlTemplateNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid variable %q in the URL template %q, it must match the regexp %s", name, *u, urlTemplateNameRegexp.String())
+		}
+	}
+	// Once the variables are replaced, the template must be a valid URL.
+	sample := urlTemplateVariableRegexp.ReplaceAllString(string(*u), "0")
+	if strings.ContainsAny(sample, "{}") {
+		return fmt.Errorf("unbalanced braces in the URL template %q", *u)
+	}
+	parsed, err := url.Parse(sample)
+	if err != nil {
+		return fmt.Errorf("invalid URL template %q: %w", *u, err)
+	}
+	if len(parsed.Scheme) == 0 || len(parsed.Host) == 0 {
+		return fmt.Errorf("invalid URL template %q: the scheme and the host are required", *u)
+	}
+	return nil
+}
+
+// Variables returns the name of the variables used in the template, in their order of appearance.
+func (u URLTemplate) Variables() []string {
+	var result []string
+	for _, match := range urlTemplateVariableRegexp.FindAllStringSubmatch(string(u), -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
+
+// Resolve replaces the variables of the template with the given values.
+// It returns an error when the value of a variable is missing or is not valid.
+func (u URLTemplate) Resolve(values map[string]string) (*url.URL, error) {
+	for _, name := range u.Variables() {
+		value, ok := values[name]
+		if !ok || len(value) == 0 {
+			return nil, fmt.Errorf("missing value for the variable %q of the URL template", name)
+		}
+		if !urlTemplateValueRegexp.MatchString(value) {
+			return nil, fmt.Errorf("invalid value %q for the variable %q of the URL template", value, name)
+		}
+	}
+	resolved := urlTemplateVariableRegexp.ReplaceAllStringFunc(string(u), func(variable string) string {
+		return values[variable[1:len(variable)-1]]
+	})
+	return url.Parse(resolved)
+}
+
+// httpProxyExtensions are the fields that can be set in the spec of an HTTP proxy on top of the ones it already supports.
+type httpProxyExtensions struct {
+	// URLTemplate overrides the URL of the proxy. It is used when a datasource has one instance per cluster.
+	URLTemplate URLTemplate `json:"urlTemplate,omitempty" yaml:"urlTemplate,omitempty"`
+}
+
+// ExtractURLTemplate returns the URL template of the HTTP proxy defined in the plugin spec.
+// It returns an empty template when there is no HTTP proxy or when it doesn't define a template.
+func ExtractURLTemplate(pluginSpec any) (URLTemplate, error) {
+	finder := &configFinder{}
+	finder.find(reflect.ValueOf(pluginSpec))
+	if finder.err != nil || finder.foundKind != http.ProxyKindName || len(finder.data) == 0 {
+		return "", finder.err
+	}
+	var extensions httpProxyExtensions
+	if err := json.Unmarshal(finder.data, &extensions); err != nil {
+		return "", err
+	}
+	return extensions.URLTemplate, nil
+}
@@ -0,0 +1,173 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalURLTemplate(t *testing.T) {
+	testSuite := []struct {
+		title    string
+		template string
+		err      string
+	}{
+		{
+			title:    "single variable",
+			template: "https://prometheus-{cluster}.internal:9090",
+		},
+		{
+			title:    "several variables",
+			template: "https://{cluster}.{region}.internal/prometheus/{tenant_id}",
+		},
+		{
+			title:    "invalid variable name",
+			template: "https://prometheus-{cluster-name}.internal:9090",
+			err:      `invalid variable "cluster-name" in the URL template "https://prometheus-{cluster-name}.internal:9090", it must match the regexp ^[a-zA-Z_][a-zA-Z0-9_]*$`,
+		},
+		{
+			title:    "empty variable name",
+			template: "https://prometheus-{}.internal:9090",
+			err:      `invalid variable "" in the URL template "https://prometheus-{}.internal:9090", it must match the regexp ^[a-zA-Z_][a-zA-Z0-9_]*$`,
+		},
+		{
+			title:    "unbalanced braces",
+			template: "https://prometheus-{cluster.internal:9090",
+			err:      `unbalanced braces in the URL template "https://prometheus-{cluster.internal:9090"`,
+		},
+		{
+			title:    "missing host",
+			template: "/prometheus/{cluster}",
+			err:      `invalid URL template "/prometheus/{cluster}": the scheme and the host are required`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			var result URLTemplate
+			data, _ := json.Marshal(test.template)
+			err := json.Unmarshal(data, &result)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, URLTemplate(test.template), result)
+		})
+	}
+}
+
+func TestURLTemplateResolve(t *testing.T) {
+	template := URLTemplate("https://prometheus-{cluster}.{region}.internal:9090")
+	testSuite := []struct {
+		title  string
+		values map[string]string
+		result string
+		err    string
+	}{
+		{
+			title:  "all variables set",
+			values: map[string]string{"cluster": "prod", "region": "eu-west-1", "unused": "value"},
+			result: "https://prometheus-prod.eu-west-1.internal:9090",
+		},
+		{
+			title:  "missing variable",
+			values: map[string]string{"cluster": "prod"},
+			err:    `missing value for the variable "region" of the URL template`,
+		},
+		{
+			title:  "empty variable",
+			values: map[string]string{"cluster": "", "region": "eu-west-1"},
+			err:    `missing value for the variable "cluster" of the URL template`,
+		},
+		{
+			title:  "value changing the host",
+			values: map[string]string{"cluster": "evil.com/", "region": "eu-west-1"},
+			err:    `invalid value "evil.com/" for the variable "cluster" of the URL template`,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := template.Resolve(test.values)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result.String())
+		})
+	}
+}
+
+func TestExtractURLTemplate(t *testing.T) {
+	testSuite := []struct {
+		title      string
+		pluginSpec any
+		result     URLTemplate
+		isError    bool
+	}{
+		{
+			title: "http proxy with a template",
+			pluginSpec: map[string]any{
+				"proxy": map[string]any{
+					"kind": "HTTPProxy",
+					"spec": map[string]any{
+						"url":         "https://prometheus.internal:9090",
+						"urlTemplate": "https://prometheus-{cluster}.internal:9090",
+					},
+				},
+			},
+			result: "https://prometheus-{cluster}.internal:9090",
+		},
+		{
+			title: "http proxy without template",
+			pluginSpec: map[string]any{
+				"proxy": map[string]any{
+					"kind": "HTTPProxy",
+					"spec": map[string]any{"url": "https://prometheus.internal:9090"},
+				},
+			},
+		},
+		{
+			title:      "no proxy",
+			pluginSpec: map[string]any{"directUrl": "https://prometheus.internal:9090"},
+		},
+		{
+			title: "invalid template",
+			pluginSpec: map[string]any{
+				"proxy": map[string]any{
+					"kind": "HTTPProxy",
+					"spec": map[string]any{
+						"url":         "https://prometheus.internal:9090",
+						"urlTemplate": "https://prometheus-{cluster name}.internal:9090",
+					},
+				},
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := ExtractURLTemplate(test.pluginSpec)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
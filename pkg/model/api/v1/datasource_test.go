@@ -159,3 +159,66 @@ spec:
 		})
 	}
 }
+
+func TestFilterDatasourceByLabels(t *testing.T) {
+	newDatasource := func(name string, labels map[string]string) *Datasource {
+		metadata := NewProjectMetadata("perses", name)
+		metadata.Labels = labels
+		return &Datasource{Kind: KindDatasource, Metadata: *metadata}
+	}
+	list := []*Datasource{
+		newDatasource("prod-infra", map[string]string{"env": "prod", "team": "infra"}),
+		newDatasource("prod-web", map[string]string{"env": "prod", "team": "web"}),
+		newDatasource("dev", map[string]string{"env": "dev"}),
+		newDatasource("unlabelled", nil),
+	}
+	testSuites := []struct {
+		title     string
+		selectors []string
+		result    []string
+	}{
+		{
+			title:     "no selector",
+			selectors: nil,
+			result:    []string{"prod-infra", "prod-web", "dev", "unlabelled"},
+		},
+		{
+			title:     "single label",
+			selectors: []string{"env=prod"},
+			result:    []string{"prod-infra", "prod-web"},
+		},
+		{
+			title:     "comma separated labels",
+			selectors: []string{"env=prod,team=web"},
+			result:    []string{"prod-web"},
+		},
+		{
+			title:     "repeated selectors",
+			selectors: []string{"env=prod", "team!=web"},
+			result:    []string{"prod-infra"},
+		},
+		{
+			title:     "inequality keeps the unlabelled datasources",
+			selectors: []string{"env!=prod"},
+			result:    []string{"dev", "unlabelled"},
+		},
+		{
+			title:     "no match",
+			selectors: []string{"env=staging"},
+			result:    []string{},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			selector, err := ParseLabelSelector(test.selectors)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, d := range FilterDatasourceByLabels(selector, list) {
+				names = append(names, d.Metadata.Name)
+			}
+			assert.Equal(t, test.result, names)
+		})
+	}
+}
@@ -24,39 +24,47 @@ import (
 type Kind string
 
 const (
-	KindDashboard          Kind = "Dashboard"
-	KindDatasource         Kind = "Datasource"
-	KindEphemeralDashboard Kind = "EphemeralDashboard"
-	KindFolder             Kind = "Folder"
-	KindGlobalDatasource   Kind = "GlobalDatasource"
-	KindGlobalRole         Kind = "GlobalRole"
-	KindGlobalRoleBinding  Kind = "GlobalRoleBinding"
-	KindGlobalVariable     Kind = "GlobalVariable"
-	KindGlobalSecret       Kind = "GlobalSecret"
-	KindProject            Kind = "Project"
-	KindRole               Kind = "Role"
-	KindRoleBinding        Kind = "RoleBinding"
-	KindSecret             Kind = "Secret"
-	KindUser               Kind = "User"
-	KindVariable           Kind = "Variable"
+	KindAPIKey                Kind = "APIKey"
+	KindDashboard             Kind = "Dashboard"
+	KindDashboardTemplate     Kind = "DashboardTemplate"
+	KindDatasource            Kind = "Datasource"
+	KindEphemeralDashboard    Kind = "EphemeralDashboard"
+	KindFolder                Kind = "Folder"
+	KindGlobalDatasource      Kind = "GlobalDatasource"
+	KindGlobalRole            Kind = "GlobalRole"
+	KindGlobalRoleBinding     Kind = "GlobalRoleBinding"
+	KindGlobalVariable        Kind = "GlobalVariable"
+	KindGlobalSecret          Kind = "GlobalSecret"
+	KindProject               Kind = "Project"
+	KindRole                  Kind = "Role"
+	KindRoleBinding           Kind = "RoleBinding"
+	KindSecret                Kind = "Secret"
+	KindTemplatePolicy        Kind = "TemplatePolicy"
+	KindTemplatePolicyBinding Kind = "TemplatePolicyBinding"
+	KindUser                  Kind = "User"
+	KindVariable              Kind = "Variable"
 )
 
 var PluralKindMap = map[Kind]string{
-	KindDashboard:          "dashboards",
-	KindDatasource:         "datasources",
-	KindEphemeralDashboard: "ephemeraldashboards",
-	KindFolder:             "folders",
-	KindGlobalDatasource:   "globaldatasources",
-	KindGlobalRole:         "globalroles",
-	KindGlobalRoleBinding:  "globalrolebindings",
-	KindGlobalSecret:       "globalsecrets",
-	KindGlobalVariable:     "globalvariables",
-	KindProject:            "projects",
-	KindRole:               "roles",
-	KindRoleBinding:        "rolebindings",
-	KindSecret:             "secrets",
-	KindUser:               "users",
-	KindVariable:           "variables",
+	KindAPIKey:                "apikeys",
+	KindDashboard:             "dashboards",
+	KindDashboardTemplate:     "dashboardtemplates",
+	KindDatasource:            "datasources",
+	KindEphemeralDashboard:    "ephemeraldashboards",
+	KindFolder:                "folders",
+	KindGlobalDatasource:      "globaldatasources",
+	KindGlobalRole:            "globalroles",
+	KindGlobalRoleBinding:     "globalrolebindings",
+	KindGlobalSecret:          "globalsecrets",
+	KindGlobalVariable:        "globalvariables",
+	KindProject:               "projects",
+	KindRole:                  "roles",
+	KindRoleBinding:           "rolebindings",
+	KindSecret:                "secrets",
+	KindTemplatePolicy:        "templatepolicies",
+	KindTemplatePolicyBinding: "templatepolicybindings",
+	KindUser:                  "users",
+	KindVariable:              "variables",
 }
 
 func (k *Kind) UnmarshalJSON(data []byte) error {
@@ -100,8 +108,12 @@ func (k *Kind) validate() error {
 // GetStruct return a pointer to an empty struct that matches the kind passed as a parameter.
 func GetStruct(kind Kind) (modelAPI.Entity, error) {
 	switch kind {
+	case KindAPIKey:
+		return &APIKey{}, nil
 	case KindDashboard:
 		return &Dashboard{}, nil
+	case KindDashboardTemplate:
+		return &DashboardTemplate{}, nil
 	case KindDatasource:
 		return &Datasource{}, nil
 	case KindEphemeralDashboard:
@@ -126,6 +138,10 @@ func GetStruct(kind Kind) (modelAPI.Entity, error) {
 		return &RoleBinding{}, nil
 	case KindSecret:
 		return &Secret{}, nil
+	case KindTemplatePolicy:
+		return &TemplatePolicy{}, nil
+	case KindTemplatePolicyBinding:
+		return &TemplatePolicyBinding{}, nil
 	case KindUser:
 		return &User{}, nil
 	case KindVariable:
@@ -137,7 +153,7 @@ func GetStruct(kind Kind) (modelAPI.Entity, error) {
 
 func IsGlobal(kind Kind) bool {
 	switch kind {
-	case KindGlobalDatasource, KindGlobalRole, KindGlobalRoleBinding, KindGlobalSecret, KindGlobalVariable, KindProject, KindUser:
+	case KindGlobalDatasource, KindGlobalRole, KindGlobalRoleBinding, KindGlobalSecret, KindGlobalVariable, KindProject, KindTemplatePolicy, KindTemplatePolicyBinding, KindUser:
 		return true
 	default:
 		return false
@@ -147,9 +163,15 @@ func IsGlobal(kind Kind) bool {
 // GetKind parse string to Kind (not case-sensitive)
 func GetKind(kind string) (*Kind, error) {
 	switch strings.ToLower(kind) {
+	case strings.ToLower(string(KindAPIKey)):
+		result := KindAPIKey
+		return &result, nil
 	case strings.ToLower(string(KindDashboard)):
 		result := KindDashboard
 		return &result, nil
+	case strings.ToLower(string(KindDashboardTemplate)):
+		result := KindDashboardTemplate
+		return &result, nil
 	case strings.ToLower(string(KindDatasource)):
 		result := KindDatasource
 		return &result, nil
@@ -186,6 +208,12 @@ func GetKind(kind string) (*Kind, error) {
 	case strings.ToLower(string(KindSecret)):
 		result := KindSecret
 		return &result, nil
+	case strings.ToLower(string(KindTemplatePolicy)):
+		result := KindTemplatePolicy
+		return &result, nil
+	case strings.ToLower(string(KindTemplatePolicyBinding)):
+		result := KindTemplatePolicyBinding
+		return &result, nil
 	case strings.ToLower(string(KindUser)):
 		result := KindUser
 		return &result, nil
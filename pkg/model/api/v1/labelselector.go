@@ -0,0 +1,94 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// labelSelectorSeparators are the characters used to write a label selector, so they cannot be part of a label.
+const labelSelectorSeparators = ",=!"
+
+func validateLabelKey(key string) error {
+	const maxLabelKeyLength = 63
+	if len(strings.TrimSpace(key)) == 0 {
+		return fmt.Errorf("label key cannot be empty")
+	}
+	if strings.TrimSpace(key) != key {
+		return fmt.Errorf("label key %q cannot start or end with whitespace", key)
+	}
+	if utf8.RuneCountInString(key) > maxLabelKeyLength {
+		return fmt.Errorf("label key %q cannot contain more than %d characters", key, maxLabelKeyLength)
+	}
+	if strings.ContainsAny(key, labelSelectorSeparators) {
+		return fmt.Errorf("label key %q cannot contain any of the characters %q", key, labelSelectorSeparators)
+	}
+	return nil
+}
+
+// LabelRequirement is a single condition of a LabelSelector.
+type LabelRequirement struct {
+	Key   string
+	Value string
+	// NotEqual is true when the label must be different from the value (or missing).
+	NotEqual bool
+}
+
+func (r LabelRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	if r.NotEqual {
+		return !ok || value != r.Value
+	}
+	return ok && value == r.Value
+}
+
+// LabelSelector filters the resources on their labels. A resource is selected when it meets every requirement.
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a list of selectors like `env=prod` or `env!=dev`.
+// Each of them can hold several requirements separated by a comma, e.g. `env=prod,team=infra`.
+func ParseLabelSelector(selectors []string) (LabelSelector, error) {
+	var result LabelSelector
+	for _, selector := range selectors {
+		for _, requirement := range strings.Split(selector, ",") {
+			notEqual := true
+			key, value, found := strings.Cut(requirement, "!=")
+			if !found {
+				notEqual = false
+				key, value, found = strings.Cut(requirement, "=")
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid label selector %q, expected key=value or key!=value", requirement)
+			}
+			key = strings.TrimSpace(key)
+			if err := validateLabelKey(key); err != nil {
+				return nil, fmt.Errorf("invalid label selector %q: %w", requirement, err)
+			}
+			result = append(result, LabelRequirement{Key: key, Value: strings.TrimSpace(value), NotEqual: notEqual})
+		}
+	}
+	return result, nil
+}
+
+// Matches returns true when the labels meet every requirement of the selector. An empty selector matches everything.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		selectors []string
+		result    LabelSelector
+	}{
+		{
+			title:     "no selector",
+			selectors: nil,
+			result:    nil,
+		},
+		{
+			title:     "single equality",
+			selectors: []string{"env=prod"},
+			result:    LabelSelector{{Key: "env", Value: "prod"}},
+		},
+		{
+			title:     "inequality",
+			selectors: []string{"env!=dev"},
+			result:    LabelSelector{{Key: "env", Value: "dev", NotEqual: true}},
+		},
+		{
+			title:     "comma separated and repeated selectors",
+			selectors: []string{"env=prod, team = infra", "tier!=cache"},
+			result: LabelSelector{
+				{Key: "env", Value: "prod"},
+				{Key: "team", Value: "infra"},
+				{Key: "tier", Value: "cache", NotEqual: true},
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := ParseLabelSelector(test.selectors)
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestParseLabelSelectorError(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		selectors []string
+	}{
+		{
+			title:     "missing operator",
+			selectors: []string{"env"},
+		},
+		{
+			title:     "empty key",
+			selectors: []string{"=prod"},
+		},
+		{
+			title:     "empty requirement",
+			selectors: []string{"env=prod,"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := ParseLabelSelector(test.selectors)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "infra"}
+	testSuites := []struct {
+		title    string
+		selector LabelSelector
+		result   bool
+	}{
+		{
+			title:    "empty selector",
+			selector: nil,
+			result:   true,
+		},
+		{
+			title:    "all requirements met",
+			selector: LabelSelector{{Key: "env", Value: "prod"}, {Key: "team", Value: "infra"}},
+			result:   true,
+		},
+		{
+			title:    "one requirement not met",
+			selector: LabelSelector{{Key: "env", Value: "prod"}, {Key: "team", Value: "web"}},
+			result:   false,
+		},
+		{
+			title:    "missing label",
+			selector: LabelSelector{{Key: "region", Value: "eu"}},
+			result:   false,
+		},
+		{
+			title:    "inequality on a missing label",
+			selector: LabelSelector{{Key: "region", Value: "eu", NotEqual: true}},
+			result:   true,
+		},
+		{
+			title:    "inequality on an equal label",
+			selector: LabelSelector{{Key: "env", Value: "prod", NotEqual: true}},
+			result:   false,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.result, test.selector.Matches(labels))
+		})
+	}
+}
@@ -46,6 +46,10 @@ type Metadata struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxItems=20
 	Tags set.Set[string] `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Labels can be used to filter the resources with a label selector, e.g. env=prod.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=20
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 func (m *Metadata) CreateNow() {
@@ -67,6 +71,10 @@ func (m *Metadata) GetName() string {
 	return m.Name
 }
 
+func (m *Metadata) GetLabels() map[string]string {
+	return m.Labels
+}
+
 func (m *Metadata) Flatten(sensitive bool) {
 	if !sensitive {
 		m.Name = strings.ToLower(m.Name)
@@ -104,12 +112,36 @@ func (m *Metadata) validate() error {
 		return err
 	}
 
-	return m.validateTags()
+	if err := m.validateTags(); err != nil {
+		return err
+	}
+	return m.validateLabels()
+}
+
+func (m *Metadata) validateLabels() error {
+	const maxLabels = 20
+	const maxLabelLength = 63
+
+	if len(m.Labels) > maxLabels {
+		return fmt.Errorf("cannot contain more than %d labels", maxLabels)
+	}
+	for key, value := range m.Labels {
+		if err := validateLabelKey(key); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(value) > maxLabelLength {
+			return fmt.Errorf("value of the label %q cannot contain more than %d characters", key, maxLabelLength)
+		}
+		if strings.ContainsAny(value, labelSelectorSeparators) {
+			return fmt.Errorf("value of the label %q cannot contain any of the characters %q", key, labelSelectorSeparators)
+		}
+	}
+	return nil
 }
 
 func (m *Metadata) validateTags() error {
 	const maxTags = 20
-	const maxTagLength = 50
+	const maxTagLength = 64
 
 	if len(m.Tags) > maxTags {
 		return fmt.Errorf("cannot contain more than %d tags", maxTags)
@@ -145,9 +177,10 @@ type PublicMetadata struct {
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Format=date-time
 	// +kubebuilder:validation:Optional
-	UpdatedAt time.Time       `json:"updatedAt" yaml:"updatedAt"`
-	Version   uint64          `json:"version" yaml:"version"`
-	Tags      set.Set[string] `json:"tags,omitempty" yaml:"tags,omitempty"`
+	UpdatedAt time.Time         `json:"updatedAt" yaml:"updatedAt"`
+	Version   uint64            `json:"version" yaml:"version"`
+	Tags      set.Set[string]   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 func NewPublicMetadata(name string) PublicMetadata {
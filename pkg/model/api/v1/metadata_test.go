@@ -245,21 +245,21 @@ version: 1
 			err: fmt.Errorf("\"f o o\" is not a correct name. It should match the regexp: ^[a-zA-Z0-9_.-]+$"),
 		},
 		{
-			title: "tag cannot exceed 50 chars",
+			title: "tag cannot exceed 64 chars",
 			jason: `
 {
   "name": "foo",
   "version": 1,
-  "tags": ["aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
+  "tags": ["aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]
 }
 `,
 			yamele: `
 name: "foo"
 version: 1
 tags:
-  - "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+  - "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 `,
-			err: fmt.Errorf("tag \"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\" cannot contain more than 50 characters"),
+			err: fmt.Errorf("tag \"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\" cannot contain more than 64 characters"),
 		},
 		{
 			title: "cannot contain more than 20 tags",
@@ -315,6 +315,40 @@ tags:
 `,
 			err: fmt.Errorf("tag \"  tag  \" cannot start or end with whitespace"),
 		},
+		{
+			title: "label key cannot contain a selector separator",
+			jason: `
+{
+  "name": "foo",
+  "version": 1,
+  "labels": {"env=prod": "true"}
+}
+`,
+			yamele: `
+name: "foo"
+version: 1
+labels:
+  "env=prod": "true"
+`,
+			err: fmt.Errorf("label key \"env=prod\" cannot contain any of the characters \",=!\""),
+		},
+		{
+			title: "label value cannot contain a selector separator",
+			jason: `
+{
+  "name": "foo",
+  "version": 1,
+  "labels": {"env": "prod,dev"}
+}
+`,
+			yamele: `
+name: "foo"
+version: 1
+labels:
+  env: "prod,dev"
+`,
+			err: fmt.Errorf("value of the label \"env\" cannot contain any of the characters \",=!\""),
+		},
 	}
 	for _, test := range testSuite {
 		t.Run(test.title, func(t *testing.T) {
@@ -421,3 +455,41 @@ project: "bar"
 		})
 	}
 }
+
+func TestDashboardMetadata(t *testing.T) {
+	deletedAt := getDummyDate()
+	expected := DashboardMetadata{
+		ProjectMetadata: ProjectMetadata{
+			Metadata: Metadata{
+				Name:    "node",
+				Version: 2,
+			},
+			ProjectMetadataWrapper: ProjectMetadataWrapper{
+				Project: "perses",
+			},
+		},
+		ResourceVersion: "2",
+		DeletedAt:       &deletedAt,
+	}
+
+	jsonData, err := json.Marshal(expected)
+	assert.NoError(t, err)
+	var fromJSON DashboardMetadata
+	assert.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	assert.Equal(t, expected, fromJSON)
+
+	yamlData, err := yaml.Marshal(expected)
+	assert.NoError(t, err)
+	var fromYAML DashboardMetadata
+	assert.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+	assert.Equal(t, expected, fromYAML)
+
+	// the other resources don't have these fields
+	projectData, err := json.Marshal(expected.ProjectMetadata)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(projectData), "resourceVersion")
+	assert.NotContains(t, string(projectData), "deletedAt")
+
+	// the validation of the metadata still applies
+	assert.Error(t, json.Unmarshal([]byte(`{"name": "invalid name", "project": "perses", "resourceVersion": "2"}`), &fromJSON))
+}
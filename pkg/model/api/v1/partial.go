@@ -50,3 +50,22 @@ func (p *PartialProjectEntity) GetKind() string {
 func (p *PartialProjectEntity) GetSpec() any {
 	return p.Spec
 }
+
+// PartialDashboard is the PartialProjectEntity of the dashboards, keeping the metadata only the dashboards have.
+type PartialDashboard struct {
+	Kind     Kind              `json:"kind" yaml:"kind"`
+	Metadata DashboardMetadata `json:"metadata" yaml:"metadata"`
+	Spec     struct{}          `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+func (p *PartialDashboard) GetMetadata() modelAPI.Metadata {
+	return &p.Metadata
+}
+
+func (p *PartialDashboard) GetKind() string {
+	return string(p.Kind)
+}
+
+func (p *PartialDashboard) GetSpec() any {
+	return p.Spec
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	modelCommon "github.com/perses/perses/pkg/model/api/v1/common"
 	"github.com/perses/spec/go/common"
 )
 
@@ -102,6 +103,8 @@ type ModuleSpec struct {
 	ModuleName  string   `json:"moduleName,omitempty" yaml:"moduleName,omitempty"`
 	ModuleOrg   string   `json:"moduleOrg,omitempty" yaml:"moduleOrg,omitempty"`
 	Plugins     []Plugin `json:"plugins" yaml:"plugins"`
+	// MinPersesVersion is the lowest version of Perses the module can run with.
+	MinPersesVersion *modelCommon.Semver `json:"minPersesVersion,omitempty" yaml:"minPersesVersion,omitempty"`
 }
 
 func (m *ModuleSpec) UnmarshalJSON(data []byte) error {
@@ -140,6 +143,19 @@ func (m *ModuleSpec) validate() error {
 	return nil
 }
 
+// PluginInfo describes a plugin installed on the server.
+type PluginInfo struct {
+	Name string `json:"name" yaml:"name"`
+	// Module is the name of the module providing the plugin.
+	Module  string `json:"module" yaml:"module"`
+	Version string `json:"version" yaml:"version"`
+	Kind    Kind   `json:"kind" yaml:"kind"`
+	// Path is the local folder of the module. It is empty for a module served by a development server.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Enabled is false when the module has been found but couldn't be loaded.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
 type ModuleMetadata struct {
 	Name     string `json:"name" yaml:"name"`
 	Version  string `json:"version" yaml:"version"`
@@ -0,0 +1,59 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+// KindPluginState is the kind of the entries stored in the key-value store of the plugins.
+// These entries are only manipulated by the server and are not exposed through the API. That's why this kind is neither
+// part of PluralKindMap nor accepted by GetKind.
+const KindPluginState Kind = "PluginState"
+
+type PluginStateSpec struct {
+	// Value is the raw value stored by the plugin.
+	Value []byte `json:"value" yaml:"value"`
+}
+
+// PluginState is an entry of the key-value store of a plugin module.
+// The project in the metadata is the name of the plugin module, and the name is the key of the entry.
+type PluginState struct {
+	// Kind is a plain string since KindPluginState is not a valid Kind to unmarshal.
+	Kind     string          `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata `json:"metadata" yaml:"metadata"`
+	Spec     PluginStateSpec `json:"spec" yaml:"spec"`
+}
+
+func NewPluginState(module string, key string, value []byte) *PluginState {
+	return &PluginState{
+		Kind:     string(KindPluginState),
+		Metadata: *NewProjectMetadata(module, key),
+		Spec: PluginStateSpec{
+			Value: value,
+		},
+	}
+}
+
+func (p *PluginState) GetMetadata() modelAPI.Metadata {
+	return &p.Metadata
+}
+
+func (p *PluginState) GetKind() string {
+	return p.Kind
+}
+
+func (p *PluginState) GetSpec() any {
+	return p.Spec
+}
@@ -16,6 +16,7 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	modelAPI "github.com/perses/perses/pkg/model/api"
 	"github.com/perses/perses/pkg/model/api/v1/common"
@@ -75,3 +76,14 @@ func (p *Project) validate() error {
 	}
 	return nil
 }
+
+// ProjectStats is a summary of the resources contained in a project.
+type ProjectStats struct {
+	DashboardCount  int `json:"dashboardCount" yaml:"dashboardCount"`
+	DatasourceCount int `json:"datasourceCount" yaml:"datasourceCount"`
+	VariableCount   int `json:"variableCount" yaml:"variableCount"`
+	// CreatedAt is the creation date of the project.
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// LastModifiedAt is the most recent update date across the project and the resources it contains.
+	LastModifiedAt time.Time `json:"lastModifiedAt" yaml:"lastModifiedAt"`
+}
@@ -0,0 +1,60 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+type PublicAPIKeySpec struct {
+	// Key is the complete key to send in the header `Authorization: Bearer <key>`.
+	// It is only returned once, in the response of the creation of the key.
+	Key       string     `json:"key,omitempty" yaml:"key,omitempty"`
+	Scopes    []string   `json:"scopes" yaml:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+}
+
+type PublicAPIKey struct {
+	Kind     Kind                  `json:"kind" yaml:"kind"`
+	Metadata PublicProjectMetadata `json:"metadata" yaml:"metadata"`
+	Spec     PublicAPIKeySpec      `json:"spec" yaml:"spec"`
+}
+
+func NewPublicAPIKey(a *APIKey) *PublicAPIKey {
+	if a == nil {
+		return nil
+	}
+	return &PublicAPIKey{
+		Kind:     a.Kind,
+		Metadata: NewPublicProjectMetadataFromCopy(a.Metadata),
+		Spec: PublicAPIKeySpec{
+			Scopes:    a.Spec.Scopes,
+			ExpiresAt: a.Spec.ExpiresAt,
+		},
+	}
+}
+
+func (a *PublicAPIKey) GetMetadata() modelAPI.Metadata {
+	return &a.Metadata
+}
+
+func (a *PublicAPIKey) GetKind() string {
+	return string(a.Kind)
+}
+
+func (a *PublicAPIKey) GetSpec() any {
+	return a.Spec
+}
@@ -0,0 +1,82 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+// KindQueryAudit is the kind of the records of the queries sent to the datasources through the proxy.
+// Like KindPluginState, these records are only written by the server and are not a resource of the API.
+const KindQueryAudit Kind = "QueryAudit"
+
+type QueryAuditSpec struct {
+	// Project is the project of the datasource. It is empty for a global datasource.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	// Dashboard is set when the datasource is defined in a dashboard.
+	Dashboard string `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`
+	// Datasource is the name of the datasource.
+	Datasource string `json:"datasource" yaml:"datasource"`
+	Method     string `json:"method" yaml:"method"`
+	// Path is the path of the request on the datasource.
+	Path string `json:"path" yaml:"path"`
+	// Query is the value of the parameter `query`, if any.
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+	// Params are the parameters of the URL, merged with the ones of the body when it is a form.
+	Params map[string][]string `json:"params,omitempty" yaml:"params,omitempty"`
+	// LatencyMs is the time, in milliseconds, taken by the proxy to answer.
+	LatencyMs int64 `json:"latencyMs" yaml:"latencyMs"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status" yaml:"status"`
+	// Time is when the request has been received.
+	Time time.Time `json:"time" yaml:"time"`
+}
+
+// QueryAudit records a query sent to a datasource through the proxy.
+// The project in the metadata is the name of the datasource, so the records can be listed by datasource, and the name
+// is generated from the time of the query to be unique.
+type QueryAudit struct {
+	// Kind is a plain string since KindQueryAudit is not a valid Kind to unmarshal.
+	Kind     string          `json:"kind" yaml:"kind"`
+	Metadata ProjectMetadata `json:"metadata" yaml:"metadata"`
+	Spec     QueryAuditSpec  `json:"spec" yaml:"spec"`
+}
+
+func NewQueryAudit(spec QueryAuditSpec) *QueryAudit {
+	suffix := make([]byte, 4)
+	// In the unlikely case it fails, two queries received at the same nanosecond would share the same name.
+	_, _ = rand.Read(suffix)
+	return &QueryAudit{
+		Kind:     string(KindQueryAudit),
+		Metadata: *NewProjectMetadata(spec.Datasource, fmt.Sprintf("%019d-%s", spec.Time.UnixNano(), hex.EncodeToString(suffix))),
+		Spec:     spec,
+	}
+}
+
+func (q *QueryAudit) GetMetadata() modelAPI.Metadata {
+	return &q.Metadata
+}
+
+func (q *QueryAudit) GetKind() string {
+	return q.Kind
+}
+
+func (q *QueryAudit) GetSpec() any {
+	return q.Spec
+}
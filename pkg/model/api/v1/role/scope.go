@@ -22,22 +22,27 @@ import (
 type Scope string
 
 const (
-	DashboardScope          Scope = "Dashboard"
-	DatasourceScope         Scope = "Datasource"
-	EphemeralDashboardScope Scope = "EphemeralDashboard"
-	FolderScope             Scope = "Folder"
-	GlobalDatasourceScope   Scope = "GlobalDatasource"
-	GlobalRoleScope         Scope = "GlobalRole"
-	GlobalRoleBindingScope  Scope = "GlobalRoleBinding"
-	GlobalSecretScope       Scope = "GlobalSecret"
-	GlobalVariableScope     Scope = "GlobalVariable"
-	ProjectScope            Scope = "Project"
-	RoleScope               Scope = "Role"
-	RoleBindingScope        Scope = "RoleBinding"
-	SecretScope             Scope = "Secret"
-	UserScope               Scope = "User"
-	VariableScope           Scope = "Variable"
-	WildcardScope           Scope = "*"
+	APIKeyScope                Scope = "APIKey"
+	DashboardScope             Scope = "Dashboard"
+	DashboardTemplateScope     Scope = "DashboardTemplate"
+	DashboardVersionScope      Scope = "DashboardVersion"
+	DatasourceScope            Scope = "Datasource"
+	EphemeralDashboardScope    Scope = "EphemeralDashboard"
+	FolderScope                Scope = "Folder"
+	GlobalDatasourceScope      Scope = "GlobalDatasource"
+	GlobalRoleScope            Scope = "GlobalRole"
+	GlobalRoleBindingScope     Scope = "GlobalRoleBinding"
+	GlobalSecretScope          Scope = "GlobalSecret"
+	GlobalVariableScope        Scope = "GlobalVariable"
+	ProjectScope               Scope = "Project"
+	RoleScope                  Scope = "Role"
+	RoleBindingScope           Scope = "RoleBinding"
+	SecretScope                Scope = "Secret"
+	TemplatePolicyScope        Scope = "TemplatePolicy"
+	TemplatePolicyBindingScope Scope = "TemplatePolicyBinding"
+	UserScope                  Scope = "User"
+	VariableScope              Scope = "Variable"
+	WildcardScope              Scope = "*"
 )
 
 func (k *Scope) UnmarshalJSON(data []byte) error {
@@ -79,9 +84,18 @@ func (k *Scope) validate() error {
 // GetScope parse string to Scope (not case-sensitive)
 func GetScope(scope string) (*Scope, error) {
 	switch strings.ToLower(scope) {
+	case strings.ToLower(string(APIKeyScope)):
+		result := APIKeyScope
+		return &result, nil
 	case strings.ToLower(string(DashboardScope)):
 		result := DashboardScope
 		return &result, nil
+	case strings.ToLower(string(DashboardTemplateScope)):
+		result := DashboardTemplateScope
+		return &result, nil
+	case strings.ToLower(string(DashboardVersionScope)):
+		result := DashboardVersionScope
+		return &result, nil
 	case strings.ToLower(string(DatasourceScope)):
 		result := DatasourceScope
 		return &result, nil
@@ -118,6 +132,12 @@ func GetScope(scope string) (*Scope, error) {
 	case strings.ToLower(string(SecretScope)):
 		result := SecretScope
 		return &result, nil
+	case strings.ToLower(string(TemplatePolicyScope)):
+		result := TemplatePolicyScope
+		return &result, nil
+	case strings.ToLower(string(TemplatePolicyBindingScope)):
+		result := TemplatePolicyBindingScope
+		return &result, nil
 	case strings.ToLower(string(UserScope)):
 		result := UserScope
 		return &result, nil
@@ -136,7 +156,7 @@ func IsGlobalScope(scope Scope) bool {
 	switch scope {
 	// ProjectScope is not global even if it should be. Owners of projects should be able to delete their own projects
 	// As ProjectScope is not Global, it can be added in Role scopes and allow this flow.
-	case GlobalDatasourceScope, GlobalRoleScope, GlobalRoleBindingScope, GlobalSecretScope, GlobalVariableScope, UserScope:
+	case GlobalDatasourceScope, GlobalRoleScope, GlobalRoleBindingScope, GlobalSecretScope, GlobalVariableScope, TemplatePolicyScope, TemplatePolicyBindingScope, UserScope:
 		return true
 	default:
 		return false
@@ -0,0 +1,79 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perses/common/set"
+)
+
+type TagOperator string
+
+const (
+	// TagOperatorAnd selects the resources having all the tags of the selector.
+	TagOperatorAnd TagOperator = "and"
+	// TagOperatorOr selects the resources having at least one tag of the selector.
+	TagOperatorOr TagOperator = "or"
+)
+
+// TagSelector filters the resources on their tags.
+type TagSelector struct {
+	Tags     []string
+	Operator TagOperator
+}
+
+// ParseTagSelector parses a list of tags like `sre`. Each of them can hold several tags separated by a comma,
+// e.g. `sre,team-payments`. An empty operator means TagOperatorAnd.
+func ParseTagSelector(tags []string, operator string) (TagSelector, error) {
+	result := TagSelector{Operator: TagOperator(strings.ToLower(operator))}
+	switch result.Operator {
+	case "":
+		result.Operator = TagOperatorAnd
+	case TagOperatorAnd, TagOperatorOr:
+	default:
+		return TagSelector{}, fmt.Errorf("invalid tag operator %q, expected %q or %q", operator, TagOperatorAnd, TagOperatorOr)
+	}
+	for _, list := range tags {
+		for _, tag := range strings.Split(list, ",") {
+			if tag = strings.TrimSpace(tag); len(tag) > 0 {
+				result.Tags = append(result.Tags, tag)
+			}
+		}
+	}
+	return result, nil
+}
+
+// IsEmpty returns true when the selector doesn't contain any tag, so it matches every resource.
+func (s TagSelector) IsEmpty() bool {
+	return len(s.Tags) == 0
+}
+
+// Matches returns true when the tags meet the selector. An empty selector matches everything.
+func (s TagSelector) Matches(tags set.Set[string]) bool {
+	if s.IsEmpty() {
+		return true
+	}
+	for _, tag := range s.Tags {
+		ok := tags.Contains(tag)
+		if ok && s.Operator == TagOperatorOr {
+			return true
+		}
+		if !ok && s.Operator == TagOperatorAnd {
+			return false
+		}
+	}
+	return s.Operator == TagOperatorAnd
+}
@@ -0,0 +1,105 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/perses/common/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagSelector(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		tags     []string
+		operator string
+		result   TagSelector
+		err      error
+	}{
+		{
+			title:  "no tag",
+			result: TagSelector{Operator: TagOperatorAnd},
+		},
+		{
+			title:  "comma separated and repeated tags",
+			tags:   []string{"sre, team-payments", "prod"},
+			result: TagSelector{Tags: []string{"sre", "team-payments", "prod"}, Operator: TagOperatorAnd},
+		},
+		{
+			title:    "or operator",
+			tags:     []string{"sre"},
+			operator: "OR",
+			result:   TagSelector{Tags: []string{"sre"}, Operator: TagOperatorOr},
+		},
+		{
+			title:    "unknown operator",
+			tags:     []string{"sre"},
+			operator: "xor",
+			err:      fmt.Errorf("invalid tag operator \"xor\", expected \"and\" or \"or\""),
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := ParseTagSelector(test.tags, test.operator)
+			if test.err != nil {
+				assert.Equal(t, test.err.Error(), err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestTagSelectorMatches(t *testing.T) {
+	tags := set.New("sre", "team-payments")
+	testSuites := []struct {
+		title    string
+		selector TagSelector
+		result   bool
+	}{
+		{
+			title:    "empty selector",
+			selector: TagSelector{Operator: TagOperatorAnd},
+			result:   true,
+		},
+		{
+			title:    "and with all the tags",
+			selector: TagSelector{Tags: []string{"sre", "team-payments"}, Operator: TagOperatorAnd},
+			result:   true,
+		},
+		{
+			title:    "and with a missing tag",
+			selector: TagSelector{Tags: []string{"sre", "prod"}, Operator: TagOperatorAnd},
+			result:   false,
+		},
+		{
+			title:    "or with one of the tags",
+			selector: TagSelector{Tags: []string{"prod", "sre"}, Operator: TagOperatorOr},
+			result:   true,
+		},
+		{
+			title:    "or with none of the tags",
+			selector: TagSelector{Tags: []string{"prod", "dev"}, Operator: TagOperatorOr},
+			result:   false,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.result, test.selector.Matches(tags))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+// TemplateLabel is the label a dashboard uses to reference the template it has been created from.
+const TemplateLabel = "perses.dev/template"
+
+type TemplatePolicySpec struct {
+	// AllowedTemplates is the list of templates the dashboards can be created from.
+	// When it is empty, any template is allowed, as well as dashboards created from scratch.
+	AllowedTemplates []string `json:"allowedTemplates,omitempty" yaml:"allowedTemplates,omitempty"`
+	// RequiredLabels are the labels every dashboard must carry.
+	// An empty value only requires the label to be present, whatever its value.
+	RequiredLabels map[string]string `json:"requiredLabels,omitempty" yaml:"requiredLabels,omitempty"`
+}
+
+func (t *TemplatePolicySpec) validate() error {
+	for _, template := range t.AllowedTemplates {
+		if len(template) == 0 {
+			return fmt.Errorf("allowedTemplates cannot contain an empty template")
+		}
+	}
+	for key := range t.RequiredLabels {
+		if err := validateLabelKey(key); err != nil {
+			return fmt.Errorf("invalid requiredLabels: %w", err)
+		}
+	}
+	return nil
+}
+
+// Check returns the list of violations of the policy by a dashboard carrying the given labels.
+// The result is empty when the dashboard complies with the policy.
+func (t *TemplatePolicySpec) Check(labels map[string]string) []string {
+	var violations []string
+	if len(t.AllowedTemplates) > 0 {
+		template, ok := labels[TemplateLabel]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("label %q is required, the dashboard must be created from one of the templates %q", TemplateLabel, t.AllowedTemplates))
+		} else if !slices.Contains(t.AllowedTemplates, template) {
+			violations = append(violations, fmt.Sprintf("template %q is not allowed, use one of the templates %q", template, t.AllowedTemplates))
+		}
+	}
+	// Sort the keys so the violations are always reported in the same order.
+	keys := make([]string, 0, len(t.RequiredLabels))
+	for key := range t.RequiredLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expected := t.RequiredLabels[key]
+		value, ok := labels[key]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("label %q is required", key))
+		} else if len(expected) > 0 && value != expected {
+			violations = append(violations, fmt.Sprintf("label %q must be equal to %q but is %q", key, expected, value))
+		}
+	}
+	return violations
+}
+
+// TemplatePolicy restricts the templates the dashboards can be created from.
+// It is enforced in the projects bound to it through a TemplatePolicyBinding.
+type TemplatePolicy struct {
+	Kind     Kind               `json:"kind" yaml:"kind"`
+	Metadata Metadata           `json:"metadata" yaml:"metadata"`
+	Spec     TemplatePolicySpec `json:"spec" yaml:"spec"`
+}
+
+func (t *TemplatePolicy) GetMetadata() modelAPI.Metadata {
+	return &t.Metadata
+}
+
+func (t *TemplatePolicy) GetKind() string {
+	return string(t.Kind)
+}
+
+func (t *TemplatePolicy) GetSpec() any {
+	return t.Spec
+}
+
+func (t *TemplatePolicy) UnmarshalJSON(data []byte) error {
+	var tmp TemplatePolicy
+	type plain TemplatePolicy
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplatePolicy) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp TemplatePolicy
+	type plain TemplatePolicy
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplatePolicy) validate() error {
+	if t.Kind != KindTemplatePolicy {
+		return fmt.Errorf("invalid kind: %q for a TemplatePolicy type", t.Kind)
+	}
+	return t.Spec.validate()
+}
+
+type TemplatePolicyBindingSpec struct {
+	// Policy is the name of the TemplatePolicy concerned by the binding (metadata.name)
+	Policy string `json:"policy" yaml:"policy"`
+	// Projects are the names of the projects in which the policy is enforced.
+	Projects []string `json:"projects" yaml:"projects"`
+}
+
+func (t *TemplatePolicyBindingSpec) Has(project string) bool {
+	return slices.Contains(t.Projects, project)
+}
+
+func (t *TemplatePolicyBindingSpec) validate() error {
+	if len(t.Policy) == 0 {
+		return fmt.Errorf("templatepolicybinding policy cannot be empty")
+	}
+	if len(t.Projects) == 0 {
+		return fmt.Errorf("templatepolicybinding projects cannot be empty")
+	}
+	for _, project := range t.Projects {
+		if len(project) == 0 {
+			return fmt.Errorf("templatepolicybinding projects cannot contain an empty project")
+		}
+	}
+	return nil
+}
+
+// TemplatePolicyBinding enforces a TemplatePolicy in a list of projects.
+type TemplatePolicyBinding struct {
+	Kind     Kind                      `json:"kind" yaml:"kind"`
+	Metadata Metadata                  `json:"metadata" yaml:"metadata"`
+	Spec     TemplatePolicyBindingSpec `json:"spec" yaml:"spec"`
+}
+
+func (t *TemplatePolicyBinding) GetMetadata() modelAPI.Metadata {
+	return &t.Metadata
+}
+
+func (t *TemplatePolicyBinding) GetKind() string {
+	return string(t.Kind)
+}
+
+func (t *TemplatePolicyBinding) GetSpec() any {
+	return t.Spec
+}
+
+func (t *TemplatePolicyBinding) UnmarshalJSON(data []byte) error {
+	var tmp TemplatePolicyBinding
+	type plain TemplatePolicyBinding
+	if err := json.Unmarshal(data, (*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplatePolicyBinding) UnmarshalYAML(unmarshal func(any) error) error {
+	var tmp TemplatePolicyBinding
+	type plain TemplatePolicyBinding
+	if err := unmarshal((*plain)(&tmp)); err != nil {
+		return err
+	}
+	if err := (&tmp).validate(); err != nil {
+		return err
+	}
+	*t = tmp
+	return nil
+}
+
+func (t *TemplatePolicyBinding) validate() error {
+	if t.Kind != KindTemplatePolicyBinding {
+		return fmt.Errorf("invalid kind: %q for a TemplatePolicyBinding type", t.Kind)
+	}
+	return t.Spec.validate()
+}
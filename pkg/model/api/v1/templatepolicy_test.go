@@ -0,0 +1,115 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatePolicySpecCheck(t *testing.T) {
+	testSuite := []struct {
+		title      string
+		spec       TemplatePolicySpec
+		labels     map[string]string
+		violations []string
+	}{
+		{
+			title:  "empty policy",
+			spec:   TemplatePolicySpec{},
+			labels: map[string]string{"env": "prod"},
+		},
+		{
+			title:  "allowed template",
+			spec:   TemplatePolicySpec{AllowedTemplates: []string{"service", "database"}},
+			labels: map[string]string{TemplateLabel: "database"},
+		},
+		{
+			title:  "disallowed template",
+			spec:   TemplatePolicySpec{AllowedTemplates: []string{"service", "database"}},
+			labels: map[string]string{TemplateLabel: "custom"},
+			violations: []string{
+				`template "custom" is not allowed, use one of the templates ["service" "database"]`,
+			},
+		},
+		{
+			title: "missing template",
+			spec:  TemplatePolicySpec{AllowedTemplates: []string{"service"}},
+			violations: []string{
+				`label "perses.dev/template" is required, the dashboard must be created from one of the templates ["service"]`,
+			},
+		},
+		{
+			title: "required labels",
+			spec: TemplatePolicySpec{RequiredLabels: map[string]string{
+				"team": "",
+				"env":  "prod",
+				"tier": "backend",
+			}},
+			labels: map[string]string{"team": "infra", "env": "dev"},
+			violations: []string{
+				`label "env" must be equal to "prod" but is "dev"`,
+				`label "tier" is required`,
+			},
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.violations, test.spec.Check(test.labels))
+		})
+	}
+}
+
+func TestUnmarshalTemplatePolicyError(t *testing.T) {
+	testSuite := []struct {
+		title  string
+		jason  string
+		entity any
+		err    error
+	}{
+		{
+			title:  "empty allowed template",
+			jason:  `{"kind": "TemplatePolicy", "metadata": {"name": "test"}, "spec": {"allowedTemplates": ["service", ""]}}`,
+			entity: &TemplatePolicy{},
+			err:    fmt.Errorf("allowedTemplates cannot contain an empty template"),
+		},
+		{
+			title:  "invalid required label",
+			jason:  `{"kind": "TemplatePolicy", "metadata": {"name": "test"}, "spec": {"requiredLabels": {"env=prod": ""}}}`,
+			entity: &TemplatePolicy{},
+			err:    fmt.Errorf(`invalid requiredLabels: label key "env=prod" cannot contain any of the characters ",=!"`),
+		},
+		{
+			title:  "binding without policy",
+			jason:  `{"kind": "TemplatePolicyBinding", "metadata": {"name": "test"}, "spec": {"projects": ["perses"]}}`,
+			entity: &TemplatePolicyBinding{},
+			err:    fmt.Errorf("templatepolicybinding policy cannot be empty"),
+		},
+		{
+			title:  "binding without projects",
+			jason:  `{"kind": "TemplatePolicyBinding", "metadata": {"name": "test"}, "spec": {"policy": "test"}}`,
+			entity: &TemplatePolicyBinding{},
+			err:    fmt.Errorf("templatepolicybinding projects cannot be empty"),
+		},
+	}
+	for _, test := range testSuite {
+		t.Run(test.title, func(t *testing.T) {
+			err := json.Unmarshal([]byte(test.jason), test.entity)
+			assert.Equal(t, test.err.Error(), err.Error())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	modelAPI "github.com/perses/perses/pkg/model/api"
+)
+
+// KindUserFavorite is the kind of the records of the dashboards marked as favorite by the users.
+// Like KindDashboardHistory, these records are only written by the server and are not a resource of the API.
+const KindUserFavorite Kind = "UserFavorite"
+
+type UserFavoriteSpec struct {
+	UserName      string `json:"userName" yaml:"userName"`
+	ProjectName   string `json:"projectName" yaml:"projectName"`
+	DashboardName string `json:"dashboardName" yaml:"dashboardName"`
+}
+
+// UserFavorite records that a user marked a dashboard as favorite.
+// It is not part of a project: the name is derived from the name of the user and the dashboard, so the favorites of a
+// user can be listed with UserFavoriteNamePrefix.
+type UserFavorite struct {
+	// Kind is a plain string since KindUserFavorite is not a valid Kind to unmarshal.
+	Kind     string           `json:"kind" yaml:"kind"`
+	Metadata Metadata         `json:"metadata" yaml:"metadata"`
+	Spec     UserFavoriteSpec `json:"spec" yaml:"spec"`
+}
+
+// UserFavoriteNamePrefix returns the prefix shared by the names of the favorites of the given user.
+// The names are hashed to keep the names of the records short enough to be valid.
+func UserFavoriteNamePrefix(user string) string {
+	sum := sha256.Sum256([]byte(user))
+	return hex.EncodeToString(sum[:8]) + "-"
+}
+
+// UserFavoriteName returns the name of the record of the given dashboard in the favorites of the user.
+func UserFavoriteName(user string, project string, dashboard string) string {
+	sum := sha256.Sum256([]byte(project + "/" + dashboard))
+	return UserFavoriteNamePrefix(user) + hex.EncodeToString(sum[:16])
+}
+
+func NewUserFavorite(user string, project string, dashboard string) *UserFavorite {
+	return &UserFavorite{
+		Kind:     string(KindUserFavorite),
+		Metadata: *NewMetadata(UserFavoriteName(user, project, dashboard)),
+		Spec: UserFavoriteSpec{
+			UserName:      user,
+			ProjectName:   project,
+			DashboardName: dashboard,
+		},
+	}
+}
+
+func (f *UserFavorite) GetMetadata() modelAPI.Metadata {
+	return &f.Metadata
+}
+
+func (f *UserFavorite) GetKind() string {
+	return f.Kind
+}
+
+func (f *UserFavorite) GetSpec() any {
+	return f.Spec
+}
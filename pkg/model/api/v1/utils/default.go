@@ -69,7 +69,7 @@ func DefaultEditorRole(projectName string) *v1.Role {
 			Permissions: []role.Permission{
 				{
 					Actions: []role.Action{role.WildcardAction},
-					Scopes:  []role.Scope{role.DashboardScope, role.DatasourceScope, role.FolderScope, role.SecretScope, role.VariableScope},
+					Scopes:  []role.Scope{role.DashboardScope, role.DashboardTemplateScope, role.DatasourceScope, role.FolderScope, role.SecretScope, role.VariableScope},
 				},
 				{
 					Actions: []role.Action{role.ReadAction},
@@ -0,0 +1,92 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package api defines the interfaces a Go program embedding Perses can implement to extend the backend.
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidCredentials must be returned (possibly wrapped) by a CustomAuthProvider when the credentials are wrong.
+// Any other error is considered as an internal error and won't be forwarded to the user.
+var ErrInvalidCredentials = errors.New("wrong login or password")
+
+// UserInfo is the information returned by a CustomAuthProvider once the user is authenticated.
+// It is used to create or update the corresponding user in the database.
+type UserInfo struct {
+	// Login is the name of the user in Perses. It is mandatory.
+	Login     string
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+// TokenInfo is returned by a CustomAuthProvider when a user authenticated by it asks for a new access token.
+type TokenInfo struct {
+	// Login is the name of the user the new access token is issued for.
+	// If empty, the login contained in the refresh token is kept.
+	Login string
+}
+
+// CustomAuthProvider is the interface to implement to authenticate users against an identity provider that is not
+// supported natively by Perses. Once registered with RegisterAuthProvider and referenced in the configuration, it is
+// reachable through the endpoint /api/auth/providers/custom/<slug_id>/login.
+type CustomAuthProvider interface {
+	// Authenticate checks the credentials given and returns the information about the user.
+	Authenticate(username, password string) (*UserInfo, error)
+	// RefreshToken is called when a user authenticated by this provider asks for a new access token.
+	// The token is the refresh token delivered by Perses. Returning an error prevents a new access token from being issued,
+	// which can be used to revoke the access of a user disabled in the identity provider.
+	RefreshToken(token string) (*TokenInfo, error)
+}
+
+var (
+	authProvidersMutex sync.RWMutex
+	authProviders      = make(map[string]CustomAuthProvider)
+)
+
+// RegisterAuthProvider makes a CustomAuthProvider available under the given slug ID.
+// It is meant to be called before the API server starts, typically in an init function.
+func RegisterAuthProvider(slugID string, provider CustomAuthProvider) error {
+	if len(slugID) == 0 {
+		return errors.New("slug ID of the custom auth provider cannot be empty")
+	}
+	if provider == nil {
+		return fmt.Errorf("custom auth provider %q cannot be nil", slugID)
+	}
+	authProvidersMutex.Lock()
+	defer authProvidersMutex.Unlock()
+	if _, ok := authProviders[slugID]; ok {
+		return fmt.Errorf("a custom auth provider is already registered with the slug ID %q", slugID)
+	}
+	authProviders[slugID] = provider
+	return nil
+}
+
+// UnregisterAuthProvider removes the CustomAuthProvider registered under the given slug ID, if any.
+func UnregisterAuthProvider(slugID string) {
+	authProvidersMutex.Lock()
+	defer authProvidersMutex.Unlock()
+	delete(authProviders, slugID)
+}
+
+// GetAuthProvider returns the CustomAuthProvider registered under the given slug ID.
+func GetAuthProvider(slugID string) (CustomAuthProvider, bool) {
+	authProvidersMutex.RLock()
+	defer authProvidersMutex.RUnlock()
+	provider, ok := authProviders[slugID]
+	return provider, ok
+}
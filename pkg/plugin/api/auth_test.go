@@ -0,0 +1,47 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProvider struct{}
+
+func (m *mockProvider) Authenticate(_, _ string) (*UserInfo, error) {
+	return nil, ErrInvalidCredentials
+}
+
+func (m *mockProvider) RefreshToken(_ string) (*TokenInfo, error) {
+	return &TokenInfo{}, nil
+}
+
+func TestRegisterAuthProvider(t *testing.T) {
+	assert.Error(t, RegisterAuthProvider("", &mockProvider{}))
+	assert.Error(t, RegisterAuthProvider("mock", nil))
+
+	assert.NoError(t, RegisterAuthProvider("mock", &mockProvider{}))
+	defer UnregisterAuthProvider("mock")
+	assert.Error(t, RegisterAuthProvider("mock", &mockProvider{}))
+
+	provider, ok := GetAuthProvider("mock")
+	assert.True(t, ok)
+	assert.NotNil(t, provider)
+
+	UnregisterAuthProvider("mock")
+	_, ok = GetAuthProvider("mock")
+	assert.False(t, ok)
+}
@@ -0,0 +1,56 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"fmt"
+
+	"github.com/perses/perses/pkg/plugin/api"
+)
+
+// staticProvider authenticates the users against a static list of credentials.
+// A real implementation would query the identity provider of the organization instead.
+type staticProvider struct {
+	passwords map[string]string
+}
+
+func (p *staticProvider) Authenticate(username, password string) (*api.UserInfo, error) {
+	if pwd, ok := p.passwords[username]; !ok || pwd != password {
+		return nil, api.ErrInvalidCredentials
+	}
+	return &api.UserInfo{Login: username, Email: username + "@example.com"}, nil
+}
+
+func (p *staticProvider) RefreshToken(_ string) (*api.TokenInfo, error) {
+	// Nothing to refresh in this provider. Returning an empty TokenInfo keeps the login contained in the token.
+	return &api.TokenInfo{}, nil
+}
+
+func ExampleRegisterAuthProvider() {
+	provider := &staticProvider{passwords: map[string]string{"alice": "secret"}}
+	// The slug ID must match the one set in the configuration under security.authentication.providers.custom.
+	if err := api.RegisterAuthProvider("static", provider); err != nil {
+		panic(err)
+	}
+	defer api.UnregisterAuthProvider("static")
+
+	registered, _ := api.GetAuthProvider("static")
+	info, err := registered.Authenticate("alice", "secret")
+	fmt.Println(info.Login, err)
+	_, err = registered.Authenticate("alice", "wrong")
+	fmt.Println(err)
+	// Output:
+	// alice <nil>
+	// wrong login or password
+}
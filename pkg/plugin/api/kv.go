@@ -0,0 +1,81 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrKeyNotFound is returned by a PluginKVStore when the requested key doesn't exist.
+var ErrKeyNotFound = errors.New("key not found")
+
+// PluginKVStore is a key-value store a plugin can use to persist its state between two restarts of Perses.
+// The data are kept in the database configured for Perses. Each plugin module has its own store: the keys of a module
+// are never visible from another one.
+// Keys follow the same naming rules as the name of any Perses resource. Like these names, they are stored in lower case
+// when the database is not case-sensitive.
+type PluginKVStore interface {
+	// Get returns the value associated to the key, or ErrKeyNotFound if it doesn't exist.
+	Get(key string) ([]byte, error)
+	// Set creates or replaces the value associated to the key.
+	Set(key string, value []byte) error
+	// Delete removes the key. It returns ErrKeyNotFound if it doesn't exist.
+	Delete(key string) error
+	// List returns all keys of the store.
+	List() ([]string, error)
+}
+
+// KVStoreConsumer receives the PluginKVStore of the plugin module it has been registered for.
+// It is called each time the module is loaded by Perses.
+type KVStoreConsumer func(store PluginKVStore)
+
+var (
+	kvStoreConsumersMutex sync.RWMutex
+	kvStoreConsumers      = make(map[string]KVStoreConsumer)
+)
+
+// RegisterKVStoreConsumer asks the plugin loader to inject the PluginKVStore of the given plugin module into the consumer.
+// It is meant to be called before the API server starts, typically in an init function.
+func RegisterKVStoreConsumer(moduleName string, consumer KVStoreConsumer) error {
+	if len(moduleName) == 0 {
+		return errors.New("module name of the key-value store consumer cannot be empty")
+	}
+	if consumer == nil {
+		return fmt.Errorf("key-value store consumer of the module %q cannot be nil", moduleName)
+	}
+	kvStoreConsumersMutex.Lock()
+	defer kvStoreConsumersMutex.Unlock()
+	if _, ok := kvStoreConsumers[moduleName]; ok {
+		return fmt.Errorf("a key-value store consumer is already registered for the module %q", moduleName)
+	}
+	kvStoreConsumers[moduleName] = consumer
+	return nil
+}
+
+// UnregisterKVStoreConsumer removes the KVStoreConsumer registered for the given plugin module, if any.
+func UnregisterKVStoreConsumer(moduleName string) {
+	kvStoreConsumersMutex.Lock()
+	defer kvStoreConsumersMutex.Unlock()
+	delete(kvStoreConsumers, moduleName)
+}
+
+// GetKVStoreConsumer returns the KVStoreConsumer registered for the given plugin module.
+func GetKVStoreConsumer(moduleName string) (KVStoreConsumer, bool) {
+	kvStoreConsumersMutex.RLock()
+	defer kvStoreConsumersMutex.RUnlock()
+	consumer, ok := kvStoreConsumers[moduleName]
+	return consumer, ok
+}
@@ -0,0 +1,38 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterKVStoreConsumer(t *testing.T) {
+	consumer := func(_ PluginKVStore) {}
+	assert.Error(t, RegisterKVStoreConsumer("", consumer))
+	assert.Error(t, RegisterKVStoreConsumer("anomaly", nil))
+
+	assert.NoError(t, RegisterKVStoreConsumer("anomaly", consumer))
+	defer UnregisterKVStoreConsumer("anomaly")
+	assert.Error(t, RegisterKVStoreConsumer("anomaly", consumer))
+
+	registered, ok := GetKVStoreConsumer("anomaly")
+	assert.True(t, ok)
+	assert.NotNil(t, registered)
+
+	UnregisterKVStoreConsumer("anomaly")
+	_, ok = GetKVStoreConsumer("anomaly")
+	assert.False(t, ok)
+}
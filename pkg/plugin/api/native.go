@@ -0,0 +1,31 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// NativePluginSymbol is the name of the variable a Go plugin (a shared library built with -buildmode=plugin) must
+// export so that Perses can load it.
+const NativePluginSymbol = "PersesPlugin"
+
+// NativePlugin is the interface the PersesPlugin variable of a Go plugin must implement. The variable can either hold
+// a value implementing NativePlugin, or be declared with the NativePlugin type.
+//
+// Go plugins are loaded from the plugin folder when plugin.enable_native_plugins is set in the configuration. They must
+// be built with the same Go version and the same versions of the dependencies as Perses.
+type NativePlugin interface {
+	// Name identifies the plugin in the logs.
+	Name() string
+	// Register is called once, right after the shared library has been loaded. This is where the plugin calls the
+	// registration functions of this package, such as RegisterAuthProvider or RegisterKVStoreConsumer.
+	Register() error
+}
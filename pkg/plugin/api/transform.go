@@ -0,0 +1,68 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// TransformPlugin converts the response of a datasource before it is sent back to the client by the datasource proxy,
+// for example to turn a CSV document into time series. Which transform is applied to which datasource is defined in the
+// configuration under datasource.transforms.
+type TransformPlugin interface {
+	// Transform receives the body of a successful response of the datasource and the configuration of the transform,
+	// encoded in JSON. It returns the new body. An error makes the proxy answer with a 502 Bad Gateway.
+	Transform(input json.RawMessage, config json.RawMessage) (json.RawMessage, error)
+}
+
+var (
+	transformPluginsMutex sync.RWMutex
+	transformPlugins      = make(map[string]TransformPlugin)
+)
+
+// RegisterTransformPlugin makes a TransformPlugin available under the given name.
+// It is meant to be called before the API server starts, typically in an init function or in the Register method of a NativePlugin.
+func RegisterTransformPlugin(name string, plugin TransformPlugin) error {
+	if len(name) == 0 {
+		return errors.New("name of the transform plugin cannot be empty")
+	}
+	if plugin == nil {
+		return fmt.Errorf("transform plugin %q cannot be nil", name)
+	}
+	transformPluginsMutex.Lock()
+	defer transformPluginsMutex.Unlock()
+	if _, ok := transformPlugins[name]; ok {
+		return fmt.Errorf("a transform plugin is already registered with the name %q", name)
+	}
+	transformPlugins[name] = plugin
+	return nil
+}
+
+// UnregisterTransformPlugin removes the TransformPlugin registered under the given name, if any.
+func UnregisterTransformPlugin(name string) {
+	transformPluginsMutex.Lock()
+	defer transformPluginsMutex.Unlock()
+	delete(transformPlugins, name)
+}
+
+// GetTransformPlugin returns the TransformPlugin registered under the given name.
+func GetTransformPlugin(name string) (TransformPlugin, bool) {
+	transformPluginsMutex.RLock()
+	defer transformPluginsMutex.RUnlock()
+	plugin, ok := transformPlugins[name]
+	return plugin, ok
+}
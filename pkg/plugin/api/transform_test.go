@@ -0,0 +1,44 @@
+// Copyright The Perses Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type identityTransform struct{}
+
+func (identityTransform) Transform(input json.RawMessage, _ json.RawMessage) (json.RawMessage, error) {
+	return input, nil
+}
+
+func TestRegisterTransformPlugin(t *testing.T) {
+	assert.Error(t, RegisterTransformPlugin("", identityTransform{}))
+	assert.Error(t, RegisterTransformPlugin("identity", nil))
+
+	assert.NoError(t, RegisterTransformPlugin("identity", identityTransform{}))
+	defer UnregisterTransformPlugin("identity")
+	assert.Error(t, RegisterTransformPlugin("identity", identityTransform{}))
+
+	registered, ok := GetTransformPlugin("identity")
+	assert.True(t, ok)
+	assert.NotNil(t, registered)
+
+	UnregisterTransformPlugin("identity")
+	_, ok = GetTransformPlugin("identity")
+	assert.False(t, ok)
+}
@@ -59,17 +59,29 @@ var (
 
 type frontend struct {
 	echoUtils.Register
-	apiPrefix     string
-	pluginService plugin.Plugin
+	apiPrefix         string
+	pluginService     plugin.Plugin
+	mimeTypeOverrides map[string]string
 }
 
 func NewPersesFrontend(cfg config.Config, pluginService plugin.Plugin) echoUtils.Register {
 	return &frontend{
-		apiPrefix:     cfg.APIPrefix,
-		pluginService: pluginService,
+		apiPrefix:         cfg.APIPrefix,
+		pluginService:     pluginService,
+		mimeTypeOverrides: cfg.API.MIMETypeOverrides,
 	}
 }
 
+// contentTypeOf returns the MIME type of the file from its extension.
+// The MIME types overridden in the configuration take precedence over the ones known by the system.
+func (f *frontend) contentTypeOf(fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if contentType, ok := f.mimeTypeOverrides[ext]; ok {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
+
 func (f *frontend) RegisterRoute(e *echo.Echo) {
 	contentRewrite := middleware.Rewrite(map[string]string{f.apiPrefix + "/*": "/app/dist/$1"})
 	e.GET(f.apiPrefix, f.serveASTFiles)
@@ -98,6 +110,9 @@ func (f *frontend) servePluginFiles(c echo.Context) error {
 		logrus.Errorf("unable to find the plugin name in the URL path: %s", req.URL.Path)
 		return apiinterface.NotFoundError
 	}
+	// The files of the plugin must not be replaced by a reload while they are served.
+	release := f.pluginService.Acquire()
+	defer release()
 	loaded, isLoaded := f.pluginService.GetLoadedPlugin(pluginName, pluginVersion, pluginRegistry)
 
 	if !isLoaded || !loaded.Module.Status.IsLoaded {
@@ -123,9 +138,15 @@ func (f *frontend) servePluginFiles(c echo.Context) error {
 		// Without it, browsers perform "MIME sniffing". For example, a file served as text/plain could be sniffed as text/html and executed as HTML, which could open the door to XSS attacks.
 		// See https://developer.mozilla.org/en-US/docs/Web/HTTP/Reference/Headers/X-Content-Type-Options
 		c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+		// c.File keeps the Content-Type header when it is already set, and guesses it from the extension otherwise.
+		if contentType, ok := f.mimeTypeOverrides[strings.ToLower(filepath.Ext(localPath))]; ok {
+			c.Response().Header().Set("Content-Type", contentType)
+		}
 		return c.File(localPath)
 	}
 	// Otherwise, it means we are in a dev environment, and we need to proxy the request to the dev server.
+	// A reload doesn't affect the files served by the dev server.
+	release()
 	// When developing a plugin, you will be able to serve the files of the plugin using a dev server (with rsbuild).
 	res := c.Response()
 	var proxyErr error
@@ -198,7 +219,7 @@ func (f *frontend) assetHandler() echo.HandlerFunc {
 		if strings.Contains(fileName, ".js") || strings.Contains(fileName, ".css") {
 			data = bytes.ReplaceAll(data, []byte(prefixPathPlaceholder), []byte(f.apiPrefix))
 		}
-		contentType := mime.TypeByExtension(filepath.Ext(fileName))
+		contentType := f.contentTypeOf(fileName)
 		if contentType == "" {
 			contentType = http.DetectContentType(data)
 		}
@@ -91,6 +91,48 @@ func TestAssetHandlerContentType(t *testing.T) {
 	}
 }
 
+func TestAssetHandlerMIMETypeOverrides(t *testing.T) {
+	// Override the package-level embedded filesystem with test data.
+	originalAsts := asts
+	t.Cleanup(func() { asts = originalAsts })
+
+	testFS := fstest.MapFS{
+		"app/dist/main.abc123.js": &fstest.MapFile{Data: []byte("console.log('ok')")},
+		"app/dist/style.css":      &fstest.MapFile{Data: []byte("body {}")},
+	}
+	asts = http.FS(testFS)
+
+	f := &frontend{mimeTypeOverrides: map[string]string{".js": "application/x-custom"}}
+
+	tests := []struct {
+		name                string
+		path                string
+		expectedContentType string
+	}{
+		{
+			name:                "overridden extension",
+			path:                "/app/dist/main.abc123.js",
+			expectedContentType: "application/x-custom",
+		},
+		{
+			name:                "extension not overridden",
+			path:                "/app/dist/style.css",
+			expectedContentType: "text/css; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			require.NoError(t, f.assetHandler()(ctx))
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+		})
+	}
+}
+
 func TestServeASTFilesContentType(t *testing.T) {
 	// Override the package-level embedded filesystem with test data.
 	originalAsts := asts
@@ -279,11 +321,15 @@ type mockPluginService struct {
 }
 
 func (m *mockPluginService) Load() error                                         { return nil }
+func (m *mockPluginService) UpdateFilter(_ plugin.Filter) error                  { return nil }
 func (m *mockPluginService) LoadDevPlugin(_ []v1.PluginInDevelopment) error      { return nil }
 func (m *mockPluginService) RefreshDevPlugin(_ pluginModel.ModuleMetadata) error { return nil }
 func (m *mockPluginService) UnLoadDevPlugin(_ pluginModel.ModuleMetadata) error  { return nil }
 func (m *mockPluginService) List() ([]byte, error)                               { return nil, nil }
+func (m *mockPluginService) ListInstalled() []pluginModel.PluginInfo             { return nil }
 func (m *mockPluginService) UnzipArchives() error                                { return nil }
+func (m *mockPluginService) Reload() error                                       { return nil }
+func (m *mockPluginService) Acquire() func()                                     { return func() {} }
 func (m *mockPluginService) Schema() schema.Schema                               { return nil }
 func (m *mockPluginService) Migration() migrate.Migration                        { return nil }
 func (m *mockPluginService) GetLoadedPlugin(name, _, _ string) (*plugin.Loaded, bool) {
@@ -396,3 +442,51 @@ func TestServePluginFilesPathTraversal(t *testing.T) {
 		})
 	}
 }
+
+func TestServePluginFilesMIMETypeOverrides(t *testing.T) {
+	pluginDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "module.wasm"), []byte("fake-wasm"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "manifest.json"), []byte(`{"name":"test"}`), 0o600))
+
+	f := &frontend{
+		pluginService: &mockPluginService{
+			loaded: map[string]*plugin.Loaded{
+				"testplugin": {
+					LocalPath: pluginDir,
+					Module: v1.PluginModule{
+						Status: &pluginModel.ModuleStatus{IsLoaded: true},
+					},
+				},
+			},
+		},
+		mimeTypeOverrides: map[string]string{".wasm": "application/x-custom-wasm"},
+	}
+
+	tests := []struct {
+		name                string
+		path                string
+		expectedContentType string
+	}{
+		{
+			name:                "overridden extension",
+			path:                "/plugins/testplugin/module.wasm",
+			expectedContentType: "application/x-custom-wasm",
+		},
+		{
+			name:                "extension not overridden",
+			path:                "/plugins/testplugin/manifest.json",
+			expectedContentType: "application/json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			require.NoError(t, f.servePluginFiles(ctx))
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+		})
+	}
+}